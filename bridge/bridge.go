@@ -8,7 +8,9 @@ import (
 	"time"
 
 	"github.com/sirupsen/logrus"
+	"github.com/srg/blim/inspector"
 	"github.com/srg/blim/internal/device"
+	goble "github.com/srg/blim/internal/device/go-ble"
 	"github.com/srg/blim/internal/devicefactory"
 	"github.com/srg/blim/internal/lua"
 	"github.com/srg/blim/internal/ptyio"
@@ -20,6 +22,12 @@ const (
 
 	// DefaultPtyStdinBufferSize is the default size, in bytes, of the ring buffer used for PTY stdin input.
 	DefaultPtyStdinBufferSize = 1000
+
+	// DefaultPTYWriteTimeout is the default timeout of each characteristic write forwarding PTY input.
+	DefaultPTYWriteTimeout = 5 * time.Second
+
+	// attHeaderSize is the ATT header of a write request or command (opcode and handle), not available for payload.
+	attHeaderSize = 3
 )
 
 // Bridge represents a running BLE-PTY bridge with access to the device and PTY
@@ -36,8 +44,18 @@ type Bridge interface {
 type BridgeOptions struct {
 	BleAddress               string                    // BLE device address
 	BleConnectTimeout        time.Duration             // BLE Connection timeout
+	BleDiscoveryTimeout      time.Duration             // Bounds service discovery after connecting (0 = no limit)
+	BleConnectRetries        int                       // Retries of a failed initial connection (0 = no retry)
+	BleConnectRetryBackoff   time.Duration             // Delay before the first connection retry, doubled after each retry
+	BleByIdentifier          bool                      // Connect to the system-cached peripheral without scanning, falling back to a scan
+	BleGATTCacheDir          string                    // On-disk GATT cache directory ("" = always discover)
 	BleDescriptorReadTimeout time.Duration             // Timeout for reading descriptor values (0 = skip reads)
 	BleSubscribeOptions      []device.SubscribeOptions // BLE subscribe options
+	NotifyToPTY              []device.SubscribeOptions // Characteristics whose notification payloads are written raw to the PTY
+	PTYWriteService          string                    // Service of PTYWriteCharacteristic
+	PTYWriteCharacteristic   string                    // Characteristic that data read from the PTY is written to (empty = PTY input stays Lua-only)
+	PTYWriteWithResponse     bool                      // Write PTY input with response instead of without response
+	PTYWriteTimeout          time.Duration             // Timeout of each PTY input write (0 = use DefaultPTYWriteTimeout)
 	Logger                   *logrus.Logger            // Logger instance
 	PtyStdinBufferSize       int                       // PTY stdin ring buffer size in bytes (0 = use default)
 	PtyStdoutBufferSize      int                       // PTY stdout ring buffer size in bytes (0 = use default)
@@ -53,8 +71,9 @@ type BridgeCallback[R any] func(Bridge) (R, error)
 // bridgeImpl implements the Bridge interface
 type bridgeImpl struct {
 	luaApi         *lua.LuaAPI
-	ttySymlinkPath string    // TTY Symlink (empty if not created)
-	pty            ptyio.PTY // PTY I/O interface for async monitoring
+	ttySymlinkPath string       // TTY Symlink (empty if not created)
+	pty            ptyio.PTY    // PTY I/O interface for async monitoring
+	ptyForward     func([]byte) // Forwards PTY input to PTYWriteCharacteristic (nil if not configured)
 }
 
 func (b *bridgeImpl) GetLuaAPI() *lua.LuaAPI {
@@ -80,9 +99,24 @@ func (b *bridgeImpl) GetPTYIO() ptyio.PTY {
 	return b.pty
 }
 
+// SetPTYReadCallback sets the callback receiving PTY input. The PTY holds a single read callback, so when
+// PTY input is also forwarded to a characteristic, both receive the data: forwarding first, then cb.
 func (b *bridgeImpl) SetPTYReadCallback(cb func([]byte)) {
-	if b.pty != nil {
+	if b.pty == nil {
+		return
+	}
+
+	forward := b.ptyForward
+	switch {
+	case forward == nil:
 		b.pty.SetReadCallback(cb)
+	case cb == nil:
+		b.pty.SetReadCallback(forward)
+	default:
+		b.pty.SetReadCallback(func(data []byte) {
+			forward(data)
+			cb(data)
+		})
 	}
 }
 
@@ -154,21 +188,34 @@ func RunDeviceBridge[R any](
 	// Report phase: Connecting
 	progressCallback("Connecting")
 
-	// Create Lua API (creates device)
-	dev := devicefactory.NewDevice(opts.BleAddress, logger)
-	luaApi = lua.NewBLEAPI2(dev, logger)
-
 	// Connect to device
 	connectOpts := &device.ConnectOptions{
 		Address:               opts.BleAddress,
 		ConnectTimeout:        opts.BleConnectTimeout,
+		DiscoveryTimeout:      opts.BleDiscoveryTimeout,
 		DescriptorReadTimeout: opts.BleDescriptorReadTimeout,
 		Services:              opts.BleSubscribeOptions,
+		GATTCacheDir:          opts.BleGATTCacheDir,
 	}
 
-	if err := luaApi.GetDevice().Connect(bridgeCtx, connectOpts); err != nil {
-		progressCallback("Failed")
-		return zero, fmt.Errorf("failed to connect to device %s: %w", opts.BleAddress, err)
+	if opts.BleByIdentifier {
+		// The device instance is only known after connecting (it may come from the fallback scan)
+		dev, err := devicefactory.ConnectByIdentifier(bridgeCtx, opts.BleAddress, connectOpts, logger)
+		if err != nil {
+			progressCallback("Failed")
+			return zero, fmt.Errorf("failed to connect to device %s: %w", opts.BleAddress, err)
+		}
+		luaApi = lua.NewBLEAPI2(dev, logger)
+	} else {
+		// Create Lua API (creates device)
+		dev := devicefactory.NewDevice(opts.BleAddress, logger)
+		luaApi = lua.NewBLEAPI2(dev, logger)
+
+		retryPolicy := inspector.ConnectRetryPolicy(opts.BleConnectRetries, opts.BleConnectRetryBackoff, logger)
+		if err := device.ConnectWithRetry(bridgeCtx, luaApi.GetDevice(), connectOpts, retryPolicy); err != nil {
+			progressCallback("Failed")
+			return zero, fmt.Errorf("failed to connect to device %s: %w", opts.BleAddress, err)
+		}
 	}
 
 	// Report phase: Connected
@@ -209,6 +256,56 @@ func RunDeviceBridge[R any](
 		}).Info("Created PTY symlink")
 	}
 
+	// Forward notifications of the selected characteristics to the PTY, as if the device typed them
+	for _, opt := range opts.NotifyToPTY {
+		for _, charUUID := range opt.Characteristics {
+			// Handlers live as long as the connection, which the bridge closes on shutdown
+			_, err := luaApi.GetDevice().GetConnection().OnNotification(opt.Service, charUUID, func(data []byte) {
+				if _, err := pty.Write(data); err != nil {
+					logger.WithError(err).WithField("characteristic", charUUID).Warn("Failed to write notification to PTY")
+				}
+			})
+			if err != nil {
+				return zero, fmt.Errorf("failed to forward notifications of %s/%s to PTY: %w", opt.Service, charUUID, err)
+			}
+			logger.WithFields(logrus.Fields{
+				"service":        opt.Service,
+				"characteristic": charUUID,
+			}).Info("Forwarding notifications to PTY")
+		}
+	}
+
+	// Forward data read from the PTY to the selected characteristic
+	var ptyForward func([]byte)
+	if opts.PTYWriteCharacteristic != "" {
+		conn := luaApi.GetDevice().GetConnection()
+		char, err := conn.GetCharacteristic(opts.PTYWriteService, opts.PTYWriteCharacteristic)
+		if err != nil {
+			return zero, fmt.Errorf("failed to forward PTY input to %s/%s: %w", opts.PTYWriteService, opts.PTYWriteCharacteristic, err)
+		}
+		if err := checkWritable(char, opts.PTYWriteWithResponse); err != nil {
+			return zero, fmt.Errorf("failed to forward PTY input to %s/%s: %w", opts.PTYWriteService, opts.PTYWriteCharacteristic, err)
+		}
+
+		timeout := opts.PTYWriteTimeout
+		if timeout == 0 {
+			timeout = DefaultPTYWriteTimeout
+		}
+		chunkSize := writeChunkSize(conn)
+		ptyForward = func(data []byte) {
+			if err := writeChunked(char, data, chunkSize, opts.PTYWriteWithResponse, timeout); err != nil {
+				logger.WithError(err).WithField("characteristic", char.UUID()).Warn("Failed to write PTY input to characteristic")
+			}
+		}
+		pty.SetReadCallback(ptyForward)
+		logger.WithFields(logrus.Fields{
+			"service":        opts.PTYWriteService,
+			"characteristic": char.UUID(),
+			"with_response":  opts.PTYWriteWithResponse,
+			"chunk_size":     chunkSize,
+		}).Info("Forwarding PTY input to characteristic")
+	}
+
 	// Report phase: Running
 	progressCallback("Running")
 
@@ -217,6 +314,7 @@ func RunDeviceBridge[R any](
 		luaApi:         luaApi,
 		ttySymlinkPath: ttySymlinkPath,
 		pty:            pty,
+		ptyForward:     ptyForward,
 	}
 
 	// Set bridge info on Lua API (enables pty_write/pty_read via strategy)
@@ -225,3 +323,41 @@ func RunDeviceBridge[R any](
 	// Execute callback with the bridge
 	return callback(bridge)
 }
+
+// checkWritable verifies the characteristic supports the requested write mode
+func checkWritable(char device.Characteristic, withResponse bool) error {
+	props := char.GetProperties()
+	if props == nil {
+		return fmt.Errorf("characteristic %s: properties not available", char.UUID())
+	}
+
+	prop, mode := props.WriteWithoutResponse(), "write-without-response"
+	if withResponse {
+		prop, mode = props.Write(), "write"
+	}
+	if prop == nil || prop.Value() == 0 {
+		return fmt.Errorf("characteristic %s does not support %s: %w", char.UUID(), mode, device.ErrUnsupported)
+	}
+	return nil
+}
+
+// writeChunkSize returns the payload of one ATT write on conn: its MTU minus the 3-byte ATT header,
+// or goble.DefaultBLEWriteChunkSize (the minimum MTU's payload) when the MTU is unknown
+func writeChunkSize(conn device.Connection) int {
+	if mtu := conn.MTU(); mtu > attHeaderSize {
+		return mtu - attHeaderSize
+	}
+	return goble.DefaultBLEWriteChunkSize
+}
+
+// writeChunked writes data to the characteristic in chunks of at most chunkSize bytes
+func writeChunked(char device.Characteristic, data []byte, chunkSize int, withResponse bool, timeout time.Duration) error {
+	for len(data) > 0 {
+		n := min(len(data), chunkSize)
+		if err := char.Write(data[:n], withResponse, timeout); err != nil {
+			return err
+		}
+		data = data[n:]
+	}
+	return nil
+}
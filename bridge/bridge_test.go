@@ -1,12 +1,17 @@
 package bridge
 
 import (
+	"bytes"
 	"context"
+	"errors"
 	"fmt"
 	"os"
+	"syscall"
 	"testing"
 	"time"
 
+	"github.com/srg/blim/internal/device"
+	"github.com/srg/blim/internal/testutils"
 	"github.com/stretchr/testify/suite"
 )
 
@@ -196,6 +201,311 @@ func (suite *BridgeTestSuite) TestTTYSymlinkAlreadyExists() {
 	suite.Contains(err.Error(), "failed to create tty symlink", "Error must mention symlink creation")
 }
 
+func (suite *BridgeTestSuite) TestNotifyToPTY() {
+	// GOAL: Verify notifications of NotifyToPTY characteristics are written raw to the PTY, others are not
+	//
+	// TEST SCENARIO: Bridge with NotifyToPTY=180D/2A37 → notify 2A19 and 2A37 → PTY slave reads only the 2A37 payload
+
+	bridgeCtx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	var received []byte
+	bridgeCallback := func(b Bridge) (error, error) {
+		ptySlave, err := os.OpenFile(b.GetTTYName(), os.O_RDWR|syscall.O_NONBLOCK, 0)
+		suite.Require().NoError(err, "PTY slave must open")
+		defer ptySlave.Close()
+
+		_, err = suite.NewPeripheralDataSimulator().
+			WithService("180F").
+			WithCharacteristic("2A19", []byte("battery")).
+			WithService("180D").
+			WithCharacteristic("2A37", []byte("hello")).
+			Build().
+			SimulateFor(b.GetLuaAPI().GetDevice().GetConnection(), false)
+		suite.Require().NoError(err, "notification simulation must succeed")
+
+		buf := make([]byte, 64)
+		deadline := time.Now().Add(maxShutdownDuration)
+		for len(received) < len("hello") && time.Now().Before(deadline) {
+			n, err := ptySlave.Read(buf)
+			if err != nil && !errors.Is(err, syscall.EAGAIN) {
+				return nil, err
+			}
+			received = append(received, buf[:n]...)
+			time.Sleep(testSyncWait / 10)
+		}
+		return nil, nil
+	}
+
+	_, err := RunDeviceBridge(
+		bridgeCtx,
+		&BridgeOptions{
+			BleAddress:        suite.LuaApi.GetDevice().Address(),
+			BleConnectTimeout: 5 * time.Second,
+			NotifyToPTY: []device.SubscribeOptions{
+				{Service: "180d", Characteristics: []string{"2a37"}},
+			},
+			Logger: suite.Logger,
+		},
+		nil,
+		bridgeCallback,
+	)
+
+	suite.NoError(err, "Bridge must run successfully")
+	suite.Equal([]byte("hello"), received, "PTY must receive only the forwarded notification payload")
+}
+
+func (suite *BridgeTestSuite) TestNotifyToPTYUnknownCharacteristic() {
+	// GOAL: Verify the bridge fails before running when a NotifyToPTY characteristic does not exist
+	//
+	// TEST SCENARIO: Bridge with NotifyToPTY=180D/9999 → error returned → callback not reached
+
+	bridgeCtx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	bridgeCallback := func(b Bridge) (error, error) {
+		suite.Fail("Callback should not be reached")
+		return nil, nil
+	}
+
+	_, err := RunDeviceBridge(
+		bridgeCtx,
+		&BridgeOptions{
+			BleAddress:        suite.LuaApi.GetDevice().Address(),
+			BleConnectTimeout: 5 * time.Second,
+			NotifyToPTY: []device.SubscribeOptions{
+				{Service: "180d", Characteristics: []string{"9999"}},
+			},
+			Logger: suite.Logger,
+		},
+		nil,
+		bridgeCallback,
+	)
+
+	suite.Error(err, "Bridge must fail for unknown characteristic")
+	suite.Contains(err.Error(), "failed to forward notifications", "Error must mention notification forwarding")
+}
+
+func (suite *BridgeTestSuite) TestPTYToWrite() {
+	// GOAL: Verify data written to the PTY slave is forwarded to the PTYWriteCharacteristic in MTU-sized chunks
+	//
+	// TEST SCENARIO: Bridge with PTYWriteCharacteristic=FFE0/FFE1 → client writes 45 bytes to PTY slave → characteristic receives them in ≤20-byte writes without response
+
+	type write struct {
+		data  []byte
+		noRsp bool
+	}
+	writes := make(chan write, 16)
+
+	suite.WithPeripheral().
+		WithService("ffe0").
+		WithCharacteristic("ffe1", "write,write-without-response", nil,
+			testutils.WithWriteHook(func(data []byte, noRsp bool) { writes <- write{data, noRsp} }))
+
+	bridgeCtx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	input := []byte("AT+NAME?\r\nAT+VERSION?\r\nAT+BAUD=115200\r\nAT+RESET\r\n")
+	var received []write
+	bridgeCallback := func(b Bridge) (error, error) {
+		ptySlave, err := os.OpenFile(b.GetTTYName(), os.O_RDWR|syscall.O_NONBLOCK, 0)
+		suite.Require().NoError(err, "PTY slave must open")
+		defer ptySlave.Close()
+
+		_, err = ptySlave.Write(input)
+		suite.Require().NoError(err, "PTY slave write must succeed")
+
+		total := 0
+		for total < len(input) {
+			select {
+			case w := <-writes:
+				received = append(received, w)
+				total += len(w.data)
+			case <-time.After(maxShutdownDuration):
+				return nil, fmt.Errorf("timed out waiting for characteristic writes (%d of %d bytes)", total, len(input))
+			}
+		}
+		return nil, nil
+	}
+
+	_, err := RunDeviceBridge(
+		bridgeCtx,
+		&BridgeOptions{
+			BleAddress:             suite.LuaApi.GetDevice().Address(),
+			BleConnectTimeout:      5 * time.Second,
+			PTYWriteService:        "ffe0",
+			PTYWriteCharacteristic: "ffe1",
+			Logger:                 suite.Logger,
+		},
+		nil,
+		bridgeCallback,
+	)
+	suite.Require().NoError(err, "Bridge must run successfully")
+
+	var written []byte
+	for _, w := range received {
+		suite.LessOrEqual(len(w.data), 20, "each write must fit the minimum ATT MTU")
+		suite.True(w.noRsp, "writes must be without response by default")
+		written = append(written, w.data...)
+	}
+	suite.Equal(input, written, "characteristic must receive the PTY input unchanged")
+}
+
+func (suite *BridgeTestSuite) TestPTYToWriteNegotiatedMTU() {
+	// GOAL: Verify PTY input is chunked at the connection's MTU minus the ATT header, not the minimum MTU
+	//
+	// TEST SCENARIO: Connection MTU 64 → client writes 100 bytes to PTY slave → characteristic receives 61-byte chunks
+
+	writes := make(chan []byte, 16)
+
+	suite.WithPeripheral().
+		WithMTU(64).
+		WithService("ffe0").
+		WithCharacteristic("ffe1", "write,write-without-response", nil,
+			testutils.WithWriteHook(func(data []byte, noRsp bool) { writes <- data }))
+
+	bridgeCtx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	input := bytes.Repeat([]byte("0123456789"), 10)
+	var received [][]byte
+	bridgeCallback := func(b Bridge) (error, error) {
+		ptySlave, err := os.OpenFile(b.GetTTYName(), os.O_RDWR|syscall.O_NONBLOCK, 0)
+		suite.Require().NoError(err, "PTY slave must open")
+		defer ptySlave.Close()
+
+		_, err = ptySlave.Write(input)
+		suite.Require().NoError(err, "PTY slave write must succeed")
+
+		total := 0
+		for total < len(input) {
+			select {
+			case data := <-writes:
+				received = append(received, data)
+				total += len(data)
+			case <-time.After(maxShutdownDuration):
+				return nil, fmt.Errorf("timed out waiting for characteristic writes (%d of %d bytes)", total, len(input))
+			}
+		}
+		return nil, nil
+	}
+
+	_, err := RunDeviceBridge(
+		bridgeCtx,
+		&BridgeOptions{
+			BleAddress:             suite.LuaApi.GetDevice().Address(),
+			BleConnectTimeout:      5 * time.Second,
+			PTYWriteService:        "ffe0",
+			PTYWriteCharacteristic: "ffe1",
+			Logger:                 suite.Logger,
+		},
+		nil,
+		bridgeCallback,
+	)
+	suite.Require().NoError(err, "Bridge must run successfully")
+
+	var written []byte
+	largest := 0
+	for _, data := range received {
+		suite.LessOrEqual(len(data), 61, "each write must fit the negotiated MTU minus the ATT header")
+		largest = max(largest, len(data))
+		written = append(written, data...)
+	}
+	suite.Equal(61, largest, "writes must use the full negotiated MTU")
+	suite.Equal(input, written, "characteristic must receive the PTY input unchanged")
+}
+
+func (suite *BridgeTestSuite) TestPTYToWriteWithReadCallback() {
+	// GOAL: Verify a PTY read callback (as set by blim.bridge.pty_on_data) does not replace forwarding to the characteristic
+	//
+	// TEST SCENARIO: Bridge with PTYWriteCharacteristic → SetPTYReadCallback registered → client writes to PTY slave → both the characteristic and the callback receive the data
+
+	writes := make(chan []byte, 16)
+
+	suite.WithPeripheral().
+		WithService("ffe0").
+		WithCharacteristic("ffe1", "write,write-without-response", nil,
+			testutils.WithWriteHook(func(data []byte, noRsp bool) { writes <- data }))
+
+	bridgeCtx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	input := []byte("AT\r\n")
+	callbackData := make(chan []byte, 16)
+	bridgeCallback := func(b Bridge) (error, error) {
+		b.SetPTYReadCallback(func(data []byte) { callbackData <- append([]byte(nil), data...) })
+
+		ptySlave, err := os.OpenFile(b.GetTTYName(), os.O_RDWR|syscall.O_NONBLOCK, 0)
+		suite.Require().NoError(err, "PTY slave must open")
+		defer ptySlave.Close()
+
+		_, err = ptySlave.Write(input)
+		suite.Require().NoError(err, "PTY slave write must succeed")
+
+		var written, seen []byte
+		for len(written) < len(input) || len(seen) < len(input) {
+			select {
+			case data := <-writes:
+				written = append(written, data...)
+			case data := <-callbackData:
+				seen = append(seen, data...)
+			case <-time.After(maxShutdownDuration):
+				return nil, fmt.Errorf("timed out waiting for PTY input (characteristic %q, callback %q)", written, seen)
+			}
+		}
+		suite.Equal(input, written, "characteristic must still receive the PTY input")
+		suite.Equal(input, seen, "read callback must receive the PTY input")
+		return nil, nil
+	}
+
+	_, err := RunDeviceBridge(
+		bridgeCtx,
+		&BridgeOptions{
+			BleAddress:             suite.LuaApi.GetDevice().Address(),
+			BleConnectTimeout:      5 * time.Second,
+			PTYWriteService:        "ffe0",
+			PTYWriteCharacteristic: "ffe1",
+			Logger:                 suite.Logger,
+		},
+		nil,
+		bridgeCallback,
+	)
+	suite.Require().NoError(err, "Bridge must run successfully")
+}
+
+func (suite *BridgeTestSuite) TestPTYToWriteUnsupportedMode() {
+	// GOAL: Verify the bridge fails before running when the characteristic does not support the requested write mode
+	//
+	// TEST SCENARIO: Bridge with PTYWriteWithResponse on a write-without-response-only characteristic → error returned
+
+	suite.WithPeripheral().
+		WithService("ffe0").
+		WithCharacteristic("ffe1", "write-without-response", nil)
+
+	bridgeCtx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	_, err := RunDeviceBridge(
+		bridgeCtx,
+		&BridgeOptions{
+			BleAddress:             suite.LuaApi.GetDevice().Address(),
+			BleConnectTimeout:      5 * time.Second,
+			PTYWriteService:        "ffe0",
+			PTYWriteCharacteristic: "ffe1",
+			PTYWriteWithResponse:   true,
+			Logger:                 suite.Logger,
+		},
+		nil,
+		func(b Bridge) (error, error) {
+			suite.Fail("Callback should not be reached")
+			return nil, nil
+		},
+	)
+
+	suite.Error(err, "Bridge must fail for unsupported write mode")
+	suite.ErrorIs(err, device.ErrUnsupported)
+}
+
 // TestBridgeTestSuite runs the test suite using testify/suite
 func TestBridgeTestSuite(t *testing.T) {
 	suite.Run(t, new(BridgeTestSuite))
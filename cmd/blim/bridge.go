@@ -5,8 +5,7 @@ import (
 	"errors"
 	"fmt"
 	"os"
-	"os/signal"
-	"syscall"
+	"strings"
 	"time"
 
 	"github.com/spf13/cobra"
@@ -27,6 +26,23 @@ The bridge creates a virtual serial device (e.g., /dev/ttys001) that application
 can connect to. Data written to the PTY is sent to the BLE device via the Nordic
 UART Service, and data received from the device is written to the PTY.
 
+Notification payloads of characteristics selected with --notify-to-pty are written
+raw to the PTY, so an interactive client on the PTY reads them as if the device
+typed them. Without the flag, notifications are only delivered to the Lua script.
+
+Data a client writes to the PTY is written to the characteristic selected with
+--pty-to-write, split into chunks that fit the connection's ATT MTU (without
+response unless --write-response is set). Together with --notify-to-pty this makes
+a transparent serial-over-BLE adapter without any Lua scripting.
+
+--allow-simulated-notifications enables blim.simulate_notification() in the Lua
+script, which feeds synthetic notifications through the regular dispatch path to
+test callbacks without the device sending data. It is always enabled with --simulate.
+
+--output-buffer controls how script output is written to stdout: "immediate" (default)
+writes every print, "size:<bytes>" batches writes until the buffer is full and
+"interval:<duration>" flushes at a fixed interval. Buffered output is flushed on shutdown.
+
 This is useful for:
 - Connecting terminal emulators to BLE devices
 - Using existing serial applications with BLE devices
@@ -36,8 +52,10 @@ This is useful for:
 Example:
   blim bridge %s
   blim bridge --service=custom-uuid %s
+  blim bridge --notify-to-pty 6e400001-b5a3-f393-e0a9-e50e24dcca9e/6e400003-b5a3-f393-e0a9-e50e24dcca9e %s
+  blim bridge --notify-to-pty 180d/2a37 --pty-to-write 180d/2a39 --write-response %s
 
-%s`, exampleDeviceAddress, exampleDeviceAddress, deviceAddressNote),
+%s`, exampleDeviceAddress, exampleDeviceAddress, exampleDeviceAddress, exampleDeviceAddress, deviceAddressNote),
 	Args: cobra.ExactArgs(1),
 	RunE: runBridge,
 }
@@ -50,6 +68,11 @@ var (
 	bridgeCharacteristicWriteTimeout time.Duration
 	bridgeLuaScript                  string
 	bridgeSymlink                    string
+	bridgeNotifyToPTY                []string
+	bridgePTYToWrite                 string
+	bridgeWriteResponse              bool
+	bridgeSimulatedNotifications     bool
+	bridgeOutputBuffer               string
 )
 
 func init() {
@@ -60,6 +83,41 @@ func init() {
 	bridgeCmd.Flags().DurationVar(&bridgeCharacteristicWriteTimeout, "characteristic-write-timeout", 0, "Timeout for characteristic write operations (0 = use default: 5s)")
 	bridgeCmd.Flags().StringVar(&bridgeLuaScript, "script", "", "Lua script file with ble_to_tty() and tty_to_ble() functions")
 	bridgeCmd.Flags().StringVar(&bridgeSymlink, "symlink", "", "Create a symlink to the PTY device (e.g., /tmp/ble-device)")
+	bridgeCmd.Flags().StringArrayVar(&bridgeNotifyToPTY, "notify-to-pty", nil, "Write notifications of <service/char> raw to the PTY (repeatable)")
+	bridgeCmd.Flags().StringVar(&bridgePTYToWrite, "pty-to-write", "", "Write data read from the PTY to <service/char>")
+	bridgeCmd.Flags().BoolVar(&bridgeWriteResponse, "write-response", false, "Use write-with-response for --pty-to-write (default: without response)")
+	bridgeCmd.Flags().BoolVar(&bridgeSimulatedNotifications, "allow-simulated-notifications", false, "Enable blim.simulate_notification() in the Lua script (always enabled with --simulate)")
+	bridgeCmd.Flags().StringVar(&bridgeOutputBuffer, "output-buffer", "immediate", "Lua script stdout buffering: immediate, size:<bytes> or interval:<duration> (batches writes for high-rate output)")
+}
+
+// parseCharacteristicPaths parses "<service>/<char>" references into subscribe options grouped by service,
+// preserving the order in which services first appear
+func parseCharacteristicPaths(flagName string, paths []string) ([]device.SubscribeOptions, error) {
+	var result []device.SubscribeOptions
+	for _, path := range paths {
+		serviceRef, charRef, ok := strings.Cut(path, "/")
+		if !ok {
+			return nil, fmt.Errorf("invalid --%s value %q: expected <service>/<char>", flagName, path)
+		}
+		uuids, err := device.ValidateUUID(serviceRef, charRef)
+		if err != nil {
+			return nil, fmt.Errorf("invalid --%s value %q: %w", flagName, path, err)
+		}
+
+		idx := -1
+		for i := range result {
+			if result[i].Service == uuids[0] {
+				idx = i
+				break
+			}
+		}
+		if idx < 0 {
+			result = append(result, device.SubscribeOptions{Service: uuids[0]})
+			idx = len(result) - 1
+		}
+		result[idx].Characteristics = append(result[idx].Characteristics, uuids[1])
+	}
+	return result, nil
 }
 
 func runBridge(cmd *cobra.Command, args []string) error {
@@ -81,18 +139,29 @@ func runBridge(cmd *cobra.Command, args []string) error {
 	}
 	serviceUUID := serviceUUIDs[0]
 
-	// Create context for graceful shutdown
-	ctx, cancel := context.WithCancel(context.Background())
-	defer cancel()
+	outputFlushPolicy, err := lua.ParseOutputFlushPolicy(bridgeOutputBuffer)
+	if err != nil {
+		return fmt.Errorf("invalid --output-buffer: %w", err)
+	}
+
+	notifyToPTY, err := parseCharacteristicPaths("notify-to-pty", bridgeNotifyToPTY)
+	if err != nil {
+		return err
+	}
+
+	var ptyWriteService, ptyWriteChar string
+	if bridgePTYToWrite != "" {
+		paths, err := parseCharacteristicPaths("pty-to-write", []string{bridgePTYToWrite})
+		if err != nil {
+			return err
+		}
+		ptyWriteService, ptyWriteChar = paths[0].Service, paths[0].Characteristics[0]
+	} else if bridgeWriteResponse {
+		return fmt.Errorf("--write-response requires --pty-to-write")
+	}
 
-	// Handle interrupts gracefully
-	sigChan := make(chan os.Signal, 1)
-	signal.Notify(sigChan, os.Interrupt, syscall.SIGTERM)
-	go func() {
-		<-sigChan
-		logger.Info("Received interrupt signal, shutting down...")
-		cancel()
-	}()
+	// Command context is canceled on SIGINT/SIGTERM for graceful shutdown
+	ctx := commandContext(cmd)
 
 	// Load script content before creating the callback
 	var scriptContent string
@@ -118,17 +187,31 @@ func runBridge(cmd *cobra.Command, args []string) error {
 	defer progress.Stop()
 
 	// Bridge callback - executes the Lua script with output streaming
+	simulated, _ := cmd.Flags().GetString("simulate")
+	allowSimulatedNotifications := bridgeSimulatedNotifications || simulated != ""
+
 	bridgeCallback := func(b bridge.Bridge) (any, error) {
+		// Leave no stale symlink or connection behind even if the bridge does not return after an interrupt
+		if symlink := b.GetTTYSymlink(); symlink != "" {
+			defer registerTeardown("remove "+symlink, removeSymlinkTeardown(symlink))()
+		}
+		defer registerTeardown("disconnect "+deviceAddress, disconnectTeardown(b.GetLuaAPI().GetDevice()))()
+
+		b.GetLuaAPI().SetSimulatedNotifications(allowSimulatedNotifications)
 
 		// HACK: Create an output drainer to capture output from the Lua API,
 		// 		even though the script execution completed, the bridge is keeping the Lua State open, using it by
 		// 		calling the Lua callbacks until the bridge is canceled.
-		drainer := lua.NewOutputDrainer(ctx, b.GetLuaAPI().OutputChannel(), logger, os.Stdout, os.Stderr)
+		stdout := lua.NewBufferedOutputWriter(os.Stdout, outputFlushPolicy)
+		drainer := lua.NewOutputDrainer(ctx, b.GetLuaAPI().OutputChannel(), logger, stdout, os.Stderr)
 
 		defer func() {
-			// Stop the drainer after a script completes
+			// Stop the drainer after a script completes, then flush what it buffered so no records are lost
 			drainer.Cancel()
 			drainer.Wait()
+			if err := stdout.Close(); err != nil {
+				logger.WithError(err).Warn("Failed to flush buffered script output")
+			}
 		}()
 
 		// Execute the Lua script
@@ -192,14 +275,24 @@ func runBridge(cmd *cobra.Command, args []string) error {
 		&bridge.BridgeOptions{
 			BleAddress:               deviceAddress,
 			BleConnectTimeout:        bridgeConnectTimeout,
+			BleDiscoveryTimeout:      discoveryTimeout,
+			BleConnectRetries:        connectRetries,
+			BleConnectRetryBackoff:   connectRetryBackoff,
+			BleByIdentifier:          connectByIdentifier,
+			BleGATTCacheDir:          gattCacheDir(),
 			BleDescriptorReadTimeout: bridgeDescriptorReadTimeout,
 			BleSubscribeOptions: []device.SubscribeOptions{
 				{
 					Service: serviceUUID,
 				},
 			},
-			Logger:         logger,
-			TTYSymlinkPath: bridgeSymlink,
+			NotifyToPTY:            notifyToPTY,
+			PTYWriteService:        ptyWriteService,
+			PTYWriteCharacteristic: ptyWriteChar,
+			PTYWriteWithResponse:   bridgeWriteResponse,
+			PTYWriteTimeout:        bridgeCharacteristicWriteTimeout,
+			Logger:                 logger,
+			TTYSymlinkPath:         bridgeSymlink,
 		},
 		progress.Callback(),
 		bridgeCallback,
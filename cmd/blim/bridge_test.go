@@ -7,6 +7,7 @@ import (
 	"time"
 
 	"github.com/sirupsen/logrus"
+	"github.com/srg/blim/internal/device"
 	"github.com/stretchr/testify/suite"
 )
 
@@ -80,12 +81,50 @@ func (suite *BridgeCmdTestSuite) SetupTest() {
 	bridgeServiceUUID = "6E400001-B5A3-F393-E0A9-E50E24DCCA9E"
 	bridgeConnectTimeout = 30 * time.Second
 	bridgeLuaScript = ""
+	bridgeNotifyToPTY = nil
 
 	// Reset command flags
 	bridgeCmd.ResetFlags()
 	bridgeCmd.Flags().StringVar(&bridgeServiceUUID, "service", "6E400001-B5A3-F393-E0A9-E50E24DCCA9E", "BLE service UUID to bridge with")
 	bridgeCmd.Flags().DurationVar(&bridgeConnectTimeout, "connect-timeout", 30*time.Second, "Connection timeout")
 	bridgeCmd.Flags().StringVar(&bridgeLuaScript, "script", "", "Lua script file")
+	bridgeCmd.Flags().StringArrayVar(&bridgeNotifyToPTY, "notify-to-pty", nil, "Write notifications of <service/char> raw to the PTY (repeatable)")
+}
+
+func (suite *BridgeCmdTestSuite) TestParseCharacteristicPaths() {
+	// GOAL: Verify --notify-to-pty values are validated, normalized, and grouped by service
+	//
+	// TEST SCENARIO: Parse valid and malformed <service>/<char> values → options or errors match expectations
+
+	suite.Run("groups characteristics by service", func() {
+		opts, err := parseCharacteristicPaths("notify-to-pty", []string{
+			"6E400001-B5A3-F393-E0A9-E50E24DCCA9E/6E400003-B5A3-F393-E0A9-E50E24DCCA9E",
+			"180d/2a37",
+			"6e400001b5a3f393e0a9e50e24dcca9e/6e400002b5a3f393e0a9e50e24dcca9e",
+		})
+		suite.Require().NoError(err)
+		suite.Equal([]device.SubscribeOptions{
+			{
+				Service:         "6e400001b5a3f393e0a9e50e24dcca9e",
+				Characteristics: []string{"6e400003b5a3f393e0a9e50e24dcca9e", "6e400002b5a3f393e0a9e50e24dcca9e"},
+			},
+			{Service: "180d", Characteristics: []string{"2a37"}},
+		}, opts)
+	})
+
+	suite.Run("no values", func() {
+		opts, err := parseCharacteristicPaths("notify-to-pty", nil)
+		suite.NoError(err)
+		suite.Empty(opts)
+	})
+
+	for _, value := range []string{"180d", "180d/", "/2a37"} {
+		suite.Run("invalid "+value, func() {
+			_, err := parseCharacteristicPaths("notify-to-pty", []string{value})
+			suite.Error(err)
+			suite.Contains(err.Error(), "--notify-to-pty")
+		})
+	}
 }
 
 //// TestBridgeCommandOutput tests runBridge function and verifies stdout output
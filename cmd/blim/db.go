@@ -0,0 +1,137 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+	"github.com/srg/blim/internal/bledb"
+)
+
+// dbCmd represents the db command
+var dbCmd = &cobra.Command{
+	Use:   "db",
+	Short: "Query the embedded Bluetooth UUID database",
+	Long: `Looks up services, characteristics, descriptors, vendor IDs and units in the UUID database
+embedded in blim. No device is needed.
+
+Examples:
+  # Name of a UUID (16-bit, 32-bit or full 128-bit form)
+  blim db lookup 2a37
+
+  # Find UUIDs by name
+  blim db search "heart rate"
+
+  # All known descriptors as JSON
+  blim db list descriptor --format json`,
+}
+
+var dbLookupCmd = &cobra.Command{
+	Use:   "lookup <uuid>",
+	Short: "Show the database entries of a UUID",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		entries := bledb.Lookup(args[0])
+		if len(entries) == 0 {
+			return fmt.Errorf("UUID %q not found in the database", args[0])
+		}
+		return printDBEntries(os.Stdout, entries, dbFormat)
+	},
+}
+
+var dbSearchCmd = &cobra.Command{
+	Use:   "search <name>",
+	Short: "Find entries whose name contains the text (case-insensitive)",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return printDBEntries(os.Stdout, bledb.Search(args[0]), dbFormat)
+	},
+}
+
+var dbListCmd = &cobra.Command{
+	Use:   "list <type>",
+	Short: "List all entries of a table (service, characteristic, descriptor, vendor, unit)",
+	Long: `Lists the entries of a table sorted by UUID. Large tables such as vendor can be narrowed with
+--filter and paged with --limit/--offset.
+
+Examples:
+  # Vendors whose ID or name contains "nordic"
+  blim db list vendor --filter nordic
+
+  # Third page of 50 vendors
+  blim db list vendor --limit 50 --offset 100`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		entries, err := bledb.Entries(bledb.Table(strings.ToLower(args[0])))
+		if err != nil {
+			return fmt.Errorf("%w: must be one of %v", err, bledb.Tables())
+		}
+		entries, err = paginateDBEntries(bledb.Filter(entries, dbListFilter), dbListOffset, dbListLimit)
+		if err != nil {
+			return err
+		}
+		return printDBEntries(os.Stdout, entries, dbFormat)
+	},
+}
+
+var (
+	dbFormat     string
+	dbListFilter string
+	dbListLimit  int
+	dbListOffset int
+)
+
+func init() {
+	dbCmd.PersistentFlags().StringVarP(&dbFormat, "format", "f", "table", "Output format (table, json)")
+	dbListCmd.Flags().StringVar(&dbListFilter, "filter", "", "Only list entries whose UUID or name contains this text (case-insensitive)")
+	dbListCmd.Flags().IntVar(&dbListLimit, "limit", 0, "Maximum number of entries to list (0 = all)")
+	dbListCmd.Flags().IntVar(&dbListOffset, "offset", 0, "Number of entries to skip (applied after --filter)")
+	dbCmd.AddCommand(dbLookupCmd)
+	dbCmd.AddCommand(dbSearchCmd)
+	dbCmd.AddCommand(dbListCmd)
+}
+
+// paginateDBEntries returns the page of entries starting at offset with at most limit entries (0 = no limit).
+// An offset past the end yields no entries.
+func paginateDBEntries(entries []bledb.Entry, offset, limit int) ([]bledb.Entry, error) {
+	if offset < 0 {
+		return nil, fmt.Errorf("invalid --offset %d: must not be negative", offset)
+	}
+	if limit < 0 {
+		return nil, fmt.Errorf("invalid --limit %d: must not be negative", limit)
+	}
+	if offset >= len(entries) {
+		return nil, nil
+	}
+	entries = entries[offset:]
+	if limit > 0 && limit < len(entries) {
+		entries = entries[:limit]
+	}
+	return entries, nil
+}
+
+// printDBEntries writes entries as an aligned table or a JSON array
+func printDBEntries(w io.Writer, entries []bledb.Entry, format string) error {
+	switch format {
+	case "json":
+		if entries == nil {
+			entries = []bledb.Entry{} // Scripts expect an array, not null
+		}
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(entries)
+	case "table":
+		tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(tw, "TYPE\tUUID\tNAME")
+		for _, e := range entries {
+			fmt.Fprintf(tw, "%s\t%s\t%s\n", e.Table, e.UUID, e.Name)
+		}
+		return tw.Flush()
+	default:
+		return fmt.Errorf("invalid format '%s': must be one of [table json]", format)
+	}
+}
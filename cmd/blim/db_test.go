@@ -0,0 +1,75 @@
+//go:build test
+
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/srg/blim/internal/bledb"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPrintDBEntries(t *testing.T) {
+	// GOAL: Verify db entries print as an aligned table or a JSON array, and unknown formats are rejected
+	//
+	// TEST SCENARIO: Print two entries as table and json → header, rows and decoded JSON match → empty json result is [] → invalid format errors
+
+	entries := []bledb.Entry{
+		{Table: bledb.TableService, UUID: "180d", Name: "Heart Rate"},
+		{Table: bledb.TableCharacteristic, UUID: "2a37", Name: "Heart Rate Measurement"},
+	}
+
+	var table bytes.Buffer
+	require.NoError(t, printDBEntries(&table, entries, "table"))
+	assert.Equal(t, "TYPE            UUID  NAME\n"+
+		"service         180d  Heart Rate\n"+
+		"characteristic  2a37  Heart Rate Measurement\n", table.String())
+
+	var out bytes.Buffer
+	require.NoError(t, printDBEntries(&out, entries, "json"))
+	var decoded []bledb.Entry
+	require.NoError(t, json.Unmarshal(out.Bytes(), &decoded))
+	assert.Equal(t, entries, decoded)
+
+	out.Reset()
+	require.NoError(t, printDBEntries(&out, nil, "json"))
+	assert.Equal(t, "[]\n", out.String(), "no results MUST encode as an empty array")
+
+	assert.Error(t, printDBEntries(&out, entries, "xml"))
+}
+
+func TestPaginateDBEntries(t *testing.T) {
+	// GOAL: Verify --offset/--limit select the expected page and reject negative values
+	//
+	// TEST SCENARIO: Page five entries with various offsets and limits → expected slices → offset past the end is empty → negative values error
+
+	var entries []bledb.Entry
+	for _, uuid := range []string{"1800", "1801", "180a", "180d", "180f"} {
+		entries = append(entries, bledb.Entry{Table: bledb.TableService, UUID: uuid})
+	}
+
+	for _, tc := range []struct {
+		offset, limit int
+		want          []bledb.Entry
+	}{
+		{0, 0, entries},
+		{0, 2, entries[:2]},
+		{2, 2, entries[2:4]},
+		{3, 10, entries[3:]},
+		{4, 0, entries[4:]},
+		{5, 0, nil},
+		{9, 1, nil},
+	} {
+		page, err := paginateDBEntries(entries, tc.offset, tc.limit)
+		require.NoError(t, err)
+		assert.Equal(t, tc.want, page, "offset %d, limit %d", tc.offset, tc.limit)
+	}
+
+	_, err := paginateDBEntries(entries, -1, 0)
+	assert.Error(t, err)
+	_, err = paginateDBEntries(entries, 0, -1)
+	assert.Error(t, err)
+}
@@ -0,0 +1,43 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// httpShutdownTimeout bounds the graceful shutdown of the subscribe HTTP endpoints
+const httpShutdownTimeout = 2 * time.Second
+
+// startHTTPEndpoint serves handler at path on addr. The listener is bound before returning so address
+// errors are reported up front; the returned function shuts the server down.
+func startHTTPEndpoint(name, addr, path string, handler http.Handler, logger *logrus.Logger) (func(), error) {
+	listener, err := net.Listen("tcp", addr)
+	if err != nil {
+		return nil, fmt.Errorf("failed to listen on %s address %s: %w", name, addr, err)
+	}
+
+	mux := http.NewServeMux()
+	mux.Handle(path, handler)
+	server := &http.Server{Handler: mux, ReadHeaderTimeout: 5 * time.Second}
+
+	go func() {
+		if err := server.Serve(listener); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			logger.WithError(err).Errorf("%s server failed", name)
+		}
+	}()
+	logger.WithField("addr", listener.Addr().String()).Infof("Serving %s at %s", name, path)
+
+	return func() {
+		ctx, cancel := context.WithTimeout(context.Background(), httpShutdownTimeout)
+		defer cancel()
+		if err := server.Shutdown(ctx); err != nil {
+			logger.WithError(err).Warnf("Failed to shut down %s server", name)
+		}
+	}, nil
+}
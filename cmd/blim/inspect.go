@@ -5,8 +5,6 @@ import (
 	"errors"
 	"fmt"
 	"os"
-	"os/signal"
-	"syscall"
 	"time"
 
 	"github.com/sirupsen/logrus"
@@ -24,6 +22,7 @@ const (
 	defaultDescriptorReadTimeout     = 0               // 0 means use inspector's default (2s); explicit 0 skips reads
 	defaultCharacteristicReadTimeout = 5 * time.Second // Timeout for characteristic read operations
 	luaOutputPollInterval            = 50 * time.Millisecond
+	defaultWatchInterval             = 1 * time.Second
 )
 
 // inspectCmd represents the inspect command
@@ -31,7 +30,18 @@ var inspectCmd = &cobra.Command{
 	Use:   "inspect <device-address>",
 	Short: "Inspect services, characteristics, and descriptors of a BLE device",
 	Long: `Connects to a BLE device by address and discovers its services,
-characteristics, and descriptors. Attempts to read characteristic values when possible.`,
+characteristics, and descriptors. Attempts to read characteristic values when possible.
+
+Use --read-values for a full snapshot of the device's readable state (e.g., for bug reports):
+every readable characteristic is read and its value is included as hex (and decoded when a
+parser exists); characteristics that fail to read show the error inline.
+
+Use --watch for a live view: notifiable characteristics are subscribed, readable ones are
+re-read every --watch-interval, and the tree is redrawn in place until Ctrl+C.
+
+Use --format dot to export the GATT hierarchy as a GraphViz graph (device as root, services
+as clusters, characteristics and descriptors as nodes), e.g.:
+  blim inspect <device-address> --format dot | dot -Tpng -o gatt.png`,
 	Args: cobra.ExactArgs(1),
 	RunE: runInspect,
 }
@@ -42,6 +52,12 @@ var (
 	inspectPreScanTimeout            time.Duration
 	inspectCharacteristicReadTimeout time.Duration
 	inspectJSON                      bool
+	inspectFormat                    string
+	inspectReadValues                bool
+	inspectWatch                     bool
+	inspectWatchInterval             time.Duration
+	inspectNoDescriptors             bool
+	inspectOnlyServices              []string
 )
 
 func init() {
@@ -49,7 +65,13 @@ func init() {
 	inspectCmd.Flags().DurationVar(&inspectDescriptorReadTimeout, "descriptor-timeout", defaultDescriptorReadTimeout, "Timeout for reading descriptor values (default: 2s if unset, 0 to skip descriptor reads)")
 	inspectCmd.Flags().DurationVar(&inspectPreScanTimeout, "pre-scan-timeout", defaultPreScanTimeout, "Pre-scan timeout to capture advertisement data (0 to skip)")
 	inspectCmd.Flags().DurationVar(&inspectCharacteristicReadTimeout, "characteristic-read-timeout", defaultCharacteristicReadTimeout, "Timeout for reading characteristic values")
+	inspectCmd.Flags().BoolVar(&inspectNoDescriptors, "no-descriptors", false, "Skip descriptor discovery for a faster connect (characteristics are listed without descriptors)")
+	inspectCmd.Flags().StringArrayVar(&inspectOnlyServices, "only-service", nil, "Discover only this service (repeatable); faster connect on devices with many services")
 	inspectCmd.Flags().BoolVar(&inspectJSON, "json", false, "Output as JSON")
+	inspectCmd.Flags().StringVar(&inspectFormat, "format", "text", "Output format: text, json, or dot (GraphViz)")
+	inspectCmd.Flags().BoolVar(&inspectReadValues, "read-values", false, "Read every readable characteristic and include its value (hex and decoded); read errors are shown inline")
+	inspectCmd.Flags().BoolVar(&inspectWatch, "watch", false, "Live view: subscribe to notifications, periodically re-read values and redraw the tree in place")
+	inspectCmd.Flags().DurationVar(&inspectWatchInterval, "watch-interval", defaultWatchInterval, "Interval between reads of readable characteristics in --watch mode")
 }
 
 // preScanForAdvertisement performs a brief scan to find the target device and capture its advertisement.
@@ -95,27 +117,41 @@ func runInspect(cmd *cobra.Command, args []string) error {
 		return err
 	}
 
+	if inspectWatch && inspectJSON {
+		return fmt.Errorf("--watch cannot be combined with --json")
+	}
+
+	format, err := resolveInspectFormat()
+	if err != nil {
+		return err
+	}
+	if inspectWatch && format != "text" {
+		return fmt.Errorf("--watch cannot be combined with --format %s", format)
+	}
+
 	// All arguments validated - don't show usage on runtime errors
 	cmd.SilenceUsage = true
 
-	// Setup context with cancellation for graceful shutdown on SIGINT/SIGTERM
-	ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
-	defer cancel()
+	// Command context is canceled on SIGINT/SIGTERM for graceful shutdown
+	ctx := commandContext(cmd)
 
-	// Setup progress printer (disabled for JSON output)
+	// Setup progress printer (disabled for JSON and DOT output)
 	var progressCallback func(string)
-	if !inspectJSON {
+	stopProgress := func() {}
+	if format == "text" {
 		progress := NewProgressPrinter(fmt.Sprintf("Inspecting device %s", address), "Connecting", "Processing results")
 		progress.Start()
 		defer progress.Stop()
 		progressCallback = progress.Callback()
+		stopProgress = progress.Stop
 	} else {
 		progressCallback = NoOpProgressCallback()
 	}
 
 	// Attempt pre-scan to capture advertisement data (manufacturer data, RSSI, etc.)
 	var adv device.Advertisement
-	if inspectPreScanTimeout > 0 {
+	// Skipped with --identifier, which exists to avoid scanning before connecting
+	if inspectPreScanTimeout > 0 && !connectByIdentifier {
 		progressCallback("Pre-scanning")
 
 		// Create timeout context for pre-scan
@@ -147,34 +183,66 @@ func runInspect(cmd *cobra.Command, args []string) error {
 	opts := &inspector.InspectOptions{
 		ConnectTimeout:            inspectConnectTimeout,
 		DescriptorReadTimeout:     inspectDescriptorReadTimeout,
+		SkipDescriptors:           inspectNoDescriptors,
+		OnlyServices:              inspectOnlyServices,
 		CharacteristicReadTimeout: inspectCharacteristicReadTimeout,
+		ConnectRetries:            connectRetries,
+		ConnectRetryBackoff:       connectRetryBackoff,
+		ByIdentifier:              connectByIdentifier,
+		GATTCacheDir:              gattCacheDir(),
+		DiscoveryTimeout:          discoveryTimeout,
 	}
 
 	// Use a Lua script for output generation
 	processDevice := func(dev device.Device) (any, error) {
+		// Disconnect on exit even if the operation does not return after an interrupt
+		defer registerTeardown("disconnect "+address, disconnectTeardown(dev))()
+
 		// Update the device with advertisement data if we have it
 		if adv != nil {
 			dev.Update(adv)
 		}
-		return nil, executeInspectLuaScript(ctx, dev, logger, opts.CharacteristicReadTimeout)
+		if inspectWatch {
+			conn := dev.GetConnection()
+			if conn == nil {
+				return nil, fmt.Errorf("device not connected")
+			}
+			stopProgress() // The watch view owns the terminal
+			return nil, runInspectWatch(ctx, conn, address, os.Stdout, inspectWatchInterval, opts.CharacteristicReadTimeout, logger)
+		}
+		return nil, executeInspectLuaScript(ctx, dev, logger, format, opts.CharacteristicReadTimeout)
 	}
 
 	_, err = inspector.InspectDevice(ctx, address, opts, logger, progressCallback, processDevice)
 	return err
 }
 
-// executeInspectLuaScript runs the embedded inspect.lua script with the connected device
-func executeInspectLuaScript(ctx context.Context, dev device.Device, logger *logrus.Logger, characteristicReadTimeout time.Duration) error {
-	// Determine format based on --json flag
-	format := "text"
+// resolveInspectFormat returns the output format selected by --format, with --json as a shorthand for --format json
+func resolveInspectFormat() (string, error) {
+	switch inspectFormat {
+	case "text", "json", "dot":
+	default:
+		return "", fmt.Errorf("invalid --format %q: must be text, json, or dot", inspectFormat)
+	}
+
 	if inspectJSON {
-		format = "json"
+		if inspectFormat != "text" && inspectFormat != "json" {
+			return "", fmt.Errorf("--json cannot be combined with --format %s", inspectFormat)
+		}
+		return "json", nil
 	}
+	return inspectFormat, nil
+}
 
+// executeInspectLuaScript runs the embedded inspect.lua script with the connected device
+func executeInspectLuaScript(ctx context.Context, dev device.Device, logger *logrus.Logger, format string, characteristicReadTimeout time.Duration) error {
 	// Prepare script arguments
 	args := map[string]string{
 		"format": format,
 	}
+	if inspectReadValues {
+		args["read_values"] = "true"
+	}
 
 	// Execute the embedded script with output streaming
 	// Note: Write timeout is 0 because the inspect command only does read characteristics
@@ -3,7 +3,10 @@
 package main
 
 import (
+	"bytes"
 	"context"
+	"encoding/json"
+	"strings"
 	"testing"
 	"time"
 
@@ -22,6 +25,10 @@ type InspectTestSuite struct {
 		preScanTimeout            time.Duration
 		characteristicReadTimeout time.Duration
 		json                      bool
+		format                    string
+		readValues                bool
+		watch                     bool
+		watchInterval             time.Duration
 	}
 }
 
@@ -36,6 +43,10 @@ func (suite *InspectTestSuite) SetupSuite() {
 	suite.originalFlags.preScanTimeout = inspectPreScanTimeout
 	suite.originalFlags.characteristicReadTimeout = inspectCharacteristicReadTimeout
 	suite.originalFlags.json = inspectJSON
+	suite.originalFlags.format = inspectFormat
+	suite.originalFlags.readValues = inspectReadValues
+	suite.originalFlags.watch = inspectWatch
+	suite.originalFlags.watchInterval = inspectWatchInterval
 }
 
 // TearDownSuite restores original flags after all tests
@@ -45,6 +56,10 @@ func (suite *InspectTestSuite) TearDownSuite() {
 	inspectPreScanTimeout = suite.originalFlags.preScanTimeout
 	inspectCharacteristicReadTimeout = suite.originalFlags.characteristicReadTimeout
 	inspectJSON = suite.originalFlags.json
+	inspectFormat = suite.originalFlags.format
+	inspectReadValues = suite.originalFlags.readValues
+	inspectWatch = suite.originalFlags.watch
+	inspectWatchInterval = suite.originalFlags.watchInterval
 }
 
 // SetupTest initializes each test with a mock peripheral
@@ -97,6 +112,10 @@ func (suite *InspectTestSuite) SetupTest() {
 	inspectPreScanTimeout = defaultPreScanTimeout
 	inspectCharacteristicReadTimeout = defaultCharacteristicReadTimeout
 	inspectJSON = false
+	inspectFormat = "text"
+	inspectReadValues = false
+	inspectWatch = false
+	inspectWatchInterval = defaultWatchInterval
 
 	// Reset command flags
 	inspectCmd.ResetFlags()
@@ -105,6 +124,10 @@ func (suite *InspectTestSuite) SetupTest() {
 	inspectCmd.Flags().DurationVar(&inspectPreScanTimeout, "pre-scan-timeout", defaultPreScanTimeout, "Pre-scan timeout to capture advertisement data (0 to skip)")
 	inspectCmd.Flags().DurationVar(&inspectCharacteristicReadTimeout, "characteristic-read-timeout", defaultCharacteristicReadTimeout, "Timeout for reading characteristic values")
 	inspectCmd.Flags().BoolVar(&inspectJSON, "json", false, "Output as JSON")
+	inspectCmd.Flags().StringVar(&inspectFormat, "format", "text", "Output format: text, json, or dot (GraphViz)")
+	inspectCmd.Flags().BoolVar(&inspectReadValues, "read-values", false, "Read every readable characteristic and include its value (hex and decoded); read errors are shown inline")
+	inspectCmd.Flags().BoolVar(&inspectWatch, "watch", false, "Live view: subscribe to notifications, periodically re-read values and redraw the tree in place")
+	inspectCmd.Flags().DurationVar(&inspectWatchInterval, "watch-interval", defaultWatchInterval, "Interval between reads of readable characteristics in --watch mode")
 }
 
 // Helper methods
@@ -402,6 +425,175 @@ func (suite *InspectTestSuite) TestInspectDevice() {
 	}
 }
 
+func (suite *InspectTestSuite) TestInspectReadValues() {
+	// GOAL: Verify --read-values includes hex values and shows read errors inline instead of aborting
+	//
+	// TEST SCENARIO: Peripheral with a fast and a slow readable characteristic → inspect --read-values --json with short read timeout → fast has value_hex, slow has read_error, command succeeds
+
+	suite.PeripheralBuilder = testutils.NewPeripheralDeviceBuilder(suite.T())
+	suite.WithPeripheral().
+		WithService("180d").
+		WithCharacteristic("2a38", "read", []byte{0x01, 0xAB}).
+		WithCharacteristic("2a39", "read", []byte{0x02}, testutils.WithReadDelay(500*time.Millisecond))
+	suite.CommandTestSuite.SetupTest()
+
+	inspectPreScanTimeout = 0
+	inspectConnectTimeout = 5 * time.Second
+	inspectCharacteristicReadTimeout = 100 * time.Millisecond
+	inspectJSON = true
+	inspectReadValues = true
+
+	var err error
+	outputStr := suite.CaptureStdout(func() {
+		err = runInspect(inspectCmd, []string{"AA:BB:CC:DD:EE:FF"})
+	})
+	suite.Require().NoError(err, "read errors MUST NOT abort the dump")
+
+	var output struct {
+		Services []struct {
+			UUID            string `json:"uuid"`
+			Characteristics []struct {
+				UUID      string `json:"uuid"`
+				ValueHex  string `json:"value_hex"`
+				ReadError string `json:"read_error"`
+			} `json:"characteristics"`
+		} `json:"services"`
+	}
+	suite.Require().NoError(json.Unmarshal([]byte(outputStr), &output), "output MUST be valid JSON")
+	suite.Require().Len(output.Services, 1, "MUST contain one service")
+
+	chars := output.Services[0].Characteristics
+	suite.Require().Len(chars, 2, "MUST contain both characteristics")
+	suite.Assert().Equal("01AB", chars[0].ValueHex, "readable characteristic MUST include hex value")
+	suite.Assert().Empty(chars[0].ReadError, "successful read MUST NOT report an error")
+	suite.Assert().Empty(chars[1].ValueHex, "failed read MUST NOT include a value")
+	suite.Assert().Contains(chars[1].ReadError, "read() failed", "failed read MUST report the error inline")
+}
+
+func (suite *InspectTestSuite) TestInspectDot() {
+	suite.Run("renders GATT hierarchy", func() {
+		// GOAL: Verify --format dot emits a GraphViz graph with the device as root, services as clusters and characteristics as nodes
+		//
+		// TEST SCENARIO: Run inspect --format dot → output is a digraph → one cluster per service → characteristics labeled with UUID and properties
+
+		inspectPreScanTimeout = 0
+		inspectConnectTimeout = 5 * time.Second
+		inspectFormat = "dot"
+
+		var err error
+		output := suite.CaptureStdout(func() {
+			err = runInspect(inspectCmd, []string{"AA:BB:CC:DD:EE:FF"})
+		})
+		suite.Require().NoError(err, "runInspect MUST succeed")
+
+		suite.Assert().True(strings.HasPrefix(output, "digraph gatt {"), "output MUST start with the graph header, got: %q", output)
+		suite.Assert().True(strings.HasSuffix(strings.TrimSpace(output), "}"), "output MUST close the graph")
+		suite.Assert().Contains(output, `device [label="AA:BB:CC:DD:EE:FF"`, "device MUST be the root node")
+		suite.Assert().Equal(2, strings.Count(output, "subgraph cluster_"), "each service MUST be a cluster")
+		suite.Assert().Contains(output, "device -> s1;", "device MUST link to the first service")
+		suite.Assert().Contains(output, "device -> s2;", "device MUST link to the second service")
+		suite.Assert().Contains(output, "(0x180d)", "service clusters MUST be labeled with the UUID")
+		suite.Assert().Regexp(`s2_c1 \[label="[^"]*\(0x2a37\)\\n[^"]*Notify`, output, "characteristic nodes MUST include UUID and properties")
+		suite.Assert().Contains(output, "s2 -> s2_c1;", "characteristics MUST hang off their service")
+	})
+
+	suite.Run("rejects invalid combinations", func() {
+		// GOAL: Verify --format is validated and conflicts with --json and --watch are rejected
+		//
+		// TEST SCENARIO: Unknown format / --json with dot / --watch with dot → runInspect fails before connecting
+
+		cases := []struct {
+			format, expected string
+			json, watch      bool
+		}{
+			{format: "yaml", expected: `invalid --format "yaml"`},
+			{format: "dot", json: true, expected: "--json cannot be combined with --format dot"},
+			{format: "dot", watch: true, expected: "--watch cannot be combined with --format dot"},
+		}
+		for _, tc := range cases {
+			inspectFormat, inspectJSON, inspectWatch = tc.format, tc.json, tc.watch
+			err := runInspect(inspectCmd, []string{"AA:BB:CC:DD:EE:FF"})
+			suite.Require().Error(err, "format %q MUST fail", tc.format)
+			suite.Assert().Contains(err.Error(), tc.expected)
+		}
+	})
+}
+
+func (suite *InspectTestSuite) TestInspectWatch() {
+	suite.Run("redraws tree with current values", func() {
+		// GOAL: Verify the watch view renders read values, redraws in place and restores the cursor on exit
+		//
+		// TEST SCENARIO: Connect → run watch view for a few intervals → output has ANSI redraws, decoded values, restored cursor
+
+		inspectPreScanTimeout = 0
+
+		var out bytes.Buffer
+		ctx, cancel := suite.createTestContext(5 * time.Second)
+		defer cancel()
+
+		_, err := inspector.InspectDevice(ctx, "AA:BB:CC:DD:EE:FF", suite.createInspectOptions(), suite.Logger, nil,
+			func(dev device.Device) (any, error) {
+				watchCtx, watchCancel := context.WithTimeout(ctx, 250*time.Millisecond)
+				defer watchCancel()
+				return nil, runInspectWatch(watchCtx, dev.GetConnection(), "AA:BB:CC:DD:EE:FF", &out, 50*time.Millisecond, time.Second, suite.Logger)
+			})
+		suite.Require().NoError(err, "watch MUST exit cleanly when the context is done")
+
+		output := out.String()
+		suite.Assert().True(strings.HasPrefix(output, ansiHideCursor+ansiClearScreen), "watch MUST hide the cursor and clear the screen")
+		suite.Assert().True(strings.HasSuffix(output, ansiShowCursor), "watch MUST restore the cursor on exit")
+		suite.Assert().Greater(strings.Count(output, ansiCursorHome), 1, "watch MUST redraw periodically")
+		suite.Assert().Contains(output, "Service 180a", "tree MUST include services")
+		suite.Assert().Contains(output, "54657374", "tree MUST include read values")
+		suite.Assert().Contains(output, "[read,notify]", "tree MUST include characteristic properties")
+	})
+
+	suite.Run("rejects --json", func() {
+		// GOAL: Verify --watch cannot be combined with --json
+		//
+		// TEST SCENARIO: Set --watch and --json → runInspect fails before connecting
+
+		inspectWatch = true
+		inspectJSON = true
+
+		err := runInspect(inspectCmd, []string{"AA:BB:CC:DD:EE:FF"})
+		suite.Require().Error(err, "--watch with --json MUST fail")
+		suite.Assert().Contains(err.Error(), "--watch cannot be combined with --json")
+	})
+
+	suite.Run("aborts stalled reads when the context is done", func() {
+		// GOAL: Verify a read that outlives the watch context does not delay the exit by the read timeout
+		//
+		// TEST SCENARIO: Characteristic read stalls for 2s, read timeout 10s → watch context ends after 200ms → watch returns well before the stall ends
+
+		suite.PeripheralBuilder = testutils.NewPeripheralDeviceBuilder(suite.T())
+		suite.WithPeripheral().
+			WithService("180d").
+			WithCharacteristic("2a39", "read", []byte{0x02}, testutils.WithReadDelay(2*time.Second))
+		suite.CommandTestSuite.SetupTest()
+
+		inspectPreScanTimeout = 0
+
+		var out bytes.Buffer
+		ctx, cancel := suite.createTestContext(5 * time.Second)
+		defer cancel()
+
+		var elapsed time.Duration
+		_, err := inspector.InspectDevice(ctx, "AA:BB:CC:DD:EE:FF", suite.createInspectOptions(), suite.Logger, nil,
+			func(dev device.Device) (any, error) {
+				watchCtx, watchCancel := context.WithTimeout(ctx, 200*time.Millisecond)
+				defer watchCancel()
+				start := time.Now()
+				err := runInspectWatch(watchCtx, dev.GetConnection(), "AA:BB:CC:DD:EE:FF", &out, 50*time.Millisecond, 10*time.Second, suite.Logger)
+				elapsed = time.Since(start)
+				return nil, err
+			})
+		suite.Require().NoError(err, "watch MUST exit cleanly when the context is done")
+		suite.Assert().Less(elapsed, time.Second, "watch MUST NOT wait for the stalled read to finish")
+		suite.Assert().True(strings.HasSuffix(out.String(), ansiShowCursor), "watch MUST restore the cursor on exit")
+	})
+}
+
 // TestInspectTestSuite runs the test suite
 func TestInspectTestSuite(t *testing.T) {
 	suite.Run(t, new(InspectTestSuite))
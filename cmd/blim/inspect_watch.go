@@ -0,0 +1,230 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/srg/blim/internal/device"
+)
+
+// ANSI sequences used by the watch view
+const (
+	ansiHideCursor   = "\033[?25l"
+	ansiShowCursor   = "\033[?25h"
+	ansiClearScreen  = "\033[2J"
+	ansiCursorHome   = "\033[H"
+	ansiClearLineEnd = "\033[K"
+	ansiClearBelow   = "\033[J"
+)
+
+// watchValue is the latest known value of a characteristic in the watch view
+type watchValue struct {
+	data []byte
+	err  error
+	at   time.Time
+}
+
+// watchView renders the GATT tree with live characteristic values
+type watchView struct {
+	conn    device.Connection
+	address string
+	out     io.Writer
+
+	mu     sync.Mutex
+	values map[string]watchValue // key: service/characteristic
+	redraw chan struct{}
+}
+
+func watchKey(service, char string) string {
+	return service + "/" + char
+}
+
+// runInspectWatch subscribes to all notifiable characteristics, periodically reads readable ones,
+// and redraws the tree in place until ctx is done or the connection is lost.
+// The terminal cursor is restored on exit.
+func runInspectWatch(ctx context.Context, conn device.Connection, address string, out io.Writer, interval, readTimeout time.Duration, logger *logrus.Logger) error {
+	if interval <= 0 {
+		return fmt.Errorf("watch interval must be positive: %v", interval)
+	}
+
+	v := &watchView{
+		conn:    conn,
+		address: address,
+		out:     out,
+		values:  make(map[string]watchValue),
+		redraw:  make(chan struct{}, 1),
+	}
+
+	_, _ = fmt.Fprint(out, ansiHideCursor+ansiClearScreen)
+	defer func() { _, _ = fmt.Fprint(out, ansiShowCursor) }()
+
+	if err := v.subscribe(); err != nil {
+		// Watching still works for readable characteristics
+		logger.WithError(err).Warn("Failed to subscribe to notifications")
+	}
+
+	v.readAll(ctx, readTimeout)
+	v.render()
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	connCtx := conn.ConnectionContext()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-connCtx.Done():
+			return ErrConnectionLost
+		case <-ticker.C:
+			v.readAll(ctx, readTimeout)
+			v.render()
+		case <-v.redraw:
+			v.render()
+		}
+	}
+}
+
+// subscribe subscribes to every notifiable characteristic, preferring notifications over indications.
+// Each service gets its own subscription: records carry characteristic UUIDs only,
+// so the callback needs to know the service to store values under the right key.
+func (v *watchView) subscribe() error {
+	var errs []error
+	for _, svc := range v.conn.Services() {
+		var notify, indicate []string
+		for _, char := range svc.GetCharacteristics() {
+			if !supportsNotifications(char) {
+				continue
+			}
+			if props := char.GetProperties(); props.Notify() != nil && props.Notify().Value() != 0 {
+				notify = append(notify, char.UUID())
+			} else {
+				indicate = append(indicate, char.UUID())
+			}
+		}
+
+		var opts []*device.SubscribeOptions
+		if len(notify) > 0 {
+			opts = append(opts, &device.SubscribeOptions{Service: svc.UUID(), Characteristics: notify})
+		}
+		if len(indicate) > 0 {
+			opts = append(opts, &device.SubscribeOptions{Service: svc.UUID(), Characteristics: indicate, Indicate: true})
+		}
+		if len(opts) == 0 {
+			continue
+		}
+
+		service := svc.UUID()
+		if err := v.conn.Subscribe(opts, device.StreamEveryUpdate, device.StreamOptions{}, func(record *device.Record) {
+			v.store(service, record)
+		}); err != nil {
+			errs = append(errs, fmt.Errorf("service %s: %w", service, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// store records the notified values of a service and requests a redraw
+func (v *watchView) store(service string, record *device.Record) {
+	now := time.Now()
+	v.mu.Lock()
+	for charUUID, data := range record.Values {
+		v.values[watchKey(service, charUUID)] = watchValue{data: append([]byte(nil), data...), at: now}
+	}
+	v.mu.Unlock()
+
+	select {
+	case v.redraw <- struct{}{}:
+	default: // Redraw already pending
+	}
+}
+
+// readAll reads every readable characteristic and stores the result (or the read error).
+// Each read is bounded by timeout and aborted when ctx is done, so a stalled device does not delay shutdown.
+func (v *watchView) readAll(ctx context.Context, timeout time.Duration) {
+	for _, svc := range v.conn.Services() {
+		for _, char := range svc.GetCharacteristics() {
+			if props := char.GetProperties(); props.Read() == nil || props.Read().Value() == 0 {
+				continue
+			}
+			if ctx.Err() != nil {
+				return
+			}
+			readCtx, cancel := context.WithTimeout(ctx, timeout)
+			data, err := char.ReadCtx(readCtx)
+			cancel()
+			if ctx.Err() != nil {
+				return // Canceled mid-read: keep the last value rather than showing the cancellation
+			}
+			v.mu.Lock()
+			v.values[watchKey(svc.UUID(), char.UUID())] = watchValue{data: data, err: err, at: time.Now()}
+			v.mu.Unlock()
+		}
+	}
+}
+
+// render redraws the whole tree from the top-left corner, clearing leftovers of the previous frame
+func (v *watchView) render() {
+	var b strings.Builder
+	line := func(format string, args ...any) {
+		b.WriteString(fmt.Sprintf(format, args...))
+		b.WriteString(ansiClearLineEnd + "\n")
+	}
+
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	b.WriteString(ansiCursorHome)
+	line("Watching %s (updated %s). Press Ctrl+C to stop...", v.address, time.Now().Format("15:04:05"))
+	line("")
+	for _, svc := range v.conn.Services() {
+		line("Service %s", formatWatchName(svc.UUID(), svc.KnownName()))
+		for _, char := range svc.GetCharacteristics() {
+			value := "-"
+			if wv, ok := v.values[watchKey(svc.UUID(), char.UUID())]; ok {
+				if wv.err != nil {
+					value = fmt.Sprintf("error: %v", wv.err)
+				} else {
+					value = fmt.Sprintf("%s  (%s ago)", formatDecodedValue(char.UUID(), wv.data), time.Since(wv.at).Truncate(100*time.Millisecond))
+				}
+			}
+			line("  %-40s [%s]  %s", formatWatchName(char.UUID(), char.KnownName()), formatWatchProperties(char.GetProperties()), value)
+		}
+	}
+	b.WriteString(ansiClearBelow)
+
+	_, _ = io.WriteString(v.out, b.String())
+}
+
+func formatWatchName(uuid, name string) string {
+	if name == "" {
+		return uuid
+	}
+	return fmt.Sprintf("%s %s", uuid, name)
+}
+
+// formatWatchProperties returns the short names of the read/write/notify/indicate properties
+func formatWatchProperties(props device.Properties) string {
+	var names []string
+	for _, p := range []struct {
+		name string
+		prop device.Property
+	}{
+		{"read", props.Read()},
+		{"write", props.Write()},
+		{"write-nr", props.WriteWithoutResponse()},
+		{"notify", props.Notify()},
+		{"indicate", props.Indicate()},
+	} {
+		if p.prop != nil && p.prop.Value() != 0 {
+			names = append(names, p.name)
+		}
+	}
+	return strings.Join(names, ",")
+}
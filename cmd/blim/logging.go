@@ -2,14 +2,19 @@ package main
 
 import (
 	"fmt"
+	"io"
 	"time"
 
 	"github.com/sirupsen/logrus"
 	"github.com/spf13/cobra"
 )
 
+// quietOutput is set by --quiet: logs are capped at error level and decorative output is suppressed
+var quietOutput bool
+
 // configureLogger creates a logger with the appropriate log level based on flags.
-// It respects both --log-level and --verbose flags, with --log-level taking precedence.
+// It respects both --log-level and --verbose flags, with --log-level taking precedence;
+// --quiet caps the resulting level at error.
 // Returns a configured logger or error if the log-level is invalid.
 func configureLogger(cmd *cobra.Command, verboseFlagName string) (*logrus.Logger, error) {
 	// Default to panic level (essentially silent for normal operations)
@@ -38,6 +43,10 @@ func configureLogger(cmd *cobra.Command, verboseFlagName string) (*logrus.Logger
 		}
 	}
 
+	if quietOutput && logLevel > logrus.ErrorLevel {
+		logLevel = logrus.ErrorLevel
+	}
+
 	// Create logger with configured level
 	logger := logrus.New()
 	logger.SetLevel(logLevel)
@@ -48,3 +57,12 @@ func configureLogger(cmd *cobra.Command, verboseFlagName string) (*logrus.Logger
 
 	return logger, nil
 }
+
+// printInfof writes a decorative message (banner, hint, confirmation) to w unless --quiet is set.
+// Command data (values, JSON, tables) is printed directly.
+func printInfof(w io.Writer, format string, a ...any) {
+	if quietOutput {
+		return
+	}
+	fmt.Fprintf(w, format, a...)
+}
@@ -0,0 +1,46 @@
+//go:build test
+
+package main
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestQuietOutput(t *testing.T) {
+	// GOAL: Verify --quiet caps the log level at error and suppresses decorative output only
+	//
+	// TEST SCENARIO: --log-level debug with --quiet → error level → default level unchanged → printInfof prints nothing → without --quiet it prints
+
+	t.Cleanup(func() { quietOutput = false })
+
+	newCommand := func(logLevel string) *cobra.Command {
+		cmd := &cobra.Command{Use: "test"}
+		cmd.Flags().String("log-level", "", "")
+		cmd.Flags().Bool("verbose", false, "")
+		require.NoError(t, cmd.Flags().Set("log-level", logLevel))
+		return cmd
+	}
+
+	quietOutput = true
+	logger, err := configureLogger(newCommand("debug"), "verbose")
+	require.NoError(t, err)
+	assert.Equal(t, logrus.ErrorLevel, logger.GetLevel(), "--quiet MUST cap the log level at error")
+
+	logger, err = configureLogger(newCommand(""), "verbose")
+	require.NoError(t, err)
+	assert.Equal(t, logrus.PanicLevel, logger.GetLevel(), "--quiet MUST NOT make the default level more verbose")
+
+	var out bytes.Buffer
+	printInfof(&out, "Write successful\n")
+	assert.Empty(t, out.String(), "decorative output MUST be suppressed with --quiet")
+
+	quietOutput = false
+	printInfof(&out, "Write successful\n")
+	assert.Equal(t, "Write successful\n", out.String())
+}
@@ -5,11 +5,37 @@ import (
 	"errors"
 	"fmt"
 	"os"
+	"os/signal"
+	"path/filepath"
+	"syscall"
+	"time"
 	"unicode"
 
 	"github.com/spf13/cobra"
+	"github.com/srg/blim/internal/devicefactory"
 )
 
+// Connection settings shared by all connecting commands (persistent flags)
+var (
+	connectRetries      int
+	connectRetryBackoff time.Duration
+	discoveryTimeout    time.Duration
+	connectByIdentifier bool
+	useGATTCache        bool
+)
+
+// gattCacheDir returns the GATT cache directory when --gatt-cache is set, "" otherwise
+func gattCacheDir() string {
+	if !useGATTCache {
+		return ""
+	}
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(dir, "blim", "gatt")
+}
+
 var (
 	version = "dev"
 	commit  = "none"
@@ -39,10 +65,27 @@ var rootCmd = &cobra.Command{
 
 Ideal for firmware development, automated testing, and BLE protocols exploration.`,
 	Version: formatVersion(version),
+	PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+		adapter, _ := cmd.Flags().GetString("adapter")
+		if err := devicefactory.SetAdapter(adapter); err != nil {
+			return err
+		}
+		if simulate, _ := cmd.Flags().GetString("simulate"); simulate != "" {
+			return devicefactory.Simulate(simulate)
+		}
+		return nil
+	},
 }
 
 func main() {
-	if err := rootCmd.Execute(); err != nil {
+	// SIGINT/SIGTERM cancel the command context; commands clean up and return, see executeWithShutdown
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	err := executeWithShutdown(ctx, stop, rootCmd, shutdownGracePeriod, func(err error) {
+		fmt.Fprintf(os.Stderr, "WARNING: cleanup on exit failed: %s\n", err)
+	})
+	if err != nil {
 		// Ctrl+C is a normal exit, not an error - exit silently
 		if errors.Is(err, context.Canceled) {
 			return
@@ -64,9 +107,20 @@ func init() {
 	rootCmd.AddCommand(readCmd)
 	rootCmd.AddCommand(writeCmd)
 	rootCmd.AddCommand(subscribeCmd)
+	rootCmd.AddCommand(snapshotCmd)
+	rootCmd.AddCommand(replayCmd)
+	rootCmd.AddCommand(dbCmd)
 
 	// Global flags
 	rootCmd.PersistentFlags().String("log-level", "", "Log level (debug, info, warn, error)")
+	rootCmd.PersistentFlags().BoolVarP(&quietOutput, "quiet", "q", false, "Only print command data: logs are limited to errors; progress, hints and banners are suppressed")
+	rootCmd.PersistentFlags().String("adapter", "", "Bluetooth adapter to use; macOS exposes a single adapter, so only \"default\" is accepted")
+	rootCmd.PersistentFlags().String("simulate", "", "Run against a simulated device described by a JSON file instead of real hardware")
+	rootCmd.PersistentFlags().IntVar(&connectRetries, "connect-retries", 0, "Retry a failed initial connection this many times")
+	rootCmd.PersistentFlags().DurationVar(&discoveryTimeout, "discovery-timeout", 60*time.Second, "Maximum time for service discovery after connecting (0 = no limit)")
+	rootCmd.PersistentFlags().DurationVar(&connectRetryBackoff, "connect-retry-backoff", time.Second, "Delay before the first connection retry, doubled after each retry")
+	rootCmd.PersistentFlags().BoolVar(&useGATTCache, "gatt-cache", false, "Cache discovered GATT databases on disk and skip discovery on later connections")
+	rootCmd.PersistentFlags().BoolVar(&connectByIdentifier, "identifier", false, "Connect directly to the system-cached peripheral without scanning (falls back to a scan if it is not known)")
 
 	// Add -v as a short flag for --version
 	rootCmd.Flags().BoolP("version", "v", false, "Show version information")
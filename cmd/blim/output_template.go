@@ -0,0 +1,90 @@
+package main
+
+import (
+	"bytes"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/srg/blim/internal/device"
+)
+
+// templateValue is the data an --output-template is executed with, once per read value or notification
+type templateValue struct {
+	UUID      string      // Characteristic or descriptor UUID (shortened)
+	Hex       string      // Value as lowercase hex
+	Ascii     string      // Value with non-printable bytes replaced by '.'
+	Raw       []byte      // Value bytes
+	Decoded   interface{} // Parsed value in its JSON form (maps keyed by JSON field names); nil without a parser
+	Timestamp time.Time   // Time the value was read or notified
+}
+
+// parseOutputTemplate parses a Go text/template for --output-template. A trailing newline is added
+// when the template has none, so each value renders on its own line.
+func parseOutputTemplate(text string) (*template.Template, error) {
+	if !strings.HasSuffix(text, "\n") {
+		text += "\n"
+	}
+	tmpl, err := template.New("output").Parse(text)
+	if err != nil {
+		return nil, fmt.Errorf("invalid --output-template: %w", err)
+	}
+	return tmpl, nil
+}
+
+// newTemplateValue builds the template data for one value, decoding it when a parser is registered
+func newTemplateValue(ts time.Time, uuid string, data []byte) *templateValue {
+	value := &templateValue{
+		UUID:      device.ShortenUUID(uuid),
+		Hex:       hex.EncodeToString(data),
+		Ascii:     printableASCII(data),
+		Raw:       data,
+		Timestamp: ts,
+	}
+	if device.IsParsableCharacteristic(uuid) {
+		if parsed, err := device.ParseCharacteristicValue(uuid, data); err == nil && parsed != nil {
+			value.Decoded = jsonValue(parsed)
+		}
+	}
+	return value
+}
+
+// jsonValue converts a parsed value to its generic JSON form so templates can address fields by JSON name
+func jsonValue(v interface{}) interface{} {
+	encoded, err := json.Marshal(v)
+	if err != nil {
+		return v
+	}
+	var generic interface{}
+	if err := json.Unmarshal(encoded, &generic); err != nil {
+		return v
+	}
+	return generic
+}
+
+// printableASCII renders data as ASCII, replacing bytes outside the printable range with '.'
+func printableASCII(data []byte) string {
+	b := make([]byte, len(data))
+	for i, c := range data {
+		if c >= 0x20 && c < 0x7F {
+			b[i] = c
+		} else {
+			b[i] = '.'
+		}
+	}
+	return string(b)
+}
+
+// renderOutputTemplate executes tmpl for one value; nothing is written if execution fails
+func renderOutputTemplate(w io.Writer, tmpl *template.Template, value *templateValue) error {
+	var b bytes.Buffer
+	if err := tmpl.Execute(&b, value); err != nil {
+		return fmt.Errorf("failed to render --output-template for %s: %w", value.UUID, err)
+	}
+	_, err := w.Write(b.Bytes())
+	return err
+}
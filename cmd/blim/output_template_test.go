@@ -0,0 +1,62 @@
+//go:build test
+
+package main
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseOutputTemplate(t *testing.T) {
+	// GOAL: Verify --output-template is validated up front and renders one line per value
+	//
+	// TEST SCENARIO: Malformed template → error naming the flag; valid template without newline → newline appended
+
+	_, err := parseOutputTemplate("{{.UUID")
+	assert.ErrorContains(t, err, "invalid --output-template")
+
+	tmpl, err := parseOutputTemplate("{{.UUID}} {{.Hex}}")
+	require.NoError(t, err)
+
+	var out bytes.Buffer
+	require.NoError(t, renderOutputTemplate(&out, tmpl, newTemplateValue(time.Now(), "2a19", []byte{0x4B})))
+	assert.Equal(t, "2a19 4b\n", out.String())
+}
+
+func TestRenderOutputTemplate_Fields(t *testing.T) {
+	// GOAL: Verify templates can address the hex, ASCII, decoded and timestamp fields of a value
+	//
+	// TEST SCENARIO: Temperature value → Decoded is the parsed number → Ascii masks non-printable bytes → Timestamp formats
+
+	ts := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	tmpl, err := parseOutputTemplate(`{{.Timestamp.Format "15:04:05"}} {{.UUID}} {{.Decoded}} {{.Ascii}} {{len .Raw}}`)
+	require.NoError(t, err)
+
+	var out bytes.Buffer
+	require.NoError(t, renderOutputTemplate(&out, tmpl, newTemplateValue(ts, "00002a6e-0000-1000-8000-00805f9b34fb", []byte{0x29, 0x09})))
+	assert.Equal(t, "03:04:05 2a6e 23.45 ). 2\n", out.String())
+
+	var generic bytes.Buffer
+	value := newTemplateValue(ts, "ffe1", []byte("ok"))
+	assert.Nil(t, value.Decoded, "value without parser MUST have no decoded form")
+	require.NoError(t, renderOutputTemplate(&generic, tmpl, value))
+	assert.Contains(t, generic.String(), " ok 2\n")
+}
+
+func TestRenderOutputTemplate_ExecutionError(t *testing.T) {
+	// GOAL: Verify a template that fails for a value reports an error without partial output
+	//
+	// TEST SCENARIO: Template indexes a field of a missing decoded value → error → nothing written
+
+	tmpl, err := parseOutputTemplate("{{.UUID}} {{.Decoded.bpm.x}}")
+	require.NoError(t, err)
+
+	var out bytes.Buffer
+	err = renderOutputTemplate(&out, tmpl, newTemplateValue(time.Now(), "ffe1", []byte{0x01}))
+	assert.ErrorContains(t, err, "ffe1")
+	assert.Empty(t, out.String(), "failed render MUST NOT write partial output")
+}
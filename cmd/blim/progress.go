@@ -71,11 +71,15 @@ func NewCountdownProgressPrinter(prefix string, phase string, duration time.Dura
 }
 
 // Start begins displaying progress updates in a background goroutine.
+// With --quiet nothing is displayed and Stop is a no-op.
 // Panics if called more than once on the same ProgressPrinter instance.
 func (p *ProgressPrinter) Start() {
 	if !p.started.CompareAndSwap(false, true) {
 		panic("ProgressPrinter.Start called more than once")
 	}
+	if quietOutput {
+		return
+	}
 
 	if p.stopChan != nil {
 		panic("ProgressPrinter cannot be reused after Stop")
@@ -1,12 +1,16 @@
 package main
 
 import (
+	"bytes"
 	"context"
 	"encoding/hex"
 	"errors"
 	"fmt"
 	"os"
 	"sort"
+	"strconv"
+	"strings"
+	"text/template"
 	"time"
 
 	"github.com/sirupsen/logrus"
@@ -17,7 +21,7 @@ import (
 
 // readCmd represents the read command
 var readCmd = &cobra.Command{
-	Use:   "read <device-address> [uuid]",
+	Use:   "read <device-address> [[service/]uuid]",
 	Short: "Read a characteristic or descriptor value",
 	Long: fmt.Sprintf(`Reads data from BLE characteristic(s) or a descriptor.
 
@@ -30,6 +34,10 @@ Examples:
 
   # Read with service disambiguation
   blim read %s --service 180f --char 2a19
+  blim read %s 180f/2a19
+
+  # Read by characteristic name
+  blim read %s "Battery Level"
 
   # Read multiple characteristics from a specific service
   blim read %s --service 180d --char 2a37,2a38
@@ -46,20 +54,46 @@ Examples:
   # Watch with custom interval
   blim read %s 2a37 --watch 500ms
 
-%s`, exampleDeviceAddress, exampleDeviceAddress, exampleDeviceAddress, exampleDeviceAddress, exampleDeviceAddress, exampleDeviceAddress, exampleDeviceAddress, exampleDeviceAddress, deviceAddressNote),
-	Args: cobra.RangeArgs(1, 2),
-	RunE: runRead,
+  # Poll every 500ms over a single connection, printing timestamped samples
+  blim read %s 2a19 --repeat 500ms --hex
+
+  # Take 10 samples, one per second
+  blim read %s 2a19 --repeat 1s --count 10 --hex
+
+  # Print a sample only when its value changes, showing changed byte indices
+  blim read %s ff01 --repeat 200ms --on-change --hex
+
+  # Pair automatically if the characteristic requires authentication
+  blim read %s ff02 --auto-pair --hex
+
+UUIDs may be 16-bit (2a19), 32-bit or 128-bit, or known names; malformed UUIDs are rejected before connecting.
+
+%s`, exampleDeviceAddress, exampleDeviceAddress, exampleDeviceAddress, exampleDeviceAddress, exampleDeviceAddress, exampleDeviceAddress, exampleDeviceAddress, exampleDeviceAddress, exampleDeviceAddress, exampleDeviceAddress, exampleDeviceAddress, exampleDeviceAddress, exampleDeviceAddress, exampleDeviceAddress, deviceAddressNote),
+	Args:              targetUUIDArgs(1, cobra.RangeArgs(1, 2)),
+	ValidArgsFunction: completeTargetUUID(1),
+	RunE:              runRead,
 }
 
 var (
-	readServiceUUID string
-	readCharUUIDs   string // supports comma-separated UUIDs
-	readDescUUID    string
-	readHex         bool
-	readTimeout     time.Duration
-	readWatch       string
+	readServiceUUID  string
+	readCharUUIDs    string // supports comma-separated UUIDs
+	readDescUUID     string
+	readHex          bool
+	readTimeout      time.Duration
+	readWatch        string
+	readRepeat       time.Duration
+	readCount        int
+	readOnChange     bool
+	readAutoPair     bool
+	readNoDesc       bool
+	readOnlyServices []string
+	readTemplateText string
+	readTemplate     *template.Template // Parsed --output-template, nil when not set
 )
 
+// repeatTimestampFormat is the timestamp layout used for --repeat samples
+const repeatTimestampFormat = "2006-01-02T15:04:05.000Z07:00"
+
 func init() {
 	readCmd.Flags().StringVar(&readServiceUUID, "service", "", "Service UUID (required if characteristic UUID is ambiguous)")
 	readCmd.Flags().StringVar(&readCharUUIDs, "char", "", "Characteristic UUID(s), comma-separated for multiple")
@@ -68,15 +102,33 @@ func init() {
 	readCmd.Flags().DurationVar(&readTimeout, "timeout", 5*time.Second, "Read timeout")
 	readCmd.Flags().StringVar(&readWatch, "watch", "", "Continuously read at interval (e.g., 1s, 500ms); default 1s if no value given")
 	readCmd.Flags().Lookup("watch").NoOptDefVal = "1s"
+	readCmd.Flags().DurationVar(&readRepeat, "repeat", 0, "Re-read over a single connection at interval (e.g., 500ms), printing timestamped samples until Ctrl+C")
+	readCmd.Flags().IntVar(&readCount, "count", 0, "Number of samples to take with --repeat (0 = unlimited)")
+	readCmd.Flags().BoolVar(&readOnChange, "on-change", false, "With --repeat, print a sample only when its value differs from the previous one")
+	readCmd.Flags().BoolVar(&readNoDesc, "no-descriptors", false, "Skip descriptor discovery for a faster connect (descriptors are unavailable)")
+	readCmd.Flags().StringArrayVar(&readOnlyServices, "only-service", nil, "Discover only this service (repeatable); faster connect on devices with many services")
+	readCmd.Flags().StringVar(&readTemplateText, "output-template", "", "Render each value with a Go template, e.g. '{{.UUID}} {{.Hex}} {{.Decoded}}' (fields: UUID, Hex, Ascii, Raw, Decoded, Timestamp)")
+	readCmd.Flags().BoolVar(&readAutoPair, "auto-pair", false, "Pair with the device and retry once when a read fails with an authentication error")
 }
 
 func runRead(cmd *cobra.Command, args []string) error {
 	address := args[0]
 
-	// Determine UUID source (raw CSV string for later parsing)
+	// Determine UUID source (raw CSV string for later parsing); the positional argument may carry the service
 	var uuidInput string
+	serviceUUID := readServiceUUID
 	if len(args) == 2 {
-		uuidInput = args[1]
+		target, err := parseTargetArg(args[1])
+		if err != nil {
+			return err
+		}
+		if target.Service != "" {
+			if serviceUUID != "" && device.NormalizeUUID(serviceUUID) != target.Service {
+				return fmt.Errorf("service %s in argument conflicts with --service %s", target.Service, serviceUUID)
+			}
+			serviceUUID = target.Service
+		}
+		uuidInput = strings.Join(target.UUIDs, ",")
 	} else if readCharUUIDs != "" {
 		uuidInput = readCharUUIDs
 	} else if readDescUUID != "" {
@@ -84,6 +136,18 @@ func runRead(cmd *cobra.Command, args []string) error {
 	} else {
 		return fmt.Errorf("UUID required: provide as second argument or via --char/--desc flag")
 	}
+	if readNoDesc && readDescUUID != "" {
+		return fmt.Errorf("--desc cannot be used with --no-descriptors")
+	}
+
+	readTemplate = nil
+	if readTemplateText != "" {
+		tmpl, err := parseOutputTemplate(readTemplateText)
+		if err != nil {
+			return err
+		}
+		readTemplate = tmpl
+	}
 
 	// Parse for validation and routing
 	charUUIDs := parseCSVUUIDs(uuidInput)
@@ -105,6 +169,28 @@ func runRead(cmd *cobra.Command, args []string) error {
 		}
 	}
 
+	// Validate repeat options
+	if readRepeat < 0 {
+		return fmt.Errorf("invalid repeat interval: %v", readRepeat)
+	}
+	if readCount < 0 {
+		return fmt.Errorf("invalid count: %d", readCount)
+	}
+	if readCount > 0 && readRepeat == 0 {
+		return fmt.Errorf("--count requires --repeat")
+	}
+	if readOnChange && readRepeat == 0 {
+		return fmt.Errorf("--on-change requires --repeat")
+	}
+	if readRepeat > 0 {
+		if readWatch != "" {
+			return fmt.Errorf("--repeat and --watch are mutually exclusive")
+		}
+		if len(charUUIDs) > 1 {
+			return fmt.Errorf("repeat mode requires a single characteristic, got %d", len(charUUIDs))
+		}
+	}
+
 	// Configure logger
 	logger, err := configureLogger(cmd, "verbose")
 	if err != nil {
@@ -117,7 +203,7 @@ func runRead(cmd *cobra.Command, args []string) error {
 	// Setup progress description
 	var progressDesc string
 	operation := "Reading"
-	if readWatch != "" {
+	if readWatch != "" || readRepeat > 0 {
 		operation = "Watching"
 	}
 	if len(charUUIDs) == 1 {
@@ -134,13 +220,24 @@ func runRead(cmd *cobra.Command, args []string) error {
 	opts := &inspector.InspectOptions{
 		ConnectTimeout:        30 * time.Second,
 		DescriptorReadTimeout: readTimeout,
+		SkipDescriptors:       readNoDesc,
+		OnlyServices:          readOnlyServices,
+		AutoPair:              readAutoPair,
+		ConnectRetries:        connectRetries,
+		ConnectRetryBackoff:   connectRetryBackoff,
+		ByIdentifier:          connectByIdentifier,
+		GATTCacheDir:          gattCacheDir(),
+		DiscoveryTimeout:      discoveryTimeout,
 	}
 
-	// Use background context
-	ctx := context.Background()
+	// Command context is canceled on SIGINT/SIGTERM; repeat mode keeps the connection open until then
+	ctx := commandContext(cmd)
 
 	// Define the read operation
 	readOperation := func(dev device.Device) (any, error) {
+		// Disconnect on exit even if the operation does not return after an interrupt
+		defer registerTeardown("disconnect "+address, disconnectTeardown(dev))()
+
 		// Stop progress indicator before printing output
 		progress.Stop()
 
@@ -152,15 +249,18 @@ func runRead(cmd *cobra.Command, args []string) error {
 
 		// Descriptor path
 		if readDescUUID != "" {
-			char, desc, _, err := resolveDescriptor(conn, readDescUUID, readServiceUUID, readCharUUIDs)
+			char, desc, _, err := resolveDescriptor(conn, readDescUUID, serviceUUID, readCharUUIDs)
 			if err != nil {
 				return nil, err
 			}
+			if readRepeat > 0 {
+				return nil, repeatRead(ctx, char, desc, readRepeat, readCount, logger)
+			}
 			return nil, performReadWithPrefix(char, desc, false)
 		}
 
 		// Characteristic path
-		_, _, chars, err := resolveCharacteristics(conn, uuidInput, readServiceUUID)
+		_, _, chars, err := resolveCharacteristics(conn, uuidInput, serviceUUID)
 		if err != nil {
 			return nil, err
 		}
@@ -171,6 +271,9 @@ func runRead(cmd *cobra.Command, args []string) error {
 				if readWatch != "" {
 					return nil, watchChar(ctx, dev, char, nil, watchInterval, logger)
 				}
+				if readRepeat > 0 {
+					return nil, repeatRead(ctx, char, nil, readRepeat, readCount, logger)
+				}
 				return nil, performReadWithPrefix(char, nil, false)
 			}
 		}
@@ -202,6 +305,12 @@ func performMultiRead(chars map[string]device.Characteristic) error {
 			continue
 		}
 
+		if readTemplate != nil {
+			if err := renderOutputTemplate(os.Stdout, readTemplate, newTemplateValue(time.Now(), uuid, data)); err != nil {
+				fmt.Fprintf(os.Stderr, "%s: error: %v\n", device.ShortenUUID(uuid), err)
+			}
+			continue
+		}
 		outputDataWithPrefix(uuid, data, true)
 	}
 
@@ -228,12 +337,23 @@ func performReadWithPrefix(char device.Characteristic, desc device.Descriptor, m
 	}
 
 	// Format and output data
+	if readTemplate != nil {
+		return renderOutputTemplate(os.Stdout, readTemplate, newTemplateValue(time.Now(), readValueUUID(char, desc), data))
+	}
 	return outputData(data)
 }
 
+// readValueUUID returns the UUID of the value being read: the descriptor if set, otherwise the characteristic
+func readValueUUID(char device.Characteristic, desc device.Descriptor) string {
+	if desc != nil {
+		return desc.UUID()
+	}
+	return char.UUID()
+}
+
 // watchChar continuously reads a characteristic or descriptor at the specified interval
 func watchChar(ctx context.Context, dev device.Device, char device.Characteristic, desc device.Descriptor, interval time.Duration, logger *logrus.Logger) error {
-	fmt.Fprintf(os.Stderr, "Watching (reading every %v). Press Ctrl+C to stop...\n", interval)
+	printInfof(os.Stderr, "Watching (reading every %v). Press Ctrl+C to stop...\n", interval)
 
 	// Perform immediate first read
 	if err := performSingleRead(char, desc, logger); err != nil {
@@ -263,6 +383,114 @@ func watchChar(ctx context.Context, dev device.Device, char device.Characteristi
 	}
 }
 
+// repeatRead re-reads a characteristic or descriptor over the already open connection
+// at the specified interval, printing each sample with a timestamp.
+// Stops after count samples (0 = unlimited) or when ctx is cancelled (Ctrl+C).
+// With --on-change, a sample is printed only if it differs from the previous one,
+// annotated with the indices of the changed bytes.
+func repeatRead(ctx context.Context, char device.Characteristic, desc device.Descriptor, interval time.Duration, count int, logger *logrus.Logger) error {
+	if count > 0 {
+		printInfof(os.Stderr, "Reading %d samples every %v. Press Ctrl+C to stop...\n", count, interval)
+	} else {
+		printInfof(os.Stderr, "Reading every %v. Press Ctrl+C to stop...\n", interval)
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	uuid := readValueUUID(char, desc)
+
+	// Last successfully read value, retained for --on-change comparison
+	var last []byte
+	hasLast := false
+
+	for samples := 1; ; samples++ {
+		var data []byte
+		var err error
+		if desc != nil {
+			data, err = desc.Read(readTimeout)
+		} else {
+			data, err = char.Read(readTimeout)
+		}
+
+		if err != nil {
+			// Connection loss is fatal; other errors are transient
+			if errors.Is(err, device.ErrNotConnected) {
+				return ErrConnectionLost
+			}
+			logger.WithError(err).Warn("Failed to read sample, continuing...")
+		} else if !readOnChange {
+			outputSample(time.Now(), uuid, data, "")
+		} else if !hasLast {
+			// First sample is always printed as the baseline
+			outputSample(time.Now(), uuid, data, "")
+			last, hasLast = data, true
+		} else if !bytes.Equal(last, data) {
+			outputSample(time.Now(), uuid, data, formatByteDiff(diffBytes(last, data)))
+			last = data
+		}
+
+		if count > 0 && samples >= count {
+			return nil
+		}
+
+		select {
+		case <-ticker.C:
+		case <-ctx.Done():
+			return nil
+		}
+	}
+}
+
+// outputSample outputs a single timestamped sample according to flags, with an optional annotation
+func outputSample(ts time.Time, uuid string, data []byte, annotation string) {
+	if readTemplate != nil {
+		if err := renderOutputTemplate(os.Stdout, readTemplate, newTemplateValue(ts, uuid, data)); err != nil {
+			fmt.Fprintf(os.Stderr, "error: %v\n", err)
+		}
+		return
+	}
+
+	prefix := ts.Format(repeatTimestampFormat) + " "
+	var suffix string
+	if annotation != "" {
+		suffix = " " + annotation
+	}
+
+	if readHex {
+		fmt.Printf("%s%s%s\n", prefix, hex.EncodeToString(data), suffix)
+		return
+	}
+
+	// Raw binary output
+	fmt.Print(prefix)
+	_, _ = os.Stdout.Write(data)
+	fmt.Println(suffix)
+}
+
+// diffBytes returns the indices of bytes that differ between prev and cur.
+// Indices present in only one of the values (length change) are reported as changed.
+func diffBytes(prev, cur []byte) []int {
+	n := max(len(prev), len(cur))
+
+	var changed []int
+	for i := 0; i < n; i++ {
+		if i >= len(prev) || i >= len(cur) || prev[i] != cur[i] {
+			changed = append(changed, i)
+		}
+	}
+	return changed
+}
+
+// formatByteDiff renders changed byte indices, e.g. "[changed: 0,3]"
+func formatByteDiff(indices []int) string {
+	parts := make([]string, len(indices))
+	for i, idx := range indices {
+		parts[i] = strconv.Itoa(idx)
+	}
+	return "[changed: " + strings.Join(parts, ",") + "]"
+}
+
 // performSingleRead executes a single read operation and outputs the data
 func performSingleRead(char device.Characteristic, desc device.Descriptor, logger *logrus.Logger) error {
 	var data []byte
@@ -284,7 +512,12 @@ func performSingleRead(char device.Characteristic, desc device.Descriptor, logge
 	}
 
 	// Output data
-	if err := outputData(data); err != nil {
+	if readTemplate != nil {
+		err = renderOutputTemplate(os.Stdout, readTemplate, newTemplateValue(time.Now(), readValueUUID(char, desc), data))
+	} else {
+		err = outputData(data)
+	}
+	if err != nil {
 		logger.WithError(err).Error("failed to output data")
 		return err
 	}
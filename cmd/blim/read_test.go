@@ -3,7 +3,9 @@
 package main
 
 import (
+	"context"
 	"encoding/hex"
+	"strings"
 	"testing"
 	"time"
 
@@ -21,6 +23,9 @@ type ReadTestSuite struct {
 		readHex         bool
 		readWatch       string
 		readTimeout     time.Duration
+		readRepeat      time.Duration
+		readCount       int
+		readOnChange    bool
 	}
 }
 
@@ -35,6 +40,9 @@ func (suite *ReadTestSuite) SetupSuite() {
 	suite.originalFlags.readHex = readHex
 	suite.originalFlags.readWatch = readWatch
 	suite.originalFlags.readTimeout = readTimeout
+	suite.originalFlags.readRepeat = readRepeat
+	suite.originalFlags.readCount = readCount
+	suite.originalFlags.readOnChange = readOnChange
 }
 
 // TearDownSuite runs once after all tests in the suite
@@ -46,6 +54,9 @@ func (suite *ReadTestSuite) TearDownSuite() {
 	readHex = suite.originalFlags.readHex
 	readWatch = suite.originalFlags.readWatch
 	readTimeout = suite.originalFlags.readTimeout
+	readRepeat = suite.originalFlags.readRepeat
+	readCount = suite.originalFlags.readCount
+	readOnChange = suite.originalFlags.readOnChange
 
 	suite.CommandTestSuite.TearDownSuite()
 }
@@ -105,6 +116,9 @@ func (suite *ReadTestSuite) SetupTest() {
 	readHex = false
 	readWatch = ""
 	readTimeout = 5 * time.Second
+	readRepeat = 0
+	readCount = 0
+	readOnChange = false
 }
 
 // =============================================================================
@@ -206,6 +220,170 @@ func (suite *ReadTestSuite) TestWatchMode_RejectMultiChar() {
 		"watch mode with multiple chars MUST trigger validation error in runRead")
 }
 
+// =============================================================================
+// Repeat Mode Tests
+// =============================================================================
+
+func (suite *ReadTestSuite) TestRepeatRead_Count() {
+	// GOAL: Verify repeat mode takes exactly --count timestamped samples over one connection
+	//
+	// TEST SCENARIO: Repeat read 2a19 with count=3 → three timestamped hex lines → returns without cancellation
+
+	dev, cleanup := suite.ConnectDevice("")
+	defer cleanup()
+	conn := dev.GetConnection()
+
+	readHex = true
+
+	char, err := conn.GetCharacteristic("180f", "2a19")
+	suite.Require().NoError(err, "characteristic lookup MUST succeed")
+
+	output := suite.CaptureStdout(func() {
+		err = repeatRead(context.Background(), char, nil, 10*time.Millisecond, 3, suite.Logger)
+		suite.Require().NoError(err, "repeat read MUST succeed")
+	})
+
+	lines := strings.Split(strings.TrimSpace(output), "\n")
+	suite.Require().Len(lines, 3, "MUST output exactly 3 samples")
+	for _, line := range lines {
+		fields := strings.Fields(line)
+		suite.Require().Len(fields, 2, "sample MUST be '<timestamp> <value>'")
+		_, err := time.Parse(repeatTimestampFormat, fields[0])
+		suite.Assert().NoError(err, "sample MUST start with a timestamp")
+		suite.Assert().Equal("4b", fields[1], "sample MUST contain hex value (75 = 0x4b)")
+	}
+}
+
+func (suite *ReadTestSuite) TestRepeatRead_StopsOnCancel() {
+	// GOAL: Verify unbounded repeat mode stops cleanly when the context is cancelled (Ctrl+C)
+	//
+	// TEST SCENARIO: Repeat read with count=0 → cancel after a few intervals → returns nil with samples printed
+
+	dev, cleanup := suite.ConnectDevice("")
+	defer cleanup()
+	conn := dev.GetConnection()
+
+	readHex = true
+
+	char, err := conn.GetCharacteristic("180f", "2a19")
+	suite.Require().NoError(err, "characteristic lookup MUST succeed")
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+
+	output := suite.CaptureStdout(func() {
+		err = repeatRead(ctx, char, nil, 10*time.Millisecond, 0, suite.Logger)
+		suite.Require().NoError(err, "repeat read MUST return nil on cancellation")
+	})
+
+	suite.Assert().GreaterOrEqual(strings.Count(output, "4b"), 1, "MUST output at least one sample before cancellation")
+}
+
+func (suite *ReadTestSuite) TestRepeatRead_OnChangeSuppressesDuplicates() {
+	// GOAL: Verify --on-change prints only the baseline sample when the value never changes
+	//
+	// TEST SCENARIO: Repeat read constant 2a19 with on-change and count=3 → one baseline line printed → duplicates suppressed
+
+	dev, cleanup := suite.ConnectDevice("")
+	defer cleanup()
+	conn := dev.GetConnection()
+
+	readHex = true
+	readOnChange = true
+
+	char, err := conn.GetCharacteristic("180f", "2a19")
+	suite.Require().NoError(err, "characteristic lookup MUST succeed")
+
+	output := suite.CaptureStdout(func() {
+		err = repeatRead(context.Background(), char, nil, 10*time.Millisecond, 3, suite.Logger)
+		suite.Require().NoError(err, "repeat read MUST succeed")
+	})
+
+	lines := strings.Split(strings.TrimSpace(output), "\n")
+	suite.Assert().Len(lines, 1, "MUST output only the baseline sample for unchanged values")
+	suite.Assert().NotContains(output, "changed", "baseline sample MUST NOT carry a diff annotation")
+}
+
+func (suite *ReadTestSuite) TestDiffBytes() {
+	// GOAL: Verify byte-level diff reports changed indices, including length changes
+	//
+	// TEST SCENARIO: Compare byte slices → changed indices computed → rendered annotation matches
+
+	tests := []struct {
+		name     string
+		prev     []byte
+		cur      []byte
+		expected []int
+		rendered string
+	}{
+		{name: "single byte changed", prev: []byte{1, 2, 3}, cur: []byte{1, 9, 3}, expected: []int{1}, rendered: "[changed: 1]"},
+		{name: "multiple bytes changed", prev: []byte{1, 2, 3, 4}, cur: []byte{0, 2, 3, 5}, expected: []int{0, 3}, rendered: "[changed: 0,3]"},
+		{name: "value grew", prev: []byte{1}, cur: []byte{1, 2}, expected: []int{1}, rendered: "[changed: 1]"},
+		{name: "value shrank", prev: []byte{1, 2, 3}, cur: []byte{1}, expected: []int{1, 2}, rendered: "[changed: 1,2]"},
+		{name: "identical", prev: []byte{1, 2}, cur: []byte{1, 2}, expected: nil, rendered: "[changed: ]"},
+	}
+
+	for _, tt := range tests {
+		suite.Run(tt.name, func() {
+			changed := diffBytes(tt.prev, tt.cur)
+			suite.Assert().Equal(tt.expected, changed, "changed indices MUST match")
+			suite.Assert().Equal(tt.rendered, formatByteDiff(changed), "rendered diff MUST match")
+		})
+	}
+}
+
+func (suite *ReadTestSuite) TestRepeatMode_Validation() {
+	// GOAL: Verify invalid --repeat/--count combinations are rejected before connecting
+	//
+	// TEST SCENARIO: Run read with conflicting flags → validation error returned → no connection attempted
+
+	tests := []struct {
+		name        string
+		setup       func()
+		uuids       string
+		expectedErr string
+	}{
+		{
+			name:        "count without repeat",
+			setup:       func() { readCount = 5 },
+			uuids:       "2a19",
+			expectedErr: "--count requires --repeat",
+		},
+		{
+			name:        "repeat with watch",
+			setup:       func() { readRepeat = time.Second; readWatch = "1s" },
+			uuids:       "2a19",
+			expectedErr: "mutually exclusive",
+		},
+		{
+			name:        "repeat with multiple characteristics",
+			setup:       func() { readRepeat = time.Second },
+			uuids:       "2a37,2a38",
+			expectedErr: "repeat mode requires a single characteristic",
+		},
+		{
+			name:        "on-change without repeat",
+			setup:       func() { readOnChange = true },
+			uuids:       "2a19",
+			expectedErr: "--on-change requires --repeat",
+		},
+	}
+
+	for _, tt := range tests {
+		suite.Run(tt.name, func() {
+			readRepeat = 0
+			readCount = 0
+			readOnChange = false
+			readWatch = ""
+			tt.setup()
+
+			err := runRead(readCmd, []string{TestDeviceAddress1, tt.uuids})
+			suite.Require().Error(err, "MUST reject invalid flag combination")
+			suite.Assert().Contains(err.Error(), tt.expectedErr, "error MUST describe the conflict")
+		})
+	}
+}
+
 // =============================================================================
 // Descriptor Read Tests
 // =============================================================================
@@ -309,6 +487,8 @@ func (suite *ReadTestSuite) TestReadCmd_Flags() {
 		{name: "char", defaultValue: ""},
 		{name: "desc", defaultValue: ""},
 		{name: "timeout", defaultValue: "5s"},
+		{name: "repeat", defaultValue: "0s"},
+		{name: "count", defaultValue: "0"},
 	}
 
 	for _, f := range flags {
@@ -322,7 +502,7 @@ func (suite *ReadTestSuite) TestReadCmd_Flags() {
 	}
 
 	// Boolean flags
-	boolFlags := []string{"hex"}
+	boolFlags := []string{"hex", "auto-pair"}
 	for _, name := range boolFlags {
 		suite.Run(name, func() {
 			flag := readCmd.Flags().Lookup(name)
@@ -0,0 +1,241 @@
+package main
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+	"github.com/srg/blim/internal/device"
+)
+
+// replayCmd represents the replay command
+var replayCmd = &cobra.Command{
+	Use:   "replay <logfile>",
+	Short: "Replay a recorded notification log to an output",
+	Long: `Reads a notification log recorded with 'subscribe --record' and re-emits every notification
+to the selected output, keeping the recorded timing. No device connection is involved.
+
+Outputs:
+  text   - "<uuid>: <value>" lines, decoded when a parser is registered (default)
+  jsonl  - One JSON object per notification (timestamp, uuid, hex, decoded)
+  csv    - timestamp,uuid,hex,decoded rows with a header line
+  ws     - Broadcast to WebSocket clients at /ws on --ws-addr (same messages as subscribe --ws-addr)
+  mqtt   - Publish to <prefix>/<uuid> on --mqtt-broker (same messages as subscribe --mqtt-broker)
+
+Examples:
+  # Print a recording in real time
+  blim replay hr.blimrec
+
+  # Convert a recording to CSV as fast as possible
+  blim replay hr.blimrec --output csv --speed max > hr.csv
+
+  # Feed a dashboard at double speed, looping until Ctrl+C
+  blim replay hr.blimrec --output ws --ws-addr :8080 --speed 2x --loop`,
+	Args: cobra.ExactArgs(1),
+	RunE: runReplay,
+}
+
+var (
+	replayOutput string
+	replaySpeed  string
+	replayLoop   bool
+	replayWSAddr string
+	replayMQTT   mqttOptions
+)
+
+func init() {
+	replayCmd.Flags().StringVar(&replayOutput, "output", "text", "Output: text, jsonl, csv, ws, or mqtt")
+	replayCmd.Flags().StringVar(&replaySpeed, "speed", "1x", "Playback speed factor (e.g., 2x, 0.5x); 'max' replays without delays")
+	replayCmd.Flags().BoolVar(&replayLoop, "loop", false, "Restart the log when it ends, until interrupted")
+	replayCmd.Flags().StringVar(&replayWSAddr, "ws-addr", ":8080", "WebSocket listen address for --output ws")
+	replayCmd.Flags().StringVar(&replayMQTT.Broker, "mqtt-broker", "", "MQTT broker for --output mqtt (host:port, tcp:// or tls://)")
+	replayCmd.Flags().StringVar(&replayMQTT.TopicPrefix, "mqtt-topic-prefix", "blim", "MQTT topic prefix; messages go to <prefix>/<characteristic-uuid>")
+	replayCmd.Flags().StringVar(&replayMQTT.ClientID, "mqtt-client-id", "", "MQTT client identifier (generated if empty)")
+	replayCmd.Flags().StringVar(&replayMQTT.Username, "mqtt-username", "", "MQTT username")
+	replayCmd.Flags().StringVar(&replayMQTT.Password, "mqtt-password", "", "MQTT password (requires --mqtt-username; visible in the process list, prefer --mqtt-password-file or $"+mqttPasswordEnv+")")
+	replayCmd.Flags().StringVar(&replayMQTT.PasswordFile, "mqtt-password-file", "", "Read the MQTT password from this file (requires --mqtt-username)")
+}
+
+// parseReplaySpeed parses a playback speed such as "2", "2x" or "0.5x". "max" (or 0) disables delays and is returned as 0.
+func parseReplaySpeed(s string) (float64, error) {
+	s = strings.ToLower(strings.TrimSpace(s))
+	if s == "max" {
+		return 0, nil
+	}
+	speed, err := strconv.ParseFloat(strings.TrimSuffix(s, "x"), 64)
+	if err != nil || speed < 0 {
+		return 0, fmt.Errorf("invalid speed %q: use a positive factor like 2x or 0.5x, or max", s)
+	}
+	return speed, nil
+}
+
+// newReplaySink returns the record handler for an output and a function releasing its resources
+func newReplaySink(output string, w io.Writer, logger *logrus.Logger) (func(*device.Record), func(), error) {
+	switch strings.ToLower(output) {
+	case "text":
+		return func(record *device.Record) {
+			forEachRecordValue(record, func(charUUID string, data []byte) {
+				fmt.Fprintf(w, "%s: %s\n", device.ShortenUUID(charUUID), formatDecodedValue(charUUID, data))
+			})
+		}, func() {}, nil
+
+	case "jsonl":
+		encoder := json.NewEncoder(w)
+		return func(record *device.Record) {
+			forEachRecordValue(record, func(charUUID string, data []byte) {
+				if err := encoder.Encode(newNotificationMessage(record.TsUs, charUUID, data)); err != nil {
+					logger.WithError(err).Warn("Failed to write JSON line")
+				}
+			})
+		}, func() {}, nil
+
+	case "csv":
+		cw := csv.NewWriter(w)
+		if err := cw.Write([]string{"timestamp", "uuid", "hex", "decoded"}); err != nil {
+			return nil, nil, fmt.Errorf("failed to write CSV header: %w", err)
+		}
+		return func(record *device.Record) {
+			forEachRecordValue(record, func(charUUID string, data []byte) {
+				msg := newNotificationMessage(record.TsUs, charUUID, data)
+				var decoded string
+				if msg.Decoded != nil {
+					decoded = formatDecodedValue(charUUID, data)
+				}
+				_ = cw.Write([]string{msg.Timestamp, msg.UUID, msg.Hex, decoded})
+			})
+			cw.Flush()
+		}, cw.Flush, nil
+
+	case "ws":
+		broadcaster := newWSBroadcaster(logger)
+		shutdown, err := startWebSocketServer(replayWSAddr, broadcaster, logger)
+		if err != nil {
+			return nil, nil, err
+		}
+		return broadcaster.broadcastRecord, shutdown, nil
+
+	case "mqtt":
+		if replayMQTT.Broker == "" {
+			return nil, nil, fmt.Errorf("--output mqtt requires --mqtt-broker")
+		}
+		if err := resolveMQTTPassword(&replayMQTT); err != nil {
+			return nil, nil, err
+		}
+		pub, shutdown, err := startMQTTPublisher(replayMQTT, logger)
+		if err != nil {
+			return nil, nil, err
+		}
+		return pub.publishRecord, shutdown, nil
+
+	default:
+		return nil, nil, fmt.Errorf("invalid output %q: use text, jsonl, csv, ws, or mqtt", output)
+	}
+}
+
+func runReplay(cmd *cobra.Command, args []string) error {
+	path := args[0]
+
+	speed, err := parseReplaySpeed(replaySpeed)
+	if err != nil {
+		return err
+	}
+
+	logger, err := configureLogger(cmd, "verbose")
+	if err != nil {
+		return err
+	}
+
+	// All arguments validated - don't show usage on runtime errors
+	cmd.SilenceUsage = true
+
+	sink, closeSink, err := newReplaySink(replayOutput, os.Stdout, logger)
+	if err != nil {
+		return err
+	}
+	defer closeSink()
+
+	ctx := commandContext(cmd)
+
+	for {
+		count, err := replayLogFile(ctx, path, speed, sink)
+		if err != nil {
+			if errors.Is(err, context.Canceled) {
+				return nil
+			}
+			return err
+		}
+		logger.WithField("notifications", count).Debug("Replay finished")
+		if !replayLoop || count == 0 {
+			return nil
+		}
+	}
+}
+
+// replayLogFile opens the log at path and replays it; see replayLog
+func replayLogFile(ctx context.Context, path string, speed float64, emit func(*device.Record)) (int, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return 0, fmt.Errorf("failed to open notification log: %w", err)
+	}
+	defer f.Close()
+
+	log, err := device.NewNotificationLogReader(f)
+	if err != nil {
+		return 0, err
+	}
+	return replayLog(ctx, log, speed, emit)
+}
+
+// replayLog emits every logged notification as a single-value record carrying its recorded timestamp.
+// Inter-notification delays are divided by speed; a speed of 0 replays without delays.
+// Blocks until the log is exhausted or ctx is done. Returns the number of replayed notifications.
+func replayLog(ctx context.Context, log *device.NotificationLogReader, speed float64, emit func(*device.Record)) (int, error) {
+	var (
+		count   int
+		firstTs int64
+		start   time.Time
+	)
+
+	for {
+		entry, err := log.Next()
+		if errors.Is(err, io.EOF) {
+			return count, nil
+		}
+		if err != nil {
+			return count, fmt.Errorf("failed to replay notifications: %w", err)
+		}
+
+		if count == 0 {
+			firstTs = entry.TsUs
+			start = time.Now()
+		} else if speed > 0 {
+			offset := time.Duration(float64(entry.TsUs-firstTs)/speed) * time.Microsecond
+			if delay := time.Until(start.Add(offset)); delay > 0 {
+				select {
+				case <-time.After(delay):
+				case <-ctx.Done():
+					return count, context.Cause(ctx)
+				}
+			}
+		}
+		if err := ctx.Err(); err != nil {
+			return count, context.Cause(ctx)
+		}
+
+		emit(&device.Record{
+			TsUs:   entry.TsUs,
+			Seq:    uint64(count),
+			Values: map[string][]byte{entry.Characteristic: entry.Data},
+		})
+		count++
+	}
+}
@@ -0,0 +1,121 @@
+//go:build test
+
+package main
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/srg/blim/internal/device"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newTestNotificationLog records entries into an in-memory notification log reader
+func newTestNotificationLog(t *testing.T, entries ...*device.NotificationLogEntry) *device.NotificationLogReader {
+	var buf bytes.Buffer
+	w, err := device.NewNotificationLogWriter(&buf)
+	require.NoError(t, err)
+	for _, entry := range entries {
+		require.NoError(t, w.Write(entry))
+	}
+	require.NoError(t, w.Flush())
+
+	r, err := device.NewNotificationLogReader(&buf)
+	require.NoError(t, err)
+	return r
+}
+
+func TestParseReplaySpeed(t *testing.T) {
+	// GOAL: Verify playback speed accepts factors with optional "x" suffix and "max"
+	//
+	// TEST SCENARIO: Parse valid and invalid speeds → factors or error
+
+	for input, expected := range map[string]float64{"1x": 1, "2": 2, "0.5x": 0.5, "MAX": 0, "0": 0} {
+		speed, err := parseReplaySpeed(input)
+		require.NoError(t, err, input)
+		assert.Equal(t, expected, speed, input)
+	}
+
+	for _, input := range []string{"fast", "-2x", ""} {
+		_, err := parseReplaySpeed(input)
+		assert.Error(t, err, input)
+	}
+}
+
+func TestReplayLog(t *testing.T) {
+	// GOAL: Verify logged notifications are re-emitted in order with their timestamps and scaled timing
+	//
+	// TEST SCENARIO: Replay 3 entries 100ms apart at 2x → records in order → elapsed ≈ 100ms; cancelled ctx stops replay
+
+	entries := []*device.NotificationLogEntry{
+		{TsUs: 1_000_000, Service: "180d", Characteristic: "2a37", Data: []byte{0x00, 60}},
+		{TsUs: 1_100_000, Service: "180d", Characteristic: "2a37", Data: []byte{0x00, 61}},
+		{TsUs: 1_200_000, Service: "180f", Characteristic: "2a19", Data: []byte{75}},
+	}
+
+	var records []*device.Record
+	start := time.Now()
+	count, err := replayLog(context.Background(), newTestNotificationLog(t, entries...), 2, func(record *device.Record) {
+		records = append(records, record)
+	})
+	elapsed := time.Since(start)
+
+	require.NoError(t, err)
+	assert.Equal(t, 3, count)
+	require.Len(t, records, 3)
+	for i, entry := range entries {
+		assert.Equal(t, entry.TsUs, records[i].TsUs, "records MUST carry the recorded timestamp")
+		assert.Equal(t, map[string][]byte{entry.Characteristic: entry.Data}, records[i].Values)
+	}
+	assert.GreaterOrEqual(t, elapsed, 90*time.Millisecond, "200ms of recording at 2x MUST take about 100ms")
+	assert.Less(t, elapsed, time.Second)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	count, err = replayLog(ctx, newTestNotificationLog(t, entries...), 1, func(*device.Record) {})
+	assert.ErrorIs(t, err, context.Canceled)
+	assert.Equal(t, 0, count, "cancelled replay MUST NOT emit notifications")
+}
+
+func TestReplaySinks(t *testing.T) {
+	// GOAL: Verify file-like outputs format replayed notifications
+	//
+	// TEST SCENARIO: Emit a Date Time record through text, jsonl and csv sinks → expected lines; unknown output rejected
+
+	record := &device.Record{TsUs: 1_700_000_000_000_000, Values: map[string][]byte{
+		"2a08": {0xEA, 0x07, 0x0A, 0x10, 0x0C, 0x1E, 0x2D},
+		"ff01": {0x01},
+	}}
+
+	render := func(output string) string {
+		var buf bytes.Buffer
+		sink, closeSink, err := newReplaySink(output, &buf, logrus.New())
+		require.NoError(t, err, output)
+		sink(record)
+		closeSink()
+		return buf.String()
+	}
+
+	text := render("text")
+	assert.Contains(t, text, "2a08: {")
+	assert.Contains(t, text, "ff01: 01\n", "values without parser MUST fall back to hex")
+
+	lines := strings.Split(strings.TrimSpace(render("jsonl")), "\n")
+	require.Len(t, lines, 2, "jsonl MUST emit one line per value")
+	assert.Contains(t, lines[0], `"uuid":"2a08"`)
+	assert.Contains(t, lines[0], `"decoded":{`)
+	assert.Equal(t, `{"timestamp":"2023-11-14T22:13:20Z","uuid":"ff01","hex":"01"}`, lines[1])
+
+	rows := strings.Split(strings.TrimSpace(render("csv")), "\n")
+	require.Len(t, rows, 3)
+	assert.Equal(t, "timestamp,uuid,hex,decoded", rows[0])
+	assert.Equal(t, "2023-11-14T22:13:20Z,ff01,01,", rows[2])
+
+	_, _, err := newReplaySink("xml", &bytes.Buffer{}, logrus.New())
+	assert.ErrorContains(t, err, "invalid output")
+}
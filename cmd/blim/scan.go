@@ -6,6 +6,7 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"math"
 	"os"
 	"os/signal"
 	"sort"
@@ -16,6 +17,7 @@ import (
 
 	"github.com/sirupsen/logrus"
 	"github.com/spf13/cobra"
+	"github.com/srg/blim/internal/bledb"
 	"github.com/srg/blim/internal/device"
 	"github.com/srg/blim/internal/groutine"
 	"github.com/srg/blim/scanner"
@@ -37,15 +39,23 @@ var (
 	scanDuration    time.Duration
 	scanFormat      string
 	scanServices    []string
+	scanServiceRefs []string
 	scanAllowList   []string
 	scanBlockList   []string
 	scanNoDuplicate bool
 	scanWatch       bool
+	scanAverageRSSI bool
+	scanRSSIAlpha   float64
+	scanDistance    bool
+	scanPathLossExp float64
 )
 
 type scanConfig struct {
-	scanTimeout  time.Duration
-	outputFormat string
+	scanTimeout   time.Duration
+	outputFormat  string
+	serviceFilter []string // Normalized service UUIDs the results are restricted to (shown in the table header)
+	averageRSSI   bool     // Show one line per device with the averaged RSSI, sorted by average
+	pathLossExp   float64  // Path-loss exponent of the estimated distance column; 0 hides the column
 }
 
 func defaultScanConfig() *scanConfig {
@@ -59,10 +69,15 @@ func init() {
 	scanCmd.Flags().DurationVarP(&scanDuration, "duration", "d", 10*time.Second, "Scan duration (0 for indefinite)")
 	scanCmd.Flags().StringVarP(&scanFormat, "format", "f", "table", "Output format (table, json)")
 	scanCmd.Flags().StringSliceVarP(&scanServices, "services", "s", nil, "Filter by service UUIDs")
+	scanCmd.Flags().StringArrayVar(&scanServiceRefs, "service", nil, "Only show advertisers including this service (name or UUID, repeatable)")
 	scanCmd.Flags().StringSliceVar(&scanAllowList, "allow", nil, "Only show devices with these addresses")
 	scanCmd.Flags().StringSliceVar(&scanBlockList, "block", nil, "Hide devices with these addresses")
 	scanCmd.Flags().BoolVar(&scanNoDuplicate, "no-duplicates", true, "Filter duplicate advertisements")
 	scanCmd.Flags().BoolVarP(&scanWatch, "watch", "w", false, "Continuously scan and update results")
+	scanCmd.Flags().BoolVar(&scanAverageRSSI, "average-rssi", false, "Average RSSI per device over the scan and sort by the average")
+	scanCmd.Flags().Float64Var(&scanRSSIAlpha, "rssi-smoothing", scanner.DefaultRSSISmoothing, "Weight of the newest RSSI sample in the moving average (0 < n <= 1)")
+	scanCmd.Flags().BoolVar(&scanDistance, "estimate-distance", false, "Add a coarse distance column estimated from RSSI and advertised TX power (table output)")
+	scanCmd.Flags().Float64Var(&scanPathLossExp, "path-loss-exponent", device.DefaultPathLossExponent, "Environmental factor of --estimate-distance: 2 in free space, up to ~4 indoors")
 }
 
 func runScan(cmd *cobra.Command, args []string) error {
@@ -79,6 +94,19 @@ func runScan(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("invalid format '%s': must be one of %v", scanFormat, validFormats)
 	}
 
+	if scanAverageRSSI {
+		if scanWatch {
+			return fmt.Errorf("--average-rssi cannot be used with --watch")
+		}
+		if scanRSSIAlpha <= 0 || scanRSSIAlpha > 1 {
+			return fmt.Errorf("invalid --rssi-smoothing %g: must be greater than 0 and at most 1", scanRSSIAlpha)
+		}
+	}
+
+	if scanDistance && scanPathLossExp <= 0 {
+		return fmt.Errorf("invalid --path-loss-exponent %g: must be a positive number", scanPathLossExp)
+	}
+
 	// Configure logger based on --log-level and --verbose flags
 	logger, err := configureLogger(cmd, "verbose")
 	if err != nil {
@@ -107,13 +135,14 @@ func runScan(cmd *cobra.Command, args []string) error {
 	}
 
 	// Validate and normalize service UUIDs if provided
-	var serviceUUIDs []string
-	if len(scanServices) > 0 {
-		var err error
-		serviceUUIDs, err = device.ValidateUUID(scanServices...)
-		if err != nil {
-			return fmt.Errorf("invalid service UUID: %w", err)
-		}
+	serviceUUIDs, err := resolveScanServices(scanServices, scanServiceRefs)
+	if err != nil {
+		return err
+	}
+	cfg.serviceFilter = serviceUUIDs
+	cfg.averageRSSI = scanAverageRSSI
+	if scanDistance {
+		cfg.pathLossExp = scanPathLossExp
 	}
 
 	// Create scan options
@@ -123,6 +152,8 @@ func runScan(cmd *cobra.Command, args []string) error {
 		ServiceUUIDs:    serviceUUIDs,
 		AllowList:       scanAllowList,
 		BlockList:       scanBlockList,
+		AverageRSSI:     scanAverageRSSI,
+		RSSISmoothing:   scanRSSIAlpha,
 	}
 
 	if scanWatch {
@@ -132,6 +163,40 @@ func runScan(cmd *cobra.Command, args []string) error {
 	return runSingleScan(s, scanOpts, cfg, logger)
 }
 
+// resolveScanServices merges --services UUIDs and --service references (names or UUIDs)
+// into a single list of normalized service UUIDs. Returns nil if no service filter was given.
+func resolveScanServices(uuids, refs []string) ([]string, error) {
+	all := append([]string(nil), uuids...)
+	for _, ref := range refs {
+		all = append(all, device.ResolveServiceUUID(ref))
+	}
+	if len(all) == 0 {
+		return nil, nil
+	}
+
+	serviceUUIDs, err := device.ValidateUUID(all...)
+	if err != nil {
+		return nil, fmt.Errorf("invalid service UUID: %w", err)
+	}
+	return serviceUUIDs, nil
+}
+
+// formatServiceFilter renders service UUIDs as "Heart Rate (0x180d), 0xfeaa" using known service names
+func formatServiceFilter(serviceUUIDs []string) string {
+	parts := make([]string, 0, len(serviceUUIDs))
+	for _, uuid := range serviceUUIDs {
+		display := uuid
+		if len(uuid) <= 8 {
+			display = "0x" + uuid
+		}
+		if name := bledb.LookupService(uuid); name != "" {
+			display = fmt.Sprintf("%s (%s)", name, display)
+		}
+		parts = append(parts, display)
+	}
+	return strings.Join(parts, ", ")
+}
+
 func runSingleScan(scanner *scanner.Scanner, opts *scanner.ScanOptions, cfg *scanConfig, logger *logrus.Logger) error {
 	if cfg == nil {
 		cfg = defaultScanConfig()
@@ -156,7 +221,7 @@ func runSingleScan(scanner *scanner.Scanner, opts *scanner.ScanOptions, cfg *sca
 	go func() {
 		select {
 		case <-sigCh:
-			fmt.Println("\nCtrl+C pressed, cancelling scan...")
+			printInfof(os.Stdout, "\nCtrl+C pressed, cancelling scan...\n")
 			cancel()
 		case <-ctx.Done():
 			// Scan completed or timed out - exit cleanly
@@ -191,7 +256,7 @@ func runWatchMode(s *scanner.Scanner, opts *scanner.ScanOptions, cfg *scanConfig
 	go func() {
 		select {
 		case <-sigCh:
-			fmt.Println("\nCtrl+C pressed, cancelling scan...")
+			printInfof(os.Stdout, "\nCtrl+C pressed, cancelling scan...\n")
 			cancel()
 		case <-ctx.Done():
 			// Context cancelled (error path) - exit cleanly
@@ -280,8 +345,12 @@ type deviceWithTime struct {
 }
 
 func displayDevicesTableFromMap(entries map[string]scanner.DeviceEntry, cfg *scanConfig) error {
+	if cfg.outputFormat != "json" && len(cfg.serviceFilter) > 0 {
+		printInfof(os.Stdout, "Filtering by service: %s\n", formatServiceFilter(cfg.serviceFilter))
+	}
+
 	if len(entries) == 0 {
-		fmt.Println("No devices discovered")
+		printInfof(os.Stdout, "No devices discovered\n")
 		return nil
 	}
 
@@ -291,6 +360,17 @@ func displayDevicesTableFromMap(entries map[string]scanner.DeviceEntry, cfg *sca
 		devList = append(devList, e)
 	}
 
+	if cfg.averageRSSI {
+		// Strongest average first
+		sort.Slice(devList, func(i, j int) bool {
+			return devList[i].AvgRSSI > devList[j].AvgRSSI
+		})
+		if cfg.outputFormat == "json" {
+			return displayAveragedDevicesJSON(devList)
+		}
+		return displayAveragedDevicesTable(devList, cfg.pathLossExp)
+	}
+
 	// Sort by Name
 	sort.Slice(devList, func(i, j int) bool {
 		return devList[i].Device.Name() > devList[j].Device.Name()
@@ -305,17 +385,22 @@ func displayDevicesTableFromMap(entries map[string]scanner.DeviceEntry, cfg *sca
 		}
 		return displayDevicesJSON(infoList)
 	default:
-		return displayDevicesTable(devList)
+		return displayDevicesTable(devList, cfg.pathLossExp)
 	}
 }
 
-func displayDevicesTable(entries []scanner.DeviceEntry) error {
+// displayDevicesTable prints one line per device; a positive pathLossExp adds the estimated distance column
+func displayDevicesTable(entries []scanner.DeviceEntry, pathLossExp float64) error {
 	var base io.Writer = os.Stdout
 	if base == nil {
 		base = io.Discard
 	}
 	w := tabwriter.NewWriter(base, 0, 0, 2, ' ', 0)
-	fmt.Fprintln(w, "NAME\tADDRESS\tRSSI\tSERVICES\tLAST SEEN")
+	if pathLossExp > 0 {
+		fmt.Fprintln(w, "NAME\tADDRESS\tRSSI\tDISTANCE\tSERVICES\tLAST SEEN")
+	} else {
+		fmt.Fprintln(w, "NAME\tADDRESS\tRSSI\tSERVICES\tLAST SEEN")
+	}
 	fmt.Fprintln(w, strings.Repeat("-", 80))
 
 	for _, e := range entries {
@@ -337,6 +422,11 @@ func displayDevicesTable(entries []scanner.DeviceEntry) error {
 
 		lastSeen := time.Since(e.LastSeen).Truncate(time.Second)
 
+		if pathLossExp > 0 {
+			fmt.Fprintf(w, "%s\t%s\t%d dBm\t%s\t%s\t%s ago\n",
+				name, dev.Address(), dev.RSSI(), formatDistance(dev.RSSI(), dev.TxPower(), pathLossExp), services, lastSeen)
+			continue
+		}
 		fmt.Fprintf(w, "%s\t%s\t%d dBm\t%s\t%s ago\n",
 			name, dev.Address(), dev.RSSI(), services, lastSeen)
 	}
@@ -344,6 +434,72 @@ func displayDevicesTable(entries []scanner.DeviceEntry) error {
 	return w.Flush()
 }
 
+// displayAveragedDevicesTable prints one line per device with its averaged RSSI (--average-rssi);
+// a positive pathLossExp adds the distance estimated from the average
+func displayAveragedDevicesTable(entries []scanner.DeviceEntry, pathLossExp float64) error {
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	if pathLossExp > 0 {
+		fmt.Fprintln(w, "NAME\tADDRESS\tAVG RSSI\tDISTANCE\tSAMPLES\tLAST SEEN")
+	} else {
+		fmt.Fprintln(w, "NAME\tADDRESS\tAVG RSSI\tSAMPLES\tLAST SEEN")
+	}
+	fmt.Fprintln(w, strings.Repeat("-", 80))
+
+	for _, e := range entries {
+		name := e.Device.Name()
+		if len(name) > 20 {
+			name = name[:17] + "..."
+		}
+		lastSeen := time.Since(e.LastSeen).Truncate(time.Second)
+
+		if pathLossExp > 0 {
+			distance := formatDistance(int(math.Round(e.AvgRSSI)), e.Device.TxPower(), pathLossExp)
+			fmt.Fprintf(w, "%s\t%s\t%.1f dBm\t%s\t%d\t%s ago\n",
+				name, e.Device.Address(), e.AvgRSSI, distance, e.Samples, lastSeen)
+			continue
+		}
+		fmt.Fprintf(w, "%s\t%s\t%.1f dBm\t%d\t%s ago\n",
+			name, e.Device.Address(), e.AvgRSSI, e.Samples, lastSeen)
+	}
+
+	return w.Flush()
+}
+
+// formatDistance renders the estimated distance as "~1.5 m", or "-" when the device doesn't advertise its TX power
+func formatDistance(rssi int, txPower *int, pathLossExp float64) string {
+	if txPower == nil {
+		return "-"
+	}
+	meters, err := device.EstimateDistance(rssi, *txPower, pathLossExp)
+	if err != nil {
+		return "-"
+	}
+	return fmt.Sprintf("~%.1f m", meters)
+}
+
+// averagedDeviceJSON is the JSON form of a device line in --average-rssi mode
+type averagedDeviceJSON struct {
+	Name    string  `json:"name"`
+	Address string  `json:"address"`
+	AvgRSSI float64 `json:"avg_rssi"`
+	Samples int     `json:"samples"`
+}
+
+func displayAveragedDevicesJSON(entries []scanner.DeviceEntry) error {
+	out := make([]averagedDeviceJSON, len(entries))
+	for i, e := range entries {
+		out[i] = averagedDeviceJSON{
+			Name:    e.Device.Name(),
+			Address: e.Device.Address(),
+			AvgRSSI: math.Round(e.AvgRSSI*10) / 10,
+			Samples: e.Samples,
+		}
+	}
+	encoder := json.NewEncoder(os.Stdout)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(out)
+}
+
 func displayDevicesJSON(devices []device.DeviceInfo) error {
 	var w io.Writer = os.Stdout
 	if w == nil {
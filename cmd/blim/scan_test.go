@@ -15,6 +15,7 @@ import (
 	"github.com/srg/blim/scanner"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
 	"github.com/stretchr/testify/suite"
 )
 
@@ -27,10 +28,13 @@ type ScanTestSuite struct {
 		scanFormat      string
 		scanVerbose     bool
 		scanServices    []string
+		scanServiceRefs []string
 		scanAllowList   []string
 		scanBlockList   []string
 		scanNoDuplicate bool
 		scanWatch       bool
+		scanAverageRSSI bool
+		scanRSSIAlpha   float64
 	}
 }
 
@@ -40,10 +44,13 @@ func (suite *ScanTestSuite) SetupSuite() {
 	suite.originalFlags.scanDuration = scanDuration
 	suite.originalFlags.scanFormat = scanFormat
 	suite.originalFlags.scanServices = scanServices
+	suite.originalFlags.scanServiceRefs = scanServiceRefs
 	suite.originalFlags.scanAllowList = scanAllowList
 	suite.originalFlags.scanBlockList = scanBlockList
 	suite.originalFlags.scanNoDuplicate = scanNoDuplicate
 	suite.originalFlags.scanWatch = scanWatch
+	suite.originalFlags.scanAverageRSSI = scanAverageRSSI
+	suite.originalFlags.scanRSSIAlpha = scanRSSIAlpha
 
 	// Save the original BLE device factory and inject mock
 	suite.originalDeviceFactory = devicefactory.DeviceFactory
@@ -63,10 +70,13 @@ func (suite *ScanTestSuite) TearDownSuite() {
 	scanDuration = suite.originalFlags.scanDuration
 	scanFormat = suite.originalFlags.scanFormat
 	scanServices = suite.originalFlags.scanServices
+	scanServiceRefs = suite.originalFlags.scanServiceRefs
 	scanAllowList = suite.originalFlags.scanAllowList
 	scanBlockList = suite.originalFlags.scanBlockList
 	scanNoDuplicate = suite.originalFlags.scanNoDuplicate
 	scanWatch = suite.originalFlags.scanWatch
+	scanAverageRSSI = suite.originalFlags.scanAverageRSSI
+	scanRSSIAlpha = suite.originalFlags.scanRSSIAlpha
 }
 
 // SetupTest runs before each test in the suite
@@ -82,10 +92,13 @@ func (suite *ScanTestSuite) SetupTest() {
 	scanCmd.Flags().DurationVarP(&scanDuration, "duration", "d", 10*time.Second, "Scan duration (0 for indefinite)")
 	scanCmd.Flags().StringVarP(&scanFormat, "format", "f", "table", "Output format (table, json)")
 	scanCmd.Flags().StringSliceVarP(&scanServices, "services", "s", nil, "Filter by service UUIDs")
+	scanCmd.Flags().StringArrayVar(&scanServiceRefs, "service", nil, "Only show advertisers including this service (name or UUID, repeatable)")
 	scanCmd.Flags().StringSliceVar(&scanAllowList, "allow", nil, "Only show devices with these addresses")
 	scanCmd.Flags().StringSliceVar(&scanBlockList, "block", nil, "Hide devices with these addresses")
 	scanCmd.Flags().BoolVar(&scanNoDuplicate, "no-duplicates", true, "Filter duplicate advertisements")
 	scanCmd.Flags().BoolVarP(&scanWatch, "watch", "w", false, "Continuously scan and update results")
+	scanCmd.Flags().BoolVar(&scanAverageRSSI, "average-rssi", false, "Average RSSI per device over the scan and sort by the average")
+	scanCmd.Flags().Float64Var(&scanRSSIAlpha, "rssi-smoothing", scanner.DefaultRSSISmoothing, "Weight of the newest RSSI sample in the moving average (0 < n <= 1)")
 }
 
 func (suite *ScanTestSuite) TestScanCmd_Help() {
@@ -120,6 +133,35 @@ func (suite *ScanTestSuite) TestScanCmd_InvalidFormat() {
 	suite.Assert().Contains(err.Error(), "invalid format 'invalid': must be one of [table json]", "error MUST list valid formats")
 }
 
+func (suite *ScanTestSuite) TestScanCmd_AverageRSSIValidation() {
+	// GOAL: Verify --average-rssi rejects invalid smoothing factors and watch mode
+	//
+	// TEST SCENARIO: Execute scan --average-rssi with bad options → returns error → error names the offending flag
+
+	tests := []struct {
+		name        string
+		args        []string
+		expectedErr string
+	}{
+		{"zero smoothing", []string{"scan", "--average-rssi", "--rssi-smoothing=0"}, "invalid --rssi-smoothing 0"},
+		{"smoothing above one", []string{"scan", "--average-rssi", "--rssi-smoothing=1.5"}, "invalid --rssi-smoothing 1.5"},
+		{"watch mode", []string{"scan", "--average-rssi", "--watch"}, "--average-rssi cannot be used with --watch"},
+	}
+
+	for _, tt := range tests {
+		suite.Run(tt.name, func() {
+			resetScanFlags()
+
+			cmd := &cobra.Command{}
+			cmd.AddCommand(scanCmd)
+
+			_, err := suite.ExecuteCommand(cmd, tt.args...)
+			suite.Require().Error(err, "invalid --average-rssi options MUST be rejected")
+			suite.Assert().Contains(err.Error(), tt.expectedErr)
+		})
+	}
+}
+
 func (suite *ScanTestSuite) TestScanCmd_Flags() {
 	// GOAL: Verify scan command parses all flags correctly
 	//
@@ -169,6 +211,21 @@ func (suite *ScanTestSuite) TestScanCmd_Flags() {
 				"services": []string{"180F", "180A"},
 			},
 		},
+		{
+			name: "repeatable service flag",
+			args: []string{"scan", "--service=Heart Rate", "--service", "feaa"},
+			expected: map[string]interface{}{
+				"service": []string{"Heart Rate", "feaa"},
+			},
+		},
+		{
+			name: "average rssi",
+			args: []string{"scan", "--average-rssi", "--rssi-smoothing=0.5"},
+			expected: map[string]interface{}{
+				"average-rssi":   true,
+				"rssi-smoothing": 0.5,
+			},
+		},
 	}
 
 	for _, tt := range tests {
@@ -193,6 +250,12 @@ func (suite *ScanTestSuite) TestScanCmd_Flags() {
 					suite.Assert().Equal(expected, scanWatch, "watch flag MUST be parsed correctly")
 				case "services":
 					suite.Assert().Equal(expected, scanServices, "services flag MUST be parsed correctly")
+				case "service":
+					suite.Assert().Equal(expected, scanServiceRefs, "service flag MUST accumulate repeated values")
+				case "average-rssi":
+					suite.Assert().Equal(expected, scanAverageRSSI, "average-rssi flag MUST be parsed correctly")
+				case "rssi-smoothing":
+					suite.Assert().Equal(expected, scanRSSIAlpha, "rssi-smoothing flag MUST be parsed correctly")
 				}
 			}
 		})
@@ -224,6 +287,25 @@ func (suite *ScanTestSuite) TestScanCmd_WatchMode() {
 	}
 }
 
+func TestResolveScanServices(t *testing.T) {
+	// GOAL: Verify --services UUIDs and --service names/UUIDs merge into normalized service UUIDs
+	//
+	// TEST SCENARIO: Resolve names and UUID variants → normalized UUIDs in flag order → known names shown for display
+
+	uuids, err := resolveScanServices([]string{"180F"}, []string{"Heart Rate", "0000FEAA-0000-1000-8000-00805F9B34FB"})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"180f", "180d", "feaa"}, uuids, "service references MUST resolve to normalized UUIDs")
+
+	uuids, err = resolveScanServices(nil, nil)
+	require.NoError(t, err)
+	assert.Nil(t, uuids, "no service flags MUST mean no filter")
+
+	assert.Equal(t, "Battery Service (0x180f), Heart Rate (0x180d)", formatServiceFilter([]string{"180f", "180d"}),
+		"known services MUST be displayed by name")
+	assert.Equal(t, "6e400001b5a3f393e0a9e50e24dcca9e", formatServiceFilter([]string{"6e400001b5a3f393e0a9e50e24dcca9e"}),
+		"unknown 128-bit services MUST be displayed as UUID")
+}
+
 func TestDisplayDevicesTable(t *testing.T) {
 	// GOAL: Verify displayDevicesTable outputs without errors
 	//
@@ -259,8 +341,22 @@ func TestDisplayDevicesTable(t *testing.T) {
 		{Device: device2, LastSeen: time.Now()},
 	}
 
-	err := displayDevicesTable(devices)
+	err := displayDevicesTable(devices, 0)
 	assert.NoError(t, err, "displayDevicesTable MUST NOT return error")
+
+	err = displayDevicesTable(devices, device.DefaultPathLossExponent)
+	assert.NoError(t, err, "displayDevicesTable with distance column MUST NOT return error")
+}
+
+func TestFormatDistance(t *testing.T) {
+	// GOAL: Verify the --estimate-distance column renders the estimate or a placeholder without TX power
+	//
+	// TEST SCENARIO: TX power -59, RSSI -79, n=2 → "~10.0 m"; no TX power → "-"
+
+	txPower := -59
+	assert.Equal(t, "~10.0 m", formatDistance(-79, &txPower, 2))
+	assert.Equal(t, "~1.0 m", formatDistance(-59, &txPower, 2))
+	assert.Equal(t, "-", formatDistance(-79, nil, 2), "devices without TX power MUST show a placeholder")
 }
 
 func TestDisplayDevicesJSON(t *testing.T) {
@@ -356,10 +452,13 @@ func resetScanFlags() {
 	scanDuration = 10 * time.Second
 	scanFormat = "table"
 	scanServices = nil
+	scanServiceRefs = nil
 	scanAllowList = nil
 	scanBlockList = nil
 	scanNoDuplicate = true
 	scanWatch = false
+	scanAverageRSSI = false
+	scanRSSIAlpha = scanner.DefaultRSSISmoothing
 }
 
 // TestScanCommandSuite runs the test suite
@@ -0,0 +1,143 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"os"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/srg/blim/internal/device"
+)
+
+// shutdownGracePeriod is how long main waits after SIGINT/SIGTERM for the command to return before
+// running the remaining teardowns itself
+const shutdownGracePeriod = 5 * time.Second
+
+// Teardown releases a resource a command holds while running (connection, PTY, symlink).
+// Commands release their resources themselves when the command context is canceled; a registered Teardown
+// is the fallback main runs when the command does not return in time, so it must tolerate running after
+// (or concurrently with) the command's own cleanup.
+type Teardown interface {
+	Teardown() error
+}
+
+// TeardownFunc adapts a function to Teardown
+type TeardownFunc func() error
+
+func (f TeardownFunc) Teardown() error { return f() }
+
+type registeredTeardown struct {
+	id       int
+	name     string
+	teardown Teardown
+}
+
+var (
+	teardownsMutex sync.Mutex
+	teardowns      []registeredTeardown // In registration order
+	nextTeardownID int
+)
+
+// registerTeardown registers t under name (used in error messages) and returns the function unregistering it.
+// Commands defer the returned function so a completed cleanup is not repeated on exit.
+func registerTeardown(name string, t Teardown) (unregister func()) {
+	teardownsMutex.Lock()
+	defer teardownsMutex.Unlock()
+
+	id := nextTeardownID
+	nextTeardownID++
+	teardowns = append(teardowns, registeredTeardown{id: id, name: name, teardown: t})
+
+	return func() {
+		teardownsMutex.Lock()
+		defer teardownsMutex.Unlock()
+		teardowns = slices.DeleteFunc(teardowns, func(r registeredTeardown) bool { return r.id == id })
+	}
+}
+
+// runTeardowns runs and unregisters every registered teardown, the most recently registered first,
+// and returns their joined errors
+func runTeardowns() error {
+	teardownsMutex.Lock()
+	entries := teardowns
+	teardowns = nil
+	teardownsMutex.Unlock()
+
+	var errs []error
+	for _, entry := range slices.Backward(entries) {
+		if err := entry.teardown.Teardown(); err != nil {
+			errs = append(errs, &teardownError{name: entry.name, err: err})
+		}
+	}
+	return errors.Join(errs...)
+}
+
+type teardownError struct {
+	name string
+	err  error
+}
+
+func (e *teardownError) Error() string { return e.name + ": " + e.err.Error() }
+func (e *teardownError) Unwrap() error { return e.err }
+
+// disconnectTeardown returns a Teardown disconnecting dev if it is still connected
+func disconnectTeardown(dev device.Device) Teardown {
+	return TeardownFunc(func() error {
+		if !dev.IsConnected() {
+			return nil
+		}
+		return dev.Disconnect()
+	})
+}
+
+// removeSymlinkTeardown returns a Teardown removing the symlink at path; a missing path is not an error and
+// anything other than a symlink is left alone
+func removeSymlinkTeardown(path string) Teardown {
+	return TeardownFunc(func() error {
+		info, err := os.Lstat(path)
+		if err != nil || info.Mode()&os.ModeSymlink == 0 {
+			return nil
+		}
+		if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+		return nil
+	})
+}
+
+// commandContext returns the context of cmd, canceled by main on SIGINT/SIGTERM.
+// Commands run directly (not via Execute) have no context and get a background one.
+func commandContext(cmd *cobra.Command) context.Context {
+	if ctx := cmd.Context(); ctx != nil {
+		return ctx
+	}
+	return context.Background()
+}
+
+// executeWithShutdown executes cmd with ctx. Once ctx is canceled (main cancels it on SIGINT/SIGTERM), it calls
+// stop so a second signal terminates the process immediately, then waits up to grace for the command to return.
+// Teardowns still registered afterwards run before returning; their errors are passed to onTeardownError.
+func executeWithShutdown(ctx context.Context, stop func(), cmd *cobra.Command, grace time.Duration, onTeardownError func(error)) error {
+	done := make(chan error, 1)
+	go func() { done <- cmd.ExecuteContext(ctx) }()
+
+	var err error
+	select {
+	case err = <-done:
+	case <-ctx.Done():
+		stop()
+		select {
+		case err = <-done:
+		case <-time.After(grace):
+			err = context.Canceled
+		}
+	}
+
+	if teardownErr := runTeardowns(); teardownErr != nil && onTeardownError != nil {
+		onTeardownError(teardownErr)
+	}
+	return err
+}
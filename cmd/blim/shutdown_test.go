@@ -0,0 +1,131 @@
+//go:build test
+
+package main
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRunTeardowns(t *testing.T) {
+	// GOAL: Verify registered teardowns run once, most recent first, unregistered ones are skipped and errors are named
+	//
+	// TEST SCENARIO: Register three teardowns, unregister the middle one → run → reverse order without it, error names the failing one → second run is a no-op
+
+	var order []string
+	record := func(name string, err error) Teardown {
+		return TeardownFunc(func() error {
+			order = append(order, name)
+			return err
+		})
+	}
+
+	registerTeardown("first", record("first", errors.New("boom")))
+	unregister := registerTeardown("second", record("second", nil))
+	registerTeardown("third", record("third", nil))
+	unregister()
+
+	err := runTeardowns()
+	assert.Equal(t, []string{"third", "first"}, order, "teardowns MUST run most recent first, skipping unregistered ones")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "first: boom", "error MUST name the failing teardown")
+
+	order = nil
+	assert.NoError(t, runTeardowns())
+	assert.Empty(t, order, "teardowns MUST run only once")
+}
+
+func TestRemoveSymlinkTeardown(t *testing.T) {
+	// GOAL: Verify the symlink teardown removes symlinks only and tolerates a missing path
+	//
+	// TEST SCENARIO: Symlink → removed → missing path → no error → regular file → left alone
+
+	dir := t.TempDir()
+	target := filepath.Join(dir, "target")
+	require.NoError(t, os.WriteFile(target, nil, 0o600))
+	link := filepath.Join(dir, "link")
+	require.NoError(t, os.Symlink(target, link))
+
+	require.NoError(t, removeSymlinkTeardown(link).Teardown())
+	_, err := os.Lstat(link)
+	assert.True(t, os.IsNotExist(err), "symlink MUST be removed")
+
+	assert.NoError(t, removeSymlinkTeardown(link).Teardown(), "missing symlink MUST NOT be an error")
+
+	require.NoError(t, removeSymlinkTeardown(target).Teardown())
+	_, err = os.Stat(target)
+	assert.NoError(t, err, "regular file MUST be left alone")
+}
+
+func TestExecuteWithShutdown(t *testing.T) {
+	// GOAL: Verify an interrupt cancels the command context, waits for the command to clean up, and falls back to teardowns
+	//
+	// TEST SCENARIO: Command returning on cancel → its result is returned and its unregistered teardown never runs →
+	// command ignoring cancel → returns context.Canceled after the grace period and its teardown runs
+
+	newCommand := func(run func(cmd *cobra.Command) error) *cobra.Command {
+		cmd := &cobra.Command{
+			Use:  "test",
+			RunE: func(cmd *cobra.Command, args []string) error { return run(cmd) },
+		}
+		cmd.SetArgs([]string{})
+		return cmd
+	}
+
+	t.Run("command cleans up", func(t *testing.T) {
+		tornDown := false
+		cmd := newCommand(func(cmd *cobra.Command) error {
+			defer registerTeardown("device", TeardownFunc(func() error {
+				tornDown = true
+				return nil
+			}))()
+			<-commandContext(cmd).Done()
+			return nil
+		})
+
+		ctx, cancel := context.WithCancel(context.Background())
+		stopped := false
+		time.AfterFunc(50*time.Millisecond, cancel)
+
+		err := executeWithShutdown(ctx, func() { stopped = true }, cmd, 5*time.Second, nil)
+		assert.NoError(t, err, "command result MUST be returned")
+		assert.True(t, stopped, "signal handling MUST be stopped after the first interrupt")
+		assert.False(t, tornDown, "teardown of a command that cleaned up MUST NOT run")
+	})
+
+	t.Run("command hangs", func(t *testing.T) {
+		tornDown := make(chan struct{})
+		release := make(chan struct{})
+		defer close(release)
+		cmd := newCommand(func(cmd *cobra.Command) error {
+			registerTeardown("device", TeardownFunc(func() error {
+				close(tornDown)
+				return nil
+			}))
+			<-release
+			return nil
+		})
+
+		ctx, cancel := context.WithCancel(context.Background())
+		time.AfterFunc(50*time.Millisecond, cancel)
+
+		start := time.Now()
+		err := executeWithShutdown(ctx, func() {}, cmd, 100*time.Millisecond, nil)
+		assert.ErrorIs(t, err, context.Canceled, "a command not returning in time MUST be reported as canceled")
+		assert.GreaterOrEqual(t, time.Since(start), 150*time.Millisecond, "the grace period MUST be waited")
+
+		select {
+		case <-tornDown:
+		default:
+			t.Fatal("teardown of a hanging command MUST run")
+		}
+	})
+}
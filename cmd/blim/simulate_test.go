@@ -0,0 +1,86 @@
+//go:build test
+
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+)
+
+// simulatedHeartRateProfile is a --simulate device description with descriptors on every characteristic
+const simulatedHeartRateProfile = `{
+	"services": [
+		{
+			"uuid": "180d",
+			"characteristics": [
+				{"uuid": "2a37", "properties": "read,notify", "value": [0, 72],
+				 "descriptors": [{"uuid": "2902", "value": [0, 0]}]}
+			]
+		},
+		{
+			"uuid": "180f",
+			"characteristics": [
+				{"uuid": "2a19", "properties": "read,notify", "value": [87],
+				 "descriptors": [{"uuid": "2902", "value": [0, 0]}, {"uuid": "2901", "value": [66, 97, 116]}]}
+			]
+		}
+	]
+}`
+
+// SimulateTestSuite runs commands end to end against a simulated device (--simulate)
+type SimulateTestSuite struct {
+	CommandTestSuite
+}
+
+// TearDownTest resets the flags parsed by the executed commands
+func (suite *SimulateTestSuite) TearDownTest() {
+	_ = rootCmd.PersistentFlags().Set("simulate", "")
+	readHex = false
+	readNoDesc = false
+	readOnlyServices = nil
+
+	suite.CommandTestSuite.TearDownTest()
+}
+
+// executeSimulated runs blim with --simulate pointing at profile and returns the captured stdout
+func (suite *SimulateTestSuite) executeSimulated(profile string, args ...string) (string, error) {
+	path := filepath.Join(suite.T().TempDir(), "device.json")
+	suite.Require().NoError(os.WriteFile(path, []byte(profile), 0o644))
+
+	var err error
+	output := suite.CaptureStdout(func() {
+		_, err = suite.ExecuteCommand(rootCmd, append([]string{"--simulate", path}, args...)...)
+	})
+	return output, err
+}
+
+func (suite *SimulateTestSuite) TestNoDescriptors() {
+	// GOAL: Verify --no-descriptors discovery works against the simulated device
+	//
+	// TEST SCENARIO: blim --simulate read --no-descriptors → scoped discovery without descriptors → value printed
+
+	output, err := suite.executeSimulated(simulatedHeartRateProfile, "read", "--no-descriptors", "--hex", "AA:BB:CC:DD:EE:FF", "2a19")
+	suite.Require().NoError(err, "read with --no-descriptors MUST succeed against the simulated device")
+	suite.Assert().True(strings.HasSuffix(output, "57\n"), "MUST print the simulated value, got: %q", output)
+}
+
+func (suite *SimulateTestSuite) TestOnlyService() {
+	// GOAL: Verify --only-service discovery works against the simulated device and scopes it to the listed service
+	//
+	// TEST SCENARIO: blim --simulate read --only-service 180f → listed service read → characteristic of an unlisted service not found
+
+	output, err := suite.executeSimulated(simulatedHeartRateProfile, "read", "--only-service", "180f", "--hex", "AA:BB:CC:DD:EE:FF", "2a19")
+	suite.Require().NoError(err, "read with --only-service MUST succeed against the simulated device")
+	suite.Assert().True(strings.HasSuffix(output, "57\n"), "MUST print the simulated value, got: %q", output)
+
+	_, err = suite.executeSimulated(simulatedHeartRateProfile, "read", "--only-service", "180f", "--hex", "AA:BB:CC:DD:EE:FF", "2a37")
+	suite.Assert().ErrorContains(err, "not found", "characteristic of an unlisted service MUST NOT be discovered")
+}
+
+func TestSimulateTestSuite(t *testing.T) {
+	suite.Run(t, new(SimulateTestSuite))
+}
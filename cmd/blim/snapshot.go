@@ -0,0 +1,197 @@
+package main
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/srg/blim/inspector"
+	"github.com/srg/blim/internal/device"
+)
+
+// snapshotCmd represents the snapshot command
+var snapshotCmd = &cobra.Command{
+	Use:   "snapshot <device-address>",
+	Short: "Read all readable characteristics and print a decoded JSON snapshot",
+	Long: fmt.Sprintf(`Connects to a BLE device, reads every readable characteristic, decodes the values
+with the registered parsers, prints a single JSON document and disconnects.
+
+Values are included as hex and, when a parser exists (or the characteristic is a known
+UTF-8 string), as "value". Read errors are reported inline per characteristic. The Generic
+Access (1800) and Generic Attribute (1801) services are skipped unless --all is set.
+
+Examples:
+  # Snapshot all sensor values
+  blim snapshot %s
+
+  # Only the Environmental Sensing and Battery services, pretty-printed
+  blim snapshot %s --service 181a --service 180f --pretty
+
+  # Append one snapshot per minute to a log for monitoring
+  while sleep 60; do blim snapshot %s >> sensors.jsonl; done
+
+%s`, exampleDeviceAddress, exampleDeviceAddress, exampleDeviceAddress, deviceAddressNote),
+	Args: cobra.ExactArgs(1),
+	RunE: runSnapshot,
+}
+
+var (
+	snapshotConnectTimeout time.Duration
+	snapshotReadTimeout    time.Duration
+	snapshotServices       []string
+	snapshotAll            bool
+	snapshotPretty         bool
+)
+
+// snapshotSkippedServices are GATT bookkeeping services without sensor data, skipped unless --all is set
+var snapshotSkippedServices = map[string]struct{}{
+	"1800": {}, // Generic Access
+	"1801": {}, // Generic Attribute
+}
+
+func init() {
+	snapshotCmd.Flags().DurationVar(&snapshotConnectTimeout, "connect-timeout", defaultConnectTimeout, "Connection timeout")
+	snapshotCmd.Flags().DurationVar(&snapshotReadTimeout, "timeout", defaultCharacteristicReadTimeout, "Timeout for each characteristic read")
+	snapshotCmd.Flags().StringArrayVar(&snapshotServices, "service", nil, "Only include this service (repeatable)")
+	snapshotCmd.Flags().BoolVar(&snapshotAll, "all", false, "Include the Generic Access and Generic Attribute services")
+	snapshotCmd.Flags().BoolVar(&snapshotPretty, "pretty", false, "Indent the JSON output (one line by default)")
+}
+
+// deviceSnapshot is the JSON document printed by the snapshot command
+type deviceSnapshot struct {
+	Address   string            `json:"address"`
+	Timestamp string            `json:"timestamp"`
+	Services  []serviceSnapshot `json:"services"`
+}
+
+type serviceSnapshot struct {
+	UUID            string                   `json:"uuid"`
+	Name            string                   `json:"name,omitempty"`
+	Characteristics []characteristicSnapshot `json:"characteristics"`
+}
+
+type characteristicSnapshot struct {
+	UUID  string      `json:"uuid"`
+	Name  string      `json:"name,omitempty"`
+	Hex   string      `json:"hex,omitempty"`
+	Value interface{} `json:"value,omitempty"` // Parsed value, or UTF-8 text for string characteristics
+	Error string      `json:"error,omitempty"`
+}
+
+func runSnapshot(cmd *cobra.Command, args []string) error {
+	address := args[0]
+
+	logger, err := configureLogger(cmd, "verbose")
+	if err != nil {
+		return err
+	}
+
+	// All arguments validated - don't show usage on runtime errors
+	cmd.SilenceUsage = true
+
+	ctx := commandContext(cmd)
+
+	opts := &inspector.InspectOptions{
+		ConnectTimeout:            snapshotConnectTimeout,
+		CharacteristicReadTimeout: snapshotReadTimeout,
+		ConnectRetries:            connectRetries,
+		ConnectRetryBackoff:       connectRetryBackoff,
+		ByIdentifier:              connectByIdentifier,
+		GATTCacheDir:              gattCacheDir(),
+		DiscoveryTimeout:          discoveryTimeout,
+	}
+
+	// No progress output: stdout carries only the JSON document
+	_, err = inspector.InspectDevice(ctx, address, opts, logger, NoOpProgressCallback(), func(dev device.Device) (any, error) {
+		// Disconnect on exit even if the operation does not return after an interrupt
+		defer registerTeardown("disconnect "+address, disconnectTeardown(dev))()
+
+		conn := dev.GetConnection()
+		if conn == nil {
+			return nil, fmt.Errorf("device not connected")
+		}
+		snapshot := buildSnapshot(conn, address, snapshotServices, snapshotAll, snapshotReadTimeout)
+		return nil, writeSnapshot(os.Stdout, snapshot, snapshotPretty)
+	})
+	return err
+}
+
+// buildSnapshot reads every readable characteristic of the selected services, in UUID order.
+// Services left without readable characteristics are omitted.
+// services limits the snapshot to the given service UUIDs (all if empty); includeAll keeps the GATT bookkeeping services.
+func buildSnapshot(conn device.Connection, address string, services []string, includeAll bool, readTimeout time.Duration) *deviceSnapshot {
+	selected := make(map[string]struct{}, len(services))
+	for _, uuid := range services {
+		selected[device.NormalizeUUID(uuid)] = struct{}{}
+	}
+
+	snapshot := &deviceSnapshot{
+		Address:   address,
+		Timestamp: time.Now().UTC().Format(time.RFC3339),
+		Services:  []serviceSnapshot{},
+	}
+
+	svcs := conn.Services()
+	sort.Slice(svcs, func(i, j int) bool { return svcs[i].UUID() < svcs[j].UUID() })
+	for _, svc := range svcs {
+		uuid := device.NormalizeUUID(svc.UUID())
+		if len(selected) > 0 {
+			if _, ok := selected[uuid]; !ok {
+				continue
+			}
+		} else if _, skip := snapshotSkippedServices[uuid]; skip && !includeAll {
+			continue
+		}
+
+		svcSnapshot := serviceSnapshot{UUID: svc.UUID(), Name: svc.KnownName(), Characteristics: []characteristicSnapshot{}}
+		for _, char := range svc.GetCharacteristics() {
+			if props := char.GetProperties(); props == nil || props.Read() == nil {
+				continue
+			}
+			svcSnapshot.Characteristics = append(svcSnapshot.Characteristics, snapshotCharacteristic(char, readTimeout))
+		}
+		if len(svcSnapshot.Characteristics) > 0 {
+			snapshot.Services = append(snapshot.Services, svcSnapshot)
+		}
+	}
+	return snapshot
+}
+
+// snapshotCharacteristic reads and decodes one characteristic; failures are recorded in Error
+func snapshotCharacteristic(char device.Characteristic, readTimeout time.Duration) characteristicSnapshot {
+	entry := characteristicSnapshot{UUID: char.UUID(), Name: char.KnownName()}
+	data, err := char.Read(readTimeout)
+	if err != nil {
+		entry.Error = err.Error()
+		return entry
+	}
+	entry.Hex = hex.EncodeToString(data)
+
+	if device.IsParsableCharacteristic(char.UUID()) {
+		if parsed, err := device.ParseCharacteristicValue(char.UUID(), data); err == nil && parsed != nil {
+			entry.Value = parsed
+		}
+	} else if device.IsUTF8Characteristic(char.UUID(), char.GetDescriptors()) {
+		if text, ok := device.DecodeUTF8Value(data); ok {
+			entry.Value = text
+		}
+	}
+	return entry
+}
+
+// writeSnapshot encodes the snapshot as JSON, on a single line unless pretty is set
+func writeSnapshot(w io.Writer, snapshot *deviceSnapshot, pretty bool) error {
+	encoder := json.NewEncoder(w)
+	if pretty {
+		encoder.SetIndent("", "  ")
+	}
+	if err := encoder.Encode(snapshot); err != nil {
+		return fmt.Errorf("failed to encode snapshot: %w", err)
+	}
+	return nil
+}
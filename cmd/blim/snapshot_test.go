@@ -0,0 +1,145 @@
+//go:build test
+
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+)
+
+// SnapshotTestSuite tests snapshot command with mock BLE peripheral
+type SnapshotTestSuite struct {
+	CommandTestSuite
+}
+
+// SetupTest runs before each test in the suite
+func (suite *SnapshotTestSuite) SetupTest() {
+	suite.WithPeripheral().
+		FromJSON(`{
+			"services": [
+				{
+					"uuid": "1800",
+					"characteristics": [
+						{"uuid": "2a00", "properties": "read", "value": [66, 108, 105, 109]}
+					]
+				},
+				{
+					"uuid": "180f",
+					"characteristics": [
+						{"uuid": "2a19", "properties": "read,notify", "value": [75]}
+					]
+				},
+				{
+					"uuid": "1805",
+					"characteristics": [
+						{"uuid": "2a2b", "properties": "read", "value": [234, 7, 10, 16, 12, 30, 45, 5, 128, 1]},
+						{"uuid": "2a16", "properties": "write", "value": [0]}
+					]
+				},
+				{
+					"uuid": "1802",
+					"characteristics": [
+						{"uuid": "2a06", "properties": "write-without-response", "value": [0]}
+					]
+				}
+			]
+		}`).
+		Build()
+
+	suite.CommandTestSuite.SetupTest()
+}
+
+// findService returns the snapshot of the given service, or nil
+func findService(snapshot *deviceSnapshot, uuid string) *serviceSnapshot {
+	for i := range snapshot.Services {
+		if snapshot.Services[i].UUID == uuid {
+			return &snapshot.Services[i]
+		}
+	}
+	return nil
+}
+
+func (suite *SnapshotTestSuite) TestBuildSnapshot_DefaultServices() {
+	// GOAL: Verify the snapshot reads and decodes readable characteristics of sensor services only
+	//
+	// TEST SCENARIO: Snapshot without filters → 1800 skipped, write-only chars and services omitted → known values decoded
+
+	dev, cleanup := suite.ConnectDevice("")
+	defer cleanup()
+
+	snapshot := buildSnapshot(dev.GetConnection(), "AA:BB", nil, false, time.Second)
+	suite.Assert().Equal("AA:BB", snapshot.Address)
+	suite.Assert().NotEmpty(snapshot.Timestamp, "snapshot MUST be timestamped")
+
+	suite.Assert().Nil(findService(snapshot, "1800"), "Generic Access MUST be skipped by default")
+	suite.Assert().Nil(findService(snapshot, "1802"), "service without readable characteristics MUST be omitted")
+
+	battery := findService(snapshot, "180f")
+	suite.Require().NotNil(battery, "battery service MUST be included")
+	suite.Require().Len(battery.Characteristics, 1)
+	suite.Assert().Equal("4b", battery.Characteristics[0].Hex, "raw value MUST be hex encoded")
+	suite.Assert().Nil(battery.Characteristics[0].Value, "value without parser MUST be hex only")
+
+	currentTime := findService(snapshot, "1805")
+	suite.Require().NotNil(currentTime, "current time service MUST be included")
+	suite.Require().Len(currentTime.Characteristics, 1, "write-only characteristic MUST NOT be read")
+	suite.Assert().Equal("2a2b", currentTime.Characteristics[0].UUID)
+	suite.Assert().NotNil(currentTime.Characteristics[0].Value, "current time MUST be parsed")
+	suite.Assert().Empty(currentTime.Characteristics[0].Error)
+}
+
+func (suite *SnapshotTestSuite) TestBuildSnapshot_ServiceFilter() {
+	// GOAL: Verify --service and --all select the snapshot services
+	//
+	// TEST SCENARIO: Filter by 180f → only battery; --all → Generic Access included with decoded device name
+
+	dev, cleanup := suite.ConnectDevice("")
+	defer cleanup()
+	conn := dev.GetConnection()
+
+	filtered := buildSnapshot(conn, "AA:BB", []string{"180F"}, false, time.Second)
+	suite.Require().Len(filtered.Services, 1, "service filter MUST limit the snapshot")
+	suite.Assert().Equal("180f", filtered.Services[0].UUID)
+
+	all := buildSnapshot(conn, "AA:BB", nil, true, time.Second)
+	access := findService(all, "1800")
+	suite.Require().NotNil(access, "--all MUST include Generic Access")
+	suite.Require().Len(access.Characteristics, 1)
+	suite.Assert().Equal("Blim", access.Characteristics[0].Value, "device name MUST be decoded as UTF-8")
+}
+
+func (suite *SnapshotTestSuite) TestWriteSnapshot_Format() {
+	// GOAL: Verify snapshot JSON is one line by default and indented with --pretty
+	//
+	// TEST SCENARIO: Encode snapshot compact and pretty → both valid JSON → only pretty spans lines
+
+	snapshot := &deviceSnapshot{
+		Address:   "AA:BB",
+		Timestamp: "2026-01-01T00:00:00Z",
+		Services: []serviceSnapshot{{
+			UUID:            "180f",
+			Characteristics: []characteristicSnapshot{{UUID: "2a19", Hex: "4b", Value: 75}},
+		}},
+	}
+
+	var compact bytes.Buffer
+	suite.Require().NoError(writeSnapshot(&compact, snapshot, false))
+	suite.Assert().Equal(1, strings.Count(compact.String(), "\n"), "compact output MUST be a single line")
+	suite.Assert().True(json.Valid(compact.Bytes()), "compact output MUST be valid JSON")
+
+	var pretty bytes.Buffer
+	suite.Require().NoError(writeSnapshot(&pretty, snapshot, true))
+	suite.Assert().Greater(strings.Count(pretty.String(), "\n"), 1, "pretty output MUST span multiple lines")
+	suite.Assert().True(json.Valid(pretty.Bytes()), "pretty output MUST be valid JSON")
+	suite.Assert().Contains(pretty.String(), `  "address": "AA:BB"`)
+}
+
+// TestSnapshotTestSuite runs the test suite
+func TestSnapshotTestSuite(t *testing.T) {
+	suite.Run(t, new(SnapshotTestSuite))
+}
@@ -1,16 +1,16 @@
 package main
 
 import (
-	"context"
 	"encoding/hex"
+	"encoding/json"
 	"fmt"
 	"os"
-	"os/signal"
 	"sort"
 	"strings"
-	"syscall"
+	"text/template"
 	"time"
 
+	"github.com/sirupsen/logrus"
 	"github.com/spf13/cobra"
 	"github.com/srg/blim/inspector"
 	"github.com/srg/blim/internal/device"
@@ -27,6 +27,11 @@ Stream modes:
   batched  - Collect notifications, output at rate interval
   latest   - Keep only latest value per characteristic, output at rate interval
 
+--rate paces delivery: each tick outputs at most one queued value per characteristic, so a
+device notifying faster than the rate falls behind. --aggregate-window instead flushes on a
+steady wall-clock timer, outputting the latest value each characteristic sent during the
+window (implies --mode latest); --emit-empty also prints "-" for windows without notifications.
+
 Examples:
   # Subscribe to single characteristic
   blim subscribe %s 2a37
@@ -43,19 +48,46 @@ Examples:
   # Batched mode with 1s collection window
   blim subscribe %s --service ff30 --char ff31,ff32 --mode batched --rate 1s
 
-%s`, exampleDeviceAddress, exampleDeviceAddress, exampleDeviceAddress, exampleDeviceAddress, exampleDeviceAddress, deviceAddressNote),
+  # Latest temperature and humidity every 5s, whatever the sensor's notification rate
+  blim subscribe %s 2a6e,2a6f --aggregate-window 5s --decode
+
+  # Record notifications to a binary log for offline replay
+  blim subscribe %s 2a37 --record hr.blimrec
+
+  # Print decoded values for characteristics with a registered parser (hex otherwise)
+  blim subscribe %s 2a37 --decode
+
+  # Expose decoded values and notification counters as Prometheus metrics on :9100/metrics
+  blim subscribe %s 2a37 --metrics-addr :9100
+
+  # Stream notifications as JSON messages to WebSocket clients at ws://localhost:8080/ws
+  blim subscribe %s 2a37 --ws-addr :8080
+
+  # Publish notifications as JSON to <prefix>/<uuid> on an MQTT broker
+  blim subscribe %s 2a37 --mqtt-broker tcp://localhost:1883 --mqtt-topic-prefix home/hr
+
+%s`, exampleDeviceAddress, exampleDeviceAddress, exampleDeviceAddress, exampleDeviceAddress, exampleDeviceAddress, exampleDeviceAddress, exampleDeviceAddress, exampleDeviceAddress, exampleDeviceAddress, exampleDeviceAddress, exampleDeviceAddress, deviceAddressNote),
 	Args: cobra.RangeArgs(1, 2),
 	RunE: runSubscribe,
 }
 
 var (
-	subscribeServiceUUID string
-	subscribeCharUUIDs   string // comma-separated
-	subscribeHex         bool
-	subscribeTimeout     time.Duration
-	subscribeMode        string
-	subscribeRate        time.Duration
-	subscribeIndicate    bool
+	subscribeServiceUUID  string
+	subscribeCharUUIDs    string // comma-separated
+	subscribeHex          bool
+	subscribeTimeout      time.Duration
+	subscribeMode         string
+	subscribeRate         time.Duration
+	subscribeWindow       time.Duration
+	subscribeEmitEmpty    bool
+	subscribeIndicate     bool
+	subscribeRecord       string
+	subscribeDecode       bool
+	subscribeMetricsAddr  string
+	subscribeWSAddr       string
+	subscribeMQTT         mqttOptions
+	subscribeTemplateText string
+	subscribeTemplate     *template.Template // Parsed --output-template, nil when not set
 )
 
 func init() {
@@ -65,7 +97,20 @@ func init() {
 	subscribeCmd.Flags().DurationVar(&subscribeTimeout, "timeout", 30*time.Second, "Connection timeout")
 	subscribeCmd.Flags().StringVar(&subscribeMode, "mode", "live", "Stream mode: live, batched, or latest")
 	subscribeCmd.Flags().DurationVar(&subscribeRate, "rate", 1*time.Second, "Rate limit interval for batched/latest modes")
+	subscribeCmd.Flags().DurationVar(&subscribeWindow, "aggregate-window", 0, "Flush the latest value per characteristic every fixed window, independent of --rate (implies --mode latest)")
+	subscribeCmd.Flags().BoolVar(&subscribeEmitEmpty, "emit-empty", false, "With --aggregate-window, print \"-\" for windows without notifications")
 	subscribeCmd.Flags().BoolVar(&subscribeIndicate, "indicate", false, "Use indications instead of notifications")
+	subscribeCmd.Flags().BoolVar(&subscribeDecode, "decode", false, "Print parsed values for characteristics with a registered parser (JSON for structured values); hex otherwise")
+	subscribeCmd.Flags().StringVar(&subscribeTemplateText, "output-template", "", "Render each notification with a Go template, e.g. '{{.UUID}} {{.Decoded.bpm}}' (fields: UUID, Hex, Ascii, Raw, Decoded, Timestamp)")
+	subscribeCmd.Flags().StringVar(&subscribeRecord, "record", "", "Record received notifications to a timestamped binary log file (live mode keeps per-notification timing)")
+	subscribeCmd.Flags().StringVar(&subscribeMetricsAddr, "metrics-addr", "", "Serve Prometheus metrics at /metrics on this address (e.g., :9100)")
+	subscribeCmd.Flags().StringVar(&subscribeWSAddr, "ws-addr", "", "Broadcast notifications as JSON to WebSocket clients at /ws on this address (e.g., :8080)")
+	subscribeCmd.Flags().StringVar(&subscribeMQTT.Broker, "mqtt-broker", "", "Publish notifications to this MQTT broker (host:port, tcp:// or tls://)")
+	subscribeCmd.Flags().StringVar(&subscribeMQTT.TopicPrefix, "mqtt-topic-prefix", "blim", "MQTT topic prefix; messages go to <prefix>/<characteristic-uuid>")
+	subscribeCmd.Flags().StringVar(&subscribeMQTT.ClientID, "mqtt-client-id", "", "MQTT client identifier (generated if empty)")
+	subscribeCmd.Flags().StringVar(&subscribeMQTT.Username, "mqtt-username", "", "MQTT username")
+	subscribeCmd.Flags().StringVar(&subscribeMQTT.Password, "mqtt-password", "", "MQTT password (requires --mqtt-username; visible in the process list, prefer --mqtt-password-file or $"+mqttPasswordEnv+")")
+	subscribeCmd.Flags().StringVar(&subscribeMQTT.PasswordFile, "mqtt-password-file", "", "Read the MQTT password from this file (requires --mqtt-username)")
 }
 
 // parseStreamMode converts CLI mode string to device.StreamMode
@@ -126,6 +171,24 @@ func runSubscribe(cmd *cobra.Command, args []string) error {
 		return err
 	}
 
+	var window *device.AggregateWindow
+	if subscribeWindow != 0 {
+		if subscribeWindow < 0 {
+			return fmt.Errorf("invalid --aggregate-window %v: must be positive", subscribeWindow)
+		}
+		if !cmd.Flags().Changed("mode") {
+			streamMode = device.StreamAggregated
+		} else if streamMode != device.StreamAggregated {
+			return fmt.Errorf("--aggregate-window requires --mode latest")
+		}
+		if cmd.Flags().Changed("rate") {
+			return fmt.Errorf("--aggregate-window cannot be used with --rate")
+		}
+		window = &device.AggregateWindow{Interval: subscribeWindow, EmitEmpty: subscribeEmitEmpty}
+	} else if subscribeEmitEmpty {
+		return fmt.Errorf("--emit-empty requires --aggregate-window")
+	}
+
 	// Determine characteristics to subscribe (raw CSV string for later parsing)
 	var charUUIDsCSV string
 	if len(args) == 2 {
@@ -140,6 +203,15 @@ func runSubscribe(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("specify characteristic UUID(s) via argument or --char flag, or use --service for all characteristics")
 	}
 
+	subscribeTemplate = nil
+	if subscribeTemplateText != "" {
+		tmpl, err := parseOutputTemplate(subscribeTemplateText)
+		if err != nil {
+			return err
+		}
+		subscribeTemplate = tmpl
+	}
+
 	// Configure logger
 	logger, err := configureLogger(cmd, "verbose")
 	if err != nil {
@@ -149,17 +221,68 @@ func runSubscribe(cmd *cobra.Command, args []string) error {
 	// All arguments validated - don't show usage on runtime errors
 	cmd.SilenceUsage = true
 
-	// Setup context with signal handling
-	ctx, cancel := context.WithCancel(context.Background())
-	defer cancel()
+	// Open the notification log if recording was requested
+	var recorder *device.NotificationLogWriter
+	if subscribeRecord != "" {
+		f, err := os.Create(subscribeRecord)
+		if err != nil {
+			return fmt.Errorf("failed to create record file: %w", err)
+		}
+		defer func() {
+			if err := f.Close(); err != nil {
+				logger.WithError(err).Error("Failed to close notification log")
+			}
+		}()
 
-	sigChan := make(chan os.Signal, 1)
-	signal.Notify(sigChan, os.Interrupt, syscall.SIGTERM)
-	defer signal.Stop(sigChan)
-	go func() {
-		<-sigChan
-		cancel()
-	}()
+		recorder, err = device.NewNotificationLogWriter(f)
+		if err != nil {
+			return err
+		}
+		defer func() {
+			if err := recorder.Flush(); err != nil {
+				logger.WithError(err).Error("Failed to flush notification log")
+			}
+		}()
+	}
+
+	// Start the metrics endpoint if requested
+	var metrics *subscribeMetrics
+	if subscribeMetricsAddr != "" {
+		metrics = newSubscribeMetrics()
+		shutdown, err := startMetricsServer(subscribeMetricsAddr, metrics, logger)
+		if err != nil {
+			return err
+		}
+		defer shutdown()
+	}
+
+	// Start the WebSocket endpoint if requested
+	var broadcaster *wsBroadcaster
+	if subscribeWSAddr != "" {
+		broadcaster = newWSBroadcaster(logger)
+		shutdown, err := startWebSocketServer(subscribeWSAddr, broadcaster, logger)
+		if err != nil {
+			return err
+		}
+		defer shutdown()
+	}
+
+	// Start publishing to MQTT if requested
+	var mqttPub *mqttPublisher
+	if subscribeMQTT.Broker != "" {
+		if err := resolveMQTTPassword(&subscribeMQTT); err != nil {
+			return err
+		}
+		pub, shutdown, err := startMQTTPublisher(subscribeMQTT, logger)
+		if err != nil {
+			return err
+		}
+		mqttPub = pub
+		defer shutdown()
+	}
+
+	// Command context is canceled on SIGINT/SIGTERM
+	ctx := commandContext(cmd)
 
 	// Setup progress (detailed description comes after resolution)
 	progress := NewProgressPrinter(fmt.Sprintf("Subscribing to %s", address), "Connecting", "Subscribed")
@@ -170,6 +293,11 @@ func runSubscribe(cmd *cobra.Command, args []string) error {
 	opts := &inspector.InspectOptions{
 		ConnectTimeout:        subscribeTimeout,
 		DescriptorReadTimeout: 2 * time.Second,
+		ConnectRetries:        connectRetries,
+		ConnectRetryBackoff:   connectRetryBackoff,
+		ByIdentifier:          connectByIdentifier,
+		GATTCacheDir:          gattCacheDir(),
+		DiscoveryTimeout:      discoveryTimeout,
 	}
 
 	// Track if we're subscribing to multiple characteristics (for output formatting)
@@ -177,6 +305,9 @@ func runSubscribe(cmd *cobra.Command, args []string) error {
 
 	// Define the subscribe operation
 	subscribeOperation := func(dev device.Device) (any, error) {
+		// Disconnect on exit even if the operation does not return after an interrupt
+		defer registerTeardown("disconnect "+address, disconnectTeardown(dev))()
+
 		conn := dev.GetConnection()
 		if conn == nil {
 			return nil, fmt.Errorf("device not connected")
@@ -198,19 +329,23 @@ func runSubscribe(cmd *cobra.Command, args []string) error {
 			// Single service
 			for svcUUID, chars := range serviceChars {
 				if len(chars) == 1 {
-					fmt.Fprintf(os.Stderr, "Subscribed to %s. Press Ctrl+C to stop...\n", chars[0])
+					printInfof(os.Stderr, "Subscribed to %s. Press Ctrl+C to stop...\n", chars[0])
 				} else {
-					fmt.Fprintf(os.Stderr, "Subscribed to %d characteristics in service %s. Press Ctrl+C to stop...\n", len(chars), svcUUID)
+					printInfof(os.Stderr, "Subscribed to %d characteristics in service %s. Press Ctrl+C to stop...\n", len(chars), svcUUID)
 				}
 			}
 		} else {
 			// Multiple services
-			fmt.Fprintf(os.Stderr, "Subscribed to %d characteristics across %d services. Press Ctrl+C to stop...\n", totalChars, len(serviceChars))
+			printInfof(os.Stderr, "Subscribed to %d characteristics across %d services. Press Ctrl+C to stop...\n", totalChars, len(serviceChars))
 		}
 
 		// Build SubscribeOptions for each service
 		var subscribeOpts []*device.SubscribeOptions
+		charServices := make(map[string]string)
 		for svcUUID, chars := range serviceChars {
+			for _, charUUID := range chars {
+				charServices[charUUID] = svcUUID
+			}
 			subscribeOpts = append(subscribeOpts, &device.SubscribeOptions{
 				Service:         svcUUID,
 				Characteristics: chars,
@@ -228,8 +363,20 @@ func runSubscribe(cmd *cobra.Command, args []string) error {
 		err = conn.Subscribe(
 			subscribeOpts,
 			streamMode,
-			rate,
+			device.StreamOptions{MaxRate: rate, Window: window},
 			func(record *device.Record) {
+				if recorder != nil {
+					recordSubscribeRecord(recorder, record, charServices, logger)
+				}
+				if metrics != nil {
+					metrics.observeRecord(record)
+				}
+				if broadcaster != nil {
+					broadcaster.broadcastRecord(record)
+				}
+				if mqttPub != nil {
+					mqttPub.publishRecord(record)
+				}
 				outputSubscribeRecord(record, multiChar)
 			},
 		)
@@ -257,12 +404,21 @@ func runSubscribe(cmd *cobra.Command, args []string) error {
 // Keys are sorted for deterministic output order.
 func outputSubscribeRecord(record *device.Record, multiChar bool) {
 	printValue := func(charUUID string, data []byte) {
+		if subscribeTemplate != nil {
+			if err := renderOutputTemplate(os.Stdout, subscribeTemplate, newTemplateValue(time.UnixMicro(record.TsUs), charUUID, data)); err != nil {
+				fmt.Fprintf(os.Stderr, "error: %v\n", err)
+			}
+			return
+		}
+
 		var prefix string
 		if multiChar {
 			prefix = device.ShortenUUID(charUUID) + ": "
 		}
 
-		if subscribeHex {
+		if subscribeDecode {
+			fmt.Printf("%s%s\n", prefix, formatDecodedValue(charUUID, data))
+		} else if subscribeHex {
 			fmt.Printf("%s%s\n", prefix, hex.EncodeToString(data))
 		} else {
 			if prefix != "" {
@@ -289,6 +445,12 @@ func outputSubscribeRecord(record *device.Record, multiChar bool) {
 		return
 	}
 
+	// Empty aggregation window (--emit-empty)
+	if len(record.Values) == 0 {
+		fmt.Println("-")
+		return
+	}
+
 	// Handle live/latest mode (Values)
 	charUUIDs := make([]string, 0, len(record.Values))
 	for k := range record.Values {
@@ -301,6 +463,109 @@ func outputSubscribeRecord(record *device.Record, multiChar bool) {
 	}
 }
 
+// formatDecodedValue renders a notification value using the characteristic's registered parser.
+// Strings are printed as-is and structured values as JSON. Falls back to hex when no parser is
+// registered, parsing fails, or the value is not recognized.
+func formatDecodedValue(charUUID string, data []byte) string {
+	if !device.IsParsableCharacteristic(charUUID) {
+		return hex.EncodeToString(data)
+	}
+
+	parsed, err := device.ParseCharacteristicValue(charUUID, data)
+	if err != nil || parsed == nil {
+		return hex.EncodeToString(data)
+	}
+
+	if s, ok := parsed.(string); ok {
+		return s
+	}
+
+	encoded, err := json.Marshal(parsed)
+	if err != nil {
+		return hex.EncodeToString(data)
+	}
+	return string(encoded)
+}
+
+// notificationMessage is the JSON form of one notification value, shared by the WebSocket and MQTT outputs
+type notificationMessage struct {
+	Timestamp string      `json:"timestamp"`
+	UUID      string      `json:"uuid"`
+	Hex       string      `json:"hex"`
+	Decoded   interface{} `json:"decoded,omitempty"` // Parsed value, only for characteristics with a registered parser
+}
+
+// newNotificationMessage builds the message for one value, decoding it when a parser is registered
+func newNotificationMessage(tsUs int64, charUUID string, data []byte) *notificationMessage {
+	msg := &notificationMessage{
+		Timestamp: time.UnixMicro(tsUs).UTC().Format(time.RFC3339Nano),
+		UUID:      device.ShortenUUID(charUUID),
+		Hex:       hex.EncodeToString(data),
+	}
+	if device.IsParsableCharacteristic(charUUID) {
+		if parsed, err := device.ParseCharacteristicValue(charUUID, data); err == nil && parsed != nil {
+			msg.Decoded = parsed
+		}
+	}
+	return msg
+}
+
+// forEachRecordValue calls fn for every value of a subscription record, in characteristic order
+// (batched values of a characteristic in arrival order)
+func forEachRecordValue(record *device.Record, fn func(charUUID string, data []byte)) {
+	if record.BatchValues != nil {
+		charUUIDs := make([]string, 0, len(record.BatchValues))
+		for charUUID := range record.BatchValues {
+			charUUIDs = append(charUUIDs, charUUID)
+		}
+		sort.Strings(charUUIDs)
+		for _, charUUID := range charUUIDs {
+			for _, data := range record.BatchValues[charUUID] {
+				fn(charUUID, data)
+			}
+		}
+		return
+	}
+
+	charUUIDs := make([]string, 0, len(record.Values))
+	for charUUID := range record.Values {
+		charUUIDs = append(charUUIDs, charUUID)
+	}
+	sort.Strings(charUUIDs)
+	for _, charUUID := range charUUIDs {
+		fn(charUUID, record.Values[charUUID])
+	}
+}
+
+// recordSubscribeRecord appends every value of a subscription record to the notification log.
+// All values of a record share the record timestamp.
+func recordSubscribeRecord(recorder *device.NotificationLogWriter, record *device.Record, charServices map[string]string, logger *logrus.Logger) {
+	write := func(charUUID string, data []byte) {
+		err := recorder.Write(&device.NotificationLogEntry{
+			TsUs:           record.TsUs,
+			Service:        charServices[charUUID],
+			Characteristic: charUUID,
+			Data:           data,
+		})
+		if err != nil {
+			logger.WithError(err).WithField("char", charUUID).Warn("Failed to record notification")
+		}
+	}
+
+	if record.BatchValues != nil {
+		for charUUID, values := range record.BatchValues {
+			for _, data := range values {
+				write(charUUID, data)
+			}
+		}
+		return
+	}
+
+	for charUUID, data := range record.Values {
+		write(charUUID, data)
+	}
+}
+
 // supportsNotifications checks if a characteristic supports notifications or indications
 func supportsNotifications(char device.Characteristic) bool {
 	props := char.GetProperties()
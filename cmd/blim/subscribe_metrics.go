@@ -0,0 +1,182 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/sirupsen/logrus"
+	"github.com/srg/blim/internal/device"
+)
+
+// metricKey identifies one numeric field of a decoded characteristic value
+type metricKey struct {
+	char  string
+	field string
+}
+
+// subscribeMetrics aggregates subscription notifications into Prometheus metrics:
+// notification and parse error counters per characteristic, and one gauge per numeric field of decoded values.
+type subscribeMetrics struct {
+	mu            sync.Mutex
+	notifications map[string]uint64
+	errors        map[string]uint64
+	values        map[metricKey]float64
+}
+
+func newSubscribeMetrics() *subscribeMetrics {
+	return &subscribeMetrics{
+		notifications: make(map[string]uint64),
+		errors:        make(map[string]uint64),
+		values:        make(map[metricKey]float64),
+	}
+}
+
+// observeRecord accounts every value of a subscription record
+func (m *subscribeMetrics) observeRecord(record *device.Record) {
+	forEachRecordValue(record, m.observe)
+}
+
+// observe counts a notification and, if the characteristic has a registered parser, updates the gauges
+// of the decoded numeric fields. Values that fail to parse are counted as errors.
+func (m *subscribeMetrics) observe(charUUID string, data []byte) {
+	var fields map[string]float64
+	var parseErr bool
+	if device.IsParsableCharacteristic(charUUID) {
+		parsed, err := device.ParseCharacteristicValue(charUUID, data)
+		if err != nil {
+			parseErr = true
+		} else if parsed != nil {
+			fields = numericFields(parsed)
+		}
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.notifications[charUUID]++
+	if parseErr {
+		m.errors[charUUID]++
+	}
+	for field, value := range fields {
+		m.values[metricKey{char: charUUID, field: field}] = value
+	}
+}
+
+// numericFields flattens a parsed value into its numeric fields, keyed by the snake_case path of JSON
+// field names ("value" for a bare number). Booleans map to 0/1; strings and nulls are skipped.
+func numericFields(parsed interface{}) map[string]float64 {
+	encoded, err := json.Marshal(parsed)
+	if err != nil {
+		return nil
+	}
+	var generic interface{}
+	if err := json.Unmarshal(encoded, &generic); err != nil {
+		return nil
+	}
+
+	fields := make(map[string]float64)
+	var walk func(path string, v interface{})
+	walk = func(path string, v interface{}) {
+		switch val := v.(type) {
+		case float64:
+			fields[path] = val
+		case bool:
+			if val {
+				fields[path] = 1
+			} else {
+				fields[path] = 0
+			}
+		case map[string]interface{}:
+			for name, child := range val {
+				childPath := metricFieldName(name)
+				if path != "value" {
+					childPath = path + "_" + childPath
+				}
+				walk(childPath, child)
+			}
+		}
+	}
+	walk("value", generic)
+	return fields
+}
+
+// metricFieldName converts a JSON field name into a snake_case label value ("UTCTime" → "utc_time")
+func metricFieldName(name string) string {
+	isUpper := func(c byte) bool { return c >= 'A' && c <= 'Z' }
+	isLower := func(c byte) bool { return c >= 'a' && c <= 'z' || c >= '0' && c <= '9' }
+
+	var b strings.Builder
+	for i := 0; i < len(name); i++ {
+		c := name[i]
+		switch {
+		case isUpper(c):
+			// Word boundary: after a lowercase letter, or at the last capital of an acronym ("UTCTime")
+			if i > 0 && (isLower(name[i-1]) || isUpper(name[i-1]) && i+1 < len(name) && isLower(name[i+1])) {
+				b.WriteByte('_')
+			}
+			b.WriteByte(c + ('a' - 'A'))
+		case isLower(c):
+			b.WriteByte(c)
+		default:
+			b.WriteByte('_')
+		}
+	}
+	return b.String()
+}
+
+// writeTo renders the metrics in the Prometheus text exposition format, sorted for stable output
+func (m *subscribeMetrics) writeTo(w io.Writer) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	var b strings.Builder
+	writeCounter := func(name, help string, counts map[string]uint64) {
+		fmt.Fprintf(&b, "# HELP %s %s\n# TYPE %s counter\n", name, help, name)
+		chars := make([]string, 0, len(counts))
+		for charUUID := range counts {
+			chars = append(chars, charUUID)
+		}
+		sort.Strings(chars)
+		for _, charUUID := range chars {
+			fmt.Fprintf(&b, "%s{characteristic=%q} %d\n", name, device.ShortenUUID(charUUID), counts[charUUID])
+		}
+	}
+	writeCounter("blim_notifications_total", "Notifications received per characteristic.", m.notifications)
+	writeCounter("blim_notification_errors_total", "Notifications whose value failed to parse.", m.errors)
+
+	keys := make([]metricKey, 0, len(m.values))
+	for key := range m.values {
+		keys = append(keys, key)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if keys[i].char != keys[j].char {
+			return keys[i].char < keys[j].char
+		}
+		return keys[i].field < keys[j].field
+	})
+	b.WriteString("# HELP blim_characteristic_value Latest decoded numeric field of a characteristic value.\n")
+	b.WriteString("# TYPE blim_characteristic_value gauge\n")
+	for _, key := range keys {
+		fmt.Fprintf(&b, "blim_characteristic_value{characteristic=%q,field=%q} %g\n",
+			device.ShortenUUID(key.char), key.field, m.values[key])
+	}
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// ServeHTTP serves the /metrics endpoint
+func (m *subscribeMetrics) ServeHTTP(w http.ResponseWriter, _ *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+	_ = m.writeTo(w)
+}
+
+// startMetricsServer exposes metrics on addr at /metrics; the returned function shuts the server down
+func startMetricsServer(addr string, metrics *subscribeMetrics, logger *logrus.Logger) (func(), error) {
+	return startHTTPEndpoint("metrics", addr, "/metrics", metrics, logger)
+}
@@ -0,0 +1,76 @@
+//go:build test
+
+package main
+
+import (
+	"io"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+	"github.com/srg/blim/internal/device"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSubscribeMetrics(t *testing.T) {
+	// GOAL: Verify notifications are exported as Prometheus counters and decoded numeric fields as gauges
+	//
+	// TEST SCENARIO: Observe parsable, unparsable and raw notifications → render /metrics → counters and gauges present
+
+	metrics := newSubscribeMetrics()
+	metrics.observeRecord(&device.Record{Values: map[string][]byte{
+		"2a08": {0xEA, 0x07, 0x0A, 0x10, 0x0C, 0x1E, 0x2D}, // Date Time
+		"ff01": {0x01, 0x02},
+	}})
+	metrics.observeRecord(&device.Record{BatchValues: map[string][][]byte{
+		"2a08": {{0xEA, 0x07}}, // Too short: parse error
+		"ff01": {{0x03}, {0x04}},
+	}})
+
+	recorder := httptest.NewRecorder()
+	metrics.ServeHTTP(recorder, httptest.NewRequest("GET", "/metrics", nil))
+	body, err := io.ReadAll(recorder.Body)
+	require.NoError(t, err)
+	output := string(body)
+
+	assert.True(t, strings.HasPrefix(recorder.Header().Get("Content-Type"), "text/plain"), "metrics MUST use the text exposition format")
+	assert.Contains(t, output, "# TYPE blim_notifications_total counter")
+	assert.Contains(t, output, `blim_notifications_total{characteristic="2a08"} 2`)
+	assert.Contains(t, output, `blim_notifications_total{characteristic="ff01"} 3`, "every batched value MUST be counted")
+	assert.Contains(t, output, `blim_notification_errors_total{characteristic="2a08"} 1`)
+	assert.NotContains(t, output, `blim_notification_errors_total{characteristic="ff01"}`, "values without parser MUST NOT count as errors")
+
+	assert.Contains(t, output, "# TYPE blim_characteristic_value gauge")
+	assert.Contains(t, output, `blim_characteristic_value{characteristic="2a08",field="year"} 2026`)
+	assert.NotContains(t, output, `characteristic_value{characteristic="ff01"`, "values without parser MUST NOT produce gauges")
+}
+
+func TestNumericFields(t *testing.T) {
+	// GOAL: Verify parsed values are flattened into snake_case numeric fields
+	//
+	// TEST SCENARIO: Bare number, nested struct with bools and strings → numeric paths only
+
+	assert.Equal(t, map[string]float64{"value": 42}, numericFields(42))
+
+	type nested struct {
+		UTCTime int
+		Label   string
+	}
+	fields := numericFields(struct {
+		HeartRate int
+		Contact   bool
+		Inner     nested
+	}{HeartRate: 72, Contact: true, Inner: nested{UTCTime: 5, Label: "x"}})
+	assert.Equal(t, map[string]float64{"heart_rate": 72, "contact": 1, "inner_utc_time": 5}, fields)
+}
+
+func TestStartMetricsServer_InvalidAddress(t *testing.T) {
+	// GOAL: Verify an unusable metrics address is reported before subscribing
+	//
+	// TEST SCENARIO: Start server on malformed address → error returned
+
+	_, err := startMetricsServer("not-an-address", newSubscribeMetrics(), logrus.New())
+	assert.ErrorContains(t, err, "metrics address")
+}
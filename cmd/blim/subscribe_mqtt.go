@@ -0,0 +1,233 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"net"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	mqtt "github.com/eclipse/paho.mqtt.golang"
+	"github.com/sirupsen/logrus"
+	"github.com/srg/blim/internal/device"
+)
+
+// MQTT publisher settings
+const (
+	mqttQueueSize         = 1024             // Messages buffered while the broker is unreachable; newer messages are dropped when full
+	mqttKeepAlive         = 30 * time.Second // Keep-alive announced in CONNECT
+	mqttDialTimeout       = 5 * time.Second  // Connect timeout, also bounds a single publish
+	mqttMinReconnectDelay = 1 * time.Second
+	mqttMaxReconnectDelay = 30 * time.Second
+	mqttDisconnectQuiesce = 250                  // Milliseconds allowed for in-flight work when disconnecting
+	mqttPasswordEnv       = "BLIM_MQTT_PASSWORD" // Password source when neither --mqtt-password nor --mqtt-password-file is given
+)
+
+// mqttOptions configures the MQTT publisher
+type mqttOptions struct {
+	Broker       string // host:port, optionally prefixed with tcp:// or tls:// (ssl:// and mqtts:// are aliases of tls://)
+	TopicPrefix  string // Messages are published to <TopicPrefix>/<characteristic-uuid>
+	ClientID     string
+	Username     string
+	Password     string
+	PasswordFile string // Read the password from this file instead of the command line
+}
+
+// resolveMQTTPassword fills in the password from --mqtt-password-file or $BLIM_MQTT_PASSWORD when it isn't given
+// on the command line, and checks that a password comes with a username
+func resolveMQTTPassword(opts *mqttOptions) error {
+	if opts.PasswordFile != "" {
+		if opts.Password != "" {
+			return fmt.Errorf("--mqtt-password and --mqtt-password-file are mutually exclusive")
+		}
+		data, err := os.ReadFile(opts.PasswordFile)
+		if err != nil {
+			return fmt.Errorf("failed to read MQTT password: %w", err)
+		}
+		opts.Password = strings.TrimRight(string(data), "\r\n")
+	} else if opts.Password == "" && opts.Username != "" {
+		opts.Password = os.Getenv(mqttPasswordEnv)
+	}
+
+	if opts.Password != "" && opts.Username == "" {
+		return fmt.Errorf("--mqtt-password requires --mqtt-username")
+	}
+	return nil
+}
+
+// mqttMessage is one queued PUBLISH
+type mqttMessage struct {
+	topic   string
+	payload []byte
+}
+
+// mqttPublisher publishes subscription notifications to an MQTT broker with QoS 0. The broker connection is
+// maintained by the MQTT client and re-established with exponential backoff, independently of the BLE link;
+// messages produced while disconnected are queued up to mqttQueueSize.
+type mqttPublisher struct {
+	opts      mqttOptions
+	logger    *logrus.Logger
+	client    mqtt.Client
+	queue     chan mqttMessage
+	connected chan struct{} // Signalled by the client on every (re)connection
+	stop      chan struct{}
+	done      chan struct{}
+
+	dropMu  sync.Mutex
+	dropped uint64 // Messages dropped because the queue was full
+}
+
+// startMQTTPublisher starts connecting to the broker; the returned function disconnects from it
+func startMQTTPublisher(opts mqttOptions, logger *logrus.Logger) (*mqttPublisher, func(), error) {
+	network, addr, err := parseMQTTBroker(opts.Broker)
+	if err != nil {
+		return nil, nil, err
+	}
+	if opts.ClientID == "" {
+		opts.ClientID = fmt.Sprintf("blim-%d", os.Getpid())
+	}
+	opts.TopicPrefix = strings.TrimSuffix(opts.TopicPrefix, "/")
+
+	p := &mqttPublisher{
+		opts:      opts,
+		logger:    logger,
+		queue:     make(chan mqttMessage, mqttQueueSize),
+		connected: make(chan struct{}, 1),
+		stop:      make(chan struct{}),
+		done:      make(chan struct{}),
+	}
+
+	clientOpts := mqtt.NewClientOptions().
+		AddBroker(network + "://" + addr).
+		SetClientID(opts.ClientID).
+		SetUsername(opts.Username).
+		SetPassword(opts.Password).
+		SetCleanSession(true).
+		SetKeepAlive(mqttKeepAlive).
+		SetConnectTimeout(mqttDialTimeout).
+		SetWriteTimeout(mqttDialTimeout).
+		SetConnectRetry(true).
+		SetConnectRetryInterval(mqttMinReconnectDelay).
+		SetAutoReconnect(true).
+		SetMaxReconnectInterval(mqttMaxReconnectDelay).
+		SetOnConnectHandler(func(mqtt.Client) {
+			logger.WithField("broker", addr).Info("Connected to MQTT broker")
+			select {
+			case p.connected <- struct{}{}:
+			default:
+			}
+		}).
+		SetConnectionLostHandler(func(_ mqtt.Client, err error) {
+			logger.WithError(err).WithField("broker", addr).Warn("MQTT broker connection lost, reconnecting")
+		})
+	p.client = mqtt.NewClient(clientOpts)
+
+	// With ConnectRetry the token completes once connected, so failures surface through the retry loop
+	p.client.Connect()
+	go p.run()
+
+	var once sync.Once
+	return p, func() {
+		once.Do(func() {
+			close(p.stop)
+			<-p.done
+			p.client.Disconnect(mqttDisconnectQuiesce)
+		})
+	}, nil
+}
+
+// parseMQTTBroker splits a broker URL into "tcp" or "tls" and host:port (default port 1883, 8883 for TLS)
+func parseMQTTBroker(broker string) (network, addr string, err error) {
+	network, addr = "tcp", broker
+	if scheme, rest, ok := strings.Cut(broker, "://"); ok {
+		switch strings.ToLower(scheme) {
+		case "tcp", "mqtt":
+		case "tls", "ssl", "mqtts":
+			network = "tls"
+		default:
+			return "", "", fmt.Errorf("unsupported MQTT broker scheme %q: use tcp:// or tls://", scheme)
+		}
+		addr = rest
+	}
+	if addr == "" {
+		return "", "", fmt.Errorf("MQTT broker address is empty")
+	}
+	if _, _, splitErr := net.SplitHostPort(addr); splitErr != nil {
+		port := "1883"
+		if network == "tls" {
+			port = "8883"
+		}
+		addr = net.JoinHostPort(addr, port)
+	}
+	return network, addr, nil
+}
+
+// publishRecord queues every value of a subscription record as a JSON message on <prefix>/<uuid>
+func (p *mqttPublisher) publishRecord(record *device.Record) {
+	forEachRecordValue(record, func(charUUID string, data []byte) {
+		msg := newNotificationMessage(record.TsUs, charUUID, data)
+		payload, err := json.Marshal(msg)
+		if err != nil {
+			p.logger.WithError(err).WithField("char", msg.UUID).Warn("Failed to encode MQTT message")
+			return
+		}
+
+		select {
+		case p.queue <- mqttMessage{topic: p.opts.TopicPrefix + "/" + msg.UUID, payload: payload}:
+		default:
+			p.dropMu.Lock()
+			p.dropped++
+			dropped := p.dropped
+			p.dropMu.Unlock()
+			p.logger.WithField("dropped", dropped).Debug("MQTT queue full, message dropped")
+		}
+	})
+}
+
+// run publishes queued messages until stopped; on stop, messages still queued are flushed if connected
+func (p *mqttPublisher) run() {
+	defer close(p.done)
+	for {
+		select {
+		case <-p.stop:
+			for {
+				select {
+				case msg := <-p.queue:
+					if !p.client.IsConnectionOpen() {
+						return
+					}
+					p.publish(msg)
+				default:
+					return
+				}
+			}
+		case msg := <-p.queue:
+			if !p.waitConnected() {
+				return
+			}
+			p.publish(msg)
+		}
+	}
+}
+
+// waitConnected blocks until the broker connection is open; returns false if the publisher is stopped first
+func (p *mqttPublisher) waitConnected() bool {
+	for !p.client.IsConnectionOpen() {
+		select {
+		case <-p.connected:
+		case <-p.stop:
+			return false
+		}
+	}
+	return true
+}
+
+// publish sends one message with QoS 0; a message lost with the connection is not retried
+func (p *mqttPublisher) publish(msg mqttMessage) {
+	token := p.client.Publish(msg.topic, 0, false, msg.payload)
+	if token.WaitTimeout(mqttDialTimeout) && token.Error() != nil {
+		p.logger.WithError(token.Error()).WithField("topic", msg.topic).Debug("MQTT publish failed")
+	}
+}
@@ -0,0 +1,173 @@
+//go:build test
+
+package main
+
+import (
+	"bufio"
+	"encoding/binary"
+	"encoding/json"
+	"io"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/srg/blim/internal/device"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// MQTT 3.1.1 control packet types (fixed header byte) seen by the fake broker
+const (
+	mqttConnect    = 0x10
+	mqttConnAck    = 0x20
+	mqttPublish    = 0x30
+	mqttDisconnect = 0xE0
+)
+
+// readMQTTPacket reads one control packet on the fake broker side, returning its type and body
+func readMQTTPacket(r *bufio.Reader) (byte, []byte, error) {
+	header, err := r.ReadByte()
+	if err != nil {
+		return 0, nil, err
+	}
+	length, multiplier := 0, 1
+	for {
+		b, err := r.ReadByte()
+		if err != nil {
+			return 0, nil, err
+		}
+		length += int(b&0x7F) * multiplier
+		if b&0x80 == 0 {
+			break
+		}
+		multiplier *= 128
+	}
+	body := make([]byte, length)
+	if _, err := io.ReadFull(r, body); err != nil {
+		return 0, nil, err
+	}
+	return header & 0xF0, body, nil
+}
+
+func TestParseMQTTBroker(t *testing.T) {
+	// GOAL: Verify broker addresses accept optional schemes and default ports
+	//
+	// TEST SCENARIO: Parse plain, tcp://, tls:// and invalid brokers → network and host:port resolved
+
+	tests := []struct {
+		broker  string
+		network string
+		addr    string
+	}{
+		{"localhost", "tcp", "localhost:1883"},
+		{"localhost:1884", "tcp", "localhost:1884"},
+		{"tcp://broker:1883", "tcp", "broker:1883"},
+		{"tls://broker", "tls", "broker:8883"},
+		{"mqtts://broker:9883", "tls", "broker:9883"},
+	}
+	for _, tt := range tests {
+		network, addr, err := parseMQTTBroker(tt.broker)
+		require.NoError(t, err, tt.broker)
+		assert.Equal(t, tt.network, network, tt.broker)
+		assert.Equal(t, tt.addr, addr, tt.broker)
+	}
+
+	_, _, err := parseMQTTBroker("ws://broker")
+	assert.ErrorContains(t, err, "unsupported MQTT broker scheme")
+}
+
+func TestMQTTPublisher(t *testing.T) {
+	// GOAL: Verify notifications are published as JSON to <prefix>/<uuid> after connecting with credentials
+	//
+	// TEST SCENARIO: Fake broker receives CONNECT → record published before CONNACK is queued → CONNACK →
+	// PUBLISH topic and payload checked → DISCONNECT on shutdown
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer listener.Close()
+
+	pub, shutdown, err := startMQTTPublisher(mqttOptions{
+		Broker:      listener.Addr().String(),
+		TopicPrefix: "home/hr/",
+		ClientID:    "test-client",
+		Username:    "user",
+		Password:    "secret",
+	}, logrus.New())
+	require.NoError(t, err)
+	defer shutdown()
+
+	conn, err := listener.Accept()
+	require.NoError(t, err)
+	defer conn.Close()
+	require.NoError(t, conn.SetDeadline(time.Now().Add(5*time.Second)))
+	r := bufio.NewReader(conn)
+
+	packetType, body, err := readMQTTPacket(r)
+	require.NoError(t, err)
+	require.Equal(t, byte(mqttConnect), packetType)
+	assert.Equal(t, "MQTT", string(body[2:6]))
+	assert.Equal(t, byte(0xC2), body[7], "CONNECT MUST flag username, password and clean session")
+	assert.Contains(t, string(body), "test-client", "CONNECT MUST carry the client ID")
+	assert.Contains(t, string(body), "secret", "CONNECT MUST carry the password")
+
+	// Published while the session is not established yet: MUST be queued, not dropped
+	pub.publishRecord(&device.Record{TsUs: 1_700_000_000_000_000, Values: map[string][]byte{"2a19": {75}}})
+	_, err = conn.Write([]byte{mqttConnAck, 2, 0, 0})
+	require.NoError(t, err)
+
+	packetType, body, err = readMQTTPacket(r)
+	require.NoError(t, err)
+	require.Equal(t, byte(mqttPublish), packetType)
+	topicLen := int(binary.BigEndian.Uint16(body))
+	assert.Equal(t, "home/hr/2a19", string(body[2:2+topicLen]), "topic MUST be <prefix>/<uuid>")
+
+	var msg map[string]interface{}
+	require.NoError(t, json.Unmarshal(body[2+topicLen:], &msg))
+	assert.Equal(t, "2a19", msg["uuid"])
+	assert.Equal(t, "4b", msg["hex"])
+	assert.Equal(t, "2023-11-14T22:13:20Z", msg["timestamp"])
+
+	shutdown()
+	packetType, _, err = readMQTTPacket(r)
+	require.NoError(t, err)
+	assert.Equal(t, byte(mqttDisconnect), packetType, "shutdown MUST send DISCONNECT")
+}
+
+func TestResolveMQTTPassword(t *testing.T) {
+	// GOAL: Verify the MQTT password can come from the command line, a file or the environment
+	//
+	// TEST SCENARIO: Password flag kept → file read with trailing newline trimmed → environment used without flags →
+	// flag and file together rejected → password without username rejected → missing file reported
+
+	passwordFile := filepath.Join(t.TempDir(), "password")
+	require.NoError(t, os.WriteFile(passwordFile, []byte("from-file\n"), 0o600))
+	t.Setenv(mqttPasswordEnv, "from-env")
+
+	opts := mqttOptions{Username: "user", Password: "from-flag"}
+	require.NoError(t, resolveMQTTPassword(&opts))
+	assert.Equal(t, "from-flag", opts.Password, "command line password MUST take precedence over the environment")
+
+	opts = mqttOptions{Username: "user", PasswordFile: passwordFile}
+	require.NoError(t, resolveMQTTPassword(&opts))
+	assert.Equal(t, "from-file", opts.Password, "password file MUST be read without the trailing newline")
+
+	opts = mqttOptions{Username: "user"}
+	require.NoError(t, resolveMQTTPassword(&opts))
+	assert.Equal(t, "from-env", opts.Password, "environment MUST supply the password when no flag is given")
+
+	opts = mqttOptions{}
+	require.NoError(t, resolveMQTTPassword(&opts))
+	assert.Empty(t, opts.Password, "environment MUST be ignored without a username")
+
+	err := resolveMQTTPassword(&mqttOptions{Username: "user", Password: "x", PasswordFile: passwordFile})
+	assert.ErrorContains(t, err, "mutually exclusive")
+
+	err = resolveMQTTPassword(&mqttOptions{PasswordFile: passwordFile})
+	assert.ErrorContains(t, err, "--mqtt-password requires --mqtt-username")
+
+	err = resolveMQTTPassword(&mqttOptions{Username: "user", PasswordFile: filepath.Join(t.TempDir(), "missing")})
+	assert.ErrorContains(t, err, "failed to read MQTT password")
+}
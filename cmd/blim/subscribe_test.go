@@ -31,6 +31,7 @@ type SubscribeTestSuite struct {
 		subscribeTimeout     time.Duration
 		subscribeMode        string
 		subscribeRate        time.Duration
+		subscribeDecode      bool
 	}
 }
 
@@ -45,6 +46,7 @@ func (suite *SubscribeTestSuite) SetupSuite() {
 	suite.originalFlags.subscribeTimeout = subscribeTimeout
 	suite.originalFlags.subscribeMode = subscribeMode
 	suite.originalFlags.subscribeRate = subscribeRate
+	suite.originalFlags.subscribeDecode = subscribeDecode
 }
 
 // TearDownSuite runs once after all tests in the suite
@@ -56,6 +58,7 @@ func (suite *SubscribeTestSuite) TearDownSuite() {
 	subscribeTimeout = suite.originalFlags.subscribeTimeout
 	subscribeMode = suite.originalFlags.subscribeMode
 	subscribeRate = suite.originalFlags.subscribeRate
+	subscribeDecode = suite.originalFlags.subscribeDecode
 }
 
 // SetupTest runs before each test in the suite
@@ -75,6 +78,7 @@ func (suite *SubscribeTestSuite) SetupTest() {
 	subscribeTimeout = 5 * time.Second
 	subscribeMode = "live"
 	subscribeRate = 1 * time.Second
+	subscribeDecode = false
 }
 
 func (suite *SubscribeTestSuite) TestParseStreamMode() {
@@ -150,6 +154,7 @@ func (suite *SubscribeTestSuite) TestSubscribeCmd() {
 			{name: "timeout", defaultValue: "30s", descContains: []string{"Connection timeout"}},
 			{name: "mode", defaultValue: "live", descContains: []string{"Stream mode", "live", "batched", "latest"}},
 			{name: "rate", defaultValue: "1s", descContains: []string{"Rate limit", "interval"}},
+			{name: "decode", defaultValue: "false", descContains: []string{"parsed values", "hex otherwise"}},
 		}
 
 		for _, f := range flags {
@@ -193,6 +198,29 @@ func (suite *SubscribeTestSuite) TestSubscribeCmd() {
 	})
 }
 
+func (suite *SubscribeTestSuite) TestFormatDecodedValue() {
+	// GOAL: Verify --decode output uses registered parsers and falls back to hex
+	//
+	// TEST SCENARIO: Parsable characteristic with known value → decoded text; unknown value or no parser → hex
+
+	tests := []struct {
+		name     string
+		charUUID string
+		data     []byte
+		expected string
+	}{
+		{name: "appearance decoded", charUUID: "2a01", data: []byte{0x40, 0x00}, expected: "Phone"},
+		{name: "appearance invalid length falls back to hex", charUUID: "2a01", data: []byte{0x40}, expected: "40"},
+		{name: "no parser falls back to hex", charUUID: "2a37", data: []byte{0x00, 0x4B}, expected: "004b"},
+	}
+
+	for _, tt := range tests {
+		suite.Run(tt.name, func() {
+			suite.Assert().Equal(tt.expected, formatDecodedValue(tt.charUUID, tt.data), "decoded output MUST match")
+		})
+	}
+}
+
 func (suite *SubscribeTestSuite) TestNotificationFlow() {
 	// GOAL: Verify full notification lifecycle for various subscription configurations
 	//
@@ -320,7 +348,7 @@ func (suite *SubscribeTestSuite) TestNotificationFlow() {
 				subscribeErr = conn.Subscribe(
 					tt.subscribeOpts,
 					device.StreamEveryUpdate,
-					0,
+					device.StreamOptions{},
 					func(record *device.Record) {
 						outputSubscribeRecord(record, multiChar)
 						if notificationCount.Add(1) >= expectedCount {
@@ -0,0 +1,178 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/sirupsen/logrus"
+	"github.com/srg/blim/internal/device"
+)
+
+// wsClientQueueSize is the number of messages buffered per WebSocket client; a client that falls
+// further behind misses messages rather than stalling the subscription
+const wsClientQueueSize = 256
+
+// wsReadLimit bounds client messages; clients have nothing to send besides control frames
+const wsReadLimit = 4096
+
+// wsWriteTimeout bounds a single write, so a stalled client can't hold its writer forever
+const wsWriteTimeout = 10 * time.Second
+
+// wsUpgrader accepts any origin: the endpoint is a local, read-only feed meant for dashboards
+// that are typically served from another origin (or opened from a file)
+var wsUpgrader = websocket.Upgrader{
+	CheckOrigin: func(*http.Request) bool { return true },
+}
+
+// wsBroadcaster serves a WebSocket endpoint and broadcasts subscription notifications to every connected client.
+// Client messages are ignored; pings are answered and a close frame ends the connection.
+type wsBroadcaster struct {
+	logger  *logrus.Logger
+	mu      sync.Mutex
+	clients map[*wsClient]struct{}
+	closed  bool
+}
+
+// wsClient is one connected WebSocket client
+type wsClient struct {
+	conn      *websocket.Conn
+	queue     chan []byte
+	closeOnce sync.Once
+}
+
+func newWSBroadcaster(logger *logrus.Logger) *wsBroadcaster {
+	return &wsBroadcaster{logger: logger, clients: make(map[*wsClient]struct{})}
+}
+
+// broadcastRecord sends every value of a subscription record as a separate message
+func (b *wsBroadcaster) broadcastRecord(record *device.Record) {
+	forEachRecordValue(record, func(charUUID string, data []byte) {
+		b.broadcast(newNotificationMessage(record.TsUs, charUUID, data))
+	})
+}
+
+// broadcast queues the message for every client; clients with a full queue miss it
+func (b *wsBroadcaster) broadcast(msg *notificationMessage) {
+	payload, err := json.Marshal(msg)
+	if err != nil {
+		b.logger.WithError(err).WithField("char", msg.UUID).Warn("Failed to encode WebSocket message")
+		return
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	for client := range b.clients {
+		select {
+		case client.queue <- payload:
+		default:
+			b.logger.WithField("char", msg.UUID).Debug("WebSocket client too slow, message dropped")
+		}
+	}
+}
+
+// ServeHTTP upgrades the request to a WebSocket connection and registers the client
+func (b *wsBroadcaster) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if !websocket.IsWebSocketUpgrade(r) {
+		http.Error(w, "WebSocket upgrade required", http.StatusUpgradeRequired)
+		return
+	}
+	b.mu.Lock()
+	closed := b.closed
+	b.mu.Unlock()
+	if closed {
+		http.Error(w, "WebSocket server shutting down", http.StatusServiceUnavailable)
+		return
+	}
+
+	conn, err := wsUpgrader.Upgrade(w, r, nil)
+	if err != nil {
+		// Upgrade has already replied with an HTTP error
+		b.logger.WithError(err).Debug("WebSocket upgrade failed")
+		return
+	}
+	conn.SetReadLimit(wsReadLimit)
+
+	client := &wsClient{conn: conn, queue: make(chan []byte, wsClientQueueSize)}
+	b.mu.Lock()
+	if b.closed { // Closed while upgrading
+		b.mu.Unlock()
+		_ = conn.Close()
+		return
+	}
+	b.clients[client] = struct{}{}
+	b.mu.Unlock()
+	b.logger.WithField("remote", conn.RemoteAddr().String()).Debug("WebSocket client connected")
+
+	go b.writeLoop(client)
+	go b.readLoop(client)
+}
+
+// writeLoop sends queued messages to the client until its queue is closed or a write fails
+func (b *wsBroadcaster) writeLoop(client *wsClient) {
+	defer func() { _ = client.conn.Close() }()
+
+	for payload := range client.queue {
+		_ = client.conn.SetWriteDeadline(time.Now().Add(wsWriteTimeout))
+		if err := client.conn.WriteMessage(websocket.TextMessage, payload); err != nil {
+			b.remove(client)
+			return
+		}
+	}
+	closeMsg := websocket.FormatCloseMessage(websocket.CloseGoingAway, "")
+	_ = client.conn.WriteControl(websocket.CloseMessage, closeMsg, time.Now().Add(wsWriteTimeout))
+}
+
+// readLoop consumes client messages until the client closes or the connection fails.
+// Pings and close frames are answered by the connection's default handlers while reading.
+func (b *wsBroadcaster) readLoop(client *wsClient) {
+	defer b.remove(client)
+	for {
+		if _, _, err := client.conn.NextReader(); err != nil {
+			return
+		}
+	}
+}
+
+// remove unregisters the client and stops its writer
+func (b *wsBroadcaster) remove(client *wsClient) {
+	b.mu.Lock()
+	_, registered := b.clients[client]
+	delete(b.clients, client)
+	b.mu.Unlock()
+
+	if registered {
+		client.closeOnce.Do(func() { close(client.queue) })
+		b.logger.WithField("remote", client.conn.RemoteAddr().String()).Debug("WebSocket client disconnected")
+	}
+}
+
+// close disconnects every client; later upgrade requests are refused
+func (b *wsBroadcaster) close() {
+	b.mu.Lock()
+	b.closed = true
+	clients := make([]*wsClient, 0, len(b.clients))
+	for client := range b.clients {
+		clients = append(clients, client)
+	}
+	b.mu.Unlock()
+
+	for _, client := range clients {
+		b.remove(client)
+	}
+}
+
+// startWebSocketServer broadcasts notifications to WebSocket clients connecting to addr at /ws.
+// The returned function disconnects the clients and shuts the server down.
+func startWebSocketServer(addr string, broadcaster *wsBroadcaster, logger *logrus.Logger) (func(), error) {
+	shutdown, err := startHTTPEndpoint("websocket", addr, "/ws", broadcaster, logger)
+	if err != nil {
+		return nil, err
+	}
+	return func() {
+		broadcaster.close()
+		shutdown()
+	}, nil
+}
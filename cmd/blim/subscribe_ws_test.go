@@ -0,0 +1,128 @@
+//go:build test
+
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/sirupsen/logrus"
+	"github.com/srg/blim/internal/device"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// dialWebSocket connects a WebSocket client to the test server's /ws endpoint
+func dialWebSocket(t *testing.T, serverURL string) *websocket.Conn {
+	conn, resp, err := websocket.DefaultDialer.Dial("ws"+strings.TrimPrefix(serverURL, "http")+"/ws", nil)
+	require.NoError(t, err)
+	require.Equal(t, http.StatusSwitchingProtocols, resp.StatusCode)
+	t.Cleanup(func() { _ = conn.Close() })
+	return conn
+}
+
+// readServerMessage reads one text message sent by the server
+func readServerMessage(t *testing.T, conn *websocket.Conn) []byte {
+	require.NoError(t, conn.SetReadDeadline(time.Now().Add(2*time.Second)))
+	msgType, payload, err := conn.ReadMessage()
+	require.NoError(t, err)
+	require.Equal(t, websocket.TextMessage, msgType, "server MUST send text messages")
+	return payload
+}
+
+// waitForClients waits until the broadcaster has n registered clients
+func waitForClients(t *testing.T, broadcaster *wsBroadcaster, n int) {
+	require.Eventually(t, func() bool {
+		broadcaster.mu.Lock()
+		defer broadcaster.mu.Unlock()
+		return len(broadcaster.clients) == n
+	}, time.Second, 10*time.Millisecond, "%d clients MUST be registered", n)
+}
+
+func TestWebSocketBroadcast(t *testing.T) {
+	// GOAL: Verify notifications are broadcast as JSON messages to every connected WebSocket client
+	//
+	// TEST SCENARIO: Two clients connect → record broadcast → both receive timestamp, uuid, hex and decoded value
+
+	broadcaster := newWSBroadcaster(logrus.New())
+	server := httptest.NewServer(broadcaster)
+	defer server.Close()
+	defer broadcaster.close()
+
+	conn1 := dialWebSocket(t, server.URL)
+	conn2 := dialWebSocket(t, server.URL)
+	waitForClients(t, broadcaster, 2)
+
+	broadcaster.broadcastRecord(&device.Record{TsUs: 1_700_000_000_000_000, Values: map[string][]byte{
+		"2a08": {0xEA, 0x07, 0x0A, 0x10, 0x0C, 0x1E, 0x2D},
+	}})
+
+	for _, conn := range []*websocket.Conn{conn1, conn2} {
+		var msg map[string]interface{}
+		require.NoError(t, json.Unmarshal(readServerMessage(t, conn), &msg))
+		assert.Equal(t, "2023-11-14T22:13:20Z", msg["timestamp"])
+		assert.Equal(t, "2a08", msg["uuid"])
+		assert.Equal(t, "ea070a100c1e2d", msg["hex"])
+		decoded, ok := msg["decoded"].(map[string]interface{})
+		require.True(t, ok, "parsable value MUST include decoded")
+		assert.Equal(t, float64(2026), decoded["Year"])
+	}
+}
+
+func TestWebSocketRejectsPlainHTTP(t *testing.T) {
+	// GOAL: Verify non-upgrade requests are refused
+	//
+	// TEST SCENARIO: Plain GET → 426 Upgrade Required
+
+	recorder := httptest.NewRecorder()
+	newWSBroadcaster(logrus.New()).ServeHTTP(recorder, httptest.NewRequest("GET", "/ws", nil))
+	assert.Equal(t, http.StatusUpgradeRequired, recorder.Code)
+}
+
+func TestWebSocketClientLifecycle(t *testing.T) {
+	// GOAL: Verify pings are answered, disconnecting clients are unregistered and close() ends every connection
+	//
+	// TEST SCENARIO: Client pings → pong received → client closes → unregistered → new client → close() →
+	// client receives a going-away close frame → later upgrades refused
+
+	broadcaster := newWSBroadcaster(logrus.New())
+	server := httptest.NewServer(broadcaster)
+	defer server.Close()
+
+	conn := dialWebSocket(t, server.URL)
+	waitForClients(t, broadcaster, 1)
+
+	pong := make(chan string, 1)
+	conn.SetPongHandler(func(data string) error {
+		pong <- data
+		return nil
+	})
+	require.NoError(t, conn.WriteControl(websocket.PingMessage, []byte("hi"), time.Now().Add(time.Second)))
+	go func() { _, _, _ = conn.ReadMessage() }() // Pong handlers run while reading
+	select {
+	case data := <-pong:
+		assert.Equal(t, "hi", data, "pong MUST echo the ping payload")
+	case <-time.After(2 * time.Second):
+		require.Fail(t, "ping MUST be answered")
+	}
+
+	require.NoError(t, conn.WriteControl(websocket.CloseMessage,
+		websocket.FormatCloseMessage(websocket.CloseNormalClosure, ""), time.Now().Add(time.Second)))
+	waitForClients(t, broadcaster, 0)
+
+	conn = dialWebSocket(t, server.URL)
+	waitForClients(t, broadcaster, 1)
+	broadcaster.close()
+
+	require.NoError(t, conn.SetReadDeadline(time.Now().Add(2*time.Second)))
+	_, _, err := conn.ReadMessage()
+	assert.True(t, websocket.IsCloseError(err, websocket.CloseGoingAway), "close() MUST send a going-away close frame, got %v", err)
+
+	_, _, err = websocket.DefaultDialer.Dial("ws"+strings.TrimPrefix(server.URL, "http")+"/ws", nil)
+	assert.Error(t, err, "closed broadcaster MUST refuse new clients")
+}
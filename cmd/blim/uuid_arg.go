@@ -0,0 +1,106 @@
+package main
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/srg/blim/internal/bledb"
+	"github.com/srg/blim/internal/device"
+)
+
+// uuidPattern matches 16-, 32- and 128-bit UUIDs with an optional 0x prefix; 128-bit UUIDs may be dashed
+var uuidPattern = regexp.MustCompile(`(?i)^(0x)?([0-9a-f]{4}|[0-9a-f]{8}|[0-9a-f]{32}|[0-9a-f]{8}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{12})$`)
+
+// targetArg is a parsed "[service/]uuid[,uuid...]" command argument
+type targetArg struct {
+	Service string   // Normalized service UUID, empty when the argument has no service part
+	UUIDs   []string // Normalized characteristic (or descriptor) UUIDs
+}
+
+// parseTargetArg parses a "[service/]uuid[,uuid...]" argument such as "2a19", "180f/2a19" or
+// "Heart Rate/2a37,2a38". Each part may be a 16-, 32- or 128-bit UUID or a known name.
+func parseTargetArg(arg string) (*targetArg, error) {
+	target := &targetArg{}
+	rest := arg
+	if svc, chars, ok := strings.Cut(arg, "/"); ok {
+		service, err := resolveUUIDRef("service", svc, bledb.LookupServiceUUID)
+		if err != nil {
+			return nil, err
+		}
+		target.Service = service
+		rest = chars
+	}
+
+	for _, ref := range parseCSVUUIDs(rest) {
+		uuid, err := resolveUUIDRef("characteristic", ref, bledb.LookupCharacteristicUUID)
+		if err != nil {
+			return nil, err
+		}
+		target.UUIDs = append(target.UUIDs, uuid)
+	}
+	if len(target.UUIDs) == 0 {
+		return nil, fmt.Errorf("invalid UUID argument %q: no characteristic UUID given", arg)
+	}
+	return target, nil
+}
+
+// resolveUUIDRef validates a UUID or known name of the given kind and returns the normalized UUID
+func resolveUUIDRef(kind, ref string, lookupName func(string) string) (string, error) {
+	ref = strings.TrimSpace(ref)
+	if uuid := lookupName(ref); uuid != "" {
+		return device.NormalizeUUID(uuid), nil
+	}
+	if !uuidPattern.MatchString(ref) {
+		return "", fmt.Errorf("invalid %s %q: expected a 16-bit (e.g. 2a19), 32-bit or 128-bit UUID, or a known %s name", kind, ref, kind)
+	}
+	return device.NormalizeUUID(ref), nil
+}
+
+// targetUUIDArgs returns a positional argument validator that applies base and then checks that the
+// "[service/]uuid[,uuid...]" argument at index, when present, is well-formed. Malformed UUIDs are
+// rejected before any connection attempt.
+func targetUUIDArgs(index int, base cobra.PositionalArgs) cobra.PositionalArgs {
+	return func(cmd *cobra.Command, args []string) error {
+		if err := base(cmd, args); err != nil {
+			return err
+		}
+		if len(args) > index {
+			if _, err := parseTargetArg(args[index]); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+}
+
+// completeTargetUUID completes the "[service/]uuid" argument at index with known characteristic UUIDs and
+// known service UUIDs followed by "/"; after a "/" the characteristics are offered under that service.
+// Candidates carry their name as description. Other arguments get no completion.
+func completeTargetUUID(index int) func(*cobra.Command, []string, string) ([]string, cobra.ShellCompDirective) {
+	return func(_ *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		if len(args) != index {
+			return nil, cobra.ShellCompDirectiveNoFileComp
+		}
+
+		if svc, _, ok := strings.Cut(toComplete, "/"); ok {
+			return uuidCompletions(bledb.KnownCharacteristics(), svc+"/", ""), cobra.ShellCompDirectiveNoFileComp
+		}
+
+		completions := uuidCompletions(bledb.KnownCharacteristics(), "", "")
+		completions = append(completions, uuidCompletions(bledb.KnownServices(), "", "/")...)
+		return completions, cobra.ShellCompDirectiveNoFileComp | cobra.ShellCompDirectiveNoSpace
+	}
+}
+
+// uuidCompletions formats known UUIDs as sorted "<prefix><uuid><suffix>\t<name>" completion candidates
+func uuidCompletions(known map[string]string, prefix, suffix string) []string {
+	completions := make([]string, 0, len(known))
+	for uuid, name := range known {
+		completions = append(completions, prefix+uuid+suffix+"\t"+name)
+	}
+	sort.Strings(completions)
+	return completions
+}
@@ -0,0 +1,91 @@
+//go:build test
+
+package main
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseTargetArg(t *testing.T) {
+	// GOAL: Verify "[service/]uuid[,uuid...]" arguments accept every UUID width and known names, and reject garbage
+	//
+	// TEST SCENARIO: Valid forms → normalized service and UUIDs; malformed UUIDs → descriptive error
+
+	tests := []struct {
+		name    string
+		arg     string
+		service string
+		uuids   []string
+	}{
+		{"16-bit", "2a19", "", []string{"2a19"}},
+		{"16-bit with 0x", "0x2A19", "", []string{"2a19"}},
+		{"32-bit", "12345678", "", []string{"12345678"}},
+		{"128-bit dashed", "6E400001-B5A3-F393-E0A9-E50E24DCCA9E", "", []string{"6e400001b5a3f393e0a9e50e24dcca9e"}},
+		{"128-bit SIG base", "00002a19-0000-1000-8000-00805f9b34fb", "", []string{"2a19"}},
+		{"list", "2a37, 2a38", "", []string{"2a37", "2a38"}},
+		{"service prefix", "180f/2a19", "180f", []string{"2a19"}},
+		{"names", "Heart Rate/Heart Rate Measurement", "180d", []string{"2a37"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			target, err := parseTargetArg(tt.arg)
+			require.NoError(t, err)
+			assert.Equal(t, tt.service, target.Service)
+			assert.Equal(t, tt.uuids, target.UUIDs)
+		})
+	}
+
+	for _, arg := range []string{"2a1", "xyz", "2a19/", "Heart Rat/2a37", "2a19,zz", "6e400001-b5a3"} {
+		t.Run("invalid "+arg, func(t *testing.T) {
+			_, err := parseTargetArg(arg)
+			assert.Error(t, err, "%q MUST be rejected", arg)
+		})
+	}
+
+	_, err := parseTargetArg("2a1g")
+	assert.ErrorContains(t, err, "16-bit (e.g. 2a19), 32-bit or 128-bit UUID", "error MUST explain the accepted formats")
+}
+
+func TestTargetUUIDArgs(t *testing.T) {
+	// GOAL: Verify the argument validator rejects malformed UUIDs before the command runs
+	//
+	// TEST SCENARIO: Base arity enforced → malformed UUID rejected → valid or absent UUID accepted
+
+	validate := targetUUIDArgs(1, cobra.RangeArgs(1, 2))
+	cmd := &cobra.Command{}
+
+	assert.Error(t, validate(cmd, nil), "base validator MUST still apply")
+	assert.ErrorContains(t, validate(cmd, []string{"AA:BB", "zz"}), "invalid characteristic")
+	assert.NoError(t, validate(cmd, []string{"AA:BB", "180f/2a19"}))
+	assert.NoError(t, validate(cmd, []string{"AA:BB"}))
+}
+
+func TestCompleteTargetUUID(t *testing.T) {
+	// GOAL: Verify completion offers known characteristics and services for the UUID argument only
+	//
+	// TEST SCENARIO: Device argument → no candidates; UUID argument → characteristics and "service/" entries; after "/" → characteristics under the service
+
+	complete := completeTargetUUID(1)
+
+	candidates, _ := complete(nil, nil, "")
+	assert.Empty(t, candidates, "device address MUST NOT be completed")
+
+	candidates, directive := complete(nil, []string{"AA:BB"}, "")
+	assert.Contains(t, candidates, "2a19\tBattery Level")
+	assert.Contains(t, candidates, "180f/\tBattery Service")
+	assert.NotZero(t, directive&cobra.ShellCompDirectiveNoSpace, "service candidates MUST NOT add a space")
+
+	candidates, _ = complete(nil, []string{"AA:BB"}, "180f/2a")
+	assert.Contains(t, candidates, "180f/2a19\tBattery Level")
+	for _, c := range candidates {
+		assert.True(t, strings.HasPrefix(c, "180f/"), "candidate %q MUST keep the service prefix", c)
+	}
+
+	candidates, _ = complete(nil, []string{"AA:BB", "2a19"}, "")
+	assert.Empty(t, candidates, "data argument MUST NOT be completed")
+}
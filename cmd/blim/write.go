@@ -1,9 +1,9 @@
 package main
 
 import (
-	"context"
 	"encoding/hex"
 	"fmt"
+	"os"
 	"strings"
 	"time"
 
@@ -14,7 +14,7 @@ import (
 
 // writeCmd represents the write command
 var writeCmd = &cobra.Command{
-	Use:   "write <device-address> <uuid> <data>",
+	Use:   "write <device-address> <[service/]uuid> <data>",
 	Short: "Write to a characteristic or descriptor",
 	Long: fmt.Sprintf(`Writes data to a BLE characteristic or descriptor.
 
@@ -25,25 +25,34 @@ Examples:
   # Write hex data
   blim write %s 2a06 01 --hex
 
+  # Write to a characteristic of a specific service
+  blim write %s 1802/2a06 02 --hex
+
   # Write to descriptor (enable notifications)
   blim write %s --service 180d --char 2a37 --desc 2902 0100 --hex
 
   # Write without response (faster, no ACK)
   blim write %s 2a06 "data" --without-response
 
-%s`, exampleDeviceAddress, exampleDeviceAddress, exampleDeviceAddress, exampleDeviceAddress, deviceAddressNote),
-	Args: cobra.RangeArgs(2, 3),
-	RunE: runWrite,
+UUIDs may be 16-bit (2a06), 32-bit or 128-bit, or known names; malformed UUIDs are rejected before connecting.
+
+%s`, exampleDeviceAddress, exampleDeviceAddress, exampleDeviceAddress, exampleDeviceAddress, exampleDeviceAddress, deviceAddressNote),
+	Args:              targetUUIDArgs(1, cobra.RangeArgs(2, 3)),
+	ValidArgsFunction: completeTargetUUID(1),
+	RunE:              runWrite,
 }
 
 var (
-	writeServiceUUID string
-	writeCharUUID    string
-	writeDescUUID    string
-	writeHex         bool
-	writeNoResponse  bool
-	writeChunkSize   int
-	writeTimeout     time.Duration
+	writeServiceUUID  string
+	writeCharUUID     string
+	writeDescUUID     string
+	writeHex          bool
+	writeNoResponse   bool
+	writeChunkSize    int
+	writeTimeout      time.Duration
+	writeAutoPair     bool
+	writeNoDesc       bool
+	writeOnlyServices []string
 )
 
 func init() {
@@ -54,6 +63,9 @@ func init() {
 	writeCmd.Flags().BoolVar(&writeNoResponse, "without-response", false, "Write without response (faster, no ACK); default waits for ACK, if available")
 	writeCmd.Flags().IntVar(&writeChunkSize, "chunk", 0, "Force writes into N-byte chunks; default 0, auto-detect from MTU")
 	writeCmd.Flags().DurationVar(&writeTimeout, "timeout", 5*time.Second, "Write timeout")
+	writeCmd.Flags().BoolVar(&writeNoDesc, "no-descriptors", false, "Skip descriptor discovery for a faster connect (descriptors are unavailable)")
+	writeCmd.Flags().StringArrayVar(&writeOnlyServices, "only-service", nil, "Discover only this service (repeatable); faster connect on devices with many services")
+	writeCmd.Flags().BoolVar(&writeAutoPair, "auto-pair", false, "Pair with the device and retry once when a write fails with an authentication error")
 }
 
 func runWrite(cmd *cobra.Command, args []string) error {
@@ -61,8 +73,22 @@ func runWrite(cmd *cobra.Command, args []string) error {
 
 	// Parse UUID from positional arg or flags
 	var targetUUID string
+	serviceUUID := writeServiceUUID
 	if len(args) >= 2 {
-		targetUUID = args[1]
+		target, err := parseTargetArg(args[1])
+		if err != nil {
+			return err
+		}
+		if len(target.UUIDs) > 1 {
+			return fmt.Errorf("write accepts a single UUID, got %d", len(target.UUIDs))
+		}
+		if target.Service != "" {
+			if serviceUUID != "" && device.NormalizeUUID(serviceUUID) != target.Service {
+				return fmt.Errorf("service %s in argument conflicts with --service %s", target.Service, serviceUUID)
+			}
+			serviceUUID = target.Service
+		}
+		targetUUID = target.UUIDs[0]
 	} else if writeCharUUID != "" {
 		targetUUID = writeCharUUID
 	} else if writeDescUUID != "" {
@@ -70,6 +96,9 @@ func runWrite(cmd *cobra.Command, args []string) error {
 	} else {
 		return fmt.Errorf("UUID required: provide as second argument or via --char/--desc flag")
 	}
+	if writeNoDesc && writeDescUUID != "" {
+		return fmt.Errorf("--desc cannot be used with --no-descriptors")
+	}
 
 	// Parse data from positional arg
 	if len(args) < 3 {
@@ -101,13 +130,24 @@ func runWrite(cmd *cobra.Command, args []string) error {
 	opts := &inspector.InspectOptions{
 		ConnectTimeout:        30 * time.Second,
 		DescriptorReadTimeout: 0, // Skip descriptor reads for write operations
+		SkipDescriptors:       writeNoDesc,
+		OnlyServices:          writeOnlyServices,
+		AutoPair:              writeAutoPair,
+		ConnectRetries:        connectRetries,
+		ConnectRetryBackoff:   connectRetryBackoff,
+		ByIdentifier:          connectByIdentifier,
+		GATTCacheDir:          gattCacheDir(),
+		DiscoveryTimeout:      discoveryTimeout,
 	}
 
-	// Use background context
-	ctx := context.Background()
+	// Command context is canceled on SIGINT/SIGTERM
+	ctx := commandContext(cmd)
 
 	// Define the write operation
 	writeOperation := func(dev device.Device) (any, error) {
+		// Disconnect on exit even if the operation does not return after an interrupt
+		defer registerTeardown("disconnect "+address, disconnectTeardown(dev))()
+
 		// Stop progress indicator before returning
 		progress.Stop()
 
@@ -118,7 +158,7 @@ func runWrite(cmd *cobra.Command, args []string) error {
 		}
 
 		// Resolve target characteristic/descriptor
-		char, desc, _, err := doResolveTarget(conn, targetUUID, writeServiceUUID, writeCharUUID, writeDescUUID)
+		char, desc, _, err := doResolveTarget(conn, targetUUID, serviceUUID, writeCharUUID, writeDescUUID)
 		if err != nil {
 			return nil, err
 		}
@@ -132,7 +172,7 @@ func runWrite(cmd *cobra.Command, args []string) error {
 		return err
 	}
 
-	fmt.Println("Write successful")
+	printInfof(os.Stdout, "Write successful\n")
 	return nil
 }
 
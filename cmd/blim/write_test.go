@@ -220,7 +220,7 @@ func (suite *WriteTestSuite) TestWriteCmd_Flags() {
 	}
 
 	// Boolean flags
-	boolFlags := []string{"hex", "without-response"}
+	boolFlags := []string{"hex", "without-response", "auto-pair"}
 	for _, name := range boolFlags {
 		suite.Run(name, func() {
 			flag := writeCmd.Flags().Lookup(name)
@@ -1,15 +1,37 @@
 -- BLE Inspect: Device Inspection
 -- This script replicates the output format of the Go outputInspectText function
 
+-- Device Information Service fields in display order (characteristic UUID → label)
+local DIS_FIELDS = {
+    { uuid = "2a29", label = "Manufacturer Name" },
+    { uuid = "2a24", label = "Model Number" },
+    { uuid = "2a25", label = "Serial Number" },
+    { uuid = "2a27", label = "Hardware Revision" },
+    { uuid = "2a26", label = "Firmware Revision" },
+    { uuid = "2a28", label = "Software Revision" },
+    { uuid = "2a23", label = "System ID" },
+    { uuid = "2a50", label = "PnP ID" },
+}
+
 -- Extract Device Information Service data
 local function extract_dis_info(services)
+    local labels = {}
+    for _, field in ipairs(DIS_FIELDS) do
+        labels[field.uuid] = field.label
+    end
+
     for _, service in ipairs(services) do
         -- Check if this is the Device Information Service (UUID 180A)
         if string.upper(service.uuid) == "180A" then
             local dis_data = {}
             for _, char in ipairs(service.characteristics) do
-                local char_name = blim.format_named(char)
-                dis_data[char_name] = blim.bytes_to_hex(char.value)
+                local char_name = labels[string.lower(char.uuid)] or blim.format_named(char)
+                -- Prefer decoded UTF-8 text, fall back to hex for binary or invalid values
+                if char.utf8_value then
+                    dis_data[char_name] = char.utf8_value
+                else
+                    dis_data[char_name] = blim.bytes_to_hex(char.value)
+                end
             end
             return dis_data
         end
@@ -18,7 +40,8 @@ local function extract_dis_info(services)
 end
 
 -- Collect all device and GATT data into a structured table
-local function collect_device_data()
+-- read_values: include hex values and inline read errors for every readable characteristic
+local function collect_device_data(read_values)
     local data = {}
 
     -- Device info
@@ -55,15 +78,28 @@ local function collect_device_data()
 
                 -- Try to read the characteristic value if it's readable
                 local value = nil
+                local value_hex = nil
+                local read_error = nil
                 local parsed_value = nil
+                local utf8_value = nil
                 if char_info.properties and char_info.properties.read and char_info.read then
                     local val, err = char_info.read()
+                    if err ~= nil and read_values then
+                        read_error = tostring(err)
+                    end
                     if err == nil then
+                        if read_values then
+                            value_hex = blim.bytes_to_hex(val)
+                        end
                         value = val
                         -- Try to parse if parser is available and value is non-empty
                         if char_info.has_parser and char_info.parse and value and value ~= "" then
                             parsed_value = char_info:parse(value)  -- Use colon syntax
                         end
+                        -- Decode as UTF-8 text if the characteristic is known to be a string
+                        if char_info.is_utf8 and char_info.decode_utf8 and value and value ~= "" then
+                            utf8_value = char_info:decode_utf8(value)  -- nil if not valid UTF-8
+                        end
                     end
                     -- Silently ignore read errors in inspect (characteristic may not be readable)
                 end
@@ -73,7 +109,10 @@ local function collect_device_data()
                     name = char_info.name,  -- Copy optional name field
                     properties = char_info.properties,  -- Keep dual-purpose table (array + hash)
                     value = value,
+                    value_hex = value_hex,  -- Hex value (only with read_values)
+                    read_error = read_error,  -- Read error message (only with read_values)
                     parsed_value = parsed_value,  -- Add parsed value
+                    utf8_value = utf8_value,  -- Add decoded UTF-8 string (nil if not a string or invalid)
                     has_parser = char_info.has_parser,  -- Add parser availability flag
                     requires_authentication = char_info.requires_authentication,  -- Add authentication flag
                     descriptors = char_info.descriptors or {}
@@ -218,20 +257,9 @@ local function output_text(data)
     -- Device Information Service section
     if data.device_info and next(data.device_info) ~= nil then
         io.write("  Device Information Service:\n")
-        -- Define display order for DIS fields
-        local dis_order = {
-            "Manufacturer Name",
-            "Model Number",
-            "Serial Number",
-            "Hardware Revision",
-            "Firmware Revision",
-            "Software Revision",
-            "System ID",
-            "PnP ID"
-        }
-        for _, field_name in ipairs(dis_order) do
-            if data.device_info[field_name] then
-                io.write(string.format("    %s: %s\n", field_name, data.device_info[field_name]))
+        for _, field in ipairs(DIS_FIELDS) do
+            if data.device_info[field.label] then
+                io.write(string.format("    %s: %s\n", field.label, data.device_info[field.label]))
             end
         end
     end
@@ -287,7 +315,10 @@ local function output_text(data)
             end
 
             -- Show characteristic value if available
-            if char.value and char.value ~= "" then
+            if char.utf8_value then
+                -- Known string characteristic with valid UTF-8 value
+                io.write(string.format("      value (utf8):  %s\n", char.utf8_value))
+            elseif char.value and char.value ~= "" then
                 local value_hex = blim.bytes_to_hex(char.value)
                 local value_ascii = blim.to_ascii(char.value)
 
@@ -304,6 +335,11 @@ local function output_text(data)
                 end
             end
 
+            -- Show read error inline (read_values mode) instead of aborting the dump
+            if char.read_error then
+                io.write(string.format("      value (error): %s\n", char.read_error))
+            end
+
             -- Show descriptors if available (with recursive printing for aggregate formats)
             if #char.descriptors > 0 then
                 -- Build set of descriptor indices referenced by 2905 aggregate descriptors
@@ -366,6 +402,63 @@ local function output_json(data)
     print(json.encode(data))
 end
 
+-- Escape a string for use inside a double-quoted DOT label
+local function dot_escape(s)
+    s = string.gsub(tostring(s), "\\", "\\\\")
+    s = string.gsub(s, '"', '\\"')
+    return s
+end
+
+-- Format and output as a GraphViz DOT graph: device as root, services as clusters,
+-- characteristics and descriptors as nodes (render with `dot -Tpng`)
+local function output_dot(data)
+    local device_label = dot_escape(data.device.address)
+    if data.device.name and data.device.name ~= "" and data.device.name ~= data.device.address then
+        device_label = dot_escape(data.device.name) .. "\\n" .. device_label
+    end
+
+    io.write("digraph gatt {\n")
+    io.write("  rankdir=LR;\n")
+    io.write('  node [shape=box, style=rounded, fontname="Helvetica"];\n')
+    io.write('  edge [arrowhead=none];\n')
+    io.write(string.format('  device [label="%s", shape=ellipse, style=bold];\n', device_label))
+
+    for s, service in ipairs(data.services) do
+        local service_id = string.format("s%d", s)
+        io.write(string.format("\n  subgraph cluster_%s {\n", service_id))
+        io.write(string.format('    label="%s";\n', dot_escape(blim.format_named(service))))
+        io.write("    style=rounded;\n")
+        -- Clusters cannot be edge endpoints, so each cluster holds an anchor node for the device edge
+        io.write(string.format('    %s [label="", shape=point];\n', service_id))
+
+        for c, char in ipairs(service.characteristics) do
+            local char_id = string.format("%s_c%d", service_id, c)
+            local prop_names = {}
+            for _, prop in ipairs(char.properties or {}) do
+                table.insert(prop_names, prop.name)
+            end
+            local label = dot_escape(blim.format_named(char))
+            if #prop_names > 0 then
+                label = label .. "\\n" .. dot_escape(table.concat(prop_names, ", "))
+            end
+            io.write(string.format('    %s [label="%s"];\n', char_id, label))
+            io.write(string.format("    %s -> %s;\n", service_id, char_id))
+
+            for d, descriptor in ipairs(char.descriptors) do
+                local desc_id = string.format("%s_d%d", char_id, d)
+                io.write(string.format('    %s [label="%s", shape=note, style=""];\n',
+                    desc_id, dot_escape(blim.format_named(descriptor))))
+                io.write(string.format("    %s -> %s;\n", char_id, desc_id))
+            end
+        end
+
+        io.write("  }\n")
+        io.write(string.format("  device -> %s;\n", service_id))
+    end
+
+    io.write("}\n")
+end
+
 -- Check for format argument (default to "text")
 -- Supports both URL params (arg["format"]) and positional args (arg[1])
 local format = "text"
@@ -377,12 +470,17 @@ if arg then
     end
 end
 
+-- Read values mode: include hex values and inline read errors
+local read_values = arg and arg["read_values"] == "true"
+
 -- Collect device data once
-local data = collect_device_data()
+local data = collect_device_data(read_values)
 
 -- Output in requested format
 if format == "json" then
     output_json(data)
+elseif format == "dot" then
+    output_dot(data)
 else
     output_text(data)
 end
\ No newline at end of file
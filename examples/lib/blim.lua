@@ -7,12 +7,80 @@ local native = _blim_internal
 -- Direct assignments (zero overhead - references to Go functions)
 blim.subscribe = native.subscribe
 blim.list = native.list
+blim.all_characteristics = native.all_characteristics
 blim.characteristic = native.characteristic
+blim.device_info = native.device_info
+blim.read_service = native.read_service
+blim.write_multiple = native.write_multiple
 blim.device = native.device
 blim.bridge = native.bridge
+blim.set_timeouts = native.set_timeouts
+blim.get_timeouts = native.get_timeouts
+blim.on_connection_event = native.on_connection_event
+blim.on_raw_notification = native.on_raw_notification
+blim.set_parser_override = native.set_parser_override
+blim.snapshot_subscriptions = native.snapshot_subscriptions
+blim.enabled_notifications = native.enabled_notifications
+blim.subscription_stats = native.subscription_stats
+blim.flush_writes = native.flush_writes
+blim.set_idle_timeout = native.set_idle_timeout
+blim.read_rssi = native.read_rssi
+blim.set_rssi_interval = native.set_rssi_interval
+blim.estimate_distance = native.estimate_distance
+blim.simulate_notification = native.simulate_notification
+blim.scan = native.scan
+blim.set_log_level = native.set_log_level
+blim.get_log_level = native.get_log_level
 blim.sleep = native.sleep
+blim.yield = native.yield
+
+-- GATT property bit values, e.g. char:has_property(blim.PROPERTIES.NOTIFY)
+blim.PROPERTIES = native.PROPERTIES
+
+-- Pair with the device, optionally retrying a pending read once pairing completes
+-- Usage:
+--   local ok, err = blim.pair()            -- (true, nil) or (nil, error)
+--   local value, err = blim.pair(char)     -- pairs, then re-reads char
+function blim.pair(char)
+    local ok, err = native.pair()
+    if not ok then
+        return nil, err
+    end
+    if char then
+        return char.read()
+    end
+    return true, nil
+end
 
+-- Re-subscribe to the characteristics captured by blim.snapshot_subscriptions()
+-- Usage:
+--   local snapshot = blim.snapshot_subscriptions()
+--   ... disconnect, reconnect ...
+--   blim.restore_subscriptions(snapshot, function(record) ... end)
+-- Returns the number of restored characteristics.
+function blim.restore_subscriptions(snapshot, callback)
+    if type(snapshot) ~= "table" or type(snapshot.services) ~= "table" then
+        error("restore_subscriptions() expects a snapshot table from snapshot_subscriptions()")
+    end
+    if type(callback) ~= "function" then
+        error("restore_subscriptions() expects a callback function")
+    end
 
+    local count = 0
+    for _, entry in ipairs(snapshot.services) do
+        count = count + #entry.chars
+    end
+    if count == 0 then
+        return 0
+    end
+
+    blim.subscribe{
+        services = snapshot.services,
+        Mode = "EveryUpdate",
+        Callback = callback,
+    }
+    return count
+end
 
 -- Helper functions for Lua scripts
 
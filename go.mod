@@ -17,6 +17,8 @@ require (
 )
 
 require (
+	github.com/eclipse/paho.mqtt.golang v1.5.1
+	github.com/gorilla/websocket v1.5.3
 	github.com/hedzr/go-ringbuf/v2 v2.2.2
 	github.com/smallnest/ringbuffer v0.0.0-20250317021400-0da97b586904
 	github.com/srgg/testify v0.1.1
@@ -49,6 +51,8 @@ require (
 	github.com/stretchr/objx v0.5.3 // indirect
 	github.com/yudai/golcs v0.0.0-20170316035057-ecda9a501e82 // indirect
 	github.com/yudai/pp v2.0.1+incompatible // indirect
+	golang.org/x/net v0.44.0 // indirect
+	golang.org/x/sync v0.17.0 // indirect
 	gopkg.in/check.v1 v1.0.0-20201130134442-10cb98267c6c // indirect
 )
 
@@ -7,6 +7,7 @@ import (
 	"github.com/sirupsen/logrus"
 	"github.com/srg/blim/internal/device"
 	goble "github.com/srg/blim/internal/device/go-ble"
+	"github.com/srg/blim/internal/devicefactory"
 )
 
 // ProgressCallback is called when the inspection phase changes
@@ -15,13 +16,32 @@ type ProgressCallback func(phase string)
 // InspectOptions defines options for inspecting a BLE device profile
 type InspectOptions struct {
 	ConnectTimeout            time.Duration
+	DiscoveryTimeout          time.Duration // Bounds service discovery after connecting (0 = no limit)
 	DescriptorReadTimeout     time.Duration // Timeout for reading descriptor values (0 = skip reads)
+	SkipDescriptors           bool          // Stop discovery at characteristics (faster connect, no descriptors)
+	OnlyServices              []string      // Discover only these services (empty = all)
 	CharacteristicReadTimeout time.Duration // Timeout for reading characteristic values
+	AutoPair                  bool          // Pair and retry once on authentication errors
+	ConnectRetries            int           // Retries of a failed initial connection (0 = no retry)
+	ConnectRetryBackoff       time.Duration // Delay before the first connection retry, doubled after each retry
+	ByIdentifier              bool          // Connect to the system-cached peripheral without scanning, falling back to a scan
+	GATTCacheDir              string        // On-disk GATT cache directory ("" = always discover)
 }
 
 // InspectCallback processes a connected device and produces output of type R
 type InspectCallback[R any] func(device.Device) (R, error)
 
+// ConnectRetryPolicy returns a retry policy for the initial connection that logs each failed attempt at info level
+func ConnectRetryPolicy(retries int, backoff time.Duration, logger *logrus.Logger) device.ConnectRetryPolicy {
+	return device.ConnectRetryPolicy{
+		Retries: retries,
+		Backoff: backoff,
+		OnRetry: func(attempt int, err error, delay time.Duration) {
+			logger.WithError(err).Infof("Connection attempt %d/%d failed, retrying in %v", attempt, retries+1, delay)
+		},
+	}
+}
+
 // InspectDevice connects to a device, discovers its profile, and executes the callback with the connected device.
 // The device lifecycle (connection and disconnection) is managed automatically.
 // The callback receives the connected device and can return any result type R along with an error.
@@ -41,14 +61,28 @@ func InspectDevice[R any](ctx context.Context, address string, opts *InspectOpti
 	// Report phase change: starting connection
 	progressCallback("Connecting")
 
-	// Create device and connect (reuses BLEConnection.Connect logic - no duplication!)
-	dev := goble.NewBLEDeviceWithAddress(address, logger)
 	connectOpts := &device.ConnectOptions{
 		ConnectTimeout:        opts.ConnectTimeout,
+		DiscoveryTimeout:      opts.DiscoveryTimeout,
 		DescriptorReadTimeout: opts.DescriptorReadTimeout,
+		SkipDescriptors:       opts.SkipDescriptors,
+		AutoPair:              opts.AutoPair,
+		GATTCacheDir:          opts.GATTCacheDir,
+	}
+	for _, svc := range opts.OnlyServices {
+		connectOpts.Services = append(connectOpts.Services, device.SubscribeOptions{Service: svc})
+		connectOpts.DiscoverOnlyListed = true
 	}
 
-	err := dev.Connect(ctx, connectOpts)
+	// Create device and connect (reuses BLEConnection.Connect logic - no duplication!)
+	var dev device.Device
+	var err error
+	if opts.ByIdentifier {
+		dev, err = devicefactory.ConnectByIdentifier(ctx, address, connectOpts, logger)
+	} else {
+		dev = goble.NewBLEDeviceWithAddress(address, logger)
+		err = device.ConnectWithRetry(ctx, dev, connectOpts, ConnectRetryPolicy(opts.ConnectRetries, opts.ConnectRetryBackoff, logger))
+	}
 
 	if err != nil {
 		progressCallback("Failed")
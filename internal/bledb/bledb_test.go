@@ -38,6 +38,16 @@ func TestNormalizeUUID(t *testing.T) {
 			input:    "6e400001-b5a3-f393-e0a9-e50e24dcca9e",
 			expected: "6e400001b5a3f393e0a9e50e24dcca9e",
 		},
+		{
+			name:     "32-bit alias of 16-bit UUID",
+			input:    "0000180d",
+			expected: "180d",
+		},
+		{
+			name:     "Full Bluetooth SIG UUID with 32-bit alias",
+			input:    "1234abcd-0000-1000-8000-00805f9b34fb",
+			expected: "1234abcd",
+		},
 		{
 			name:     "UUID with braces",
 			input:    "{0000180d-0000-1000-8000-00805f9b34fb}",
@@ -80,6 +90,11 @@ func TestLookupServiceWithFullUUID(t *testing.T) {
 			uuid:     "0000180d00001000800000805f9b34fb",
 			expected: "Heart Rate",
 		},
+		{
+			name:     "Heart Rate - 32-bit alias",
+			uuid:     "0000180D",
+			expected: "Heart Rate",
+		},
 		{
 			name:     "Battery Service - short form",
 			uuid:     "180f",
@@ -122,6 +137,11 @@ func TestLookupCharacteristicWithFullUUID(t *testing.T) {
 			uuid:     "00002a37-0000-1000-8000-00805f9b34fb",
 			expected: "Heart Rate Measurement",
 		},
+		{
+			name:     "Heart Rate Measurement - 32-bit alias",
+			uuid:     "0x00002a37",
+			expected: "Heart Rate Measurement",
+		},
 		{
 			name:     "Battery Level - short form",
 			uuid:     "2a19",
@@ -178,3 +198,94 @@ func TestLookupDescriptorWithFullUUID(t *testing.T) {
 		})
 	}
 }
+
+// TestLookupUUIDByName verifies that service and characteristic names resolve back to their UUIDs
+func TestLookupUUIDByName(t *testing.T) {
+	assert.Equal(t, "180d", LookupServiceUUID("Heart Rate"))
+	assert.Equal(t, "180d", LookupServiceUUID("  heart rate "))
+	assert.Equal(t, "180f", LookupServiceUUID("Battery Service"))
+	assert.Equal(t, "2a37", LookupCharacteristicUUID("Heart Rate Measurement"))
+	assert.Equal(t, "2a19", LookupCharacteristicUUID("BATTERY LEVEL"))
+
+	assert.Empty(t, LookupServiceUUID("Heart Rate Measurement"), "characteristic name MUST NOT resolve as service")
+	assert.Empty(t, LookupCharacteristicUUID("2a37"), "UUID strings MUST NOT resolve as names")
+	assert.Empty(t, LookupCharacteristicUUID(""))
+}
+
+// TestKnownUUIDs verifies that the known service and characteristic tables are exposed as copies
+func TestKnownUUIDs(t *testing.T) {
+	services := KnownServices()
+	assert.Equal(t, "Heart Rate", services["180d"])
+	chars := KnownCharacteristics()
+	assert.Equal(t, "Battery Level", chars["2a19"])
+
+	delete(chars, "2a19")
+	assert.Equal(t, "Battery Level", KnownCharacteristics()["2a19"], "callers MUST NOT modify the database")
+}
+
+// TestTableEntries verifies listing, lookup across tables and name search
+func TestTableEntries(t *testing.T) {
+	services, err := Entries(TableService)
+	assert.NoError(t, err)
+	assert.Contains(t, services, Entry{Table: TableService, UUID: "180d", Name: "Heart Rate"})
+	for i := 1; i < len(services); i++ {
+		assert.Less(t, services[i-1].UUID, services[i].UUID, "entries MUST be sorted by UUID")
+	}
+
+	_, err = Entries("appearance")
+	assert.Error(t, err, "unknown tables MUST be rejected")
+
+	assert.Equal(t, []Entry{{Table: TableCharacteristic, UUID: "2a37", Name: "Heart Rate Measurement"}},
+		Lookup("00002A37-0000-1000-8000-00805f9b34fb"), "lookup MUST normalize the UUID")
+	assert.Empty(t, Lookup("ffffffff-ffff-ffff-ffff-ffffffffffff"))
+
+	found := Search("heart rate")
+	assert.Contains(t, found, Entry{Table: TableService, UUID: "180d", Name: "Heart Rate"})
+	assert.Contains(t, found, Entry{Table: TableCharacteristic, UUID: "2a37", Name: "Heart Rate Measurement"})
+	assert.Equal(t, TableService, found[0].Table, "results MUST be ordered by table")
+	assert.Empty(t, Search("  "), "an empty query MUST match nothing")
+}
+
+// TestFilterEntries verifies that filtering matches UUIDs and names case-insensitively
+func TestFilterEntries(t *testing.T) {
+	entries := []Entry{
+		{Table: TableService, UUID: "180d", Name: "Heart Rate"},
+		{Table: TableService, UUID: "180f", Name: "Battery Service"},
+		{Table: TableService, UUID: "6e400001b5a3f393e0a9e50e24dcca9e", Name: "Nordic UART Service"},
+	}
+
+	assert.Equal(t, entries[:1], Filter(entries, "HEART"), "name MUST match case-insensitively")
+	assert.Equal(t, entries[1:2], Filter(entries, "180F"), "UUID MUST match case-insensitively")
+	assert.Equal(t, entries[2:], Filter(entries, "6E400001-B5A3"), "dashes MUST be ignored for UUID matches")
+	assert.Equal(t, entries[1:], Filter(entries, "service"), "order MUST be kept")
+	assert.Equal(t, entries, Filter(entries, ""), "an empty filter MUST keep all entries")
+	assert.Empty(t, Filter(entries, "glucose"))
+}
+
+// TestLookupAppearanceCode verifies that appearance categories and subcategories resolve from the generated table
+func TestLookupAppearanceCode(t *testing.T) {
+	assert.Equal(t, "Phone", LookupAppearanceCode(0x0040))
+	assert.Equal(t, "Heart Rate Sensor", LookupAppearanceCode(0x0340))
+	assert.Equal(t, "Heart Rate Sensor: Heart Rate Belt", LookupAppearanceCode(0x0341))
+	assert.Equal(t, "Watch: Sports Watch", LookupAppearanceCode(0x00C1))
+
+	assert.Empty(t, LookupAppearanceCode(0x0000), "Unknown category MUST NOT resolve to a name")
+	assert.Empty(t, LookupAppearanceCode(0xFFFF), "unassigned values MUST NOT resolve")
+}
+
+// TestAppearanceName verifies the category/subcategory split of appearance values
+func TestAppearanceName(t *testing.T) {
+	name, ok := AppearanceName(0x0341)
+	assert.True(t, ok)
+	assert.Equal(t, "Heart Rate Sensor: Heart Rate Belt", name)
+
+	name, ok = AppearanceName(0x00C0)
+	assert.True(t, ok, "subcategory 0 MUST resolve to the category")
+	assert.Equal(t, "Watch", name)
+
+	_, ok = AppearanceName(0x007F)
+	assert.False(t, ok, "unassigned subcategory of a known category MUST NOT resolve")
+
+	_, ok = AppearanceName(0xFFC0)
+	assert.False(t, ok, "unknown category MUST NOT resolve")
+}
@@ -2,14 +2,25 @@
 //
 // This tool downloads BLE service, characteristic, descriptor, and vendor data from
 // Nordic's GitHub repository and generates a lookup table in bledb_gen.go.
+//
+// At the end of a run a report (entries per category, duplicates skipped, entries per upstream source)
+// is printed to stderr; run with --report <file> to also write it as JSON, e.g., for CI checks:
+//
+//	cd internal/bledb && go run ./gen --report bledb-report.json
+//
+// For offline (air-gapped) generation, --source-dir <path> reads every input from a local directory
+// mirroring the upstream URLs as <host>/<path> instead of downloading, e.g.,
+// <path>/bitbucket.org/bluetooth-SIG/public/raw/main/assigned_numbers/core/appearance_values.yaml.
 package main
 
 import (
 	_ "embed"
 	"encoding/json"
+	"flag"
 	"fmt"
 	"io"
 	"net/http"
+	"net/url"
 	"os"
 	"path/filepath"
 	"sort"
@@ -46,11 +57,42 @@ const (
 //go:embed bledb.go.tmpl
 var codeTemplate string
 
+// sourceDir is a local mirror of the upstream files (--source-dir); empty means download into the cache
+var sourceDir string
+
 // rawEntry represents a single BLE database entry before processing.
 type rawEntry struct {
-	UUID string
-	Name string
-	Type BLEType
+	UUID   string
+	Name   string
+	Type   BLEType
+	Source Source
+}
+
+// Source identifies the upstream dataset an entry came from.
+type Source string
+
+const (
+	SourceNordic Source = "Nordic"
+	SourceBSIG   Source = "BSIG"
+	SourceBleak  Source = "Bleak"
+	SourceBLIM   Source = "BLIM" // Entries added by the generator itself
+)
+
+// priority ranks sources for resolving conflicting names of the same UUID (lower wins):
+// generator-added entries, then Bluetooth SIG assigned numbers, then Nordic, then Bleak.
+func (s Source) priority() int {
+	switch s {
+	case SourceBLIM:
+		return 0
+	case SourceBSIG:
+		return 1
+	case SourceNordic:
+		return 2
+	case SourceBleak:
+		return 3
+	default:
+		return 4
+	}
 }
 
 // templateData holds the data for the code generation template.
@@ -67,7 +109,9 @@ type templateData struct {
 	VendorEntries         []templateEntry
 	UnitEntries           []templateEntry
 	BleakEntries          []templateEntry
-	AppearanceEntries     []templateEntry
+	// Appearance values are split into category (upper 10 bits) and subcategory (full 16-bit value) tables
+	AppearanceCategoryEntries    []templateEntry
+	AppearanceSubcategoryEntries []templateEntry
 }
 
 // templateEntry represents a UUID entry in the template.
@@ -86,18 +130,25 @@ const (
 	Vendor         BLEType = "Vendor"
 	Unit           BLEType = "Unit"
 	Appearance     BLEType = "Appearance"
-	Other          BLEType = "Other"
+	// AppearanceCategory entries are keyed by the 10-bit category number rather than the full appearance value
+	AppearanceCategory BLEType = "AppearanceCategory"
+	Other              BLEType = "Other"
 )
 
 func main() {
-	if err := run(); err != nil {
+	reportPath := flag.String("report", "", "Also write the generation report as JSON to this file")
+	flag.StringVar(&sourceDir, "source-dir", "", "Read input files from this directory (laid out as <host>/<path> of the upstream URLs) instead of downloading")
+	flag.Parse()
+
+	if err := run(*reportPath); err != nil {
 		fmt.Fprintf(os.Stderr, "ERROR: %v\n", err)
 		os.Exit(1)
 	}
 }
 
 // run executes the main generation logic.
-func run() error {
+// The generation report is printed to stderr and, if reportPath is set, written there as JSON.
+func run(reportPath string) error {
 	fmt.Println("Generating BLE database...")
 
 	servicesPath, err := ensureCached("services.json", serviceURL)
@@ -250,9 +301,10 @@ The build will succeed once 0xFFFE is no longer used by BLIMCo.
 	// If 0xFFFE is not assigned or already BLIMCo, ensure BLIMCo is in the list
 	if existingVendor == "" {
 		vendors = append(vendors, rawEntry{
-			UUID: blimcoVendorID,
-			Name: blimcoName,
-			Type: Vendor,
+			UUID:   blimcoVendorID,
+			Name:   blimcoName,
+			Type:   Vendor,
+			Source: SourceBLIM,
 		})
 		fmt.Printf("✓ Added BLIMCo test vendor (0xFFFE) to vendor list\n")
 	}
@@ -309,16 +361,33 @@ The build will succeed once 0xFFFE is no longer used by BLIMCo.
 	}
 	defer f.Close()
 
-	if err := writeGeneratedFile(f, services, characteristics, descriptors, vendors, bsigUnits, bsigAppearances, bleakEntries, timestamp); err != nil {
+	report := newGenerationReport(timestamp)
+	for _, entries := range [][]rawEntry{services, characteristics, descriptors, vendors, bsigUnits, bsigAppearances, bleakEntries} {
+		report.countSources(entries)
+	}
+
+	if err := writeGeneratedFile(f, services, characteristics, descriptors, vendors, bsigUnits, bsigAppearances, bleakEntries, timestamp, report); err != nil {
 		return fmt.Errorf("failed to write generated file: %w", err)
 	}
 	fmt.Println("Generated", outFile)
+
+	report.print(os.Stderr)
+	if reportPath != "" {
+		if err := report.writeJSON(reportPath); err != nil {
+			return err
+		}
+	}
 	return nil
 }
 
 // ensureCached downloads a file from the given URL if it doesn't exist in the cache.
+// With --source-dir, the file is read from the local mirror instead and never downloaded.
 // Returns the path to the cached file.
 func ensureCached(filename, url string) (string, error) {
+	if sourceDir != "" {
+		return sourceFile(filename, url)
+	}
+
 	if err := os.MkdirAll(cacheDir, 0755); err != nil {
 		return "", fmt.Errorf("failed to create cache dir: %w", err)
 	}
@@ -352,6 +421,21 @@ func ensureCached(filename, url string) (string, error) {
 	return path, nil
 }
 
+// sourceFile returns the path of an upstream file in the --source-dir mirror (<host>/<path> of the URL)
+func sourceFile(filename, rawURL string) (string, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return "", fmt.Errorf("invalid source URL for %s: %w", filename, err)
+	}
+
+	path := filepath.Join(sourceDir, u.Host, filepath.FromSlash(u.Path))
+	if _, err := os.Stat(path); err != nil {
+		return "", fmt.Errorf("source file for %s not found in %s (expected %s): %w", filename, sourceDir, path, err)
+	}
+	fmt.Println("Using source file", path)
+	return path, nil
+}
+
 // parseJSONArray parses a JSON file containing BLE database entries.
 // The bleType parameter specifies the category of entries in the file.
 func parseJSONArray(path string, bleType BLEType) ([]rawEntry, error) {
@@ -396,9 +480,10 @@ func parseJSONArray(path string, bleType BLEType) ([]rawEntry, error) {
 
 		if uuid != "" && name != "" {
 			entries = append(entries, rawEntry{
-				UUID: uuid,
-				Name: name,
-				Type: bleType,
+				UUID:   uuid,
+				Name:   name,
+				Type:   bleType,
+				Source: SourceNordic,
 			})
 		}
 	}
@@ -457,9 +542,10 @@ func parseBluetoothSIGYAML(path string, bleType BLEType) ([]rawEntry, error) {
 
 			if uuid != "" && name != "" {
 				entries = append(entries, rawEntry{
-					UUID: uuid,
-					Name: name,
-					Type: Vendor, // always Vendor
+					UUID:   uuid,
+					Name:   name,
+					Type:   Vendor, // always Vendor
+					Source: SourceBSIG,
 				})
 			}
 		}
@@ -499,9 +585,10 @@ func parseBluetoothSIGYAML(path string, bleType BLEType) ([]rawEntry, error) {
 
 			if uuid != "" && name != "" {
 				entries = append(entries, rawEntry{
-					UUID: uuid,
-					Name: name,
-					Type: bleType, // caller-specified type
+					UUID:   uuid,
+					Name:   name,
+					Type:   bleType, // caller-specified type
+					Source: SourceBSIG,
 				})
 			}
 		}
@@ -536,9 +623,10 @@ func parseBleakUUIDs(path string) ([]rawEntry, error) {
 			name := strings.Trim(parts[1], " \",")
 			if uuid != "" && name != "" {
 				entries = append(entries, rawEntry{
-					UUID: uuid,
-					Name: name,
-					Type: Other,
+					UUID:   uuid,
+					Name:   name,
+					Type:   Other,
+					Source: SourceBleak,
 				})
 			}
 		}
@@ -548,7 +636,9 @@ func parseBleakUUIDs(path string) ([]rawEntry, error) {
 
 // parseAppearanceYAML parses the Bluetooth SIG appearance values YAML file.
 // Appearance values encode device type as category (upper 10 bits) + subcategory (lower 6 bits).
-// Format: category=1 (0x0001) → category only, category=1 subcategory=2 (0x0042) → specific subcategory
+// Returns AppearanceCategory entries keyed by category number (category=1 → 0001, "Phone") and
+// Appearance entries keyed by full value with the subcategory name only (category=3 subcategory=1 → 00c1, "Sports Watch").
+// The Unknown category (0) is skipped so that value 0x0000 stays unresolved.
 func parseAppearanceYAML(path string) ([]rawEntry, error) {
 	data, err := os.ReadFile(path)
 	if err != nil {
@@ -599,14 +689,13 @@ func parseAppearanceYAML(path string) ([]rawEntry, error) {
 			catName = n
 		}
 
-		// Add category-only entry (subcategory=0)
+		// Add category entry (covers subcategory 0 and prefixes subcategory names at lookup time)
 		if catValue > 0 && catName != "" {
-			// Category-only appearance: category value, subcategory 0
-			appearanceValue := fmt.Sprintf("%04x", catValue<<6)
 			entries = append(entries, rawEntry{
-				UUID: appearanceValue,
-				Name: catName,
-				Type: Appearance,
+				UUID:   fmt.Sprintf("%04x", catValue),
+				Name:   catName,
+				Type:   AppearanceCategory,
+				Source: SourceBSIG,
 			})
 		}
 
@@ -644,15 +733,14 @@ func parseAppearanceYAML(path string) ([]rawEntry, error) {
 				}
 
 				// Add subcategory entry
-				if subValue > 0 && subName != "" {
+				if catValue > 0 && subValue > 0 && subName != "" {
 					// Full appearance value: (category << 6) | subcategory
 					appearanceValue := fmt.Sprintf("%04x", (catValue<<6)|subValue)
-					// Compose full name: "Category: Subcategory"
-					fullName := fmt.Sprintf("%s: %s", catName, subName)
 					entries = append(entries, rawEntry{
-						UUID: appearanceValue,
-						Name: fullName,
-						Type: Appearance,
+						UUID:   appearanceValue,
+						Name:   subName,
+						Type:   Appearance,
+						Source: SourceBSIG,
 					})
 				}
 			}
@@ -666,8 +754,84 @@ func parseAppearanceYAML(path string) ([]rawEntry, error) {
 	return entries, nil
 }
 
+// filterEntries returns the entries of the given type
+func filterEntries(entries []rawEntry, bleType BLEType) []rawEntry {
+	result := make([]rawEntry, 0, len(entries))
+	for _, e := range entries {
+		if e.Type == bleType {
+			result = append(result, e)
+		}
+	}
+	return result
+}
+
+// generationReport summarizes a generator run so upstream data regressions
+// (e.g., a category suddenly empty) can be caught in CI.
+type generationReport struct {
+	Timestamp         string          `json:"timestamp"`
+	Categories        map[BLEType]int `json:"categories"`         // Generated entries per category
+	DuplicatesSkipped map[BLEType]int `json:"duplicates_skipped"` // Entries dropped as duplicate UUIDs per category
+	Sources           map[Source]int  `json:"sources"`            // Parsed entries per upstream dataset
+}
+
+func newGenerationReport(timestamp string) *generationReport {
+	return &generationReport{
+		Timestamp:         timestamp,
+		Categories:        make(map[BLEType]int),
+		DuplicatesSkipped: make(map[BLEType]int),
+		Sources:           make(map[Source]int),
+	}
+}
+
+// countSources adds parsed entries to the per-source counts
+func (r *generationReport) countSources(entries []rawEntry) {
+	for _, e := range entries {
+		r.Sources[e.Source]++
+	}
+}
+
+// print writes a human-readable summary, warning about empty categories
+func (r *generationReport) print(w io.Writer) {
+	fmt.Fprintln(w, "Generation report:")
+
+	categories := make([]string, 0, len(r.Categories))
+	for c := range r.Categories {
+		categories = append(categories, string(c))
+	}
+	sort.Strings(categories)
+	for _, c := range categories {
+		count := r.Categories[BLEType(c)]
+		fmt.Fprintf(w, "  %-20s %6d entries (%d duplicates skipped)\n", c, count, r.DuplicatesSkipped[BLEType(c)])
+		if count == 0 {
+			fmt.Fprintf(w, "WARNING: no %s entries generated, check upstream data\n", c)
+		}
+	}
+
+	sources := make([]string, 0, len(r.Sources))
+	for s := range r.Sources {
+		sources = append(sources, string(s))
+	}
+	sort.Strings(sources)
+	for _, s := range sources {
+		fmt.Fprintf(w, "  source %-13s %6d entries\n", s, r.Sources[Source(s)])
+	}
+}
+
+// writeJSON writes the report as indented JSON
+func (r *generationReport) writeJSON(path string) error {
+	data, err := json.MarshalIndent(r, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode report: %w", err)
+	}
+	if err := os.WriteFile(path, append(data, '\n'), 0644); err != nil {
+		return fmt.Errorf("failed to write report %s: %w", path, err)
+	}
+	return nil
+}
+
 // writeGeneratedFile writes the BLE database to a Go source file using a template.
-func writeGeneratedFile(f *os.File, services, characteristics, descriptors, vendors, units, appearances, bleakEntries []rawEntry, timestamp string) error {
+// Per-category entry and duplicate counts are recorded in report.
+func writeGeneratedFile(f *os.File, services, characteristics, descriptors, vendors, units, appearances, bleakEntries []rawEntry, timestamp string, report *generationReport) error {
 	tmpl, err := template.New("bledb").Parse(codeTemplate)
 	if err != nil {
 		return fmt.Errorf("failed to parse template: %w", err)
@@ -685,56 +849,61 @@ func writeGeneratedFile(f *os.File, services, characteristics, descriptors, vend
 		}
 
 		// First, normalize and collect entries
-		normalized := make([]templateEntry, 0, len(entries))
+		normalized := make([]rawEntry, 0, len(entries))
 		for _, e := range entries {
 			if e.UUID == "" || e.Name == "" {
 				continue
 			}
-			normalized = append(normalized, templateEntry{
-				UUID: normalizeUUID(e.UUID),
-				Name: e.Name,
-			})
+			e.UUID = normalizeUUID(e.UUID)
+			normalized = append(normalized, e)
 		}
 
-		// Sort by UUID
-		sort.Slice(normalized, func(i, j int) bool {
-			return normalized[i].UUID < normalized[j].UUID
+		// Sort by UUID, then by source priority so the most authoritative entry comes first.
+		// Stable sort keeps the input order among entries of the same source.
+		sort.SliceStable(normalized, func(i, j int) bool {
+			if normalized[i].UUID != normalized[j].UUID {
+				return normalized[i].UUID < normalized[j].UUID
+			}
+			return normalized[i].Source.priority() < normalized[j].Source.priority()
 		})
 
-		// Detect and remove duplicates (first write wins)
+		// Detect and remove duplicates (highest-priority source wins)
 		result := make([]templateEntry, 0, len(normalized))
-		seen := make(map[string]string)
+		seen := make(map[string]rawEntry)
 		for _, e := range normalized {
-			if existingName, exists := seen[e.UUID]; exists {
+			if kept, exists := seen[e.UUID]; exists {
 				// Only warn if the data is different (same UUID, different name)
-				if existingName != e.Name {
-					fmt.Fprintf(os.Stderr, "WARNING: Duplicate UUID %q in %s (keeping %q, skipping %q)\n",
-						e.UUID, bleType, existingName, e.Name)
+				if kept.Name != e.Name {
+					fmt.Fprintf(os.Stderr, "WARNING: Conflicting names for UUID %q in %s: %s wins with %q, skipping %q from %s\n",
+						e.UUID, bleType, kept.Source, kept.Name, e.Name, e.Source)
 				}
 				// Skip duplicate (whether identical or different)
 				continue
 			}
-			seen[e.UUID] = e.Name
-			result = append(result, e)
+			seen[e.UUID] = e
+			result = append(result, templateEntry{UUID: e.UUID, Name: e.Name})
 		}
 
+		report.Categories[bleType] = len(result)
+		report.DuplicatesSkipped[bleType] = len(normalized) - len(result)
 		return result
 	}
 
 	data := templateData{
-		Timestamp:             timestamp,
-		ServiceURL:            serviceURL,
-		CharacteristicURL:     characteristicURL,
-		DescriptorURL:         descriptorURL,
-		VendorURL:             vendorURL,
-		BleakURL:              bleakURL,
-		ServiceEntries:        convertEntries(services, Service),
-		CharacteristicEntries: convertEntries(characteristics, Characteristic),
-		DescriptorEntries:     convertEntries(descriptors, Descriptor),
-		VendorEntries:         convertEntries(vendors, Vendor),
-		UnitEntries:           convertEntries(units, Unit),
-		AppearanceEntries:     convertEntries(appearances, Appearance),
-		BleakEntries:          convertEntries(bleakEntries, Other),
+		Timestamp:                    timestamp,
+		ServiceURL:                   serviceURL,
+		CharacteristicURL:            characteristicURL,
+		DescriptorURL:                descriptorURL,
+		VendorURL:                    vendorURL,
+		BleakURL:                     bleakURL,
+		ServiceEntries:               convertEntries(services, Service),
+		CharacteristicEntries:        convertEntries(characteristics, Characteristic),
+		DescriptorEntries:            convertEntries(descriptors, Descriptor),
+		VendorEntries:                convertEntries(vendors, Vendor),
+		UnitEntries:                  convertEntries(units, Unit),
+		AppearanceCategoryEntries:    convertEntries(filterEntries(appearances, AppearanceCategory), AppearanceCategory),
+		AppearanceSubcategoryEntries: convertEntries(filterEntries(appearances, Appearance), Appearance),
+		BleakEntries:                 convertEntries(bleakEntries, Other),
 	}
 
 	if err := tmpl.Execute(f, data); err != nil {
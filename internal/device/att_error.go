@@ -0,0 +1,118 @@
+package device
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ATTErrorCode is an Attribute Protocol error code (Core Spec Vol 3, Part F, 3.4.1.1)
+type ATTErrorCode uint8
+
+// ATT error codes returned by the remote GATT server
+const (
+	ATTErrInvalidHandle               ATTErrorCode = 0x01
+	ATTErrReadNotPermitted            ATTErrorCode = 0x02
+	ATTErrWriteNotPermitted           ATTErrorCode = 0x03
+	ATTErrInvalidPDU                  ATTErrorCode = 0x04
+	ATTErrInsufficientAuthentication  ATTErrorCode = 0x05
+	ATTErrRequestNotSupported         ATTErrorCode = 0x06
+	ATTErrInvalidOffset               ATTErrorCode = 0x07
+	ATTErrInsufficientAuthorization   ATTErrorCode = 0x08
+	ATTErrPrepareQueueFull            ATTErrorCode = 0x09
+	ATTErrAttributeNotFound           ATTErrorCode = 0x0A
+	ATTErrAttributeNotLong            ATTErrorCode = 0x0B
+	ATTErrInsufficientEncryptionKey   ATTErrorCode = 0x0C
+	ATTErrInvalidAttributeValueLength ATTErrorCode = 0x0D
+	ATTErrUnlikely                    ATTErrorCode = 0x0E
+	ATTErrInsufficientEncryption      ATTErrorCode = 0x0F
+	ATTErrUnsupportedGroupType        ATTErrorCode = 0x10
+	ATTErrInsufficientResources       ATTErrorCode = 0x11
+	ATTErrDatabaseOutOfSync           ATTErrorCode = 0x12
+	ATTErrValueNotAllowed             ATTErrorCode = 0x13
+	ATTErrWriteRequestRejected        ATTErrorCode = 0xFC
+	ATTErrCCCDImproperlyConfigured    ATTErrorCode = 0xFD
+	ATTErrProcedureInProgress         ATTErrorCode = 0xFE
+	ATTErrOutOfRange                  ATTErrorCode = 0xFF
+)
+
+// attErrorMessages maps ATT error codes to human-readable reasons
+var attErrorMessages = map[ATTErrorCode]string{
+	ATTErrInvalidHandle:               "invalid handle",
+	ATTErrReadNotPermitted:            "read not permitted",
+	ATTErrWriteNotPermitted:           "write not permitted",
+	ATTErrInvalidPDU:                  "invalid PDU",
+	ATTErrInsufficientAuthentication:  "insufficient authentication",
+	ATTErrRequestNotSupported:         "request not supported",
+	ATTErrInvalidOffset:               "invalid offset",
+	ATTErrInsufficientAuthorization:   "insufficient authorization",
+	ATTErrPrepareQueueFull:            "prepare queue full",
+	ATTErrAttributeNotFound:           "attribute not found",
+	ATTErrAttributeNotLong:            "attribute not long",
+	ATTErrInsufficientEncryptionKey:   "insufficient encryption key size",
+	ATTErrInvalidAttributeValueLength: "invalid attribute value length",
+	ATTErrUnlikely:                    "unlikely error",
+	ATTErrInsufficientEncryption:      "insufficient encryption",
+	ATTErrUnsupportedGroupType:        "unsupported group type",
+	ATTErrInsufficientResources:       "insufficient resources",
+	ATTErrDatabaseOutOfSync:           "database out of sync",
+	ATTErrValueNotAllowed:             "value not allowed",
+	ATTErrWriteRequestRejected:        "write request rejected",
+	ATTErrCCCDImproperlyConfigured:    "client characteristic configuration descriptor improperly configured",
+	ATTErrProcedureInProgress:         "procedure already in progress",
+	ATTErrOutOfRange:                  "out of range",
+}
+
+// String returns the decoded reason for the ATT error code
+func (c ATTErrorCode) String() string {
+	if msg, ok := attErrorMessages[c]; ok {
+		return msg
+	}
+	if c >= 0x80 && c <= 0x9F {
+		return "application error"
+	}
+	return "unknown ATT error"
+}
+
+// ATTError represents an Attribute Protocol error returned by the remote device
+// for a read, write, or subscribe request
+type ATTError struct {
+	Code ATTErrorCode
+	Err  error // Underlying platform error (optional)
+}
+
+// Error implements the error interface
+func (e *ATTError) Error() string {
+	if e == nil {
+		return "<nil>"
+	}
+	return fmt.Sprintf("%s (ATT error 0x%02X)", e.Code, uint8(e.Code))
+}
+
+// Unwrap returns the underlying platform error
+func (e *ATTError) Unwrap() error {
+	if e == nil {
+		return nil
+	}
+	return e.Err
+}
+
+// Is allows errors.Is to compare ATTError values by Code
+func (e *ATTError) Is(target error) bool {
+	if e == nil {
+		return false
+	}
+	t, ok := target.(*ATTError)
+	if !ok {
+		return false
+	}
+	return e.Code == t.Code
+}
+
+// IsATTError reports whether err is an ATTError with the given code
+func IsATTError(err error, code ATTErrorCode) bool {
+	var aerr *ATTError
+	if errors.As(err, &aerr) {
+		return aerr.Code == code
+	}
+	return false
+}
@@ -0,0 +1,55 @@
+package device
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// ----------------------------
+// ATTError Tests
+// ----------------------------
+
+func TestATTError_Error(t *testing.T) {
+	// GOAL: Verify ATTError renders the decoded ATT reason together with the numeric code
+	//
+	// TEST SCENARIO: Format ATTError for known, application, and unknown codes → message matches expected text
+
+	tests := []struct {
+		name     string
+		code     ATTErrorCode
+		expected string
+	}{
+		{name: "read not permitted", code: ATTErrReadNotPermitted, expected: "read not permitted (ATT error 0x02)"},
+		{name: "insufficient authentication", code: ATTErrInsufficientAuthentication, expected: "insufficient authentication (ATT error 0x05)"},
+		{name: "insufficient encryption", code: ATTErrInsufficientEncryption, expected: "insufficient encryption (ATT error 0x0F)"},
+		{name: "application error", code: 0x80, expected: "application error (ATT error 0x80)"},
+		{name: "unknown code", code: 0x50, expected: "unknown ATT error (ATT error 0x50)"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := &ATTError{Code: tt.code}
+			assert.Equal(t, tt.expected, err.Error())
+		})
+	}
+}
+
+func TestATTError_Matching(t *testing.T) {
+	// GOAL: Verify wrapped ATTErrors can be matched by code and unwrap to the platform error
+	//
+	// TEST SCENARIO: Wrap ATTError in fmt.Errorf → errors.Is/IsATTError match by code → platform error reachable via Unwrap
+
+	platformErr := errors.New("Authentication is insufficient.")
+	wrapped := fmt.Errorf("failed to read characteristic 2a19: %w", &ATTError{Code: ATTErrInsufficientAuthentication, Err: platformErr})
+
+	assert.True(t, errors.Is(wrapped, &ATTError{Code: ATTErrInsufficientAuthentication}), "errors.Is MUST match by code")
+	assert.False(t, errors.Is(wrapped, &ATTError{Code: ATTErrReadNotPermitted}), "errors.Is MUST NOT match different code")
+	assert.False(t, errors.Is(wrapped, ErrNotConnected), "ATTError MUST NOT match connection errors")
+	assert.True(t, IsATTError(wrapped, ATTErrInsufficientAuthentication), "IsATTError MUST match by code")
+	assert.False(t, IsATTError(errors.New("plain"), ATTErrInsufficientAuthentication), "IsATTError MUST NOT match plain errors")
+	assert.True(t, errors.Is(wrapped, platformErr), "underlying platform error MUST be reachable")
+	assert.Contains(t, wrapped.Error(), "insufficient authentication (ATT error 0x05)", "message MUST include decoded ATT reason")
+}
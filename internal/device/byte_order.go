@@ -0,0 +1,47 @@
+package device
+
+import "encoding/binary"
+
+// Bounds-checked integer readers shared by characteristic and descriptor parsers.
+// Each reads at offset and reports false instead of panicking if the buffer is too short,
+// so parsers can degrade to nil on truncated values.
+
+// readUint16LE reads a little-endian uint16 (the GATT default byte order) at offset
+func readUint16LE(b []byte, offset int) (uint16, bool) {
+	if offset < 0 || len(b) < offset+2 {
+		return 0, false
+	}
+	return binary.LittleEndian.Uint16(b[offset:]), true
+}
+
+// readUint16BE reads a big-endian uint16 at offset
+func readUint16BE(b []byte, offset int) (uint16, bool) {
+	if offset < 0 || len(b) < offset+2 {
+		return 0, false
+	}
+	return binary.BigEndian.Uint16(b[offset:]), true
+}
+
+// readUint24LE reads a little-endian uint24 at offset
+func readUint24LE(b []byte, offset int) (uint32, bool) {
+	if offset < 0 || len(b) < offset+3 {
+		return 0, false
+	}
+	return uint32(b[offset]) | uint32(b[offset+1])<<8 | uint32(b[offset+2])<<16, true
+}
+
+// readUint32LE reads a little-endian uint32 at offset
+func readUint32LE(b []byte, offset int) (uint32, bool) {
+	if offset < 0 || len(b) < offset+4 {
+		return 0, false
+	}
+	return binary.LittleEndian.Uint32(b[offset:]), true
+}
+
+// readUint32BE reads a big-endian uint32 at offset
+func readUint32BE(b []byte, offset int) (uint32, bool) {
+	if offset < 0 || len(b) < offset+4 {
+		return 0, false
+	}
+	return binary.BigEndian.Uint32(b[offset:]), true
+}
@@ -0,0 +1,45 @@
+package device
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// GOAL: Verify bounds-checked integer readers decode both byte orders and reject short buffers
+//
+// TEST SCENARIO: Read 16/24/32-bit values at offsets → LE/BE decoded correctly → truncated or negative offsets report !ok
+func TestReadUintHelpers(t *testing.T) {
+	data := []byte{0x00, 0x01, 0x02, 0x03, 0x04}
+
+	v16, ok := readUint16LE(data, 1)
+	assert.True(t, ok)
+	assert.Equal(t, uint16(0x0201), v16)
+
+	v16, ok = readUint16BE(data, 1)
+	assert.True(t, ok)
+	assert.Equal(t, uint16(0x0102), v16)
+
+	v24, ok := readUint24LE(data, 2)
+	assert.True(t, ok)
+	assert.Equal(t, uint32(0x040302), v24)
+
+	v32, ok := readUint32LE(data, 1)
+	assert.True(t, ok)
+	assert.Equal(t, uint32(0x04030201), v32)
+
+	v32, ok = readUint32BE(data, 1)
+	assert.True(t, ok)
+	assert.Equal(t, uint32(0x01020304), v32)
+
+	_, ok = readUint16LE(data, 4)
+	assert.False(t, ok, "reading past the end MUST fail")
+	_, ok = readUint16BE(nil, 0)
+	assert.False(t, ok, "empty buffer MUST fail")
+	_, ok = readUint24LE(data, 3)
+	assert.False(t, ok, "reading past the end MUST fail")
+	_, ok = readUint32LE(data, 2)
+	assert.False(t, ok, "reading past the end MUST fail")
+	_, ok = readUint32BE(data, -1)
+	assert.False(t, ok, "negative offset MUST fail")
+}
@@ -5,6 +5,7 @@
 package device_test
 
 import (
+	"context"
 	"testing"
 	"time"
 
@@ -121,6 +122,35 @@ func (suite *CharacteristicTestSuite) TestCharacteristicRead() {
 		suite.Assert().Contains(err.Error(), "2a41", "error message MUST contain characteristic UUID")
 		suite.Assert().Contains(err.Error(), "500ms", "error message MUST contain timeout duration")
 	})
+
+	suite.Run("context-aware read", func() {
+		// GOAL: Verify ReadCtx reads like Read, reports an expired deadline as ErrTimeout and stops on cancellation
+		//
+		// TEST SCENARIO: ReadCtx without deadline → data returned → 1s delayed read with 200ms deadline → ErrTimeout →
+		// delayed read canceled after 100ms → context.Canceled returned well before the delay
+
+		char, err := suite.connection.GetCharacteristic("180f", "2a19")
+		suite.Require().NoError(err, "MUST find characteristic")
+		data, err := char.ReadCtx(context.Background())
+		suite.Require().NoError(err, "MUST read successfully")
+		suite.Assert().Equal([]byte{85}, data, "data MUST match expected value")
+
+		delayed, err := suite.connection.GetCharacteristic("180d", "2a41")
+		suite.Require().NoError(err, "MUST find characteristic")
+
+		ctx, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+		defer cancel()
+		_, err = delayed.ReadCtx(ctx)
+		suite.Assert().ErrorIs(err, device.ErrTimeout, "expired deadline MUST wrap device.ErrTimeout")
+		suite.Assert().Contains(err.Error(), "2a41", "error message MUST contain characteristic UUID")
+
+		ctx, cancel = context.WithCancel(context.Background())
+		time.AfterFunc(100*time.Millisecond, cancel)
+		start := time.Now()
+		_, err = delayed.ReadCtx(ctx)
+		suite.Assert().ErrorIs(err, context.Canceled, "cancellation MUST abort the read")
+		suite.Assert().Less(time.Since(start), 900*time.Millisecond, "canceled read MUST NOT wait for the device")
+	})
 }
 
 func (suite *CharacteristicTestSuite) TestCharacteristicWrite() {
@@ -269,6 +299,33 @@ func (suite *CharacteristicTestSuite) TestCharacteristicWrite() {
 		suite.Assert().Contains(err.Error(), "2a42", "error message MUST contain characteristic UUID")
 		suite.Assert().Contains(err.Error(), "500ms", "error message MUST contain timeout duration")
 	})
+
+	suite.Run("context-aware write", func() {
+		// GOAL: Verify WriteCtx writes like Write, reports an expired deadline as ErrTimeout and stops on cancellation
+		//
+		// TEST SCENARIO: WriteCtx without deadline → succeeds → 1s delayed write with 200ms deadline → ErrTimeout →
+		// delayed write canceled after 100ms → context.Canceled returned well before the delay
+
+		char, err := suite.connection.GetCharacteristic("180d", "2a39")
+		suite.Require().NoError(err, "MUST find characteristic")
+		suite.Assert().NoError(char.WriteCtx(context.Background(), []byte{0x01}, true), "MUST write successfully")
+
+		delayed, err := suite.connection.GetCharacteristic("180d", "2a42")
+		suite.Require().NoError(err, "MUST find characteristic")
+
+		ctx, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+		defer cancel()
+		err = delayed.WriteCtx(ctx, []byte{0x01}, true)
+		suite.Assert().ErrorIs(err, device.ErrTimeout, "expired deadline MUST wrap device.ErrTimeout")
+		suite.Assert().Contains(err.Error(), "2a42", "error message MUST contain characteristic UUID")
+
+		ctx, cancel = context.WithCancel(context.Background())
+		time.AfterFunc(100*time.Millisecond, cancel)
+		start := time.Now()
+		err = delayed.WriteCtx(ctx, []byte{0x01}, true)
+		suite.Assert().ErrorIs(err, context.Canceled, "cancellation MUST abort the write")
+		suite.Assert().Less(time.Since(start), 900*time.Millisecond, "canceled write MUST NOT wait for the device")
+	})
 }
 
 func (suite *CharacteristicTestSuite) TestCharacteristicReadWrite() {
@@ -471,6 +528,8 @@ func (suite *CharacteristicTestSuite) TestRequiresAuthentication() {
 
 		requiresAuth := char.RequiresAuthentication()
 		suite.Assert().True(requiresAuth, "RequiresAuthentication() MUST return true for AuthenticatedSignedWrites property")
+		suite.Assert().True(char.WriteRequiresAuthentication(), "WriteRequiresAuthentication() MUST return true for AuthenticatedSignedWrites property")
+		suite.Assert().False(char.ReadRequiresAuthentication(), "ReadRequiresAuthentication() MUST NOT be affected by AuthenticatedSignedWrites")
 	})
 
 	suite.Run("no properties exposed indicates macOS hidden due to encryption requirement", func() {
@@ -495,6 +554,8 @@ func (suite *CharacteristicTestSuite) TestRequiresAuthentication() {
 
 		requiresAuth := char.RequiresAuthentication()
 		suite.Assert().True(requiresAuth, "RequiresAuthentication() MUST return true when properties hidden (macOS pairing required)")
+		suite.Assert().True(char.ReadRequiresAuthentication(), "ReadRequiresAuthentication() MUST return true when properties hidden")
+		suite.Assert().True(char.WriteRequiresAuthentication(), "WriteRequiresAuthentication() MUST return true when properties hidden")
 	})
 
 	suite.Run("normal read characteristic does not require authentication", func() {
@@ -519,6 +580,8 @@ func (suite *CharacteristicTestSuite) TestRequiresAuthentication() {
 
 		requiresAuth := char.RequiresAuthentication()
 		suite.Assert().False(requiresAuth, "RequiresAuthentication() MUST return false for read/write characteristic")
+		suite.Assert().False(char.ReadRequiresAuthentication(), "ReadRequiresAuthentication() MUST return false for read/write characteristic")
+		suite.Assert().False(char.WriteRequiresAuthentication(), "WriteRequiresAuthentication() MUST return false for read/write characteristic")
 	})
 
 	suite.Run("notify characteristic does not require authentication", func() {
@@ -569,6 +632,19 @@ func (suite *CharacteristicTestSuite) TestWellKnownCharacteristicParsers() {
 			suite.Assert().Equal("Phone", parsed, "ParseValue() MUST return 'Phone' for 0x0040")
 		})
 
+		suite.Run("subcategory value 0x0341 returns category and subcategory", func() {
+			// GOAL: Verify Appearance parser resolves subcategories from the generated appearance table
+			//
+			// TEST SCENARIO: Parse 0x0341 → "Heart Rate Sensor: Heart Rate Belt"
+
+			char, err := suite.connection.GetCharacteristic("1800", "2a01")
+			suite.Require().NoError(err, "MUST find Appearance characteristic")
+
+			parsed, err := char.ParseValue([]byte{0x41, 0x03})
+			suite.Assert().NoError(err, "ParseValue() MUST succeed for subcategory value")
+			suite.Assert().Equal("Heart Rate Sensor: Heart Rate Belt", parsed, "ParseValue() MUST return category and subcategory")
+		})
+
 		suite.Run("unknown value 0xFFFF returns nil gracefully", func() {
 			// GOAL: Verify Appearance parser gracefully handles unknown appearance codes
 			//
@@ -0,0 +1,51 @@
+package device
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// maxConnectRetryBackoff caps the exponential delay between connection attempts
+const maxConnectRetryBackoff = 30 * time.Second
+
+// ConnectRetryPolicy configures retries of the initial connection to a device.
+// It only covers establishing the connection, not reconnecting after the link is lost.
+type ConnectRetryPolicy struct {
+	Retries int           // Attempts after the first failed one (0 = no retry)
+	Backoff time.Duration // Delay before the first retry, doubled after each failed retry (capped at 30s)
+
+	// OnRetry, if set, is called after a failed attempt that will be retried after delay
+	OnRetry func(attempt int, err error, delay time.Duration)
+}
+
+// ConnectWithRetry connects dev, retrying failed attempts according to policy.
+// Failures that a retry cannot fix (already connected, Bluetooth off, ctx done) are returned immediately.
+// Returns the error of the last attempt.
+func ConnectWithRetry(ctx context.Context, dev Device, opts *ConnectOptions, policy ConnectRetryPolicy) error {
+	delay := policy.Backoff
+	for attempt := 1; ; attempt++ {
+		err := dev.Connect(ctx, opts)
+		if err == nil || attempt > policy.Retries || !isRetryableConnectError(ctx, err) {
+			return err
+		}
+
+		if policy.OnRetry != nil {
+			policy.OnRetry(attempt, err, delay)
+		}
+		select {
+		case <-time.After(delay):
+		case <-ctx.Done():
+			return err
+		}
+		delay = min(delay*2, maxConnectRetryBackoff)
+	}
+}
+
+// isRetryableConnectError reports whether another connection attempt may succeed
+func isRetryableConnectError(ctx context.Context, err error) bool {
+	if ctx.Err() != nil {
+		return false
+	}
+	return !errors.Is(err, ErrAlreadyConnected) && !errors.Is(err, ErrBluetoothOff) && !errors.Is(err, context.Canceled)
+}
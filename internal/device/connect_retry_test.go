@@ -0,0 +1,84 @@
+package device
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// flakyDevice fails the first failures Connect calls with err
+type flakyDevice struct {
+	Device
+	failures int
+	err      error
+	calls    int
+}
+
+func (d *flakyDevice) Connect(context.Context, *ConnectOptions) error {
+	d.calls++
+	if d.calls <= d.failures {
+		return d.err
+	}
+	return nil
+}
+
+func TestConnectWithRetry(t *testing.T) {
+	// GOAL: Verify the initial connection is retried with exponential backoff, except for unrecoverable failures
+	//
+	// TEST SCENARIO: Transient failures within retries → connected with doubled delays; too many failures → last error;
+	// Bluetooth off → no retry
+
+	t.Run("succeeds within retries", func(t *testing.T) {
+		dev := &flakyDevice{failures: 2, err: errors.New("connection refused")}
+		var delays []time.Duration
+		err := ConnectWithRetry(context.Background(), dev, &ConnectOptions{}, ConnectRetryPolicy{
+			Retries: 3,
+			Backoff: time.Millisecond,
+			OnRetry: func(attempt int, err error, delay time.Duration) { delays = append(delays, delay) },
+		})
+
+		assert.NoError(t, err)
+		assert.Equal(t, 3, dev.calls)
+		assert.Equal(t, []time.Duration{time.Millisecond, 2 * time.Millisecond}, delays, "backoff MUST double after each retry")
+	})
+
+	t.Run("gives up after retries", func(t *testing.T) {
+		dev := &flakyDevice{failures: 5, err: errors.New("connection refused")}
+		err := ConnectWithRetry(context.Background(), dev, &ConnectOptions{}, ConnectRetryPolicy{Retries: 2, Backoff: time.Millisecond})
+
+		assert.EqualError(t, err, "connection refused")
+		assert.Equal(t, 3, dev.calls, "MUST make 1 attempt plus 2 retries")
+	})
+
+	t.Run("no retries by default", func(t *testing.T) {
+		dev := &flakyDevice{failures: 1, err: errors.New("connection refused")}
+		err := ConnectWithRetry(context.Background(), dev, &ConnectOptions{}, ConnectRetryPolicy{})
+
+		assert.Error(t, err)
+		assert.Equal(t, 1, dev.calls)
+	})
+
+	t.Run("unrecoverable errors are not retried", func(t *testing.T) {
+		dev := &flakyDevice{failures: 1, err: ErrBluetoothOff}
+		err := ConnectWithRetry(context.Background(), dev, &ConnectOptions{}, ConnectRetryPolicy{Retries: 3, Backoff: time.Millisecond})
+
+		assert.ErrorIs(t, err, ErrBluetoothOff)
+		assert.Equal(t, 1, dev.calls)
+	})
+
+	t.Run("cancellation stops retrying", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		dev := &flakyDevice{failures: 5, err: errors.New("connection refused")}
+		err := ConnectWithRetry(ctx, dev, &ConnectOptions{}, ConnectRetryPolicy{
+			Retries: 3,
+			Backoff: time.Hour,
+			OnRetry: func(int, error, time.Duration) { cancel() },
+		})
+
+		assert.EqualError(t, err, "connection refused")
+		assert.Equal(t, 1, dev.calls, "MUST NOT retry once ctx is done")
+	})
+}
@@ -3,11 +3,21 @@
 package device_test
 
 import (
+	"bytes"
 	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
 
 	"github.com/srg/blim/internal/device"
+	goble "github.com/srg/blim/internal/device/go-ble"
+	"github.com/srg/blim/internal/devicefactory"
+	"github.com/srg/blim/internal/testutils"
 	"github.com/stretchr/testify/suite"
 )
 
@@ -71,12 +81,19 @@ func (suite *ConnectionTestSuite) TestConnectionServices() {
 		svc1, err1 := suite.connection.GetService("180f")
 		svc2, err2 := suite.connection.GetService("180F")
 		svc3, err3 := suite.connection.GetService("0000180f-0000-1000-8000-00805f9b34fb")
+		svc4, err4 := suite.connection.GetService("0000180F")
 
 		suite.Assert().NoError(err1, "lowercase UUID MUST work")
 		suite.Assert().NoError(err2, "uppercase UUID MUST work")
 		suite.Assert().NoError(err3, "full UUID MUST work")
+		suite.Assert().NoError(err4, "32-bit UUID alias MUST work")
 		suite.Assert().Equal(svc1.UUID(), svc2.UUID(), "UUIDs MUST match")
 		suite.Assert().Equal(svc1.UUID(), svc3.UUID(), "UUIDs MUST match")
+		suite.Assert().Equal(svc1.UUID(), svc4.UUID(), "UUIDs MUST match")
+
+		char, err := suite.connection.GetCharacteristic("0000180f", "00002A19")
+		suite.Assert().NoError(err, "32-bit UUID aliases MUST resolve characteristics")
+		suite.Assert().Equal("2a19", char.UUID(), "characteristic UUID MUST be normalized")
 	})
 }
 
@@ -97,6 +114,39 @@ func (suite *ConnectionTestSuite) TestConnectionCharacteristics() {
 		suite.Assert().Equal("2a19", char.UUID(), "characteristic UUID MUST match")
 	})
 
+	suite.Run("get characteristic with case and dash variants", func() {
+		// GOAL: Verify GetCharacteristic() tolerates case, dashes and full 128-bit UUIDs
+		//
+		// TEST SCENARIO: Request characteristic with UUID variants → same characteristic returned for each
+
+		variants := []string{"2A37", "2a37", "0x2A37", "00002a37-0000-1000-8000-00805f9b34fb", "00002A3700001000800000805F9B34FB"}
+		for _, v := range variants {
+			char, err := suite.connection.GetCharacteristic("180D", v)
+			suite.Assert().NoError(err, "MUST find characteristic by %q", v)
+			if char != nil {
+				suite.Assert().Equal("2a37", char.UUID(), "characteristic UUID MUST match for %q", v)
+			}
+		}
+	})
+
+	suite.Run("get characteristic by known name", func() {
+		// GOAL: Verify GetCharacteristic() accepts a known characteristic name as lookup key
+		//
+		// TEST SCENARIO: Request characteristic by name in various cases → characteristic returned → UUID matches
+
+		for _, name := range []string{"Heart Rate Measurement", "heart rate measurement"} {
+			char, err := suite.connection.GetCharacteristic("180d", name)
+			suite.Assert().NoError(err, "MUST find characteristic by name %q", name)
+			if char != nil {
+				suite.Assert().Equal("2a37", char.UUID(), "characteristic UUID MUST match for %q", name)
+			}
+		}
+
+		_, err := suite.connection.GetCharacteristic("180d", "Battery Level")
+		var notFoundErr *device.NotFoundError
+		suite.Assert().ErrorAs(err, &notFoundErr, "name of a characteristic from another service MUST not resolve")
+	})
+
 	suite.Run("characteristic not found in service", func() {
 		// GOAL: Verify GetCharacteristic() returns NotFoundError for non-existent characteristic
 		//
@@ -166,7 +216,7 @@ func (suite *ConnectionTestSuite) TestConnectionSubscriptionValidation() {
 				Service:         "180f",
 				Characteristics: []string{"2a19"},
 			},
-		}, device.StreamEveryUpdate, 0, func(record *device.Record) {
+		}, device.StreamEveryUpdate, device.StreamOptions{}, func(record *device.Record) {
 			suite.Fail("callback MUST NOT be invoked when validation fails")
 		})
 
@@ -188,7 +238,7 @@ func (suite *ConnectionTestSuite) TestConnectionSubscriptionValidation() {
 				Service: "180d",
 				// Empty Characteristics means subscribe to all in service
 			},
-		}, device.StreamEveryUpdate, 0, func(record *device.Record) {
+		}, device.StreamEveryUpdate, device.StreamOptions{}, func(record *device.Record) {
 			suite.Fail("callback MUST NOT be invoked when validation fails")
 		})
 
@@ -208,7 +258,7 @@ func (suite *ConnectionTestSuite) TestConnectionSubscriptionValidation() {
 				Characteristics: []string{"2a37"},
 				Indicate:        false, // Notify mode (default)
 			},
-		}, device.StreamEveryUpdate, 0, func(record *device.Record) {
+		}, device.StreamEveryUpdate, device.StreamOptions{}, func(record *device.Record) {
 			// Callback receives notifications
 		})
 
@@ -226,7 +276,7 @@ func (suite *ConnectionTestSuite) TestConnectionSubscriptionValidation() {
 				Characteristics: []string{"2a3a"}, // Indicate-only characteristic
 				Indicate:        true,             // Indicate mode
 			},
-		}, device.StreamEveryUpdate, 0, func(record *device.Record) {
+		}, device.StreamEveryUpdate, device.StreamOptions{}, func(record *device.Record) {
 			// Callback receives indications
 		})
 
@@ -244,7 +294,7 @@ func (suite *ConnectionTestSuite) TestConnectionSubscriptionValidation() {
 				Characteristics: []string{"2a37"}, // Notify-only characteristic
 				Indicate:        true,             // Request Indicate mode
 			},
-		}, device.StreamEveryUpdate, 0, func(record *device.Record) {
+		}, device.StreamEveryUpdate, device.StreamOptions{}, func(record *device.Record) {
 			suite.Fail("callback MUST NOT be invoked when subscription fails")
 		})
 
@@ -265,7 +315,7 @@ func (suite *ConnectionTestSuite) TestConnectionSubscriptionValidation() {
 				Characteristics: []string{"2a3a"}, // Indicate-only characteristic
 				Indicate:        false,            // Notify mode (default)
 			},
-		}, device.StreamEveryUpdate, 0, func(record *device.Record) {
+		}, device.StreamEveryUpdate, device.StreamOptions{}, func(record *device.Record) {
 			suite.Fail("callback MUST NOT be invoked when subscription fails")
 		})
 
@@ -286,7 +336,7 @@ func (suite *ConnectionTestSuite) TestConnectionSubscriptionValidation() {
 				Characteristics: []string{"2a19"}, // Read-only characteristic (Battery Level)
 				Indicate:        true,             // Request Indicate mode
 			},
-		}, device.StreamEveryUpdate, 0, func(record *device.Record) {
+		}, device.StreamEveryUpdate, device.StreamOptions{}, func(record *device.Record) {
 			suite.Fail("callback MUST NOT be invoked when subscription fails")
 		})
 
@@ -307,7 +357,7 @@ func (suite *ConnectionTestSuite) TestConnectionSubscriptionValidation() {
 				Characteristics: []string{"2a3b"}, // Both notify and indicate
 				Indicate:        true,             // Explicitly select Indicate
 			},
-		}, device.StreamEveryUpdate, 0, func(record *device.Record) {
+		}, device.StreamEveryUpdate, device.StreamOptions{}, func(record *device.Record) {
 			// Callback receives indications
 		})
 
@@ -325,7 +375,7 @@ func (suite *ConnectionTestSuite) TestConnectionSubscriptionValidation() {
 				Characteristics: []string{"2a3b"}, // Both notify and indicate
 				Indicate:        false,            // Notify mode (default)
 			},
-		}, device.StreamEveryUpdate, 0, func(record *device.Record) {
+		}, device.StreamEveryUpdate, device.StreamOptions{}, func(record *device.Record) {
 			// Callback receives notifications
 		})
 
@@ -344,7 +394,7 @@ func (suite *ConnectionTestSuite) TestConnectionSubscriptionValidation() {
 				Service:         "ffee",
 				Characteristics: []string{"ffef"},
 			},
-		}, device.StreamEveryUpdate, 0, func(record *device.Record) {
+		}, device.StreamEveryUpdate, device.StreamOptions{}, func(record *device.Record) {
 			suite.Fail("callback MUST NOT be invoked when validation fails")
 		})
 
@@ -364,7 +414,7 @@ func (suite *ConnectionTestSuite) TestConnectionSubscriptionValidation() {
 				Service:         "180d",
 				Characteristics: []string{"2aff"},
 			},
-		}, device.StreamEveryUpdate, 0, func(record *device.Record) {
+		}, device.StreamEveryUpdate, device.StreamOptions{}, func(record *device.Record) {
 			suite.Fail("callback MUST NOT be invoked when validation fails")
 		})
 
@@ -414,7 +464,7 @@ func (suite *ConnectionTestSuite) TestConnectionErrors() {
 				Service:         "180d",
 				Characteristics: []string{"2a37"},
 			},
-		}, device.StreamEveryUpdate, 0, func(record *device.Record) {
+		}, device.StreamEveryUpdate, device.StreamOptions{}, func(record *device.Record) {
 			suite.Fail("callback MUST NOT be invoked when not connected")
 		})
 
@@ -507,6 +557,1307 @@ func (suite *ConnectionTestSuite) TestGracefulDisconnect() {
 	})
 }
 
+func (suite *ConnectionTestSuite) TestFlatCharacteristicLookup() {
+	// GOAL: Verify Characteristics() and FindCharacteristicByUUID() work across service boundaries
+	//
+	// TEST SCENARIO: Same characteristic UUID in two services → Characteristics() lists both → FindCharacteristicByUUID() returns first match → unknown UUID returns NotFoundError
+
+	suite.WithPeripheral().
+		WithService("1234").
+		WithCharacteristic("2a19", "read", []byte{0x2A})
+
+	err := suite.device.Disconnect()
+	suite.Require().NoError(err, "disconnect MUST succeed")
+	suite.ensureConnected()
+
+	conn := suite.device.GetConnection()
+	suite.Require().NotNil(conn, "connection MUST exist")
+
+	suite.Run("Characteristics() flattens all services", func() {
+		// GOAL: Verify Characteristics() returns every characteristic of every service
+		//
+		// TEST SCENARIO: Flatten characteristics per service → Characteristics() matches in order → each reports its service UUID
+
+		var expected []device.Characteristic
+		var services []string
+		for _, svc := range conn.Services() {
+			for _, char := range svc.GetCharacteristics() {
+				expected = append(expected, char)
+				services = append(services, svc.UUID())
+			}
+		}
+
+		chars := conn.Characteristics()
+		suite.Require().Equal(expected, chars, "Characteristics() MUST include all characteristics across services in service order")
+		for i, char := range chars {
+			suite.Assert().Equal(services[i], char.ServiceUUID(), "characteristic %s MUST report its service UUID", char.UUID())
+		}
+	})
+
+	suite.Run("FindCharacteristicByUUID() returns first match", func() {
+		// GOAL: Verify FindCharacteristicByUUID() finds a characteristic regardless of service
+		//
+		// TEST SCENARIO: Lookup 2A19 (uppercase) → first match in service 1234 → value from that service
+
+		char, err := conn.FindCharacteristicByUUID("2A19")
+		suite.Require().NoError(err, "MUST find characteristic by UUID")
+		suite.Assert().Equal("2a19", char.UUID(), "UUID MUST be normalized")
+
+		expected, err := conn.GetCharacteristic("1234", "2a19")
+		suite.Require().NoError(err, "MUST find characteristic in service 1234")
+		suite.Assert().Same(expected, char, "first match MUST come from the lowest service UUID")
+	})
+
+	suite.Run("FindCharacteristicByUUID() unknown UUID", func() {
+		// GOAL: Verify FindCharacteristicByUUID() reports missing characteristics with NotFoundError
+		//
+		// TEST SCENARIO: Lookup unknown UUID → NotFoundError returned
+
+		_, err := conn.FindCharacteristicByUUID("dead")
+		var notFound *device.NotFoundError
+		suite.Assert().ErrorAs(err, &notFound, "error MUST be NotFoundError")
+	})
+}
+
+func (suite *ConnectionTestSuite) TestOnServicesChanged() {
+	// GOAL: Verify OnServicesChanged handlers fire on Service Changed (0x2A05) indications only
+	//
+	// TEST SCENARIO: Peripheral exposes 1801/2A05 → register two handlers → indicate 2A05 → both fire → other notification → no extra calls
+
+	suite.WithPeripheral().
+		WithService("1801").
+		WithCharacteristic("2A05", "indicate", []byte{0x01, 0x00, 0xFF, 0xFF})
+	suite.Require().NoError(suite.device.Disconnect(), "disconnect MUST succeed")
+	suite.ensureConnected()
+
+	bleConn, ok := suite.connection.(*goble.BLEConnection)
+	suite.Require().True(ok, "connection MUST be a *goble.BLEConnection")
+
+	var first, second atomic.Int32
+	suite.connection.OnServicesChanged(func() { first.Add(1) })
+	suite.connection.OnServicesChanged(func() { second.Add(1) })
+	suite.connection.OnServicesChanged(nil) // MUST be ignored
+
+	serviceChanged, err := suite.connection.GetCharacteristic("1801", "2a05")
+	suite.Require().NoError(err, "MUST find Service Changed characteristic")
+	bleConn.ProcessCharacteristicNotification(serviceChanged.(*goble.BLECharacteristic), []byte{0x01, 0x00, 0xFF, 0xFF})
+
+	suite.Assert().Equal(int32(1), first.Load(), "first handler MUST fire once")
+	suite.Assert().Equal(int32(1), second.Load(), "second handler MUST fire once")
+
+	hrm, err := suite.connection.GetCharacteristic("180d", "2a37")
+	suite.Require().NoError(err, "MUST find Heart Rate Measurement characteristic")
+	bleConn.ProcessCharacteristicNotification(hrm.(*goble.BLECharacteristic), []byte{0x00, 0x48})
+
+	suite.Assert().Equal(int32(1), first.Load(), "other notifications MUST NOT fire Service Changed handlers")
+}
+
+func (suite *ConnectionTestSuite) TestReplayNotifications() {
+	// GOAL: Verify recorded notifications are replayed through the notification path at original timing
+	//
+	// TEST SCENARIO: Record log with 50ms gap → replay → characteristic value updated → elapsed time preserved → unknown characteristic fails
+
+	writeLog := func(entries ...*device.NotificationLogEntry) *device.NotificationLogReader {
+		var buf bytes.Buffer
+		writer, err := device.NewNotificationLogWriter(&buf)
+		suite.Require().NoError(err, "log writer MUST be created")
+		for _, entry := range entries {
+			suite.Require().NoError(writer.Write(entry), "entry MUST be written")
+		}
+		suite.Require().NoError(writer.Flush(), "log MUST be flushed")
+
+		reader, err := device.NewNotificationLogReader(&buf)
+		suite.Require().NoError(err, "log reader MUST be created")
+		return reader
+	}
+
+	suite.Run("replays at original timing", func() {
+		// GOAL: Verify replay delivers notifications with the recorded spacing
+		//
+		// TEST SCENARIO: Two 2A37 notifications 50ms apart → ReplayNotifications() → count 2 → value is last payload → elapsed >= 50ms
+
+		bleConn, ok := suite.connection.(*goble.BLEConnection)
+		suite.Require().True(ok, "connection MUST be a *goble.BLEConnection")
+
+		log := writeLog(
+			&device.NotificationLogEntry{TsUs: 1_000_000, Service: "180d", Characteristic: "2a37", Data: []byte{0x00, 0x48}},
+			&device.NotificationLogEntry{TsUs: 1_050_000, Service: "180d", Characteristic: "2a37", Data: []byte{0x00, 0x50}},
+		)
+
+		start := time.Now()
+		count, err := bleConn.ReplayNotifications(context.Background(), log)
+		elapsed := time.Since(start)
+
+		suite.Require().NoError(err, "replay MUST succeed")
+		suite.Assert().Equal(2, count, "both notifications MUST be replayed")
+		suite.Assert().GreaterOrEqual(elapsed, 50*time.Millisecond, "replay MUST preserve original spacing")
+
+		char, err := suite.connection.GetCharacteristic("180d", "2a37")
+		suite.Require().NoError(err, "MUST find Heart Rate Measurement characteristic")
+		bleChar, ok := char.(*goble.BLECharacteristic)
+		suite.Require().True(ok, "characteristic MUST be a *goble.BLECharacteristic")
+		suite.Assert().Equal([]byte{0x00, 0x50}, bleChar.GetValue(), "characteristic value MUST be the last replayed payload")
+	})
+
+	suite.Run("unknown characteristic fails", func() {
+		// GOAL: Verify replay reports entries that do not match the connected device
+		//
+		// TEST SCENARIO: Entry for unknown characteristic → ReplayNotifications() → NotFoundError, nothing replayed
+
+		bleConn, ok := suite.connection.(*goble.BLEConnection)
+		suite.Require().True(ok, "connection MUST be a *goble.BLEConnection")
+
+		log := writeLog(&device.NotificationLogEntry{TsUs: 1, Service: "abcd", Characteristic: "dcba", Data: []byte{0x01}})
+
+		count, err := bleConn.ReplayNotifications(context.Background(), log)
+		var notFound *device.NotFoundError
+		suite.Assert().ErrorAs(err, &notFound, "error MUST be NotFoundError")
+		suite.Assert().Equal(0, count, "nothing MUST be replayed")
+	})
+}
+
+func (suite *ConnectionTestSuite) TestCallbackWorkers() {
+	// GOAL: Verify CallbackWorkers decouples slow callbacks while keeping per-characteristic order
+	//
+	// TEST SCENARIO: Connect with 4 workers → slow callback for 2A37 → 2A3B delivered while 2A37 is blocked → 2A37 values delivered in order
+
+	err := suite.device.Disconnect()
+	suite.Require().NoError(err, "disconnect MUST succeed")
+
+	suite.device = devicefactory.NewDevice("AA:BB:CC:DD:EE:FF", suite.Logger)
+	err = suite.device.Connect(context.Background(), &device.ConnectOptions{
+		ConnectTimeout:  5 * time.Second,
+		CallbackWorkers: 4,
+	})
+	suite.Require().NoError(err, "MUST connect successfully")
+	conn := suite.device.GetConnection()
+
+	var (
+		mu        sync.Mutex
+		hrmValues []byte
+	)
+	release := make(chan struct{})
+	otherDelivered := make(chan struct{}, 1)
+
+	err = conn.Subscribe([]*device.SubscribeOptions{
+		{Service: "180d", Characteristics: []string{"2a37", "2a3b"}},
+	}, device.StreamEveryUpdate, device.StreamOptions{}, func(record *device.Record) {
+		if v, ok := record.Values["2a37"]; ok {
+			<-release // Slow consumer for 2A37
+			mu.Lock()
+			hrmValues = append(hrmValues, v[0])
+			mu.Unlock()
+		}
+		if _, ok := record.Values["2a3b"]; ok {
+			otherDelivered <- struct{}{}
+		}
+	})
+	suite.Require().NoError(err, "subscription MUST succeed")
+
+	bleConn, ok := conn.(*goble.BLEConnection)
+	suite.Require().True(ok, "connection MUST be a *goble.BLEConnection")
+	hrm, err := conn.GetCharacteristic("180d", "2a37")
+	suite.Require().NoError(err, "MUST find 2A37")
+	other, err := conn.GetCharacteristic("180d", "2a3b")
+	suite.Require().NoError(err, "MUST find 2A3B")
+
+	for i := byte(1); i <= 3; i++ {
+		bleConn.ProcessCharacteristicNotification(hrm.(*goble.BLECharacteristic), []byte{i})
+		time.Sleep(10 * time.Millisecond)
+	}
+	bleConn.ProcessCharacteristicNotification(other.(*goble.BLECharacteristic), []byte{0x42})
+
+	select {
+	case <-otherDelivered:
+	case <-time.After(time.Second):
+		suite.Fail("2A3B MUST be delivered while the 2A37 callback is blocked")
+	}
+
+	close(release)
+	suite.Assert().Eventually(func() bool {
+		mu.Lock()
+		defer mu.Unlock()
+		return len(hrmValues) == 3
+	}, time.Second, 10*time.Millisecond, "all 2A37 records MUST be delivered")
+
+	mu.Lock()
+	suite.Assert().Equal([]byte{1, 2, 3}, hrmValues, "2A37 records MUST be delivered in order")
+	mu.Unlock()
+}
+
+func (suite *ConnectionTestSuite) TestNotificationRateLimit() {
+	// GOAL: Verify MaxNotificationRate limits callback dispatch across all subscriptions of a connection
+	//
+	// TEST SCENARIO: Connect with 1 notification/s → two subscriptions → burst of notifications → one record delivered → remaining counted per overflow policy
+
+	connectWithLimit := func(policy device.OverflowPolicy) device.Connection {
+		err := suite.device.Disconnect()
+		suite.Require().NoError(err, "disconnect MUST succeed")
+
+		suite.device = devicefactory.NewDevice("AA:BB:CC:DD:EE:FF", suite.Logger)
+		err = suite.device.Connect(context.Background(), &device.ConnectOptions{
+			ConnectTimeout:       5 * time.Second,
+			MaxNotificationRate:  1,
+			NotificationOverflow: policy,
+		})
+		suite.Require().NoError(err, "MUST connect successfully")
+		return suite.device.GetConnection()
+	}
+
+	burst := func(conn device.Connection) *atomic.Int32 {
+		var delivered atomic.Int32
+		for _, charUUID := range []string{"2a37", "2a3b"} {
+			err := conn.Subscribe([]*device.SubscribeOptions{
+				{Service: "180d", Characteristics: []string{charUUID}},
+			}, device.StreamEveryUpdate, device.StreamOptions{}, func(record *device.Record) {
+				delivered.Add(1)
+			})
+			suite.Require().NoError(err, "subscription MUST succeed")
+		}
+
+		bleConn, ok := conn.(*goble.BLEConnection)
+		suite.Require().True(ok, "connection MUST be a *goble.BLEConnection")
+		for i := 0; i < 3; i++ {
+			for _, charUUID := range []string{"2a37", "2a3b"} {
+				char, err := conn.GetCharacteristic("180d", charUUID)
+				suite.Require().NoError(err, "MUST find characteristic")
+				bleConn.ProcessCharacteristicNotification(char.(*goble.BLECharacteristic), []byte{byte(i)})
+			}
+			time.Sleep(20 * time.Millisecond)
+		}
+		time.Sleep(100 * time.Millisecond)
+		return &delivered
+	}
+
+	suite.Run("drop policy", func() {
+		// GOAL: Verify records over the limit are dropped and counted
+		//
+		// TEST SCENARIO: 6 notifications within 1s across two subscriptions → 1 delivered → 5 counted as dropped
+
+		conn := connectWithLimit(device.OverflowDrop)
+		delivered := burst(conn)
+
+		stats := conn.SubscriptionStats()
+		suite.Assert().Equal(int32(1), delivered.Load(), "only one record MUST be delivered within the rate limit")
+		suite.Assert().Equal(uint64(1), stats.Delivered, "delivered count MUST match callbacks")
+		suite.Assert().Equal(uint64(5), stats.RateLimitDropped, "records over the limit MUST be counted as dropped")
+		suite.Assert().Zero(stats.Coalesced, "drop policy MUST NOT coalesce")
+	})
+
+	suite.Run("coalesce policy", func() {
+		// GOAL: Verify records over the limit are coalesced instead of dropped
+		//
+		// TEST SCENARIO: 6 notifications within 1s across two subscriptions → 1 delivered → 5 coalesced, none dropped
+
+		conn := connectWithLimit(device.OverflowCoalesce)
+		delivered := burst(conn)
+
+		stats := conn.SubscriptionStats()
+		suite.Assert().Equal(int32(1), delivered.Load(), "only one record MUST be delivered within the rate limit")
+		suite.Assert().Equal(uint64(5), stats.Coalesced, "records over the limit MUST be coalesced")
+		suite.Assert().Zero(stats.RateLimitDropped, "coalesce policy MUST NOT drop records")
+	})
+}
+
+func (suite *ConnectionTestSuite) TestNotificationIntervals() {
+	// GOAL: Verify SubscriptionStats reports the intervals between consecutive notifications per characteristic
+	//
+	// TEST SCENARIO: 4 notifications of 2A37 ~20ms apart, 1 of 2A3B → 2A37 has 3 intervals with consistent min/mean/max → 2A3B has none
+
+	conn := suite.device.GetConnection()
+	bleConn, ok := conn.(*goble.BLEConnection)
+	suite.Require().True(ok, "connection MUST be a *goble.BLEConnection")
+
+	notify := func(charUUID string) {
+		char, err := conn.GetCharacteristic("180d", charUUID)
+		suite.Require().NoError(err, "MUST find characteristic")
+		bleConn.ProcessCharacteristicNotification(char.(*goble.BLECharacteristic), []byte{0x00})
+	}
+
+	notify("2a3b")
+	for i := 0; i < 4; i++ {
+		if i > 0 {
+			time.Sleep(20 * time.Millisecond)
+		}
+		notify("2a37")
+	}
+
+	intervals := conn.SubscriptionStats().Intervals
+	hrm, ok := intervals["2a37"]
+	suite.Require().True(ok, "2A37 intervals MUST be reported")
+	suite.Assert().Equal(uint64(3), hrm.Count, "4 notifications MUST yield 3 intervals")
+	suite.Assert().GreaterOrEqual(hrm.Min, 20*time.Millisecond, "min MUST cover the sleep between notifications")
+	suite.Assert().LessOrEqual(hrm.Min, hrm.Mean, "mean MUST NOT be below min")
+	suite.Assert().LessOrEqual(hrm.Mean, hrm.Max, "mean MUST NOT exceed max")
+	suite.Assert().LessOrEqual(hrm.StdDev, hrm.Max-hrm.Min, "stddev MUST NOT exceed the interval range")
+
+	_, ok = intervals["2a3b"]
+	suite.Assert().False(ok, "a single notification MUST NOT report intervals")
+}
+
+func (suite *ConnectionTestSuite) TestNotificationIntervals_Restart() {
+	// GOAL: Verify the gap while notifications are stopped (unsubscribe, disconnect) is not reported as an interval
+	//
+	// TEST SCENARIO: 2 notifications of 2A37 → 1 interval → unsubscribe → pause → notification → still 1 interval →
+	// notification → 2 intervals, max below the pause → disconnect → pause → reconnect → notification → still 2 intervals
+
+	const pause = 150 * time.Millisecond
+
+	notify := func() {
+		conn := suite.device.GetConnection()
+		char, err := conn.GetCharacteristic("180d", "2a37")
+		suite.Require().NoError(err, "MUST find 2A37")
+		conn.(*goble.BLEConnection).ProcessCharacteristicNotification(char.(*goble.BLECharacteristic), []byte{0x00})
+	}
+	intervals := func() device.NotificationIntervals {
+		return suite.device.GetConnection().SubscriptionStats().Intervals["2a37"]
+	}
+
+	notify()
+	notify()
+	suite.Require().Equal(uint64(1), intervals().Count, "2 notifications MUST yield 1 interval")
+
+	bleConn := suite.device.GetConnection().(*goble.BLEConnection)
+	suite.Require().NoError(bleConn.BLEUnsubscribe(&device.SubscribeOptions{Service: "180d", Characteristics: []string{"2a37"}}),
+		"unsubscribe MUST succeed")
+	time.Sleep(pause)
+	notify()
+	suite.Assert().Equal(uint64(1), intervals().Count, "first notification after unsubscribe MUST NOT close an interval")
+	notify()
+	suite.Assert().Equal(uint64(2), intervals().Count, "later notifications MUST be measured again")
+	suite.Assert().Less(intervals().Max, pause, "the unsubscribed gap MUST NOT be reported")
+
+	suite.Require().NoError(suite.device.Disconnect(), "disconnect MUST succeed")
+	time.Sleep(pause)
+	suite.Require().NoError(suite.device.Connect(context.Background(), &device.ConnectOptions{ConnectTimeout: 5 * time.Second}),
+		"reconnect MUST succeed")
+	notify()
+	suite.Assert().Equal(uint64(2), intervals().Count, "first notification after reconnect MUST NOT close an interval")
+	suite.Assert().Less(intervals().Max, pause, "the disconnected gap MUST NOT be reported")
+}
+
+func (suite *ConnectionTestSuite) TestPair() {
+	// GOAL: Verify Pair() selects a characteristic requiring authentication and reports pairing state
+	//
+	// TEST SCENARIO: Pair on device without protected characteristics → ErrUnsupported → Pair on protected characteristic → read error surfaced → IsPaired stays false
+
+	suite.Run("no characteristic requiring authentication", func() {
+		// GOAL: Verify Pair() fails with ErrUnsupported when nothing triggers pairing
+		//
+		// TEST SCENARIO: Default peripheral (all readable) → Pair() → error wraps ErrUnsupported → IsPaired() false
+
+		conn := suite.device.GetConnection()
+		suite.Require().NotNil(conn, "connection MUST exist")
+
+		err := conn.Pair(context.Background())
+		suite.Require().Error(err, "Pair() MUST fail without protected characteristics")
+		suite.Assert().ErrorIs(err, device.ErrUnsupported, "error MUST wrap device.ErrUnsupported")
+		suite.Assert().Contains(err.Error(), "no characteristic requiring authentication", "error MUST explain why pairing is not possible")
+		suite.Assert().False(conn.IsPaired(), "IsPaired() MUST be false after failed pairing")
+	})
+
+	suite.Run("non-pairing read error is surfaced", func() {
+		// GOAL: Verify Pair() does not retry errors unrelated to pairing
+		//
+		// TEST SCENARIO: Characteristic with hidden properties → read fails with generic error → Pair() returns "pairing failed" → IsPaired() false
+
+		suite.WithPeripheral().
+			WithService("1234").
+			WithCharacteristicNoProperties("ABCD", []byte{0x01})
+
+		err := suite.device.Disconnect()
+		suite.Require().NoError(err, "disconnect MUST succeed")
+		suite.ensureConnected()
+
+		conn := suite.device.GetConnection()
+		ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+		defer cancel()
+
+		err = conn.Pair(ctx)
+		suite.Require().Error(err, "Pair() MUST fail when the protected read fails")
+		suite.Assert().Contains(err.Error(), "pairing failed", "error MUST indicate pairing failure")
+		suite.Assert().False(conn.IsPaired(), "IsPaired() MUST be false after failed pairing")
+	})
+
+	suite.Run("canceled context stops probing", func() {
+		// GOAL: Verify Pair() honours the caller's context while probing for a pairing target
+		//
+		// TEST SCENARIO: Slow readable characteristics → Pair() with canceled context → context error returned without reading → IsPaired() false
+
+		suite.WithPeripheral().
+			WithService("1234").
+			WithCharacteristic("ABCD", "read", []byte{0x01}, testutils.WithReadDelay(2*time.Second)).
+			WithCharacteristic("ABCE", "read", []byte{0x02}, testutils.WithReadDelay(2*time.Second))
+
+		err := suite.device.Disconnect()
+		suite.Require().NoError(err, "disconnect MUST succeed")
+		suite.ensureConnected()
+
+		conn := suite.device.GetConnection()
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		start := time.Now()
+		err = conn.Pair(ctx)
+		suite.Require().Error(err, "Pair() MUST fail with a canceled context")
+		suite.Assert().ErrorIs(err, context.Canceled, "error MUST wrap the context error")
+		suite.Assert().Less(time.Since(start), time.Second, "Pair() MUST NOT wait for probe reads")
+		suite.Assert().False(conn.IsPaired(), "IsPaired() MUST be false after canceled pairing")
+	})
+
+	suite.Run("canceled context stops pairing", func() {
+		// GOAL: Verify pairing with a protected characteristic honours the caller's context
+		//
+		// TEST SCENARIO: Characteristic with hidden properties → Pair() with canceled context → context error, not "pairing failed"
+
+		suite.WithPeripheral().
+			WithService("1234").
+			WithCharacteristicNoProperties("ABCD", []byte{0x01})
+
+		err := suite.device.Disconnect()
+		suite.Require().NoError(err, "disconnect MUST succeed")
+		suite.ensureConnected()
+
+		conn := suite.device.GetConnection()
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		err = conn.Pair(ctx)
+		suite.Require().Error(err, "Pair() MUST fail with a canceled context")
+		suite.Assert().ErrorIs(err, context.Canceled, "error MUST wrap the context error")
+		suite.Assert().Contains(err.Error(), "pairing not completed", "error MUST indicate pairing was interrupted")
+		suite.Assert().False(conn.IsPaired(), "IsPaired() MUST be false after canceled pairing")
+	})
+}
+
+func (suite *ConnectionTestSuite) TestReadCache() {
+	// GOAL: Verify the read cache serves values within the TTL and is refreshed by notifications
+	//
+	// TEST SCENARIO: Slow characteristic → default Read() hits device → SetReadCacheTTL → Read() served from cache → notification updates cache → ReadCached(0) hits device
+
+	const readDelay = 200 * time.Millisecond
+
+	suite.WithPeripheral().
+		WithService("1234").
+		WithCharacteristic("5678", "read,notify", []byte{0x01}, testutils.WithReadDelay(readDelay))
+
+	err := suite.device.Disconnect()
+	suite.Require().NoError(err, "disconnect MUST succeed")
+	suite.ensureConnected()
+
+	conn := suite.device.GetConnection()
+	char, err := conn.GetCharacteristic("1234", "5678")
+	suite.Require().NoError(err, "characteristic MUST exist")
+
+	timedRead := func(read func() ([]byte, error)) ([]byte, time.Duration) {
+		start := time.Now()
+		value, err := read()
+		suite.Require().NoError(err, "read MUST succeed")
+		return value, time.Since(start)
+	}
+
+	suite.Run("cache disabled by default", func() {
+		_, elapsed := timedRead(func() ([]byte, error) { return char.Read(time.Second) })
+		suite.Assert().GreaterOrEqual(elapsed, readDelay, "first read MUST hit the device")
+		_, elapsed = timedRead(func() ([]byte, error) { return char.Read(time.Second) })
+		suite.Assert().GreaterOrEqual(elapsed, readDelay, "Read() MUST hit the device when the cache is disabled")
+	})
+
+	suite.Run("read served from cache within TTL", func() {
+		suite.Require().NoError(conn.SetReadCacheTTL("1234", "5678", time.Minute), "SetReadCacheTTL MUST succeed")
+
+		value, elapsed := timedRead(func() ([]byte, error) { return char.Read(time.Second) })
+		suite.Assert().Equal([]byte{0x01}, value, "cached value MUST match last read")
+		suite.Assert().Less(elapsed, readDelay, "Read() MUST be served from cache")
+	})
+
+	suite.Run("notification updates cache", func() {
+		bleConn := conn.(*goble.BLEConnection)
+		bleConn.ProcessCharacteristicNotification(char.(*goble.BLECharacteristic), []byte{0x02})
+
+		value, elapsed := timedRead(func() ([]byte, error) { return char.ReadCached(time.Minute, time.Second) })
+		suite.Assert().Equal([]byte{0x02}, value, "cached value MUST reflect the notification")
+		suite.Assert().Less(elapsed, readDelay, "ReadCached() MUST be served from cache")
+
+		value, elapsed = timedRead(func() ([]byte, error) { return char.ReadCached(0, time.Second) })
+		suite.Assert().Equal([]byte{0x01}, value, "zero TTL MUST read the device value")
+		suite.Assert().GreaterOrEqual(elapsed, readDelay, "zero TTL MUST hit the device")
+	})
+
+	suite.Run("unknown characteristic", func() {
+		err := conn.SetReadCacheTTL("1234", "9999", time.Second)
+		suite.Require().Error(err, "SetReadCacheTTL MUST fail for unknown characteristic")
+	})
+}
+
+func (suite *ConnectionTestSuite) TestReadLong() {
+	// GOAL: Verify values longer than one ATT MTU are read in full instead of being silently truncated
+	//
+	// TEST SCENARIO: 100-byte value, single reads limited to MTU 23 (22 bytes) → ReadLong() returns all bytes → Read() detects the full response and switches to a long read
+
+	longValue := make([]byte, 100) // Spans several Read Blob responses
+	for i := range longValue {
+		longValue[i] = byte(i)
+	}
+
+	suite.WithPeripheral().
+		WithService("1812").
+		WithCharacteristic("2a4b", "read", longValue, testutils.WithReadMTU(goble.DefaultATTMTU)).
+		WithCharacteristic("2a4a", "read", []byte{0x11, 0x01, 0x00, 0x03}, testutils.WithReadMTU(goble.DefaultATTMTU))
+
+	err := suite.device.Disconnect()
+	suite.Require().NoError(err, "disconnect MUST succeed")
+	suite.ensureConnected()
+
+	conn := suite.device.GetConnection()
+
+	suite.Run("explicit long read", func() {
+		value, err := conn.ReadLong("1812", "2a4b", time.Second)
+		suite.Require().NoError(err, "ReadLong MUST succeed")
+		suite.Assert().Equal(longValue, value, "ReadLong MUST return the complete value")
+	})
+
+	suite.Run("read switches to long read at the MTU boundary", func() {
+		char, err := conn.GetCharacteristic("1812", "2a4b")
+		suite.Require().NoError(err, "characteristic MUST exist")
+
+		value, err := char.Read(time.Second)
+		suite.Require().NoError(err, "Read MUST succeed")
+		suite.Assert().Equal(longValue, value, "Read MUST NOT truncate values longer than the MTU")
+	})
+
+	suite.Run("short value uses a single read", func() {
+		char, err := conn.GetCharacteristic("1812", "2a4a")
+		suite.Require().NoError(err, "characteristic MUST exist")
+
+		value, err := char.Read(time.Second)
+		suite.Require().NoError(err, "Read MUST succeed")
+		suite.Assert().Equal([]byte{0x11, 0x01, 0x00, 0x03}, value, "short values MUST be returned as read")
+	})
+
+	suite.Run("unknown characteristic", func() {
+		_, err := conn.ReadLong("1812", "9999", time.Second)
+		suite.Require().Error(err, "ReadLong MUST fail for unknown characteristic")
+	})
+}
+
+func (suite *ConnectionTestSuite) TestEnabledCCCDs() {
+	// GOAL: Verify EnabledCCCDs reports notify/indicate subscriptions and is cleared on disconnect
+	//
+	// TEST SCENARIO: No subscriptions → empty → subscribe 2A37 (notify) and 2A3A (indicate) → both reported with mode → disconnect → empty
+
+	conn := suite.device.GetConnection()
+	suite.Assert().Empty(conn.EnabledCCCDs(), "no CCCD MUST be enabled before subscribing")
+
+	err := conn.Subscribe([]*device.SubscribeOptions{
+		{Service: "180d", Characteristics: []string{"2a37"}},
+		{Service: "180d", Characteristics: []string{"2a3a"}, Indicate: true},
+	}, device.StreamEveryUpdate, device.StreamOptions{}, func(*device.Record) {})
+	suite.Require().NoError(err, "subscription MUST succeed")
+
+	suite.Assert().Equal([]device.CCCDState{
+		{Service: "180d", Characteristic: "2a37"},
+		{Service: "180d", Characteristic: "2a3a", Indicate: true},
+	}, conn.EnabledCCCDs(), "enabled CCCDs MUST match subscriptions")
+	suite.Assert().Equal([]string{"2a37", "2a3a"}, conn.EnabledNotifications(), "enabled notifications MUST list subscribed UUIDs")
+
+	err = suite.device.Disconnect()
+	suite.Require().NoError(err, "disconnect MUST succeed")
+	suite.Assert().Empty(conn.EnabledCCCDs(), "CCCD state MUST be cleared on disconnect")
+	suite.Assert().Empty(conn.EnabledNotifications(), "enabled notifications MUST be cleared on disconnect")
+}
+
+func (suite *ConnectionTestSuite) TestOnNotification() {
+	// GOAL: Verify OnNotification handlers observe notifications without stealing them from subscriptions, and can be unregistered
+	//
+	// TEST SCENARIO: Register handler on 2A37 → notifications enabled → subscribe to 2A37 → notification → both handler and subscription receive it →
+	// unregister handler → notification → only the subscription receives it, notifications stay enabled
+
+	conn := suite.device.GetConnection()
+
+	received := make(chan []byte, 1)
+	unregister, err := conn.OnNotification("180d", "2a37", func(data []byte) { received <- data })
+	suite.Require().NoError(err, "OnNotification MUST succeed")
+	suite.Assert().Equal([]device.CCCDState{{Service: "180d", Characteristic: "2a37"}}, conn.EnabledCCCDs(),
+		"OnNotification MUST enable notifications")
+
+	records := make(chan *device.Record, 1)
+	err = conn.Subscribe([]*device.SubscribeOptions{
+		{Service: "180d", Characteristics: []string{"2a37"}},
+	}, device.StreamEveryUpdate, device.StreamOptions{}, func(r *device.Record) { records <- r })
+	suite.Require().NoError(err, "subscription MUST succeed")
+
+	char, err := conn.GetCharacteristic("180d", "2a37")
+	suite.Require().NoError(err, "MUST find 2A37")
+	conn.(*goble.BLEConnection).ProcessCharacteristicNotification(char.(*goble.BLECharacteristic), []byte{0x00, 0x48})
+
+	select {
+	case data := <-received:
+		suite.Assert().Equal([]byte{0x00, 0x48}, data, "handler MUST receive the notification payload")
+	case <-time.After(time.Second):
+		suite.Fail("handler MUST be called")
+	}
+
+	select {
+	case r := <-records:
+		suite.Assert().Equal([]byte{0x00, 0x48}, r.Values["2a37"], "subscription MUST still receive the notification")
+	case <-time.After(time.Second):
+		suite.Fail("subscription callback MUST be called")
+	}
+
+	unregister()
+	unregister() // MUST be a no-op
+	conn.(*goble.BLEConnection).ProcessCharacteristicNotification(char.(*goble.BLECharacteristic), []byte{0x00, 0x49})
+
+	select {
+	case r := <-records:
+		suite.Assert().Equal([]byte{0x00, 0x49}, r.Values["2a37"], "subscription MUST receive notifications after the handler is unregistered")
+	case <-time.After(time.Second):
+		suite.Fail("subscription callback MUST be called")
+	}
+	suite.Assert().Empty(received, "unregistered handler MUST NOT be called")
+	suite.Assert().Equal([]device.CCCDState{{Service: "180d", Characteristic: "2a37"}}, conn.EnabledCCCDs(),
+		"unregistering MUST leave notifications enabled")
+
+	suite.Run("unknown characteristic", func() {
+		_, err := conn.OnNotification("180d", "9999", func([]byte) {})
+		suite.Assert().Error(err, "OnNotification MUST fail for unknown characteristic")
+	})
+}
+
+func (suite *ConnectionTestSuite) TestSetNotifyHandler() {
+	// GOAL: Verify SetNotifyHandler delivers notifications as Records, replaces earlier handlers and is removed by nil,
+	// without affecting OnNotification handlers of the same characteristic
+	//
+	// TEST SCENARIO: Set handler on 2A37 → notifications enabled → register OnNotification handler → notification → Record with value →
+	// replace handler → only the new one is called → nil handler → no calls, OnNotification handler still called →
+	// panicking handler recovered → unknown characteristic fails
+
+	conn := suite.device.GetConnection()
+	char, err := conn.GetCharacteristic("180d", "2a37")
+	suite.Require().NoError(err, "MUST find 2A37")
+	notify := func(data []byte) {
+		conn.(*goble.BLEConnection).ProcessCharacteristicNotification(char.(*goble.BLECharacteristic), data)
+	}
+
+	first := make(chan device.Record, 2)
+	err = conn.SetNotifyHandler("180d", "2a37", func(r device.Record) { first <- r })
+	suite.Require().NoError(err, "SetNotifyHandler MUST succeed")
+	suite.Assert().Equal([]device.CCCDState{{Service: "180d", Characteristic: "2a37"}}, conn.EnabledCCCDs(),
+		"SetNotifyHandler MUST enable notifications")
+
+	observed := make(chan []byte, 8)
+	_, err = conn.OnNotification("180d", "2a37", func(data []byte) { observed <- data })
+	suite.Require().NoError(err, "OnNotification MUST succeed")
+
+	notify([]byte{0x00, 0x48})
+	r := <-first
+	suite.Assert().Equal([]byte{0x00, 0x48}, r.Values["2a37"], "record MUST carry the notification payload")
+	suite.Assert().Equal([]string{"2a37"}, r.Keys(), "record MUST hold the characteristic only")
+	suite.Assert().NotZero(r.TsUs, "record MUST carry the receive timestamp")
+
+	second := make(chan device.Record, 1)
+	suite.Require().NoError(conn.SetNotifyHandler("180d", "2a37", func(r device.Record) { second <- r }))
+	notify([]byte{0x00, 0x49})
+	suite.Assert().Equal([]byte{0x00, 0x49}, (<-second).Values["2a37"], "replacing handler MUST receive notifications")
+	suite.Assert().Empty(first, "replaced handler MUST NOT be called")
+
+	suite.Require().NoError(conn.SetNotifyHandler("180d", "2a37", nil))
+	notify([]byte{0x00, 0x4A})
+	suite.Assert().Empty(second, "removed handler MUST NOT be called")
+	suite.Assert().Len(observed, 3, "OnNotification handler MUST keep receiving notifications")
+
+	suite.Require().NoError(conn.SetNotifyHandler("180d", "2a37", func(device.Record) { panic("boom") }))
+	suite.Assert().NotPanics(func() { notify([]byte{0x00, 0x4B}) }, "handler panic MUST be recovered")
+
+	suite.Run("unknown characteristic", func() {
+		err := conn.SetNotifyHandler("180d", "9999", func(device.Record) {})
+		var notFound *device.NotFoundError
+		suite.Assert().ErrorAs(err, &notFound, "SetNotifyHandler MUST fail for unknown characteristic")
+	})
+}
+
+func (suite *ConnectionTestSuite) TestSubscriptionChannelBuffer() {
+	// GOAL: Verify ChannelBuffer enlarges the notification queue of a subscription so bursts are not dropped
+	//
+	// TEST SCENARIO: Burst of 300 notifications into a default-buffered batched subscription → oldest dropped →
+	// same burst with ChannelBuffer 512 → all delivered → negative buffer rejected
+
+	conn := suite.device.GetConnection()
+	bleConn := conn.(*goble.BLEConnection)
+	const burst = 300
+
+	deliver := func(uuid string, buffer int) int {
+		var delivered atomic.Int64
+		err := conn.Subscribe([]*device.SubscribeOptions{
+			{Service: "180d", Characteristics: []string{uuid}, ChannelBuffer: buffer},
+		}, device.StreamBatched, device.StreamOptions{MaxRate: 200 * time.Millisecond}, func(r *device.Record) {
+			delivered.Add(int64(len(r.BatchValues[uuid])))
+		})
+		suite.Require().NoError(err, "subscription MUST succeed")
+
+		char, err := conn.GetCharacteristic("180d", uuid)
+		suite.Require().NoError(err, "MUST find characteristic")
+		for i := 0; i < burst; i++ {
+			bleConn.ProcessCharacteristicNotification(char.(*goble.BLECharacteristic), []byte{byte(i)})
+		}
+		time.Sleep(500 * time.Millisecond)
+		return int(delivered.Load())
+	}
+
+	suite.Assert().Less(deliver("2a3b", 0), burst, "default buffer MUST drop part of the burst")
+	suite.Assert().Equal(burst, deliver("2a37", 512), "enlarged buffer MUST absorb the whole burst")
+
+	err := conn.Subscribe([]*device.SubscribeOptions{
+		{Service: "180d", Characteristics: []string{"2a37"}, ChannelBuffer: -1},
+	}, device.StreamEveryUpdate, device.StreamOptions{}, func(*device.Record) {})
+	suite.Assert().ErrorContains(err, "channel buffer must not be negative")
+}
+
+func (suite *ConnectionTestSuite) TestSubscriptionLifetime() {
+	// GOAL: Verify a subscription with a lifetime delivers notifications, then a final End record, then stops
+	//
+	// TEST SCENARIO: Subscribe to 2A37 with 200ms lifetime → notification delivered → End record after lifetime → later notifications ignored
+
+	conn := suite.device.GetConnection()
+
+	records := make(chan *device.Record, 4)
+	err := conn.Subscribe([]*device.SubscribeOptions{
+		{Service: "180d", Characteristics: []string{"2a37"}},
+	}, device.StreamEveryUpdate, device.StreamOptions{Lifetime: 200 * time.Millisecond}, func(r *device.Record) { records <- r })
+	suite.Require().NoError(err, "subscription MUST succeed")
+
+	char, err := conn.GetCharacteristic("180d", "2a37")
+	suite.Require().NoError(err, "MUST find 2A37")
+	bleConn := conn.(*goble.BLEConnection)
+	bleConn.ProcessCharacteristicNotification(char.(*goble.BLECharacteristic), []byte{0x00, 0x48})
+
+	select {
+	case r := <-records:
+		suite.Assert().False(r.End, "notification record MUST NOT be an end marker")
+		suite.Assert().Equal([]byte{0x00, 0x48}, r.Values["2a37"], "notification MUST be delivered before the lifetime elapses")
+	case <-time.After(time.Second):
+		suite.Require().Fail("notification MUST be delivered")
+	}
+
+	select {
+	case r := <-records:
+		suite.Assert().True(r.End, "final record MUST be an end marker")
+		suite.Assert().Empty(r.Values, "end marker MUST carry no values")
+	case <-time.After(time.Second):
+		suite.Require().Fail("end marker MUST be delivered after the lifetime")
+	}
+
+	bleConn.ProcessCharacteristicNotification(char.(*goble.BLECharacteristic), []byte{0x00, 0x50})
+	select {
+	case r := <-records:
+		suite.Failf("expired subscription MUST NOT deliver records", "got %+v", r)
+	case <-time.After(100 * time.Millisecond):
+	}
+}
+
+func (suite *ConnectionTestSuite) TestSubscriptionLifetimeDisablesCCCD() {
+	// GOAL: Verify an expired subscription clears the CCCD of characteristics nothing else consumes, and only those
+	//
+	// TEST SCENARIO: Subscribe 2A37+2A3B with 200ms lifetime and 2A3B without → End record → 2A37 CCCD cleared, 2A3B kept enabled
+
+	conn := suite.device.GetConnection()
+
+	ended := make(chan struct{})
+	err := conn.Subscribe([]*device.SubscribeOptions{
+		{Service: "180d", Characteristics: []string{"2a37", "2a3b"}},
+	}, device.StreamEveryUpdate, device.StreamOptions{Lifetime: 200 * time.Millisecond}, func(r *device.Record) {
+		if r.End {
+			close(ended)
+		}
+	})
+	suite.Require().NoError(err, "expiring subscription MUST succeed")
+
+	err = conn.Subscribe([]*device.SubscribeOptions{
+		{Service: "180d", Characteristics: []string{"2a3b"}},
+	}, device.StreamEveryUpdate, device.StreamOptions{}, func(*device.Record) {})
+	suite.Require().NoError(err, "live subscription MUST succeed")
+
+	suite.Assert().Equal([]device.CCCDState{
+		{Service: "180d", Characteristic: "2a37"},
+		{Service: "180d", Characteristic: "2a3b"},
+	}, conn.EnabledCCCDs(), "both CCCDs MUST be enabled while subscribed")
+
+	select {
+	case <-ended:
+	case <-time.After(time.Second):
+		suite.Require().Fail("end marker MUST be delivered after the lifetime")
+	}
+
+	suite.Assert().Eventually(func() bool {
+		return len(conn.EnabledCCCDs()) == 1
+	}, time.Second, 10*time.Millisecond, "expired subscription MUST clear the CCCD it alone consumed")
+	suite.Assert().Equal([]device.CCCDState{{Service: "180d", Characteristic: "2a3b"}}, conn.EnabledCCCDs(),
+		"CCCD of a characteristic still subscribed MUST stay enabled")
+}
+
+func (suite *ConnectionTestSuite) TestAggregateWindow() {
+	// GOAL: Verify windowed aggregation delivers the latest value per window on a steady timer, including empty windows on request
+	//
+	// TEST SCENARIO: Subscribe Aggregated with 150ms window + EmitEmpty → burst of 3 notifications → latest value delivered, not queued ones → quiet window → empty record flagged missing → invalid windows rejected
+
+	conn := suite.device.GetConnection()
+
+	records := make(chan *device.Record, 16)
+	window := &device.AggregateWindow{Interval: 150 * time.Millisecond, EmitEmpty: true}
+	err := conn.Subscribe([]*device.SubscribeOptions{
+		{Service: "180d", Characteristics: []string{"2a37"}},
+	}, device.StreamAggregated, device.StreamOptions{Window: window}, func(r *device.Record) { records <- r })
+	suite.Require().NoError(err, "subscription MUST succeed")
+
+	char, err := conn.GetCharacteristic("180d", "2a37")
+	suite.Require().NoError(err, "MUST find 2A37")
+	bleConn := conn.(*goble.BLEConnection)
+	for _, b := range []byte{0x01, 0x02, 0x03} {
+		bleConn.ProcessCharacteristicNotification(char.(*goble.BLECharacteristic), []byte{0x00, b})
+	}
+
+	// The burst may straddle a window boundary, so the latest value arrives within at most two windows
+	var withValues []*device.Record
+	deadline := time.After(time.Second)
+	for len(withValues) == 0 || !bytes.Equal(withValues[len(withValues)-1].Values["2a37"], []byte{0x00, 0x03}) {
+		select {
+		case r := <-records:
+			if len(r.Values) > 0 {
+				withValues = append(withValues, r)
+			}
+		case <-deadline:
+			suite.Require().Fail("latest value MUST be delivered", "got %d records with values", len(withValues))
+		}
+	}
+	suite.Assert().LessOrEqual(len(withValues), 2, "a window MUST drain all queued values instead of handing out one per tick")
+
+	select {
+	case r := <-records:
+		suite.Assert().Empty(r.Values, "quiet window MUST be delivered without values")
+		suite.Assert().NotZero(r.Flags&goble.FlagMissing, "quiet window MUST be flagged missing")
+	case <-time.After(time.Second):
+		suite.Require().Fail("EmitEmpty MUST deliver quiet windows")
+	}
+
+	suite.Run("invalid window", func() {
+		opts := []*device.SubscribeOptions{{Service: "180d", Characteristics: []string{"2a37"}}}
+		err := conn.Subscribe(opts, device.StreamEveryUpdate, device.StreamOptions{Window: window}, func(*device.Record) {})
+		suite.Assert().ErrorContains(err, "requires Aggregated", "window MUST require Aggregated mode")
+		err = conn.Subscribe(opts, device.StreamAggregated, device.StreamOptions{Window: &device.AggregateWindow{}}, func(*device.Record) {})
+		suite.Assert().ErrorContains(err, "must be positive", "zero window MUST be rejected")
+	})
+}
+
+func (suite *ConnectionTestSuite) TestFlushWrites() {
+	// GOAL: Verify FlushWrites waits for write-without-response commands still in flight after their caller timed out
+	//
+	// TEST SCENARIO: Slow write-without-response times out → FlushWrites blocks until the peripheral received it → FlushWrites with an expired context fails
+
+	var written atomic.Bool
+	suite.WithPeripheral().
+		WithService("6e400001-b5a3-f393-e0a9-e50e24dcca9e").
+		WithCharacteristic("6e400002-b5a3-f393-e0a9-e50e24dcca9e", "write-without-response", []byte{},
+			testutils.WithWriteDelay(300*time.Millisecond),
+			testutils.WithWriteHook(func(data []byte, noRsp bool) { written.Store(true) })).
+		WithCharacteristic("6e400003-b5a3-f393-e0a9-e50e24dcca9e", "read", []byte{0x01})
+
+	err := suite.device.Disconnect()
+	suite.Require().NoError(err, "disconnect MUST succeed")
+	suite.ensureConnected()
+
+	conn := suite.device.GetConnection()
+	char, err := conn.GetCharacteristic("6e400001-b5a3-f393-e0a9-e50e24dcca9e", "6e400002-b5a3-f393-e0a9-e50e24dcca9e")
+	suite.Require().NoError(err, "characteristic MUST exist")
+
+	suite.Run("waits for in-flight writes", func() {
+		written.Store(false)
+		err := char.Write([]byte("ping"), false, 50*time.Millisecond)
+		suite.Require().ErrorIs(err, device.ErrTimeout, "write MUST time out before the peripheral receives it")
+		suite.Require().False(written.Load(), "write MUST still be in flight")
+
+		ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+		defer cancel()
+		suite.Require().NoError(conn.FlushWrites(ctx), "FlushWrites MUST succeed")
+		suite.Assert().True(written.Load(), "FlushWrites MUST NOT return before the pending write completed")
+	})
+
+	suite.Run("no pending writes", func() {
+		suite.Assert().NoError(conn.FlushWrites(context.Background()), "FlushWrites MUST succeed without pending writes")
+	})
+
+	suite.Run("context expires", func() {
+		_ = char.Write([]byte("ping"), false, 10*time.Millisecond)
+
+		ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+		defer cancel()
+		err := conn.FlushWrites(ctx)
+		suite.Assert().ErrorIs(err, context.DeadlineExceeded, "FlushWrites MUST fail when the context expires first")
+
+		suite.Require().NoError(conn.FlushWrites(context.Background()), "pending write MUST eventually drain")
+	})
+}
+
+func (suite *ConnectionTestSuite) TestIdleTimeout() {
+	// GOAL: Verify IdleTimeout disconnects an idle connection, activity postpones it, and handlers get the "idle" reason
+	//
+	// TEST SCENARIO: Connect with 150ms idle timeout → notifications every 50ms keep it alive → stop activity → disconnected with reason "idle"
+
+	err := suite.device.Disconnect()
+	suite.Require().NoError(err, "disconnect MUST succeed")
+
+	suite.device = devicefactory.NewDevice("AA:BB:CC:DD:EE:FF", suite.Logger)
+	err = suite.device.Connect(context.Background(), &device.ConnectOptions{
+		ConnectTimeout: 5 * time.Second,
+		IdleTimeout:    150 * time.Millisecond,
+	})
+	suite.Require().NoError(err, "MUST connect successfully")
+	conn := suite.device.GetConnection()
+
+	reasons := make(chan string, 1)
+	conn.OnDisconnected(func(reason string) { reasons <- reason })
+
+	bleConn := conn.(*goble.BLEConnection)
+	char, err := conn.GetCharacteristic("180d", "2a37")
+	suite.Require().NoError(err, "MUST find 2A37")
+
+	for i := 0; i < 6; i++ {
+		bleConn.ProcessCharacteristicNotification(char.(*goble.BLECharacteristic), []byte{byte(i)})
+		time.Sleep(50 * time.Millisecond)
+	}
+	suite.Assert().True(suite.device.IsConnected(), "activity MUST keep the connection alive past the idle timeout")
+
+	select {
+	case reason := <-reasons:
+		suite.Assert().Equal(device.DisconnectReasonIdle, reason, "reason MUST be idle")
+	case <-time.After(time.Second):
+		suite.Fail("idle connection MUST be disconnected")
+	}
+	suite.Assert().False(suite.device.IsConnected(), "device MUST be disconnected after idle timeout")
+
+	suite.Run("SetIdleTimeout requires a connection", func() {
+		err := conn.SetIdleTimeout(time.Second)
+		suite.Assert().ErrorIs(err, device.ErrNotConnected, "SetIdleTimeout MUST fail when disconnected")
+	})
+}
+
+func (suite *ConnectionTestSuite) TestDiscoveryTimeout() {
+	// GOAL: Verify DiscoveryTimeout aborts a discovery that takes too long and leaves the device disconnected
+	//
+	// TEST SCENARIO: Discovery delayed 500ms → connect with 50ms discovery timeout → ErrTimeout mentioning discovery → not connected
+
+	err := suite.device.Disconnect()
+	suite.Require().NoError(err, "disconnect MUST succeed")
+
+	suite.WithPeripheral().WithDiscoveryDelay(500 * time.Millisecond)
+	suite.device = devicefactory.NewDevice("AA:BB:CC:DD:EE:FF", suite.Logger)
+
+	start := time.Now()
+	err = suite.device.Connect(context.Background(), &device.ConnectOptions{
+		ConnectTimeout:   5 * time.Second,
+		DiscoveryTimeout: 50 * time.Millisecond,
+	})
+	suite.Require().Error(err, "connect MUST fail when discovery exceeds the timeout")
+	suite.Assert().ErrorIs(err, device.ErrTimeout, "error MUST wrap ErrTimeout")
+	suite.Assert().Contains(err.Error(), "discovery", "error MUST name the discovery phase")
+	suite.Assert().Less(time.Since(start), 500*time.Millisecond, "connect MUST NOT wait for the slow discovery")
+	suite.Assert().False(suite.device.IsConnected(), "device MUST NOT be connected after a discovery timeout")
+}
+
+func (suite *ConnectionTestSuite) TestSkipDescriptors() {
+	// GOAL: Verify SkipDescriptors discovers characteristics without descriptors while reads keep working
+	//
+	// TEST SCENARIO: Characteristic with a user description descriptor → connect with SkipDescriptors → characteristic found, no descriptors → read succeeds
+
+	suite.WithPeripheral().
+		WithService("1234").
+		WithCharacteristic("5678", "read", []byte{0x2A}).
+		WithDescriptor("2901", []byte("Level"))
+
+	err := suite.device.Disconnect()
+	suite.Require().NoError(err, "disconnect MUST succeed")
+
+	suite.device = devicefactory.NewDevice("AA:BB:CC:DD:EE:FF", suite.Logger)
+	err = suite.device.Connect(context.Background(), &device.ConnectOptions{
+		ConnectTimeout:        5 * time.Second,
+		DescriptorReadTimeout: time.Second,
+		SkipDescriptors:       true,
+	})
+	suite.Require().NoError(err, "MUST connect without descriptor discovery")
+
+	char, err := suite.device.GetConnection().GetCharacteristic("1234", "5678")
+	suite.Require().NoError(err, "characteristic MUST be discovered")
+	suite.Assert().Empty(char.GetDescriptors(), "descriptors MUST NOT be discovered")
+
+	value, err := char.Read(time.Second)
+	suite.Require().NoError(err, "read MUST succeed without descriptors")
+	suite.Assert().Equal([]byte{0x2A}, value)
+}
+
+func (suite *ConnectionTestSuite) TestGATTCache() {
+	// GOAL: Verify the GATT cache replaces discovery on reconnection and is invalidated by Service Changed
+	//
+	// TEST SCENARIO: Peripheral with Service Changed → first connect writes the cache → reconnect with stalled discovery succeeds from the cache → reads work → Service Changed indication removes the cache
+
+	suite.WithPeripheral().
+		WithService("1801").
+		WithCharacteristic("2A05", "indicate", []byte{0x01, 0x00, 0xFF, 0xFF}).
+		WithDescriptor("2902", []byte{0x00, 0x00})
+	suite.Require().NoError(suite.device.Disconnect(), "disconnect MUST succeed")
+
+	cacheDir := suite.T().TempDir()
+	cacheFile := filepath.Join(cacheDir, "aabbccddeeff.json")
+	connect := func(discoveryTimeout time.Duration) error {
+		suite.device = devicefactory.NewDevice("AA:BB:CC:DD:EE:FF", suite.Logger)
+		return suite.device.Connect(context.Background(), &device.ConnectOptions{
+			ConnectTimeout:        5 * time.Second,
+			DescriptorReadTimeout: time.Second,
+			DiscoveryTimeout:      discoveryTimeout,
+			GATTCacheDir:          cacheDir,
+		})
+	}
+
+	suite.Require().NoError(connect(0), "first connection MUST discover the profile")
+	suite.Assert().FileExists(cacheFile, "full discovery MUST write the cache keyed by peripheral identifier")
+	suite.Require().NoError(suite.device.Disconnect(), "disconnect MUST succeed")
+
+	// Discovery would now exceed the timeout, so only a cached profile can connect
+	suite.WithPeripheral().WithDiscoveryDelay(2 * time.Second)
+	start := time.Now()
+	suite.Require().NoError(connect(200*time.Millisecond), "reconnection MUST use the cached profile")
+	suite.Assert().Less(time.Since(start), time.Second, "reconnection MUST NOT wait for discovery")
+
+	conn := suite.device.GetConnection()
+	char, err := conn.GetCharacteristic("180f", "2a19")
+	suite.Require().NoError(err, "cached characteristic MUST exist")
+	value, err := char.Read(time.Second)
+	suite.Require().NoError(err, "read through a cached characteristic MUST succeed")
+	suite.Assert().Equal([]byte{85}, value)
+
+	bleConn, ok := conn.(*goble.BLEConnection)
+	suite.Require().True(ok, "connection MUST be a *goble.BLEConnection")
+	serviceChanged, err := conn.GetCharacteristic("1801", "2a05")
+	suite.Require().NoError(err, "MUST find Service Changed characteristic")
+	bleConn.ProcessCharacteristicNotification(serviceChanged.(*goble.BLECharacteristic), []byte{0x01, 0x00, 0xFF, 0xFF})
+	suite.Assert().NoFileExists(cacheFile, "Service Changed indication MUST invalidate the cache")
+}
+
+func (suite *ConnectionTestSuite) TestGATTCacheStale() {
+	// GOAL: Verify a cache that no longer validates against the peripheral is discarded and rediscovered
+	//
+	// TEST SCENARIO: Cache written → Service Changed CCCD handle moved onto a user description descriptor → reconnect → validation fails → discovery runs → cache rewritten with the live handle
+
+	suite.WithPeripheral().
+		WithService("1801").
+		WithCharacteristic("2A05", "indicate", []byte{0x01, 0x00, 0xFF, 0xFF}).
+		WithDescriptor("2902", []byte{0x00, 0x00}).
+		WithService("1234").
+		WithCharacteristic("5678", "read", []byte{0x2A}).
+		WithDescriptor("2901", []byte("Level"))
+	suite.Require().NoError(suite.device.Disconnect(), "disconnect MUST succeed")
+
+	cacheDir := suite.T().TempDir()
+	cacheFile := filepath.Join(cacheDir, "aabbccddeeff.json")
+	connect := func() {
+		suite.device = devicefactory.NewDevice("AA:BB:CC:DD:EE:FF", suite.Logger)
+		err := suite.device.Connect(context.Background(), &device.ConnectOptions{
+			ConnectTimeout:        5 * time.Second,
+			DescriptorReadTimeout: time.Second,
+			GATTCacheDir:          cacheDir,
+		})
+		suite.Require().NoError(err, "MUST connect successfully")
+	}
+
+	type cache struct {
+		Services []struct {
+			UUID            string `json:"uuid"`
+			Characteristics []struct {
+				Descriptors []struct {
+					UUID   string `json:"uuid"`
+					Handle uint16 `json:"handle"`
+				} `json:"descriptors"`
+			} `json:"characteristics"`
+		} `json:"services"`
+	}
+	// readCache returns the cache file with the cached handles of the Service Changed CCCD and the user description
+	readCache := func() (raw []byte, cccd, userDesc uint16) {
+		raw, err := os.ReadFile(cacheFile)
+		suite.Require().NoError(err, "cache MUST be written")
+		var c cache
+		suite.Require().NoError(json.Unmarshal(raw, &c), "cache MUST be valid JSON")
+		for _, svc := range c.Services {
+			for _, char := range svc.Characteristics {
+				for _, desc := range char.Descriptors {
+					switch device.NormalizeUUID(desc.UUID) {
+					case "2902":
+						if device.NormalizeUUID(svc.UUID) == "1801" {
+							cccd = desc.Handle
+						}
+					case "2901":
+						userDesc = desc.Handle
+					}
+				}
+			}
+		}
+		suite.Require().NotZero(cccd, "cache MUST hold the Service Changed CCCD")
+		suite.Require().NotZero(userDesc, "cache MUST hold the user description")
+		return raw, cccd, userDesc
+	}
+
+	connect()
+	raw, cccd, userDesc := readCache()
+	suite.Require().NoError(suite.device.Disconnect(), "disconnect MUST succeed")
+
+	stale := bytes.Replace(raw, []byte(fmt.Sprintf(`"handle": %d`, cccd)), []byte(fmt.Sprintf(`"handle": %d`, userDesc)), 1)
+	suite.Require().NotEqual(raw, stale, "cache MUST be modified")
+	suite.Require().NoError(os.WriteFile(cacheFile, stale, 0o644), "stale cache MUST be written")
+
+	connect()
+	_, rewritten, _ := readCache()
+	suite.Assert().Equal(cccd, rewritten, "rediscovery MUST rewrite the cache with the live handle")
+}
+
+func (suite *ConnectionTestSuite) TestGATTCacheRequiresServiceChanged() {
+	// GOAL: Verify a device without Service Changed is never cached, since its layout cannot be validated
+	//
+	// TEST SCENARIO: Default peripheral (no 1801/2A05) → connect with a cache directory → no cache file written
+
+	suite.Require().NoError(suite.device.Disconnect(), "disconnect MUST succeed")
+
+	cacheDir := suite.T().TempDir()
+	suite.device = devicefactory.NewDevice("AA:BB:CC:DD:EE:FF", suite.Logger)
+	err := suite.device.Connect(context.Background(), &device.ConnectOptions{
+		ConnectTimeout:        5 * time.Second,
+		DescriptorReadTimeout: time.Second,
+		GATTCacheDir:          cacheDir,
+	})
+	suite.Require().NoError(err, "MUST connect successfully")
+
+	entries, err := os.ReadDir(cacheDir)
+	suite.Require().NoError(err, "cache directory MUST be readable")
+	suite.Assert().Empty(entries, "profile without Service Changed MUST NOT be cached")
+}
+
+func (suite *ConnectionTestSuite) TestDiscoverOnlyListed() {
+	// GOAL: Verify DiscoverOnlyListed scopes discovery to the listed services, keeping their descriptors
+	//
+	// TEST SCENARIO: Two services → connect listing 1111 only → 1111 characteristic and its descriptor found → other services unknown → empty list rejected
+
+	suite.WithPeripheral().
+		WithService("1111").
+		WithCharacteristic("2222", "read", []byte{0x4B}).
+		WithDescriptor("2901", []byte("Level")).
+		WithService("1234").
+		WithCharacteristic("5678", "read", []byte{0x01})
+
+	err := suite.device.Disconnect()
+	suite.Require().NoError(err, "disconnect MUST succeed")
+
+	suite.device = devicefactory.NewDevice("AA:BB:CC:DD:EE:FF", suite.Logger)
+	err = suite.device.Connect(context.Background(), &device.ConnectOptions{
+		ConnectTimeout:     5 * time.Second,
+		Services:           []device.SubscribeOptions{{Service: "1111"}},
+		DiscoverOnlyListed: true,
+	})
+	suite.Require().NoError(err, "MUST connect with scoped discovery")
+	conn := suite.device.GetConnection()
+
+	char, err := conn.GetCharacteristic("1111", "2222")
+	suite.Require().NoError(err, "listed service MUST be discovered")
+	suite.Assert().Len(char.GetDescriptors(), 1, "descriptors of listed services MUST be discovered")
+
+	_, err = conn.GetCharacteristic("1234", "5678")
+	suite.Assert().Error(err, "unlisted service MUST NOT be discovered")
+	_, err = conn.GetCharacteristic("180f", "2a19")
+	suite.Assert().Error(err, "unlisted default service MUST NOT be discovered")
+
+	suite.Run("no listed services", func() {
+		suite.Require().NoError(suite.device.Disconnect(), "disconnect MUST succeed")
+		suite.device = devicefactory.NewDevice("AA:BB:CC:DD:EE:FF", suite.Logger)
+		err := suite.device.Connect(context.Background(), &device.ConnectOptions{
+			ConnectTimeout:     5 * time.Second,
+			DiscoverOnlyListed: true,
+		})
+		suite.Assert().ErrorContains(err, "no services are listed", "scoped discovery without services MUST fail")
+	})
+}
+
+func (suite *ConnectionTestSuite) TestMTU() {
+	// GOAL: Verify MTU() reports the ATT MTU of the live connection and 0 once disconnected
+	//
+	// TEST SCENARIO: Peripheral with MTU 185 → connect → MTU() is 185 → disconnect → MTU() is 0
+
+	suite.WithPeripheral().WithMTU(185)
+
+	suite.Require().NoError(suite.device.Disconnect(), "disconnect MUST succeed")
+	suite.ensureConnected()
+
+	conn := suite.device.GetConnection()
+	suite.Assert().Equal(185, conn.MTU(), "MTU MUST be the MTU of the live connection")
+
+	suite.Require().NoError(suite.device.Disconnect(), "disconnect MUST succeed")
+	suite.Assert().Zero(conn.MTU(), "MTU MUST be 0 when disconnected")
+}
+
+func (suite *ConnectionTestSuite) TestRSSI() {
+	// GOAL: Verify RSSI can be read on demand and polled into OnRSSI handlers while connected
+	//
+	// TEST SCENARIO: ReadRSSI() returns the peripheral RSSI → poll every 20ms → handler receives readings → disable → disconnect → ReadRSSI fails
+
+	suite.WithPeripheral().WithRSSI(-42).
+		WithService("180d").
+		WithCharacteristic("2a37", "read,notify", []byte{0x00, 0x48})
+
+	err := suite.device.Disconnect()
+	suite.Require().NoError(err, "disconnect MUST succeed")
+	suite.ensureConnected()
+
+	conn := suite.device.GetConnection()
+
+	suite.Run("read on demand", func() {
+		rssi, err := conn.ReadRSSI()
+		suite.Require().NoError(err, "ReadRSSI MUST succeed while connected")
+		suite.Assert().Equal(-42, rssi, "ReadRSSI MUST return the peripheral RSSI")
+	})
+
+	suite.Run("polling", func() {
+		readings := make(chan int, 16)
+		conn.OnRSSI(func(rssi int) {
+			select {
+			case readings <- rssi:
+			default:
+			}
+		})
+
+		suite.Require().NoError(conn.SetRSSIPollInterval(20*time.Millisecond), "SetRSSIPollInterval MUST succeed")
+		for i := 0; i < 2; i++ {
+			select {
+			case rssi := <-readings:
+				suite.Assert().Equal(-42, rssi, "polled RSSI MUST match the peripheral RSSI")
+			case <-time.After(time.Second):
+				suite.Fail("RSSI MUST be polled periodically")
+				return
+			}
+		}
+
+		suite.Require().NoError(conn.SetRSSIPollInterval(0), "disabling polling MUST succeed")
+		time.Sleep(50 * time.Millisecond) // Let an in-flight poll finish
+		for len(readings) > 0 {
+			<-readings
+		}
+		time.Sleep(100 * time.Millisecond)
+		suite.Assert().Empty(readings, "no readings MUST arrive after polling is disabled")
+	})
+
+	suite.Run("requires a connection", func() {
+		suite.Require().NoError(suite.device.Disconnect(), "disconnect MUST succeed")
+
+		_, err := conn.ReadRSSI()
+		suite.Assert().ErrorIs(err, device.ErrNotConnected, "ReadRSSI MUST fail when disconnected")
+		suite.Assert().ErrorIs(conn.SetRSSIPollInterval(time.Second), device.ErrNotConnected, "SetRSSIPollInterval MUST fail when disconnected")
+	})
+}
+
 // TestConnectionTestSuite runs the test suite
 func TestConnectionTestSuite(t *testing.T) {
 	suite.Run(t, new(ConnectionTestSuite))
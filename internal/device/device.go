@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"sort"
 	"time"
 )
 
@@ -96,7 +97,9 @@ type Scanner interface {
 }
 
 type Advertisement interface {
-	LocalName() string
+	LocalName() string         // Best available local name (complete if advertised, otherwise shortened)
+	CompleteLocalName() string // Complete Local Name AD field (0x09), "" if not advertised or unavailable
+	ShortLocalName() string    // Shortened Local Name AD field (0x08), "" if not advertised or unavailable
 	ManufacturerData() []byte
 	ServiceData() []struct {
 		UUID string
@@ -117,6 +120,8 @@ type Advertisement interface {
 type DeviceInfo interface {
 	ID() string
 	Name() string
+	CompleteName() string // Complete Local Name from the advertisement, "" if not advertised
+	ShortName() string    // Shortened Local Name from the advertisement, "" if not advertised
 	Address() string
 	RSSI() int
 	TxPower() *int
@@ -143,8 +148,55 @@ type Connection interface {
 	Services() []Service
 	GetService(uuid string) (Service, error)
 	GetCharacteristic(service, uuid string) (Characteristic, error)
-	Subscribe(opts []*SubscribeOptions, pattern StreamMode, maxRate time.Duration, callback func(*Record)) error
+	Characteristics() []Characteristic                            // All characteristics across all services
+	FindCharacteristicByUUID(uuid string) (Characteristic, error) // First characteristic with the UUID in any service
+	// Subscribe streams notifications of the characteristics to callback, paced as configured by stream
+	// (see StreamOptions).
+	Subscribe(opts []*SubscribeOptions, pattern StreamMode, stream StreamOptions, callback func(*Record)) error
 	ConnectionContext() context.Context // Returns context that's cancelled when connection errors occur
+	Pair(ctx context.Context) error     // Triggers pairing/bonding and waits for completion
+	IsPaired() bool                     // Returns true if pairing completed via Pair()
+	SubscriptionStats() SubscriptionStats
+	OnServicesChanged(handler func()) // Registers a handler fired on Service Changed (0x2A05) indications
+
+	// SetReadCacheTTL makes Read() of the characteristic return the last read or notified value while it is
+	// younger than ttl, instead of issuing a new ATT read. A ttl of 0 disables caching (default).
+	SetReadCacheTTL(service, uuid string, ttl time.Duration) error
+
+	// ReadLong reads a value longer than one ATT MTU with sequential Read Blob requests.
+	// Read() switches to it automatically when a response fills the MTU.
+	ReadLong(service, uuid string, timeout time.Duration) ([]byte, error)
+
+	// OnNotification registers a handler receiving every notification payload of the characteristic
+	// alongside Subscribe() callbacks, enabling notifications if no subscription has enabled them yet.
+	// The returned function unregisters the handler.
+	OnNotification(service, uuid string, handler func(data []byte)) (unregister func(), err error)
+
+	// SetNotifyHandler sets the handler receiving every notification of the characteristic as a Record holding
+	// its value, enabling notifications if no subscription has enabled them yet. It is the entry point for Go
+	// programs embedding blim and is registered like an OnNotification handler, except that a characteristic
+	// holds at most one: a later call replaces the handler and a nil handler removes it.
+	SetNotifyHandler(service, uuid string, handler func(Record)) error
+
+	// OnRawNotification registers a handler receiving every incoming notification of any characteristic with
+	// its raw payload and receive timestamp (µs), before subscriptions batch or aggregate it
+	OnRawNotification(handler func(uuid string, data []byte, tsUs int64))
+
+	// FlushWrites blocks until all writes, including write-without-response commands, have left the host,
+	// confirming delivery with a read barrier when a readable characteristic exists.
+	FlushWrites(ctx context.Context) error
+
+	EnabledCCCDs() []CCCDState      // Characteristics with notifications/indications enabled via Subscribe
+	EnabledNotifications() []string // UUIDs of characteristics with an enabled CCCD (see EnabledCCCDs)
+
+	SetIdleTimeout(timeout time.Duration) error // Changes ConnectOptions.IdleTimeout of the live connection (0 disables)
+	OnDisconnected(handler func(reason string)) // Registers a handler fired when the connection closes itself (e.g., idle)
+
+	ReadRSSI() (int, error)                           // Reads the signal strength (dBm) of the live connection
+	SetRSSIPollInterval(interval time.Duration) error // Reads the RSSI every interval and reports it to OnRSSI handlers (0 disables)
+	OnRSSI(handler func(rssi int))                    // Registers a handler receiving polled RSSI readings
+
+	MTU() int // ATT MTU of the live connection (0 if not connected or not reported by the stack)
 }
 
 // Service represents a GATT service interface
@@ -157,10 +209,13 @@ type Service interface {
 // CharacteristicInfo represents characteristic metadata
 type CharacteristicInfo interface {
 	UUID() string
+	ServiceUUID() string // UUID of the service holding the characteristic
 	KnownName() string
 	GetProperties() Properties
 	GetDescriptors() []Descriptor
-	RequiresAuthentication() bool // Returns true if characteristic requires pairing/authentication
+	RequiresAuthentication() bool      // Returns true if characteristic requires pairing/authentication
+	ReadRequiresAuthentication() bool  // Returns true if reads require pairing/authentication
+	WriteRequiresAuthentication() bool // Returns true if writes require pairing/authentication
 }
 
 // DescriptorInfo represents descriptor metadata
@@ -176,11 +231,13 @@ type DescriptorInfo interface {
 // CharacteristicReader provides read operations
 type CharacteristicReader interface {
 	Read(timeout time.Duration) ([]byte, error)
+	ReadCtx(ctx context.Context) ([]byte, error) // Bounded by the deadline of ctx and aborted when it is canceled
 }
 
 // CharacteristicWriter provides write operations
 type CharacteristicWriter interface {
 	Write(data []byte, withResponse bool, timeout time.Duration) error
+	WriteCtx(ctx context.Context, data []byte, withResponse bool) error // Bounded by the deadline of ctx and aborted when it is canceled
 }
 
 // DescriptorReader provides on-demand read operations for descriptors
@@ -196,6 +253,12 @@ type Characteristic interface {
 
 	HasParser() bool                              // Returns true if a parser is registered for this characteristic type
 	ParseValue(value []byte) (interface{}, error) // Parses value using registered parser
+
+	// ReadCached returns the last read or notified value if it is younger than ttl, otherwise reads from the device
+	ReadCached(ttl, timeout time.Duration) ([]byte, error)
+
+	// LastValue returns the last notified value and its timestamp (Unix microseconds); ok is false if none was received yet
+	LastValue() (value []byte, tsUs int64, ok bool)
 }
 
 // Descriptor combines descriptor information with read operations
@@ -227,14 +290,96 @@ type SubscribeOptions struct {
 	Service         string
 	Characteristics []string // can be empty
 	Indicate        bool     // true = Indicate, false = Notify (default)
+
+	// ChannelBuffer sets how many notifications of each listed characteristic are queued for delivery to
+	// subscriptions (0 = the default of 128). On overflow the oldest queued value is dropped, so a larger buffer
+	// absorbs longer bursts at the cost of memory and, when callbacks fall behind, latency. The queue is shared by
+	// all subscriptions of a characteristic and only grows: the largest buffer requested while connected applies.
+	ChannelBuffer int
 }
 
 // ConnectOptions defines BLE connection options
 type ConnectOptions struct {
 	Address               string
 	ConnectTimeout        time.Duration
+	DiscoveryTimeout      time.Duration // Bounds service/characteristic/descriptor discovery after the link is up (0 = no limit)
 	DescriptorReadTimeout time.Duration // Timeout for reading descriptor values (0 = skip reads)
-	Services              []SubscribeOptions
+
+	// SkipDescriptors stops discovery at characteristics: no descriptors are discovered or read, which makes
+	// connecting considerably faster. Characteristics then report no descriptors, so descriptor reads/writes,
+	// descriptor-based parsing (presentation/aggregate formats) and, on Linux, subscriptions (CCCD) are unavailable.
+	SkipDescriptors bool
+
+	// Services lists the services to subscribe to; with DiscoverOnlyListed it also scopes discovery to these
+	// services (all of their characteristics), which makes connecting to devices with many services much faster.
+	// Other services are then unknown to the connection.
+	Services           []SubscribeOptions
+	DiscoverOnlyListed bool
+
+	// RequireAll fails the connection with a *MissingAttributesError when any service or characteristic listed
+	// in Services is absent after discovery, instead of connecting to a device that lacks them
+	RequireAll bool
+
+	AutoPair bool // Pair and retry once when a read/write fails with an authentication-class ATT error
+
+	// MaxNotificationRate limits total subscription callback dispatches per second across all
+	// subscriptions of the connection (0 = unlimited). Records over the limit are handled per NotificationOverflow.
+	MaxNotificationRate  float64
+	NotificationOverflow OverflowPolicy
+
+	// CallbackWorkers is the number of goroutines running subscription callbacks (0 or 1 = inline on the
+	// subscription goroutine). With more workers, a slow callback no longer holds up other characteristics.
+	// Records of one characteristic are always delivered in order; in EveryUpdate mode different
+	// characteristics may be delivered concurrently and out of order, so callbacks must be safe for
+	// concurrent use. Batched, Aggregated and coalesced records keep the subscription's order.
+	CallbackWorkers int
+
+	// GATTCacheDir enables the on-disk GATT cache (empty = disabled): the attribute layout discovered for a device
+	// is stored there, keyed by peripheral identifier, and later connections use it instead of rediscovering once it
+	// validates against the Service Changed characteristic. A Service Changed indication invalidates the cache.
+	// Devices without Service Changed cannot be validated and are never cached.
+	GATTCacheDir string
+
+	// IdleTimeout disconnects the connection after this long without reads, writes or notifications
+	// (0 = disabled). Handlers registered via Connection.OnDisconnected receive DisconnectReasonIdle.
+	IdleTimeout time.Duration
+}
+
+// DisconnectReasonIdle is reported to Connection.OnDisconnected handlers when IdleTimeout elapsed
+const DisconnectReasonIdle = "idle"
+
+// OverflowPolicy defines how records exceeding the connection-wide notification rate are handled
+type OverflowPolicy int
+
+const (
+	OverflowDrop     OverflowPolicy = iota // Drop records over the limit
+	OverflowCoalesce                       // Merge records over the limit into the next dispatched record (latest value per characteristic wins)
+)
+
+// CCCDState describes a characteristic with notifications or indications enabled
+type CCCDState struct {
+	Service        string // Normalized service UUID
+	Characteristic string // Normalized characteristic UUID
+	Indicate       bool   // true = Indicate, false = Notify
+}
+
+// SubscriptionStats holds connection-wide subscription delivery counters
+type SubscriptionStats struct {
+	Delivered        uint64                           // Records dispatched to subscription callbacks
+	RateLimitDropped uint64                           // Records dropped by the connection-wide notification rate limit
+	Coalesced        uint64                           // Records merged into a later record by the connection-wide notification rate limit
+	Intervals        map[string]NotificationIntervals // Time between consecutive notifications, by characteristic UUID
+}
+
+// NotificationIntervals summarizes the time between consecutive notifications of a characteristic, measured at
+// receipt. Mean shows the actual notification rate; a StdDev large relative to Mean indicates jitter and a Max of
+// several Means indicates dropped packets.
+type NotificationIntervals struct {
+	Count  uint64 // Intervals measured (one less than the notifications received)
+	Min    time.Duration
+	Max    time.Duration
+	Mean   time.Duration
+	StdDev time.Duration // Population standard deviation
 }
 
 // StreamMode defines how subscription data is delivered
@@ -246,6 +391,23 @@ const (
 	StreamAggregated
 )
 
+// StreamOptions tunes how a subscription delivers records. The zero value applies the defaults: no rate limit
+// for StreamEveryUpdate, DefaultBatchedInterval ticks for the other modes, and no lifetime.
+type StreamOptions struct {
+	MaxRate  time.Duration    // Tick interval of StreamBatched/StreamAggregated (0 = default); ignored by StreamEveryUpdate
+	Lifetime time.Duration    // Tear the subscription down after this long, delivering a final Record with End set (0 = never)
+	Window   *AggregateWindow // Fixed wall-clock windows instead of MaxRate ticks; StreamAggregated only (nil = MaxRate ticks)
+}
+
+// AggregateWindow configures windowed aggregation for StreamAggregated subscriptions. Unlike MaxRate, which
+// paces delivery and hands out at most one queued value per characteristic per tick, a window flushes on a
+// steady timer: every Interval the notifications received during that window are drained and the latest value
+// of each characteristic is delivered in one Record, however fast or slow the device notifies.
+type AggregateWindow struct {
+	Interval  time.Duration // Window length; must be positive
+	EmitEmpty bool          // Deliver a Record with no values for windows without notifications (skipped otherwise)
+}
+
 // Record represents a subscription notification record
 type Record struct {
 	TsUs        int64
@@ -253,4 +415,63 @@ type Record struct {
 	Values      map[string][]byte   // Single value per characteristic (EveryUpdate/Aggregated modes)
 	BatchValues map[string][][]byte // Multiple values per characteristic (Batched mode)
 	Flags       uint32
+	End         bool     // Final record of a subscription torn down after its lifetime; carries no values
+	Initial     bool     // Synthetic record with values read when the subscription started, not notified
+	Order       []string // Keys of Values/BatchValues in the order they were first added (see Keys)
+}
+
+// SetValue stores the value of a characteristic in Values, recording the key in Order when first added
+func (r *Record) SetValue(uuid string, data []byte) {
+	if r.Values == nil {
+		r.Values = make(map[string][]byte)
+	}
+	if _, ok := r.Values[uuid]; !ok {
+		r.Order = append(r.Order, uuid)
+	}
+	r.Values[uuid] = data
+}
+
+// AppendBatchValues appends values of a characteristic to BatchValues, recording the key in Order when first added
+func (r *Record) AppendBatchValues(uuid string, values ...[]byte) {
+	if r.BatchValues == nil {
+		r.BatchValues = make(map[string][][]byte)
+	}
+	if _, ok := r.BatchValues[uuid]; !ok {
+		r.Order = append(r.Order, uuid)
+	}
+	r.BatchValues[uuid] = append(r.BatchValues[uuid], values...)
+}
+
+// Keys returns the keys of Values and BatchValues in a deterministic order: first as listed in Order,
+// then keys added without SetValue/AppendBatchValues, sorted.
+func (r *Record) Keys() []string {
+	keys := make([]string, 0, len(r.Values)+len(r.BatchValues))
+	seen := make(map[string]bool, cap(keys))
+	for _, key := range r.Order {
+		if seen[key] {
+			continue
+		}
+		_, inValues := r.Values[key]
+		_, inBatch := r.BatchValues[key]
+		if inValues || inBatch {
+			keys = append(keys, key)
+			seen[key] = true
+		}
+	}
+
+	var rest []string
+	for key := range r.Values {
+		if !seen[key] {
+			rest = append(rest, key)
+			seen[key] = true
+		}
+	}
+	for key := range r.BatchValues {
+		if !seen[key] {
+			rest = append(rest, key)
+			seen[key] = true
+		}
+	}
+	sort.Strings(rest)
+	return append(keys, rest...)
 }
@@ -337,6 +337,44 @@ func (suite *DeviceBasicTestSuite) TestNameUpdateBehavior() {
 	}`)
 }
 
+func (suite *DeviceBasicTestSuite) TestLocalNameFields() {
+	// GOAL: Verify both Complete and Shortened Local Name AD fields are retained alongside the best name
+	//
+	// TEST SCENARIO: Advertisement with short name only → scan response adds complete name → both kept, name follows LocalName
+
+	adv1 := testutils.CreateMockAdvertisementFromJSON(`{
+				"name": "Therm",
+				"shortName": "Therm",
+				"address": "AA:BB:CC:DD:EE:FF",
+				"rssi": -50,
+				"manufacturerData": null,
+				"serviceData": null,
+				"services": [],
+				"txPower": 127,
+				"connectable": true
+			}`).Build()
+
+	dev := devicefactory.NewDeviceFromAdvertisement(adv1, suite.helper.Logger)
+	suite.Assert().Equal("Therm", dev.Name())
+	suite.Assert().Equal("Therm", dev.ShortName())
+	suite.Assert().Empty(dev.CompleteName(), "complete name MUST be empty until advertised")
+
+	adv2 := testutils.CreateMockAdvertisementFromJSON(`{
+				"name": "Thermometer Kitchen",
+				"completeName": "Thermometer Kitchen",
+				"rssi": -45,
+				"manufacturerData": null,
+				"serviceData": null,
+				"services": [],
+				"txPower": 127
+			}`).Build()
+
+	dev.Update(adv2)
+	suite.Assert().Equal("Thermometer Kitchen", dev.Name())
+	suite.Assert().Equal("Thermometer Kitchen", dev.CompleteName())
+	suite.Assert().Equal("Therm", dev.ShortName(), "short name MUST survive advertisements without it")
+}
+
 func (suite *DeviceBasicTestSuite) TestDeviceWriteToCharacteristicErrors() {
 	// GOAL: Verify characteristic write returns appropriate errors for invalid operations
 	//
@@ -0,0 +1,23 @@
+package device
+
+import (
+	"fmt"
+	"math"
+)
+
+// DefaultPathLossExponent is the environmental factor of free space; indoor environments typically range 2-4
+const DefaultPathLossExponent = 2.0
+
+// EstimateDistance estimates the distance in meters to an advertiser with the log-distance path-loss model:
+//
+//	d = 10 ^ ((txPower - rssi) / (10 * n))
+//
+// txPower is the expected RSSI at 1 m in dBm (the advertised TX Power Level is the usual stand-in) and n is the
+// environmental path-loss exponent. The result is a coarse estimate: multipath, obstacles, antenna orientation and
+// uncalibrated TX power easily put it off by a factor of two or more.
+func EstimateDistance(rssi, txPower int, n float64) (float64, error) {
+	if n <= 0 || math.IsNaN(n) || math.IsInf(n, 0) {
+		return 0, fmt.Errorf("invalid path-loss exponent %g: must be a positive number", n)
+	}
+	return math.Pow(10, float64(txPower-rssi)/(10*n)), nil
+}
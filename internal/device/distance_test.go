@@ -0,0 +1,38 @@
+package device
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// GOAL: Verify the log-distance path-loss model and its exponent validation
+//
+// TEST SCENARIO: RSSI equal to TX power → 1 m; 20 dB loss → 10 m (n=2) or 100 m (n=1); higher n → shorter estimate; invalid n → error
+func TestEstimateDistance(t *testing.T) {
+	d, err := EstimateDistance(-59, -59, DefaultPathLossExponent)
+	require.NoError(t, err)
+	assert.InDelta(t, 1.0, d, 1e-9, "RSSI equal to TX power MUST estimate 1 m")
+
+	d, err = EstimateDistance(-79, -59, 2)
+	require.NoError(t, err)
+	assert.InDelta(t, 10.0, d, 1e-9)
+
+	d, err = EstimateDistance(-79, -59, 1)
+	require.NoError(t, err)
+	assert.InDelta(t, 100.0, d, 1e-9)
+
+	d, err = EstimateDistance(-79, -59, 4)
+	require.NoError(t, err)
+	assert.InDelta(t, 3.1623, d, 1e-4, "higher path-loss exponent MUST shorten the estimate")
+
+	d, err = EstimateDistance(-49, -59, 2)
+	require.NoError(t, err)
+	assert.Less(t, d, 1.0, "RSSI above TX power MUST estimate less than 1 m")
+
+	for _, n := range []float64{0, -2} {
+		_, err = EstimateDistance(-70, -59, n)
+		assert.Error(t, err, "n=%g MUST be rejected", n)
+	}
+}
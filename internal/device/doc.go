@@ -8,4 +8,11 @@
 //   - Real-time notification streaming with multiple patterns
 //   - Thread-safe concurrent operations with mutex protection
 //   - Object pooling for high-performance notification handling
+//
+// # Embedding
+//
+// Go programs can use the package directly, without the Lua layer: connect a Device and register a
+// per-characteristic handler with Connection.SetNotifyHandler, which receives every notification as a
+// Record keyed by the characteristic UUID. Connection.Subscribe covers the batched and aggregated
+// streaming modes the Lua subscriptions are built on.
 package device
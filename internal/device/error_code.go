@@ -0,0 +1,62 @@
+package device
+
+import (
+	"context"
+	"errors"
+)
+
+// Error codes reported to scripts by ErrorCode. Connection state failures use their ConnectionState value
+// ("not_connected", "already_connected", "not_initialized").
+const (
+	ErrorCodeTimeout      = "timeout"
+	ErrorCodeUnsupported  = "unsupported"
+	ErrorCodeBluetoothOff = "bluetooth_off"
+	ErrorCodeNotFound     = "not_found"
+	ErrorCodeATT          = "att_error"
+	ErrorCodeCanceled     = "canceled"
+	ErrorCodeUnknown      = "error"
+)
+
+// ErrorCode classifies err into a stable, machine-readable code based on the typed errors of this package.
+// Returns ErrorCodeUnknown for errors that match no known type.
+func ErrorCode(err error) string {
+	var (
+		attErr  *ATTError
+		connErr *ConnectionError
+		nfErr   *NotFoundError
+		maErr   *MissingAttributesError
+		descErr *DescriptorError
+	)
+
+	switch {
+	case err == nil:
+		return ""
+	case errors.As(err, &attErr):
+		return ErrorCodeATT
+	case errors.As(err, &connErr):
+		return string(connErr.State)
+	case errors.As(err, &nfErr), errors.As(err, &maErr):
+		return ErrorCodeNotFound
+	case errors.Is(err, ErrTimeout), errors.Is(err, context.DeadlineExceeded):
+		return ErrorCodeTimeout
+	case errors.Is(err, ErrUnsupported):
+		return ErrorCodeUnsupported
+	case errors.Is(err, ErrBluetoothOff):
+		return ErrorCodeBluetoothOff
+	case errors.Is(err, context.Canceled):
+		return ErrorCodeCanceled
+	case errors.As(err, &descErr):
+		return descErr.Reason
+	default:
+		return ErrorCodeUnknown
+	}
+}
+
+// ATTErrorCodeOf returns the ATT error code carried by err, if any
+func ATTErrorCodeOf(err error) (ATTErrorCode, bool) {
+	var attErr *ATTError
+	if errors.As(err, &attErr) {
+		return attErr.Code, true
+	}
+	return 0, false
+}
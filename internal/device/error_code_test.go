@@ -0,0 +1,54 @@
+package device
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestErrorCode(t *testing.T) {
+	// GOAL: Verify typed errors map to stable codes, including when wrapped
+	//
+	// TEST SCENARIO: Wrap each typed error → ErrorCode returns the matching code → unknown error → "error"
+
+	tests := []struct {
+		name     string
+		err      error
+		expected string
+	}{
+		{name: "nil", err: nil, expected: ""},
+		{name: "not connected", err: fmt.Errorf("read failed: %w", ErrNotConnected), expected: "not_connected"},
+		{name: "already connected", err: ErrAlreadyConnected, expected: "already_connected"},
+		{name: "timeout", err: fmt.Errorf("read characteristic 2a37: %w", ErrTimeout), expected: ErrorCodeTimeout},
+		{name: "context deadline", err: context.DeadlineExceeded, expected: ErrorCodeTimeout},
+		{name: "unsupported", err: fmt.Errorf("no read property: %w", ErrUnsupported), expected: ErrorCodeUnsupported},
+		{name: "bluetooth off", err: ErrBluetoothOff, expected: ErrorCodeBluetoothOff},
+		{name: "not found", err: &NotFoundError{Resource: "characteristic", UUIDs: []string{"180d", "2a37"}}, expected: ErrorCodeNotFound},
+		{name: "att error", err: fmt.Errorf("write failed: %w", &ATTError{Code: ATTErrWriteNotPermitted}), expected: ErrorCodeATT},
+		{name: "canceled", err: context.Canceled, expected: ErrorCodeCanceled},
+		{name: "descriptor error", err: &DescriptorError{Reason: "parse_error"}, expected: "parse_error"},
+		{name: "unknown", err: errors.New("boom"), expected: ErrorCodeUnknown},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, ErrorCode(tt.err))
+		})
+	}
+}
+
+func TestATTErrorCodeOf(t *testing.T) {
+	// GOAL: Verify the ATT code is extracted from wrapped ATT errors only
+	//
+	// TEST SCENARIO: Wrapped ATTError → code and true → other error → false
+
+	code, ok := ATTErrorCodeOf(fmt.Errorf("read failed: %w", &ATTError{Code: ATTErrInsufficientAuthentication}))
+	assert.True(t, ok, "ATT error MUST be detected")
+	assert.Equal(t, ATTErrInsufficientAuthentication, code)
+
+	_, ok = ATTErrorCodeOf(ErrTimeout)
+	assert.False(t, ok, "non-ATT error MUST NOT report an ATT code")
+}
@@ -0,0 +1,55 @@
+//go:build test
+
+package device_test
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/go-ble/ble"
+	"github.com/sirupsen/logrus"
+	"github.com/srg/blim/internal/device"
+	goble "github.com/srg/blim/internal/device/go-ble"
+	"github.com/srg/blim/internal/devicefactory"
+	"github.com/srg/blim/internal/simulator"
+)
+
+// ExampleConnection_SetNotifyHandler receives heart rate notifications in plain Go, without Lua.
+// A simulated peripheral stands in for the hardware; a real program just connects to the device address.
+func ExampleConnection_SetNotifyHandler() {
+	profile, err := simulator.ParseProfile([]byte(`{
+		"services": [{"uuid": "180D", "characteristics": [{"uuid": "2A37", "properties": "notify"}]}],
+		"script": [{"delay_ms": 10, "service": "180D", "char": "2A37", "value": [0, 72]}]
+	}`))
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+	originalFactory := goble.DeviceFactory
+	goble.DeviceFactory = func() (ble.Device, error) { return simulator.NewDevice(profile) }
+	defer func() { goble.DeviceFactory = originalFactory }()
+
+	logger := logrus.New()
+	logger.SetLevel(logrus.ErrorLevel)
+
+	dev := devicefactory.NewDevice("AA:BB:CC:DD:EE:FF", logger)
+	if err := dev.Connect(context.Background(), &device.ConnectOptions{ConnectTimeout: 5 * time.Second}); err != nil {
+		fmt.Println(err)
+		return
+	}
+	defer dev.Disconnect()
+
+	records := make(chan device.Record, 1)
+	err = dev.GetConnection().SetNotifyHandler("180d", "2a37", func(r device.Record) {
+		records <- r
+	})
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+
+	record := <-records
+	fmt.Printf("heart rate: %d bpm\n", record.Values["2a37"][1])
+	// Output: heart rate: 72 bpm
+}
@@ -0,0 +1,70 @@
+package goble
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/go-ble/ble"
+	"github.com/go-ble/ble/darwin"
+)
+
+// ----------------------------
+// Adapter Selection
+// ----------------------------
+
+// DefaultAdapter identifies the system default Bluetooth adapter.
+//
+// The only supported backend is macOS CoreBluetooth, which exposes a single central manager bound to the
+// controller the OS currently uses (the built-in one, or a USB dongle when macOS has switched to it),
+// so "default" is the only available adapter and selecting it has no further effect.
+const DefaultAdapter = "default"
+
+var (
+	adapterMutex    sync.RWMutex
+	selectedAdapter = DefaultAdapter
+)
+
+// AvailableAdapters returns the identifiers of the Bluetooth adapters that can be selected
+func AvailableAdapters() []string {
+	return []string{DefaultAdapter}
+}
+
+// SetAdapter selects the adapter used by the default DeviceFactory.
+// An empty identifier selects the default adapter. Returns an error if the adapter doesn't exist.
+func SetAdapter(id string) error {
+	resolved, err := resolveAdapter(id)
+	if err != nil {
+		return err
+	}
+
+	adapterMutex.Lock()
+	selectedAdapter = resolved
+	adapterMutex.Unlock()
+	return nil
+}
+
+// Adapter returns the identifier of the selected adapter
+func Adapter() string {
+	adapterMutex.RLock()
+	defer adapterMutex.RUnlock()
+	return selectedAdapter
+}
+
+// resolveAdapter maps a user-supplied adapter identifier to an available adapter
+func resolveAdapter(id string) (string, error) {
+	switch strings.ToLower(strings.TrimSpace(id)) {
+	case "", DefaultAdapter:
+		return DefaultAdapter, nil
+	default:
+		return "", fmt.Errorf("bluetooth adapter %q not found (available: %s)", id, strings.Join(AvailableAdapters(), ", "))
+	}
+}
+
+// newAdapterDevice creates a ble.Device bound to the given adapter; the only adapter is the CoreBluetooth default
+func newAdapterDevice(adapter string) (ble.Device, error) {
+	if _, err := resolveAdapter(adapter); err != nil {
+		return nil, err
+	}
+	return darwin.NewDevice()
+}
@@ -15,12 +15,14 @@ func NewBLEAdvertisement(adv ble.Advertisement) device.Advertisement {
 	return &BLEAdvertisement{adv: adv}
 }
 
-func (a *BLEAdvertisement) LocalName() string        { return a.adv.LocalName() }
-func (a *BLEAdvertisement) ManufacturerData() []byte { return a.adv.ManufacturerData() }
-func (a *BLEAdvertisement) TxPowerLevel() int        { return int(a.adv.TxPowerLevel()) }
-func (a *BLEAdvertisement) Connectable() bool        { return a.adv.Connectable() }
-func (a *BLEAdvertisement) RSSI() int                { return a.adv.RSSI() }
-func (a *BLEAdvertisement) Addr() string             { return a.adv.Addr().String() }
+func (a *BLEAdvertisement) LocalName() string         { return a.adv.LocalName() }
+func (a *BLEAdvertisement) CompleteLocalName() string { return a.adField(adTypeCompleteLocalName) }
+func (a *BLEAdvertisement) ShortLocalName() string    { return a.adField(adTypeShortLocalName) }
+func (a *BLEAdvertisement) ManufacturerData() []byte  { return a.adv.ManufacturerData() }
+func (a *BLEAdvertisement) TxPowerLevel() int         { return int(a.adv.TxPowerLevel()) }
+func (a *BLEAdvertisement) Connectable() bool         { return a.adv.Connectable() }
+func (a *BLEAdvertisement) RSSI() int                 { return a.adv.RSSI() }
+func (a *BLEAdvertisement) Addr() string              { return a.adv.Addr().String() }
 
 func (a *BLEAdvertisement) ServiceData() []struct {
 	UUID string
@@ -65,6 +67,51 @@ func (a *BLEAdvertisement) SolicitedService() []string {
 	return result
 }
 
+// AD types of the local name fields (Core Specification Supplement, Part A, 1.2)
+const (
+	adTypeShortLocalName    = 0x08
+	adTypeCompleteLocalName = 0x09
+)
+
+// rawAdvertisement is implemented by ble.Advertisement backends that expose the raw advertising and
+// scan response payloads (HCI on Linux). CoreBluetooth only reports the merged local name.
+type rawAdvertisement interface {
+	Data() []byte
+	ScanResponse() []byte
+}
+
+// adField returns the value of the AD structure of the given type from the raw advertising data,
+// falling back to the scan response. Returns "" when the backend doesn't expose raw payloads.
+func (a *BLEAdvertisement) adField(adType byte) string {
+	raw, ok := a.adv.(rawAdvertisement)
+	if !ok {
+		return ""
+	}
+	if v, ok := findADField(raw.Data(), adType); ok {
+		return string(v)
+	}
+	if v, ok := findADField(raw.ScanResponse(), adType); ok {
+		return string(v)
+	}
+	return ""
+}
+
+// findADField scans length-type-value AD structures for the first one of adType.
+// Parsing stops at a zero length (padding) or a structure running past the end of data.
+func findADField(data []byte, adType byte) ([]byte, bool) {
+	for len(data) > 1 {
+		length := int(data[0])
+		if length == 0 || length >= len(data) {
+			break
+		}
+		if data[1] == adType {
+			return data[2 : 1+length], true
+		}
+		data = data[1+length:]
+	}
+	return nil, false
+}
+
 // Unwrap returns the underlying ble.Advertisement for internal use within go-ble package
 func (a *BLEAdvertisement) Unwrap() ble.Advertisement {
 	return a.adv
@@ -0,0 +1,107 @@
+package goble
+
+import (
+	"context"
+	"fmt"
+	"hash/fnv"
+	"sync"
+
+	"github.com/sirupsen/logrus"
+	"github.com/srg/blim/internal/device"
+	"github.com/srg/blim/internal/groutine"
+)
+
+// ----------------------------
+// Callback Worker Pool
+// ----------------------------
+
+// callbackTask is a single subscription callback invocation
+type callbackTask struct {
+	callback func(*device.Record)
+	record   *device.Record
+}
+
+// callbackPool runs subscription callbacks on a fixed set of workers (see device.ConnectOptions.CallbackWorkers).
+// Each worker owns a FIFO queue; tasks routed to the same worker run one at a time, in submission order.
+type callbackPool struct {
+	queues []chan callbackTask
+	wg     sync.WaitGroup
+	logger *logrus.Logger
+}
+
+func newCallbackPool(workers int, logger *logrus.Logger) *callbackPool {
+	p := &callbackPool{
+		queues: make([]chan callbackTask, workers),
+		logger: logger,
+	}
+	for i := range p.queues {
+		queue := make(chan callbackTask, DefaultChannelBuffer)
+		p.queues[i] = queue
+		p.wg.Add(1)
+		groutine.Go(nil, fmt.Sprintf("callback-worker-%d", i), func(ctx context.Context) {
+			defer p.wg.Done()
+			for task := range queue {
+				p.invoke(task)
+			}
+		})
+	}
+	return p
+}
+
+// workerFor returns the worker index for a routing key
+func (p *callbackPool) workerFor(key string) int {
+	h := fnv.New32a()
+	_, _ = h.Write([]byte(key))
+	return int(h.Sum32() % uint32(len(p.queues)))
+}
+
+// submit queues a callback on the given worker. Blocks while the worker queue is full
+// (backpressure on the subscription goroutine); the task is dropped if ctx is done first.
+func (p *callbackPool) submit(ctx context.Context, worker int, task callbackTask) {
+	select {
+	case p.queues[worker] <- task:
+	case <-ctx.Done():
+	}
+}
+
+// stop closes all queues and waits for workers to finish the tasks already queued
+func (p *callbackPool) stop() {
+	for _, queue := range p.queues {
+		close(queue)
+	}
+	p.wg.Wait()
+}
+
+// invoke runs a callback, recovering from panics so one failing callback doesn't stop the worker
+func (p *callbackPool) invoke(task callbackTask) {
+	defer func() {
+		if r := recover(); r != nil && p.logger != nil {
+			p.logger.WithField("panic", r).Error("Subscription callback panicked on worker")
+		}
+	}()
+	task.callback(task.record)
+}
+
+// deliver invokes the subscription callback inline, or on the callback pool when one is configured.
+//
+// Ordering: records of the same characteristic are always delivered in order. In EveryUpdate mode
+// (without coalescing) records are routed per characteristic, so different characteristics may be
+// delivered concurrently and out of order relative to each other. Batched, Aggregated and coalesced
+// records span several characteristics and are routed per subscription, keeping the subscription's order.
+func (c *BLEConnection) deliver(sub *Subscription, record *device.Record) {
+	if c.callbackPool == nil {
+		sub.Callback(record)
+		return
+	}
+
+	// Pooled value buffers are released once dispatch returns, so the queued record owns copies
+	record = copyRecord(record)
+
+	worker := sub.worker
+	if sub.Mode == device.StreamEveryUpdate && !(c.rateLimiter != nil && c.overflowPolicy == device.OverflowCoalesce) && len(record.Values) == 1 {
+		for uuid := range record.Values {
+			worker = c.callbackPool.workerFor(uuid)
+		}
+	}
+	c.callbackPool.submit(sub.ctx, worker, callbackTask{callback: sub.Callback, record: record})
+}
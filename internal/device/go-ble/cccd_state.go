@@ -0,0 +1,55 @@
+package goble
+
+import (
+	"sort"
+
+	"github.com/srg/blim/internal/device"
+)
+
+// CCCD (Client Characteristic Configuration Descriptor) values enabled by BLESubscribe
+const (
+	cccdDisabled uint32 = 0x0000
+	cccdNotify   uint32 = 0x0001
+	cccdIndicate uint32 = 0x0002
+)
+
+// EnabledCCCDs returns the characteristics with notifications or indications enabled through Subscribe,
+// sorted by service and characteristic UUID. Internally managed subscriptions (Service Changed) are not included.
+func (c *BLEConnection) EnabledCCCDs() []device.CCCDState {
+	c.connMutex.RLock()
+	defer c.connMutex.RUnlock()
+
+	var states []device.CCCDState
+	for serviceUUID, service := range c.services {
+		for charUUID, char := range service.Characteristics {
+			switch char.cccd.Load() {
+			case cccdNotify:
+				states = append(states, device.CCCDState{Service: serviceUUID, Characteristic: charUUID})
+			case cccdIndicate:
+				states = append(states, device.CCCDState{Service: serviceUUID, Characteristic: charUUID, Indicate: true})
+			}
+		}
+	}
+
+	sort.Slice(states, func(i, j int) bool {
+		if states[i].Service != states[j].Service {
+			return states[i].Service < states[j].Service
+		}
+		return states[i].Characteristic < states[j].Characteristic
+	})
+	return states
+}
+
+// EnabledNotifications returns the sorted, distinct UUIDs of the characteristics reported by EnabledCCCDs
+func (c *BLEConnection) EnabledNotifications() []string {
+	var uuids []string
+	seen := make(map[string]bool)
+	for _, state := range c.EnabledCCCDs() {
+		if !seen[state.Characteristic] {
+			seen[state.Characteristic] = true
+			uuids = append(uuids, state.Characteristic)
+		}
+	}
+	sort.Strings(uuids)
+	return uuids
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"runtime/debug"
 	"sync"
 	"sync/atomic"
 	"time"
@@ -38,6 +39,9 @@ const (
 	// DefaultReadTimeout is the default timeout for characteristic read operations.
 	// This prevents indefinite blocking if a device becomes unresponsive during a read.
 	DefaultReadTimeout = 5 * time.Second
+
+	// DefaultWriteTimeout bounds WriteCtx when its context has no deadline
+	DefaultWriteTimeout = 5 * time.Second
 )
 
 // BLEValue represents a BLE notification value.
@@ -109,6 +113,7 @@ func drainAndReleaseChannel(ch chan *BLEValue) {
 
 type BLECharacteristic struct {
 	uuid        string
+	serviceUUID string
 	knownName   string
 	properties  device.Properties
 	descriptors []device.Descriptor
@@ -119,14 +124,22 @@ type BLECharacteristic struct {
 	updates chan *BLEValue
 	closed  atomic.Bool
 	mu      sync.RWMutex
-	subs    []func(*BLEValue)
+	subs    []*notificationHandler
+	cache   readCache // Last read/notified value for cached reads (see SetReadCacheTTL)
+
+	removeNotifyHandler func() // Unregisters the handler set via BLEConnection.SetNotifyHandler, guarded by mu
+
+	cccd             atomic.Uint32 // Enabled CCCD value (cccdNotify/cccdIndicate), see EnabledCCCDs
+	lastNotified     []byte        // Last notified value (nil until the first notification)
+	lastNotifiedTsUs int64         // Timestamp of the last notification (Unix microseconds)
 }
 
-func NewCharacteristic(c *ble.Characteristic, buffer int, conn *BLEConnection, descriptors []device.Descriptor) *BLECharacteristic {
+func NewCharacteristic(c *ble.Characteristic, serviceUUID string, buffer int, conn *BLEConnection, descriptors []device.Descriptor) *BLECharacteristic {
 	rawUUID := c.UUID.String()
 	uuid := device.NormalizeUUID(rawUUID)
 
 	return &BLECharacteristic{
+		serviceUUID: serviceUUID,
 		uuid:        uuid,                                // store normalized
 		knownName:   bledb.LookupCharacteristic(rawUUID), // lookup using raw form if DB expects dashed
 		BLEChar:     c,
@@ -153,23 +166,71 @@ func (c *BLECharacteristic) EnqueueValue(v *BLEValue) {
 		}).Debug("[EnqueueValue] BLE notification arrived, enqueueing")
 	}
 
+	// Held while sending so growUpdates can't swap the channel underneath
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
 	select {
 	case c.updates <- v:
 	default:
-		// Channel full, drop the oldest
-		old := <-c.updates
-		old.Flags |= FlagDropped
-		releaseBLEValue(old)
+		// Channel full, drop the oldest (unless a consumer just took it)
+		select {
+		case old := <-c.updates:
+			old.Flags |= FlagDropped
+			releaseBLEValue(old)
+		default:
+		}
 		// Recheck closed before second send (could have closed while we were dropping)
-		if !c.closed.Load() {
-			c.updates <- v
-		} else {
+		if c.closed.Load() {
+			releaseBLEValue(v)
+			return
+		}
+		select {
+		case c.updates <- v:
+		default:
 			releaseBLEValue(v)
 		}
 	}
 }
 
+// updatesChan returns the channel queuing notified values for subscriptions
+func (c *BLECharacteristic) updatesChan() chan *BLEValue {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.updates
+}
+
+// growUpdates enlarges the updates channel to hold at least buffer values, moving queued values over.
+// The channel never shrinks, so the largest buffer requested by any subscription of the characteristic applies.
+func (c *BLECharacteristic) growUpdates(buffer int) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.closed.Load() || cap(c.updates) >= buffer {
+		return
+	}
+	updates := make(chan *BLEValue, buffer)
+move:
+	for {
+		select {
+		case v := <-c.updates:
+			updates <- v
+		default:
+			break move
+		}
+	}
+	c.updates = updates
+}
+
+// notificationHandler is a callback registered with BLECharacteristic.Subscribe; its pointer identifies the
+// registration, so the same function can be registered more than once and removed independently.
+type notificationHandler struct {
+	fn func(*BLEValue)
+}
+
 // Subscribe registers a callback function to be invoked when this characteristic receives notifications.
+// Returns a function that unregisters the callback; calling it more than once is a no-op.
+// A panicking callback is recovered and logged, so it cannot take down the notification goroutine.
 //
 // IMPORTANT: BLEValue objects are pooled and reused for performance. The callback MUST copy
 // v.Data immediately if it needs to retain the data beyond the callback invocation, as the
@@ -183,24 +244,62 @@ func (c *BLECharacteristic) EnqueueValue(v *BLEValue) {
 //	    copy(dataCopy, v.Data)
 //	    // Use dataCopy safely after callback returns
 //	})
-func (c *BLECharacteristic) Subscribe(fn func(*BLEValue)) {
+func (c *BLECharacteristic) Subscribe(fn func(*BLEValue)) (unsubscribe func()) {
+	h := &notificationHandler{fn: fn}
 	c.mu.Lock()
-	defer c.mu.Unlock()
-	c.subs = append(c.subs, fn)
+	c.subs = append(c.subs, h)
+	c.mu.Unlock()
+
+	return func() {
+		c.mu.Lock()
+		defer c.mu.Unlock()
+		// Copy on removal: notifySubscribers iterates a snapshot of the slice without holding the lock
+		subs := make([]*notificationHandler, 0, len(c.subs))
+		for _, s := range c.subs {
+			if s != h {
+				subs = append(subs, s)
+			}
+		}
+		c.subs = subs
+	}
 }
 
-func (c *BLECharacteristic) notifySubscribers(v *BLEValue) {
+// hasHandlers reports whether a handler registered via Subscribe still observes notifications
+func (c *BLECharacteristic) hasHandlers() bool {
 	c.mu.RLock()
 	defer c.mu.RUnlock()
-	for _, fn := range c.subs {
-		fn(v)
+	return len(c.subs) > 0
+}
+
+func (c *BLECharacteristic) notifySubscribers(v *BLEValue) {
+	c.mu.RLock()
+	subs := c.subs
+	c.mu.RUnlock()
+
+	// Called without the lock, so a callback may unregister itself or others
+	for _, h := range subs {
+		c.callSubscriber(h.fn, v)
 	}
 }
 
+// callSubscriber runs fn, keeping a panic from escaping onto the notification goroutine
+func (c *BLECharacteristic) callSubscriber(fn func(*BLEValue), v *BLEValue) {
+	defer func() {
+		if r := recover(); r != nil && c.connection != nil && c.connection.logger != nil {
+			c.connection.logger.Errorf("Notification handler of %s panic (recovered): %v\nStack:\n%s", c.uuid, r, string(debug.Stack()))
+		}
+	}()
+	fn(v)
+}
+
 func (c *BLECharacteristic) UUID() string {
 	return c.uuid
 }
 
+func (c *BLECharacteristic) ServiceUUID() string {
+	return c.serviceUUID
+}
+
 func (c *BLECharacteristic) KnownName() string {
 	return c.knownName
 }
@@ -213,12 +312,31 @@ func (c *BLECharacteristic) GetDescriptors() []device.Descriptor {
 	return c.descriptors
 }
 
-// RequiresAuthentication returns true if the characteristic requires pairing/authentication.
-// Detection heuristic:
-//   - AuthenticatedSignedWrites property indicates explicit authentication requirement
-//   - No properties exposed (props == 0) likely indicates hidden due to encryption/pairing requirements
-//     (Common on macOS/iOS when peripheral requires encryption before revealing properties)
+// RequiresAuthentication returns true if the characteristic requires pairing/authentication for reads or writes.
+// See ReadRequiresAuthentication and WriteRequiresAuthentication for the per-operation detection heuristics.
 func (c *BLECharacteristic) RequiresAuthentication() bool {
+	return c.ReadRequiresAuthentication() || c.WriteRequiresAuthentication()
+}
+
+// ReadRequiresAuthentication returns true if reading the characteristic likely requires pairing/authentication,
+// i.e. its properties are hidden (see propertiesHidden).
+func (c *BLECharacteristic) ReadRequiresAuthentication() bool {
+	return c.propertiesHidden()
+}
+
+// WriteRequiresAuthentication returns true if writing the characteristic likely requires pairing/authentication:
+//   - AuthenticatedSignedWrites property indicates explicit authentication requirement
+//   - Hidden properties (see propertiesHidden)
+func (c *BLECharacteristic) WriteRequiresAuthentication() bool {
+	if c.properties == nil {
+		return false
+	}
+	return c.properties.AuthenticatedSignedWrites() != nil || c.propertiesHidden()
+}
+
+// propertiesHidden returns true if no properties are exposed (props == 0), which likely indicates they are hidden
+// due to encryption/pairing requirements (common on macOS/iOS when peripheral requires encryption before revealing properties).
+func (c *BLECharacteristic) propertiesHidden() bool {
 	if c.properties == nil {
 		return false
 	}
@@ -228,28 +346,17 @@ func (c *BLECharacteristic) RequiresAuthentication() bool {
 	// the OS hides characteristic properties (returns 0) until pairing completes.
 	// The peripheral's GATT database may define properties, but CoreBluetooth masks them
 	// for security reasons. This is a common indicator that authentication is required.
-	hasAnyProperty := c.properties.Broadcast() != nil ||
-		c.properties.Read() != nil ||
-		c.properties.Write() != nil ||
-		c.properties.WriteWithoutResponse() != nil ||
-		c.properties.Notify() != nil ||
-		c.properties.Indicate() != nil ||
-		c.properties.AuthenticatedSignedWrites() != nil ||
-		c.properties.ExtendedProperties() != nil
-
-	if !hasAnyProperty {
-		return true // No properties exposed - macOS/iOS hides properties until paired
-	}
-
-	// AuthenticatedSignedWrites explicitly requires authentication
-	if c.properties.AuthenticatedSignedWrites() != nil {
-		return true
-	}
-
+	//
 	// Note: ExtendedProperties alone is not a reliable security indicator
 	// Would need to read descriptor 0x2900 to confirm security requirements
-
-	return false
+	return c.properties.Broadcast() == nil &&
+		c.properties.Read() == nil &&
+		c.properties.Write() == nil &&
+		c.properties.WriteWithoutResponse() == nil &&
+		c.properties.Notify() == nil &&
+		c.properties.Indicate() == nil &&
+		c.properties.AuthenticatedSignedWrites() == nil &&
+		c.properties.ExtendedProperties() == nil
 }
 
 func (c *BLECharacteristic) HasParser() bool {
@@ -275,15 +382,53 @@ func (c *BLECharacteristic) SetValue(value []byte) {
 	c.value = value
 }
 
+// LastValue returns a copy of the last notified value and its timestamp (Unix microseconds).
+// Returns ok == false if no notification has been received yet.
+func (c *BLECharacteristic) LastValue() (value []byte, tsUs int64, ok bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	if c.lastNotified == nil {
+		return nil, 0, false
+	}
+	return append([]byte(nil), c.lastNotified...), c.lastNotifiedTsUs, true
+}
+
+// setLastNotified records a notified value
+func (c *BLECharacteristic) setLastNotified(val *BLEValue) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.lastNotified = append(c.lastNotified[:0:0], val.Data...)
+	c.lastNotifiedTsUs = val.TsUs
+}
+
 // Read reads the current value of the characteristic from the device with the specified timeout.
 // This implements the device.CharacteristicReader interface.
 func (c *BLECharacteristic) Read(timeout time.Duration) ([]byte, error) {
 	return c.ReadWithTimeout(timeout)
 }
 
+// ReadCtx reads the current value of the characteristic, bounded by the deadline of ctx (DefaultReadTimeout
+// if it has none) and aborted when ctx is canceled. An expired deadline is reported as device.ErrTimeout.
+// Like Read, it returns a fresh cached value instead when a read cache TTL is set.
+func (c *BLECharacteristic) ReadCtx(ctx context.Context) ([]byte, error) {
+	if value, ok := c.cache.get(c.cache.defaultTTL()); ok {
+		return value, nil
+	}
+	return c.readFromDevice(ctx, contextTimeout(ctx, DefaultReadTimeout))
+}
+
 // ReadWithTimeout reads the current value of the characteristic from the device with the specified timeout.
 // This prevents indefinite blocking if the device becomes unresponsive during a read operation.
+// When a read cache TTL is set (see BLEConnection.SetReadCacheTTL), a fresh cached value is returned instead.
 func (c *BLECharacteristic) ReadWithTimeout(timeout time.Duration) ([]byte, error) {
+	if value, ok := c.cache.get(c.cache.defaultTTL()); ok {
+		return value, nil
+	}
+	return c.readFromDevice(context.Background(), timeout)
+}
+
+// readFromDevice issues an ATT read and refreshes the read cache on success
+func (c *BLECharacteristic) readFromDevice(ctx context.Context, timeout time.Duration) ([]byte, error) {
 	if c.connection == nil {
 		return nil, fmt.Errorf("no connection available for reading characteristic %s", c.uuid)
 	}
@@ -300,6 +445,44 @@ func (c *BLECharacteristic) ReadWithTimeout(timeout time.Duration) ([]byte, erro
 		return nil, fmt.Errorf("characteristic %s does not support read operations: %w", c.uuid, device.ErrUnsupported)
 	}
 
+	data, err := c.readValue(ctx, timeout)
+	if err != nil && ctx.Err() == nil && c.connection.autoPairOnAuthError(err, c) {
+		data, err = c.readValue(ctx, contextTimeout(ctx, timeout))
+	}
+	if err == nil {
+		c.cache.store(data)
+	}
+	return data, err
+}
+
+// readValue performs the read request without checking the read property.
+// Used directly by pairing, since CoreBluetooth hides properties of protected characteristics until paired.
+// A response that fills the ATT MTU may be truncated, so the value is then re-read in full with Read Blob requests.
+func (c *BLECharacteristic) readValue(ctx context.Context, timeout time.Duration) ([]byte, error) {
+	deadline := time.Now().Add(timeout)
+	data, err := c.doRead(ctx, timeout, func(client ble.Client) ([]byte, error) {
+		return client.ReadCharacteristic(c.BLEChar)
+	})
+	if err != nil || !fillsReadMTU(data) {
+		return data, err
+	}
+
+	long, err := c.readLongValue(ctx, time.Until(deadline))
+	if err != nil {
+		// Keep the (possibly truncated) single-read value rather than failing a read that succeeded
+		if c.connection.logger != nil {
+			c.connection.logger.WithError(err).WithField("characteristic", c.uuid).
+				Warn("Long read failed, value may be truncated at the ATT MTU")
+		}
+		return data, nil
+	}
+	return long, nil
+}
+
+// doRead issues a read request on the connection's client, bounded by timeout and aborted when ctx is done
+func (c *BLECharacteristic) doRead(ctx context.Context, timeout time.Duration, read func(client ble.Client) ([]byte, error)) ([]byte, error) {
+	c.connection.idle.touch()
+
 	// Add connection mutex locking to prevent race condition
 	c.connection.connMutex.RLock()
 	if c.connection.client == nil {
@@ -317,7 +500,7 @@ func (c *BLECharacteristic) ReadWithTimeout(timeout time.Duration) ([]byte, erro
 	resultCh := make(chan readResult, 1)
 
 	groutine.Go(context.Background(), fmt.Sprintf("ble-characteristic-read-%s", c.uuid), func(ctx context.Context) {
-		data, err := client.ReadCharacteristic(c.BLEChar)
+		data, err := read(client)
 		resultCh <- readResult{data: data, err: err}
 	})
 
@@ -341,6 +524,8 @@ func (c *BLECharacteristic) ReadWithTimeout(timeout time.Duration) ([]byte, erro
 		return result.data, nil
 	case <-time.After(timeout):
 		return nil, fmt.Errorf("read characteristic %s after %v: %w", c.uuid, timeout, device.ErrTimeout)
+	case <-ctx.Done():
+		return nil, fmt.Errorf("read characteristic %s: %w", c.uuid, contextAborted(ctx))
 	}
 }
 
@@ -348,6 +533,18 @@ func (c *BLECharacteristic) ReadWithTimeout(timeout time.Duration) ([]byte, erro
 // This implements the device.CharacteristicWriter interface.
 // The withResponse parameter determines if write-with-response (true) or write-without-response (false) is used.
 func (c *BLECharacteristic) Write(data []byte, withResponse bool, timeout time.Duration) error {
+	return c.write(context.Background(), data, withResponse, timeout)
+}
+
+// WriteCtx writes data to the characteristic like Write, bounded by the deadline of ctx (DefaultWriteTimeout if it
+// has none) and aborted when ctx is canceled. An expired deadline is reported as device.ErrTimeout.
+// A canceled write may still reach the device, since an ATT request cannot be recalled once sent.
+func (c *BLECharacteristic) WriteCtx(ctx context.Context, data []byte, withResponse bool) error {
+	return c.write(ctx, data, withResponse, contextTimeout(ctx, DefaultWriteTimeout))
+}
+
+// write checks the write properties and performs the write, pairing and retrying once on authentication errors
+func (c *BLECharacteristic) write(ctx context.Context, data []byte, withResponse bool, timeout time.Duration) error {
 	if c.connection == nil {
 		return fmt.Errorf("no connection available for writing characteristic %s", c.uuid)
 	}
@@ -366,6 +563,21 @@ func (c *BLECharacteristic) Write(data []byte, withResponse bool, timeout time.D
 		return fmt.Errorf("characteristic %s does not support write operations: %w", c.uuid, device.ErrUnsupported)
 	}
 
+	err := c.writeValue(ctx, data, withResponse, timeout)
+	if err != nil && ctx.Err() == nil && c.connection.autoPairOnAuthError(err, nil) {
+		err = c.writeValue(ctx, data, withResponse, contextTimeout(ctx, timeout))
+	}
+	if err == nil {
+		// The device may store a different value than written (e.g., clamped), so the next read must hit the device
+		c.cache.invalidate()
+	}
+	return err
+}
+
+// writeValue performs the write request without checking the write properties
+func (c *BLECharacteristic) writeValue(ctx context.Context, data []byte, withResponse bool, timeout time.Duration) error {
+	c.connection.idle.touch()
+
 	// Add connection mutex locking to prevent race conditions
 	c.connection.connMutex.RLock()
 	if c.connection.client == nil {
@@ -385,7 +597,10 @@ func (c *BLECharacteristic) Write(data []byte, withResponse bool, timeout time.D
 	}
 	resultCh := make(chan writeResult, 1)
 
+	// Tracked until the client returns, even if this call times out first, so FlushWrites can wait for it
+	c.connection.writes.begin()
 	groutine.Go(context.Background(), fmt.Sprintf("ble-characteristic-write-%s", c.uuid), func(ctx context.Context) {
+		defer c.connection.writes.end()
 		// BLE client WriteCharacteristic: noResponse parameter is opposite of withResponse
 		err := client.WriteCharacteristic(c.BLEChar, data, !withResponse)
 		resultCh <- writeResult{err: err}
@@ -404,7 +619,26 @@ func (c *BLECharacteristic) Write(data []byte, withResponse bool, timeout time.D
 		return nil
 	case <-time.After(timeout):
 		return fmt.Errorf("write characteristic %s after %v: %w", c.uuid, timeout, device.ErrTimeout)
+	case <-ctx.Done():
+		return fmt.Errorf("write characteristic %s: %w", c.uuid, contextAborted(ctx))
+	}
+}
+
+// contextTimeout returns the time left until the deadline of ctx, or fallback if ctx has no deadline
+func contextTimeout(ctx context.Context, fallback time.Duration) time.Duration {
+	if deadline, ok := ctx.Deadline(); ok {
+		return time.Until(deadline)
+	}
+	return fallback
+}
+
+// contextAborted describes why ctx stopped an operation: an expired deadline (device.ErrTimeout) or cancellation
+func contextAborted(ctx context.Context) error {
+	cause := context.Cause(ctx)
+	if errors.Is(cause, context.DeadlineExceeded) {
+		return fmt.Errorf("%w: %w", device.ErrTimeout, cause)
 	}
+	return cause
 }
 
 // CloseUpdates safely closes the updates channel (once only, thread-safe)
@@ -2,14 +2,15 @@ package goble
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"sort"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/go-ble/ble"
-	"github.com/go-ble/ble/darwin"
 	"github.com/sirupsen/logrus"
 	"github.com/srg/blim/internal/bledb"
 	"github.com/srg/blim/internal/device"
@@ -29,17 +30,23 @@ const (
 
 	// DefaultBatchedInterval is the default rate limiting interval for batched/aggregated modes
 	DefaultBatchedInterval = 100 * time.Millisecond
+
+	// DefaultPairingRetryInterval is the delay between pairing attempts while the pairing prompt is pending
+	DefaultPairingRetryInterval = 500 * time.Millisecond
+
+	// DefaultAutoPairTimeout is how long auto-pairing waits for the user to accept the pairing prompt
+	DefaultAutoPairTimeout = 30 * time.Second
 )
 
 // ----------------------------
 // Device Factory
 // ----------------------------
 
-// DeviceFactory creates ble.Device instances (can be overridden in tests)
+// DeviceFactory creates ble.Device instances bound to the selected adapter (can be overridden in tests)
 //
 //nolint:revive // DeviceFactory name is intentional for test mocking as device.DeviceFactory
 var DeviceFactory = func() (ble.Device, error) {
-	return darwin.NewDevice()
+	return newAdapterDevice(Adapter())
 }
 
 // ----------------------------
@@ -51,19 +58,45 @@ type BLEConnection struct {
 	client                ble.Client
 	logger                *logrus.Logger
 	writeMutex            sync.Mutex
+	writes                writeTracker // In-flight writes awaited by FlushWrites
 	connMutex             sync.RWMutex
 	isConnected           bool
+	isPaired              atomic.Bool   // Set once pairing/bonding completed via Pair()
+	autoPair              bool          // Pair and retry once on authentication-class ATT errors
 	descriptorReadTimeout time.Duration // Timeout for reading descriptor values during discovery
+	identifier            string        // Peripheral identifier the connection was dialed with, keys the GATT cache
+	gattCacheDir          string        // GATT cache directory ("" = disabled), invalidated on Service Changed
+
+	rateLimiter    *notificationRateLimiter // Connection-wide callback dispatch limit (nil = unlimited)
+	overflowPolicy device.OverflowPolicy    // How records over the rate limit are handled
+	stats          subscriptionCounters
+	callbackPool   *callbackPool // Runs subscription callbacks off the subscription goroutines (nil = inline)
 
 	services map[string]*BLEService
 
+	servicesChangedMutex    sync.Mutex
+	servicesChangedHandlers []func() // Invoked on Service Changed (0x2A05) indications
+
+	rawNotificationMutex    sync.Mutex
+	rawNotificationHandlers []func(uuid string, data []byte, tsUs int64) // Invoked with every incoming notification
+
+	idle                 idleMonitor // Disconnects after IdleTimeout without reads, writes or notifications
+	disconnectedMutex    sync.Mutex
+	disconnectedHandlers []func(reason string) // Invoked when the connection closes itself (e.g., idle)
+
+	rssiPoll     rssiPoller // Periodic RSSI reads, see SetRSSIPollInterval
+	rssiMutex    sync.Mutex
+	rssiHandlers []func(rssi int) // Invoked with every polled RSSI reading
+
 	subMgr *SubscriptionManager
 	ctx    context.Context
 	cancel context.CancelCauseFunc
 }
 
 // GetCharacteristic retrieves a characteristic by service and characteristic UUID.
-// Both UUIDs are normalized for consistent lookup (lowercase, no dashes).
+// Both UUIDs are normalized for consistent lookup (lowercase, no dashes), so "2A37", "2a37"
+// and the full 128-bit form resolve to the same characteristic. The characteristic may also be
+// referenced by its known name (e.g., "Heart Rate Measurement", case-insensitive).
 // Returns a NotFoundError if the service or characteristic is not found.
 func (c *BLEConnection) GetCharacteristic(service, uuid string) (device.Characteristic, error) {
 	// Normalize UUIDs for a consistent lookup
@@ -75,12 +108,28 @@ func (c *BLEConnection) GetCharacteristic(service, uuid string) (device.Characte
 		return nil, &device.NotFoundError{Resource: "service", UUIDs: []string{service}}
 	}
 
-	char, ok := svc.Characteristics[normalizedCharUUID]
-	if !ok {
-		return nil, &device.NotFoundError{Resource: "characteristic", UUIDs: []string{service, uuid}}
+	if char, ok := svc.Characteristics[normalizedCharUUID]; ok {
+		return char, nil
+	}
+	if char := findCharacteristicByName(svc, uuid); char != nil {
+		return char, nil
 	}
 
-	return char, nil
+	return nil, &device.NotFoundError{Resource: "characteristic", UUIDs: []string{service, uuid}}
+}
+
+// findCharacteristicByName returns the service characteristic whose known name matches (case-insensitive), or nil
+func findCharacteristicByName(svc *BLEService, name string) device.Characteristic {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return nil
+	}
+	for _, char := range svc.GetCharacteristics() {
+		if knownName := char.KnownName(); knownName != "" && strings.EqualFold(knownName, name) {
+			return char
+		}
+	}
+	return nil
 }
 
 // Services returns all discovered BLE services for this connection.
@@ -116,26 +165,85 @@ func (c *BLEConnection) GetService(uuid string) (device.Service, error) {
 	return svc, nil
 }
 
+// Characteristics returns all discovered characteristics across all services.
+// Ordered by service UUID, then by characteristic UUID. Thread-safe.
+func (c *BLEConnection) Characteristics() []device.Characteristic {
+	var result []device.Characteristic
+	for _, svc := range c.Services() {
+		result = append(result, svc.GetCharacteristics()...)
+	}
+	return result
+}
+
+// MTU returns the ATT MTU of the live connection, 0 when not connected or the stack does not report it
+func (c *BLEConnection) MTU() int {
+	c.connMutex.RLock()
+	defer c.connMutex.RUnlock()
+	if !c.isConnectedInternal() {
+		return 0
+	}
+	conn := c.client.Conn()
+	if conn == nil {
+		return 0
+	}
+	return conn.TxMTU()
+}
+
+// FindCharacteristicByUUID retrieves a characteristic by its UUID regardless of service.
+// If several services expose the same characteristic UUID, the first match in Characteristics() order is returned.
+// Returns a NotFoundError if no service has the characteristic.
+func (c *BLEConnection) FindCharacteristicByUUID(uuid string) (device.Characteristic, error) {
+	normalizedUUID := device.NormalizeUUID(uuid)
+	for _, char := range c.Characteristics() {
+		if char.UUID() == normalizedUUID {
+			return char, nil
+		}
+	}
+	return nil, &device.NotFoundError{Resource: "characteristic", UUIDs: []string{uuid}}
+}
+
 // ProcessCharacteristicNotification processes incoming characteristic notification data
 // This method is extracted to allow reuse in both production subscriptions and tests
 func (c *BLEConnection) ProcessCharacteristicNotification(char *BLECharacteristic, data []byte) {
 	// Create a new BLE value from the received data
 	val := newBLEValue(data)
 
-	// Update the characteristic's value
+	c.idle.touch()
+
+	// Update the characteristic's value and the read cache
 	char.SetValue(data)
+	char.cache.store(data)
+	char.setLastNotified(val)
+	c.stats.intervals.record(char.uuid, val.TsUs)
+
+	// Raw taps see the payload before any mode-specific delivery
+	c.fireRawNotification(char.uuid, val)
 
 	// Enqueue the value for any waiting consumers
 	char.EnqueueValue(val)
 
 	// Notify all subscribers
 	char.notifySubscribers(val)
+
+	if char.uuid == serviceChangedCharUUID {
+		c.fireServicesChanged()
+	}
 }
 
-// SimulateNotification provides a proxy method for testing/simulation capabilities
-// This method calls ProcessCharacteristicNotification internally
-func (c *BLEConnection) SimulateNotification(char *BLECharacteristic, data []byte) {
-	c.ProcessCharacteristicNotification(char, data)
+// SimulateNotification injects data as a notification of the characteristic, taking the same path as a
+// notification received from the peripheral (value and read cache update, subscriptions, OnNotification and SetNotifyHandler handlers).
+// Returns a NotFoundError if the characteristic doesn't exist.
+func (c *BLEConnection) SimulateNotification(service, uuid string, data []byte) error {
+	char, err := c.GetCharacteristic(service, uuid)
+	if err != nil {
+		return err
+	}
+	bleChar, ok := char.(*BLECharacteristic)
+	if !ok {
+		return fmt.Errorf("characteristic %s does not support simulated notifications", uuid)
+	}
+	c.ProcessCharacteristicNotification(bleChar, data)
+	return nil
 }
 
 func NewBLEConnection(logger *logrus.Logger) *BLEConnection {
@@ -171,6 +279,13 @@ func (c *BLEConnection) Connect(ctx context.Context, address string, opts *devic
 		// If the field wasn't touched, use default; if explicitly 0, skip reads
 		c.descriptorReadTimeout = DefaultDescriptorReadTimeout
 	}
+	c.autoPair = opts.AutoPair
+
+	c.rateLimiter = nil
+	if opts.MaxNotificationRate > 0 {
+		c.rateLimiter = newNotificationRateLimiter(opts.MaxNotificationRate)
+	}
+	c.overflowPolicy = opts.NotificationOverflow
 
 	c.logger.WithFields(logrus.Fields{
 		"address": address,
@@ -200,9 +315,11 @@ func (c *BLEConnection) Connect(ctx context.Context, address string, opts *devic
 		return fmt.Errorf("failed to connect to device with address \"%s\": %w", address, err)
 	}
 
-	// Discover services and characteristics
-	c.logger.WithField("address", address).Debug("Discovering services and characteristics...")
-	bleProfile, err := client.DiscoverProfile(true)
+	// Discover services and characteristics (and read descriptors), scoped by the options and bounded by DiscoveryTimeout
+	discoveryCtx, cancelDiscovery := newDiscoveryContext(ctx, opts.DiscoveryTimeout)
+	defer cancelDiscovery()
+
+	bleProfile, err := c.loadOrDiscoverProfile(discoveryCtx, client, address, opts)
 	if err != nil {
 		c.logger.WithFields(logrus.Fields{
 			"address": address,
@@ -235,6 +352,16 @@ func (c *BLEConnection) Connect(ctx context.Context, address string, opts *devic
 		}
 
 		for _, bleCharacteristic := range bleSvc.Characteristics {
+			// Descriptor reads can stall as well; stop between characteristics once discovery is over time
+			if discoveryCtx.Err() != nil {
+				err := discoveryAborted(discoveryCtx, opts.DiscoveryTimeout)
+				c.logger.WithError(err).WithField("address", address).Error("Failed to discover profile")
+				if cancelErr := client.CancelConnection(); cancelErr != nil {
+					c.logger.WithField("cancel_error", cancelErr).Warn("Failed to cancel connection during profile discovery failure")
+				}
+				return fmt.Errorf("failed to discover profile: %w", err)
+			}
+
 			charRawUUID := bleCharacteristic.UUID.String()
 			charUUID := device.NormalizeUUID(charRawUUID)
 			c.logger.WithFields(logrus.Fields{
@@ -248,9 +375,15 @@ func (c *BLEConnection) Connect(ctx context.Context, address string, opts *devic
 				// which means descriptors cannot be read. This is a limitation of the go-ble/ble Darwin implementation.
 				// The descriptors are listed for informational purposes, but their values will be nil.
 
+				// With SkipDescriptors, descriptors the stack may still hold from an earlier full discovery are ignored
+				bleDescriptors := bleCharacteristic.Descriptors
+				if opts.SkipDescriptors {
+					bleDescriptors = nil
+				}
+
 				// Create descriptors with values (reads are best-effort, won't fail characteristic creation)
-				descriptors := make([]device.Descriptor, 0, len(bleCharacteristic.Descriptors))
-				for idx, d := range bleCharacteristic.Descriptors {
+				descriptors := make([]device.Descriptor, 0, len(bleDescriptors))
+				for idx, d := range bleDescriptors {
 					descriptors = append(descriptors, newDescriptor(d, c, client, c.descriptorReadTimeout, uint8(idx), c.logger))
 				}
 
@@ -283,7 +416,7 @@ func (c *BLEConnection) Connect(ctx context.Context, address string, opts *devic
 				})
 
 				// Create BLECharacteristic with pre-created descriptors
-				characteristic = NewCharacteristic(bleCharacteristic, DefaultChannelBuffer, c, descriptors)
+				characteristic = NewCharacteristic(bleCharacteristic, svcUUID, DefaultChannelBuffer, c, descriptors)
 				svc.Characteristics[charUUID] = characteristic
 			} else {
 				// Reconnecting - update live handle and recreate channel if closed on disconnect
@@ -300,9 +433,37 @@ func (c *BLEConnection) Connect(ctx context.Context, address string, opts *devic
 		}
 	}
 
+	// Fail fast on firmware that lacks listed services or characteristics
+	if opts.RequireAll {
+		missing := device.FindMissingAttributes(opts.Services,
+			func(service string) bool {
+				_, ok := c.services[device.NormalizeUUID(service)]
+				return ok
+			},
+			func(service, char string) bool {
+				_, err := c.GetCharacteristic(service, char)
+				return err == nil
+			})
+		if len(missing) > 0 {
+			err := &device.MissingAttributesError{Missing: missing}
+			c.logger.WithError(err).WithField("address", address).Error("Required attributes missing after discovery")
+			if cancelErr := client.CancelConnection(); cancelErr != nil {
+				c.logger.WithField("cancel_error", cancelErr).Warn("Failed to cancel connection after missing required attributes")
+			}
+			return err
+		}
+	}
+
 	// Mark as connected and assign client
 	c.client = client
 	c.isConnected = true
+	c.identifier = address
+	c.gattCacheDir = opts.GATTCacheDir
+
+	// Start callback workers only once connected, so a failed connect leaves no goroutines behind
+	if opts.CallbackWorkers > 1 {
+		c.callbackPool = newCallbackPool(opts.CallbackWorkers, c.logger)
+	}
 
 	// Set up context for subscriptions - derive from caller's context to tie lifecycle
 	// Use WithCancelCause to propagate connection errors to all subscribers
@@ -328,6 +489,12 @@ func (c *BLEConnection) Connect(ctx context.Context, address string, opts *devic
 		c.logger.Debug("Client does not support Disconnected() channel (non-Darwin platform?)")
 	}
 
+	// Watch for GATT changes on peripherals with a dynamic database
+	c.subscribeServiceChanged(client)
+
+	// Auto-disconnect after a period without activity
+	c.idle.start(opts.IdleTimeout, c.disconnectIdle)
+
 	// Count total characteristics across all services
 	totalChars := 0
 	for _, svc := range c.services {
@@ -366,6 +533,10 @@ func (c *BLEConnection) Disconnect() error {
 	}
 	c.subMgr.CancelAll()
 
+	// Stop the idle timer and RSSI polling, the connection is going away anyway
+	c.idle.stop()
+	c.rssiPoll.stop()
+
 	// Grab client and cancel the function to release the lock before blocking waits
 	client := c.client
 	cancel := c.cancel
@@ -403,6 +574,12 @@ func (c *BLEConnection) Disconnect() error {
 		c.logger.Debug("All subscription goroutines exited")
 	}
 
+	// Finish callbacks already queued on the worker pool
+	if pool := c.callbackPool; pool != nil {
+		c.callbackPool = nil
+		pool.stop()
+	}
+
 	// Unsubscribe from remote BLE notifications before canceling the connection
 	if c.logger != nil {
 		c.logger.Debug("Unsubscribing from remote BLE notifications...")
@@ -415,9 +592,13 @@ func (c *BLEConnection) Disconnect() error {
 	// Drain and close per-characteristic update channels
 	for _, service := range servicesCopy {
 		for _, char := range service.Characteristics {
-			drainAndReleaseChannel(char.updates)
+			drainAndReleaseChannel(char.updatesChan())
 			// Close channel to signal EOF - will be recreated on reconnect
 			char.CloseUpdates()
+			// Cached values may be stale after reconnecting
+			char.cache.invalidate()
+			char.cccd.Store(cccdDisabled)
+			c.stats.intervals.restart(char.uuid)
 		}
 	}
 
@@ -466,6 +647,8 @@ func (c *BLEConnection) tryUnsubscribe(client ble.Client, char *BLECharacteristi
 		}
 		return fmt.Errorf("%s: notify=%v, indicate=%v", charUUID, err1, err2)
 	}
+	char.cccd.Store(cccdDisabled)
+	c.stats.intervals.restart(char.uuid)
 
 	if c.logger != nil {
 		c.logger.WithFields(logrus.Fields{
@@ -518,6 +701,139 @@ func (c *BLEConnection) ConnectionContext() context.Context {
 	return c.ctx
 }
 
+// IsPaired returns true if pairing/bonding with the device completed via Pair()
+func (c *BLEConnection) IsPaired() bool {
+	return c.isPaired.Load()
+}
+
+// Pair triggers pairing/bonding with the connected device and waits for completion.
+//
+// CoreBluetooth has no explicit pairing API: the OS initiates pairing (showing a system
+// prompt on macOS) when an attribute requiring authentication or encryption is accessed.
+// Pair reads such a characteristic and retries while the device reports insufficient
+// authentication/encryption, until the read succeeds or ctx is done.
+func (c *BLEConnection) Pair(ctx context.Context) error {
+	if !c.IsConnected() {
+		return fmt.Errorf("pair: %w", device.ErrNotConnected)
+	}
+
+	target, err := c.findPairingTarget(ctx)
+	if err != nil {
+		return fmt.Errorf("pair: %w", err)
+	}
+
+	return c.pairWith(ctx, target)
+}
+
+// pairWith triggers pairing by reading the target characteristic until the read succeeds or ctx is done
+func (c *BLEConnection) pairWith(ctx context.Context, target *BLECharacteristic) error {
+	c.logger.WithField("char_uuid", target.uuid).Info("Initiating pairing (accept the system pairing prompt if shown)...")
+
+	if ctx.Err() != nil {
+		return fmt.Errorf("pairing not completed: %w", context.Cause(ctx))
+	}
+
+	for {
+		_, err := target.readValue(ctx, DefaultReadTimeout)
+		if err == nil {
+			c.isPaired.Store(true)
+			c.logger.WithField("char_uuid", target.uuid).Info("Pairing completed")
+			return nil
+		}
+
+		if ctx.Err() != nil {
+			return fmt.Errorf("pairing not completed: %w", context.Cause(ctx))
+		}
+		if !isPairingPending(err) {
+			return fmt.Errorf("pairing failed: %w", err)
+		}
+
+		c.logger.WithError(err).Debug("Pairing pending, retrying...")
+
+		select {
+		case <-time.After(DefaultPairingRetryInterval):
+		case <-ctx.Done():
+			return fmt.Errorf("pairing not completed: %w", context.Cause(ctx))
+		}
+	}
+}
+
+// findPairingTarget selects a characteristic whose access triggers pairing.
+// Prefers characteristics whose read is flagged by ReadRequiresAuthentication(); otherwise probes readable
+// characteristics for an insufficient authentication/encryption ATT error, those flagged by
+// WriteRequiresAuthentication() first. Probing stops once ctx is done.
+func (c *BLEConnection) findPairingTarget(ctx context.Context) (*BLECharacteristic, error) {
+	var protected, readable []*BLECharacteristic
+	for _, svc := range c.Services() {
+		for _, ch := range svc.GetCharacteristics() {
+			char, ok := ch.(*BLECharacteristic)
+			if !ok || char.BLEChar == nil {
+				continue
+			}
+			if char.ReadRequiresAuthentication() {
+				return char, nil
+			}
+			if readProps := char.properties.Read(); readProps != nil && readProps.Value() != 0 {
+				if char.WriteRequiresAuthentication() {
+					protected = append(protected, char)
+				} else {
+					readable = append(readable, char)
+				}
+			}
+		}
+	}
+
+	for _, char := range append(protected, readable...) {
+		if ctx.Err() != nil {
+			return nil, fmt.Errorf("pairing target probe aborted: %w", context.Cause(ctx))
+		}
+		if _, err := char.readValue(ctx, DefaultReadTimeout); err != nil && isAuthenticationError(err) {
+			return char, nil
+		}
+	}
+
+	return nil, fmt.Errorf("no characteristic requiring authentication found: %w", device.ErrUnsupported)
+}
+
+// autoPairOnAuthError initiates pairing if auto-pair is enabled and err is an authentication-class ATT error.
+// The target characteristic is used to trigger pairing when set; otherwise one is selected automatically.
+// Returns true if pairing completed and the failed operation should be retried.
+func (c *BLEConnection) autoPairOnAuthError(err error, target *BLECharacteristic) bool {
+	if !c.autoPair || !isAuthenticationError(err) {
+		return false
+	}
+
+	c.logger.WithError(err).Info("Authentication required, auto-pairing...")
+
+	ctx, cancel := context.WithTimeout(c.ConnectionContext(), DefaultAutoPairTimeout)
+	defer cancel()
+
+	var pairErr error
+	if target != nil {
+		pairErr = c.pairWith(ctx, target)
+	} else {
+		pairErr = c.Pair(ctx)
+	}
+	if pairErr != nil {
+		c.logger.WithError(pairErr).Warn("Auto-pairing failed")
+		return false
+	}
+	return true
+}
+
+// isAuthenticationError reports whether err is an ATT error that pairing/bonding can resolve
+func isAuthenticationError(err error) bool {
+	return device.IsATTError(err, device.ATTErrInsufficientAuthentication) ||
+		device.IsATTError(err, device.ATTErrInsufficientEncryption) ||
+		device.IsATTError(err, device.ATTErrInsufficientEncryptionKey)
+}
+
+// isPairingPending reports whether err indicates that access is blocked until pairing completes
+func isPairingPending(err error) bool {
+	return isAuthenticationError(err) ||
+		errors.Is(err, device.ErrTimeout) // Read blocks while the system pairing prompt is displayed
+}
+
 // validateSubscribeOptions validates service and characteristics existence and notification support
 func (c *BLEConnection) validateSubscribeOptions(opts *device.SubscribeOptions, requireNotificationSupport bool) (map[string]*BLECharacteristic, error) {
 	// Comprehensive validation - collect ALL issues before failing
@@ -672,6 +988,11 @@ func (c *BLEConnection) BLESubscribe(opts *device.SubscribeOptions) error {
 				}).Error("Failed to subscribe to characteristic")
 			}
 		} else {
+			if opts.Indicate {
+				char.cccd.Store(cccdIndicate)
+			} else {
+				char.cccd.Store(cccdNotify)
+			}
 			if c.logger != nil {
 				c.logger.WithFields(logrus.Fields{
 					"serviceUUID": opts.Service,
@@ -762,7 +1083,7 @@ func (c *BLEConnection) unsubscribeInternal(opts *device.SubscribeOptions) error
 		// Drain per-characteristic update channels and release BLEValue objects
 		for _, service := range servicesCopy {
 			for _, char := range service.Characteristics {
-				drainAndReleaseChannel(char.updates)
+				drainAndReleaseChannel(char.updatesChan())
 			}
 		}
 
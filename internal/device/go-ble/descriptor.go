@@ -170,6 +170,7 @@ func (d *BLEDescriptor) Read(timeout time.Duration) ([]byte, error) {
 	}
 	client := d.connection.client
 	d.connection.connMutex.RUnlock()
+	d.connection.idle.touch()
 
 	// Perform read with timeout
 	type readResult struct {
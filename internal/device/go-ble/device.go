@@ -49,6 +49,8 @@ type BLEDevice struct {
 	// Device data
 	id                 string
 	name               string
+	completeName       string
+	shortName          string
 	address            string
 	rssi               int
 	txPower            *int
@@ -88,6 +90,8 @@ func NewBLEDeviceFromAdvertisement(adv device.Advertisement, logger *logrus.Logg
 
 	// Set advertisement-specific data
 	dev.name = adv.LocalName()
+	dev.completeName = adv.CompleteLocalName()
+	dev.shortName = adv.ShortLocalName()
 	dev.rssi = adv.RSSI()
 	dev.connectable = adv.Connectable()
 	dev.manufData = adv.ManufacturerData()
@@ -152,6 +156,20 @@ func (d *BLEDevice) Name() string {
 	return d.name
 }
 
+// CompleteName returns the Complete Local Name last seen in an advertisement, "" if none was seen
+func (d *BLEDevice) CompleteName() string {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+	return d.completeName
+}
+
+// ShortName returns the Shortened Local Name last seen in an advertisement, "" if none was seen
+func (d *BLEDevice) ShortName() string {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+	return d.shortName
+}
+
 func (d *BLEDevice) Address() string {
 	d.mu.RLock()
 	defer d.mu.RUnlock()
@@ -297,6 +315,14 @@ func (d *BLEDevice) Update(adv device.Advertisement) {
 	d.rssi = adv.RSSI()
 	d.lastSeen = time.Now()
 
+	// Keep both name fields: the complete name is often only in the scan response
+	if name := adv.CompleteLocalName(); name != "" {
+		d.completeName = name
+	}
+	if name := adv.ShortLocalName(); name != "" {
+		d.shortName = name
+	}
+
 	// Update name if it wasn't available before or changed
 	if name := adv.LocalName(); name != "" {
 		d.name = name
@@ -402,6 +428,9 @@ func (d *BLEDevice) WriteToCharacteristic(uuid string, data []byte) error {
 	conn.writeMutex.Lock()
 	defer conn.writeMutex.Unlock()
 
+	conn.writes.begin()
+	defer conn.writes.end()
+
 	// Write data in chunks
 	for len(data) > 0 {
 		n := len(data)
@@ -0,0 +1,104 @@
+package goble
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/go-ble/ble"
+	"github.com/srg/blim/internal/device"
+	"github.com/srg/blim/internal/groutine"
+)
+
+// newDiscoveryContext bounds the discovery phase of Connect by timeout (0 = only by ctx)
+func newDiscoveryContext(ctx context.Context, timeout time.Duration) (context.Context, context.CancelFunc) {
+	if timeout > 0 {
+		return context.WithTimeout(ctx, timeout)
+	}
+	return context.WithCancel(ctx)
+}
+
+// discoverProfile runs profile discovery until it completes or ctx is done. Discovery is scoped by opts:
+// DiscoverOnlyListed limits it to the services of opts.Services and SkipDescriptors stops it at characteristics.
+// The go-ble discovery is not cancellable itself; callers abort it by cancelling the connection on error.
+func discoverProfile(ctx context.Context, client ble.Client, opts *device.ConnectOptions) (*ble.Profile, error) {
+	var serviceUUIDs []ble.UUID
+	if opts.DiscoverOnlyListed {
+		if len(opts.Services) == 0 {
+			return nil, fmt.Errorf("discovery limited to listed services, but no services are listed")
+		}
+		for _, svc := range opts.Services {
+			u, err := ble.Parse(device.NormalizeUUID(svc.Service))
+			if err != nil {
+				return nil, fmt.Errorf("invalid service UUID %q: %w", svc.Service, err)
+			}
+			serviceUUIDs = append(serviceUUIDs, u)
+		}
+	}
+
+	type result struct {
+		profile *ble.Profile
+		err     error
+	}
+	resultCh := make(chan result, 1)
+	groutine.Go(context.Background(), "ble-connection-discover-profile", func(context.Context) {
+		var profile *ble.Profile
+		var err error
+		if serviceUUIDs == nil && !opts.SkipDescriptors {
+			profile, err = client.DiscoverProfile(true)
+		} else {
+			profile, err = discoverScoped(client, serviceUUIDs, !opts.SkipDescriptors)
+		}
+		resultCh <- result{profile, err}
+	})
+
+	select {
+	case r := <-resultCh:
+		return r.profile, r.err
+	case <-ctx.Done():
+		return nil, discoveryAborted(ctx, opts.DiscoveryTimeout)
+	}
+}
+
+// discoverScoped discovers the given services (all when empty) with their characteristics and, if requested,
+// descriptors. Services outside the filter are dropped, since some stacks return every cached service.
+func discoverScoped(client ble.Client, serviceUUIDs []ble.UUID, descriptors bool) (*ble.Profile, error) {
+	services, err := client.DiscoverServices(serviceUUIDs)
+	if err != nil {
+		return nil, fmt.Errorf("failed to discover services: %w", err)
+	}
+
+	profile := &ble.Profile{}
+	for _, svc := range services {
+		if len(serviceUUIDs) > 0 && !ble.Contains(serviceUUIDs, svc.UUID) {
+			continue
+		}
+		chars, err := client.DiscoverCharacteristics(nil, svc)
+		if err != nil {
+			return nil, fmt.Errorf("failed to discover characteristics of service %s: %w", svc.UUID, err)
+		}
+		svc.Characteristics = chars
+
+		if descriptors {
+			for _, char := range chars {
+				descs, err := client.DiscoverDescriptors(nil, char)
+				if err != nil {
+					return nil, fmt.Errorf("failed to discover descriptors of characteristic %s: %w", char.UUID, err)
+				}
+				char.Descriptors = descs
+			}
+		}
+		profile.Services = append(profile.Services, svc)
+	}
+	return profile, nil
+}
+
+// discoveryAborted describes why discovery bounded by ctx stopped: the discovery timeout or caller cancellation
+func discoveryAborted(ctx context.Context, timeout time.Duration) error {
+	cause := context.Cause(ctx)
+	if timeout > 0 && errors.Is(cause, context.DeadlineExceeded) {
+		return fmt.Errorf("discovery did not complete within %v: %w", timeout, device.ErrTimeout)
+	}
+	return fmt.Errorf("discovery aborted: %w", cause)
+}
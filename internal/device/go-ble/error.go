@@ -6,9 +6,32 @@ import (
 	"fmt"
 	"strings"
 
+	"github.com/go-ble/ble"
 	"github.com/srg/blim/internal/device"
 )
 
+// coreBluetoothATTMessages maps CoreBluetooth CBATTError descriptions to ATT error codes.
+// On macOS, ATT errors surface as NSError descriptions rather than typed ble.ATTError values.
+var coreBluetoothATTMessages = []struct {
+	msg  string
+	code device.ATTErrorCode
+}{
+	{"the handle is invalid", device.ATTErrInvalidHandle},
+	{"reading is not permitted", device.ATTErrReadNotPermitted},
+	{"writing is not permitted", device.ATTErrWriteNotPermitted},
+	{"authentication is insufficient", device.ATTErrInsufficientAuthentication},
+	{"the request is not supported", device.ATTErrRequestNotSupported},
+	{"the offset is invalid", device.ATTErrInvalidOffset},
+	{"authorization is insufficient", device.ATTErrInsufficientAuthorization},
+	{"the prepare queue is full", device.ATTErrPrepareQueueFull},
+	{"the attribute could not be found", device.ATTErrAttributeNotFound},
+	{"the attribute is not long", device.ATTErrAttributeNotLong},
+	{"the encryption key size is insufficient", device.ATTErrInsufficientEncryptionKey},
+	{"the value's length is invalid", device.ATTErrInvalidAttributeValueLength},
+	{"encryption is insufficient", device.ATTErrInsufficientEncryption},
+	{"resources are insufficient", device.ATTErrInsufficientResources},
+}
+
 // NormalizeError maps known go-ble error strings to structured ConnectionError types.
 // It ensures consistent handling even if the upstream library changes messages slightly.
 // Returns wrapped errors to preserve original context.
@@ -25,8 +48,20 @@ func NormalizeError(err error) error {
 		return err // Don't wrap - cancellation is explicit user action
 	}
 
+	// ATT protocol errors reported by the remote GATT server
+	var attErr ble.ATTError
+	if errors.As(err, &attErr) {
+		return &device.ATTError{Code: device.ATTErrorCode(attErr), Err: err}
+	}
+
 	// Check platform-specific error messages
 	msg := err.Error()
+	for _, m := range coreBluetoothATTMessages {
+		if containsIgnoreCase(msg, m.msg) {
+			return &device.ATTError{Code: m.code, Err: err}
+		}
+	}
+
 	switch {
 	case msg == "central manager has invalid state: have=4 want=5: is Bluetooth turned on?":
 		return fmt.Errorf("%w: %v", device.ErrBluetoothOff, err)
@@ -0,0 +1,115 @@
+package goble
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/go-ble/ble"
+	"github.com/srg/blim/internal/device"
+)
+
+// DefaultFlushBarrierTimeout bounds the barrier read of FlushWrites when ctx has no deadline
+const DefaultFlushBarrierTimeout = 5 * time.Second
+
+// ----------------------------
+// In-flight Write Tracking
+// ----------------------------
+
+// writeTracker counts ATT writes still in flight, including writes whose caller already gave up on a timeout
+type writeTracker struct {
+	mu      sync.Mutex
+	pending int
+	idle    chan struct{} // Closed once pending drops to zero
+}
+
+// begin records a write handed to the client
+func (t *writeTracker) begin() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if t.pending == 0 {
+		t.idle = make(chan struct{})
+	}
+	t.pending++
+}
+
+// end records the completion of a write started with begin
+func (t *writeTracker) end() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.pending--
+	if t.pending == 0 {
+		close(t.idle)
+	}
+}
+
+// wait blocks until no write is in flight or ctx is done
+func (t *writeTracker) wait(ctx context.Context) error {
+	t.mu.Lock()
+	if t.pending == 0 {
+		t.mu.Unlock()
+		return nil
+	}
+	idle := t.idle
+	t.mu.Unlock()
+
+	select {
+	case <-idle:
+		return nil
+	case <-ctx.Done():
+		return context.Cause(ctx)
+	}
+}
+
+// FlushWrites blocks until every write handed to the BLE stack has completed, then issues a read of a readable
+// characteristic as a barrier: ATT requests are answered in order, so its response confirms that earlier
+// write-without-response commands were transmitted. The barrier is skipped if the device has no readable characteristic.
+func (c *BLEConnection) FlushWrites(ctx context.Context) error {
+	if err := c.writes.wait(ctx); err != nil {
+		return fmt.Errorf("failed to flush writes: %w", err)
+	}
+
+	c.connMutex.RLock()
+	if !c.isConnectedInternal() {
+		c.connMutex.RUnlock()
+		return fmt.Errorf("failed to flush writes: %w", device.ErrNotConnected)
+	}
+	barrier := c.barrierCharacteristic()
+	c.connMutex.RUnlock()
+
+	if barrier == nil {
+		return nil
+	}
+
+	if _, err := barrier.doRead(ctx, contextTimeout(ctx, DefaultFlushBarrierTimeout), func(client ble.Client) ([]byte, error) {
+		return client.ReadCharacteristic(barrier.BLEChar)
+	}); err != nil {
+		return fmt.Errorf("failed to flush writes: barrier read: %w", err)
+	}
+	return nil
+}
+
+// barrierCharacteristic returns the readable characteristic with the lowest UUID that doesn't require
+// authentication, or nil if there is none. Caller must hold connMutex.
+func (c *BLEConnection) barrierCharacteristic() *BLECharacteristic {
+	var candidates []*BLECharacteristic
+	for _, service := range c.services {
+		for _, char := range service.Characteristics {
+			if props := char.GetProperties(); props != nil && props.Read() != nil && !char.RequiresAuthentication() {
+				candidates = append(candidates, char)
+			}
+		}
+	}
+	if len(candidates) == 0 {
+		return nil
+	}
+
+	sort.Slice(candidates, func(i, j int) bool {
+		return candidates[i].UUID() < candidates[j].UUID()
+	})
+	return candidates[0]
+}
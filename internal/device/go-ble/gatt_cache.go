@@ -0,0 +1,287 @@
+package goble
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/go-ble/ble"
+	"github.com/srg/blim/internal/device"
+	"github.com/srg/blim/internal/groutine"
+)
+
+// ----------------------------
+// GATT Cache
+// ----------------------------
+
+// gattCacheVersion is bumped whenever the on-disk layout changes; files of other versions are ignored
+const gattCacheVersion = 1
+
+// gattCacheFile is the on-disk layout of a cached GATT database, keyed by peripheral identifier
+type gattCacheFile struct {
+	Version    int                 `json:"version"`
+	Identifier string              `json:"identifier"`
+	Services   []gattCachedService `json:"services"`
+}
+
+type gattCachedService struct {
+	UUID            string                     `json:"uuid"`
+	Handle          uint16                     `json:"handle"`
+	EndHandle       uint16                     `json:"end_handle"`
+	Characteristics []gattCachedCharacteristic `json:"characteristics"`
+}
+
+type gattCachedCharacteristic struct {
+	UUID        string                 `json:"uuid"`
+	Property    ble.Property           `json:"property"`
+	Handle      uint16                 `json:"handle"`
+	ValueHandle uint16                 `json:"value_handle"`
+	EndHandle   uint16                 `json:"end_handle"`
+	Descriptors []gattCachedDescriptor `json:"descriptors"`
+}
+
+type gattCachedDescriptor struct {
+	UUID   string `json:"uuid"`
+	Handle uint16 `json:"handle"`
+}
+
+// loadOrDiscoverProfile returns the profile of the connected device: from the GATT cache when opts.GATTCacheDir
+// holds a layout that still validates, otherwise by discovery. A full discovery (all services with descriptors)
+// refreshes the cache; scoped discoveries are not cached since they describe only part of the database.
+func (c *BLEConnection) loadOrDiscoverProfile(ctx context.Context, client ble.Client, identifier string, opts *device.ConnectOptions) (*ble.Profile, error) {
+	cacheDir := opts.GATTCacheDir
+	if cacheDir != "" {
+		profile, err := loadGATTCache(cacheDir, identifier)
+		if err == nil {
+			err = validateGATTCache(ctx, client, profile)
+			if err == nil {
+				c.logger.WithField("identifier", identifier).Debug("Using cached GATT database, skipping discovery")
+				return scopeCachedProfile(profile, opts), nil
+			}
+			if ctx.Err() != nil {
+				return nil, discoveryAborted(ctx, opts.DiscoveryTimeout)
+			}
+			c.logger.WithError(err).WithField("identifier", identifier).Info("Cached GATT database is stale, rediscovering")
+			if err := invalidateGATTCache(cacheDir, identifier); err != nil {
+				c.logger.WithError(err).Warn("Failed to remove stale GATT cache")
+			}
+		} else if !errors.Is(err, os.ErrNotExist) {
+			c.logger.WithError(err).WithField("identifier", identifier).Warn("Ignoring unreadable GATT cache")
+		}
+	}
+
+	c.logger.WithField("identifier", identifier).Debug("Discovering services and characteristics...")
+	profile, err := discoverProfile(ctx, client, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	if cacheDir != "" && !opts.DiscoverOnlyListed && !opts.SkipDescriptors {
+		if serviceChangedCCCD(profile) == nil {
+			c.logger.WithField("identifier", identifier).Debug("No Service Changed characteristic to validate a GATT cache against, not caching")
+		} else if err := saveGATTCache(cacheDir, identifier, profile); err != nil {
+			c.logger.WithError(err).WithField("identifier", identifier).Warn("Failed to write GATT cache")
+		}
+	}
+	return profile, nil
+}
+
+// gattCachePath returns the cache file of the peripheral identifier in dir
+func gattCachePath(dir, identifier string) string {
+	name := strings.NewReplacer(":", "", "-", "").Replace(strings.ToLower(identifier))
+	return filepath.Join(dir, name+".json")
+}
+
+// loadGATTCache reads the cached profile of identifier. Returns an error wrapping os.ErrNotExist when nothing is cached.
+func loadGATTCache(dir, identifier string) (*ble.Profile, error) {
+	data, err := os.ReadFile(gattCachePath(dir, identifier))
+	if err != nil {
+		return nil, err
+	}
+
+	var file gattCacheFile
+	if err := json.Unmarshal(data, &file); err != nil {
+		return nil, fmt.Errorf("corrupt GATT cache: %w", err)
+	}
+	if file.Version != gattCacheVersion {
+		return nil, fmt.Errorf("unsupported GATT cache version %d", file.Version)
+	}
+
+	profile := &ble.Profile{}
+	for _, cs := range file.Services {
+		svcUUID, err := ble.Parse(cs.UUID)
+		if err != nil {
+			return nil, fmt.Errorf("corrupt GATT cache: service %q: %w", cs.UUID, err)
+		}
+		svc := &ble.Service{UUID: svcUUID, Handle: cs.Handle, EndHandle: cs.EndHandle}
+
+		for _, cc := range cs.Characteristics {
+			charUUID, err := ble.Parse(cc.UUID)
+			if err != nil {
+				return nil, fmt.Errorf("corrupt GATT cache: characteristic %q: %w", cc.UUID, err)
+			}
+			char := &ble.Characteristic{
+				UUID:        charUUID,
+				Property:    cc.Property,
+				Handle:      cc.Handle,
+				ValueHandle: cc.ValueHandle,
+				EndHandle:   cc.EndHandle,
+			}
+
+			for _, cd := range cc.Descriptors {
+				descUUID, err := ble.Parse(cd.UUID)
+				if err != nil {
+					return nil, fmt.Errorf("corrupt GATT cache: descriptor %q: %w", cd.UUID, err)
+				}
+				desc := &ble.Descriptor{UUID: descUUID, Handle: cd.Handle}
+				if descUUID.Equal(ble.ClientCharacteristicConfigUUID) {
+					char.CCCD = desc
+				}
+				char.Descriptors = append(char.Descriptors, desc)
+			}
+			svc.Characteristics = append(svc.Characteristics, char)
+		}
+		profile.Services = append(profile.Services, svc)
+	}
+	return profile, nil
+}
+
+// saveGATTCache writes the attribute layout of profile (no values) as the cache of identifier.
+// The file is replaced atomically, so a concurrent reader never sees a partial cache.
+func saveGATTCache(dir, identifier string, profile *ble.Profile) error {
+	file := gattCacheFile{Version: gattCacheVersion, Identifier: identifier}
+	for _, svc := range profile.Services {
+		cs := gattCachedService{UUID: svc.UUID.String(), Handle: svc.Handle, EndHandle: svc.EndHandle}
+		for _, char := range svc.Characteristics {
+			cc := gattCachedCharacteristic{
+				UUID:        char.UUID.String(),
+				Property:    char.Property,
+				Handle:      char.Handle,
+				ValueHandle: char.ValueHandle,
+				EndHandle:   char.EndHandle,
+			}
+			for _, desc := range char.Descriptors {
+				cc.Descriptors = append(cc.Descriptors, gattCachedDescriptor{UUID: desc.UUID.String(), Handle: desc.Handle})
+			}
+			cs.Characteristics = append(cs.Characteristics, cc)
+		}
+		file.Services = append(file.Services, cs)
+	}
+
+	data, err := json.MarshalIndent(file, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(dir, ".gatt-*.json")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name()) // No-op after a successful rename
+	if _, err := tmp.Write(data); err != nil {
+		_ = tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), gattCachePath(dir, identifier))
+}
+
+// invalidateGATTCache removes the cache of identifier; a missing cache is not an error
+func invalidateGATTCache(dir, identifier string) error {
+	err := os.Remove(gattCachePath(dir, identifier))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	return err
+}
+
+// scopeCachedProfile applies the discovery scope of opts to a full cached profile:
+// DiscoverOnlyListed keeps only the listed services and SkipDescriptors drops all descriptors.
+func scopeCachedProfile(profile *ble.Profile, opts *device.ConnectOptions) *ble.Profile {
+	if !opts.DiscoverOnlyListed && !opts.SkipDescriptors {
+		return profile
+	}
+
+	listed := make(map[string]bool, len(opts.Services))
+	for _, svc := range opts.Services {
+		listed[device.NormalizeUUID(svc.Service)] = true
+	}
+
+	scoped := &ble.Profile{}
+	for _, svc := range profile.Services {
+		if opts.DiscoverOnlyListed && !listed[device.NormalizeUUID(svc.UUID.String())] {
+			continue
+		}
+		if opts.SkipDescriptors {
+			for _, char := range svc.Characteristics {
+				char.Descriptors = nil
+				char.CCCD = nil
+			}
+		}
+		scoped.Services = append(scoped.Services, svc)
+	}
+	return scoped
+}
+
+// serviceChangedCCCD returns the Client Characteristic Configuration descriptor of the Service Changed
+// characteristic (0x2A05 in 0x1801), nil if the profile has none
+func serviceChangedCCCD(profile *ble.Profile) *ble.Descriptor {
+	for _, svc := range profile.Services {
+		if device.NormalizeUUID(svc.UUID.String()) != genericAttributeServiceUUID {
+			continue
+		}
+		for _, char := range svc.Characteristics {
+			if device.NormalizeUUID(char.UUID.String()) != serviceChangedCharUUID {
+				continue
+			}
+			for _, desc := range char.Descriptors {
+				if desc.UUID.Equal(ble.ClientCharacteristicConfigUUID) {
+					return desc
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// validateGATTCache checks a cached profile against the live peripheral by reading the Client Characteristic
+// Configuration of the Service Changed characteristic at its cached handle: a peripheral whose database moved
+// rejects the read or returns something other than a CCCD value. A profile without Service Changed cannot be
+// validated and is rejected.
+func validateGATTCache(ctx context.Context, client ble.Client, profile *ble.Profile) error {
+	cccd := serviceChangedCCCD(profile)
+	if cccd == nil {
+		return fmt.Errorf("no service changed configuration to validate against")
+	}
+
+	type result struct {
+		value []byte
+		err   error
+	}
+	resultCh := make(chan result, 1)
+	groutine.Go(context.Background(), "ble-connection-validate-gatt-cache", func(context.Context) {
+		value, err := client.ReadDescriptor(cccd)
+		resultCh <- result{value, err}
+	})
+
+	select {
+	case r := <-resultCh:
+		if r.err != nil {
+			return fmt.Errorf("service changed configuration unreadable at handle 0x%04x: %w", cccd.Handle, NormalizeError(r.err))
+		}
+		if len(r.value) != 2 {
+			return fmt.Errorf("unexpected service changed configuration at handle 0x%04x: %x", cccd.Handle, r.value)
+		}
+		return nil
+	case <-ctx.Done():
+		return context.Cause(ctx)
+	}
+}
@@ -0,0 +1,121 @@
+package goble
+
+import (
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/srg/blim/internal/device"
+)
+
+// ----------------------------
+// Connection Idle Timeout
+// ----------------------------
+
+// idleMonitor fires onIdle once no activity was recorded for the configured timeout.
+// touch() is lock-free so it can be called on every read, write and notification;
+// the timer re-arms itself for the remaining time when activity happened since it was scheduled.
+type idleMonitor struct {
+	lastActivity atomic.Int64 // UnixNano of the last activity
+
+	mu      sync.Mutex
+	timeout time.Duration
+	timer   *time.Timer
+	onIdle  func()
+}
+
+// touch records activity
+func (m *idleMonitor) touch() {
+	m.lastActivity.Store(time.Now().UnixNano())
+}
+
+// start (re)arms the monitor; a timeout of 0 disables it
+func (m *idleMonitor) start(timeout time.Duration, onIdle func()) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.timer != nil {
+		m.timer.Stop()
+		m.timer = nil
+	}
+	m.timeout = timeout
+	m.onIdle = onIdle
+	if timeout <= 0 {
+		return
+	}
+
+	m.touch()
+	m.timer = time.AfterFunc(timeout, m.check)
+}
+
+// stop disarms the monitor
+func (m *idleMonitor) stop() {
+	m.start(0, nil)
+}
+
+// check fires onIdle if the connection stayed idle for the timeout, otherwise re-arms for the remaining time
+func (m *idleMonitor) check() {
+	m.mu.Lock()
+	if m.timer == nil {
+		m.mu.Unlock()
+		return // Stopped concurrently
+	}
+	idle := time.Since(time.Unix(0, m.lastActivity.Load()))
+	if remaining := m.timeout - idle; remaining > 0 {
+		m.timer.Reset(remaining)
+		m.mu.Unlock()
+		return
+	}
+	m.timer = nil
+	onIdle := m.onIdle
+	m.mu.Unlock()
+
+	onIdle()
+}
+
+// SetIdleTimeout changes the idle timeout of the live connection (0 disables it).
+// See device.ConnectOptions.IdleTimeout.
+func (c *BLEConnection) SetIdleTimeout(timeout time.Duration) error {
+	c.connMutex.RLock()
+	defer c.connMutex.RUnlock()
+
+	if !c.isConnectedInternal() {
+		return device.ErrNotConnected
+	}
+	c.idle.start(timeout, c.disconnectIdle)
+	return nil
+}
+
+// OnDisconnected registers a handler that fires when the connection closes itself,
+// with the reason (device.DisconnectReasonIdle). Explicit Disconnect() calls are not reported. Thread-safe.
+func (c *BLEConnection) OnDisconnected(handler func(reason string)) {
+	if handler == nil {
+		return
+	}
+	c.disconnectedMutex.Lock()
+	defer c.disconnectedMutex.Unlock()
+	c.disconnectedHandlers = append(c.disconnectedHandlers, handler)
+}
+
+// disconnectIdle closes an idle connection and notifies the disconnect handlers
+func (c *BLEConnection) disconnectIdle() {
+	if c.logger != nil {
+		c.logger.WithField("idle_timeout", c.idle.timeout).Info("Connection idle, disconnecting")
+	}
+
+	if err := c.Disconnect(); err != nil && c.logger != nil {
+		c.logger.WithFields(logrus.Fields{
+			"reason": device.DisconnectReasonIdle,
+			"error":  err,
+		}).Warn("Idle disconnect completed with errors")
+	}
+
+	c.disconnectedMutex.Lock()
+	handlers := append([]func(string){}, c.disconnectedHandlers...)
+	c.disconnectedMutex.Unlock()
+
+	for _, handler := range handlers {
+		handler(device.DisconnectReasonIdle)
+	}
+}
@@ -0,0 +1,131 @@
+package goble
+
+import (
+	"fmt"
+	"runtime/debug"
+
+	"github.com/srg/blim/internal/device"
+)
+
+// OnNotification registers a handler receiving a copy of every notification payload of the characteristic.
+// Unlike Subscribe(), whose subscriptions consume the characteristic's queued values, handlers observe
+// notifications without competing with existing subscriptions. Any number of handlers can be registered;
+// the returned function unregisters this one and leaves notifications enabled.
+// Enables notifications on the characteristic if no subscription has enabled them yet.
+func (c *BLEConnection) OnNotification(service, uuid string, handler func(data []byte)) (func(), error) {
+	if handler == nil {
+		return nil, fmt.Errorf("no notification handler specified")
+	}
+
+	bleChar, err := c.bleCharacteristic(service, uuid)
+	if err != nil {
+		return nil, err
+	}
+
+	// BLEValue data is pooled and released after dispatch, so the handler gets its own copy
+	return c.addNotificationHandler(service, bleChar, func(_ *BLECharacteristic, v *BLEValue) {
+		handler(append([]byte(nil), v.Data...))
+	})
+}
+
+// SetNotifyHandler sets the handler receiving every notification of the characteristic as a Record with the
+// characteristic's value, receive timestamp and sequence number. It is registered like an OnNotification
+// handler, but the characteristic holds at most one: a later call replaces the handler and a nil handler
+// removes it, leaving notifications enabled. Handlers run on the notification goroutine, so they should
+// return quickly; a panicking handler is recovered and logged.
+// Enables notifications on the characteristic if no subscription has enabled them yet.
+func (c *BLEConnection) SetNotifyHandler(service, uuid string, handler func(device.Record)) error {
+	bleChar, err := c.bleCharacteristic(service, uuid)
+	if err != nil {
+		return err
+	}
+
+	var remove func()
+	if handler != nil {
+		remove, err = c.addNotificationHandler(service, bleChar, func(char *BLECharacteristic, v *BLEValue) {
+			// BLEValue data is pooled and released after dispatch, so the record gets its own copy
+			record := device.Record{TsUs: v.TsUs, Seq: v.Seq, Flags: v.Flags}
+			record.SetValue(char.uuid, append([]byte(nil), v.Data...))
+			handler(record)
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	bleChar.mu.Lock()
+	previous := bleChar.removeNotifyHandler
+	bleChar.removeNotifyHandler = remove
+	bleChar.mu.Unlock()
+
+	if previous != nil {
+		previous()
+	}
+	return nil
+}
+
+// addNotificationHandler registers fn for every notification of the characteristic and enables notifications
+// if no subscription has enabled them yet. It backs OnNotification and SetNotifyHandler; the returned function
+// unregisters fn. The registration is rolled back if notifications can't be enabled.
+func (c *BLEConnection) addNotificationHandler(service string, bleChar *BLECharacteristic, fn func(*BLECharacteristic, *BLEValue)) (func(), error) {
+	remove := bleChar.Subscribe(func(v *BLEValue) { fn(bleChar, v) })
+
+	if bleChar.cccd.Load() != cccdDisabled {
+		return remove, nil
+	}
+	if err := c.BLESubscribe(&device.SubscribeOptions{Service: service, Characteristics: []string{bleChar.uuid}}); err != nil {
+		remove()
+		return nil, fmt.Errorf("failed to enable notifications for %s: %w", bleChar.uuid, err)
+	}
+	return remove, nil
+}
+
+// bleCharacteristic looks up the characteristic, which must be backed by go-ble to carry notification handlers
+func (c *BLEConnection) bleCharacteristic(service, uuid string) (*BLECharacteristic, error) {
+	c.connMutex.RLock()
+	char, err := c.GetCharacteristic(service, uuid)
+	c.connMutex.RUnlock()
+	if err != nil {
+		return nil, err
+	}
+
+	bleChar, ok := char.(*BLECharacteristic)
+	if !ok {
+		return nil, fmt.Errorf("characteristic %s is not a BLE characteristic (got %T): %w", uuid, char, device.ErrUnsupported)
+	}
+	return bleChar, nil
+}
+
+// OnRawNotification registers a handler receiving every incoming notification of any characteristic, with
+// the characteristic UUID, a copy of the raw payload and the receive timestamp in microseconds. Handlers run
+// on the notification goroutine before subscriptions batch or aggregate the value, so they should return
+// quickly; a panicking handler is recovered and logged. Thread-safe.
+func (c *BLEConnection) OnRawNotification(handler func(uuid string, data []byte, tsUs int64)) {
+	if handler == nil {
+		return
+	}
+	c.rawNotificationMutex.Lock()
+	defer c.rawNotificationMutex.Unlock()
+	c.rawNotificationHandlers = append(c.rawNotificationHandlers, handler)
+}
+
+// fireRawNotification invokes all registered raw notification handlers with val
+func (c *BLEConnection) fireRawNotification(uuid string, val *BLEValue) {
+	c.rawNotificationMutex.Lock()
+	handlers := c.rawNotificationHandlers
+	c.rawNotificationMutex.Unlock()
+
+	for _, handler := range handlers {
+		c.callRawNotificationHandler(handler, uuid, append([]byte(nil), val.Data...), val.TsUs)
+	}
+}
+
+// callRawNotificationHandler runs handler, keeping a panic from escaping onto the notification goroutine
+func (c *BLEConnection) callRawNotificationHandler(handler func(uuid string, data []byte, tsUs int64), uuid string, data []byte, tsUs int64) {
+	defer func() {
+		if r := recover(); r != nil && c.logger != nil {
+			c.logger.Errorf("Raw notification handler panic (recovered): %v\nStack:\n%s", r, string(debug.Stack()))
+		}
+	}()
+	handler(uuid, data, tsUs)
+}
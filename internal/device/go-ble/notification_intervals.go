@@ -0,0 +1,100 @@
+package goble
+
+import (
+	"math"
+	"sync"
+	"time"
+
+	"github.com/srg/blim/internal/device"
+)
+
+// ----------------------------
+// Notification Inter-arrival Statistics
+// ----------------------------
+
+// intervalStats accumulates the intervals between consecutive notifications of one characteristic
+// (Welford's online algorithm, so memory does not grow with the number of notifications)
+type intervalStats struct {
+	lastTsUs int64
+	hasLast  bool // False until the first notification and after restart, so no interval spans the gap
+	count    uint64
+	minUs    int64
+	maxUs    int64
+	mean     float64 // Microseconds
+	m2       float64 // Sum of squared deviations from the mean
+}
+
+// notificationIntervals tracks intervalStats per characteristic UUID
+type notificationIntervals struct {
+	mu    sync.Mutex
+	stats map[string]*intervalStats
+}
+
+// record adds a notification of uuid received at tsUs (Unix microseconds)
+func (n *notificationIntervals) record(uuid string, tsUs int64) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	if n.stats == nil {
+		n.stats = make(map[string]*intervalStats)
+	}
+	s, ok := n.stats[uuid]
+	if !ok {
+		s = &intervalStats{}
+		n.stats[uuid] = s
+	}
+	if !s.hasLast {
+		s.lastTsUs, s.hasLast = tsUs, true
+		return
+	}
+
+	interval := tsUs - s.lastTsUs
+	s.lastTsUs = tsUs
+	if interval < 0 {
+		return // Clock stepped backwards; the next interval is measured from here
+	}
+
+	s.count++
+	if s.count == 1 || interval < s.minUs {
+		s.minUs = interval
+	}
+	if interval > s.maxUs {
+		s.maxUs = interval
+	}
+	delta := float64(interval) - s.mean
+	s.mean += delta / float64(s.count)
+	s.m2 += delta * (float64(interval) - s.mean)
+}
+
+// restart makes the next notification of uuid start a new measurement instead of closing an interval
+// with the last one. Used when notifications stop (unsubscribe, disconnect), so the gap until they resume
+// is not reported as an interval. Accumulated statistics are kept.
+func (n *notificationIntervals) restart(uuid string) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	if s, ok := n.stats[uuid]; ok {
+		s.hasLast = false
+	}
+}
+
+// snapshot returns the statistics of every characteristic with at least one measured interval
+func (n *notificationIntervals) snapshot() map[string]device.NotificationIntervals {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	result := make(map[string]device.NotificationIntervals, len(n.stats))
+	for uuid, s := range n.stats {
+		if s.count == 0 {
+			continue
+		}
+		result[uuid] = device.NotificationIntervals{
+			Count:  s.count,
+			Min:    time.Duration(s.minUs) * time.Microsecond,
+			Max:    time.Duration(s.maxUs) * time.Microsecond,
+			Mean:   time.Duration(s.mean * float64(time.Microsecond)),
+			StdDev: time.Duration(math.Sqrt(s.m2/float64(s.count)) * float64(time.Microsecond)),
+		}
+	}
+	return result
+}
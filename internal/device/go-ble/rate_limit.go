@@ -0,0 +1,162 @@
+package goble
+
+import (
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/srg/blim/internal/device"
+)
+
+// ----------------------------
+// Connection-wide Notification Rate Limit
+// ----------------------------
+
+// notificationRateLimiter is a token bucket shared by all subscriptions of a connection.
+// The bucket holds up to one second worth of tokens (minimum 1) and refills continuously.
+type notificationRateLimiter struct {
+	mu     sync.Mutex
+	rate   float64 // tokens per second
+	burst  float64
+	tokens float64
+	last   time.Time
+}
+
+func newNotificationRateLimiter(rate float64) *notificationRateLimiter {
+	burst := rate
+	if burst < 1 {
+		burst = 1
+	}
+	return &notificationRateLimiter{
+		rate:   rate,
+		burst:  burst,
+		tokens: burst,
+		last:   time.Now(),
+	}
+}
+
+// allow consumes a token if one is available
+func (l *notificationRateLimiter) allow() bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := time.Now()
+	l.tokens += now.Sub(l.last).Seconds() * l.rate
+	if l.tokens > l.burst {
+		l.tokens = l.burst
+	}
+	l.last = now
+
+	if l.tokens < 1 {
+		return false
+	}
+	l.tokens--
+	return true
+}
+
+// subscriptionCounters holds connection-wide delivery counters (see device.SubscriptionStats)
+type subscriptionCounters struct {
+	delivered        atomic.Uint64
+	rateLimitDropped atomic.Uint64
+	coalesced        atomic.Uint64
+	intervals        notificationIntervals
+}
+
+// SubscriptionStats returns connection-wide subscription delivery counters and per-characteristic
+// notification intervals
+func (c *BLEConnection) SubscriptionStats() device.SubscriptionStats {
+	return device.SubscriptionStats{
+		Delivered:        c.stats.delivered.Load(),
+		RateLimitDropped: c.stats.rateLimitDropped.Load(),
+		Coalesced:        c.stats.coalesced.Load(),
+		Intervals:        c.stats.intervals.snapshot(),
+	}
+}
+
+// dispatch delivers a record to the subscription callback, enforcing the connection-wide rate limit.
+// Records over the limit are dropped or coalesced into the subscription's pending record.
+func (c *BLEConnection) dispatch(sub *Subscription, record *device.Record) {
+	if c.rateLimiter == nil || c.rateLimiter.allow() {
+		if sub.pending != nil {
+			record = mergeRecords(sub.pending, record)
+			sub.pending = nil
+		}
+		c.stats.delivered.Add(1)
+		c.deliver(sub, record)
+		return
+	}
+
+	if c.overflowPolicy != device.OverflowCoalesce {
+		c.stats.rateLimitDropped.Add(1)
+		return
+	}
+
+	// Pooled value buffers are released after dispatch returns, so the pending record keeps copies
+	if sub.pending == nil {
+		sub.pending = copyRecord(record)
+	} else {
+		sub.pending = mergeRecords(sub.pending, copyRecord(record))
+		sub.pending.Flags |= FlagDropped // Earlier values of the same characteristic were overwritten
+	}
+	c.stats.coalesced.Add(1)
+}
+
+// flushPending delivers a coalesced record once the rate limit allows it
+func (c *BLEConnection) flushPending(sub *Subscription) {
+	if sub.pending == nil || !c.rateLimiter.allow() {
+		return
+	}
+	record := sub.pending
+	sub.pending = nil
+	c.stats.delivered.Add(1)
+	c.deliver(sub, record)
+}
+
+// copyRecord returns a deep copy of the record that does not reference pooled buffers
+func copyRecord(r *device.Record) *device.Record {
+	cp := &device.Record{TsUs: r.TsUs, Seq: r.Seq, Flags: r.Flags, End: r.End, Initial: r.Initial, Order: append([]string(nil), r.Order...)}
+	if r.Values != nil {
+		cp.Values = make(map[string][]byte, len(r.Values))
+		for k, v := range r.Values {
+			cp.Values[k] = append([]byte(nil), v...)
+		}
+	}
+	if r.BatchValues != nil {
+		cp.BatchValues = make(map[string][][]byte, len(r.BatchValues))
+		for k, vs := range r.BatchValues {
+			for _, v := range vs {
+				cp.BatchValues[k] = append(cp.BatchValues[k], append([]byte(nil), v...))
+			}
+		}
+	}
+	return cp
+}
+
+// mergeRecords merges newer into older: latest value per characteristic wins, batched values are appended
+func mergeRecords(older, newer *device.Record) *device.Record {
+	merged := copyRecord(older)
+	merged.TsUs = newer.TsUs
+	merged.Seq = newer.Seq
+	merged.Flags |= newer.Flags
+	if newer.Values != nil {
+		if merged.Values == nil {
+			merged.Values = make(map[string][]byte, len(newer.Values))
+		}
+		for _, k := range newer.Keys() {
+			if v, ok := newer.Values[k]; ok {
+				merged.SetValue(k, v)
+			}
+		}
+	}
+	if newer.BatchValues != nil {
+		if merged.BatchValues == nil {
+			merged.BatchValues = make(map[string][][]byte, len(newer.BatchValues))
+		}
+		for _, k := range newer.Keys() {
+			if vs, ok := newer.BatchValues[k]; ok {
+				merged.AppendBatchValues(k, vs...)
+			}
+		}
+	}
+	return merged
+}
@@ -0,0 +1,94 @@
+package goble
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/srg/blim/internal/device"
+)
+
+// ----------------------------
+// Characteristic Read Cache
+// ----------------------------
+
+// readCache holds the last value read from or notified by a characteristic.
+// Read() serves it while younger than ttl; ttl == 0 (default) disables caching for Read().
+type readCache struct {
+	mu    sync.Mutex
+	ttl   time.Duration
+	value []byte
+	at    time.Time
+	valid bool
+}
+
+// get returns a copy of the cached value if it is younger than ttl
+func (rc *readCache) get(ttl time.Duration) ([]byte, bool) {
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+	if !rc.valid || ttl <= 0 || time.Since(rc.at) >= ttl {
+		return nil, false
+	}
+	return append([]byte(nil), rc.value...), true
+}
+
+// store records a fresh value
+func (rc *readCache) store(value []byte) {
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+	rc.value = append(rc.value[:0], value...)
+	rc.at = time.Now()
+	rc.valid = true
+}
+
+// invalidate drops the cached value
+func (rc *readCache) invalidate() {
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+	rc.valid = false
+}
+
+func (rc *readCache) defaultTTL() time.Duration {
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+	return rc.ttl
+}
+
+func (rc *readCache) setTTL(ttl time.Duration) {
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+	rc.ttl = ttl
+}
+
+// ReadCached returns the last value read or notified if it is younger than ttl,
+// otherwise reads the characteristic from the device (refreshing the cache).
+func (c *BLECharacteristic) ReadCached(ttl, timeout time.Duration) ([]byte, error) {
+	if value, ok := c.cache.get(ttl); ok {
+		return value, nil
+	}
+	return c.readFromDevice(context.Background(), timeout)
+}
+
+// SetReadCacheTTL enables the read cache for a characteristic: within ttl of the last read or
+// notification, Read() returns the cached value instead of issuing a new ATT read.
+// A ttl of 0 disables caching (default). Returns a NotFoundError if the characteristic is not found.
+func (c *BLEConnection) SetReadCacheTTL(service, uuid string, ttl time.Duration) error {
+	if ttl < 0 {
+		return fmt.Errorf("read cache TTL must not be negative: %v", ttl)
+	}
+
+	c.connMutex.RLock()
+	char, err := c.GetCharacteristic(service, uuid)
+	c.connMutex.RUnlock()
+	if err != nil {
+		return err
+	}
+
+	bleChar, ok := char.(*BLECharacteristic)
+	if !ok {
+		return fmt.Errorf("characteristic %s is not a BLE characteristic (got %T): %w", uuid, char, device.ErrUnsupported)
+	}
+	bleChar.cache.setTTL(ttl)
+	return nil
+}
@@ -0,0 +1,71 @@
+package goble
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/go-ble/ble"
+	"github.com/srg/blim/internal/device"
+)
+
+// DefaultATTMTU is the minimum ATT_MTU every LE link supports. A Read Response carries at most ATT_MTU-1 bytes.
+const DefaultATTMTU = 23
+
+// fillsReadMTU reports whether a read response may have been cut off at the ATT MTU.
+// The negotiated MTU is not exposed on all platforms, so any response of at least DefaultATTMTU-1 bytes
+// is treated as possibly truncated; a value ending exactly at the boundary costs one extra Read Blob round trip.
+func fillsReadMTU(data []byte) bool {
+	return len(data) >= DefaultATTMTU-1
+}
+
+// ReadLong reads the full value of the characteristic with a Read request followed by sequential
+// Read Blob requests until the value is complete, for values longer than one ATT MTU.
+func (c *BLECharacteristic) ReadLong(timeout time.Duration) ([]byte, error) {
+	if c.connection == nil {
+		return nil, fmt.Errorf("no connection available for reading characteristic %s", c.uuid)
+	}
+	if c.BLEChar == nil {
+		return nil, fmt.Errorf("characteristic %s not initialized", c.uuid)
+	}
+
+	readProps := c.properties.Read()
+	if readProps == nil || readProps.Value() == 0 {
+		return nil, fmt.Errorf("characteristic %s does not support read operations: %w", c.uuid, device.ErrUnsupported)
+	}
+
+	data, err := c.readLongValue(context.Background(), timeout)
+	if err != nil && c.connection.autoPairOnAuthError(err, c) {
+		data, err = c.readLongValue(context.Background(), timeout)
+	}
+	if err == nil {
+		c.cache.store(data)
+	}
+	return data, err
+}
+
+// readLongValue performs the Read / Read Blob sequence without checking the read property
+func (c *BLECharacteristic) readLongValue(ctx context.Context, timeout time.Duration) ([]byte, error) {
+	if timeout <= 0 {
+		return nil, fmt.Errorf("long read characteristic %s: %w", c.uuid, device.ErrTimeout)
+	}
+	return c.doRead(ctx, timeout, func(client ble.Client) ([]byte, error) {
+		return client.ReadLongCharacteristic(c.BLEChar)
+	})
+}
+
+// ReadLong reads the full value of a characteristic that may be longer than one ATT MTU (see BLECharacteristic.ReadLong)
+func (c *BLEConnection) ReadLong(service, uuid string, timeout time.Duration) ([]byte, error) {
+	c.connMutex.RLock()
+	char, err := c.GetCharacteristic(service, uuid)
+	c.connMutex.RUnlock()
+	if err != nil {
+		return nil, err
+	}
+
+	bleChar, ok := char.(*BLECharacteristic)
+	if !ok {
+		return nil, fmt.Errorf("characteristic %s is not a BLE characteristic (got %T): %w", uuid, char, device.ErrUnsupported)
+	}
+	return bleChar.ReadLong(timeout)
+}
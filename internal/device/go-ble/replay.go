@@ -0,0 +1,71 @@
+package goble
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/srg/blim/internal/device"
+)
+
+// ReplayNotifications feeds recorded notifications back through ProcessCharacteristicNotification,
+// preserving the original inter-notification timing. Characteristics are resolved by service and UUID,
+// falling back to a service-independent lookup. Blocks until the log is exhausted or ctx is done.
+// Returns the number of replayed notifications.
+func (c *BLEConnection) ReplayNotifications(ctx context.Context, log *device.NotificationLogReader) (int, error) {
+	var (
+		count   int
+		firstTs int64
+		start   time.Time
+	)
+
+	for {
+		entry, err := log.Next()
+		if errors.Is(err, io.EOF) {
+			return count, nil
+		}
+		if err != nil {
+			return count, fmt.Errorf("failed to replay notifications: %w", err)
+		}
+
+		char, err := c.resolveReplayCharacteristic(entry)
+		if err != nil {
+			return count, fmt.Errorf("failed to replay notification #%d: %w", count+1, err)
+		}
+
+		if count == 0 {
+			firstTs = entry.TsUs
+			start = time.Now()
+		} else if delay := time.Until(start.Add(time.Duration(entry.TsUs-firstTs) * time.Microsecond)); delay > 0 {
+			select {
+			case <-time.After(delay):
+			case <-ctx.Done():
+				return count, context.Cause(ctx)
+			}
+		}
+
+		c.ProcessCharacteristicNotification(char, entry.Data)
+		count++
+	}
+}
+
+// resolveReplayCharacteristic finds the live characteristic a recorded entry belongs to
+func (c *BLEConnection) resolveReplayCharacteristic(entry *device.NotificationLogEntry) (*BLECharacteristic, error) {
+	c.connMutex.RLock()
+	char, err := c.GetCharacteristic(entry.Service, entry.Characteristic)
+	c.connMutex.RUnlock()
+	if err != nil {
+		char, err = c.FindCharacteristicByUUID(entry.Characteristic)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	bleChar, ok := char.(*BLECharacteristic)
+	if !ok {
+		return nil, fmt.Errorf("characteristic %s is not a BLE characteristic (got %T)", entry.Characteristic, char)
+	}
+	return bleChar, nil
+}
@@ -0,0 +1,126 @@
+package goble
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/srg/blim/internal/device"
+	"github.com/srg/blim/internal/groutine"
+)
+
+// DefaultRSSIReadTimeout bounds a single RSSI read of a live connection
+const DefaultRSSIReadTimeout = 2 * time.Second
+
+// ----------------------------
+// Connection RSSI
+// ----------------------------
+
+// rssiPoller periodically invokes poll until stopped. stop() does not wait for an in-flight poll,
+// so it is safe to call while holding connMutex.
+type rssiPoller struct {
+	mu   sync.Mutex
+	done chan struct{} // Closed to stop the running poll loop (nil when stopped)
+}
+
+// start (re)starts polling every interval; an interval of 0 disables it
+func (p *rssiPoller) start(interval time.Duration, poll func()) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.done != nil {
+		close(p.done)
+		p.done = nil
+	}
+	if interval <= 0 {
+		return
+	}
+
+	done := make(chan struct{})
+	p.done = done
+	groutine.Go(context.Background(), "ble-connection-rssi-poll", func(ctx context.Context) {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-done:
+				return
+			case <-ticker.C:
+				poll()
+			}
+		}
+	})
+}
+
+// stop disables polling
+func (p *rssiPoller) stop() {
+	p.start(0, nil)
+}
+
+// ReadRSSI reads the signal strength (dBm) of the live connection.
+// On macOS this maps to CoreBluetooth's peripheral RSSI read.
+func (c *BLEConnection) ReadRSSI() (int, error) {
+	c.connMutex.RLock()
+	if !c.isConnectedInternal() {
+		c.connMutex.RUnlock()
+		return 0, fmt.Errorf("read RSSI: %w", device.ErrNotConnected)
+	}
+	client := c.client
+	c.connMutex.RUnlock()
+
+	resultCh := make(chan int, 1)
+	groutine.Go(context.Background(), "ble-connection-read-rssi", func(ctx context.Context) {
+		resultCh <- client.ReadRSSI()
+	})
+
+	select {
+	case rssi := <-resultCh:
+		return rssi, nil
+	case <-time.After(DefaultRSSIReadTimeout):
+		return 0, fmt.Errorf("read RSSI after %v: %w", DefaultRSSIReadTimeout, device.ErrTimeout)
+	}
+}
+
+// SetRSSIPollInterval reads the RSSI every interval and reports it to the OnRSSI handlers (0 disables polling).
+// Polling stops when the connection is closed.
+func (c *BLEConnection) SetRSSIPollInterval(interval time.Duration) error {
+	c.connMutex.RLock()
+	defer c.connMutex.RUnlock()
+
+	if !c.isConnectedInternal() {
+		return device.ErrNotConnected
+	}
+	c.rssiPoll.start(interval, c.pollRSSI)
+	return nil
+}
+
+// OnRSSI registers a handler receiving the RSSI readings of SetRSSIPollInterval. Handlers run on the poll
+// goroutine and should return quickly. Thread-safe.
+func (c *BLEConnection) OnRSSI(handler func(rssi int)) {
+	if handler == nil {
+		return
+	}
+	c.rssiMutex.Lock()
+	defer c.rssiMutex.Unlock()
+	c.rssiHandlers = append(c.rssiHandlers, handler)
+}
+
+// pollRSSI reads the RSSI once and notifies the OnRSSI handlers; failed reads are logged and skipped
+func (c *BLEConnection) pollRSSI() {
+	rssi, err := c.ReadRSSI()
+	if err != nil {
+		if c.logger != nil {
+			c.logger.WithError(err).Debug("RSSI poll failed")
+		}
+		return
+	}
+
+	c.rssiMutex.Lock()
+	handlers := append([]func(int){}, c.rssiHandlers...)
+	c.rssiMutex.Unlock()
+
+	for _, handler := range handlers {
+		handler(rssi)
+	}
+}
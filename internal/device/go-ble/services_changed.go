@@ -0,0 +1,71 @@
+package goble
+
+import (
+	"github.com/go-ble/ble"
+	"github.com/sirupsen/logrus"
+)
+
+// ----------------------------
+// Service Changed Indication
+// ----------------------------
+
+const (
+	genericAttributeServiceUUID = "1801" // Generic Attribute service
+	serviceChangedCharUUID      = "2a05" // Service Changed characteristic
+)
+
+// OnServicesChanged registers a handler that fires when the peripheral sends a Service Changed (0x2A05)
+// indication, meaning attribute handles may be stale. Handlers run on the notification goroutine and
+// should return quickly; deciding whether to rediscover is up to the caller. Thread-safe.
+func (c *BLEConnection) OnServicesChanged(handler func()) {
+	if handler == nil {
+		return
+	}
+	c.servicesChangedMutex.Lock()
+	defer c.servicesChangedMutex.Unlock()
+	c.servicesChangedHandlers = append(c.servicesChangedHandlers, handler)
+}
+
+// fireServicesChanged invokes all registered Service Changed handlers
+func (c *BLEConnection) fireServicesChanged() {
+	c.servicesChangedMutex.Lock()
+	handlers := append([]func(){}, c.servicesChangedHandlers...)
+	c.servicesChangedMutex.Unlock()
+
+	if c.logger != nil {
+		c.logger.WithField("handlers", len(handlers)).Info("Peripheral indicated Service Changed")
+	}
+
+	// Cached handles are no longer trustworthy; the next connection rediscovers
+	if c.gattCacheDir != "" {
+		if err := invalidateGATTCache(c.gattCacheDir, c.identifier); err != nil && c.logger != nil {
+			c.logger.WithError(err).Warn("Failed to invalidate GATT cache")
+		}
+	}
+	for _, handler := range handlers {
+		handler()
+	}
+}
+
+// subscribeServiceChanged enables Service Changed indications if the peripheral exposes 0x2A05.
+// Best-effort: failures are logged and do not fail the connection.
+func (c *BLEConnection) subscribeServiceChanged(client ble.Client) {
+	svc, ok := c.services[genericAttributeServiceUUID]
+	if !ok {
+		return
+	}
+	char, ok := svc.Characteristics[serviceChangedCharUUID]
+	if !ok || char.BLEChar == nil || char.BLEChar.Property&ble.CharIndicate == 0 {
+		return
+	}
+
+	err := NormalizeError(client.Subscribe(char.BLEChar, true, func(data []byte) {
+		c.ProcessCharacteristicNotification(char, data)
+	}))
+	if err != nil && c.logger != nil {
+		c.logger.WithFields(logrus.Fields{
+			"char_uuid": serviceChangedCharUUID,
+			"error":     err,
+		}).Warn("Failed to enable Service Changed indications")
+	}
+}
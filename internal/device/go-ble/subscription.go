@@ -3,6 +3,7 @@ package goble
 import (
 	"context"
 	"fmt"
+	"sort"
 	"sync"
 	"time"
 
@@ -27,14 +28,42 @@ func newRecord(mode device.StreamMode) *device.Record {
 	return r
 }
 
+// orderedCharacteristics returns the validated characteristics in the order of opt.Characteristics,
+// or sorted by UUID when opt subscribes to every characteristic of the service
+func orderedCharacteristics(opt *device.SubscribeOptions, chars map[string]*BLECharacteristic) []*BLECharacteristic {
+	var uuids []string
+	if len(opt.Characteristics) > 0 {
+		uuids = device.NormalizeUUIDs(opt.Characteristics)
+	} else {
+		for uuid := range chars {
+			uuids = append(uuids, uuid)
+		}
+		sort.Strings(uuids)
+	}
+
+	ordered := make([]*BLECharacteristic, 0, len(chars))
+	seen := make(map[string]bool, len(chars))
+	for _, uuid := range uuids {
+		if char, ok := chars[uuid]; ok && !seen[uuid] {
+			ordered = append(ordered, char)
+			seen[uuid] = true
+		}
+	}
+	return ordered
+}
+
 type Subscription struct {
 	Chars    []*BLECharacteristic
 	Mode     device.StreamMode
 	MaxRate  time.Duration
+	Lifetime time.Duration           // Tears the subscription down after this long (0 = until the connection closes)
+	Window   *device.AggregateWindow // Fixed aggregation window of StreamAggregated (nil = MaxRate ticks)
 	Callback func(*device.Record)
 
-	ctx    context.Context
-	cancel context.CancelFunc
+	pending *device.Record // Record coalesced by the connection-wide rate limit, awaiting dispatch
+	worker  int            // Callback pool worker for records routed per subscription
+	ctx     context.Context
+	cancel  context.CancelFunc
 }
 
 // ----------------------------
@@ -71,6 +100,34 @@ func (m *SubscriptionManager) Add(sub *Subscription, runner func(*Subscription))
 	})
 }
 
+// Remove drops a subscription that ended on its own from the manager
+func (m *SubscriptionManager) Remove(sub *Subscription) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for i, s := range m.subscriptions {
+		if s == sub {
+			m.subscriptions = append(m.subscriptions[:i], m.subscriptions[i+1:]...)
+			return
+		}
+	}
+}
+
+// Covers reports whether a subscription still managed by m consumes char
+func (m *SubscriptionManager) Covers(char *BLECharacteristic) bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for _, sub := range m.subscriptions {
+		for _, c := range sub.Chars {
+			if c == char {
+				return true
+			}
+		}
+	}
+	return false
+}
+
 // CancelAll cancels all active subscriptions and clears the list
 func (m *SubscriptionManager) CancelAll() {
 	m.mu.Lock()
@@ -110,8 +167,11 @@ func (m *SubscriptionManager) Done() {
 //	connection.Subscribe([]*device.SubscribeOptions{
 //	  { Service: "0000180d-0000-1000-8000-00805f9b34fb", Characteristics: []string{"00002a37-0000-1000-8000-00805f9b34fb"} },
 //	  { Service: "1000180d-0000-1000-8000-00805f9b34fb", Characteristics: []string{"10002a37-0000-1000-8000-00805f9b34fb"} }
-//	}, device.StreamEveryUpdate, 0, func(record *device.Record) { ... })
-func (c *BLEConnection) Subscribe(opts []*device.SubscribeOptions, mode device.StreamMode, maxRate time.Duration, callback func(*device.Record)) error {
+//	}, device.StreamEveryUpdate, device.StreamOptions{}, func(record *device.Record) { ... })
+//
+// A positive stream.Lifetime tears the subscription down after that wall-clock time and delivers a final
+// record with End set to the callback. A stream.Window is only valid with StreamAggregated and replaces stream.MaxRate.
+func (c *BLEConnection) Subscribe(opts []*device.SubscribeOptions, mode device.StreamMode, stream device.StreamOptions, callback func(*device.Record)) error {
 	// Validate parameters before acquiring any locks or allocating resources
 	if callback == nil {
 		return fmt.Errorf("no callback specified in Lua subscription")
@@ -121,6 +181,21 @@ func (c *BLEConnection) Subscribe(opts []*device.SubscribeOptions, mode device.S
 		return fmt.Errorf("no services specified in Lua subscription")
 	}
 
+	for _, opt := range opts {
+		if opt.ChannelBuffer < 0 {
+			return fmt.Errorf("channel buffer must not be negative, got %d", opt.ChannelBuffer)
+		}
+	}
+
+	if window := stream.Window; window != nil {
+		if mode != device.StreamAggregated {
+			return fmt.Errorf("aggregate window requires Aggregated stream mode")
+		}
+		if window.Interval <= 0 {
+			return fmt.Errorf("aggregate window must be positive, got %v", window.Interval)
+		}
+	}
+
 	c.logger.WithFields(map[string]interface{}{
 		"services": len(opts),
 		"mode":     mode,
@@ -143,9 +218,14 @@ func (c *BLEConnection) Subscribe(opts []*device.SubscribeOptions, mode device.S
 			return fmt.Errorf("lua subscription validation failed: %w", err)
 		}
 
-		// Convert validated BLECharacteristics for Subscription
-		for _, bleChar := range characteristicsToSubscribe {
-			allCharacteristics = append(allCharacteristics, bleChar)
+		// Keep the configured characteristic order (sorted when subscribing to a whole service),
+		// so records list their values deterministically (see device.Record.Keys)
+		allCharacteristics = append(allCharacteristics, orderedCharacteristics(opt, characteristicsToSubscribe)...)
+
+		if opt.ChannelBuffer > 0 {
+			for _, char := range characteristicsToSubscribe {
+				char.growUpdates(opt.ChannelBuffer)
+			}
 		}
 	}
 
@@ -175,10 +255,15 @@ func (c *BLEConnection) Subscribe(opts []*device.SubscribeOptions, mode device.S
 	sub := &Subscription{
 		Chars:    allCharacteristics,
 		Mode:     mode,
-		MaxRate:  maxRate,
+		MaxRate:  stream.MaxRate,
+		Lifetime: stream.Lifetime,
+		Window:   stream.Window,
 		Callback: callback,
 	}
 	sub.ctx, sub.cancel = context.WithCancel(c.ctx)
+	if c.callbackPool != nil {
+		sub.worker = c.callbackPool.workerFor(fmt.Sprintf("subscription-%p", sub))
+	}
 
 	// Add subscription to manager and start goroutine
 	c.subMgr.Add(sub, c.runSubscription)
@@ -212,7 +297,9 @@ func (c *BLEConnection) runSubscription(sub *Subscription) {
 
 	// Create a ticker for all modes with the appropriate interval
 	var ticker *time.Ticker
-	if sub.Mode == device.StreamBatched || sub.Mode == device.StreamAggregated {
+	if sub.Window != nil {
+		ticker = time.NewTicker(sub.Window.Interval)
+	} else if sub.Mode == device.StreamBatched || sub.Mode == device.StreamAggregated {
 		if sub.MaxRate <= 0 {
 			// Default to DefaultBatchedInterval for batched/aggregated modes if MaxRate is 0 or negative
 			sub.MaxRate = DefaultBatchedInterval
@@ -224,19 +311,33 @@ func (c *BLEConnection) runSubscription(sub *Subscription) {
 	}
 	defer ticker.Stop()
 
+	var expired <-chan time.Time
+	if sub.Lifetime > 0 {
+		timer := time.NewTimer(sub.Lifetime)
+		defer timer.Stop()
+		expired = timer.C
+	}
+
 	for {
 		select {
 		case <-sub.ctx.Done():
 			return
+		case <-expired:
+			c.expireSubscription(sub)
+			return
 		case <-ticker.C:
+			if c.rateLimiter != nil {
+				c.flushPending(sub)
+			}
+
 			if sub.Mode == device.StreamBatched {
 				record := newRecord(device.StreamBatched)
 				for _, c := range sub.Chars {
 					// Drain all available updates for this characteristic
 					for {
 						select {
-						case val := <-c.updates:
-							record.BatchValues[c.UUID()] = append(record.BatchValues[c.UUID()], val.Data)
+						case val := <-c.updatesChan():
+							record.AppendBatchValues(c.UUID(), val.Data)
 							if val.Flags != 0 {
 								record.Flags |= val.Flags
 							}
@@ -250,14 +351,16 @@ func (c *BLEConnection) runSubscription(sub *Subscription) {
 				}
 				// Only invoke callback when there's actual data to report
 				if len(record.BatchValues) > 0 {
-					sub.Callback(record)
+					c.dispatch(sub, record)
 				}
+			} else if sub.Window != nil {
+				c.flushAggregateWindow(sub)
 			} else if sub.Mode == device.StreamAggregated {
 				record := newRecord(device.StreamAggregated)
 				for _, c := range sub.Chars {
 					select {
-					case val := <-c.updates:
-						record.Values[c.UUID()] = val.Data
+					case val := <-c.updatesChan():
+						record.SetValue(c.UUID(), val.Data)
 						if val.Flags != 0 {
 							record.Flags |= val.Flags
 						}
@@ -270,14 +373,14 @@ func (c *BLEConnection) runSubscription(sub *Subscription) {
 				// Only invoke callback when there's actual data to report
 				// Skip empty aggregation ticks to avoid JSON serialization issues with empty Values
 				if len(record.Values) > 0 {
-					sub.Callback(record)
+					c.dispatch(sub, record)
 				}
 			} else if sub.Mode == device.StreamEveryUpdate {
 				for _, char := range sub.Chars {
 					select {
 					case <-sub.ctx.Done():
 						return
-					case val := <-char.updates:
+					case val := <-char.updatesChan():
 						if c.logger != nil {
 							c.logger.WithFields(map[string]interface{}{
 								"char": char.UUID(),
@@ -285,12 +388,12 @@ func (c *BLEConnection) runSubscription(sub *Subscription) {
 							}).Debug("[subscription] BLE notification received, calling callback")
 						}
 						record := newRecord(device.StreamEveryUpdate)
-						record.Values[char.UUID()] = val.Data
+						record.SetValue(char.UUID(), val.Data)
 						record.TsUs = val.TsUs
 						if val.Flags != 0 {
 							record.Flags |= val.Flags
 						}
-						sub.Callback(record)
+						c.dispatch(sub, record)
 						if c.logger != nil {
 							c.logger.Debug("[subscription] callback returned")
 						}
@@ -303,3 +406,76 @@ func (c *BLEConnection) runSubscription(sub *Subscription) {
 		}
 	}
 }
+
+// flushAggregateWindow delivers the values received during the window that just ended: every queued
+// update is drained and the latest value of each characteristic kept. Characteristics without a value are
+// flagged FlagMissing; an entirely empty window is only delivered with Window.EmitEmpty.
+func (c *BLEConnection) flushAggregateWindow(sub *Subscription) {
+	record := newRecord(device.StreamAggregated)
+	for _, char := range sub.Chars {
+		received := false
+	drain:
+		for {
+			select {
+			case val := <-char.updatesChan():
+				record.SetValue(char.UUID(), val.Data)
+				if val.Flags != 0 {
+					record.Flags |= val.Flags
+				}
+				releaseBLEValue(val)
+				received = true
+			default:
+				break drain
+			}
+		}
+		if !received {
+			record.Flags |= FlagMissing
+		}
+	}
+
+	if len(record.Values) == 0 && !sub.Window.EmitEmpty {
+		return
+	}
+	c.dispatch(sub, record)
+}
+
+// expireSubscription tears down a subscription whose lifetime elapsed: a record still held back by the
+// rate limit is delivered, followed by the End marker, then the subscription is cancelled and removed.
+// Notifications of characteristics no other subscription or handler consumes are disabled on the device.
+func (c *BLEConnection) expireSubscription(sub *Subscription) {
+	if c.logger != nil {
+		c.logger.WithField("lifetime", sub.Lifetime).Debug("Subscription lifetime elapsed, tearing down")
+	}
+
+	if sub.pending != nil {
+		record := sub.pending
+		sub.pending = nil
+		c.stats.delivered.Add(1)
+		c.deliver(sub, record)
+	}
+	c.deliver(sub, &device.Record{TsUs: time.Now().UnixMicro(), End: true})
+
+	sub.cancel()
+	c.subMgr.Remove(sub)
+	c.releaseCharacteristics(sub)
+}
+
+// releaseCharacteristics unsubscribes from the characteristics of a removed subscription that are no longer
+// consumed, clearing their CCCD so the peripheral stops sending notifications nobody receives.
+func (c *BLEConnection) releaseCharacteristics(sub *Subscription) {
+	c.connMutex.RLock()
+	client := c.client
+	connected := c.isConnectedInternal()
+	c.connMutex.RUnlock()
+	if !connected {
+		return
+	}
+
+	for _, char := range sub.Chars {
+		if char.cccd.Load() == cccdDisabled || c.subMgr.Covers(char) || char.hasHandlers() {
+			continue
+		}
+		// tryUnsubscribe logs the failure; the subscription is gone either way
+		_ = c.tryUnsubscribe(client, char, char.serviceUUID, char.uuid)
+	}
+}
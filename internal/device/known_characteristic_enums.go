@@ -0,0 +1,124 @@
+package device
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Well-known single-byte enum characteristic UUIDs
+const (
+	CharacteristicAlertLevel         = "2a06" // Immediate Alert and Link Loss services
+	CharacteristicBodySensorLocation = "2a38" // Heart Rate Service
+	CharacteristicScanRefresh        = "2a31" // Scan Parameters Service
+)
+
+// Well-known bitmask characteristic UUIDs
+const (
+	CharacteristicSupportedNewAlertCategory    = "2a47" // Alert Notification Service
+	CharacteristicSupportedUnreadAlertCategory = "2a48" // Alert Notification Service
+)
+
+// alertCategoryNames labels the bits of the Alert Notification Service Category ID Bit Mask
+var alertCategoryNames = []string{
+	"Simple Alert",
+	"Email",
+	"News",
+	"Call",
+	"Missed Call",
+	"SMS/MMS",
+	"Voice Mail",
+	"Schedule",
+	"High Prioritized Alert",
+	"Instant Message",
+}
+
+func init() {
+	RegisterEnumParser(CharacteristicAlertLevel, map[byte]string{
+		0: "None",
+		1: "Mild",
+		2: "High",
+	})
+	RegisterEnumParser(CharacteristicBodySensorLocation, map[byte]string{
+		0: "Other",
+		1: "Chest",
+		2: "Wrist",
+		3: "Finger",
+		4: "Hand",
+		5: "Ear Lobe",
+		6: "Foot",
+	})
+	RegisterEnumParser(CharacteristicScanRefresh, map[byte]string{
+		0: "Server Requires Refresh",
+	})
+	RegisterBitmaskParser(CharacteristicSupportedNewAlertCategory, alertCategoryNames)
+	RegisterBitmaskParser(CharacteristicSupportedUnreadAlertCategory, alertCategoryNames)
+}
+
+// EnumValue is the parsed value of a single-byte enum characteristic
+type EnumValue struct {
+	Value uint8  // Raw value
+	Name  string // Human-readable label, e.g. "Wrist"
+}
+
+// String returns the label
+func (e *EnumValue) String() string { return e.Name }
+
+// RegisterEnumParser registers a parser for a characteristic whose value is a single byte enum.
+// The parser returns an *EnumValue labelled from table, nil for values missing from table,
+// and an error for values that are not exactly 1 byte. Registration is not synchronized with
+// parsing: call it during initialization (e.g. from init()).
+func RegisterEnumParser(uuid string, table map[byte]string) {
+	names := make(map[byte]string, len(table))
+	for value, name := range table {
+		names[value] = name
+	}
+
+	normalizedUUID := NormalizeUUID(uuid)
+	characteristicParsers[normalizedUUID] = func(value []byte) (interface{}, error) {
+		if len(value) != 1 {
+			return nil, fmt.Errorf("characteristic %s value must be 1 byte, got %d", ShortenUUID(normalizedUUID), len(value))
+		}
+		name, ok := names[value[0]]
+		if !ok {
+			return nil, nil
+		}
+		return &EnumValue{Value: value[0], Name: name}, nil
+	}
+}
+
+// BitmaskValue is the parsed value of a bitmask characteristic
+type BitmaskValue struct {
+	Value uint32   // Raw bits, little-endian
+	Names []string // Labels of the set bits in bit order; empty (not nil) when no labelled bit is set
+}
+
+// String returns the labels joined with ", "
+func (b *BitmaskValue) String() string { return strings.Join(b.Names, ", ") }
+
+// RegisterBitmaskParser registers a parser for a characteristic whose value is a little-endian bitmask,
+// names[i] labelling bit i. The value may be shorter than the mask (trailing bytes omitted, as in the Alert
+// Notification 1- and 2-byte forms) but not longer; set bits without a label are left out of Names.
+// Like RegisterEnumParser, call it during initialization.
+func RegisterBitmaskParser(uuid string, names []string) {
+	labels := append([]string(nil), names...)
+	maxLen := (len(labels) + 7) / 8
+
+	normalizedUUID := NormalizeUUID(uuid)
+	characteristicParsers[normalizedUUID] = func(value []byte) (interface{}, error) {
+		if len(value) == 0 || len(value) > maxLen {
+			return nil, fmt.Errorf("characteristic %s value must be 1 to %d bytes, got %d", ShortenUUID(normalizedUUID), maxLen, len(value))
+		}
+
+		var bits uint32
+		for i, b := range value {
+			bits |= uint32(b) << (8 * i)
+		}
+		set := []string{}
+		for bit, name := range labels {
+			if bits&(1<<bit) != 0 {
+				set = append(set, name)
+			}
+		}
+		return &BitmaskValue{Value: bits, Names: set}, nil
+	}
+}
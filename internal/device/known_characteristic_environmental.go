@@ -0,0 +1,60 @@
+package device
+
+import (
+	"github.com/srg/blim/internal/bledb"
+)
+
+// Well-known Environmental Sensing characteristic UUIDs
+const (
+	CharacteristicTemperature = "2a6e" // sint16, 0.01 °C
+	CharacteristicHumidity    = "2a6f" // uint16, 0.01 %
+)
+
+// Bluetooth SIG unit UUIDs of scalar characteristic values
+const (
+	UnitDegreeCelsius = "272f"
+	UnitPercentage    = "27ad"
+)
+
+func init() {
+	characteristicParsers[CharacteristicTemperature] = parseTemperature
+	characteristicParsers[CharacteristicHumidity] = parseHumidity
+}
+
+// characteristicUnits maps characteristics whose parsed value is a bare number to the unit of that number
+var characteristicUnits = map[string]string{
+	CharacteristicTemperature: UnitDegreeCelsius,
+	CharacteristicHumidity:    UnitPercentage,
+}
+
+// CharacteristicUnit returns the unit name of a characteristic whose parsed value is a bare number,
+// resolved via the Bluetooth SIG unit registry (e.g., "thermodynamic temperature (degree Celsius)").
+// Returns "" for other characteristics.
+func CharacteristicUnit(uuid string) string {
+	unit, ok := characteristicUnits[NormalizeUUID(uuid)]
+	if !ok {
+		return ""
+	}
+	return bledb.LookupUnit(unit)
+}
+
+// parseTemperature parses the Temperature characteristic (0x2A6E): sint16 in units of 0.01 °C.
+// Returns degrees Celsius as float64, or nil for values that are not 2 bytes or 0x8000 ("value is not known").
+func parseTemperature(value []byte) (interface{}, error) {
+	raw, ok := readUint16LE(value, 0)
+	if !ok || len(value) != 2 || raw == 0x8000 {
+		return nil, nil
+	}
+	return float64(int16(raw)) / 100, nil
+}
+
+// parseHumidity parses the Humidity characteristic (0x2A6F): uint16 in units of 0.01 %.
+// Returns the relative humidity in percent as float64, or nil for values that are not 2 bytes,
+// 0xFFFF ("value is not known") or outside 0-100 %.
+func parseHumidity(value []byte) (interface{}, error) {
+	raw, ok := readUint16LE(value, 0)
+	if !ok || len(value) != 2 || raw > 10000 {
+		return nil, nil
+	}
+	return float64(raw) / 100, nil
+}
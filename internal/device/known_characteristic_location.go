@@ -0,0 +1,146 @@
+package device
+
+import (
+	"fmt"
+)
+
+// Well-known location characteristic UUIDs (Location and Navigation Service)
+const (
+	CharacteristicLocationAndSpeed = "2a67"
+)
+
+// Location and Speed flags (0x2A67, uint16)
+const (
+	LocationSpeedPresent         = 1 << 0  // Instantaneous Speed field present
+	LocationDistancePresent      = 1 << 1  // Total Distance field present
+	LocationLocationPresent      = 1 << 2  // Latitude and Longitude fields present
+	LocationElevationPresent     = 1 << 3  // Elevation field present
+	LocationHeadingPresent       = 1 << 4  // Heading field present
+	LocationRollingTimePresent   = 1 << 5  // Rolling Time field present
+	LocationUTCTimePresent       = 1 << 6  // UTC Time field present
+	LocationPositionStatusShift  = 7       // Bits 7-8: position status
+	LocationSpeedDistance3D      = 1 << 9  // Speed and distance are 3D (2D if clear)
+	LocationElevationSourceShift = 10      // Bits 10-11: elevation source
+	LocationHeadingFromCompass   = 1 << 12 // Heading from magnetic compass (from movement if clear)
+)
+
+var (
+	locationPositionStatusNames  = [4]string{"no_position", "ok", "estimated", "last_known"}
+	locationElevationSourceNames = [4]string{"positioning_system", "barometric", "database", "other"}
+)
+
+// LocationAndSpeed represents the Location and Speed characteristic (0x2A67).
+// Optional fields are nil when their flag is clear; values are scaled to meters, seconds and degrees.
+type LocationAndSpeed struct {
+	Flags         uint16
+	Speed         *float64  // Instantaneous speed in m/s (resolution 1/100)
+	TotalDistance *float64  // Total distance in m (resolution 1/10)
+	Latitude      *float64  // Degrees (resolution 1e-7)
+	Longitude     *float64  // Degrees (resolution 1e-7)
+	Elevation     *float64  // Meters (resolution 1/100)
+	Heading       *float64  // Degrees (resolution 1/100)
+	RollingTime   *uint8    // Seconds, wraps at 255
+	UTCTime       *DateTime // UTC time of the location fix
+}
+
+// PositionStatus returns the position status: "no_position", "ok", "estimated" or "last_known"
+func (ls *LocationAndSpeed) PositionStatus() string {
+	return locationPositionStatusNames[(ls.Flags>>LocationPositionStatusShift)&0x03]
+}
+
+// SpeedDistance3D reports whether speed and distance are 3D values (2D otherwise)
+func (ls *LocationAndSpeed) SpeedDistance3D() bool { return ls.Flags&LocationSpeedDistance3D != 0 }
+
+// ElevationSource returns the elevation source: "positioning_system", "barometric", "database" or "other"
+func (ls *LocationAndSpeed) ElevationSource() string {
+	return locationElevationSourceNames[(ls.Flags>>LocationElevationSourceShift)&0x03]
+}
+
+// HeadingSource returns the heading source: "movement" or "magnetic_compass"
+func (ls *LocationAndSpeed) HeadingSource() string {
+	if ls.Flags&LocationHeadingFromCompass != 0 {
+		return "magnetic_compass"
+	}
+	return "movement"
+}
+
+// locationAndSpeedLength returns the value length implied by the flags
+func locationAndSpeedLength(flags uint16) int {
+	n := 2 // Flags
+	for _, field := range []struct {
+		flag uint16
+		size int
+	}{
+		{LocationSpeedPresent, 2},       // uint16
+		{LocationDistancePresent, 3},    // uint24
+		{LocationLocationPresent, 8},    // 2x sint32
+		{LocationElevationPresent, 3},   // sint24
+		{LocationHeadingPresent, 2},     // uint16
+		{LocationRollingTimePresent, 1}, // uint8
+		{LocationUTCTimePresent, 7},     // Date Time
+	} {
+		if flags&field.flag != 0 {
+			n += field.size
+		}
+	}
+	return n
+}
+
+// parseLocationAndSpeed parses the Location and Speed characteristic (0x2A67) value.
+// Format: Flags (uint16), then the fields whose flags are set, in order: Instantaneous Speed (uint16, 1/100 m/s),
+// Total Distance (uint24, 1/10 m), Latitude and Longitude (sint32, 1e-7 degrees), Elevation (sint24, 1/100 m),
+// Heading (uint16, 1/100 degrees), Rolling Time (uint8, s), UTC Time (Date Time). All little-endian.
+func parseLocationAndSpeed(value []byte) (interface{}, error) {
+	flags, ok := readUint16LE(value, 0)
+	if !ok {
+		return nil, fmt.Errorf("location and speed value must be at least 2 bytes, got %d", len(value))
+	}
+	if expected := locationAndSpeedLength(flags); len(value) != expected {
+		return nil, fmt.Errorf("location and speed value must be %d bytes for flags 0x%04X, got %d", expected, flags, len(value))
+	}
+
+	scaled := func(raw int64, resolution float64) *float64 {
+		v := float64(raw) * resolution
+		return &v
+	}
+
+	ls := &LocationAndSpeed{Flags: flags}
+	offset := 2
+	if flags&LocationSpeedPresent != 0 {
+		speed, _ := readUint16LE(value, offset)
+		ls.Speed = scaled(int64(speed), 0.01)
+		offset += 2
+	}
+	if flags&LocationDistancePresent != 0 {
+		distance, _ := readUint24LE(value, offset)
+		ls.TotalDistance = scaled(int64(distance), 0.1)
+		offset += 3
+	}
+	if flags&LocationLocationPresent != 0 {
+		lat, _ := readUint32LE(value, offset)
+		lon, _ := readUint32LE(value, offset+4)
+		ls.Latitude = scaled(int64(int32(lat)), 1e-7)
+		ls.Longitude = scaled(int64(int32(lon)), 1e-7)
+		offset += 8
+	}
+	if flags&LocationElevationPresent != 0 {
+		elevation, _ := readUint24LE(value, offset)
+		ls.Elevation = scaled(int64(int32(elevation<<8)>>8), 0.01) // Sign-extend sint24
+		offset += 3
+	}
+	if flags&LocationHeadingPresent != 0 {
+		heading, _ := readUint16LE(value, offset)
+		ls.Heading = scaled(int64(heading), 0.01)
+		offset += 2
+	}
+	if flags&LocationRollingTimePresent != 0 {
+		rolling := value[offset]
+		ls.RollingTime = &rolling
+		offset++
+	}
+	if flags&LocationUTCTimePresent != 0 {
+		ls.UTCTime, _ = decodeDateTime(value[offset : offset+7])
+	}
+
+	return ls, nil
+}
@@ -0,0 +1,131 @@
+package device
+
+import (
+	"time"
+)
+
+// Well-known time characteristic UUIDs (Current Time Service)
+const (
+	CharacteristicDateTime    = "2a08"
+	CharacteristicCurrentTime = "2a2b"
+)
+
+// Current Time adjust reason flags (0x2A2B, byte 9)
+const (
+	AdjustReasonManual   = 1 << 0 // Manual time update
+	AdjustReasonExternal = 1 << 1 // External reference time update
+	AdjustReasonTimezone = 1 << 2 // Change of time zone
+	AdjustReasonDST      = 1 << 3 // Change of DST (daylight savings time)
+)
+
+// DateTime represents the Date Time characteristic (0x2A08).
+// Zero Year, Month, or Day means the field is not known.
+type DateTime struct {
+	Year    uint16 // 1582..9999, 0 = unknown
+	Month   uint8  // 1..12, 0 = unknown
+	Day     uint8  // 1..31, 0 = unknown
+	Hours   uint8  // 0..23
+	Minutes uint8  // 0..59
+	Seconds uint8  // 0..59
+}
+
+// Time converts the date time to a UTC time.Time.
+// Returns false if the date is not fully known or out of range.
+func (dt *DateTime) Time() (time.Time, bool) {
+	if dt.Year == 0 || dt.Month < 1 || dt.Month > 12 || dt.Day < 1 || dt.Day > 31 ||
+		dt.Hours > 23 || dt.Minutes > 59 || dt.Seconds > 59 {
+		return time.Time{}, false
+	}
+
+	t := time.Date(int(dt.Year), time.Month(dt.Month), int(dt.Day),
+		int(dt.Hours), int(dt.Minutes), int(dt.Seconds), 0, time.UTC)
+	if t.Day() != int(dt.Day) { // Normalized, e.g. Feb 30
+		return time.Time{}, false
+	}
+	return t, true
+}
+
+// RFC3339 formats the date time as an RFC3339 string (the characteristic carries no zone, UTC is assumed).
+// Returns an empty string if the date is not fully known.
+func (dt *DateTime) RFC3339() string {
+	t, ok := dt.Time()
+	if !ok {
+		return ""
+	}
+	return t.Format(time.RFC3339)
+}
+
+// decodeDateTime decodes the 7-byte Date Time layout shared by Date Time and Current Time.
+// Format: Year (uint16, little-endian), Month, Day, Hours, Minutes, Seconds (uint8).
+func decodeDateTime(value []byte) (*DateTime, bool) {
+	year, ok := readUint16LE(value, 0)
+	if !ok || len(value) < 7 {
+		return nil, false
+	}
+	return &DateTime{
+		Year:    year,
+		Month:   value[2],
+		Day:     value[3],
+		Hours:   value[4],
+		Minutes: value[5],
+		Seconds: value[6],
+	}, true
+}
+
+// parseDateTime parses the Date Time characteristic (0x2A08) value
+// Returns nil for values that are not exactly 7 bytes
+func parseDateTime(value []byte) (interface{}, error) {
+	if len(value) != 7 {
+		return nil, nil
+	}
+	dt, _ := decodeDateTime(value)
+	return dt, nil
+}
+
+// CurrentTime represents the Current Time characteristic (0x2A2B): Date Time extended with
+// day of week, 1/256 second fractions, and the reason of the last adjustment.
+type CurrentTime struct {
+	DateTime     DateTime
+	DayOfWeek    uint8 // 1 = Monday .. 7 = Sunday, 0 = unknown
+	Fractions256 uint8 // 1/256 fractions of a second
+	AdjustReason uint8 // Bitfield of AdjustReason* flags
+}
+
+// Manual reports whether the time was updated manually
+func (ct *CurrentTime) Manual() bool { return ct.AdjustReason&AdjustReasonManual != 0 }
+
+// External reports whether the time was updated from an external reference
+func (ct *CurrentTime) External() bool { return ct.AdjustReason&AdjustReasonExternal != 0 }
+
+// TimezoneChanged reports whether the time zone was changed
+func (ct *CurrentTime) TimezoneChanged() bool { return ct.AdjustReason&AdjustReasonTimezone != 0 }
+
+// DSTChanged reports whether the DST offset was changed
+func (ct *CurrentTime) DSTChanged() bool { return ct.AdjustReason&AdjustReasonDST != 0 }
+
+// RFC3339 formats the current time as an RFC3339 string, including fractions of a second when non-zero.
+// Returns an empty string if the date is not fully known.
+func (ct *CurrentTime) RFC3339() string {
+	t, ok := ct.DateTime.Time()
+	if !ok {
+		return ""
+	}
+	t = t.Add(time.Duration(ct.Fractions256) * time.Second / 256)
+	return t.Format(time.RFC3339Nano)
+}
+
+// parseCurrentTime parses the Current Time characteristic (0x2A2B) value
+// Format (10 bytes): Date Time (7 bytes), Day of Week, Fractions256, Adjust Reason (uint8).
+// Returns nil for values that are not exactly 10 bytes.
+func parseCurrentTime(value []byte) (interface{}, error) {
+	if len(value) != 10 {
+		return nil, nil
+	}
+	dt, _ := decodeDateTime(value[:7])
+	return &CurrentTime{
+		DateTime:     *dt,
+		DayOfWeek:    value[7],
+		Fractions256: value[8],
+		AdjustReason: value[9],
+	}, nil
+}
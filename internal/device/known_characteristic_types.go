@@ -1,15 +1,38 @@
 package device
 
 import (
+	"bytes"
 	"encoding/binary"
 	"fmt"
+	"unicode"
+	"unicode/utf8"
 
 	"github.com/srg/blim/internal/bledb"
 )
 
 // Well-known GATT characteristic UUIDs (16-bit short form, normalized without dashes)
 const (
-	CharacteristicAppearance = "2a01"
+	CharacteristicDeviceName       = "2a00"
+	CharacteristicAppearance       = "2a01"
+	CharacteristicSystemID         = "2a23"
+	CharacteristicModelNumber      = "2a24"
+	CharacteristicSerialNumber     = "2a25"
+	CharacteristicFirmwareRevision = "2a26"
+	CharacteristicHardwareRevision = "2a27"
+	CharacteristicSoftwareRevision = "2a28"
+	CharacteristicManufacturerName = "2a29"
+	CharacteristicPnPID            = "2a50"
+)
+
+// Well-known GATT service UUIDs (16-bit short form)
+const (
+	ServiceDeviceInformation = "180a"
+)
+
+// PnP ID vendor ID sources (0x2A50, byte 0)
+const (
+	VendorIDSourceBluetoothSIG = 0x01
+	VendorIDSourceUSB          = 0x02
 )
 
 // CharacteristicParser is a function that parses a characteristic value
@@ -18,24 +41,57 @@ type CharacteristicParser func([]byte) (interface{}, error)
 // parseAppearance parses the Appearance characteristic (0x2A01) value
 // Returns human-readable appearance name (e.g., "Phone"), or nil if unknown
 func parseAppearance(value []byte) (interface{}, error) {
-	if len(value) != 2 {
+	code, ok := readUint16LE(value, 0)
+	if !ok || len(value) != 2 {
 		return nil, fmt.Errorf("appearance value must be 2 bytes, got %d", len(value))
 	}
 
-	code := binary.LittleEndian.Uint16(value)
-	name := bledb.LookupAppearanceCode(code)
-
-	// Return nil if unknown (bledb returns empty string for unknown codes)
-	if name == "" {
+	name, ok := bledb.AppearanceName(code)
+	if !ok {
 		return nil, nil
 	}
 
 	return name, nil
 }
 
+// PnPID represents the PnP ID characteristic (0x2A50) of the Device Information Service
+type PnPID struct {
+	VendorIDSource uint8  // 0x01 = Bluetooth SIG company ID, 0x02 = USB Implementer's Forum vendor ID
+	VendorID       uint16 // Vendor identifier, interpreted according to VendorIDSource
+	ProductID      uint16 // Manufacturer-managed product identifier
+	ProductVersion uint16 // Manufacturer-managed product version
+	VendorName     string // Vendor name for Bluetooth SIG company IDs, empty if unknown
+}
+
+// ParsePnPID parses the PnP ID characteristic (0x2A50) value.
+// Format (7 bytes): Vendor ID Source (uint8), Vendor ID, Product ID, Product Version (uint16, little-endian).
+func ParsePnPID(value []byte) (*PnPID, error) {
+	if len(value) != 7 {
+		return nil, fmt.Errorf("PnP ID value must be 7 bytes, got %d", len(value))
+	}
+
+	pnp := &PnPID{
+		VendorIDSource: value[0],
+		VendorID:       binary.LittleEndian.Uint16(value[1:3]),
+		ProductID:      binary.LittleEndian.Uint16(value[3:5]),
+		ProductVersion: binary.LittleEndian.Uint16(value[5:7]),
+	}
+
+	// Only Bluetooth SIG company IDs can be resolved via bledb (vendor map is keyed by decimal company ID)
+	if pnp.VendorIDSource == VendorIDSourceBluetoothSIG {
+		pnp.VendorName = bledb.LookupVendor(fmt.Sprintf("%d", pnp.VendorID))
+	}
+
+	return pnp, nil
+}
+
 // characteristicParsers maps normalized characteristic UUIDs to their parser functions
 var characteristicParsers = map[string]CharacteristicParser{
-	CharacteristicAppearance: parseAppearance,
+	CharacteristicAppearance:  parseAppearance,
+	CharacteristicDateTime:    parseDateTime,
+	CharacteristicCurrentTime: parseCurrentTime,
+
+	CharacteristicLocationAndSpeed: parseLocationAndSpeed,
 }
 
 // IsParsableCharacteristic returns true if the characteristic UUID supports value parsing
@@ -60,3 +116,53 @@ func ParseCharacteristicValue(uuid string, value []byte) (interface{}, error) {
 
 	return parser(value)
 }
+
+// stringCharacteristics lists well-known characteristics whose value is defined as a UTF-8 string
+var stringCharacteristics = map[string]struct{}{
+	CharacteristicDeviceName:       {},
+	CharacteristicModelNumber:      {},
+	CharacteristicSerialNumber:     {},
+	CharacteristicFirmwareRevision: {},
+	CharacteristicHardwareRevision: {},
+	CharacteristicSoftwareRevision: {},
+	CharacteristicManufacturerName: {},
+}
+
+// IsUTF8Characteristic returns true if the characteristic value is known to be a UTF-8 string.
+// A characteristic qualifies if it is listed as a well-known string characteristic, or if it has
+// a Presentation Format descriptor (0x2904) declaring the UTF-8 format.
+func IsUTF8Characteristic(uuid string, descriptors []Descriptor) bool {
+	if _, exists := stringCharacteristics[NormalizeUUID(uuid)]; exists {
+		return true
+	}
+
+	for _, desc := range descriptors {
+		if desc == nil || NormalizeUUID(desc.UUID()) != DescriptorPresentationFormat {
+			continue
+		}
+		if pf, ok := desc.ParsedValue().(*PresentationFormat); ok && pf != nil && pf.Format == FormatUTF8 {
+			return true
+		}
+	}
+
+	return false
+}
+
+// DecodeUTF8Value decodes a characteristic value as a UTF-8 string.
+// Trailing NUL padding (common in fixed-size string fields) is stripped.
+// Returns ("", false) if the value is not valid UTF-8 or contains non-printable characters.
+func DecodeUTF8Value(value []byte) (string, bool) {
+	trimmed := bytes.TrimRight(value, "\x00")
+	if !utf8.Valid(trimmed) {
+		return "", false
+	}
+
+	str := string(trimmed)
+	for _, r := range str {
+		if !unicode.IsPrint(r) && !unicode.IsSpace(r) {
+			return "", false
+		}
+	}
+
+	return str, true
+}
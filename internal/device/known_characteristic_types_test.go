@@ -0,0 +1,569 @@
+package device
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// ----------------------------
+// UTF-8 String Characteristic Tests
+// ----------------------------
+
+// stubDescriptor is a minimal Descriptor implementation for parser tests
+type stubDescriptor struct {
+	uuid   string
+	parsed interface{}
+}
+
+func (d *stubDescriptor) UUID() string                         { return d.uuid }
+func (d *stubDescriptor) Handle() uint16                       { return 0 }
+func (d *stubDescriptor) Index() uint8                         { return 0 }
+func (d *stubDescriptor) KnownName() string                    { return "" }
+func (d *stubDescriptor) Value() []byte                        { return nil }
+func (d *stubDescriptor) ParsedValue() interface{}             { return d.parsed }
+func (d *stubDescriptor) Read(_ time.Duration) ([]byte, error) { return nil, nil }
+
+func TestIsUTF8Characteristic(t *testing.T) {
+	// GOAL: Verify string characteristics are detected via the well-known table and the Presentation Format descriptor
+	//
+	// TEST SCENARIO: Check known/unknown UUIDs with and without 2904 descriptors → detection matches expectations
+
+	tests := []struct {
+		name        string
+		uuid        string
+		descriptors []Descriptor
+		expected    bool
+	}{
+		{name: "manufacturer name", uuid: "2a29", expected: true},
+		{name: "model number full UUID", uuid: "00002a24-0000-1000-8000-00805f9b34fb", expected: true},
+		{name: "device name uppercase", uuid: "2A00", expected: true},
+		{name: "battery level", uuid: "2a19", expected: false},
+		{
+			name: "custom UUID with UTF-8 presentation format",
+			uuid: "5678",
+			descriptors: []Descriptor{
+				&stubDescriptor{uuid: "2904", parsed: &PresentationFormat{Format: FormatUTF8}},
+			},
+			expected: true,
+		},
+		{
+			name: "custom UUID with uint8 presentation format",
+			uuid: "5678",
+			descriptors: []Descriptor{
+				&stubDescriptor{uuid: "2904", parsed: &PresentationFormat{Format: FormatUint8}},
+			},
+			expected: false,
+		},
+		{
+			name: "custom UUID with failed presentation format read",
+			uuid: "5678",
+			descriptors: []Descriptor{
+				&stubDescriptor{uuid: "2904", parsed: &DescriptorError{Reason: "timeout"}},
+			},
+			expected: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, IsUTF8Characteristic(tt.uuid, tt.descriptors))
+		})
+	}
+}
+
+func TestDecodeUTF8Value(t *testing.T) {
+	// GOAL: Verify UTF-8 decoding accepts printable text and rejects binary or invalid data
+	//
+	// TEST SCENARIO: Decode various byte sequences → valid text returned → invalid/binary rejected
+
+	tests := []struct {
+		name     string
+		data     []byte
+		expected string
+		ok       bool
+	}{
+		{name: "ascii", data: []byte("Acme"), expected: "Acme", ok: true},
+		{name: "multi-byte", data: []byte("Grüße"), expected: "Grüße", ok: true},
+		{name: "trailing NUL padding", data: []byte{'A', 'B', 0x00, 0x00}, expected: "AB", ok: true},
+		{name: "empty", data: []byte{}, expected: "", ok: true},
+		{name: "invalid UTF-8", data: []byte{0xff, 0xfe}, expected: "", ok: false},
+		{name: "control characters", data: []byte{0x01, 0x02, 0x03}, expected: "", ok: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			str, ok := DecodeUTF8Value(tt.data)
+			assert.Equal(t, tt.ok, ok)
+			assert.Equal(t, tt.expected, str)
+		})
+	}
+}
+
+// ----------------------------
+// Date Time / Current Time Tests
+// ----------------------------
+
+func TestParseDateTime(t *testing.T) {
+	// GOAL: Verify the Date Time (0x2A08) parser decodes the 7-byte layout and formats RFC3339
+	//
+	// TEST SCENARIO: Parse valid, unknown-date, and wrong-length values → fields and RFC3339 match expectations
+
+	parsed, err := ParseCharacteristicValue("2a08", []byte{0xEA, 0x07, 0x0A, 0x10, 0x0C, 0x1E, 0x2D})
+	assert.NoError(t, err)
+	dt, ok := parsed.(*DateTime)
+	if assert.True(t, ok, "Date Time MUST parse to *DateTime, got %T", parsed) {
+		assert.Equal(t, DateTime{Year: 2026, Month: 10, Day: 16, Hours: 12, Minutes: 30, Seconds: 45}, *dt)
+		assert.Equal(t, "2026-10-16T12:30:45Z", dt.RFC3339())
+	}
+
+	parsed, err = ParseCharacteristicValue("2a08", []byte{0x00, 0x00, 0x00, 0x00, 0x0C, 0x1E, 0x2D})
+	assert.NoError(t, err)
+	if dt, ok := parsed.(*DateTime); assert.True(t, ok) {
+		assert.Empty(t, dt.RFC3339(), "unknown date MUST NOT format as RFC3339")
+	}
+
+	parsed, err = ParseCharacteristicValue("2a08", []byte{0xEA, 0x07, 0x0A})
+	assert.NoError(t, err)
+	assert.Nil(t, parsed, "truncated Date Time MUST parse to nil")
+}
+
+func TestParseCurrentTime(t *testing.T) {
+	// GOAL: Verify the Current Time (0x2A2B) parser extends Date Time with day of week, fractions, and adjust reason
+	//
+	// TEST SCENARIO: Parse 10-byte values and other lengths → fields, flags, and RFC3339 match expectations
+
+	tests := []struct {
+		name     string
+		value    []byte
+		expected *CurrentTime
+		rfc3339  string
+		flags    [4]bool // manual, external, timezone, dst
+	}{
+		{
+			name:  "manual update with fractions",
+			value: []byte{0xEA, 0x07, 0x0A, 0x10, 0x0C, 0x1E, 0x2D, 0x05, 0x80, 0x01},
+			expected: &CurrentTime{
+				DateTime:     DateTime{Year: 2026, Month: 10, Day: 16, Hours: 12, Minutes: 30, Seconds: 45},
+				DayOfWeek:    5,
+				Fractions256: 0x80,
+				AdjustReason: AdjustReasonManual,
+			},
+			rfc3339: "2026-10-16T12:30:45.5Z",
+			flags:   [4]bool{true, false, false, false},
+		},
+		{
+			name:  "timezone and DST change",
+			value: []byte{0xEA, 0x07, 0x03, 0x1D, 0x02, 0x00, 0x00, 0x07, 0x00, 0x0C},
+			expected: &CurrentTime{
+				DateTime:     DateTime{Year: 2026, Month: 3, Day: 29, Hours: 2},
+				DayOfWeek:    7,
+				AdjustReason: AdjustReasonTimezone | AdjustReasonDST,
+			},
+			rfc3339: "2026-03-29T02:00:00Z",
+			flags:   [4]bool{false, false, true, true},
+		},
+		{
+			name:     "unknown date",
+			value:    []byte{0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02},
+			expected: &CurrentTime{AdjustReason: AdjustReasonExternal},
+			flags:    [4]bool{false, true, false, false},
+		},
+		{name: "Date Time length", value: []byte{0xEA, 0x07, 0x0A, 0x10, 0x0C, 0x1E, 0x2D}},
+		{name: "too long", value: make([]byte, 11)},
+		{name: "empty", value: []byte{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			parsed, err := ParseCharacteristicValue("2a2b", tt.value)
+			assert.NoError(t, err)
+
+			if tt.expected == nil {
+				assert.Nil(t, parsed, "lengths other than 10 MUST parse to nil")
+				return
+			}
+
+			ct, ok := parsed.(*CurrentTime)
+			if !assert.True(t, ok, "Current Time MUST parse to *CurrentTime, got %T", parsed) {
+				return
+			}
+			assert.Equal(t, *tt.expected, *ct)
+			assert.Equal(t, tt.rfc3339, ct.RFC3339())
+			assert.Equal(t, tt.flags, [4]bool{ct.Manual(), ct.External(), ct.TimezoneChanged(), ct.DSTChanged()})
+		})
+	}
+}
+
+// ----------------------------
+// Location and Speed Tests
+// ----------------------------
+
+func TestParseLocationAndSpeed(t *testing.T) {
+	// GOAL: Verify the Location and Speed (0x2A67) parser follows the flag-gated layout and scales every field
+	//
+	// TEST SCENARIO: Parse all-fields and location-only values → present fields scaled, absent fields nil → truncated or padded values rejected
+
+	t.Run("all fields", func(t *testing.T) {
+		// Flags 0x16FF: all fields, position ok, 3D, barometric elevation, compass heading
+		value := []byte{
+			0xFF, 0x16,
+			0xD2, 0x04, // Speed 1234 → 12.34 m/s
+			0x40, 0xE2, 0x01, // Distance 123456 → 12345.6 m
+			0xCF, 0x24, 0x4E, 0x16, // Latitude 374219983 → 37.4219983°
+			0x81, 0x73, 0x3B, 0xB7, // Longitude -1220840575 → -122.0840575°
+			0x1E, 0xFB, 0xFF, // Elevation -1250 → -12.5 m
+			0x78, 0x69, // Heading 27000 → 270°
+			0x1E,                                     // Rolling time 30 s
+			0xEA, 0x07, 0x0A, 0x10, 0x0C, 0x1E, 0x2D, // UTC 2026-10-16 12:30:45
+		}
+
+		parsed, err := ParseCharacteristicValue("2a67", value)
+		require.NoError(t, err)
+		ls, ok := parsed.(*LocationAndSpeed)
+		require.True(t, ok, "Location and Speed MUST parse to *LocationAndSpeed, got %T", parsed)
+
+		require.NotNil(t, ls.Speed)
+		assert.InDelta(t, 12.34, *ls.Speed, 1e-9)
+		require.NotNil(t, ls.TotalDistance)
+		assert.InDelta(t, 12345.6, *ls.TotalDistance, 1e-9)
+		require.NotNil(t, ls.Latitude)
+		assert.InDelta(t, 37.4219983, *ls.Latitude, 1e-9)
+		require.NotNil(t, ls.Longitude)
+		assert.InDelta(t, -122.0840575, *ls.Longitude, 1e-9)
+		require.NotNil(t, ls.Elevation)
+		assert.InDelta(t, -12.5, *ls.Elevation, 1e-9, "elevation MUST be sign-extended from 24 bits")
+		require.NotNil(t, ls.Heading)
+		assert.InDelta(t, 270.0, *ls.Heading, 1e-9)
+		require.NotNil(t, ls.RollingTime)
+		assert.Equal(t, uint8(30), *ls.RollingTime)
+		require.NotNil(t, ls.UTCTime)
+		assert.Equal(t, "2026-10-16T12:30:45Z", ls.UTCTime.RFC3339())
+
+		assert.Equal(t, "ok", ls.PositionStatus())
+		assert.True(t, ls.SpeedDistance3D())
+		assert.Equal(t, "barometric", ls.ElevationSource())
+		assert.Equal(t, "magnetic_compass", ls.HeadingSource())
+	})
+
+	t.Run("location only", func(t *testing.T) {
+		// Flags 0x0084: location present, position ok
+		value := []byte{0x84, 0x00, 0x00, 0x08, 0xD0, 0xEB, 0x48, 0xB5, 0x20, 0x5A}
+
+		parsed, err := ParseCharacteristicValue("2a67", value)
+		require.NoError(t, err)
+		ls := parsed.(*LocationAndSpeed)
+
+		assert.InDelta(t, -33.8688, *ls.Latitude, 1e-9)
+		assert.InDelta(t, 151.2093, *ls.Longitude, 1e-9)
+		assert.Nil(t, ls.Speed, "absent fields MUST be nil")
+		assert.Nil(t, ls.TotalDistance)
+		assert.Nil(t, ls.Elevation)
+		assert.Nil(t, ls.Heading)
+		assert.Nil(t, ls.RollingTime)
+		assert.Nil(t, ls.UTCTime)
+		assert.False(t, ls.SpeedDistance3D())
+		assert.Equal(t, "positioning_system", ls.ElevationSource())
+		assert.Equal(t, "movement", ls.HeadingSource())
+	})
+
+	t.Run("flags only", func(t *testing.T) {
+		parsed, err := ParseCharacteristicValue("2a67", []byte{0x00, 0x01})
+		require.NoError(t, err)
+		assert.Equal(t, "estimated", parsed.(*LocationAndSpeed).PositionStatus())
+	})
+
+	t.Run("invalid lengths", func(t *testing.T) {
+		_, err := ParseCharacteristicValue("2a67", []byte{0x04})
+		assert.Error(t, err, "value shorter than flags MUST fail")
+
+		_, err = ParseCharacteristicValue("2a67", []byte{0x04, 0x00, 0x01, 0x02, 0x03})
+		assert.ErrorContains(t, err, "must be 10 bytes for flags 0x0004", "truncated location MUST fail")
+
+		_, err = ParseCharacteristicValue("2a67", []byte{0x01, 0x00, 0xD2, 0x04, 0x00})
+		assert.Error(t, err, "trailing bytes MUST fail")
+	})
+}
+
+func TestParseWeightMeasurement(t *testing.T) {
+	// GOAL: Verify the Weight Measurement (0x2A9D) parser scales weight and height by the unit flag and gates optional fields
+	//
+	// TEST SCENARIO: Parse SI value with all fields, imperial value with BMI, unsuccessful measurement → kg/m values and present fields match → wrong lengths rejected
+
+	t.Run("SI with all fields", func(t *testing.T) {
+		value := []byte{
+			0x0E,       // Flags: timestamp, user ID, BMI and height (SI)
+			0xB0, 0x36, // Weight 14000 → 70 kg
+			0xEA, 0x07, 0x0A, 0x10, 0x07, 0x1E, 0x00, // 2026-10-16 07:30:00
+			0x01,       // User 1
+			0xE5, 0x00, // BMI 229 → 22.9
+			0xD6, 0x06, // Height 1750 → 1.75 m
+		}
+
+		parsed, err := ParseCharacteristicValue("2a9d", value)
+		require.NoError(t, err)
+		wm, ok := parsed.(*WeightMeasurement)
+		require.True(t, ok, "Weight Measurement MUST parse to *WeightMeasurement, got %T", parsed)
+
+		assert.False(t, wm.Imperial())
+		require.NotNil(t, wm.Weight)
+		assert.InDelta(t, 70.0, *wm.Weight, 1e-9)
+		require.NotNil(t, wm.Timestamp)
+		assert.Equal(t, "2026-10-16T07:30:00Z", wm.Timestamp.RFC3339())
+		require.NotNil(t, wm.UserID)
+		assert.Equal(t, uint8(1), *wm.UserID)
+		require.NotNil(t, wm.BMI)
+		assert.InDelta(t, 22.9, *wm.BMI, 1e-9)
+		require.NotNil(t, wm.Height)
+		assert.InDelta(t, 1.75, *wm.Height, 1e-9)
+	})
+
+	t.Run("imperial converted to kg and m", func(t *testing.T) {
+		value := []byte{
+			0x09,       // Flags: imperial, BMI and height
+			0x48, 0x3C, // Weight 15432 → 154.32 lb
+			0xE5, 0x00, // BMI 22.9
+			0xB1, 0x02, // Height 689 → 68.9 in
+		}
+
+		parsed, err := ParseCharacteristicValue("2a9d", value)
+		require.NoError(t, err)
+		wm := parsed.(*WeightMeasurement)
+
+		assert.True(t, wm.Imperial())
+		assert.InDelta(t, 154.32*0.45359237, *wm.Weight, 1e-9, "pounds MUST be converted to kilograms")
+		assert.InDelta(t, 68.9*0.0254, *wm.Height, 1e-9, "inches MUST be converted to meters")
+		assert.Nil(t, wm.Timestamp, "absent fields MUST be nil")
+		assert.Nil(t, wm.UserID)
+	})
+
+	t.Run("unsuccessful measurement", func(t *testing.T) {
+		parsed, err := ParseCharacteristicValue("2a9d", []byte{0x00, 0xFF, 0xFF})
+		require.NoError(t, err)
+		assert.Nil(t, parsed.(*WeightMeasurement).Weight, "0xFFFF MUST report no weight")
+	})
+
+	t.Run("invalid lengths", func(t *testing.T) {
+		_, err := ParseCharacteristicValue("2a9d", []byte{})
+		assert.Error(t, err, "empty value MUST fail")
+
+		_, err = ParseCharacteristicValue("2a9d", []byte{0x00, 0xB0})
+		assert.ErrorContains(t, err, "must be at least 3 bytes", "value shorter than flags and weight MUST fail")
+
+		_, err = ParseCharacteristicValue("2a9d", []byte{0x04, 0xB0, 0x36})
+		assert.ErrorContains(t, err, "must be 4 bytes for flags 0x04", "missing user ID MUST fail")
+
+		_, err = ParseCharacteristicValue("2a9d", []byte{0x00, 0xB0, 0x36, 0x00})
+		assert.Error(t, err, "trailing bytes MUST fail")
+	})
+}
+
+// ----------------------------
+// Environmental Sensing Parser Tests
+// ----------------------------
+
+func TestParseTemperature(t *testing.T) {
+	// GOAL: Verify the Temperature (0x2A6E) parser scales sint16 hundredths to degrees Celsius
+	//
+	// TEST SCENARIO: Positive and negative values → °C → unknown marker and wrong length nil → unit resolved
+
+	tests := []struct {
+		name     string
+		value    []byte
+		expected interface{}
+	}{
+		{"positive", []byte{0x29, 0x09}, 23.45},
+		{"negative", []byte{0x0C, 0xFE}, -5.0},
+		{"zero", []byte{0x00, 0x00}, 0.0},
+		{"not known", []byte{0x00, 0x80}, nil},
+		{"too short", []byte{0x29}, nil},
+		{"too long", []byte{0x29, 0x09, 0x00}, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			parsed, err := ParseCharacteristicValue("2a6e", tt.value)
+			require.NoError(t, err)
+			if tt.expected == nil {
+				assert.Nil(t, parsed)
+				return
+			}
+			assert.InDelta(t, tt.expected, parsed, 1e-9)
+		})
+	}
+
+	assert.True(t, IsParsableCharacteristic("00002a6e-0000-1000-8000-00805f9b34fb"), "full UUID MUST resolve the parser")
+	assert.Contains(t, strings.ToLower(CharacteristicUnit("2a6e")), "celsius", "unit MUST resolve via bledb")
+	assert.Empty(t, CharacteristicUnit("2a06"), "non-scalar characteristic MUST have no unit")
+}
+
+func TestParseHumidity(t *testing.T) {
+	// GOAL: Verify the Humidity (0x2A6F) parser scales uint16 hundredths to percent and rejects out-of-range values
+	//
+	// TEST SCENARIO: In-range values → percent → 100 % boundary accepted → above 100 %, unknown marker and wrong length nil
+
+	tests := []struct {
+		name     string
+		value    []byte
+		expected interface{}
+	}{
+		{"typical", []byte{0xC6, 0x11}, 45.5},
+		{"zero", []byte{0x00, 0x00}, 0.0},
+		{"saturated", []byte{0x10, 0x27}, 100.0},
+		{"above 100 percent", []byte{0x11, 0x27}, nil},
+		{"not known", []byte{0xFF, 0xFF}, nil},
+		{"too short", []byte{0xC6}, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			parsed, err := ParseCharacteristicValue("2a6f", tt.value)
+			require.NoError(t, err)
+			if tt.expected == nil {
+				assert.Nil(t, parsed)
+				return
+			}
+			assert.InDelta(t, tt.expected, parsed, 1e-9)
+		})
+	}
+
+	assert.NotEmpty(t, CharacteristicUnit("2a6f"), "unit MUST resolve via bledb")
+}
+
+// ----------------------------
+// Enum Parser Tests
+// ----------------------------
+
+func TestParseAlertLevel(t *testing.T) {
+	// GOAL: Verify the Alert Level (0x2A06) enum parser maps levels to labels and ignores reserved values
+	//
+	// TEST SCENARIO: Parse levels 0-2 → value and label → reserved level nil → wrong length rejected
+
+	for level, name := range []string{"None", "Mild", "High"} {
+		parsed, err := ParseCharacteristicValue("2a06", []byte{byte(level)})
+		require.NoError(t, err)
+		alert, ok := parsed.(*EnumValue)
+		require.True(t, ok, "Alert Level MUST parse to *EnumValue, got %T", parsed)
+		assert.Equal(t, uint8(level), alert.Value)
+		assert.Equal(t, name, alert.Name)
+	}
+
+	parsed, err := ParseCharacteristicValue("2a06", []byte{3})
+	assert.NoError(t, err)
+	assert.Nil(t, parsed, "reserved level MUST parse to nil")
+
+	_, err = ParseCharacteristicValue("2a06", []byte{1, 0})
+	assert.Error(t, err, "2-byte value MUST be rejected")
+}
+
+func TestParseBodySensorLocation(t *testing.T) {
+	// GOAL: Verify the Body Sensor Location (0x2A38) enum parser resolves every defined location to its label
+	//
+	// TEST SCENARIO: Parse values 0-6 → value and label → reserved value nil → wrong length rejected
+
+	tests := []struct {
+		value byte
+		name  string
+	}{
+		{0, "Other"},
+		{1, "Chest"},
+		{2, "Wrist"},
+		{3, "Finger"},
+		{4, "Hand"},
+		{5, "Ear Lobe"},
+		{6, "Foot"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			parsed, err := ParseCharacteristicValue("2a38", []byte{tt.value})
+			require.NoError(t, err)
+			location, ok := parsed.(*EnumValue)
+			require.True(t, ok, "Body Sensor Location MUST parse to *EnumValue, got %T", parsed)
+			assert.Equal(t, tt.value, location.Value)
+			assert.Equal(t, tt.name, location.Name)
+		})
+	}
+
+	parsed, err := ParseCharacteristicValue("2a38", []byte{7})
+	assert.NoError(t, err)
+	assert.Nil(t, parsed, "reserved value MUST parse to nil")
+
+	_, err = ParseCharacteristicValue("2a38", []byte{})
+	assert.Error(t, err, "empty value MUST be rejected")
+}
+
+func TestParseScanRefresh(t *testing.T) {
+	// GOAL: Verify the Scan Refresh (0x2A31) enum parser labels the only defined value
+	//
+	// TEST SCENARIO: Parse 0 → "Server Requires Refresh" → reserved value nil
+
+	parsed, err := ParseCharacteristicValue("2a31", []byte{0x00})
+	require.NoError(t, err)
+	assert.Equal(t, &EnumValue{Value: 0x00, Name: "Server Requires Refresh"}, parsed)
+
+	parsed, err = ParseCharacteristicValue("2a31", []byte{0x01})
+	assert.NoError(t, err)
+	assert.Nil(t, parsed, "reserved value MUST parse to nil")
+}
+
+func TestParseAlertCategory(t *testing.T) {
+	// GOAL: Verify the Supported New/Unread Alert Category (0x2A47/0x2A48) parsers decode the category bitmask
+	//
+	// TEST SCENARIO: 1- and 2-byte forms → set category names in bit order → zero and reserved bits → empty list → wrong length rejected
+
+	tests := []struct {
+		name     string
+		value    []byte
+		expected []string
+	}{
+		{"zero", []byte{0x00}, []string{}},
+		{"one byte", []byte{0x0A}, []string{"Email", "Call"}},
+		{"two bytes", []byte{0x01, 0x03}, []string{"Simple Alert", "High Prioritized Alert", "Instant Message"}},
+		{"all categories", []byte{0xFF, 0x03}, alertCategoryNames},
+		{"reserved bits only", []byte{0x00, 0xFC}, []string{}},
+	}
+	for _, uuid := range []string{"2a47", "2a48"} {
+		for _, tt := range tests {
+			t.Run(uuid+" "+tt.name, func(t *testing.T) {
+				parsed, err := ParseCharacteristicValue(uuid, tt.value)
+				require.NoError(t, err)
+				categories, ok := parsed.(*BitmaskValue)
+				require.True(t, ok, "alert category MUST parse to *BitmaskValue, got %T", parsed)
+				assert.Equal(t, tt.expected, categories.Names)
+			})
+		}
+	}
+
+	parsed, err := ParseCharacteristicValue("2a47", []byte{0x06, 0x00})
+	require.NoError(t, err)
+	assert.Equal(t, "Email, News", parsed.(*BitmaskValue).String())
+	assert.Equal(t, uint32(0x06), parsed.(*BitmaskValue).Value)
+
+	_, err = ParseCharacteristicValue("2a47", []byte{})
+	assert.Error(t, err, "empty value MUST be rejected")
+	_, err = ParseCharacteristicValue("2a48", []byte{0x01, 0x00, 0x00})
+	assert.Error(t, err, "3-byte value MUST be rejected")
+}
+
+func TestRegisterEnumParser(t *testing.T) {
+	// GOAL: Verify RegisterEnumParser builds a parser from a declarative table
+	//
+	// TEST SCENARIO: Register table for a custom UUID → parsable → labelled and unmapped values → later table edits ignored
+
+	uuid := "F000AA01-0451-4000-B000-000000000000"
+	table := map[byte]string{0x01: "On", 0x02: "Off"}
+	RegisterEnumParser(uuid, table)
+	t.Cleanup(func() { delete(characteristicParsers, NormalizeUUID(uuid)) })
+	table[0x03] = "Standby"
+
+	assert.True(t, IsParsableCharacteristic(uuid), "registered enum MUST be parsable")
+
+	parsed, err := ParseCharacteristicValue(uuid, []byte{0x02})
+	require.NoError(t, err)
+	assert.Equal(t, &EnumValue{Value: 0x02, Name: "Off"}, parsed)
+
+	parsed, err = ParseCharacteristicValue(uuid, []byte{0x03})
+	assert.NoError(t, err)
+	assert.Nil(t, parsed, "values added to the table after registration MUST NOT be labelled")
+}
@@ -0,0 +1,107 @@
+package device
+
+import (
+	"fmt"
+)
+
+// Well-known Weight Scale characteristic UUIDs
+const (
+	CharacteristicWeightMeasurement = "2a9d"
+)
+
+// Weight Measurement flags (0x2A9D, uint8)
+const (
+	WeightImperial         = 1 << 0 // Weight in lb and height in inches (kg and meters if clear)
+	WeightTimestampPresent = 1 << 1 // Time Stamp field present
+	WeightUserIDPresent    = 1 << 2 // User ID field present
+	WeightBMIPresent       = 1 << 3 // BMI and Height fields present
+)
+
+// Unit conversions of imperial Weight Measurement values
+const (
+	kilogramsPerPound = 0.45359237
+	metersPerInch     = 0.0254
+)
+
+// WeightUserUnknown is the User ID reported for a measurement not assigned to a known user
+const WeightUserUnknown = 0xFF
+
+func init() {
+	characteristicParsers[CharacteristicWeightMeasurement] = parseWeightMeasurement
+}
+
+// WeightMeasurement represents the Weight Measurement characteristic (0x2A9D).
+// Weight and height are converted to kilograms and meters whatever unit the scale reports;
+// optional fields are nil when their flag is clear.
+type WeightMeasurement struct {
+	Flags     uint8
+	Weight    *float64  // Kilograms; nil when the scale reports an unsuccessful measurement (0xFFFF)
+	Timestamp *DateTime // Time of the measurement
+	UserID    *uint8    // User index, WeightUserUnknown for an unknown user
+	BMI       *float64  // Body mass index in kg/m² (resolution 0.1)
+	Height    *float64  // Meters
+}
+
+// Imperial reports whether the scale sent the values in lb and inches
+func (wm *WeightMeasurement) Imperial() bool { return wm.Flags&WeightImperial != 0 }
+
+// weightMeasurementLength returns the value length implied by the flags
+func weightMeasurementLength(flags uint8) int {
+	n := 3 // Flags, Weight
+	if flags&WeightTimestampPresent != 0 {
+		n += 7 // Date Time
+	}
+	if flags&WeightUserIDPresent != 0 {
+		n++ // uint8
+	}
+	if flags&WeightBMIPresent != 0 {
+		n += 4 // BMI and Height, uint16 each
+	}
+	return n
+}
+
+// parseWeightMeasurement parses the Weight Measurement characteristic (0x2A9D) value.
+// Format: Flags (uint8), Weight (uint16: 0.005 kg, or 0.01 lb with WeightImperial), then the fields whose flags are
+// set, in order: Time Stamp (Date Time), User ID (uint8), BMI (uint16, 0.1 kg/m²) and Height (uint16: 0.001 m,
+// or 0.1 inch with WeightImperial). All little-endian.
+func parseWeightMeasurement(value []byte) (interface{}, error) {
+	if len(value) < 3 {
+		return nil, fmt.Errorf("weight measurement value must be at least 3 bytes, got %d", len(value))
+	}
+	flags := value[0]
+	if expected := weightMeasurementLength(flags); len(value) != expected {
+		return nil, fmt.Errorf("weight measurement value must be %d bytes for flags 0x%02X, got %d", expected, flags, len(value))
+	}
+
+	weightResolution, heightResolution := 0.005, 0.001
+	if flags&WeightImperial != 0 {
+		weightResolution, heightResolution = 0.01*kilogramsPerPound, 0.1*metersPerInch
+	}
+	scaled := func(raw uint16, resolution float64) *float64 {
+		v := float64(raw) * resolution
+		return &v
+	}
+
+	wm := &WeightMeasurement{Flags: flags}
+	if weight, _ := readUint16LE(value, 1); weight != 0xFFFF {
+		wm.Weight = scaled(weight, weightResolution)
+	}
+	offset := 3
+	if flags&WeightTimestampPresent != 0 {
+		wm.Timestamp, _ = decodeDateTime(value[offset : offset+7])
+		offset += 7
+	}
+	if flags&WeightUserIDPresent != 0 {
+		userID := value[offset]
+		wm.UserID = &userID
+		offset++
+	}
+	if flags&WeightBMIPresent != 0 {
+		bmi, _ := readUint16LE(value, offset)
+		height, _ := readUint16LE(value, offset+2)
+		wm.BMI = scaled(bmi, 0.1)
+		wm.Height = scaled(height, heightResolution)
+	}
+
+	return wm, nil
+}
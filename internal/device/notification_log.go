@@ -0,0 +1,141 @@
+package device
+
+import (
+	"bufio"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// notificationLogMagic identifies a recorded notification log (format version 1)
+var notificationLogMagic = []byte("BLIMREC\x01")
+
+// MaxNotificationLogData is the largest payload a log entry may carry: the ATT maximum attribute value length.
+// Readers reject larger length fields instead of trusting them, so a corrupt log can't force a huge allocation.
+const MaxNotificationLogData = 512
+
+// NotificationLogEntry is a single recorded characteristic notification
+type NotificationLogEntry struct {
+	TsUs           int64  // Notification timestamp (Unix microseconds)
+	Service        string // Normalized service UUID
+	Characteristic string // Normalized characteristic UUID
+	Data           []byte // Notification payload
+}
+
+// NotificationLogWriter writes notifications to a timestamped binary log.
+//
+// Format: magic header, followed by entries of
+// TsUs (int64), service length (uint8) + service, characteristic length (uint8) + characteristic,
+// data length (uint32, at most MaxNotificationLogData) + data. All integers are little-endian.
+type NotificationLogWriter struct {
+	w *bufio.Writer
+}
+
+// NewNotificationLogWriter creates a log writer and writes the log header
+func NewNotificationLogWriter(w io.Writer) (*NotificationLogWriter, error) {
+	bw := bufio.NewWriter(w)
+	if _, err := bw.Write(notificationLogMagic); err != nil {
+		return nil, fmt.Errorf("failed to write notification log header: %w", err)
+	}
+	return &NotificationLogWriter{w: bw}, nil
+}
+
+// Write appends a notification entry to the log
+func (lw *NotificationLogWriter) Write(entry *NotificationLogEntry) error {
+	if len(entry.Service) > 0xFF || len(entry.Characteristic) > 0xFF {
+		return fmt.Errorf("UUID too long for notification log: %s/%s", entry.Service, entry.Characteristic)
+	}
+	if len(entry.Data) > MaxNotificationLogData {
+		return fmt.Errorf("notification payload of %d bytes exceeds the %d-byte log limit", len(entry.Data), MaxNotificationLogData)
+	}
+
+	var hdr [8]byte
+	binary.LittleEndian.PutUint64(hdr[:], uint64(entry.TsUs))
+	if _, err := lw.w.Write(hdr[:]); err != nil {
+		return fmt.Errorf("failed to write notification log entry: %w", err)
+	}
+	for _, s := range []string{entry.Service, entry.Characteristic} {
+		if err := lw.w.WriteByte(byte(len(s))); err != nil {
+			return fmt.Errorf("failed to write notification log entry: %w", err)
+		}
+		if _, err := lw.w.WriteString(s); err != nil {
+			return fmt.Errorf("failed to write notification log entry: %w", err)
+		}
+	}
+
+	var dataLen [4]byte
+	binary.LittleEndian.PutUint32(dataLen[:], uint32(len(entry.Data)))
+	if _, err := lw.w.Write(dataLen[:]); err != nil {
+		return fmt.Errorf("failed to write notification log entry: %w", err)
+	}
+	if _, err := lw.w.Write(entry.Data); err != nil {
+		return fmt.Errorf("failed to write notification log entry: %w", err)
+	}
+	return nil
+}
+
+// Flush writes any buffered entries to the underlying writer
+func (lw *NotificationLogWriter) Flush() error {
+	return lw.w.Flush()
+}
+
+// NotificationLogReader reads entries from a binary notification log
+type NotificationLogReader struct {
+	r *bufio.Reader
+}
+
+// NewNotificationLogReader creates a log reader and validates the log header
+func NewNotificationLogReader(r io.Reader) (*NotificationLogReader, error) {
+	br := bufio.NewReader(r)
+	magic := make([]byte, len(notificationLogMagic))
+	if _, err := io.ReadFull(br, magic); err != nil {
+		return nil, fmt.Errorf("failed to read notification log header: %w", err)
+	}
+	if string(magic) != string(notificationLogMagic) {
+		return nil, fmt.Errorf("not a notification log (invalid header)")
+	}
+	return &NotificationLogReader{r: br}, nil
+}
+
+// Next returns the next entry in the log, or io.EOF when the log is exhausted
+func (lr *NotificationLogReader) Next() (*NotificationLogEntry, error) {
+	var hdr [8]byte
+	if _, err := io.ReadFull(lr.r, hdr[:]); err != nil {
+		if errors.Is(err, io.EOF) {
+			return nil, io.EOF
+		}
+		return nil, fmt.Errorf("truncated notification log entry: %w", err)
+	}
+
+	entry := &NotificationLogEntry{TsUs: int64(binary.LittleEndian.Uint64(hdr[:]))}
+
+	uuids := make([]string, 2)
+	for i := range uuids {
+		n, err := lr.r.ReadByte()
+		if err != nil {
+			return nil, fmt.Errorf("truncated notification log entry: %w", err)
+		}
+		buf := make([]byte, n)
+		if _, err := io.ReadFull(lr.r, buf); err != nil {
+			return nil, fmt.Errorf("truncated notification log entry: %w", err)
+		}
+		uuids[i] = string(buf)
+	}
+	entry.Service, entry.Characteristic = uuids[0], uuids[1]
+
+	var dataLen [4]byte
+	if _, err := io.ReadFull(lr.r, dataLen[:]); err != nil {
+		return nil, fmt.Errorf("truncated notification log entry: %w", err)
+	}
+	n := binary.LittleEndian.Uint32(dataLen[:])
+	if n > MaxNotificationLogData {
+		return nil, fmt.Errorf("corrupt notification log entry: payload length %d exceeds %d bytes", n, MaxNotificationLogData)
+	}
+	entry.Data = make([]byte, n)
+	if _, err := io.ReadFull(lr.r, entry.Data); err != nil {
+		return nil, fmt.Errorf("truncated notification log entry: %w", err)
+	}
+
+	return entry, nil
+}
@@ -0,0 +1,101 @@
+package device
+
+import (
+	"bytes"
+	"encoding/binary"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// ----------------------------
+// Notification Log Tests
+// ----------------------------
+
+func TestNotificationLog_RoundTrip(t *testing.T) {
+	// GOAL: Verify notifications written to a log are read back unchanged and in order
+	//
+	// TEST SCENARIO: Write entries (incl. empty payload) → read back → entries match → io.EOF at end
+
+	entries := []*NotificationLogEntry{
+		{TsUs: 1000, Service: "180d", Characteristic: "2a37", Data: []byte{0x00, 0x4B}},
+		{TsUs: 1500, Service: "180d", Characteristic: "2a37", Data: []byte{}},
+		{TsUs: 2500, Service: "6e400001b5a3f393e0a9e50e24dcca9e", Characteristic: "6e400003b5a3f393e0a9e50e24dcca9e", Data: []byte("hello")},
+	}
+
+	var buf bytes.Buffer
+	writer, err := NewNotificationLogWriter(&buf)
+	require.NoError(t, err)
+	for _, entry := range entries {
+		require.NoError(t, writer.Write(entry))
+	}
+	require.NoError(t, writer.Flush())
+
+	reader, err := NewNotificationLogReader(&buf)
+	require.NoError(t, err)
+	for i, expected := range entries {
+		entry, err := reader.Next()
+		require.NoError(t, err, "entry %d MUST be readable", i)
+		assert.Equal(t, expected.TsUs, entry.TsUs)
+		assert.Equal(t, expected.Service, entry.Service)
+		assert.Equal(t, expected.Characteristic, entry.Characteristic)
+		assert.Equal(t, expected.Data, entry.Data)
+	}
+
+	_, err = reader.Next()
+	assert.ErrorIs(t, err, io.EOF, "exhausted log MUST return io.EOF")
+}
+
+func TestNotificationLog_InvalidInput(t *testing.T) {
+	// GOAL: Verify malformed logs are rejected with descriptive errors
+	//
+	// TEST SCENARIO: Invalid header → reader creation fails; truncated entry → Next() fails without io.EOF;
+	// oversized payload length → Next() fails before allocating; oversized payload → Write() fails
+
+	t.Run("invalid header", func(t *testing.T) {
+		_, err := NewNotificationLogReader(bytes.NewReader([]byte("NOTALOG!")))
+		assert.ErrorContains(t, err, "invalid header")
+	})
+
+	t.Run("truncated entry", func(t *testing.T) {
+		var buf bytes.Buffer
+		writer, err := NewNotificationLogWriter(&buf)
+		require.NoError(t, err)
+		require.NoError(t, writer.Write(&NotificationLogEntry{TsUs: 1, Service: "180d", Characteristic: "2a37", Data: []byte{1, 2, 3}}))
+		require.NoError(t, writer.Flush())
+
+		reader, err := NewNotificationLogReader(bytes.NewReader(buf.Bytes()[:buf.Len()-1]))
+		require.NoError(t, err)
+		_, err = reader.Next()
+		assert.ErrorContains(t, err, "truncated notification log entry")
+		assert.NotErrorIs(t, err, io.EOF, "truncation MUST NOT look like a clean end of log")
+	})
+
+	t.Run("oversized payload length", func(t *testing.T) {
+		var buf bytes.Buffer
+		writer, err := NewNotificationLogWriter(&buf)
+		require.NoError(t, err)
+		require.NoError(t, writer.Write(&NotificationLogEntry{TsUs: 1, Service: "180d", Characteristic: "2a37", Data: []byte{1}}))
+		require.NoError(t, writer.Flush())
+
+		// Corrupt the data length (last 4 bytes before the 1-byte payload) to 4 GiB - 1
+		log := buf.Bytes()
+		binary.LittleEndian.PutUint32(log[len(log)-5:], 0xFFFFFFFF)
+
+		reader, err := NewNotificationLogReader(bytes.NewReader(log))
+		require.NoError(t, err)
+		_, err = reader.Next()
+		assert.ErrorContains(t, err, "payload length 4294967295 exceeds 512 bytes")
+	})
+
+	t.Run("oversized payload", func(t *testing.T) {
+		writer, err := NewNotificationLogWriter(io.Discard)
+		require.NoError(t, err)
+		err = writer.Write(&NotificationLogEntry{TsUs: 1, Service: "180d", Characteristic: "2a37", Data: make([]byte, MaxNotificationLogData+1)})
+		assert.ErrorContains(t, err, "exceeds the 512-byte log limit")
+		assert.NoError(t, writer.Write(&NotificationLogEntry{TsUs: 1, Service: "180d", Characteristic: "2a37", Data: make([]byte, MaxNotificationLogData)}),
+			"payload of the ATT maximum MUST be accepted")
+	})
+}
@@ -0,0 +1,30 @@
+package device
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// GOAL: Verify Record keeps the insertion order of its keys and falls back to sorted order for keys added directly
+//
+// TEST SCENARIO: SetValue/AppendBatchValues in non-sorted order → Keys follows insertion; overwrite keeps position; map-only keys appended sorted
+func TestRecordKeys(t *testing.T) {
+	r := &Record{}
+	r.SetValue("2a38", []byte{1})
+	r.SetValue("2a37", []byte{2})
+	r.SetValue("2a38", []byte{3})
+	assert.Equal(t, []string{"2a38", "2a37"}, r.Keys(), "keys MUST follow first insertion")
+	assert.Equal(t, []byte{3}, r.Values["2a38"], "SetValue MUST overwrite the value")
+
+	b := &Record{}
+	b.AppendBatchValues("ffe1", []byte{1})
+	b.AppendBatchValues("2a19", []byte{2}, []byte{3})
+	b.AppendBatchValues("ffe1", []byte{4})
+	assert.Equal(t, []string{"ffe1", "2a19"}, b.Keys(), "batch keys MUST follow first insertion")
+	assert.Len(t, b.BatchValues["ffe1"], 2, "AppendBatchValues MUST append")
+
+	m := &Record{Values: map[string][]byte{"c": nil, "a": nil}}
+	m.SetValue("z", nil)
+	assert.Equal(t, []string{"z", "a", "c"}, m.Keys(), "keys added without SetValue MUST follow, sorted")
+}
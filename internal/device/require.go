@@ -0,0 +1,53 @@
+package device
+
+import (
+	"fmt"
+	"strings"
+)
+
+// MissingAttributesError reports the listed services and characteristics a device lacks when all of them
+// are required (ConnectOptions.RequireAll, RequireAll of a Lua subscription)
+type MissingAttributesError struct {
+	Missing []string // "<service>" or "<characteristic> (in service <service>)", in listing order
+}
+
+func (e *MissingAttributesError) Error() string {
+	return fmt.Sprintf("required attributes missing: %s", strings.Join(e.Missing, ", "))
+}
+
+// FindMissingAttributes returns every service and characteristic listed in services that the lookups do not
+// find, in listing order. A service without listed characteristics only requires the service itself, and the
+// characteristics of a missing service are not reported separately.
+func FindMissingAttributes(services []SubscribeOptions, hasService func(service string) bool, hasCharacteristic func(service, char string) bool) []string {
+	var missing []string
+	for _, svc := range services {
+		if !hasService(svc.Service) {
+			missing = append(missing, svc.Service)
+			continue
+		}
+		for _, char := range svc.Characteristics {
+			if !hasCharacteristic(svc.Service, char) {
+				missing = append(missing, fmt.Sprintf("%s (in service %s)", char, svc.Service))
+			}
+		}
+	}
+	return missing
+}
+
+// CheckRequiredAttributes returns a *MissingAttributesError naming everything listed in services that conn
+// lacks, or nil when all of it was discovered
+func CheckRequiredAttributes(conn Connection, services []SubscribeOptions) error {
+	missing := FindMissingAttributes(services,
+		func(service string) bool {
+			_, err := conn.GetService(service)
+			return err == nil
+		},
+		func(service, char string) bool {
+			_, err := conn.GetCharacteristic(service, char)
+			return err == nil
+		})
+	if len(missing) > 0 {
+		return &MissingAttributesError{Missing: missing}
+	}
+	return nil
+}
@@ -0,0 +1,43 @@
+package device
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// GOAL: Verify every absent listed service and characteristic is reported, in listing order
+//
+// TEST SCENARIO: List a present service with one missing char, a missing service with chars, a whole present service → only the missing char and the missing service are reported
+func TestFindMissingAttributes(t *testing.T) {
+	present := map[string][]string{
+		"180d": {"2a37", "2a38"},
+		"180f": {"2a19"},
+	}
+	hasService := func(service string) bool {
+		_, ok := present[service]
+		return ok
+	}
+	hasCharacteristic := func(service, char string) bool {
+		for _, c := range present[service] {
+			if c == char {
+				return true
+			}
+		}
+		return false
+	}
+
+	missing := FindMissingAttributes([]SubscribeOptions{
+		{Service: "180d", Characteristics: []string{"2a37", "2a39"}},
+		{Service: "1816", Characteristics: []string{"2a5b"}},
+		{Service: "180f"},
+	}, hasService, hasCharacteristic)
+	assert.Equal(t, []string{"2a39 (in service 180d)", "1816"}, missing)
+
+	assert.Empty(t, FindMissingAttributes([]SubscribeOptions{{Service: "180d", Characteristics: []string{"2a38"}}}, hasService, hasCharacteristic),
+		"nothing MUST be reported when everything listed is present")
+
+	err := &MissingAttributesError{Missing: missing}
+	assert.Equal(t, "required attributes missing: 2a39 (in service 180d), 1816", err.Error())
+	assert.Equal(t, ErrorCodeNotFound, ErrorCode(err), "missing attributes MUST classify as not_found")
+}
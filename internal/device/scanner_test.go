@@ -111,6 +111,27 @@ func (suite *ScannerErrorTestSuite) TestScanner_NormalizesErrors() {
 	})
 }
 
+func (suite *ScannerErrorTestSuite) TestSetAdapter() {
+	// GOAL: Verify adapter selection accepts the single default adapter and rejects anything else
+	//
+	// TEST SCENARIO: Select the default adapter → selection succeeds → select Linux-style or unknown adapters → clear error, selection unchanged
+
+	defer func() { suite.NoError(goble.SetAdapter("")) }()
+
+	for _, id := range []string{"", "default", "DEFAULT"} {
+		suite.NoError(goble.SetAdapter(id), "adapter %q MUST be accepted", id)
+		suite.Equal(goble.DefaultAdapter, goble.Adapter(), "adapter %q MUST resolve to the default adapter", id)
+	}
+
+	for _, id := range []string{"0", "hci0", "hci7"} {
+		err := goble.SetAdapter(id)
+		suite.Error(err, "adapter %q MUST be rejected", id)
+		suite.Contains(err.Error(), fmt.Sprintf("bluetooth adapter %q not found", id), "error MUST name the missing adapter")
+		suite.Contains(err.Error(), "available: default", "error MUST list available adapters")
+		suite.Equal(goble.DefaultAdapter, goble.Adapter(), "failed selection MUST NOT change the adapter")
+	}
+}
+
 func TestScannerErrorTestSuite(t *testing.T) {
 	suite.Run(t, new(ScannerErrorTestSuite))
 }
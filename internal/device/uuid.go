@@ -10,12 +10,39 @@ import (
 // It converts a UUID string to the internal BLE library format (lowercase, no dashes).
 // Handles both standard UUID format (with dashes) and already normalized format (without dashes).
 // Also strips 0x prefix if present (e.g., "0x2902" -> "2902").
-// For full 128-bit UUIDs in Bluetooth SIG base format (0000xxxx-0000-1000-8000-00805f9b34fb),
-// extracts the 16-bit short form (xxxx).
+// UUIDs in Bluetooth SIG base format (xxxxxxxx-0000-1000-8000-00805f9b34fb) are reduced to their
+// shortest alias: the 16-bit form (xxxx) when possible, otherwise the 32-bit form (xxxxxxxx).
 func NormalizeUUID(uuid string) string {
 	return bledb.NormalizeUUID(uuid)
 }
 
+// ExpandUUID is re-exported from bledb for convenience.
+// It converts a 16-, 32- or 128-bit UUID to the full 128-bit form with dashes,
+// expanding short UUIDs with the Bluetooth SIG base UUID.
+func ExpandUUID(uuid string) string {
+	return bledb.ExpandUUID(uuid)
+}
+
+// ResolveServiceUUID resolves a service reference to a normalized UUID.
+// The reference may be a known service name (e.g., "Heart Rate") or a UUID in any supported format;
+// strings that match no known name are treated as UUIDs.
+func ResolveServiceUUID(ref string) string {
+	if uuid := bledb.LookupServiceUUID(ref); uuid != "" {
+		return NormalizeUUID(uuid)
+	}
+	return NormalizeUUID(ref)
+}
+
+// ResolveCharacteristicUUID resolves a characteristic reference to a normalized UUID.
+// The reference may be a known characteristic name (e.g., "Heart Rate Measurement") or a UUID in any
+// supported format; strings that match no known name are treated as UUIDs.
+func ResolveCharacteristicUUID(ref string) string {
+	if uuid := bledb.LookupCharacteristicUUID(ref); uuid != "" {
+		return NormalizeUUID(uuid)
+	}
+	return NormalizeUUID(ref)
+}
+
 // NormalizeUUIDs is re-exported from bledb for convenience.
 // It normalizes a slice of UUID strings to internal format.
 func NormalizeUUIDs(uuids []string) []string {
@@ -53,3 +80,17 @@ func ValidateUUID(uuids ...string) ([]string, error) {
 	}
 	return result, nil
 }
+
+// ContainsAnyUUID reports whether any UUID in want matches a UUID in have.
+// UUIDs are normalized before comparison, so format differences (dashes, case, SIG base form) are ignored.
+func ContainsAnyUUID(have, want []string) bool {
+	for _, w := range want {
+		wantNorm := NormalizeUUID(w)
+		for _, h := range have {
+			if NormalizeUUID(h) == wantNorm {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -57,12 +57,14 @@ func TestNormalizeUUID(t *testing.T) {
 			expected: "180d",
 		},
 
-		// Custom 128-bit UUIDs (should NOT be shortened)
+		// Full Bluetooth SIG UUID with a 32-bit value (shortened to 32-bit form)
 		{
-			name:     "Custom UUID - wrong prefix",
+			name:     "Full Bluetooth SIG UUID - 32-bit value",
 			input:    "AA002902-0000-1000-8000-00805f9b34fb",
-			expected: "aa00290200001000800000805f9b34fb",
+			expected: "aa002902",
 		},
+
+		// Custom 128-bit UUIDs (should NOT be shortened)
 		{
 			name:     "Custom UUID - wrong suffix",
 			input:    "00002902-1234-5678-9abc-def012345678",
@@ -91,16 +93,21 @@ func TestNormalizeUUID(t *testing.T) {
 			expected: "2a02",
 		},
 
-		// 32-bit and other length UUIDs
+		// 32-bit UUID formats
 		{
 			name:     "32-bit UUID format",
 			input:    "12345678",
 			expected: "12345678",
 		},
 		{
-			name:     "Partial UUID",
+			name:     "32-bit UUID with 0x prefix",
+			input:    "0x1234ABCD",
+			expected: "1234abcd",
+		},
+		{
+			name:     "32-bit alias of 16-bit UUID",
 			input:    "00002902",
-			expected: "00002902",
+			expected: "2902",
 		},
 	}
 
@@ -159,21 +166,11 @@ func TestNormalizeUUID_NoShortening(t *testing.T) {
 		input  string
 		reason string
 	}{
-		{
-			name:   "Wrong prefix - AA00 instead of 0000",
-			input:  "AA002902-0000-1000-8000-00805f9b34fb",
-			reason: "prefix is not 0000",
-		},
 		{
 			name:   "Wrong suffix - custom UUID",
 			input:  "00002902-1234-5678-9abc-def012345678",
 			reason: "suffix doesn't match Bluetooth SIG base",
 		},
-		{
-			name:   "Too short",
-			input:  "00002902",
-			reason: "only 8 chars, not 32",
-		},
 		{
 			name:   "Too long",
 			input:  "0000290200001000800000805f9b34fb00",
@@ -193,3 +190,73 @@ func TestNormalizeUUID_NoShortening(t *testing.T) {
 		})
 	}
 }
+
+// GOAL: Verify 16-, 32- and 128-bit UUIDs survive an expand/normalize round trip
+//
+// TEST SCENARIO: Normalize UUID → expand to 128-bit form → normalize again → same short alias
+func TestExpandUUID_RoundTrip(t *testing.T) {
+	tests := []struct {
+		name       string
+		input      string
+		normalized string
+		expanded   string
+	}{
+		{
+			name:       "16-bit UUID",
+			input:      "0x180D",
+			normalized: "180d",
+			expanded:   "0000180d-0000-1000-8000-00805f9b34fb",
+		},
+		{
+			name:       "32-bit UUID",
+			input:      "1234ABCD",
+			normalized: "1234abcd",
+			expanded:   "1234abcd-0000-1000-8000-00805f9b34fb",
+		},
+		{
+			name:       "32-bit alias of 16-bit UUID",
+			input:      "0000180d",
+			normalized: "180d",
+			expanded:   "0000180d-0000-1000-8000-00805f9b34fb",
+		},
+		{
+			name:       "128-bit custom UUID",
+			input:      "6E400001-B5A3-F393-E0A9-E50E24DCCA9E",
+			normalized: "6e400001b5a3f393e0a9e50e24dcca9e",
+			expanded:   "6e400001-b5a3-f393-e0a9-e50e24dcca9e",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			normalized := NormalizeUUID(tt.input)
+			assert.Equal(t, tt.normalized, normalized)
+
+			expanded := ExpandUUID(normalized)
+			assert.Equal(t, tt.expanded, expanded)
+			assert.Equal(t, tt.normalized, NormalizeUUID(expanded), "round trip should yield the same alias")
+		})
+	}
+}
+
+// GOAL: Verify service/characteristic references resolve from known names, falling back to UUIDs
+//
+// TEST SCENARIO: Resolve names and UUID variants → normalized UUIDs returned → unknown strings normalized as UUIDs
+func TestResolveUUID(t *testing.T) {
+	assert.Equal(t, "180d", ResolveServiceUUID("Heart Rate"))
+	assert.Equal(t, "180d", ResolveServiceUUID("0000180D-0000-1000-8000-00805F9B34FB"))
+	assert.Equal(t, "2a37", ResolveCharacteristicUUID("heart rate measurement"))
+	assert.Equal(t, "2a37", ResolveCharacteristicUUID("0x2A37"))
+	assert.Equal(t, "6e400001b5a3f393e0a9e50e24dcca9e", ResolveCharacteristicUUID("6E400001-B5A3-F393-E0A9-E50E24DCCA9E"))
+}
+
+// GOAL: Verify UUID list matching ignores UUID format differences
+//
+// TEST SCENARIO: Compare advertised and wanted UUID lists → match on any normalized UUID → no match otherwise
+func TestContainsAnyUUID(t *testing.T) {
+	assert.True(t, ContainsAnyUUID([]string{"180F", "180D"}, []string{"0000180d-0000-1000-8000-00805f9b34fb"}))
+	assert.True(t, ContainsAnyUUID([]string{"6E400001-B5A3-F393-E0A9-E50E24DCCA9E"}, []string{"feaa", "6e400001b5a3f393e0a9e50e24dcca9e"}))
+	assert.False(t, ContainsAnyUUID([]string{"180f"}, []string{"180d"}))
+	assert.False(t, ContainsAnyUUID(nil, []string{"180d"}))
+	assert.False(t, ContainsAnyUUID([]string{"180d"}, nil))
+}
@@ -1,9 +1,17 @@
 package devicefactory
 
 import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/go-ble/ble"
 	"github.com/sirupsen/logrus"
 	"github.com/srg/blim/internal/device"
 	"github.com/srg/blim/internal/device/go-ble"
+	"github.com/srg/blim/internal/simulator"
 )
 
 // DeviceFactory creates a device.Scanner instances for BLE scanning operations.
@@ -12,12 +20,89 @@ var DeviceFactory = func() (device.Scanner, error) {
 	return goble.NewScanner()
 }
 
+// SetAdapter selects the Bluetooth adapter used for scanning and connecting.
+// An empty identifier selects the system default adapter.
+// Returns an error if the named adapter doesn't exist.
+func SetAdapter(id string) error {
+	return goble.SetAdapter(id)
+}
+
+// Simulate replaces the BLE hardware with a simulated peripheral loaded from a JSON device description
+// (see simulator.Profile). Every connection, regardless of address, is made to the simulated device;
+// scans find no devices.
+func Simulate(path string) error {
+	profile, err := simulator.LoadProfile(path)
+	if err != nil {
+		return err
+	}
+	goble.DeviceFactory = func() (ble.Device, error) {
+		return simulator.NewDevice(profile)
+	}
+	return nil
+}
+
 // NewDevice creates a new BLE device with the specified address.
 // This is the primary constructor for creating device instances.
 func NewDevice(address string, logger *logrus.Logger) device.Device {
 	return goble.NewBLEDeviceWithAddress(address, logger)
 }
 
+// ConnectByIdentifier connects to a peripheral the OS already knows by its identifier (the CoreBluetooth
+// peripheral UUID on macOS, the MAC address on Linux) without scanning first, which makes reconnecting to a
+// paired or recently used device fast. If the direct connection fails, e.g. because the system has not cached
+// the peripheral, it scans until the peripheral advertises (bounded by opts.ConnectTimeout) and connects again.
+func ConnectByIdentifier(ctx context.Context, identifier string, opts *device.ConnectOptions, logger *logrus.Logger) (device.Device, error) {
+	dev := NewDevice(identifier, logger)
+	err := dev.Connect(ctx, opts)
+	if err == nil {
+		return dev, nil
+	}
+	if ctx.Err() != nil || errors.Is(err, device.ErrBluetoothOff) {
+		return nil, err
+	}
+
+	logger.WithError(err).WithField("identifier", identifier).Info("Direct connection by identifier failed, scanning for the peripheral")
+	adv, scanErr := scanForIdentifier(ctx, identifier, opts.ConnectTimeout)
+	if scanErr != nil {
+		return nil, fmt.Errorf("failed to connect by identifier %s: %w (fallback scan: %v)", identifier, err, scanErr)
+	}
+
+	dev = NewDeviceFromAdvertisement(adv, logger)
+	if err := dev.Connect(ctx, opts); err != nil {
+		return nil, err
+	}
+	return dev, nil
+}
+
+// scanForIdentifier scans until an advertisement from identifier is received or timeout elapses (0 = 30s)
+func scanForIdentifier(ctx context.Context, identifier string, timeout time.Duration) (device.Advertisement, error) {
+	if timeout <= 0 {
+		timeout = 30 * time.Second
+	}
+	scanCtx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	scanner, err := DeviceFactory()
+	if err != nil {
+		return nil, err
+	}
+
+	var found device.Advertisement
+	err = scanner.Scan(scanCtx, false, func(adv device.Advertisement) {
+		if found == nil && strings.EqualFold(adv.Addr(), identifier) {
+			found = adv
+			cancel() // Stop scanning as soon as the peripheral is seen
+		}
+	})
+	if found != nil {
+		return found, nil
+	}
+	if err == nil {
+		err = fmt.Errorf("peripheral %s not found", identifier)
+	}
+	return nil, err
+}
+
 // NewDeviceFromAdvertisement creates a new BLE device from a device.Advertisement.
 // This is used during scanning to create device instances from discovered advertisements.
 func NewDeviceFromAdvertisement(adv device.Advertisement, logger *logrus.Logger) device.Device {
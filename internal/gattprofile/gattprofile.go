@@ -0,0 +1,152 @@
+// Package gattprofile builds a go-ble GATT profile from a JSON/YAML peripheral description.
+// It is shared by the simulated device (--simulate) and the mock peripheral builder of the test suites,
+// so both accept the same description format.
+package gattprofile
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+
+	"github.com/go-ble/ble"
+)
+
+// DeviceProfileConfig describes the services of a peripheral:
+//
+//	{
+//	  "services": [
+//	    {"uuid": "180D", "characteristics": [
+//	      {"uuid": "2A37", "properties": "read,notify", "value": [0, 72],
+//	       "descriptors": [{"uuid": "2901", "value": [72, 82]}]}
+//	    ]}
+//	  ]
+//	}
+type DeviceProfileConfig struct {
+	Services []ServiceConfig `json:"services"`
+}
+
+// ServiceConfig describes a service
+type ServiceConfig struct {
+	UUID            string                 `json:"uuid"`
+	Characteristics []CharacteristicConfig `json:"characteristics,omitempty"`
+}
+
+// CharacteristicConfig describes a characteristic
+type CharacteristicConfig struct {
+	UUID        string             `json:"uuid"`
+	Properties  string             `json:"properties,omitempty"` // e.g., "read,write,notify" (default: read,write,notify)
+	Value       []byte             `json:"value,omitempty"`
+	Descriptors []DescriptorConfig `json:"descriptors,omitempty"`
+}
+
+// DescriptorConfig describes a descriptor
+type DescriptorConfig struct {
+	UUID  string `json:"uuid"`
+	Value []byte `json:"value,omitempty"`
+}
+
+// Build converts the description to a ble.Profile with sequential ATT handles:
+// a service takes one handle, a characteristic two (declaration and value), a descriptor one.
+// Values are copied, so the profile can be modified without affecting the description.
+func Build(config DeviceProfileConfig) (*ble.Profile, error) {
+	handle := uint16(0x0001)
+	profile := &ble.Profile{}
+
+	for _, svcConfig := range config.Services {
+		svcUUID, err := ble.Parse(svcConfig.UUID)
+		if err != nil {
+			return nil, fmt.Errorf("invalid service UUID %q: %w", svcConfig.UUID, err)
+		}
+		svc := &ble.Service{UUID: svcUUID, Handle: handle}
+		handle++
+
+		for _, charConfig := range svcConfig.Characteristics {
+			charUUID, err := ble.Parse(charConfig.UUID)
+			if err != nil {
+				return nil, fmt.Errorf("invalid characteristic UUID %q in service %s: %w", charConfig.UUID, svcConfig.UUID, err)
+			}
+			props, err := ParseProperties(charConfig.Properties)
+			if err != nil {
+				return nil, fmt.Errorf("characteristic %s: %w", charConfig.UUID, err)
+			}
+			char := &ble.Characteristic{
+				UUID:        charUUID,
+				Property:    props,
+				Value:       bytes.Clone(charConfig.Value),
+				Handle:      handle,
+				ValueHandle: handle + 1,
+			}
+			handle += 2
+
+			for _, descConfig := range charConfig.Descriptors {
+				descUUID, err := ble.Parse(descConfig.UUID)
+				if err != nil {
+					return nil, fmt.Errorf("invalid descriptor UUID %q in characteristic %s: %w", descConfig.UUID, charConfig.UUID, err)
+				}
+				char.Descriptors = append(char.Descriptors, &ble.Descriptor{
+					UUID:   descUUID,
+					Value:  bytes.Clone(descConfig.Value),
+					Handle: handle,
+				})
+				handle++
+			}
+			char.EndHandle = handle - 1
+			svc.Characteristics = append(svc.Characteristics, char)
+		}
+		svc.EndHandle = handle - 1
+		profile.Services = append(profile.Services, svc)
+	}
+	return profile, nil
+}
+
+// FindCharacteristic looks up a characteristic by service and characteristic UUID (nil if not found)
+func FindCharacteristic(profile *ble.Profile, service, char string) *ble.Characteristic {
+	svcUUID, err := ble.Parse(service)
+	if err != nil {
+		return nil
+	}
+	charUUID, err := ble.Parse(char)
+	if err != nil {
+		return nil
+	}
+	for _, svc := range profile.Services {
+		if !svc.UUID.Equal(svcUUID) {
+			continue
+		}
+		for _, c := range svc.Characteristics {
+			if c.UUID.Equal(charUUID) {
+				return c
+			}
+		}
+	}
+	return nil
+}
+
+// ParseProperties converts a comma-separated property list to ble.Property flags.
+// An empty list means read,write,notify.
+func ParseProperties(props string) (ble.Property, error) {
+	if strings.TrimSpace(props) == "" {
+		return ble.CharRead | ble.CharWrite | ble.CharNotify, nil
+	}
+
+	var property ble.Property
+	for _, part := range strings.Split(props, ",") {
+		switch strings.TrimSpace(part) {
+		case "read":
+			property |= ble.CharRead
+		case "write":
+			property |= ble.CharWrite
+		case "write-without-response":
+			property |= ble.CharWriteNR
+		case "notify":
+			property |= ble.CharNotify
+		case "indicate":
+			property |= ble.CharIndicate
+		case "authenticated_signed_writes":
+			property |= ble.CharSignedWrite
+		default:
+			return 0, fmt.Errorf("unknown property %q in %q", strings.TrimSpace(part), props)
+		}
+	}
+	return property, nil
+}
@@ -0,0 +1,89 @@
+package gattprofile
+
+import (
+	"testing"
+
+	"github.com/go-ble/ble"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// GOAL: Verify the description is converted to a profile with unique sequential ATT handles and copied values
+//
+// TEST SCENARIO: Two services, characteristic with descriptors → handles follow service → characteristic → descriptors → values are copies
+func TestBuild(t *testing.T) {
+	config := DeviceProfileConfig{Services: []ServiceConfig{
+		{UUID: "180D", Characteristics: []CharacteristicConfig{
+			{UUID: "2A37", Properties: "notify", Value: []byte{0, 60}, Descriptors: []DescriptorConfig{
+				{UUID: "2902", Value: []byte{0, 0}},
+				{UUID: "2901", Value: []byte("HR")},
+			}},
+		}},
+		{UUID: "180F", Characteristics: []CharacteristicConfig{{UUID: "2A19"}}},
+	}}
+
+	profile, err := Build(config)
+	require.NoError(t, err)
+	require.Len(t, profile.Services, 2)
+
+	hr := profile.Services[0]
+	assert.Equal(t, uint16(0x0001), hr.Handle)
+	assert.Equal(t, uint16(0x0005), hr.EndHandle)
+	char := hr.Characteristics[0]
+	assert.Equal(t, uint16(0x0002), char.Handle)
+	assert.Equal(t, uint16(0x0003), char.ValueHandle)
+	assert.Equal(t, uint16(0x0005), char.EndHandle)
+	assert.Equal(t, ble.CharNotify, char.Property)
+	require.Len(t, char.Descriptors, 2)
+	assert.Equal(t, uint16(0x0004), char.Descriptors[0].Handle)
+	assert.Equal(t, uint16(0x0005), char.Descriptors[1].Handle)
+
+	battery := profile.Services[1]
+	assert.Equal(t, uint16(0x0006), battery.Handle)
+	assert.Equal(t, ble.CharRead|ble.CharWrite|ble.CharNotify, battery.Characteristics[0].Property, "empty properties MUST default to read,write,notify")
+
+	char.Value[1] = 72
+	assert.Equal(t, byte(60), config.Services[0].Characteristics[0].Value[1], "profile values MUST NOT alias the description")
+}
+
+// GOAL: Verify invalid descriptions are rejected with an error naming the problem
+//
+// TEST SCENARIO: Invalid UUIDs and unknown property → error returned → message names the offending value
+func TestBuild_Invalid(t *testing.T) {
+	tests := []struct {
+		name    string
+		config  DeviceProfileConfig
+		wantErr string
+	}{
+		{
+			name:    "invalid service UUID",
+			config:  DeviceProfileConfig{Services: []ServiceConfig{{UUID: "xyz"}}},
+			wantErr: `invalid service UUID "xyz"`,
+		},
+		{
+			name:    "invalid characteristic UUID",
+			config:  DeviceProfileConfig{Services: []ServiceConfig{{UUID: "180D", Characteristics: []CharacteristicConfig{{UUID: "12"}}}}},
+			wantErr: `invalid characteristic UUID "12" in service 180D`,
+		},
+		{
+			name: "invalid descriptor UUID",
+			config: DeviceProfileConfig{Services: []ServiceConfig{{UUID: "180D", Characteristics: []CharacteristicConfig{
+				{UUID: "2A37", Descriptors: []DescriptorConfig{{UUID: "zz"}}},
+			}}}},
+			wantErr: `invalid descriptor UUID "zz" in characteristic 2A37`,
+		},
+		{
+			name:    "unknown property",
+			config:  DeviceProfileConfig{Services: []ServiceConfig{{UUID: "180D", Characteristics: []CharacteristicConfig{{UUID: "2A37", Properties: "read, teleport"}}}}},
+			wantErr: `unknown property "teleport"`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Build(tt.config)
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.wantErr)
+		})
+	}
+}
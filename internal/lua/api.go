@@ -1,12 +1,16 @@
 package lua
 
 import (
+	"bytes"
 	"context"
 	"errors"
 	"fmt"
 	"io"
+	"runtime"
 	"runtime/debug"
 	"strings"
+	"sync"
+	"sync/atomic"
 	"syscall"
 	"time"
 
@@ -14,6 +18,8 @@ import (
 	"github.com/sirupsen/logrus"
 	blim "github.com/srg/blim"
 	"github.com/srg/blim/internal/device"
+	"github.com/srg/blim/internal/devicefactory"
+	"github.com/srg/blim/internal/groutine"
 )
 
 const (
@@ -23,6 +29,10 @@ const (
 	DefaultCharacteristicWriteTimeout = 5 * time.Second
 	// DefaultDescriptorReadTimeout is the default timeout for descriptor read operations
 	DefaultDescriptorReadTimeout = 2 * time.Second
+	// DefaultPairingTimeout is the maximum time blim.pair() waits for pairing to complete (includes user confirmation)
+	DefaultPairingTimeout = 30 * time.Second
+	// DefaultScanDuration is the default duration of blim.scan()
+	DefaultScanDuration = 5 * time.Second
 )
 
 // BridgeInfo bridge information exposed to Lua
@@ -35,10 +45,36 @@ type BridgeInfo interface {
 
 // LuaSubscriptionTable Lua subscription configuration
 type LuaSubscriptionTable struct {
-	Services    []device.SubscribeOptions `json:"services"`
-	Mode        string                    `json:"mode"`
-	MaxRate     int                       `json:"max_rate"`
-	CallbackRef int                       `json:"-"` // Lua function reference
+	Services        []device.SubscribeOptions `json:"services"`
+	Mode            string                    `json:"mode"`
+	MaxRate         int                       `json:"max_rate"`
+	AggregateWindow int                       `json:"aggregate_window"` // Fixed aggregation window in ms (0 = MaxRate ticks)
+	EmitEmpty       bool                      `json:"emit_empty"`       // Deliver empty aggregation windows
+	Buffer          int                       `json:"buffer"`           // Notification queue depth per characteristic (0 = default)
+	Duration        int                       `json:"duration"`
+	Parsed          bool                      `json:"parsed"`
+	ReadInitial     bool                      `json:"read_initial"` // Read subscribed characteristics once and deliver them as an initial record
+	RequireAll      bool                      `json:"require_all"`  // Fail with every missing service/characteristic named instead of the first validation error
+	Reassemble      *LuaReassembleOptions     `json:"reassemble,omitempty"`
+	CallbackRef     int                       `json:"-"` // Lua function reference
+	FilterRef       int                       `json:"-"` // Optional Lua filter predicate reference (0 if none)
+	KeyFnRef        int                       `json:"-"` // Optional Lua record key function reference (0 if none)
+}
+
+// unref releases the registry references taken by parseSubscriptionTable, for a subscription that was not started
+func (config *LuaSubscriptionTable) unref(L *lua.State) {
+	for _, ref := range []*int{&config.FilterRef, &config.KeyFnRef, &config.CallbackRef} {
+		if *ref != 0 {
+			L.Unref(lua.LUA_REGISTRYINDEX, *ref)
+			*ref = 0
+		}
+	}
+}
+
+// LuaReassembleOptions configures joining notification fragments into terminator-delimited messages
+type LuaReassembleOptions struct {
+	Terminator []byte `json:"terminator"`
+	MaxSize    int    `json:"max_size"` // Max buffered bytes per characteristic without a terminator (0 = DefaultReassembleMaxSize)
 }
 
 // LuaAPI represents the new BLE API that supports Lua subscriptions
@@ -50,8 +86,30 @@ type LuaAPI struct {
 	bridge                     BridgeInfo    // Optional bridge information
 	characteristicReadTimeout  time.Duration // Default timeout for characteristic read operations
 	characteristicWriteTimeout time.Duration // Default timeout for characteristic write operations
+	descriptorReadTimeout      time.Duration // Default timeout for descriptor read operations
+	timeoutsMutex              sync.RWMutex  // Guards timeouts changed at runtime via blim.set_timeouts()
+
+	simulatedNotifications atomic.Bool // Enables blim.simulate_notification() (see SetSimulatedNotifications)
+
+	connectionEventMutex   sync.Mutex        // Guards the connection event callback state below
+	connectionEventRef     int               // Lua callback registered via blim.on_connection_event() (LUA_NOREF if none)
+	connectionEventsHooked device.Connection // Connection the event handlers are registered on (nil if none)
+
+	rawNotificationMutex  sync.Mutex        // Guards the raw notification callback state below
+	rawNotificationRef    int               // Lua callback registered via blim.on_raw_notification() (LUA_NOREF if none)
+	rawNotificationHooked device.Connection // Connection the raw notification handler is registered on (nil if none)
+
+	// Lua parsers set via blim.set_parser_override(), keyed by parserOverrideKey. Only accessed while holding the Lua state.
+	parserOverrides map[string]int
 }
 
+// Connection event types passed to the blim.on_connection_event() callback
+const (
+	ConnectionEventServicesChanged = "services_changed" // Peripheral indicated Service Changed (0x2A05); handles may be stale
+	ConnectionEventDisconnected    = "disconnected"     // Connection closed itself; event.reason tells why (e.g., "idle")
+	ConnectionEventRSSI            = "rssi"             // Polled RSSI reading (see blim.set_rssi_interval); event.rssi in dBm
+)
+
 // NewBLEAPI2 creates a new BLE API instance with subscription support
 func NewBLEAPI2(device device.Device, logger *logrus.Logger) *LuaAPI {
 	r := &LuaAPI{
@@ -60,6 +118,7 @@ func NewBLEAPI2(device device.Device, logger *logrus.Logger) *LuaAPI {
 		LuaEngine:                  NewLuaEngine(logger),
 		characteristicReadTimeout:  DefaultCharacteristicReadTimeout,
 		characteristicWriteTimeout: DefaultCharacteristicWriteTimeout,
+		descriptorReadTimeout:      DefaultDescriptorReadTimeout,
 	}
 
 	r.Reset()
@@ -70,6 +129,13 @@ func (api *LuaAPI) GetDevice() device.Device {
 	return api.device
 }
 
+// timeouts returns the current read, write, and descriptor read timeouts
+func (api *LuaAPI) timeouts() (read, write, descriptor time.Duration) {
+	api.timeoutsMutex.RLock()
+	defer api.timeoutsMutex.RUnlock()
+	return api.characteristicReadTimeout, api.characteristicWriteTimeout, api.descriptorReadTimeout
+}
+
 // SetBridge sets the bridge information and updates the PTY strategy.
 // When a bridge is set, the ptyio field is updated to use the bridge's PTY I/O strategy.
 // When the bridge is nil, the ptyio field reverts to NilPTYIO.
@@ -81,6 +147,18 @@ func (api *LuaAPI) SetBridge(bridge BridgeInfo) {
 	api.bridge = bridge
 }
 
+// SetSimulatedNotifications enables blim.simulate_notification(), which injects synthetic notifications
+// into the connection so scripts can exercise their callbacks without the peripheral sending data.
+// Disabled by default: on a real connection the function then does nothing.
+func (api *LuaAPI) SetSimulatedNotifications(enabled bool) {
+	api.simulatedNotifications.Store(enabled)
+}
+
+// notificationSimulator is implemented by connections able to inject synthetic notifications
+type notificationSimulator interface {
+	SimulateNotification(service, uuid string, data []byte) error
+}
+
 // registerBridgeInfo registers the blim.bridge table with runtime bridge checking
 // This is called internally during API registration within the DoWithState block
 // Stack: expects _blim_internal table at top (-1)
@@ -307,6 +385,17 @@ func (api *LuaAPI) LoadScript(script, name string) error {
 
 func (api *LuaAPI) Reset() {
 	api.LuaEngine.Reset()
+
+	// Registry references do not survive a state reset. Handlers already hooked on the connection stay
+	// registered and find no callback until a new one is set.
+	api.connectionEventMutex.Lock()
+	api.connectionEventRef = lua.LUA_NOREF
+	api.connectionEventMutex.Unlock()
+	api.rawNotificationMutex.Lock()
+	api.rawNotificationRef = lua.LUA_NOREF
+	api.rawNotificationMutex.Unlock()
+	api.parserOverrides = make(map[string]int)
+
 	api.registerBlimAPI() // Register _blim_internal for Lua wrapper
 }
 
@@ -321,14 +410,40 @@ func (api *LuaAPI) registerBlimAPI() {
 		// Create _blim_internal table
 		L.NewTable()
 
+		// Metatable for structured error tables returned by API functions
+		if err := registerErrorMetatable(L); err != nil {
+			api.logger.WithError(err).Error("Failed to register Lua error metatable")
+		}
+
 		// Register API functions (same as ble)
 		api.registerSubscribeFunction(L)
 		api.registerListFunction(L)
+		api.registerAllCharacteristicsFunction(L)
 		api.registerDeviceInfo(L)
 		api.registerCharacteristicFunction(L)
+		api.registerDeviceInfoFunction(L)
+		api.registerReadServiceFunction(L)
+		api.registerWriteMultipleFunction(L)
+		api.registerPairFunction(L)
+		api.registerTimeoutsFunctions(L)
+		api.registerConnectionEventFunction(L)
+		api.registerRawNotificationFunction(L)
+		api.registerParserOverrideFunction(L)
+		api.registerSnapshotSubscriptionsFunction(L)
+		api.registerEnabledNotificationsFunction(L)
+		api.registerSubscriptionStatsFunction(L)
+		api.registerFlushWritesFunction(L)
+		api.registerPropertyConstants(L)
+		api.registerIdleTimeoutFunction(L)
+		api.registerRSSIFunctions(L)
+		api.registerEstimateDistanceFunction(L)
+		api.registerSimulateNotificationFunction(L)
+		api.registerScanFunction(L)
+		api.registerLogLevelFunctions(L)
 
 		// Register utility functions
 		api.registerSleepFunction(L)
+		api.registerYieldFunction(L)
 
 		// Register bridge info if set
 		api.registerBridgeInfo(L)
@@ -365,6 +480,7 @@ func (api *LuaAPI) registerSubscribeFunction(L *lua.State) {
 		// Execute the subscription
 		err = api.executeSubscription(config)
 		if err != nil {
+			config.unref(L)
 			L.RaiseError("Error executing subscription: " + err.Error())
 			return 0
 		}
@@ -402,6 +518,38 @@ func (api *LuaAPI) registerSubscribeFunction(L *lua.State) {
 //  2. UUID-based lookup: services[uuid] to get service info
 func (api *LuaAPI) registerListFunction(L *lua.State) {
 	api.SafePushGoFunction(L, "list", func(L *lua.State) int {
+		// Optional filter: list{properties={"notify", ...}}
+		var requiredProps []string
+		if !L.IsNoneOrNil(1) {
+			if !L.IsTable(1) {
+				L.RaiseError("list([filter]) expects a table as filter")
+				return 0
+			}
+			L.GetField(1, "properties")
+			if !L.IsNil(-1) {
+				if !L.IsTable(-1) {
+					L.RaiseError("list([filter]) expects an array of property names as properties")
+					return 0
+				}
+				for i := 1; ; i++ {
+					L.RawGeti(-1, i)
+					if L.IsNil(-1) {
+						L.Pop(1)
+						break
+					}
+					name := strings.ToLower(L.ToString(-1))
+					if _, ok := characteristicPropertyAccessors[name]; !L.IsString(-1) || !ok {
+						L.RaiseError(fmt.Sprintf("list([filter]) unknown property %q (expected one of: %s)",
+							L.ToString(-1), strings.Join(characteristicPropertyNames, ", ")))
+						return 0
+					}
+					requiredProps = append(requiredProps, name)
+					L.Pop(1)
+				}
+			}
+			L.Pop(1)
+		}
+
 		// Get connection when function is called, not when registered
 		connection := api.device.GetConnection()
 		if connection == nil {
@@ -416,6 +564,17 @@ func (api *LuaAPI) registerListFunction(L *lua.State) {
 		for _, service := range services {
 			uuid := service.UUID()
 
+			var chars []device.Characteristic
+			for _, c := range service.GetCharacteristics() {
+				if hasProperties(c.GetProperties(), requiredProps) {
+					chars = append(chars, c)
+				}
+			}
+			// Prune services left without characteristics by the filter
+			if len(requiredProps) > 0 && len(chars) == 0 {
+				continue
+			}
+
 			// Create service info table
 			L.NewTable()
 
@@ -430,7 +589,7 @@ func (api *LuaAPI) registerListFunction(L *lua.State) {
 			L.PushString("characteristics")
 			L.NewTable()
 			charIndex := 1
-			for _, c := range service.GetCharacteristics() {
+			for _, c := range chars {
 				L.PushInteger(int64(charIndex))
 				L.PushString(c.UUID())
 				L.SetTable(-3)
@@ -459,6 +618,104 @@ func (api *LuaAPI) registerListFunction(L *lua.State) {
 	L.SetTable(-3)
 }
 
+// characteristicPropertyNames lists the property keys of a characteristic properties table in bit order
+var characteristicPropertyNames = []string{
+	"broadcast", "read", "write_without_response", "write",
+	"notify", "indicate", "authenticated_signed_writes", "extended_properties",
+}
+
+// characteristicPropertyAccessors maps property keys to their device.Properties accessors
+var characteristicPropertyAccessors = map[string]func(device.Properties) device.Property{
+	"broadcast":                   device.Properties.Broadcast,
+	"read":                        device.Properties.Read,
+	"write_without_response":      device.Properties.WriteWithoutResponse,
+	"write":                       device.Properties.Write,
+	"notify":                      device.Properties.Notify,
+	"indicate":                    device.Properties.Indicate,
+	"authenticated_signed_writes": device.Properties.AuthenticatedSignedWrites,
+	"extended_properties":         device.Properties.ExtendedProperties,
+}
+
+// hasProperties reports whether props has every named property
+func hasProperties(props device.Properties, names []string) bool {
+	if len(names) == 0 {
+		return true
+	}
+	if props == nil {
+		return false
+	}
+	for _, name := range names {
+		if characteristicPropertyAccessors[name](props) == nil {
+			return false
+		}
+	}
+	return true
+}
+
+// propertyMask returns the GATT property bits of props (0 if nil)
+func propertyMask(props device.Properties) int64 {
+	var mask int64
+	if props == nil {
+		return mask
+	}
+	for _, name := range characteristicPropertyNames {
+		if prop := characteristicPropertyAccessors[name](props); prop != nil {
+			mask |= int64(prop.Value())
+		}
+	}
+	return mask
+}
+
+// registerPropertyConstants registers blim.PROPERTIES, mapping upper-case property names (READ, NOTIFY, ...)
+// to their GATT bit values. characteristicPropertyNames is in bit order, so the n-th name is bit 1<<n.
+func (api *LuaAPI) registerPropertyConstants(L *lua.State) {
+	L.PushString("PROPERTIES")
+	L.NewTable()
+	for i, name := range characteristicPropertyNames {
+		L.PushString(strings.ToUpper(name))
+		L.PushInteger(int64(1) << i)
+		L.SetTable(-3)
+	}
+	L.SetTable(-3)
+}
+
+// registerAllCharacteristicsFunction registers the blim.all_characteristics() function
+func (api *LuaAPI) registerAllCharacteristicsFunction(L *lua.State) {
+	api.SafePushGoFunction(L, "all_characteristics", func(L *lua.State) int {
+		// Get connection when function is called, not when registered
+		connection := api.device.GetConnection()
+		L.NewTable()
+		if connection == nil {
+			return 1 // Return empty table if no connection
+		}
+
+		// Flat array ordered by service UUID, then characteristic UUID
+		for i, c := range connection.Characteristics() {
+			L.PushInteger(int64(i + 1))
+
+			// Create characteristic info table: { uuid, name?, service }
+			L.NewTable()
+			L.PushString("uuid")
+			L.PushString(c.UUID())
+			L.SetTable(-3)
+
+			if knownName := c.KnownName(); knownName != "" {
+				L.PushString("name")
+				L.PushString(knownName)
+				L.SetTable(-3)
+			}
+
+			L.PushString("service")
+			L.PushString(c.ServiceUUID())
+			L.SetTable(-3)
+
+			L.SetTable(-3) // main_table[i+1] = char_info
+		}
+		return 1
+	})
+	L.SetTable(-3)
+}
+
 // registerDeviceInfo registers the ble.device table with device information
 func (api *LuaAPI) registerDeviceInfo(L *lua.State) {
 	dev := api.device
@@ -482,6 +739,18 @@ func (api *LuaAPI) registerDeviceInfo(L *lua.State) {
 		L.PushString(dev.Name())
 		L.SetTable(-3)
 
+		// Complete and Shortened Local Name AD fields (optional)
+		if completeName := dev.CompleteName(); completeName != "" {
+			L.PushString("complete_name")
+			L.PushString(completeName)
+			L.SetTable(-3)
+		}
+		if shortName := dev.ShortName(); shortName != "" {
+			L.PushString("short_name")
+			L.PushString(shortName)
+			L.SetTable(-3)
+		}
+
 		// RSSI
 		L.PushString("rssi")
 		L.PushInteger(int64(dev.RSSI()))
@@ -492,6 +761,12 @@ func (api *LuaAPI) registerDeviceInfo(L *lua.State) {
 		L.PushBoolean(dev.IsConnectable())
 		L.SetTable(-3)
 
+		// Paired (updated by blim.pair() on success)
+		conn := dev.GetConnection()
+		L.PushString("is_paired")
+		L.PushBoolean(conn != nil && conn.IsPaired())
+		L.SetTable(-3)
+
 		// TX Power (optional)
 		if txPower := dev.TxPower(); txPower != nil {
 			L.PushString("tx_power")
@@ -592,6 +867,130 @@ func (api *LuaAPI) parseSubscriptionTable(L *lua.State, tableIndex int) (*LuaSub
 	}
 	L.Pop(1)
 
+	// Parse optional AggregateWindow
+	L.PushString("AggregateWindow")
+	L.GetTable(tableIndex)
+	if !L.IsNil(-1) {
+		if !L.IsNumber(-1) || L.ToInteger(-1) <= 0 {
+			L.Pop(1)
+			return nil, fmt.Errorf("subscription AggregateWindow must be a positive number of milliseconds")
+		}
+		config.AggregateWindow = L.ToInteger(-1)
+	}
+	L.Pop(1)
+
+	// Parse optional EmitEmpty flag
+	L.PushString("EmitEmpty")
+	L.GetTable(tableIndex)
+	if !L.IsNil(-1) {
+		if !L.IsBoolean(-1) {
+			L.Pop(1)
+			return nil, fmt.Errorf("subscription EmitEmpty must be a boolean")
+		}
+		config.EmitEmpty = L.ToBoolean(-1)
+	}
+	L.Pop(1)
+
+	// Parse optional Buffer
+	L.PushString("Buffer")
+	L.GetTable(tableIndex)
+	if !L.IsNil(-1) {
+		if !L.IsNumber(-1) || L.ToInteger(-1) <= 0 {
+			L.Pop(1)
+			return nil, fmt.Errorf("subscription Buffer must be a positive number of notifications")
+		}
+		config.Buffer = L.ToInteger(-1)
+	}
+	L.Pop(1)
+
+	// Parse optional Duration
+	L.PushString("Duration")
+	L.GetTable(tableIndex)
+	if !L.IsNil(-1) {
+		if !L.IsNumber(-1) || L.ToInteger(-1) <= 0 {
+			L.Pop(1)
+			return nil, fmt.Errorf("subscription Duration must be a positive number of milliseconds")
+		}
+		config.Duration = L.ToInteger(-1)
+	}
+	L.Pop(1)
+
+	// Parse optional Parsed flag
+	L.PushString("Parsed")
+	L.GetTable(tableIndex)
+	if !L.IsNil(-1) {
+		if !L.IsBoolean(-1) {
+			L.Pop(1)
+			return nil, fmt.Errorf("subscription Parsed must be a boolean")
+		}
+		config.Parsed = L.ToBoolean(-1)
+	}
+	L.Pop(1)
+
+	// Parse optional ReadInitial flag
+	L.PushString("ReadInitial")
+	L.GetTable(tableIndex)
+	if !L.IsNil(-1) {
+		if !L.IsBoolean(-1) {
+			L.Pop(1)
+			return nil, fmt.Errorf("subscription ReadInitial must be a boolean")
+		}
+		config.ReadInitial = L.ToBoolean(-1)
+	}
+	L.Pop(1)
+
+	// Parse optional RequireAll flag
+	L.PushString("RequireAll")
+	L.GetTable(tableIndex)
+	if !L.IsNil(-1) {
+		if !L.IsBoolean(-1) {
+			L.Pop(1)
+			return nil, fmt.Errorf("subscription RequireAll must be a boolean")
+		}
+		config.RequireAll = L.ToBoolean(-1)
+	}
+	L.Pop(1)
+
+	// Parse optional Reassemble options
+	L.PushString("Reassemble")
+	L.GetTable(tableIndex)
+	if !L.IsNil(-1) {
+		reassemble, err := parseReassembleOptions(L)
+		if err != nil {
+			L.Pop(1)
+			return nil, err
+		}
+		config.Reassemble = reassemble
+	}
+	L.Pop(1)
+
+	// Parse optional Filter predicate
+	L.PushString("Filter")
+	L.GetTable(tableIndex)
+	if L.IsFunction(-1) {
+		config.FilterRef = L.Ref(lua.LUA_REGISTRYINDEX)
+	} else {
+		isNil := L.IsNil(-1)
+		L.Pop(1)
+		if !isNil {
+			return nil, fmt.Errorf("subscription Filter must be a function(uuid, bytes)")
+		}
+	}
+
+	// Parse optional KeyFn
+	L.PushString("KeyFn")
+	L.GetTable(tableIndex)
+	if L.IsFunction(-1) {
+		config.KeyFnRef = L.Ref(lua.LUA_REGISTRYINDEX)
+	} else {
+		isNil := L.IsNil(-1)
+		L.Pop(1)
+		if !isNil {
+			config.unref(L)
+			return nil, fmt.Errorf("subscription KeyFn must be a function(bytes)")
+		}
+	}
+
 	// Parse Callback function
 	L.PushString("Callback")
 	L.GetTable(tableIndex)
@@ -605,7 +1004,36 @@ func (api *LuaAPI) parseSubscriptionTable(L *lua.State, tableIndex int) (*LuaSub
 	return config, nil
 }
 
-// parseServicesArray parses the service array from the Lua table
+// parseReassembleOptions parses the Reassemble table at the top of the stack: {terminator=bytes, max_size=n}
+func parseReassembleOptions(L *lua.State) (*LuaReassembleOptions, error) {
+	if !L.IsTable(-1) {
+		return nil, fmt.Errorf("subscription Reassemble must be a table {terminator=bytes, max_size=n}")
+	}
+
+	opts := &LuaReassembleOptions{}
+	L.GetField(-1, "terminator")
+	if L.Type(-1) != lua.LUA_TSTRING || L.ToString(-1) == "" {
+		L.Pop(1)
+		return nil, fmt.Errorf("subscription Reassemble.terminator must be a non-empty string")
+	}
+	opts.Terminator = []byte(L.ToString(-1))
+	L.Pop(1)
+
+	L.GetField(-1, "max_size")
+	if !L.IsNil(-1) {
+		if !L.IsNumber(-1) || L.ToInteger(-1) <= 0 {
+			L.Pop(1)
+			return nil, fmt.Errorf("subscription Reassemble.max_size must be a positive number of bytes")
+		}
+		opts.MaxSize = L.ToInteger(-1)
+	}
+	L.Pop(1)
+
+	return opts, nil
+}
+
+// parseServicesArray parses the service array from the Lua table.
+// Services may be given by UUID or known name (e.g., "Heart Rate").
 func (api *LuaAPI) parseServicesArray(L *lua.State, tableIndex int) ([]device.SubscribeOptions, error) {
 	var services []device.SubscribeOptions
 
@@ -620,13 +1048,11 @@ func (api *LuaAPI) parseServicesArray(L *lua.State, tableIndex int) ([]device.Su
 		if L.IsTable(-1) {
 			service := device.SubscribeOptions{}
 
-			// Parse service UUID
+			// Parse service UUID or known name
 			L.PushString("service")
 			L.GetTable(-2)
 			if L.IsString(-1) {
-				// Normalize service UUID
-				normalizedService := device.NormalizeUUID(L.ToString(-1))
-				service.Service = normalizedService
+				service.Service = device.ResolveServiceUUID(L.ToString(-1))
 			}
 			L.Pop(1)
 
@@ -657,7 +1083,8 @@ func (api *LuaAPI) parseServicesArray(L *lua.State, tableIndex int) ([]device.Su
 	return services, nil
 }
 
-// parseCharsArray parses the characteristic array from a service
+// parseCharsArray parses the characteristic array from a service.
+// Entries may be characteristic UUIDs or known names.
 func (api *LuaAPI) parseCharsArray(L *lua.State, tableIndex int) []string {
 	var chars []string
 
@@ -669,9 +1096,8 @@ func (api *LuaAPI) parseCharsArray(L *lua.State, tableIndex int) []string {
 	L.PushNil()
 	for L.Next(tableIndex) != 0 {
 		if L.IsString(-1) {
-			// Normalize characteristic UUID
-			normalizedChar := device.NormalizeUUID(L.ToString(-1))
-			chars = append(chars, normalizedChar)
+			// Resolve characteristic UUID or known name
+			chars = append(chars, device.ResolveCharacteristicUUID(L.ToString(-1)))
 		}
 		L.Pop(1) // Pop value, keep key for next iteration
 	}
@@ -693,77 +1119,318 @@ func (api *LuaAPI) executeSubscription(config *LuaSubscriptionTable) error {
 			Service:         serviceConfig.Service,
 			Characteristics: serviceConfig.Characteristics,
 			Indicate:        serviceConfig.Indicate, // Use per-service Indicate flag
+			ChannelBuffer:   config.Buffer,
 		}
 		opts = append(opts, opt)
 	}
 
 	// Parse mode and max rate
 	pattern := parseStreamPattern(config.Mode)
-	maxRate := time.Duration(config.MaxRate) * time.Millisecond
+	stream := device.StreamOptions{
+		MaxRate:  time.Duration(config.MaxRate) * time.Millisecond,
+		Lifetime: time.Duration(config.Duration) * time.Millisecond,
+	}
+
+	if config.AggregateWindow > 0 {
+		stream.Window = &device.AggregateWindow{
+			Interval:  time.Duration(config.AggregateWindow) * time.Millisecond,
+			EmitEmpty: config.EmitEmpty,
+		}
+	} else if config.EmitEmpty {
+		return fmt.Errorf("subscription EmitEmpty requires AggregateWindow")
+	}
+
+	var reassembler *messageReassembler
+	if config.Reassemble != nil {
+		reassembler = newMessageReassembler(config.Reassemble.Terminator, config.Reassemble.MaxSize)
+	}
+
+	// deliver filters, keys and passes a record to the Lua callback
+	deliver := func(record *device.Record) {
+		if config.FilterRef != 0 {
+			if record = api.applyLuaFilter(config.FilterRef, record); record == nil {
+				return // Every value was filtered out
+			}
+		}
+		if config.KeyFnRef != 0 {
+			record = api.applyLuaKeyFn(config.KeyFnRef, record)
+		}
+		api.callLuaCallback(config.CallbackRef, record, config.Parsed)
+	}
 
 	// Create a callback that calls the Lua function (nil if no callback provided)
 	var callback func(*device.Record)
 	if config.CallbackRef != 0 {
 		callback = func(record *device.Record) {
-			api.callLuaCallback(config.CallbackRef, record)
+			if record.End {
+				api.callLuaCallback(config.CallbackRef, record, false) // End marker carries no values to filter, key or parse
+				return
+			}
+			if reassembler == nil {
+				deliver(record)
+				return
+			}
+
+			// Only completed messages reach the callback
+			records, overflowed := reassembler.apply(record)
+			for _, uuid := range overflowed {
+				api.logger.Warnf("Reassembly buffer of %s exceeded %d bytes without terminator, discarded", uuid, reassembler.maxSize)
+				api.LuaEngine.outputChan.ForceSend(LuaOutputRecord{
+					Content:   fmt.Sprintf("Reassemble error: %s buffer exceeded %d bytes without terminator, discarded", uuid, reassembler.maxSize),
+					Timestamp: time.Now(),
+					Source:    "stderr",
+				})
+			}
+			for _, r := range records {
+				deliver(r)
+			}
+		}
+	}
+
+	// Check everything listed up front, so a firmware mismatch names all missing UUIDs at once
+	if config.RequireAll {
+		if err := device.CheckRequiredAttributes(api.device.GetConnection(), config.Services); err != nil {
+			return err
 		}
 	}
 
 	// Call Subscribe on the connection
-	return api.device.GetConnection().Subscribe(opts, pattern, maxRate, callback)
+	if err := api.device.GetConnection().Subscribe(opts, pattern, stream, callback); err != nil {
+		return err
+	}
+
+	if config.ReadInitial && config.CallbackRef != 0 {
+		if record := api.readInitialRecord(opts, pattern); record != nil {
+			// The Lua state is held by the running subscribe() call: deliver once the script releases it.
+			// A read value is complete, so it bypasses reassembly
+			groutine.Go(context.Background(), "lua-subscribe-initial", func(ctx context.Context) {
+				deliver(record)
+			})
+		}
+	}
+	return nil
 }
 
-// callPTYDataCallback calls the Lua callback function when PTY data arrives
-func (api *LuaAPI) callPTYDataCallback(callbackRef int, data []byte) error {
-	if callbackRef == lua.LUA_NOREF {
+// readInitialRecord reads every readable subscribed characteristic once and returns the values as a record
+// flagged Initial, shaped for the stream mode (BatchValues for Batched, Values otherwise).
+// Returns nil if nothing could be read; read failures are reported on stderr.
+func (api *LuaAPI) readInitialRecord(opts []*device.SubscribeOptions, pattern device.StreamMode) *device.Record {
+	connection := api.device.GetConnection()
+	readTimeout, _, _ := api.timeouts()
+	record := &device.Record{TsUs: time.Now().UnixMicro(), Initial: true}
+
+	for _, opt := range opts {
+		var chars []device.Characteristic
+		if len(opt.Characteristics) == 0 {
+			service, err := connection.GetService(opt.Service)
+			if err != nil {
+				continue
+			}
+			chars = service.GetCharacteristics()
+		} else {
+			for _, uuid := range opt.Characteristics {
+				if char, err := connection.GetCharacteristic(opt.Service, uuid); err == nil {
+					chars = append(chars, char)
+				}
+			}
+		}
+
+		for _, char := range chars {
+			if props := char.GetProperties(); props == nil || props.Read() == nil || props.Read().Value() == 0 {
+				continue
+			}
+			value, err := char.Read(readTimeout)
+			if err != nil {
+				api.logger.WithError(err).Warnf("Initial read of %s failed", char.UUID())
+				api.LuaEngine.outputChan.ForceSend(LuaOutputRecord{
+					Content:   fmt.Sprintf("ReadInitial error: %s: %v", char.UUID(), err),
+					Timestamp: time.Now(),
+					Source:    "stderr",
+				})
+				continue
+			}
+			if pattern == device.StreamBatched {
+				record.AppendBatchValues(char.UUID(), value)
+			} else {
+				record.SetValue(char.UUID(), value)
+			}
+		}
+	}
+
+	if record.Values == nil && record.BatchValues == nil {
 		return nil
 	}
+	return record
+}
+
+// applyLuaFilter evaluates the subscription Filter predicate for every value of the record.
+// Returns a record containing only the values the predicate accepted, or nil if none were accepted.
+// A failing predicate is reported on stderr and treated as accepting, so errors never drop data silently.
+func (api *LuaAPI) applyLuaFilter(filterRef int, record *device.Record) (filtered *device.Record) {
+	filtered = &device.Record{TsUs: record.TsUs, Seq: record.Seq, Flags: record.Flags, Initial: record.Initial}
+
+	reportError := func(err any) {
+		api.logger.Errorf("Lua subscribe filter failed: %v", err)
+		api.LuaEngine.outputChan.ForceSend(LuaOutputRecord{
+			Content:   fmt.Sprintf("Filter error: %v", err),
+			Timestamp: time.Now(),
+			Source:    "stderr",
+		})
+	}
 
-	// Outer panic handler: catches ALL panics (including LuaError from StackTrace crashes)
-	// This ensures one callback's error doesn't crash the PTY dispatcher
 	defer func() {
 		if r := recover(); r != nil {
-			// Log ALL panics (LuaError or otherwise) and recover gracefully
-			stack := string(debug.Stack())
-			api.logger.Errorf("PTY Lua callback panic (recovered): %v\nStack:\n%s", r, stack)
-
-			// Send error to stderr for user visibility
-			api.LuaEngine.outputChan.ForceSend(LuaOutputRecord{
-				Content:   fmt.Sprintf("PTY callback error: %v", r),
-				Timestamp: time.Now(),
-				Source:    "stderr",
-			})
-
-			// DANGEROUS - DO NOT DO THIS:
-			// Attempting to clean up Lua state after panic is unsafe because:
-			// 1. SIGSEGV from Lua FFI code means the Lua VM is corrupted
-			// 2. Calling L.SetTop(0) on corrupted state → another SIGSEGV
-			// 3. Go's recover() cannot catch SIGSEGV - process will crash
-			// 4. When L.Call() returns error normally, stack is already cleaned
-			//
-			// OLD DANGEROUS CODE (commented out):
-			// api.LuaEngine.DoWithState(func(L *lua.State) interface{} {
-			//     L.SetTop(0) // ← SIGSEGV if state is corrupted
-			//     return nil
-			// })
-
-			// DO NOT re-panic - allow PTY reading to continue
+			reportError(r)
+			filtered = record // Fail open
 		}
 	}()
 
 	api.LuaEngine.DoWithState(func(L *lua.State) interface{} {
-		// Inner panic handler: catches panics from L.Call() (including StackTrace crashes)
-		defer func() {
-			if r := recover(); r != nil {
-				// Re-panic to outer handler for cleanup
-				panic(r)
+		// accept calls filter(uuid, bytes) and returns its truthiness
+		accept := func(uuid string, data []byte) bool {
+			L.RawGeti(lua.LUA_REGISTRYINDEX, filterRef)
+			L.PushString(uuid)
+			L.PushString(string(data))
+			if err := L.Call(2, 1); err != nil {
+				reportError(err)
+				L.SetTop(0)
+				return true
 			}
-		}()
+			ok := L.ToBoolean(-1)
+			L.Pop(1)
+			return ok
+		}
 
-		// Push the callback function onto the stack using reference
-		L.RawGeti(lua.LUA_REGISTRYINDEX, callbackRef)
+		for _, uuid := range record.Keys() {
+			if data, ok := record.Values[uuid]; ok && accept(uuid, data) {
+				filtered.SetValue(uuid, data)
+			}
+			for _, data := range record.BatchValues[uuid] {
+				if accept(uuid, data) {
+					filtered.AppendBatchValues(uuid, data)
+				}
+			}
+		}
+		return nil
+	})
 
-		// Push data as string argument (Lua string can contain binary data)
+	if filtered.Values == nil && filtered.BatchValues == nil {
+		return nil
+	}
+	return filtered
+}
+
+// applyLuaKeyFn re-keys every value of the record by the string the subscription KeyFn returns for it,
+// so Batched records group values per key instead of per characteristic UUID.
+// Values for which KeyFn fails or returns a non-string keep their UUID; failures are reported on stderr.
+func (api *LuaAPI) applyLuaKeyFn(keyFnRef int, record *device.Record) (keyed *device.Record) {
+	keyed = &device.Record{TsUs: record.TsUs, Seq: record.Seq, Flags: record.Flags, Initial: record.Initial}
+
+	reportError := func(err any) {
+		api.logger.Errorf("Lua subscribe KeyFn failed: %v", err)
+		api.LuaEngine.outputChan.ForceSend(LuaOutputRecord{
+			Content:   fmt.Sprintf("KeyFn error: %v", err),
+			Timestamp: time.Now(),
+			Source:    "stderr",
+		})
+	}
+
+	defer func() {
+		if r := recover(); r != nil {
+			reportError(r)
+			keyed = record // Keep UUID keys
+		}
+	}()
+
+	api.LuaEngine.DoWithState(func(L *lua.State) interface{} {
+		// keyOf calls keyFn(bytes) and falls back to the UUID unless it returns a string
+		keyOf := func(uuid string, data []byte) string {
+			L.RawGeti(lua.LUA_REGISTRYINDEX, keyFnRef)
+			L.PushString(string(data))
+			if err := L.Call(1, 1); err != nil {
+				reportError(err)
+				L.SetTop(0)
+				return uuid
+			}
+			key := uuid
+			if L.Type(-1) == lua.LUA_TSTRING {
+				key = L.ToString(-1)
+			}
+			L.Pop(1)
+			return key
+		}
+
+		if record.Values != nil {
+			keyed.Values = make(map[string][]byte, len(record.Values))
+		}
+		if record.BatchValues != nil {
+			keyed.BatchValues = make(map[string][][]byte, len(record.BatchValues))
+		}
+		for _, uuid := range record.Keys() {
+			if data, ok := record.Values[uuid]; ok {
+				keyed.SetValue(keyOf(uuid, data), data)
+			}
+			for _, data := range record.BatchValues[uuid] {
+				keyed.AppendBatchValues(keyOf(uuid, data), data)
+			}
+		}
+		return nil
+	})
+
+	return keyed
+}
+
+// callPTYDataCallback calls the Lua callback function when PTY data arrives
+func (api *LuaAPI) callPTYDataCallback(callbackRef int, data []byte) error {
+	if callbackRef == lua.LUA_NOREF {
+		return nil
+	}
+
+	// Outer panic handler: catches ALL panics (including LuaError from StackTrace crashes)
+	// This ensures one callback's error doesn't crash the PTY dispatcher
+	defer func() {
+		if r := recover(); r != nil {
+			// Log ALL panics (LuaError or otherwise) and recover gracefully
+			stack := string(debug.Stack())
+			api.logger.Errorf("PTY Lua callback panic (recovered): %v\nStack:\n%s", r, stack)
+
+			// Send error to stderr for user visibility
+			api.LuaEngine.outputChan.ForceSend(LuaOutputRecord{
+				Content:   fmt.Sprintf("PTY callback error: %v", r),
+				Timestamp: time.Now(),
+				Source:    "stderr",
+			})
+
+			// DANGEROUS - DO NOT DO THIS:
+			// Attempting to clean up Lua state after panic is unsafe because:
+			// 1. SIGSEGV from Lua FFI code means the Lua VM is corrupted
+			// 2. Calling L.SetTop(0) on corrupted state → another SIGSEGV
+			// 3. Go's recover() cannot catch SIGSEGV - process will crash
+			// 4. When L.Call() returns error normally, stack is already cleaned
+			//
+			// OLD DANGEROUS CODE (commented out):
+			// api.LuaEngine.DoWithState(func(L *lua.State) interface{} {
+			//     L.SetTop(0) // ← SIGSEGV if state is corrupted
+			//     return nil
+			// })
+
+			// DO NOT re-panic - allow PTY reading to continue
+		}
+	}()
+
+	api.LuaEngine.DoWithState(func(L *lua.State) interface{} {
+		// Inner panic handler: catches panics from L.Call() (including StackTrace crashes)
+		defer func() {
+			if r := recover(); r != nil {
+				// Re-panic to outer handler for cleanup
+				panic(r)
+			}
+		}()
+
+		// Push the callback function onto the stack using reference
+		L.RawGeti(lua.LUA_REGISTRYINDEX, callbackRef)
+
+		// Push data as string argument (Lua string can contain binary data)
 		L.PushString(string(data))
 
 		// Call the function with one argument (the data string)
@@ -792,7 +1459,7 @@ func (api *LuaAPI) callPTYDataCallback(callbackRef int, data []byte) error {
 }
 
 // callLuaCallback calls the Lua callback function with the record data
-func (api *LuaAPI) callLuaCallback(callbackRef int, record *device.Record) error {
+func (api *LuaAPI) callLuaCallback(callbackRef int, record *device.Record, parsed bool) error {
 	api.logger.Debugf("[callLuaCallback] entry, callbackRef=%d", callbackRef)
 	if callbackRef == lua.LUA_NOREF {
 		api.logger.Debug("[callLuaCallback] LUA_NOREF, returning")
@@ -861,6 +1528,20 @@ func (api *LuaAPI) callLuaCallback(callbackRef int, record *device.Record) error
 		L.PushInteger(int64(record.Flags))
 		L.SetTable(-3)
 
+		// Set end marker (final record of a subscription with a Duration)
+		if record.End {
+			L.PushString("end")
+			L.PushBoolean(true)
+			L.SetTable(-3)
+		}
+
+		// Set initial marker (values read at subscribe time with ReadInitial)
+		if record.Initial {
+			L.PushString("initial")
+			L.PushBoolean(true)
+			L.SetTable(-3)
+		}
+
 		// Set Values table (for EveryUpdate/Aggregated modes)
 		if record.Values != nil {
 			L.PushString("Values")
@@ -896,6 +1577,23 @@ func (api *LuaAPI) callLuaCallback(callbackRef int, record *device.Record) error
 			L.SetTable(-3)
 		}
 
+		// Set order: keys of Values/BatchValues in configured/received order, for deterministic iteration
+		if record.Values != nil || record.BatchValues != nil {
+			L.PushString("order")
+			L.NewTable()
+			for i, key := range record.Keys() {
+				L.PushInteger(int64(i + 1))
+				L.PushString(key)
+				L.SetTable(-3)
+			}
+			L.SetTable(-3)
+		}
+
+		// Set the Parsed table (for subscriptions with Parsed = true)
+		if parsed {
+			api.pushParsedRecordValues(L, record)
+		}
+
 		// Call the function with 1 argument (the record table)
 		// This can panic if StackTrace() crashes while building LuaError
 		if err := L.Call(1, 0); err != nil {
@@ -921,6 +1619,52 @@ func (api *LuaAPI) callLuaCallback(callbackRef int, record *device.Record) error
 	return nil
 }
 
+// pushParsedRecordValues sets record.Parsed on the record table at the top of the stack, decoding values of
+// characteristics with a registered parser: Parsed[uuid] mirrors Values[uuid], or for Batched records is an array
+// aligned with BatchValues[uuid] (false where a value could not be parsed).
+// Values without a parser, or failing to parse, are only available raw.
+func (api *LuaAPI) pushParsedRecordValues(L *lua.State, record *device.Record) {
+	parse := func(uuid string, data []byte) interface{} {
+		value, err := device.ParseCharacteristicValue(uuid, data)
+		if err != nil {
+			api.logger.WithError(err).Debugf("Failed to parse notified value of %s", uuid)
+			return nil
+		}
+		return value
+	}
+
+	L.PushString("Parsed")
+	L.NewTable()
+	for uuid, data := range record.Values {
+		if !device.IsParsableCharacteristic(uuid) {
+			continue
+		}
+		if value := parse(uuid, data); value != nil {
+			L.PushString(uuid)
+			api.pushCharacteristicParsedValue(L, value)
+			L.SetTable(-3)
+		}
+	}
+	for uuid, dataArray := range record.BatchValues {
+		if !device.IsParsableCharacteristic(uuid) {
+			continue
+		}
+		L.PushString(uuid)
+		L.NewTable()
+		for i, data := range dataArray {
+			L.PushInteger(int64(i + 1))
+			if value := parse(uuid, data); value != nil {
+				api.pushCharacteristicParsedValue(L, value)
+			} else {
+				L.PushBoolean(false)
+			}
+			L.SetTable(-3)
+		}
+		L.SetTable(-3)
+	}
+	L.SetTable(-3)
+}
+
 // registerCharacteristicFunction registers the ble.characteristic() function
 func (api *LuaAPI) registerCharacteristicFunction(L *lua.State) {
 	api.SafePushGoFunction(L, "characteristic", func(L *lua.State) int {
@@ -1015,6 +1759,14 @@ func (api *LuaAPI) registerCharacteristicFunction(L *lua.State) {
 		addProp(props.AuthenticatedSignedWrites(), "authenticated_signed_writes")
 		addProp(props.ExtendedProperties(), "extended_properties")
 
+		// Per-operation security requirements (hash part only, so ipairs() still yields properties)
+		L.PushString("read_requires_auth")
+		L.PushBoolean(char.ReadRequiresAuthentication())
+		L.SetTable(-3)
+		L.PushString("write_requires_auth")
+		L.PushBoolean(char.WriteRequiresAuthentication())
+		L.SetTable(-3)
+
 		L.SetTable(-3)
 
 		// Field: descriptors (array of objects with uuid, handle, name, value, and parsed_value)
@@ -1028,9 +1780,24 @@ func (api *LuaAPI) registerCharacteristicFunction(L *lua.State) {
 		}
 		L.SetTable(-3)
 
-		// Field: has_parser (true if a parser is registered for this characteristic type)
+		// Field: has_parser (true if a parser is registered for this characteristic type or overridden for this service)
+		overrideKey := parserOverrideKey(serviceUUID, char.UUID())
+		_, overridden := api.parserOverrides[overrideKey]
 		L.PushString("has_parser")
-		L.PushBoolean(char.HasParser())
+		L.PushBoolean(char.HasParser() || overridden)
+		L.SetTable(-3)
+
+		// Field: unit (optional, unit name of characteristics that parse to a bare number)
+		if unit := device.CharacteristicUnit(char.UUID()); unit != "" {
+			L.PushString("unit")
+			L.PushString(unit)
+			L.SetTable(-3)
+		}
+
+		// Field: is_utf8 (true if the value is known to be a UTF-8 string)
+		isUTF8 := device.IsUTF8Characteristic(char.UUID(), descriptors)
+		L.PushString("is_utf8")
+		L.PushBoolean(isUTF8)
 		L.SetTable(-3)
 
 		// Field: requires_authentication (true if characteristic requires pairing/authentication)
@@ -1039,12 +1806,37 @@ func (api *LuaAPI) registerCharacteristicFunction(L *lua.State) {
 		L.SetTable(-3)
 
 		// Method: read() - reads the characteristic value from the device
-		// Returns (value, nil) on success or (nil, error_message) on failure
+		// Returns (value, nil) on success or (nil, error_table) on failure
 		api.SafePushGoFunction(L, "read", func(L *lua.State) int {
-			value, err := char.Read(api.characteristicReadTimeout)
+			readTimeout, _, _ := api.timeouts()
+			value, err := char.Read(readTimeout)
+			if err != nil {
+				L.PushNil()
+				pushLuaError(L, "read()", err)
+				return 2
+			}
+
+			L.PushString(string(value))
+			L.PushNil()
+			return 2 // (value, nil)
+		})
+		L.SetTable(-3)
+
+		// Method: read_cached(ttl_ms) - returns the last read or notified value if younger than ttl_ms,
+		// otherwise reads from the device. Note: colon syntax, ttl_ms is argument 2.
+		// Returns (value, nil) on success or (nil, error_table) on failure
+		api.SafePushGoFunction(L, "read_cached", func(L *lua.State) int {
+			if !L.IsNumber(2) || L.ToInteger(2) < 0 {
+				L.RaiseError("read_cached(ttl_ms) expects a non-negative number argument")
+				return 0
+			}
+			ttl := time.Duration(L.ToInteger(2)) * time.Millisecond
+
+			readTimeout, _, _ := api.timeouts()
+			value, err := char.ReadCached(ttl, readTimeout)
 			if err != nil {
 				L.PushNil()
-				L.PushString(fmt.Sprintf("read() failed: %s", stripWrappedGoErrorSuffix(err.Error())))
+				pushLuaError(L, "read_cached()", err)
 				return 2
 			}
 
@@ -1054,11 +1846,125 @@ func (api *LuaAPI) registerCharacteristicFunction(L *lua.State) {
 		})
 		L.SetTable(-3)
 
+		// Method: unpack(format) - reads the value and decodes it with a string.pack-style format (e.g. "<HBf").
+		// Note: colon syntax, format is argument 2.
+		// Returns the decoded fields as multiple values on success or (nil, error_table) on failure
+		api.SafePushGoFunction(L, "unpack", func(L *lua.State) int {
+			if !L.IsString(2) {
+				L.RaiseError("unpack(format) expects a format string argument")
+				return 0
+			}
+			format := L.ToString(2)
+
+			readTimeout, _, _ := api.timeouts()
+			value, err := char.Read(readTimeout)
+			if err == nil {
+				var fields []any
+				if fields, err = unpackStruct(format, value); err == nil {
+					for _, field := range fields {
+						switch v := field.(type) {
+						case int64:
+							L.PushInteger(v)
+						case float64:
+							L.PushNumber(v)
+						}
+					}
+					return len(fields)
+				}
+			}
+
+			L.PushNil()
+			pushLuaError(L, "unpack()", err)
+			return 2
+		})
+		L.SetTable(-3)
+
+		// Method: last_value() - returns the last notified value without issuing a read.
+		// Returns (value, timestamp_us) or nil if no notification has been received yet
+		api.SafePushGoFunction(L, "last_value", func(L *lua.State) int {
+			value, tsUs, ok := char.LastValue()
+			if !ok {
+				L.PushNil()
+				return 1
+			}
+			L.PushString(string(value))
+			L.PushInteger(tsUs)
+			return 2 // (value, timestamp_us)
+		})
+		L.SetTable(-3)
+
+		// Method: has_property(bits) - returns true if the characteristic has every property bit in bits
+		// (e.g. blim.PROPERTIES.NOTIFY, or several bits combined). Note: colon syntax, bits is argument 2
+		api.SafePushGoFunction(L, "has_property", func(L *lua.State) int {
+			if !L.IsNumber(2) || L.ToInteger(2) <= 0 {
+				L.RaiseError("has_property(bits) expects a positive number, e.g. blim.PROPERTIES.NOTIFY")
+				return 0
+			}
+			bits := int64(L.ToInteger(2))
+			L.PushBoolean(propertyMask(char.GetProperties())&bits == bits)
+			return 1
+		})
+		L.SetTable(-3)
+
+		// Method: write_verified(data, [timeout_ms]) - writes with response, reads the value back and compares.
+		// Note: colon syntax, data is argument 2. timeout_ms applies to both the write and the read
+		// (default: the configured write and read timeouts).
+		// Returns (true, nil) if the read-back matches, (false, "mismatch", actual_bytes) if it differs,
+		// or (nil, error_table) if the characteristic is not readable or the write/read fails
+		api.SafePushGoFunction(L, "write_verified", func(L *lua.State) int {
+			if !L.IsString(2) {
+				L.RaiseError("write_verified(data, [timeout_ms]) expects string as first argument")
+				return 0
+			}
+			data := []byte(L.ToString(2))
+
+			readTimeout, writeTimeout, _ := api.timeouts()
+			if !L.IsNoneOrNil(3) {
+				if !L.IsNumber(3) || L.ToInteger(3) <= 0 {
+					L.RaiseError("write_verified(data, [timeout_ms]) expects a positive number as timeout_ms")
+					return 0
+				}
+				readTimeout = time.Duration(L.ToInteger(3)) * time.Millisecond
+				writeTimeout = readTimeout
+			}
+
+			if props := char.GetProperties(); props == nil || props.Read() == nil || props.Read().Value() == 0 {
+				L.PushNil()
+				pushLuaError(L, "write_verified()", fmt.Errorf("characteristic %s is not readable, cannot verify write: %w", char.UUID(), device.ErrUnsupported))
+				return 2
+			}
+
+			if err := char.Write(data, true, writeTimeout); err != nil {
+				L.PushNil()
+				pushLuaError(L, "write_verified()", err)
+				return 2
+			}
+
+			actual, err := char.Read(readTimeout)
+			if err != nil {
+				L.PushNil()
+				pushLuaError(L, "write_verified()", err)
+				return 2
+			}
+
+			if !bytes.Equal(actual, data) {
+				L.PushBoolean(false)
+				L.PushString("mismatch")
+				L.PushString(string(actual))
+				return 3 // (false, "mismatch", actual_bytes)
+			}
+
+			L.PushBoolean(true)
+			L.PushNil()
+			return 2 // (true, nil)
+		})
+		L.SetTable(-3)
+
 		// Method: write(data, [with_response]) - writes data to the characteristic
 		// Parameters:
 		//   - data: string - data to write (will be converted to bytes)
 		//   - with_response: boolean (optional) - whether to wait for write response (default: true)
-		// Returns (true, nil) on success or (nil, error_message) on failure
+		// Returns (true, nil) on success or (nil, error_table) on failure
 		api.SafePushGoFunction(L, "write", func(L *lua.State) int {
 			// Validate first argument (data)
 			if !L.IsString(1) {
@@ -1081,12 +1987,13 @@ func (api *LuaAPI) registerCharacteristicFunction(L *lua.State) {
 			}
 
 			// Use the abstracted CharacteristicWriter interface with timeout
-			err := char.Write(data, withResponse, api.characteristicWriteTimeout)
+			_, writeTimeout, _ := api.timeouts()
+			err := char.Write(data, withResponse, writeTimeout)
 			if err != nil {
-				// Return (nil, error_message) for expected errors
+				// Return (nil, error_table) for expected errors
 				// Strip wrapped Go error suffix for cleaner Lua messages
 				L.PushNil()
-				L.PushString(fmt.Sprintf("write() failed: %s", stripWrappedGoErrorSuffix(err.Error())))
+				pushLuaError(L, "write()", err)
 				return 2
 			}
 			// Return (true, nil) on success
@@ -1096,9 +2003,91 @@ func (api *LuaAPI) registerCharacteristicFunction(L *lua.State) {
 		})
 		L.SetTable(-3)
 
+		// Method: read_descriptor(uuid) - reads the current descriptor value from the device
+		// Returns (value, nil) on success or (nil, error_table) on failure
+		api.SafePushGoFunction(L, "read_descriptor", func(L *lua.State) int {
+			if !L.IsString(1) {
+				L.RaiseError("read_descriptor(uuid) expects a string argument")
+				return 0
+			}
+
+			descUUID := device.NormalizeUUID(L.ToString(1))
+			for _, desc := range descriptors {
+				if device.NormalizeUUID(desc.UUID()) != descUUID {
+					continue
+				}
+
+				_, _, descriptorTimeout := api.timeouts()
+				value, err := desc.Read(descriptorTimeout)
+				if err != nil {
+					L.PushNil()
+					pushLuaError(L, "read_descriptor()", err)
+					return 2
+				}
+
+				L.PushString(string(value))
+				L.PushNil()
+				return 2 // (value, nil)
+			}
+
+			L.PushNil()
+			L.PushString(fmt.Sprintf("read_descriptor() failed: descriptor %s not found", L.ToString(1)))
+			return 2
+		})
+		L.SetTable(-3)
+
+		// Method: notifications_enabled() - reports whether notifications or indications are enabled.
+		// Uses the connection's known CCCD state when this client enabled them, otherwise reads the
+		// CCCD (0x2902) descriptor. Returns (bool, nil) or (nil, error_table) if there is no CCCD
+		api.SafePushGoFunction(L, "notifications_enabled", func(L *lua.State) int {
+			var cccd device.Descriptor
+			for _, desc := range descriptors {
+				if device.NormalizeUUID(desc.UUID()) == device.DescriptorClientConfig {
+					cccd = desc
+					break
+				}
+			}
+			if cccd == nil {
+				L.PushNil()
+				pushLuaError(L, "notifications_enabled()", &device.NotFoundError{
+					Resource: "descriptor",
+					UUIDs:    []string{device.NormalizeUUID(serviceUUID), char.UUID(), device.DescriptorClientConfig},
+				})
+				return 2
+			}
+
+			normalizedService := device.NormalizeUUID(serviceUUID)
+			for _, state := range connection.EnabledCCCDs() {
+				if state.Service == normalizedService && state.Characteristic == char.UUID() {
+					L.PushBoolean(true)
+					L.PushNil()
+					return 2 // (true, nil)
+				}
+			}
+
+			_, _, descriptorTimeout := api.timeouts()
+			value, err := cccd.Read(descriptorTimeout)
+			if err != nil {
+				L.PushNil()
+				pushLuaError(L, "notifications_enabled()", err)
+				return 2
+			}
+			cfg, err := device.ParseClientConfig(value)
+			if err != nil {
+				L.PushNil()
+				pushLuaError(L, "notifications_enabled()", err)
+				return 2
+			}
+
+			L.PushBoolean(cfg.Notifications || cfg.Indications)
+			L.PushNil()
+			return 2 // (enabled, nil)
+		})
+		L.SetTable(-3)
+
 		// Method: parse(value) - parses characteristic value (only for characteristics with registered parsers)
-		// Returns parsed value or nil if parse error
-		if char.HasParser() {
+		// Returns parsed value or nil if parse error. A parser override of the service+characteristic takes precedence.
+		if char.HasParser() || overridden {
 			api.SafePushGoFunction(L, "parse", func(L *lua.State) int {
 				// Validate argument
 				// Note: when called as char:parse(value), char is passed as arg 1, value as arg 2 (colon syntax)
@@ -1110,19 +2099,50 @@ func (api *LuaAPI) registerCharacteristicFunction(L *lua.State) {
 				// Get value to parse (argument 2 due to colon syntax)
 				value := []byte(L.ToString(2))
 
+				if ref, ok := api.parserOverrides[overrideKey]; ok {
+					return api.callParserOverride(L, ref, value)
+				}
+				if !char.HasParser() {
+					// The override was removed after the handle was created
+					L.PushNil()
+					return 1
+				}
+
 				// Parse the value using the characteristic's registered parser
 				parsed, err := char.ParseValue(value)
-				if err != nil || parsed == nil {
+				if err != nil {
+					L.PushNil()
+					pushLuaError(L, "parse()", err)
+					return 2
+				}
+				if parsed == nil {
 					L.PushNil()
 					return 1
 				}
 
-				// Return parsed value (currently only a string type is supported for Appearance)
-				if str, ok := parsed.(string); ok {
-					L.PushString(str)
-				} else {
+				api.pushCharacteristicParsedValue(L, parsed)
+				return 1
+			})
+			L.SetTable(-3)
+		}
+
+		// Method: decode_utf8(value) - decodes value as UTF-8 text (only for string characteristics)
+		// Returns decoded string or nil if value is not valid printable UTF-8
+		if isUTF8 {
+			api.SafePushGoFunction(L, "decode_utf8", func(L *lua.State) int {
+				// Note: colon syntax, value is argument 2
+				if !L.IsString(2) {
+					L.RaiseError("decode_utf8(value) expects a string argument")
+					return 0
+				}
+
+				str, ok := device.DecodeUTF8Value([]byte(L.ToString(2)))
+				if !ok {
 					L.PushNil()
+					return 1
 				}
+
+				L.PushString(str)
 				return 1
 			})
 			L.SetTable(-3)
@@ -1135,14 +2155,724 @@ func (api *LuaAPI) registerCharacteristicFunction(L *lua.State) {
 	L.SetTable(-3)
 }
 
-// registerSleepFunction registers the blim.sleep() utility function
-// Usage: blim.sleep(milliseconds)
-// Sleeps for the specified number of milliseconds.
-// IMPORTANT: sleep releases the Lua state mutex during sleep to allow subscription
-// callbacks to execute. This enables polling loops to receive BLE notifications.
-func (api *LuaAPI) registerSleepFunction(L *lua.State) {
-	api.SafePushGoFunction(L, "sleep", func(L *lua.State) int {
-		// Validate argument
+// disStringFields maps Device Information Service string characteristics to device_info() table keys
+var disStringFields = []struct {
+	uuid string
+	key  string
+}{
+	{device.CharacteristicManufacturerName, "manufacturer_name"},
+	{device.CharacteristicModelNumber, "model_number"},
+	{device.CharacteristicSerialNumber, "serial_number"},
+	{device.CharacteristicHardwareRevision, "hardware_revision"},
+	{device.CharacteristicFirmwareRevision, "firmware_revision"},
+	{device.CharacteristicSoftwareRevision, "software_revision"},
+}
+
+// registerDeviceInfoFunction registers the blim.device_info() function
+// Usage: local info, err = blim.device_info()
+// Reads all present Device Information Service (0x180A) characteristics in one call.
+// Returns (table, nil) on success or (nil, error_table) if the service is not available.
+// Absent or unreadable characteristics are omitted from the table.
+func (api *LuaAPI) registerDeviceInfoFunction(L *lua.State) {
+	api.SafePushGoFunction(L, "device_info", func(L *lua.State) int {
+		connection := api.device.GetConnection()
+		if connection == nil {
+			L.RaiseError("no connection available")
+			return 0
+		}
+
+		if _, err := connection.GetService(device.ServiceDeviceInformation); err != nil {
+			L.PushNil()
+			pushLuaError(L, "device_info()", err)
+			return 2
+		}
+
+		// readValue reads a DIS characteristic, returns nil if absent, not readable, or the read fails
+		readValue := func(uuid string) []byte {
+			char, err := connection.GetCharacteristic(device.ServiceDeviceInformation, uuid)
+			if err != nil {
+				return nil
+			}
+			if props := char.GetProperties(); props == nil || props.Read() == nil {
+				return nil
+			}
+			readTimeout, _, _ := api.timeouts()
+			value, err := char.Read(readTimeout)
+			if err != nil {
+				api.logger.WithFields(logrus.Fields{
+					"characteristic": uuid,
+					"error":          err,
+				}).Debug("Failed to read Device Information Service characteristic")
+				return nil
+			}
+			return value
+		}
+
+		L.NewTable()
+
+		// String fields: decoded as UTF-8, raw bytes if not valid text
+		for _, field := range disStringFields {
+			value := readValue(field.uuid)
+			if value == nil {
+				continue
+			}
+			str, ok := device.DecodeUTF8Value(value)
+			if !ok {
+				str = string(value)
+			}
+			L.PushString(field.key)
+			L.PushString(str)
+			L.SetTable(-3)
+		}
+
+		// System ID: 8-byte binary identifier, rendered as hex
+		if value := readValue(device.CharacteristicSystemID); len(value) > 0 {
+			L.PushString("system_id")
+			L.PushString(fmt.Sprintf("%X", value))
+			L.SetTable(-3)
+		}
+
+		// PnP ID: vendor/product identification, vendor name resolved via bledb
+		if value := readValue(device.CharacteristicPnPID); value != nil {
+			pnp, err := device.ParsePnPID(value)
+			if err != nil {
+				api.logger.WithError(err).Debug("Failed to parse PnP ID")
+			} else {
+				L.PushString("pnp_id")
+				L.NewTable()
+
+				L.PushString("vendor_id_source")
+				L.PushInteger(int64(pnp.VendorIDSource))
+				L.SetTable(-3)
+
+				L.PushString("vendor_id")
+				L.PushInteger(int64(pnp.VendorID))
+				L.SetTable(-3)
+
+				if pnp.VendorName != "" {
+					L.PushString("vendor_name")
+					L.PushString(pnp.VendorName)
+					L.SetTable(-3)
+				}
+
+				L.PushString("product_id")
+				L.PushInteger(int64(pnp.ProductID))
+				L.SetTable(-3)
+
+				L.PushString("product_version")
+				L.PushInteger(int64(pnp.ProductVersion))
+				L.SetTable(-3)
+
+				L.SetTable(-3)
+			}
+		}
+
+		L.PushNil()
+		return 2 // (info, nil)
+	})
+	L.SetTable(-3)
+}
+
+// registerReadServiceFunction registers the blim.read_service(service_uuid) function
+// Usage: local values, err = blim.read_service("180a")
+// Reads every readable characteristic of the service over the existing connection.
+// Returns (table, nil) keyed by characteristic UUID, each entry {value=, name=, parsed=, error=},
+// or (nil, error_table) if the service is not available. A failed read sets error instead of value.
+func (api *LuaAPI) registerReadServiceFunction(L *lua.State) {
+	api.SafePushGoFunction(L, "read_service", func(L *lua.State) int {
+		if !L.IsString(1) {
+			L.RaiseError("read_service(service_uuid) expects a string argument")
+			return 0
+		}
+
+		connection := api.device.GetConnection()
+		if connection == nil {
+			L.RaiseError("no connection available")
+			return 0
+		}
+
+		service, err := connection.GetService(L.ToString(1))
+		if err != nil {
+			L.PushNil()
+			pushLuaError(L, "read_service()", err)
+			return 2
+		}
+
+		readTimeout, _, _ := api.timeouts()
+		L.NewTable()
+		for _, char := range service.GetCharacteristics() {
+			if props := char.GetProperties(); props == nil || props.Read() == nil || props.Read().Value() == 0 {
+				continue
+			}
+
+			L.PushString(char.UUID())
+			L.NewTable()
+
+			if knownName := char.KnownName(); knownName != "" {
+				L.PushString("name")
+				L.PushString(knownName)
+				L.SetTable(-3)
+			}
+
+			value, err := char.Read(readTimeout)
+			if err != nil {
+				L.PushString("error")
+				pushLuaError(L, "read_service()", err)
+				L.SetTable(-3)
+			} else {
+				L.PushString("value")
+				L.PushString(string(value))
+				L.SetTable(-3)
+
+				// Parse errors are not read errors: the raw value is still returned
+				if char.HasParser() {
+					if parsed, err := char.ParseValue(value); err == nil && parsed != nil {
+						L.PushString("parsed")
+						api.pushCharacteristicParsedValue(L, parsed)
+						L.SetTable(-3)
+					}
+				}
+			}
+
+			L.SetTable(-3)
+		}
+
+		L.PushNil()
+		return 2 // (values, nil)
+	})
+	L.SetTable(-3)
+}
+
+// multiWrite is a single write of blim.write_multiple()
+type multiWrite struct {
+	service, char string
+	data          []byte
+	withResponse  bool
+}
+
+// registerWriteMultipleFunction registers the blim.write_multiple(writes, [options]) function
+// Usage:
+//
+//	local results, err = blim.write_multiple({
+//	    {service = "fff0", char = "fff1", data = "\x01"},
+//	    {service = "fff0", char = "fff2", data = "\x0A\x00", with_response = false},
+//	}, {stop_on_error = true})
+//
+// Writes each entry in order (with_response defaults to true, like char.write()). All entries are validated before
+// the first write. Returns (results, nil) when every write succeeded, or (results, error_table) of the first failure.
+// results[i] is {service=, char=, ok=true}, {service=, char=, ok=false, error=} or, for writes not attempted
+// after a failure with stop_on_error, {service=, char=, ok=false, skipped=true}.
+func (api *LuaAPI) registerWriteMultipleFunction(L *lua.State) {
+	const usage = "write_multiple(writes, [options]) expects "
+
+	api.SafePushGoFunction(L, "write_multiple", func(L *lua.State) int {
+		if !L.IsTable(1) {
+			L.RaiseError(usage + "an array of writes")
+			return 0
+		}
+
+		stopOnError := false
+		if !L.IsNoneOrNil(2) {
+			if !L.IsTable(2) {
+				L.RaiseError(usage + "a table as options")
+				return 0
+			}
+			L.GetField(2, "stop_on_error")
+			if !L.IsNil(-1) {
+				if !L.IsBoolean(-1) {
+					L.RaiseError(usage + "a boolean as stop_on_error")
+					return 0
+				}
+				stopOnError = L.ToBoolean(-1)
+			}
+			L.Pop(1)
+		}
+
+		var writes []multiWrite
+		for i := 1; ; i++ {
+			L.RawGeti(1, i)
+			if L.IsNil(-1) {
+				L.Pop(1)
+				break
+			}
+			if !L.IsTable(-1) {
+				L.RaiseError(fmt.Sprintf(usage+"a table as write %d", i))
+				return 0
+			}
+
+			w := multiWrite{withResponse: true}
+			for _, field := range []struct {
+				name  string
+				value *string
+			}{{"service", &w.service}, {"char", &w.char}, {"data", new(string)}} {
+				L.GetField(-1, field.name)
+				if !L.IsString(-1) {
+					L.RaiseError(fmt.Sprintf(usage+"a string as %s of write %d", field.name, i))
+					return 0
+				}
+				*field.value = L.ToString(-1)
+				if field.name == "data" {
+					w.data = []byte(*field.value)
+				}
+				L.Pop(1)
+			}
+			L.GetField(-1, "with_response")
+			if !L.IsNil(-1) {
+				if !L.IsBoolean(-1) {
+					L.RaiseError(fmt.Sprintf(usage+"a boolean as with_response of write %d", i))
+					return 0
+				}
+				w.withResponse = L.ToBoolean(-1)
+			}
+			L.Pop(2) // with_response, write table
+
+			writes = append(writes, w)
+		}
+
+		connection := api.device.GetConnection()
+		if connection == nil {
+			L.RaiseError("no connection available")
+			return 0
+		}
+
+		_, writeTimeout, _ := api.timeouts()
+		var firstErr error
+		L.NewTable()
+		for i, w := range writes {
+			L.NewTable()
+			L.PushString(w.service)
+			L.SetField(-2, "service")
+			L.PushString(w.char)
+			L.SetField(-2, "char")
+
+			if firstErr != nil && stopOnError {
+				L.PushBoolean(false)
+				L.SetField(-2, "ok")
+				L.PushBoolean(true)
+				L.SetField(-2, "skipped")
+				L.RawSeti(-2, i+1)
+				continue
+			}
+
+			char, err := connection.GetCharacteristic(w.service, w.char)
+			if err == nil {
+				err = char.Write(w.data, w.withResponse, writeTimeout)
+			}
+			L.PushBoolean(err == nil)
+			L.SetField(-2, "ok")
+			if err != nil {
+				pushLuaError(L, "write_multiple()", err)
+				L.SetField(-2, "error")
+				if firstErr == nil {
+					firstErr = err
+				}
+			}
+			L.RawSeti(-2, i+1)
+		}
+
+		if firstErr != nil {
+			pushLuaError(L, "write_multiple()", firstErr)
+			return 2
+		}
+		L.PushNil()
+		return 2
+	})
+	L.SetTable(-3)
+}
+
+// registerPairFunction registers the blim.pair() function
+// Usage: local ok, err = blim.pair()
+// Triggers pairing/bonding and waits up to DefaultPairingTimeout for completion.
+// Returns (true, nil) on success or (nil, error_table) on failure.
+// IMPORTANT: pair releases the Lua state mutex while waiting, since pairing may require user
+// interaction (system prompt on macOS), allowing subscription callbacks to execute meanwhile.
+func (api *LuaAPI) registerPairFunction(L *lua.State) {
+	api.SafePushGoFunction(L, "pair", func(L *lua.State) int {
+		connection := api.device.GetConnection()
+		if connection == nil {
+			L.RaiseError("no connection available")
+			return 0
+		}
+
+		parentCtx := connection.ConnectionContext()
+		if parentCtx == nil {
+			parentCtx = context.Background()
+		}
+		ctx, cancel := context.WithTimeout(parentCtx, DefaultPairingTimeout)
+		defer cancel()
+
+		// Release mutex to allow callbacks to execute while pairing is pending
+		api.LuaEngine.stateMutex.Unlock()
+		err := connection.Pair(ctx)
+		api.LuaEngine.stateMutex.Lock()
+
+		if err != nil {
+			L.PushNil()
+			pushLuaError(L, "pair()", err)
+			return 2
+		}
+
+		// Reflect bonding state in blim.device.is_paired
+		L.GetGlobal("_blim_internal")
+		if L.IsTable(-1) {
+			L.GetField(-1, "device")
+			if L.IsTable(-1) {
+				L.PushBoolean(true)
+				L.SetField(-2, "is_paired")
+			}
+			L.Pop(1)
+		}
+		L.Pop(1)
+
+		L.PushBoolean(true)
+		L.PushNil()
+		return 2 // (true, nil)
+	})
+	L.SetTable(-3)
+}
+
+// timeoutFields maps blim.set_timeouts()/get_timeouts() table keys to LuaAPI timeout fields
+var timeoutFields = []struct {
+	key   string
+	field func(api *LuaAPI) *time.Duration
+}{
+	{key: "read_ms", field: func(api *LuaAPI) *time.Duration { return &api.characteristicReadTimeout }},
+	{key: "write_ms", field: func(api *LuaAPI) *time.Duration { return &api.characteristicWriteTimeout }},
+	{key: "descriptor_ms", field: func(api *LuaAPI) *time.Duration { return &api.descriptorReadTimeout }},
+}
+
+// pushTimeouts pushes a { read_ms, write_ms, descriptor_ms } table built from the given durations.
+// Stack effect: pushes one table
+func pushTimeouts(L *lua.State, values []time.Duration) {
+	L.NewTable()
+	for i, tf := range timeoutFields {
+		L.PushString(tf.key)
+		L.PushInteger(values[i].Milliseconds())
+		L.SetTable(-3)
+	}
+}
+
+// registerTimeoutsFunctions registers blim.set_timeouts() and blim.get_timeouts()
+// Usage:
+//
+//	local prev = blim.set_timeouts{read_ms = 10000}  -- returns previous settings
+//	blim.set_timeouts(prev)                          -- restore
+//	local t = blim.get_timeouts()                    -- { read_ms, write_ms, descriptor_ms }
+func (api *LuaAPI) registerTimeoutsFunctions(L *lua.State) {
+	api.SafePushGoFunction(L, "set_timeouts", func(L *lua.State) int {
+		if !L.IsTable(1) {
+			L.RaiseError("set_timeouts(table) expects a table argument")
+			return 0
+		}
+
+		// Validate all values before applying any of them
+		updates := make([]time.Duration, len(timeoutFields))
+		for i, tf := range timeoutFields {
+			L.GetField(1, tf.key)
+			if !L.IsNil(-1) {
+				if !L.IsNumber(-1) || L.ToInteger(-1) <= 0 {
+					L.Pop(1)
+					L.RaiseError(fmt.Sprintf("set_timeouts() expects %s to be a positive number", tf.key))
+					return 0
+				}
+				updates[i] = time.Duration(L.ToInteger(-1)) * time.Millisecond
+			}
+			L.Pop(1)
+		}
+
+		api.timeoutsMutex.Lock()
+		previous := make([]time.Duration, len(timeoutFields))
+		for i, tf := range timeoutFields {
+			field := tf.field(api)
+			previous[i] = *field
+			if updates[i] > 0 {
+				*field = updates[i]
+			}
+		}
+		api.timeoutsMutex.Unlock()
+
+		pushTimeouts(L, previous)
+		return 1
+	})
+	L.SetTable(-3)
+
+	api.SafePushGoFunction(L, "get_timeouts", func(L *lua.State) int {
+		read, write, descriptor := api.timeouts()
+		pushTimeouts(L, []time.Duration{read, write, descriptor})
+		return 1
+	})
+	L.SetTable(-3)
+}
+
+// registerConnectionEventFunction registers the blim.on_connection_event() function
+// Usage:
+//
+//	blim.on_connection_event(function(event)
+//	    if event.type == "services_changed" then ... end  -- handles may be stale, rediscover if needed
+//	    if event.type == "disconnected" then ... end      -- event.reason, e.g. "idle"
+//	    if event.type == "rssi" then ... end              -- event.rssi, see blim.set_rssi_interval()
+//	end)
+//	blim.on_connection_event(nil)  -- unregister
+//
+// Only one callback is active at a time; registering a new one replaces the previous callback.
+func (api *LuaAPI) registerConnectionEventFunction(L *lua.State) {
+	api.SafePushGoFunction(L, "on_connection_event", func(L *lua.State) int {
+		var ref = lua.LUA_NOREF
+		if !L.IsNoneOrNil(1) {
+			if !L.IsFunction(1) {
+				L.RaiseError("on_connection_event() expects a function or nil argument")
+				return 0
+			}
+			connection := api.device.GetConnection()
+			if connection == nil {
+				L.RaiseError("no connection available")
+				return 0
+			}
+
+			L.PushValue(1)
+			ref = L.Ref(lua.LUA_REGISTRYINDEX)
+
+			// Handlers cannot be removed from a connection: register them once per connection instance,
+			// and again whenever the device hands out a new one
+			api.connectionEventMutex.Lock()
+			hook := api.connectionEventsHooked != connection
+			api.connectionEventsHooked = connection
+			api.connectionEventMutex.Unlock()
+
+			if hook {
+				connection.OnServicesChanged(func() {
+					api.callConnectionEventCallback(ConnectionEventServicesChanged, "")
+				})
+				connection.OnDisconnected(func(reason string) {
+					api.callConnectionEventCallback(ConnectionEventDisconnected, reason)
+				})
+				connection.OnRSSI(func(rssi int) {
+					api.callConnectionEvent(ConnectionEventRSSI, func(L *lua.State) {
+						L.PushInteger(int64(rssi))
+						L.SetField(-2, "rssi")
+					})
+				})
+			}
+		}
+
+		api.connectionEventMutex.Lock()
+		previous := api.connectionEventRef
+		api.connectionEventRef = ref
+		api.connectionEventMutex.Unlock()
+
+		if previous != lua.LUA_NOREF {
+			L.Unref(lua.LUA_REGISTRYINDEX, previous)
+		}
+		return 0
+	})
+	L.SetTable(-3)
+}
+
+// callConnectionEventCallback calls the blim.on_connection_event() callback with {type = eventType, reason = reason}.
+// reason is omitted when empty.
+func (api *LuaAPI) callConnectionEventCallback(eventType, reason string) {
+	var setFields func(L *lua.State)
+	if reason != "" {
+		setFields = func(L *lua.State) {
+			L.PushString(reason)
+			L.SetField(-2, "reason")
+		}
+	}
+	api.callConnectionEvent(eventType, setFields)
+}
+
+// callConnectionEvent calls the blim.on_connection_event() callback with {type = eventType}; setFields, if not nil,
+// adds event-specific fields to the event table on top of the stack.
+func (api *LuaAPI) callConnectionEvent(eventType string, setFields func(L *lua.State)) {
+	api.connectionEventMutex.Lock()
+	callbackRef := api.connectionEventRef
+	api.connectionEventMutex.Unlock()
+	if callbackRef == lua.LUA_NOREF {
+		return
+	}
+
+	// Connection events arrive on the notification goroutine; never let a Lua failure escape
+	defer func() {
+		if r := recover(); r != nil {
+			api.logger.Errorf("Connection event Lua callback panic (recovered): %v\nStack:\n%s", r, string(debug.Stack()))
+			api.LuaEngine.outputChan.ForceSend(LuaOutputRecord{
+				Content:   fmt.Sprintf("connection event callback error: %v", r),
+				Timestamp: time.Now(),
+				Source:    "stderr",
+			})
+		}
+	}()
+
+	api.LuaEngine.DoWithState(func(L *lua.State) interface{} {
+		L.RawGeti(lua.LUA_REGISTRYINDEX, callbackRef)
+		L.NewTable()
+		L.PushString(eventType)
+		L.SetField(-2, "type")
+		if setFields != nil {
+			setFields(L)
+		}
+
+		if err := L.Call(1, 0); err != nil {
+			api.logger.Errorf("Connection event Lua callback execution failed: %v", err)
+			api.LuaEngine.outputChan.ForceSend(LuaOutputRecord{
+				Content:   fmt.Sprintf("connection event callback error: %v", err),
+				Timestamp: time.Now(),
+				Source:    "stderr",
+			})
+			// Reset the stack after a failed call so the next callback starts clean
+			L.SetTop(0)
+		}
+		return nil
+	})
+}
+
+// registerRawNotificationFunction registers the blim.on_raw_notification() function
+// Usage:
+//
+//	blim.on_raw_notification(function(uuid, bytes, ts_us)
+//	    io.stderr:write(uuid .. " " .. blim.to_hex(bytes) .. "\n")
+//	end)
+//	blim.on_raw_notification(nil)  -- unregister
+//
+// The callback sees every incoming notification of any subscribed characteristic before subscription modes
+// batch or aggregate it. Only one callback is active at a time; registering a new one replaces the previous callback.
+func (api *LuaAPI) registerRawNotificationFunction(L *lua.State) {
+	api.SafePushGoFunction(L, "on_raw_notification", func(L *lua.State) int {
+		var ref = lua.LUA_NOREF
+		if !L.IsNoneOrNil(1) {
+			if !L.IsFunction(1) {
+				L.RaiseError("on_raw_notification() expects a function or nil argument")
+				return 0
+			}
+			connection := api.device.GetConnection()
+			if connection == nil {
+				L.RaiseError("no connection available")
+				return 0
+			}
+
+			L.PushValue(1)
+			ref = L.Ref(lua.LUA_REGISTRYINDEX)
+
+			// Register once per connection instance, as for blim.on_connection_event()
+			api.rawNotificationMutex.Lock()
+			hook := api.rawNotificationHooked != connection
+			api.rawNotificationHooked = connection
+			api.rawNotificationMutex.Unlock()
+
+			if hook {
+				connection.OnRawNotification(api.callRawNotificationCallback)
+			}
+		}
+
+		api.rawNotificationMutex.Lock()
+		previous := api.rawNotificationRef
+		api.rawNotificationRef = ref
+		api.rawNotificationMutex.Unlock()
+
+		if previous != lua.LUA_NOREF {
+			L.Unref(lua.LUA_REGISTRYINDEX, previous)
+		}
+		return 0
+	})
+	L.SetTable(-3)
+}
+
+// callRawNotificationCallback calls the blim.on_raw_notification() callback with (uuid, bytes, ts_us)
+func (api *LuaAPI) callRawNotificationCallback(uuid string, data []byte, tsUs int64) {
+	api.rawNotificationMutex.Lock()
+	callbackRef := api.rawNotificationRef
+	api.rawNotificationMutex.Unlock()
+	if callbackRef == lua.LUA_NOREF {
+		return
+	}
+
+	// Raw notifications arrive on the notification goroutine; never let a Lua failure escape
+	defer func() {
+		if r := recover(); r != nil {
+			api.logger.Errorf("Raw notification Lua callback panic (recovered): %v\nStack:\n%s", r, string(debug.Stack()))
+			api.LuaEngine.outputChan.ForceSend(LuaOutputRecord{
+				Content:   fmt.Sprintf("raw notification callback error: %v", r),
+				Timestamp: time.Now(),
+				Source:    "stderr",
+			})
+		}
+	}()
+
+	api.LuaEngine.DoWithState(func(L *lua.State) interface{} {
+		L.RawGeti(lua.LUA_REGISTRYINDEX, callbackRef)
+		L.PushString(uuid)
+		L.PushString(string(data))
+		L.PushInteger(tsUs)
+
+		if err := L.Call(3, 0); err != nil {
+			api.logger.Errorf("Raw notification Lua callback execution failed: %v", err)
+			api.LuaEngine.outputChan.ForceSend(LuaOutputRecord{
+				Content:   fmt.Sprintf("raw notification callback error: %v", err),
+				Timestamp: time.Now(),
+				Source:    "stderr",
+			})
+			// Reset the stack after a failed call so the next callback starts clean
+			L.SetTop(0)
+		}
+		return nil
+	})
+}
+
+// parserOverrideKey returns the blim.set_parser_override() key of a service+characteristic pair
+func parserOverrideKey(service, char string) string {
+	return device.NormalizeUUID(service) + "/" + device.NormalizeUUID(char)
+}
+
+// registerParserOverrideFunction registers the blim.set_parser_override() function
+// Usage:
+//
+//	blim.set_parser_override("fff0", "2a37", function(bytes) return {bpm = string.byte(bytes, 3)} end)
+//	blim.set_parser_override("fff0", "2a37", nil)  -- remove
+//
+// The parser applies to char:parse() of that characteristic in that service only, taking precedence over the
+// built-in parser of the UUID. Handles created before the override was set without a built-in parser have no parse().
+func (api *LuaAPI) registerParserOverrideFunction(L *lua.State) {
+	api.SafePushGoFunction(L, "set_parser_override", func(L *lua.State) int {
+		if !L.IsString(1) || !L.IsString(2) || (!L.IsNoneOrNil(3) && !L.IsFunction(3)) {
+			L.RaiseError("set_parser_override(service, char, parser) expects two string arguments and a function or nil")
+			return 0
+		}
+		key := parserOverrideKey(L.ToString(1), L.ToString(2))
+
+		if previous, ok := api.parserOverrides[key]; ok {
+			L.Unref(lua.LUA_REGISTRYINDEX, previous)
+			delete(api.parserOverrides, key)
+		}
+		if L.IsFunction(3) {
+			L.PushValue(3)
+			api.parserOverrides[key] = L.Ref(lua.LUA_REGISTRYINDEX)
+		}
+		return 0
+	})
+	L.SetTable(-3)
+}
+
+// callParserOverride calls the override parser ref with value and leaves its result as the parse() result:
+// the returned value, or nil plus an error table when the parser raised an error.
+func (api *LuaAPI) callParserOverride(L *lua.State, ref int, value []byte) int {
+	L.RawGeti(lua.LUA_REGISTRYINDEX, ref)
+	L.PushString(string(value))
+	if err := L.Call(1, 1); err != nil {
+		L.PushNil()
+		pushLuaError(L, "parse()", err)
+		return 2
+	}
+	return 1
+}
+
+// registerSleepFunction registers the blim.sleep() utility function
+// Usage: blim.sleep(milliseconds)
+// Sleeps for the specified number of milliseconds.
+// IMPORTANT: sleep releases the Lua state mutex during sleep to allow subscription
+// callbacks to execute. This enables polling loops to receive BLE notifications.
+func (api *LuaAPI) registerSleepFunction(L *lua.State) {
+	api.SafePushGoFunction(L, "sleep", func(L *lua.State) int {
+		// Validate argument
 		if !L.IsNumber(1) {
 			L.RaiseError("sleep(milliseconds) expects a number argument")
 			return 0
@@ -1163,9 +2893,251 @@ func (api *LuaAPI) registerSleepFunction(L *lua.State) {
 		// Reacquire mutex before returning to Lua
 		api.LuaEngine.stateMutex.Lock()
 
-		return 0
-	})
-	L.SetTable(-3)
+		return 0
+	})
+	L.SetTable(-3)
+}
+
+// registerYieldFunction registers the blim.yield() utility function
+// Usage: blim.yield()
+// Releases and immediately reacquires the Lua state mutex without sleeping. The state lock is FIFO,
+// so callbacks already waiting for the state run before the script continues.
+// IMPORTANT: callbacks run between the release and the return of yield, so script globals they modify
+// may change across the call, exactly as with sleep.
+func (api *LuaAPI) registerYieldFunction(L *lua.State) {
+	api.SafePushGoFunction(L, "yield", func(L *lua.State) int {
+		api.LuaEngine.stateMutex.Unlock()
+
+		// Let callback goroutines that are about to block on the state lock queue up before reacquiring
+		runtime.Gosched()
+
+		api.LuaEngine.stateMutex.Lock()
+		return 0
+	})
+	L.SetTable(-3)
+}
+
+// registerScanFunction registers the blim.scan() function
+// Usage: local devices, err = blim.scan{duration_ms = 5000, services = {"Heart Rate", "feaa"}}
+// Scans for advertisers and returns an array of {address, name, rssi, connectable, services} tables,
+// one per address (the latest advertisement wins), in discovery order. services (names or UUIDs)
+// restricts results to advertisers including at least one of the given services.
+// Optional callbacks: on_device(dev) fires once per newly discovered address while scanning,
+// on_complete(devices) fires once at the end with the same array scan returns.
+// IMPORTANT: like sleep, scan releases the Lua state mutex while scanning so subscription callbacks keep running.
+func (api *LuaAPI) registerScanFunction(L *lua.State) {
+	api.SafePushGoFunction(L, "scan", func(L *lua.State) int {
+		duration := DefaultScanDuration
+		var services []string
+		onDeviceRef, onCompleteRef := lua.LUA_NOREF, lua.LUA_NOREF
+		defer func() {
+			for _, ref := range []int{onDeviceRef, onCompleteRef} {
+				if ref != lua.LUA_NOREF {
+					L.Unref(lua.LUA_REGISTRYINDEX, ref)
+				}
+			}
+		}()
+
+		if !L.IsNoneOrNil(1) {
+			if !L.IsTable(1) {
+				L.RaiseError("scan([options]) expects a table as options")
+				return 0
+			}
+
+			L.GetField(1, "duration_ms")
+			if !L.IsNil(-1) {
+				if !L.IsNumber(-1) || L.ToInteger(-1) <= 0 {
+					L.RaiseError("scan([options]) expects a positive number as duration_ms")
+					return 0
+				}
+				duration = time.Duration(L.ToInteger(-1)) * time.Millisecond
+			}
+			L.Pop(1)
+
+			L.GetField(1, "services")
+			if !L.IsNil(-1) {
+				if !L.IsTable(-1) {
+					L.RaiseError("scan([options]) expects an array of service names or UUIDs as services")
+					return 0
+				}
+				for i := 1; ; i++ {
+					L.RawGeti(-1, i)
+					if L.IsNil(-1) {
+						L.Pop(1)
+						break
+					}
+					if !L.IsString(-1) {
+						L.RaiseError("scan([options]) expects an array of service names or UUIDs as services")
+						return 0
+					}
+					services = append(services, device.ResolveServiceUUID(L.ToString(-1)))
+					L.Pop(1)
+				}
+			}
+			L.Pop(1)
+
+			for _, cb := range []struct {
+				field string
+				ref   *int
+			}{{"on_device", &onDeviceRef}, {"on_complete", &onCompleteRef}} {
+				L.GetField(1, cb.field)
+				if L.IsNil(-1) {
+					L.Pop(1)
+					continue
+				}
+				if !L.IsFunction(-1) {
+					L.RaiseError(fmt.Sprintf("scan([options]) expects a function as %s", cb.field))
+					return 0
+				}
+				*cb.ref = L.Ref(lua.LUA_REGISTRYINDEX) // Pops the function
+			}
+		}
+
+		var onDevice func(device.Advertisement)
+		if onDeviceRef != lua.LUA_NOREF {
+			onDevice = func(adv device.Advertisement) {
+				api.callScanCallback("on_device", onDeviceRef, func(L *lua.State) {
+					api.pushAdvertisement(L, adv)
+				})
+			}
+		}
+
+		// Release mutex to allow callbacks to execute during the scan
+		api.LuaEngine.stateMutex.Unlock()
+		adverts, err := api.scan(duration, services, onDevice)
+		api.LuaEngine.stateMutex.Lock()
+
+		if err != nil {
+			L.PushNil()
+			pushLuaError(L, "scan()", err)
+			return 2
+		}
+
+		L.NewTable()
+		for i, adv := range adverts {
+			L.PushInteger(int64(i + 1)) // Lua arrays are 1-indexed
+			api.pushAdvertisement(L, adv)
+			L.SetTable(-3)
+		}
+
+		if onCompleteRef != lua.LUA_NOREF {
+			// The state mutex is held again, so call directly; the result array stays on the stack for the return
+			devicesIdx := L.GetTop()
+			api.protectedScanCallback(L, "on_complete", onCompleteRef, func(L *lua.State) {
+				L.PushValue(devicesIdx)
+			})
+		}
+		return 1
+	})
+	L.SetTable(-3)
+}
+
+// callScanCallback calls a blim.scan() callback from the scan goroutine, acquiring the Lua state
+func (api *LuaAPI) callScanCallback(name string, callbackRef int, pushArg func(L *lua.State)) {
+	api.LuaEngine.DoWithState(func(L *lua.State) interface{} {
+		api.protectedScanCallback(L, name, callbackRef, pushArg)
+		return nil
+	})
+}
+
+// protectedScanCallback calls a blim.scan() callback with one argument; the caller must hold the Lua state.
+// Lua errors and Go panics are reported to stderr and never abort the scan.
+func (api *LuaAPI) protectedScanCallback(L *lua.State, name string, callbackRef int, pushArg func(L *lua.State)) {
+	// Restore the stack on failure so the blim.scan() frame (options table, result array) stays intact
+	top := L.GetTop()
+	defer func() {
+		if r := recover(); r != nil {
+			api.logger.Errorf("Scan %s Lua callback panic (recovered): %v\nStack:\n%s", name, r, string(debug.Stack()))
+			api.reportScanCallbackError(name, r)
+			L.SetTop(top)
+		}
+	}()
+
+	L.RawGeti(lua.LUA_REGISTRYINDEX, callbackRef)
+	pushArg(L)
+	if err := L.Call(1, 0); err != nil {
+		api.logger.Errorf("Scan %s Lua callback execution failed: %v", name, err)
+		api.reportScanCallbackError(name, err)
+		L.SetTop(top)
+	}
+}
+
+// reportScanCallbackError sends a blim.scan() callback failure to the script's stderr
+func (api *LuaAPI) reportScanCallbackError(name string, cause interface{}) {
+	api.LuaEngine.outputChan.ForceSend(LuaOutputRecord{
+		Content:   fmt.Sprintf("scan %s callback error: %v", name, cause),
+		Timestamp: time.Now(),
+		Source:    "stderr",
+	})
+}
+
+// scan collects advertisements for the given duration, deduplicated by address in discovery order.
+// Advertisers not including any of services are skipped (no filtering if services is empty).
+// onDevice (optional) is called once per newly discovered address.
+func (api *LuaAPI) scan(duration time.Duration, services []string, onDevice func(device.Advertisement)) ([]device.Advertisement, error) {
+	scanDevice, err := devicefactory.DeviceFactory()
+	if err != nil {
+		return nil, fmt.Errorf("failed to create BLE scanner: %w", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), duration)
+	defer cancel()
+
+	var (
+		mu      sync.Mutex
+		order   []string
+		adverts = make(map[string]device.Advertisement)
+	)
+	err = scanDevice.Scan(ctx, false, func(adv device.Advertisement) {
+		if len(services) > 0 && !device.ContainsAnyUUID(adv.Services(), services) {
+			return
+		}
+		mu.Lock()
+		_, seen := adverts[adv.Addr()]
+		if !seen {
+			order = append(order, adv.Addr())
+		}
+		adverts[adv.Addr()] = adv
+		mu.Unlock()
+
+		if !seen && onDevice != nil {
+			onDevice(adv)
+		}
+	})
+	if err != nil && !errors.Is(err, context.Canceled) && !errors.Is(err, context.DeadlineExceeded) {
+		return nil, fmt.Errorf("scan failed: %w", err)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	result := make([]device.Advertisement, 0, len(order))
+	for _, addr := range order {
+		result = append(result, adverts[addr])
+	}
+	return result, nil
+}
+
+// pushAdvertisement pushes an advertisement onto the Lua stack as a table
+// with address, name, rssi, connectable, and services fields.
+// Stack effect: pushes one table
+func (api *LuaAPI) pushAdvertisement(L *lua.State, adv device.Advertisement) {
+	L.NewTable()
+	L.PushString(adv.Addr())
+	L.SetField(-2, "address")
+	L.PushString(adv.LocalName())
+	L.SetField(-2, "name")
+	L.PushInteger(int64(adv.RSSI()))
+	L.SetField(-2, "rssi")
+	L.PushBoolean(adv.Connectable())
+	L.SetField(-2, "connectable")
+
+	L.NewTable()
+	for i, uuid := range adv.Services() {
+		L.PushInteger(int64(i + 1))
+		L.PushString(device.NormalizeUUID(uuid))
+		L.SetTable(-3)
+	}
+	L.SetField(-2, "services")
 }
 
 // pushDescriptor pushes a descriptor object onto the Lua stack as a table.
@@ -1297,6 +3269,196 @@ func (api *LuaAPI) pushDescriptorParsedValue(L *lua.State, parsedValue interface
 	}
 }
 
+// pushCharacteristicParsedValue pushes a parsed characteristic value onto the Lua stack.
+// Handles all known characteristic parser results (Appearance name, scalar measurements, Date Time, Current Time,
+// Location and Speed, Weight Measurement, enums).
+// Stack effect: pushes one value (string, number, table, or nil)
+func (api *LuaAPI) pushCharacteristicParsedValue(L *lua.State, parsedValue interface{}) {
+	switch v := parsedValue.(type) {
+	case string:
+		// Appearance - push as a plain string
+		L.PushString(v)
+
+	case float64:
+		// Scalar measurements (Temperature, ...) - push as a number in the characteristic's unit
+		L.PushNumber(v)
+
+	case *device.DateTime:
+		// Push DateTime as {year, month, day, hours, minutes, seconds, rfc3339}
+		api.pushDateTime(L, v)
+
+	case *device.CurrentTime:
+		// Push CurrentTime as {datetime={...}, day_of_week, fractions256, adjust_reason={...}, rfc3339}
+		L.NewTable()
+		L.PushString("datetime")
+		api.pushDateTime(L, &v.DateTime)
+		L.SetTable(-3)
+		L.PushString("day_of_week")
+		L.PushInteger(int64(v.DayOfWeek))
+		L.SetTable(-3)
+		L.PushString("fractions256")
+		L.PushInteger(int64(v.Fractions256))
+		L.SetTable(-3)
+
+		L.PushString("adjust_reason")
+		L.NewTable()
+		L.PushString("manual")
+		L.PushBoolean(v.Manual())
+		L.SetTable(-3)
+		L.PushString("external")
+		L.PushBoolean(v.External())
+		L.SetTable(-3)
+		L.PushString("timezone")
+		L.PushBoolean(v.TimezoneChanged())
+		L.SetTable(-3)
+		L.PushString("dst")
+		L.PushBoolean(v.DSTChanged())
+		L.SetTable(-3)
+		L.SetTable(-3)
+
+		if s := v.RFC3339(); s != "" {
+			L.PushString("rfc3339")
+			L.PushString(s)
+			L.SetTable(-3)
+		}
+
+	case *device.LocationAndSpeed:
+		api.pushLocationAndSpeed(L, v)
+
+	case *device.WeightMeasurement:
+		api.pushWeightMeasurement(L, v)
+
+	case *device.EnumValue:
+		// Push enum characteristics (Alert Level, Body Sensor Location, ...) as {value, name}
+		L.NewTable()
+		L.PushInteger(int64(v.Value))
+		L.SetField(-2, "value")
+		L.PushString(v.Name)
+		L.SetField(-2, "name")
+
+	case *device.BitmaskValue:
+		// Push bitmask characteristics (alert categories, ...) as an array of the set bit names
+		L.NewTable()
+		for i, name := range v.Names {
+			L.PushString(name)
+			L.RawSeti(-2, i+1)
+		}
+
+	default:
+		// Fallback for unexpected types - push nil
+		L.PushNil()
+	}
+}
+
+// pushLocationAndSpeed pushes a Location and Speed value as {flags, position_status, speed_distance_3d,
+// elevation_source, heading_source} plus the present fields among speed, total_distance, latitude, longitude,
+// elevation, heading, rolling_time and utc_time (Date Time table).
+// Stack effect: pushes one value (table)
+func (api *LuaAPI) pushLocationAndSpeed(L *lua.State, ls *device.LocationAndSpeed) {
+	L.NewTable()
+	L.PushInteger(int64(ls.Flags))
+	L.SetField(-2, "flags")
+	L.PushString(ls.PositionStatus())
+	L.SetField(-2, "position_status")
+	L.PushBoolean(ls.SpeedDistance3D())
+	L.SetField(-2, "speed_distance_3d")
+	L.PushString(ls.ElevationSource())
+	L.SetField(-2, "elevation_source")
+	L.PushString(ls.HeadingSource())
+	L.SetField(-2, "heading_source")
+
+	for _, field := range []struct {
+		name  string
+		value *float64
+	}{
+		{"speed", ls.Speed},
+		{"total_distance", ls.TotalDistance},
+		{"latitude", ls.Latitude},
+		{"longitude", ls.Longitude},
+		{"elevation", ls.Elevation},
+		{"heading", ls.Heading},
+	} {
+		if field.value != nil {
+			L.PushNumber(*field.value)
+			L.SetField(-2, field.name)
+		}
+	}
+
+	if ls.RollingTime != nil {
+		L.PushInteger(int64(*ls.RollingTime))
+		L.SetField(-2, "rolling_time")
+	}
+	if ls.UTCTime != nil {
+		api.pushDateTime(L, ls.UTCTime)
+		L.SetField(-2, "utc_time")
+	}
+}
+
+// pushWeightMeasurement pushes a Weight Measurement value as {flags, units} plus the present fields among
+// weight (kg), timestamp (Date Time table), user_id, bmi and height (m).
+// Stack effect: pushes one value (table)
+func (api *LuaAPI) pushWeightMeasurement(L *lua.State, wm *device.WeightMeasurement) {
+	L.NewTable()
+	L.PushInteger(int64(wm.Flags))
+	L.SetField(-2, "flags")
+	units := "si"
+	if wm.Imperial() {
+		units = "imperial"
+	}
+	L.PushString(units)
+	L.SetField(-2, "units")
+
+	for _, field := range []struct {
+		name  string
+		value *float64
+	}{
+		{"weight", wm.Weight},
+		{"bmi", wm.BMI},
+		{"height", wm.Height},
+	} {
+		if field.value != nil {
+			L.PushNumber(*field.value)
+			L.SetField(-2, field.name)
+		}
+	}
+
+	if wm.UserID != nil {
+		L.PushInteger(int64(*wm.UserID))
+		L.SetField(-2, "user_id")
+	}
+	if wm.Timestamp != nil {
+		api.pushDateTime(L, wm.Timestamp)
+		L.SetField(-2, "timestamp")
+	}
+}
+
+// pushDateTime pushes a Date Time value as a table; rfc3339 is omitted when the date is not fully known.
+// Stack effect: pushes one value (table)
+func (api *LuaAPI) pushDateTime(L *lua.State, dt *device.DateTime) {
+	L.NewTable()
+	for _, field := range []struct {
+		name  string
+		value int64
+	}{
+		{"year", int64(dt.Year)},
+		{"month", int64(dt.Month)},
+		{"day", int64(dt.Day)},
+		{"hours", int64(dt.Hours)},
+		{"minutes", int64(dt.Minutes)},
+		{"seconds", int64(dt.Seconds)},
+	} {
+		L.PushString(field.name)
+		L.PushInteger(field.value)
+		L.SetTable(-3)
+	}
+
+	if s := dt.RFC3339(); s != "" {
+		L.PushString("rfc3339")
+		L.PushString(s)
+		L.SetTable(-3)
+	}
+}
+
 // pushManufacturerParsedData pushes parsed manufacturer data onto the Lua stack as a table.
 // Handles all known manufacturer data types (BlimManufacturerData, etc.).
 // If parsedData implements the VendorInfo interface, vendor info is included first.
@@ -1350,3 +3512,347 @@ func (api *LuaAPI) Close() {
 		api.logger.WithField("lua_api_ptr", fmt.Sprintf("%p", api)).Debug("Lua api closed")
 	}
 }
+
+// registerSnapshotSubscriptionsFunction registers blim.snapshot_subscriptions(), which returns the enabled CCCDs
+// as a table compatible with blim.subscribe(): { services = { {service=..., chars={...}, indicate=bool}, ... } }.
+// blim.restore_subscriptions() (blim.lua) re-subscribes from such a snapshot.
+func (api *LuaAPI) registerSnapshotSubscriptionsFunction(L *lua.State) {
+	api.SafePushGoFunction(L, "snapshot_subscriptions", func(L *lua.State) int {
+		connection := api.device.GetConnection()
+		if connection == nil {
+			L.RaiseError("no connection available")
+			return 0
+		}
+
+		// Group characteristics by service and mode (states are sorted by service)
+		type group struct {
+			service  string
+			indicate bool
+			chars    []string
+		}
+		var groups []*group
+		for _, state := range connection.EnabledCCCDs() {
+			var g *group
+			for _, existing := range groups {
+				if existing.service == state.Service && existing.indicate == state.Indicate {
+					g = existing
+					break
+				}
+			}
+			if g == nil {
+				g = &group{service: state.Service, indicate: state.Indicate}
+				groups = append(groups, g)
+			}
+			g.chars = append(g.chars, state.Characteristic)
+		}
+
+		L.NewTable() // snapshot
+		L.PushString("services")
+		L.NewTable()
+		for i, g := range groups {
+			L.PushInteger(int64(i + 1))
+			L.NewTable()
+
+			L.PushString("service")
+			L.PushString(g.service)
+			L.SetTable(-3)
+
+			L.PushString("chars")
+			L.NewTable()
+			for j, char := range g.chars {
+				L.PushInteger(int64(j + 1))
+				L.PushString(char)
+				L.SetTable(-3)
+			}
+			L.SetTable(-3)
+
+			L.PushString("indicate")
+			L.PushBoolean(g.indicate)
+			L.SetTable(-3)
+
+			L.SetTable(-3) // services[i] = entry
+		}
+		L.SetTable(-3) // snapshot.services = services
+		return 1
+	})
+	L.SetTable(-3)
+}
+
+// registerEnabledNotificationsFunction registers blim.enabled_notifications(), which returns the UUIDs of the
+// characteristics with notifications or indications enabled at the CCCD level
+func (api *LuaAPI) registerEnabledNotificationsFunction(L *lua.State) {
+	api.SafePushGoFunction(L, "enabled_notifications", func(L *lua.State) int {
+		connection := api.device.GetConnection()
+		if connection == nil {
+			L.RaiseError("no connection available")
+			return 0
+		}
+
+		L.NewTable()
+		for i, uuid := range connection.EnabledNotifications() {
+			L.PushInteger(int64(i + 1))
+			L.PushString(uuid)
+			L.SetTable(-3)
+		}
+		return 1
+	})
+	L.SetTable(-3)
+}
+
+// registerSubscriptionStatsFunction registers blim.subscription_stats(), which returns the connection-wide delivery
+// counters and, per characteristic, the intervals between consecutive notifications in milliseconds:
+//
+//	{delivered = 120, rate_limit_dropped = 0, coalesced = 0,
+//	 intervals = {["2a37"] = {count = 119, min_ms = 980.1, max_ms = 2011.7, mean_ms = 1003.2, stddev_ms = 92.4}}}
+func (api *LuaAPI) registerSubscriptionStatsFunction(L *lua.State) {
+	api.SafePushGoFunction(L, "subscription_stats", func(L *lua.State) int {
+		connection := api.device.GetConnection()
+		if connection == nil {
+			L.RaiseError("no connection available")
+			return 0
+		}
+		stats := connection.SubscriptionStats()
+		ms := func(d time.Duration) float64 { return float64(d) / float64(time.Millisecond) }
+
+		L.NewTable()
+		L.PushInteger(int64(stats.Delivered))
+		L.SetField(-2, "delivered")
+		L.PushInteger(int64(stats.RateLimitDropped))
+		L.SetField(-2, "rate_limit_dropped")
+		L.PushInteger(int64(stats.Coalesced))
+		L.SetField(-2, "coalesced")
+
+		L.NewTable()
+		for uuid, iv := range stats.Intervals {
+			L.NewTable()
+			L.PushInteger(int64(iv.Count))
+			L.SetField(-2, "count")
+			L.PushNumber(ms(iv.Min))
+			L.SetField(-2, "min_ms")
+			L.PushNumber(ms(iv.Max))
+			L.SetField(-2, "max_ms")
+			L.PushNumber(ms(iv.Mean))
+			L.SetField(-2, "mean_ms")
+			L.PushNumber(ms(iv.StdDev))
+			L.SetField(-2, "stddev_ms")
+			L.SetField(-2, uuid)
+		}
+		L.SetField(-2, "intervals")
+		return 1
+	})
+	L.SetTable(-3)
+}
+
+// registerFlushWritesFunction registers blim.flush_writes([timeout_ms]), which blocks until all pending writes,
+// including write-without-response commands, have been delivered. Defaults to the characteristic write timeout.
+func (api *LuaAPI) registerFlushWritesFunction(L *lua.State) {
+	api.SafePushGoFunction(L, "flush_writes", func(L *lua.State) int {
+		_, timeout, _ := api.timeouts()
+		if !L.IsNoneOrNil(1) {
+			if !L.IsNumber(1) || L.ToInteger(1) <= 0 {
+				L.RaiseError("flush_writes([timeout_ms]) expects a positive number argument")
+				return 0
+			}
+			timeout = time.Duration(L.ToInteger(1)) * time.Millisecond
+		}
+		connection := api.device.GetConnection()
+		if connection == nil {
+			L.RaiseError("no connection available")
+			return 0
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), timeout)
+		defer cancel()
+		if err := connection.FlushWrites(ctx); err != nil {
+			L.PushNil()
+			pushLuaError(L, "flush_writes()", err)
+			return 2
+		}
+		L.PushBoolean(true)
+		return 1
+	})
+	L.SetTable(-3)
+}
+
+// registerIdleTimeoutFunction registers blim.set_idle_timeout(ms), which auto-disconnects the connection after
+// ms milliseconds without reads, writes or notifications (0 disables). The disconnect is reported to the
+// blim.on_connection_event() callback as {type = "disconnected", reason = "idle"}.
+func (api *LuaAPI) registerIdleTimeoutFunction(L *lua.State) {
+	api.SafePushGoFunction(L, "set_idle_timeout", func(L *lua.State) int {
+		if !L.IsNumber(1) || L.ToInteger(1) < 0 {
+			L.RaiseError("set_idle_timeout(milliseconds) expects a non-negative number argument")
+			return 0
+		}
+		connection := api.device.GetConnection()
+		if connection == nil {
+			L.RaiseError("no connection available")
+			return 0
+		}
+
+		if err := connection.SetIdleTimeout(time.Duration(L.ToInteger(1)) * time.Millisecond); err != nil {
+			L.PushNil()
+			pushLuaError(L, "set_idle_timeout()", err)
+			return 2
+		}
+		L.PushBoolean(true)
+		return 1
+	})
+	L.SetTable(-3)
+}
+
+// registerRSSIFunctions registers blim.read_rssi(), which returns the signal strength (dBm) of the connection,
+// and blim.set_rssi_interval(ms), which polls it every ms milliseconds (0 disables). Polled readings are reported
+// to the blim.on_connection_event() callback as {type = "rssi", rssi = -60}.
+func (api *LuaAPI) registerRSSIFunctions(L *lua.State) {
+	api.SafePushGoFunction(L, "read_rssi", func(L *lua.State) int {
+		connection := api.device.GetConnection()
+		if connection == nil {
+			L.RaiseError("no connection available")
+			return 0
+		}
+
+		rssi, err := connection.ReadRSSI()
+		if err != nil {
+			L.PushNil()
+			pushLuaError(L, "read_rssi()", err)
+			return 2
+		}
+		L.PushInteger(int64(rssi))
+		return 1
+	})
+	L.SetTable(-3)
+
+	api.SafePushGoFunction(L, "set_rssi_interval", func(L *lua.State) int {
+		if !L.IsNumber(1) || L.ToInteger(1) < 0 {
+			L.RaiseError("set_rssi_interval(milliseconds) expects a non-negative number argument")
+			return 0
+		}
+		connection := api.device.GetConnection()
+		if connection == nil {
+			L.RaiseError("no connection available")
+			return 0
+		}
+
+		if err := connection.SetRSSIPollInterval(time.Duration(L.ToInteger(1)) * time.Millisecond); err != nil {
+			L.PushNil()
+			pushLuaError(L, "set_rssi_interval()", err)
+			return 2
+		}
+		L.PushBoolean(true)
+		return 1
+	})
+	L.SetTable(-3)
+}
+
+// registerEstimateDistanceFunction registers blim.estimate_distance(rssi, tx_power[, n]), returning the
+// coarse distance in meters from the log-distance path-loss model (see device.EstimateDistance).
+// n is the environmental path-loss exponent, 2 (free space) by default.
+func (api *LuaAPI) registerEstimateDistanceFunction(L *lua.State) {
+	api.SafePushGoFunction(L, "estimate_distance", func(L *lua.State) int {
+		if !L.IsNumber(1) || !L.IsNumber(2) {
+			L.RaiseError("estimate_distance(rssi, tx_power[, n]) expects numeric rssi and tx_power arguments")
+			return 0
+		}
+		n := device.DefaultPathLossExponent
+		if !L.IsNoneOrNil(3) {
+			if !L.IsNumber(3) {
+				L.RaiseError("estimate_distance(rssi, tx_power[, n]) expects a numeric n")
+				return 0
+			}
+			n = L.ToNumber(3)
+		}
+
+		distance, err := device.EstimateDistance(int(L.ToInteger(1)), int(L.ToInteger(2)), n)
+		if err != nil {
+			L.RaiseError("estimate_distance(): " + err.Error())
+			return 0
+		}
+		L.PushNumber(distance)
+		return 1
+	})
+	L.SetTable(-3)
+}
+
+// registerSimulateNotificationFunction registers blim.simulate_notification(service, char, bytes).
+// When enabled via SetSimulatedNotifications, bytes are fed through the same dispatch path as a real
+// notification and true is returned; otherwise the call is a no-op returning false.
+// Like sleep, it releases the Lua state mutex while injecting so handlers needing the state can't deadlock.
+func (api *LuaAPI) registerSimulateNotificationFunction(L *lua.State) {
+	api.SafePushGoFunction(L, "simulate_notification", func(L *lua.State) int {
+		if !L.IsString(1) || !L.IsString(2) || !L.IsString(3) {
+			L.RaiseError("simulate_notification(service, char, bytes) expects three string arguments")
+			return 0
+		}
+		service, uuid, data := L.ToString(1), L.ToString(2), []byte(L.ToString(3))
+
+		if !api.simulatedNotifications.Load() {
+			api.logger.WithField("characteristic", uuid).Warn("blim.simulate_notification() ignored: simulated notifications are not enabled")
+			L.PushBoolean(false)
+			return 1
+		}
+
+		connection := api.device.GetConnection()
+		if connection == nil {
+			L.RaiseError("no connection available")
+			return 0
+		}
+		simulator, ok := connection.(notificationSimulator)
+		if !ok {
+			L.RaiseError("simulate_notification() is not supported by this connection")
+			return 0
+		}
+
+		api.LuaEngine.stateMutex.Unlock()
+		err := simulator.SimulateNotification(service, uuid, data)
+		api.LuaEngine.stateMutex.Lock()
+
+		if err != nil {
+			L.PushNil()
+			pushLuaError(L, "simulate_notification()", err)
+			return 2
+		}
+		L.PushBoolean(true)
+		return 1
+	})
+	L.SetTable(-3)
+}
+
+// luaLogLevels maps the level names accepted by blim.set_log_level() to logrus levels
+var luaLogLevels = map[string]logrus.Level{
+	"debug": logrus.DebugLevel,
+	"info":  logrus.InfoLevel,
+	"warn":  logrus.WarnLevel,
+	"error": logrus.ErrorLevel,
+}
+
+// luaLogLevelName returns the blim.get_log_level() name of a logrus level ("warn" rather than logrus' "warning")
+func luaLogLevelName(level logrus.Level) string {
+	if level == logrus.WarnLevel {
+		return "warn"
+	}
+	return level.String()
+}
+
+// registerLogLevelFunctions registers blim.set_log_level(level) and blim.get_log_level(), bound to the shared logger.
+// set_log_level accepts "debug", "info", "warn" or "error" and returns the previous level.
+func (api *LuaAPI) registerLogLevelFunctions(L *lua.State) {
+	api.SafePushGoFunction(L, "set_log_level", func(L *lua.State) int {
+		level, ok := luaLogLevels[strings.ToLower(L.ToString(1))]
+		if !L.IsString(1) || !ok {
+			L.RaiseError("set_log_level(level) expects one of \"debug\", \"info\", \"warn\", \"error\"")
+			return 0
+		}
+
+		previous := api.logger.GetLevel()
+		api.logger.SetLevel(level)
+		L.PushString(luaLogLevelName(previous))
+		return 1
+	})
+	L.SetTable(-3)
+
+	api.SafePushGoFunction(L, "get_log_level", func(L *lua.State) int {
+		L.PushString(luaLogLevelName(api.logger.GetLevel()))
+		return 1
+	})
+	L.SetTable(-3)
+}
@@ -5,7 +5,9 @@ package lua
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
 	"net/url"
 	"sort"
 	"strings"
@@ -117,6 +119,10 @@ type TestStep struct {
 	// ExpectedErrors is a list of expected error message substrings to validate after this step (optional)
 	ExpectedErrors []string `json:"expected_errors,omitempty" yaml:"expected_errors,omitempty"`
 
+	// ExpectedMaxLatency fails the step if a record delivered after it was received later than this after the
+	// step's simulation started, judged by the record's TsUs (optional, e.g. "20ms")
+	ExpectedMaxLatency time.Duration `json:"expected_max_latency,omitempty" yaml:"expected_max_latency,omitempty"`
+
 	// Lua is optional Lua code to execute during this step (Bridge tests)
 	Lua string `json:"lua,omitempty" yaml:"lua,omitempty"`
 
@@ -283,6 +289,14 @@ type ScriptExecutor interface {
 //   - Any other non-empty string: Returns an error immediately (e.g., "permission denied")
 //   - Omitted or empty: Normal descriptor read returning the value
 //
+// A descriptor with a malformed value can declare the parse error it MUST produce;
+// the suite asserts it against the descriptor's parsed value after the script runs:
+//
+//	descriptors:
+//	  - uuid: "2902"
+//	    value: [0x01]
+//	    parse_error: "invalid length for client config: expected 2, got 1"
+//
 // These error behaviors map to the DescriptorReadBehavior enum in PeripheralDeviceBuilder:
 //   - DescriptorReadTimeout (1): For timeout simulation
 //   - DescriptorReadError (2): For immediate error returns
@@ -339,6 +353,10 @@ type LuaApiSuite struct {
 	luaOutputCapture *LuaOutputCollector
 	Executor         ScriptExecutor
 	templateData     map[string]interface{} // Template data for dynamic test assertions
+
+	// AllowUnknownYAMLFields decodes legacy scenario files leniently, ignoring unknown keys.
+	// By default, unknown keys fail the test so a typo can't silently disable a check.
+	AllowUnknownYAMLFields bool
 }
 
 // NewPeripheralDataSimulator creates a builder with automatic connection access via LuaApi.
@@ -700,19 +718,28 @@ func (suite *LuaApiSuite) RunTestCasesFromFile(filePath string) {
 
 // RunTestCasesFromYAML parses YAML test case definitions and executes them.
 // Automatically dedents the YAML content to support inline test definitions.
-// Expects a "test_cases" array at the root level.
+// Expects a "test_cases" array at the root level; unknown keys fail unless AllowUnknownYAMLFields is set.
 // Each test case runs as a separate subtest with full isolation.
 func (suite *LuaApiSuite) RunTestCasesFromYAML(yamlContent string) {
-	// Dedent the YAML content
-	yamlContent = dedent(yamlContent)
+	testCases, err := decodeTestCasesYAML(dedent(yamlContent), !suite.AllowUnknownYAMLFields)
+	suite.Require().NoError(err, "Failed to parse YAML test cases")
+
+	suite.RunTestCases(testCases...)
+}
 
+// decodeTestCasesYAML decodes the "test_cases" array of a scenario file.
+// In strict mode, unknown keys are rejected with an error naming the field and its line.
+func decodeTestCasesYAML(yamlContent string, strict bool) ([]TestCase, error) {
 	var scenario struct {
 		TestCases []TestCase `yaml:"test_cases"`
 	}
-	err := yaml.Unmarshal([]byte(yamlContent), &scenario)
-	suite.Require().NoError(err, "Failed to parse YAML test cases")
 
-	suite.RunTestCases(scenario.TestCases...)
+	decoder := yaml.NewDecoder(strings.NewReader(yamlContent))
+	decoder.KnownFields(strict)
+	if err := decoder.Decode(&scenario); err != nil && !errors.Is(err, io.EOF) {
+		return nil, err
+	}
+	return scenario.TestCases, nil
 }
 
 func dedent(s string) string {
@@ -1006,19 +1033,46 @@ func (suite *LuaApiSuite) executeTestSteps(
 				time.Sleep(DefaultStepWaitDuration)
 			}
 
-			// Validate step output
-			if len(currentStep.ExpectedJSONOutput) > 0 {
-				suite.ValidateOutput2(collector, currentStep.ExpectedJSONOutput, "", true)
-			}
+			// Validate step output, errors and latency against the output consumed once
+			if len(currentStep.ExpectedJSONOutput) > 0 || len(currentStep.ExpectedErrors) > 0 || currentStep.ExpectedMaxLatency > 0 {
+				output := suite.consumeOutput(collector)
 
-			// Validate step errors
-			if len(currentStep.ExpectedErrors) > 0 {
-				suite.ValidateOutput2(collector, nil, "", true, currentStep.ExpectedErrors...)
+				var expectedJson interface{}
+				if len(currentStep.ExpectedJSONOutput) > 0 {
+					expectedJson = currentStep.ExpectedJSONOutput
+				}
+				suite.validateOutput(output, expectedJson, "", true, currentStep.ExpectedErrors...)
+
+				if currentStep.ExpectedMaxLatency > 0 {
+					suite.validateLatency(output.Stdout, simulator.SentAt(), currentStep.ExpectedMaxLatency)
+				}
 			}
 		})
 	}
 }
 
+// validateLatency fails unless every subscription record in stdout was received within maxLatency of sentAt.
+// Latency is the record's TsUs (notification receipt timestamp) minus the simulation send time.
+func (suite *LuaApiSuite) validateLatency(stdout string, sentAt time.Time, maxLatency time.Duration) {
+	records := 0
+	for _, line := range strings.Split(strings.TrimSpace(stdout), "\n") {
+		var data struct {
+			Record struct {
+				TsUs int64 `json:"TsUs"`
+			} `json:"record"`
+		}
+		if err := json.Unmarshal([]byte(strings.TrimSpace(line)), &data); err != nil || data.Record.TsUs == 0 {
+			continue // Not a subscription record
+		}
+		records++
+
+		latency := time.Duration(data.Record.TsUs-sentAt.UnixMicro()) * time.Microsecond
+		suite.Require().LessOrEqual(latency, maxLatency,
+			"Notification latency %v exceeds expected_max_latency %v (record: %s)", latency, maxLatency, line)
+	}
+	suite.Require().Positive(records, "expected_max_latency requires at least one delivered record, got none")
+}
+
 // validateFinalOutput performs final validation after all test steps complete.
 // Helper method extracted for reuse in template method pattern.
 func (suite *LuaApiSuite) validateFinalOutput(testCase TestCase, collector *LuaOutputCollector) {
@@ -1038,6 +1092,38 @@ func (suite *LuaApiSuite) validateFinalOutput(testCase TestCase, collector *LuaO
 	}
 }
 
+// validateDescriptorParseErrors checks every peripheral descriptor that declares parse_error:
+// its parsed value MUST be a *device.DescriptorError with reason "parse_error" containing the expected text.
+func (suite *LuaApiSuite) validateDescriptorParseErrors(testCase TestCase, conn device.Connection) {
+	for _, svcCfg := range testCase.Peripheral {
+		for _, charCfg := range svcCfg.Characteristics {
+			for _, descCfg := range charCfg.Descriptors {
+				if descCfg.ParseError == "" {
+					continue
+				}
+				suite.Require().NotNil(conn, "connection MUST exist to validate parse_error of descriptor %s", descCfg.UUID)
+
+				char, err := conn.GetCharacteristic(svcCfg.UUID, charCfg.UUID)
+				suite.Require().NoError(err, "characteristic %s/%s MUST exist", svcCfg.UUID, charCfg.UUID)
+
+				var desc device.Descriptor
+				for _, d := range char.GetDescriptors() {
+					if d.UUID() == device.NormalizeUUID(descCfg.UUID) {
+						desc = d
+						break
+					}
+				}
+				suite.Require().NotNil(desc, "descriptor %s MUST exist on characteristic %s", descCfg.UUID, charCfg.UUID)
+
+				descErr, ok := desc.ParsedValue().(*device.DescriptorError)
+				suite.Require().True(ok, "descriptor %s parsed value MUST be a *device.DescriptorError, got: %T", descCfg.UUID, desc.ParsedValue())
+				suite.Assert().Equal("parse_error", descErr.Reason, "descriptor %s MUST fail with a parse error", descCfg.UUID)
+				suite.Assert().ErrorContains(descErr, descCfg.ParseError, "descriptor %s parse error MUST match", descCfg.UUID)
+			}
+		}
+	}
+}
+
 // ExecuteScriptWithCallbacks is a template method that executes a Lua script with before/after callbacks.
 // The default implementation uses suite.LuaApi. BridgeSuite can override to use Bridge's LuaAPI.
 // Both callbacks receive both PTY functions (write and read) for maximum flexibility.
@@ -1124,6 +1210,9 @@ func (suite *LuaApiSuite) RunTestCase(testCase TestCase) {
 			// Execute test steps
 			suite.executeTestSteps(testCase, conn, collector, luaApi, ptySlaveWrite, ptySlaveRead)
 
+			// Descriptor parse errors declared in the peripheral configuration
+			suite.validateDescriptorParseErrors(testCase, conn)
+
 			// Final validation
 			suite.validateFinalOutput(testCase, collector)
 
@@ -1169,33 +1258,42 @@ func (suite *LuaApiSuite) RunTestCase(testCase TestCase) {
 // JSON validation supports flexible formats for both expected and actual: string, map, struct, array, or array of maps/structs.
 // Reuses normalization and hex conversion for consistent validation.
 func (suite *LuaApiSuite) ValidateOutput2(collector *LuaOutputCollector, expectedJson interface{}, expectedStdout string, isSubscription bool, expectedErrors ...string) {
-	// testify automatically handles T substitution when using suite.Run()
-	t := suite.T()
-	req := require.New(t)
+	suite.validateOutput(suite.consumeOutput(collector), expectedJson, expectedStdout, isSubscription, expectedErrors...)
+}
+
+// luaOutput holds stdout and stderr consumed from an output collector
+type luaOutput struct {
+	Stdout string
+	Stderr []string
+}
 
-	// Consumer: split stdout/stderr
+// consumeOutput drains the collector, splitting its records into stdout text and stderr lines
+func (suite *LuaApiSuite) consumeOutput(collector *LuaOutputCollector) luaOutput {
 	var stdoutBuf strings.Builder
 	var stderrLines []string
 
-	type Output struct {
-		Stdout string
-		Stderr []string
-	}
-
-	consumer := func(record *LuaOutputRecord) (Output, error) {
+	consumer := func(record *LuaOutputRecord) (luaOutput, error) {
 		if record == nil {
-			return Output{Stdout: stdoutBuf.String(), Stderr: stderrLines}, nil
+			return luaOutput{Stdout: stdoutBuf.String(), Stderr: stderrLines}, nil
 		}
 		if record.Source == "stdout" {
 			stdoutBuf.WriteString(record.Content)
 		} else if record.Source == "stderr" {
 			stderrLines = append(stderrLines, record.Content)
 		}
-		return Output{}, nil
+		return luaOutput{}, nil
 	}
 
 	output, err := ConsumeRecords(collector, consumer)
-	req.NoError(err, "Failed to consume output")
+	suite.Require().NoError(err, "Failed to consume output")
+	return output
+}
+
+// validateOutput validates already consumed output (see ValidateOutput2)
+func (suite *LuaApiSuite) validateOutput(output luaOutput, expectedJson interface{}, expectedStdout string, isSubscription bool, expectedErrors ...string) {
+	// testify automatically handles T substitution when using suite.Run()
+	t := suite.T()
+	req := require.New(t)
 
 	// Validate JSON (flexible: string/array/map/struct)
 	if expectedJson != nil {
@@ -21,6 +21,34 @@ func (suite *LuaApiSuiteTestSuite) TestSuiteScenarios() {
 	suite.RunTestCasesFromFile("test-scenarios/lua-api-suite-test-scenarios.yaml")
 }
 
+// TestStrictYAMLDecoding verifies scenario files with unknown keys are rejected unless decoded leniently
+func (suite *LuaApiSuiteTestSuite) TestStrictYAMLDecoding() {
+	// GOAL: Verify a misspelled scenario field fails decoding with an error naming the field, instead of being ignored
+	//
+	// TEST SCENARIO: Decode a test case with "expected_stdut" → strict mode fails naming the field and line → lenient mode decodes it
+
+	content := dedent(`
+		test_cases:
+		  - name: "typo"
+		    script: "print('hi')"
+		    expected_stdut: "hi"
+	`)
+
+	_, err := decodeTestCasesYAML(content, true)
+	suite.Require().Error(err, "strict decoding MUST reject unknown fields")
+	suite.Assert().Contains(err.Error(), "field expected_stdut not found", "error MUST name the offending field")
+	suite.Assert().Contains(err.Error(), "line 5", "error MUST point at the offending line")
+
+	testCases, err := decodeTestCasesYAML(content, false)
+	suite.Require().NoError(err, "lenient decoding MUST ignore unknown fields")
+	suite.Require().Len(testCases, 1)
+	suite.Assert().Equal("typo", testCases[0].Name)
+
+	testCases, err = decodeTestCasesYAML("", true)
+	suite.Assert().NoError(err, "empty content MUST decode to no test cases")
+	suite.Assert().Empty(testCases)
+}
+
 // TestLuaApiSuiteTestSuite runs the suite test infrastructure tests
 func TestLuaApiSuiteTestSuite(t *testing.T) {
 	suitelib.Run(t, new(LuaApiSuiteTestSuite))
@@ -10,6 +10,7 @@ import (
 
 	_ "embed"
 
+	"github.com/sirupsen/logrus"
 	"github.com/srg/blim/internal/device"
 	"github.com/srg/blim/internal/testutils"
 	suitelib "github.com/stretchr/testify/suite"
@@ -298,6 +299,23 @@ func (suite *LuaApiTestSuite) TestCharacteristicFunction() {
 		suite.NoError(err, "Should have valid properties field")
 	})
 
+	suite.Run("Per-operation authentication fields", func() {
+		// GOAL: Verify properties expose read_requires_auth/write_requires_auth without affecting ipairs() iteration
+		//
+		// TEST SCENARIO: Lookup open characteristic → both flags false → ipairs() yields only property sub-tables
+
+		script := `
+			local char = blim.characteristic("180D", "2A37")
+			assert(char.properties.read_requires_auth == false, "read_requires_auth MUST be false for an open characteristic")
+			assert(char.properties.write_requires_auth == false, "write_requires_auth MUST be false for an open characteristic")
+			for _, prop in ipairs(char.properties) do
+				assert(type(prop) == "table", "ipairs() MUST yield property tables only")
+			end
+		`
+		err := suite.ExecuteScript(script)
+		suite.NoError(err, "Should expose per-operation authentication fields")
+	})
+
 	suite.Run("Error: Invalid service UUID", func() {
 		// GOAL: Verify blim.characteristic() raises error when service UUID not found
 		//
@@ -511,7 +529,8 @@ func (suite *LuaApiTestSuite) TestCharacteristicRead() {
 
 			-- MUST fail because the characteristic doesn't support read
 			assert(value == nil, "value MUST be nil when error occurs")
-			assert(err == "read() failed: characteristic bbbb does not support read operations", "error message MUST be exact, got: " .. tostring(err))
+			assert(tostring(err) == "read() failed: characteristic bbbb does not support read operations", "error message MUST be exact, got: " .. tostring(err))
+			assert(err.code == "unsupported", "error code MUST be unsupported, got: " .. tostring(err.code))
 		`
 		err := suite.ExecuteScript(script)
 		suite.NoError(err, "Should properly error on non-readable characteristic")
@@ -627,7 +646,8 @@ func (suite *LuaApiTestSuite) TestCharacteristicRead() {
 
 			-- MUST fail because device is not connected
 			assert(value == nil, "value MUST be nil when error occurs")
-			assert(err == "read() failed: read characteristic 5678", "error message MUST be exact, got: " .. tostring(err))
+			assert(tostring(err) == "read() failed: read characteristic 5678", "error message MUST be exact, got: " .. tostring(err))
+			assert(err.code == "not_connected", "error code MUST be not_connected, got: " .. tostring(err.code))
 		`
 		err := suite.ExecuteScript(script)
 		suite.NoError(err, "Should properly error on disconnected device")
@@ -725,246 +745,2344 @@ func (suite *LuaApiTestSuite) TestCharacteristicWrite() {
 		suite.NoError(err, "Should successfully write to writable characteristic")
 	})
 
-	suite.Run("Successful write without response returns true and nil error", func() {
-		// GOAL: Verify write() returns true and nil error when writing without response
-		//
-		// TEST SCENARIO: Write to characteristic with with_response=false → success → verify (true, nil) returned
+	suite.Run("Successful write without response returns true and nil error", func() {
+		// GOAL: Verify write() returns true and nil error when writing without response
+		//
+		// TEST SCENARIO: Write to characteristic with with_response=false → success → verify (true, nil) returned
+
+		script := `
+			local char = blim.characteristic("1234", "ABCD")
+			local result, err = char.write("test data", false)
+			assert(result == true, "write without response should return true on success")
+			assert(err == nil, "write should not return error")
+		`
+		err := suite.ExecuteScript(script)
+		suite.NoError(err, "Should successfully write without response")
+	})
+
+	suite.Run("Write with explicit with_response=true parameter", func() {
+		// GOAL: Verify write() accepts explicit with_response=true parameter
+		//
+		// TEST SCENARIO: Write with with_response=true explicitly → success → verify (true, nil) returned
+
+		script := `
+			local char = blim.characteristic("1234", "ABCD")
+			local result, err = char.write("test", true)
+			assert(result == true, "write with explicit with_response=true should succeed")
+			assert(err == nil, "write should not return error")
+		`
+		err := suite.ExecuteScript(script)
+		suite.NoError(err, "Should successfully write with explicit with_response=true")
+	})
+
+	suite.Run("Write returns nil and error on failure", func() {
+		// GOAL: Verify write() returns nil and error when writing to a read-only characteristic
+		//
+		// TEST SCENARIO: Write to read-only characteristic (5678) → returns (nil, error)
+
+		script := `
+			local char = blim.characteristic("1234", "5678")
+			local result, err = char.write("data")
+
+			-- MUST fail because the characteristic doesn't support write
+			assert(result == nil, "result MUST be nil when error occurs")
+			assert(tostring(err) == "write() failed: characteristic 5678 does not support write operations", "error message MUST be exact, got: " .. tostring(err))
+			assert(err.code == "unsupported", "error code MUST be unsupported, got: " .. tostring(err.code))
+		`
+		err := suite.ExecuteScript(script)
+		suite.NoError(err, "Should properly error on non-writable characteristic")
+	})
+
+	suite.Run("Write binary data", func() {
+		// GOAL: Verify write() correctly sends binary data including null bytes
+		//
+		// TEST SCENARIO: Write binary string with \x00 bytes → success → verify (true, nil) returned
+
+		script := `
+			local char = blim.characteristic("1234", "ABCD")
+			local binary_data = "\x01\x02\x00\xFF\x03"
+			local result, err = char.write(binary_data)
+			assert(result == true, "write should succeed with binary data")
+			assert(err == nil, "write should not return error")
+		`
+		err := suite.ExecuteScript(script)
+		suite.NoError(err, "Should handle binary data correctly")
+	})
+
+	suite.Run("Write empty string", func() {
+		// GOAL: Verify write() accepts empty string as valid data
+		//
+		// TEST SCENARIO: Write empty string "" → success → verify (true, nil) returned
+
+		script := `
+			local char = blim.characteristic("1234", "ABCD")
+			local result, err = char.write("")
+			assert(result == true, "write should succeed with empty string")
+			assert(err == nil, "write should not return error")
+		`
+		err := suite.ExecuteScript(script)
+		suite.NoError(err, "Should handle empty string write")
+	})
+
+	suite.Run("Write multiple times to same characteristic", func() {
+		// GOAL: Verify write() is idempotent and can be called multiple times
+		//
+		// TEST SCENARIO: Write to same characteristic 3 times → all return success → verify consistent behavior
+
+		script := `
+			local char = blim.characteristic("1234", "ABCD")
+
+			local result1, err1 = char.write("first")
+			local result2, err2 = char.write("second")
+			local result3, err3 = char.write("third")
+
+			assert(result1 == true, "first write should succeed")
+			assert(result2 == true, "second write should succeed")
+			assert(result3 == true, "third write should succeed")
+
+			assert(err1 == nil, "first write should not error")
+			assert(err2 == nil, "second write should not error")
+			assert(err3 == nil, "third write should not error")
+		`
+		err := suite.ExecuteScript(script)
+		suite.NoError(err, "Should allow multiple writes")
+	})
+
+	suite.Run("Write with implicit number-to-string conversion", func() {
+		// GOAL: Verify write() handles Lua's implicit number-to-string conversion (consistent with characteristic())
+		//       (Note: Lua IsString() returns true for numbers due to implicit conversion, so 123 becomes "123")
+		//
+		// TEST SCENARIO: Call write(123) with number → number converted to string "123" → write succeeds with converted data
+
+		script := `
+			local char = blim.characteristic("1234", "ABCD")
+			local result, err = char.write(123)
+			-- Number 123 gets converted to string "123" by Lua
+			assert(result == true, "write should succeed with implicitly converted number")
+			assert(err == nil, "write should not return error")
+		`
+		err := suite.ExecuteScript(script)
+		suite.NoError(err, "Should handle implicit number-to-string conversion")
+	})
+
+	suite.Run("Error: write() with invalid with_response parameter", func() {
+		// GOAL: Verify write() raises error when with_response parameter is not boolean
+		//
+		// TEST SCENARIO: Call write("data", "not a boolean") → Lua error raised → verify error message
+
+		script := `
+			local char = blim.characteristic("1234", "ABCD")
+			local result, err = char.write("data", "not boolean")
+		`
+		err := suite.ExecuteScript(script)
+		suite.AssertLuaError(err, "expects boolean as second argument")
+	})
+
+	suite.Run("Error: write() on non-connected device", func() {
+		// GOAL: Verify write() returns error when called on disconnected device
+		//
+		// TEST SCENARIO: Disconnect device → attempt write → returns (nil, error)
+
+		// Disconnect the device first
+		disconnectErr := suite.LuaApi.GetDevice().Disconnect()
+		suite.NoError(disconnectErr, "Should disconnect successfully")
+
+		script := `
+			local char = blim.characteristic("1234", "ABCD")
+			local result, err = char.write("data")
+
+			-- MUST fail because device is not connected
+			assert(result == nil, "result MUST be nil when error occurs")
+			assert(tostring(err) == "write() failed: write characteristic abcd", "error message MUST be exact, got: " .. tostring(err))
+			assert(err.code == "not_connected", "error code MUST be not_connected, got: " .. tostring(err.code))
+		`
+		err := suite.ExecuteScript(script)
+		suite.NoError(err, "Should properly error on disconnected device")
+	})
+
+	suite.Run("Write to characteristic supporting both write modes", func() {
+		// GOAL: Verify write() can use both with_response and without_response modes on the same characteristic
+		//
+		// TEST SCENARIO: Write twice to ABCD characteristic with both modes → both succeed → verify returns
+
+		script := `
+			local char = blim.characteristic("1234", "ABCD")
+
+			-- Verify characteristic supports both modes
+			if not char.properties.write then
+				error("Test setup error: characteristic should support write with response")
+			end
+			if not char.properties.write_without_response then
+				error("Test setup error: characteristic should support write without response")
+			end
+
+			-- Write with response (default)
+			local result1, err1 = char.write("with response")
+			assert(result1 == true, "write with response should succeed")
+			assert(err1 == nil, "write with response should not error")
+
+			-- Write without response
+			local result2, err2 = char.write("without response", false)
+			assert(result2 == true, "write without response should succeed")
+			assert(err2 == nil, "write without response should not error")
+		`
+		err := suite.ExecuteScript(script)
+		suite.NoError(err, "Should support both write modes on same characteristic")
+	})
+
+	suite.Run("Write large data payload", func() {
+		// GOAL: Verify write() handles large data payloads (MTU chunking is transparent to Lua API)
+		//
+		// TEST SCENARIO: Write 512 bytes of data → success → verify (true, nil) returned
+
+		script := `
+			local char = blim.characteristic("1234", "ABCD")
+
+			-- Generate 512 bytes of data
+			local large_data = string.rep("A", 512)
+
+			local result, err = char.write(large_data)
+			assert(result == true, "write should succeed with large payload")
+			assert(err == nil, "write should not return error")
+		`
+		err := suite.ExecuteScript(script)
+		suite.NoError(err, "Should handle large data payloads")
+	})
+
+	suite.Run("Verify write() is a method not a field", func() {
+		// GOAL: Verify write() is exposed as a callable method (userdata type in aarzilli/golua, not a field)
+		//
+		// TEST SCENARIO: Get a characteristic handle → check write type is userdata/function → verify callable
+
+		script := `
+			local char = blim.characteristic("1234", "ABCD")
+
+			-- write should be a callable (in aarzilli/golua, Go functions are userdata type, not "function")
+			assert(char.write ~= nil, "write should not be nil")
+			assert(type(char.write) == "function" or type(char.write) == "userdata",
+			       "write should be callable (function or userdata), got: " .. type(char.write))
+
+			-- Calling it should work
+			local result, err = char.write("test")
+			assert(result ~= nil or err ~= nil, "should return either result or error")
+		`
+		err := suite.ExecuteScript(script)
+		suite.NoError(err, "write should be a callable method")
+	})
+
+	suite.Run("Write with nil with_response parameter defaults to true", func() {
+		// GOAL: Verify write() treats nil with_response parameter as default (true)
+		//
+		// TEST SCENARIO: Call write("data", nil) → defaults to with_response=true → success
+
+		script := `
+			local char = blim.characteristic("1234", "ABCD")
+			local result, err = char.write("data", nil)
+			assert(result == true, "write with nil with_response should succeed (default to true)")
+			assert(err == nil, "write should not return error")
+		`
+		err := suite.ExecuteScript(script)
+		suite.NoError(err, "Should treat nil with_response as default true")
+	})
+}
+
+// TestLuaBridgeAccess tests blim.bridge exposure to Lua
+func (suite *LuaApiTestSuite) TestDeviceInfoFunction() {
+	// Set up peripheral with Device Information Service (partial: no serial number, hardware or software revision)
+	suite.WithPeripheral().FromJSON(`{
+		"services": [
+			{
+				"uuid": "180A",
+				"characteristics": [
+					{ "uuid": "2A29", "properties": "read", "value": [66, 76, 73, 77, 67, 111] },
+					{ "uuid": "2A24", "properties": "read", "value": [84, 101, 115, 116] },
+					{ "uuid": "2A26", "properties": "read", "value": [49, 46, 50, 46, 51] },
+					{ "uuid": "2A23", "properties": "read", "value": [1, 2, 3, 4, 5, 6, 7, 8] },
+					{ "uuid": "2A50", "properties": "read", "value": [1, 76, 0, 52, 18, 1, 0] }
+				]
+			}
+		]
+	}`).Build()
+
+	suite.Run("Reads all present DIS characteristics", func() {
+		// GOAL: Verify device_info() returns decoded DIS fields in a single call
+		//
+		// TEST SCENARIO: Call device_info() → strings decoded, system ID hex, PnP ID table → absent fields omitted
+
+		script := `
+			local info, err = blim.device_info()
+			assert(err == nil, "device_info() MUST NOT return error, got: " .. tostring(err))
+			assert(info.manufacturer_name == "BLIMCo", "manufacturer_name MUST be decoded, got: " .. tostring(info.manufacturer_name))
+			assert(info.model_number == "Test", "model_number MUST be decoded, got: " .. tostring(info.model_number))
+			assert(info.firmware_revision == "1.2.3", "firmware_revision MUST be decoded, got: " .. tostring(info.firmware_revision))
+			assert(info.serial_number == nil, "absent serial_number MUST be omitted")
+			assert(info.hardware_revision == nil, "absent hardware_revision MUST be omitted")
+			assert(info.software_revision == nil, "absent software_revision MUST be omitted")
+			assert(info.system_id == "0102030405060708", "system_id MUST be hex, got: " .. tostring(info.system_id))
+			assert(info.pnp_id ~= nil, "pnp_id MUST be present")
+			assert(info.pnp_id.vendor_id_source == 1, "vendor_id_source MUST be 1")
+			assert(info.pnp_id.vendor_id == 0x004C, "vendor_id MUST be 0x004C")
+			assert(info.pnp_id.vendor_name ~= nil, "vendor_name MUST be resolved for Bluetooth SIG company ID")
+			assert(info.pnp_id.product_id == 0x1234, "product_id MUST be 0x1234")
+			assert(info.pnp_id.product_version == 1, "product_version MUST be 1")
+		`
+		err := suite.ExecuteScript(script)
+		suite.NoError(err, "device_info() MUST return populated table")
+	})
+
+	// Peripheral without Device Information Service (applied to the next subtest's connection)
+	suite.WithPeripheral().FromJSON(`{
+		"services": [
+			{
+				"uuid": "180F",
+				"characteristics": [
+					{ "uuid": "2A19", "properties": "read", "value": [85] }
+				]
+			}
+		]
+	}`).Build()
+
+	suite.Run("Missing DIS returns nil and error", func() {
+		// GOAL: Verify device_info() reports a missing Device Information Service as (nil, error)
+		//
+		// TEST SCENARIO: Peripheral without 180A → device_info() → returns (nil, error message)
+
+		script := `
+			local info, err = blim.device_info()
+			assert(info == nil, "info MUST be nil when DIS is absent")
+			assert(err ~= nil, "error MUST be returned when DIS is absent")
+		`
+		err := suite.ExecuteScript(script)
+		suite.NoError(err, "device_info() MUST report missing DIS gracefully")
+	})
+}
+
+func (suite *LuaApiTestSuite) TestReadServiceFunction() {
+	suite.WithPeripheral().FromJSON(`{
+		"services": [
+			{
+				"uuid": "180D",
+				"characteristics": [
+					{ "uuid": "2A38", "properties": "read", "value": [1] },
+					{ "uuid": "2A39", "properties": "write", "value": [] },
+					{ "uuid": "AAAA", "properties": "read", "value": [104, 105] }
+				]
+			}
+		]
+	}`).Build()
+
+	suite.Run("Reads all readable characteristics", func() {
+		// GOAL: Verify read_service() reads every readable characteristic of a service in one call
+		//
+		// TEST SCENARIO: Call read_service("180d") → readable values keyed by UUID with name and parsed value → write-only skipped → unknown service fails
+
+		script := `
+			local values, err = blim.read_service("180d")
+			assert(err == nil, "read_service() MUST NOT return error, got: " .. tostring(err))
+
+			local location = values["2a38"]
+			assert(location ~= nil, "readable characteristic MUST be present")
+			assert(location.value == "\x01", "value MUST be the raw bytes")
+			assert(location.name == "Body Sensor Location", "name MUST be the known name, got: " .. tostring(location.name))
+			assert(location.parsed ~= nil and location.parsed.name == "Chest", "parsed MUST be set for characteristics with a parser")
+			assert(location.error == nil, "successful read MUST NOT set error")
+
+			local custom = values["aaaa"]
+			assert(custom ~= nil and custom.value == "hi", "custom characteristic MUST be read")
+			assert(custom.name == nil and custom.parsed == nil, "unknown characteristic MUST NOT have name or parsed")
+
+			assert(values["2a39"] == nil, "write-only characteristic MUST be skipped")
+
+			local missing, err = blim.read_service("1234")
+			assert(missing == nil and err ~= nil, "unknown service MUST fail")
+			assert(err.code == "not_found", "error code MUST be not_found, got: " .. tostring(err.code))
+		`
+		err := suite.ExecuteScript(script)
+		suite.NoError(err, "read_service() MUST return populated table")
+	})
+}
+
+func (suite *LuaApiTestSuite) TestWriteMultipleFunction() {
+	suite.WithPeripheral().FromJSON(`{
+		"services": [
+			{
+				"uuid": "FFF0",
+				"characteristics": [
+					{ "uuid": "FFF1", "properties": "write", "value": [] },
+					{ "uuid": "FFF2", "properties": "read", "value": [0] },
+					{ "uuid": "FFF3", "properties": "write,write-without-response", "value": [] }
+				]
+			}
+		]
+	}`).Build()
+
+	suite.Run("Reports per-write results", func() {
+		// GOAL: Verify write_multiple() writes every entry in order and reports each result
+		//
+		// TEST SCENARIO: Write fff1, read-only fff2, missing fff9, fff3 → ok/failed per entry → err is the first failure
+
+		err := suite.ExecuteScript(`
+			local results, err = blim.write_multiple({
+				{service = "fff0", char = "fff1", data = "\x01"},
+				{service = "fff0", char = "fff2", data = "\x02"},
+				{service = "fff0", char = "fff9", data = "\x03"},
+				{service = "fff0", char = "fff3", data = "\x04", with_response = false},
+			})
+			assert(#results == 4, "every write MUST have a result, got: " .. #results)
+			assert(results[1].ok and results[1].error == nil, "writable characteristic MUST succeed")
+			assert(results[1].service == "fff0" and results[1].char == "fff1", "result MUST identify the write")
+			assert(not results[2].ok and results[2].error ~= nil, "read-only characteristic MUST fail")
+			assert(not results[3].ok and results[3].error.code == "not_found", "missing characteristic MUST fail as not_found")
+			assert(results[4].ok and not results[4].skipped, "writes after a failure MUST continue without stop_on_error")
+			assert(err ~= nil and err.code == results[2].error.code, "err MUST be the first failure")
+
+			local ok_results, ok_err = blim.write_multiple({{service = "fff0", char = "fff1", data = "\x05"}})
+			assert(ok_err == nil and ok_results[1].ok, "all successful writes MUST return a nil error")
+		`)
+		suite.NoError(err, "write_multiple() MUST report every write")
+	})
+
+	suite.Run("Stops on error", func() {
+		// GOAL: Verify stop_on_error skips the writes after the first failure
+		//
+		// TEST SCENARIO: Write fff1, read-only fff2, fff3 with stop_on_error → fff3 skipped
+
+		err := suite.ExecuteScript(`
+			local results, err = blim.write_multiple({
+				{service = "fff0", char = "fff1", data = "\x01"},
+				{service = "fff0", char = "fff2", data = "\x02"},
+				{service = "fff0", char = "fff3", data = "\x03"},
+			}, {stop_on_error = true})
+			assert(err ~= nil, "failure MUST be reported")
+			assert(results[1].ok and not results[2].ok, "writes up to the failure MUST be attempted")
+			assert(results[3].skipped and not results[3].ok, "writes after the failure MUST be skipped")
+			assert(results[3].char == "fff3", "skipped result MUST identify the write")
+		`)
+		suite.NoError(err, "write_multiple() MUST stop on error")
+	})
+
+	suite.Run("Rejects invalid writes before writing", func() {
+		// GOAL: Verify malformed entries raise errors
+		//
+		// TEST SCENARIO: Non-table writes, missing data, non-boolean with_response, non-boolean stop_on_error → Lua errors
+
+		for script, expected := range map[string]string{
+			`blim.write_multiple("fff1")`:                                                            "expects an array of writes",
+			`blim.write_multiple({{service = "fff0", char = "fff1"}})`:                               "a string as data of write 1",
+			`blim.write_multiple({{service = "fff0", char = "fff1", data = "", with_response = 1}})`: "with_response of write 1",
+			`blim.write_multiple({}, {stop_on_error = "yes"})`:                                       "a boolean as stop_on_error",
+		} {
+			err := suite.ExecuteScript(script)
+			suite.AssertLuaError(err, expected, script)
+		}
+	})
+}
+
+func (suite *LuaApiTestSuite) TestAllCharacteristicsFunction() {
+	// Set up peripheral reusing the Battery Level UUID across two services
+	suite.WithPeripheral().FromJSON(`{
+		"services": [
+			{
+				"uuid": "180F",
+				"characteristics": [
+					{ "uuid": "2A19", "properties": "read", "value": [85] }
+				]
+			},
+			{
+				"uuid": "1234",
+				"characteristics": [
+					{ "uuid": "2A19", "properties": "read", "value": [42] },
+					{ "uuid": "5678", "properties": "read,write", "value": [0] }
+				]
+			}
+		]
+	}`).Build()
+
+	suite.Run("Returns flat ordered array across services", func() {
+		// GOAL: Verify all_characteristics() flattens characteristics of all services in a stable order
+		//
+		// TEST SCENARIO: Two services share a characteristic UUID → all_characteristics() → three entries ordered by service, then characteristic UUID
+
+		script := `
+			local chars = blim.all_characteristics()
+			assert(#chars == 3, "MUST return 3 characteristics, got: " .. #chars)
+			assert(chars[1].service == "1234" and chars[1].uuid == "2a19", "first entry MUST be 1234/2a19")
+			assert(chars[2].service == "1234" and chars[2].uuid == "5678", "second entry MUST be 1234/5678")
+			assert(chars[3].service == "180f" and chars[3].uuid == "2a19", "third entry MUST be 180f/2a19")
+			assert(chars[1].name == "Battery Level", "known name MUST be present, got: " .. tostring(chars[1].name))
+			assert(chars[2].name == nil, "unknown characteristic MUST NOT have name")
+		`
+		err := suite.ExecuteScript(script)
+		suite.NoError(err, "all_characteristics() MUST return flat array")
+	})
+}
+
+func (suite *LuaApiTestSuite) TestTimeoutsFunctions() {
+	suite.Run("Set returns previous settings and get reflects changes", func() {
+		// GOAL: Verify set_timeouts() applies only given fields and returns the previous settings
+		//
+		// TEST SCENARIO: get_timeouts() → defaults → set read_ms → previous returned → restore → defaults again
+
+		script := `
+			local t = blim.get_timeouts()
+			assert(t.read_ms == 5000 and t.write_ms == 5000 and t.descriptor_ms == 2000,
+				string.format("defaults MUST be 5000/5000/2000, got %s/%s/%s", t.read_ms, t.write_ms, t.descriptor_ms))
+
+			local prev = blim.set_timeouts{read_ms = 12000}
+			assert(prev.read_ms == 5000 and prev.write_ms == 5000 and prev.descriptor_ms == 2000, "previous settings MUST be returned")
+
+			local cur = blim.get_timeouts()
+			assert(cur.read_ms == 12000, "read_ms MUST be updated, got: " .. tostring(cur.read_ms))
+			assert(cur.write_ms == 5000 and cur.descriptor_ms == 2000, "omitted fields MUST keep their values")
+
+			blim.set_timeouts(prev)
+			assert(blim.get_timeouts().read_ms == 5000, "previous settings MUST be restorable")
+
+			-- Reads keep working with the changed timeout
+			blim.set_timeouts{read_ms = 1000}
+			local value, err = blim.characteristic("180f", "2a19").read()
+			assert(value ~= nil, "read MUST succeed, got: " .. tostring(err))
+		`
+		err := suite.ExecuteScript(script)
+		suite.NoError(err, "timeouts MUST be adjustable at runtime")
+	})
+
+	suite.Run("Rejects non-positive values without applying any", func() {
+		// GOAL: Verify set_timeouts() validates all values before changing settings
+		//
+		// TEST SCENARIO: set_timeouts{write_ms = 1000, read_ms = 0} → error raised → write_ms unchanged
+
+		script := `
+			local ok, err = pcall(blim.set_timeouts, {write_ms = 1000, read_ms = 0})
+			assert(not ok, "zero timeout MUST be rejected")
+			assert(string.find(tostring(err), "read_ms to be a positive number", 1, true), "error MUST name the field, got: " .. tostring(err))
+			assert(blim.get_timeouts().write_ms == 5000, "valid fields MUST NOT be applied when validation fails")
+		`
+		err := suite.ExecuteScript(script)
+		suite.NoError(err, "invalid timeouts MUST be rejected")
+	})
+}
+
+func (suite *LuaApiTestSuite) TestPairFunction() {
+	suite.Run("Pair without protected characteristics returns nil and error", func() {
+		// GOAL: Verify blim.pair() reports (nil, error) when no characteristic triggers pairing
+		//
+		// TEST SCENARIO: Default peripheral → blim.device.is_paired false → blim.pair() → (nil, error) → is_paired still false
+
+		script := `
+			assert(blim.device.is_paired == false, "is_paired MUST be false before pairing")
+			local ok, err = blim.pair()
+			assert(ok == nil, "pair() MUST return nil on failure")
+			assert(err ~= nil and string.find(err, "no characteristic requiring authentication", 1, true),
+				"pair() MUST explain failure, got: " .. tostring(err))
+			assert(blim.device.is_paired == false, "is_paired MUST stay false after failed pairing")
+		`
+		err := suite.ExecuteScript(script)
+		suite.NoError(err, "pair() MUST report failure gracefully")
+	})
+}
+
+func (suite *LuaApiTestSuite) TestConnectionEventFunction() {
+	// Peripheral with a dynamic GATT database (Service Changed indication)
+	suite.WithPeripheral().FromJSON(`{
+		"services": [
+			{
+				"uuid": "1801",
+				"characteristics": [
+					{ "uuid": "2A05", "properties": "indicate", "value": [1, 0, 255, 255] }
+				]
+			},
+			{
+				"uuid": "180F",
+				"characteristics": [
+					{ "uuid": "2A19", "properties": "read", "value": [85] }
+				]
+			}
+		]
+	}`).Build()
+
+	suite.Run("services_changed event is delivered to the callback", func() {
+		// GOAL: Verify blim.on_connection_event() receives "services_changed" when 0x2A05 is indicated
+		//
+		// TEST SCENARIO: Register callback → simulate Service Changed indication → callback receives {type="services_changed"} → unregister → no further events
+
+		err := suite.ExecuteScript(`
+			events = {}
+			blim.on_connection_event(function(event)
+				table.insert(events, event.type)
+			end)
+		`)
+		suite.Require().NoError(err, "on_connection_event() MUST accept a function")
+
+		suite.NewPeripheralDataSimulator().
+			WithService("1801").
+			WithCharacteristic("2A05", []byte{0x01, 0x00, 0xFF, 0xFF}).
+			Simulate(false)
+		time.Sleep(50 * time.Millisecond)
+
+		err = suite.ExecuteScript(`
+			assert(#events == 1, "MUST receive one event, got: " .. #events)
+			assert(events[1] == "services_changed", "event type MUST be services_changed, got: " .. tostring(events[1]))
+			blim.on_connection_event(nil)
+		`)
+		suite.Require().NoError(err, "services_changed event MUST be delivered")
+
+		suite.NewPeripheralDataSimulator().
+			WithService("1801").
+			WithCharacteristic("2A05", []byte{0x01, 0x00, 0xFF, 0xFF}).
+			Simulate(false)
+		time.Sleep(50 * time.Millisecond)
+
+		err = suite.ExecuteScript(`assert(#events == 1, "unregistered callback MUST NOT be called, got: " .. #events)`)
+		suite.NoError(err, "on_connection_event(nil) MUST unregister the callback")
+	})
+
+	suite.Run("rssi readings are delivered to the callback", func() {
+		// GOAL: Verify blim.read_rssi() returns the connection RSSI and blim.set_rssi_interval() reports {type="rssi", rssi=...}
+		//
+		// TEST SCENARIO: read_rssi() → mock RSSI → register callback → poll every 20ms → callback receives rssi events → stop polling
+
+		err := suite.ExecuteScript(`
+			assert(blim.read_rssi() == -60, "read_rssi() MUST return the connection RSSI, got: " .. tostring(blim.read_rssi()))
+
+			rssi_events = {}
+			blim.on_connection_event(function(event)
+				if event.type == "rssi" then
+					table.insert(rssi_events, event.rssi)
+				end
+			end)
+			assert(blim.set_rssi_interval(20) == true, "set_rssi_interval() MUST succeed while connected")
+		`)
+		suite.Require().NoError(err, "set_rssi_interval() MUST accept an interval")
+
+		time.Sleep(150 * time.Millisecond)
+
+		err = suite.ExecuteScript(`
+			assert(blim.set_rssi_interval(0) == true, "set_rssi_interval(0) MUST stop polling")
+			assert(#rssi_events >= 2, "MUST receive periodic rssi events, got: " .. #rssi_events)
+			assert(rssi_events[1] == -60, "event.rssi MUST be the connection RSSI, got: " .. tostring(rssi_events[1]))
+			blim.on_connection_event(nil)
+		`)
+		suite.NoError(err, "rssi events MUST be delivered")
+	})
+
+	suite.Run("idle disconnect is delivered to the callback", func() {
+		// GOAL: Verify blim.set_idle_timeout() disconnects an idle connection and reports {type="disconnected", reason="idle"}
+		//
+		// TEST SCENARIO: Register callback → set 100ms idle timeout → wait → callback receives disconnected/idle
+
+		err := suite.ExecuteScript(`
+			idle_events = {}
+			blim.on_connection_event(function(event)
+				if event.type == "disconnected" then
+					table.insert(idle_events, event.reason)
+				end
+			end)
+			assert(blim.set_idle_timeout(100) == true, "set_idle_timeout() MUST succeed while connected")
+		`)
+		suite.Require().NoError(err, "set_idle_timeout() MUST accept a timeout")
+
+		time.Sleep(300 * time.Millisecond)
+
+		err = suite.ExecuteScript(`
+			assert(#idle_events == 1, "MUST receive one disconnected event, got: " .. #idle_events)
+			assert(idle_events[1] == "idle", "reason MUST be idle, got: " .. tostring(idle_events[1]))
+			blim.on_connection_event(nil)
+		`)
+		suite.NoError(err, "idle disconnect MUST be delivered")
+	})
+
+	suite.Run("new connection is hooked again", func() {
+		// GOAL: Verify blim.on_connection_event() registers its handlers on a connection handed out after the first registration
+		//
+		// TEST SCENARIO: Register callback on the first connection → device hands out a new connection → register again →
+		// RSSI polled on the new connection reaches the callback
+
+		err := suite.ExecuteScript(`blim.on_connection_event(function() end)`)
+		suite.Require().NoError(err, "on_connection_event() MUST accept a function")
+
+		other := suite.createLuaApi()
+		defer other.Close()
+		defer func() { _ = other.GetDevice().Disconnect() }()
+		first := suite.LuaApi.device
+		suite.LuaApi.device = other.GetDevice()
+		defer func() { suite.LuaApi.device = first }()
+
+		err = suite.ExecuteScript(`
+			rssi_events = {}
+			blim.on_connection_event(function(event)
+				if event.type == "rssi" then
+					table.insert(rssi_events, event.rssi)
+				end
+			end)
+			assert(blim.set_rssi_interval(20) == true, "set_rssi_interval() MUST succeed on the new connection")
+		`)
+		suite.Require().NoError(err, "on_connection_event() MUST accept a function on the new connection")
+
+		time.Sleep(150 * time.Millisecond)
+
+		err = suite.ExecuteScript(`
+			assert(blim.set_rssi_interval(0) == true, "set_rssi_interval(0) MUST stop polling")
+			assert(#rssi_events >= 2, "new connection MUST deliver events, got: " .. #rssi_events)
+			blim.on_connection_event(nil)
+		`)
+		suite.NoError(err, "events of the new connection MUST be delivered")
+	})
+
+	suite.Run("rejects non-function argument", func() {
+		// GOAL: Verify on_connection_event() validates its argument
+		//
+		// TEST SCENARIO: Call with a string → Lua error raised with clear message
+
+		err := suite.ExecuteScript(`blim.on_connection_event("not a function")`)
+		suite.AssertLuaError(err, "on_connection_event() expects a function or nil argument")
+	})
+}
+
+func (suite *LuaApiTestSuite) TestRawNotificationFunction() {
+	suite.Run("taps notifications before batching", func() {
+		// GOAL: Verify blim.on_raw_notification() sees every notification's raw bytes independent of the subscription mode
+		//
+		// TEST SCENARIO: Register raw hook → Batched subscription with a long interval → two notifications → hook called twice with uuid, bytes and timestamp while the batch is still pending → unregister
+
+		err := suite.ExecuteScript(`
+			raw = {}
+			blim.on_raw_notification(function(uuid, bytes, ts_us)
+				table.insert(raw, {uuid = uuid, bytes = bytes, ts = ts_us})
+			end)
+			batches = 0
+			blim.subscribe{
+				services = {{service = "180d", chars = {"2a37"}}},
+				Mode = "Batched",
+				MaxRate = 10000,
+				Callback = function() batches = batches + 1 end
+			}
+		`)
+		suite.Require().NoError(err, "on_raw_notification() MUST accept a function")
+
+		for _, value := range [][]byte{{0x00, 0x48}, {0x00, 0x49}} {
+			suite.NewPeripheralDataSimulator().
+				WithService("180d").
+				WithCharacteristic("2a37", value).
+				Simulate(false)
+		}
+		time.Sleep(100 * time.Millisecond)
+
+		err = suite.ExecuteScript(`
+			assert(#raw == 2, "every notification MUST reach the raw hook, got: " .. #raw)
+			assert(batches == 0, "batched delivery MUST still be pending")
+			assert(raw[1].uuid == "2a37", "uuid MUST be the characteristic, got: " .. tostring(raw[1].uuid))
+			assert(string.byte(raw[1].bytes, 2) == 0x48 and string.byte(raw[2].bytes, 2) == 0x49, "raw bytes MUST arrive in order")
+			assert(raw[1].ts > 0 and raw[2].ts >= raw[1].ts, "timestamps MUST be set and monotonic")
+			blim.on_raw_notification(nil)
+		`)
+		suite.NoError(err, "raw hook MUST see notifications before batching")
+	})
+
+	suite.Run("callback errors are contained", func() {
+		// GOAL: Verify a failing raw hook does not break notification delivery
+		//
+		// TEST SCENARIO: Register a hook that errors → EveryUpdate subscription → notification still reaches the subscription callback
+
+		err := suite.ExecuteScript(`
+			blim.on_raw_notification(function() error("boom") end)
+			delivered = 0
+			blim.subscribe{
+				services = {{service = "180d", chars = {"2a37"}}},
+				Mode = "EveryUpdate",
+				Callback = function() delivered = delivered + 1 end
+			}
+		`)
+		suite.Require().NoError(err)
+
+		suite.NewPeripheralDataSimulator().
+			WithService("180d").
+			WithCharacteristic("2a37", []byte{0x00, 0x50}).
+			Simulate(false)
+		time.Sleep(100 * time.Millisecond)
+
+		err = suite.ExecuteScript(`
+			assert(delivered == 1, "subscription MUST still receive the notification, got: " .. delivered)
+			blim.on_raw_notification(nil)
+		`)
+		suite.NoError(err, "raw hook failure MUST NOT affect subscriptions")
+	})
+
+	suite.Run("new connection is hooked again", func() {
+		// GOAL: Verify blim.on_raw_notification() registers its handler on a connection handed out after the first registration
+		//
+		// TEST SCENARIO: Register hook on the first connection → device hands out a new connection → register again →
+		// notification on the new connection reaches the hook
+
+		err := suite.ExecuteScript(`blim.on_raw_notification(function() end)`)
+		suite.Require().NoError(err, "on_raw_notification() MUST accept a function")
+
+		other := suite.createLuaApi()
+		defer other.Close()
+		defer func() { _ = other.GetDevice().Disconnect() }()
+		first := suite.LuaApi.device
+		suite.LuaApi.device = other.GetDevice()
+		defer func() { suite.LuaApi.device = first }()
+
+		err = suite.ExecuteScript(`
+			raw = 0
+			blim.on_raw_notification(function() raw = raw + 1 end)
+		`)
+		suite.Require().NoError(err, "on_raw_notification() MUST accept a function on the new connection")
+
+		suite.NewPeripheralDataSimulator().
+			WithService("180d").
+			WithCharacteristic("2a37", []byte{0x00, 0x48}).
+			Simulate(false)
+		time.Sleep(50 * time.Millisecond)
+
+		err = suite.ExecuteScript(`
+			assert(raw == 1, "new connection MUST deliver notifications to the hook, got: " .. raw)
+			blim.on_raw_notification(nil)
+		`)
+		suite.NoError(err, "notifications of the new connection MUST reach the hook")
+	})
+
+	suite.Run("rejects non-function argument", func() {
+		// GOAL: Verify blim.on_raw_notification() validates its argument
+		//
+		// TEST SCENARIO: on_raw_notification(42) → Lua error
+
+		err := suite.ExecuteScript(`blim.on_raw_notification(42)`)
+		suite.AssertLuaError(err, "on_raw_notification() expects a function or nil argument")
+	})
+}
+
+func (suite *LuaApiTestSuite) TestReadCachedFunction() {
+	suite.WithPeripheral().FromJSON(`{
+		"services": [
+			{
+				"uuid": "180F",
+				"characteristics": [
+					{ "uuid": "2A19", "properties": "read,notify", "value": [85] }
+				]
+			}
+		]
+	}`).Build()
+
+	suite.Run("returns notified value within TTL", func() {
+		// GOAL: Verify char:read_cached() serves the last notified value within the TTL and reads the device otherwise
+		//
+		// TEST SCENARIO: Simulate notification → read_cached(60000) returns notified value → read_cached(0) returns device value
+
+		suite.NewPeripheralDataSimulator().
+			WithService("180F").
+			WithCharacteristic("2A19", []byte{42}).
+			Simulate(false)
+		time.Sleep(50 * time.Millisecond)
+
+		err := suite.ExecuteScript(`
+			local char = blim.characteristic("180F", "2A19")
+			local value, err = char:read_cached(60000)
+			assert(err == nil, "read_cached() MUST succeed, got: " .. tostring(err))
+			assert(string.byte(value) == 42, "cached value MUST be the notified value, got: " .. string.byte(value))
+
+			value, err = char:read_cached(0)
+			assert(err == nil, "read_cached(0) MUST succeed, got: " .. tostring(err))
+			assert(string.byte(value) == 85, "zero TTL MUST read the device value, got: " .. string.byte(value))
+		`)
+		suite.NoError(err, "read_cached() MUST honor the TTL")
+	})
+
+	suite.Run("rejects invalid TTL", func() {
+		// GOAL: Verify read_cached() validates its argument
+		//
+		// TEST SCENARIO: Call with a string → Lua error raised with clear message
+
+		err := suite.ExecuteScript(`blim.characteristic("180F", "2A19"):read_cached("soon")`)
+		suite.AssertLuaError(err, "read_cached(ttl_ms) expects a non-negative number argument")
+	})
+}
+
+func (suite *LuaApiTestSuite) TestLastValueFunction() {
+	suite.WithPeripheral().FromJSON(`{
+		"services": [
+			{
+				"uuid": "180D",
+				"characteristics": [
+					{ "uuid": "2A37", "properties": "notify", "value": [0, 60] }
+				]
+			}
+		]
+	}`).Build()
+
+	suite.Run("returns last notified value and timestamp", func() {
+		// GOAL: Verify char:last_value() returns nil before any notification and the latest value afterwards
+		//
+		// TEST SCENARIO: last_value() before notification → nil → simulate two notifications → last_value() returns latest bytes and timestamp
+
+		err := suite.ExecuteScript(`
+			local char = blim.characteristic("180D", "2A37")
+			assert(char:last_value() == nil, "last_value() MUST be nil before any notification")
+		`)
+		suite.Require().NoError(err, "last_value() MUST return nil initially")
+
+		before := time.Now().UnixMicro()
+		for _, bpm := range []byte{70, 72} {
+			suite.NewPeripheralDataSimulator().
+				WithService("180D").
+				WithCharacteristic("2A37", []byte{0x00, bpm}).
+				Simulate(false)
+		}
+		time.Sleep(50 * time.Millisecond)
+
+		err = suite.ExecuteScript(fmt.Sprintf(`
+			local value, ts = blim.characteristic("180D", "2A37"):last_value()
+			assert(value ~= nil, "last_value() MUST return a value after notification")
+			assert(string.byte(value, 2) == 72, "last_value() MUST return the latest value, got: " .. string.byte(value, 2))
+			assert(ts >= %d, "timestamp MUST be the notification time in microseconds, got: " .. tostring(ts))
+		`, before))
+		suite.NoError(err, "last_value() MUST expose the latest notification")
+	})
+}
+
+func (suite *LuaApiTestSuite) TestUnpackFunction() {
+	suite.WithPeripheral().FromJSON(`{
+		"services": [
+			{
+				"uuid": "FFF0",
+				"characteristics": [
+					{ "uuid": "FFF1", "properties": "read", "value": [52, 18, 255, 0, 0, 128, 63] }
+				]
+			}
+		]
+	}`).Build()
+
+	suite.Run("decodes fields as multiple return values", func() {
+		// GOAL: Verify char:unpack() reads the value and returns decoded fields
+		//
+		// TEST SCENARIO: Characteristic with uint16 + uint8 + float payload → unpack("<HBf") → three decoded values
+
+		err := suite.ExecuteScript(`
+			local char = blim.characteristic("FFF0", "FFF1")
+			local a, b, c = char:unpack("<HBf")
+			assert(a == 0x1234, "uint16 MUST decode, got: " .. tostring(a))
+			assert(b == 255, "uint8 MUST decode, got: " .. tostring(b))
+			assert(c == 1.0, "float MUST decode, got: " .. tostring(c))
+		`)
+		suite.NoError(err, "unpack() MUST decode the payload")
+	})
+
+	suite.Run("returns error on length mismatch", func() {
+		// GOAL: Verify unpack() reports format/length mismatches as (nil, error)
+		//
+		// TEST SCENARIO: Format describes 2 bytes for a 7-byte value → (nil, "unpack() failed: ...")
+
+		err := suite.ExecuteScript(`
+			local value, err = blim.characteristic("FFF0", "FFF1"):unpack("<H")
+			assert(value == nil, "value MUST be nil on mismatch")
+			assert(err:find("unpack%(%) failed: data length mismatch"), "error MUST describe the mismatch, got: " .. tostring(err))
+		`)
+		suite.NoError(err, "unpack() MUST return an error on mismatch")
+	})
+
+	suite.Run("rejects missing format", func() {
+		// GOAL: Verify unpack() validates its argument
+		//
+		// TEST SCENARIO: Call without a format → Lua error raised with clear message
+
+		err := suite.ExecuteScript(`blim.characteristic("FFF0", "FFF1"):unpack()`)
+		suite.AssertLuaError(err, "unpack(format) expects a format string argument")
+	})
+}
+
+func (suite *LuaApiTestSuite) TestSnapshotRestoreSubscriptions() {
+	suite.WithPeripheral().FromJSON(`{
+		"services": [
+			{
+				"uuid": "180D",
+				"characteristics": [
+					{ "uuid": "2A37", "properties": "notify", "value": [0, 60] },
+					{ "uuid": "2A3A", "properties": "indicate", "value": [1] }
+				]
+			}
+		]
+	}`).Build()
+
+	suite.Run("snapshot is compatible with restore", func() {
+		// GOAL: Verify snapshot_subscriptions() captures enabled CCCDs and restore_subscriptions() re-subscribes them
+		//
+		// TEST SCENARIO: Empty snapshot → subscribe notify + indicate → snapshot groups by mode → restore → notification delivered to new callback
+
+		err := suite.ExecuteScript(`
+			local empty = blim.snapshot_subscriptions()
+			assert(#empty.services == 0, "snapshot MUST be empty before subscribing")
+			assert(blim.restore_subscriptions(empty, function() end) == 0, "restoring an empty snapshot MUST be a no-op")
+
+			blim.subscribe{
+				services = {
+					{service="180D", chars={"2A37"}},
+					{service="180D", chars={"2A3A"}, indicate=true}
+				},
+				Callback = function() end
+			}
+
+			snapshot = blim.snapshot_subscriptions()
+			assert(#snapshot.services == 2, "snapshot MUST group by mode, got: " .. #snapshot.services)
+			for _, entry in ipairs(snapshot.services) do
+				if entry.indicate then
+					assert(entry.chars[1] == "2a3a", "indicate entry MUST contain 2a3a")
+				else
+					assert(entry.chars[1] == "2a37", "notify entry MUST contain 2a37")
+				end
+			end
+
+			restored = {}
+			local count = blim.restore_subscriptions(snapshot, function(record)
+				for uuid in pairs(record.Values) do
+					restored[uuid] = true
+				end
+			end)
+			assert(count == 2, "MUST restore both characteristics, got: " .. count)
+		`)
+		suite.Require().NoError(err, "snapshot and restore MUST succeed")
+
+		suite.NewPeripheralDataSimulator().
+			WithService("180D").
+			WithCharacteristic("2A37", []byte{0x00, 0x48}).
+			Simulate(false)
+		time.Sleep(50 * time.Millisecond)
+
+		err = suite.ExecuteScript(`assert(restored["2a37"], "restored subscription MUST deliver notifications")`)
+		suite.NoError(err, "restored subscription MUST be active")
+	})
+
+	suite.Run("rejects invalid snapshot", func() {
+		// GOAL: Verify restore_subscriptions() validates its arguments
+		//
+		// TEST SCENARIO: Pass a string snapshot → Lua error raised with clear message
+
+		err := suite.ExecuteScript(`blim.restore_subscriptions("nope", function() end)`)
+		suite.AssertLuaError(err, "restore_subscriptions() expects a snapshot table from snapshot_subscriptions()")
+	})
+}
+
+func (suite *LuaApiTestSuite) TestSubscribeFilter() {
+	suite.Run("drops values rejected by the filter", func() {
+		// GOAL: Verify the Filter predicate runs before the callback and skips rejected values
+		//
+		// TEST SCENARIO: Filter accepts first byte >= 0x10 → send 0x05, 0x20, 0x30 → callback receives only 0x20 and 0x30
+
+		err := suite.ExecuteScript(`
+			filtered = {}
+			filter_calls = 0
+			blim.subscribe{
+				services = {{service = "1234", chars = {"5678"}}},
+				Mode = "EveryUpdate",
+				Filter = function(uuid, data)
+					filter_calls = filter_calls + 1
+					return uuid == "5678" and string.byte(data, 1) >= 0x10
+				end,
+				Callback = function(record)
+					table.insert(filtered, string.byte(record.Values["5678"], 1))
+				end
+			}
+		`)
+		suite.Require().NoError(err, "subscription with Filter MUST succeed")
+
+		for _, v := range []byte{0x05, 0x20, 0x30} {
+			suite.NewPeripheralDataSimulator().
+				WithService("1234").
+				WithCharacteristic("5678", []byte{v}).
+				Simulate(false)
+			time.Sleep(20 * time.Millisecond)
+		}
+		time.Sleep(50 * time.Millisecond)
+
+		err = suite.ExecuteScript(`
+			assert(filter_calls == 3, "filter MUST run for every value, got: " .. filter_calls)
+			assert(#filtered == 2, "callback MUST only receive accepted values, got: " .. #filtered)
+			assert(filtered[1] == 0x20 and filtered[2] == 0x30, "callback MUST receive accepted values in order")
+		`)
+		suite.NoError(err, "Filter MUST drop rejected values")
+	})
+
+	suite.Run("rejects non-function filter", func() {
+		// GOAL: Verify blim.subscribe() validates the Filter field
+		//
+		// TEST SCENARIO: Filter is a string → Lua error raised with clear message
+
+		err := suite.ExecuteScript(`
+			blim.subscribe{
+				services = {{service = "1234", chars = {"5678"}}},
+				Filter = "not a function",
+				Callback = function(record) end
+			}
+		`)
+		suite.AssertLuaError(err, "subscription Filter must be a function(uuid, bytes)")
+	})
+}
+
+func (suite *LuaApiTestSuite) TestSubscribeKeyFn() {
+	suite.Run("groups batched values by key", func() {
+		// GOAL: Verify KeyFn re-keys batched values by the Lua-returned key instead of the characteristic UUID
+		//
+		// TEST SCENARIO: KeyFn returns "ch" .. first byte → send channels 1, 2, 1 in one batch → BatchValues has ch1 (2 values) and ch2 (1 value)
+
+		err := suite.ExecuteScript(`
+			batches = {}
+			blim.subscribe{
+				services = {{service = "1234", chars = {"5678"}}},
+				Mode = "Batched",
+				MaxRate = 200,
+				KeyFn = function(data)
+					return "ch" .. string.byte(data, 1)
+				end,
+				Callback = function(record)
+					table.insert(batches, record.BatchValues)
+				end
+			}
+		`)
+		suite.Require().NoError(err, "subscription with KeyFn MUST succeed")
+
+		for _, v := range [][]byte{{0x01, 0xAA}, {0x02, 0xBB}, {0x01, 0xCC}} {
+			suite.NewPeripheralDataSimulator().
+				WithService("1234").
+				WithCharacteristic("5678", v).
+				Simulate(false)
+			time.Sleep(5 * time.Millisecond)
+		}
+		time.Sleep(400 * time.Millisecond)
+
+		err = suite.ExecuteScript(`
+			local ch1, ch2 = {}, {}
+			for _, batch in ipairs(batches) do
+				assert(batch["5678"] == nil, "values MUST NOT be keyed by UUID")
+				for _, data in ipairs(batch.ch1 or {}) do table.insert(ch1, string.byte(data, 2)) end
+				for _, data in ipairs(batch.ch2 or {}) do table.insert(ch2, string.byte(data, 2)) end
+			end
+			assert(#ch1 == 2 and ch1[1] == 0xAA and ch1[2] == 0xCC, "ch1 MUST hold both channel 1 values in order")
+			assert(#ch2 == 1 and ch2[1] == 0xBB, "ch2 MUST hold the channel 2 value")
+		`)
+		suite.NoError(err, "KeyFn MUST group values per key")
+	})
+
+	suite.Run("keeps UUID when KeyFn fails", func() {
+		// GOAL: Verify a failing or non-string KeyFn never drops data and falls back to the UUID key
+		//
+		// TEST SCENARIO: KeyFn errors for 0x01 and returns nil otherwise → send 0x01, 0x02 → callback receives both under "5678"
+
+		err := suite.ExecuteScript(`
+			fallback = {}
+			blim.subscribe{
+				services = {{service = "1234", chars = {"5678"}}},
+				Mode = "EveryUpdate",
+				KeyFn = function(data)
+					if string.byte(data, 1) == 0x01 then error("boom") end
+					return nil
+				end,
+				Callback = function(record)
+					table.insert(fallback, string.byte(record.Values["5678"], 1))
+				end
+			}
+		`)
+		suite.Require().NoError(err, "subscription with KeyFn MUST succeed")
+
+		for _, v := range []byte{0x01, 0x02} {
+			suite.NewPeripheralDataSimulator().
+				WithService("1234").
+				WithCharacteristic("5678", []byte{v}).
+				Simulate(false)
+			time.Sleep(20 * time.Millisecond)
+		}
+		time.Sleep(50 * time.Millisecond)
+
+		err = suite.ExecuteScript(`
+			assert(#fallback == 2 and fallback[1] == 0x01 and fallback[2] == 0x02, "values MUST be delivered under the UUID")
+		`)
+		suite.NoError(err, "KeyFn failures MUST fall back to UUID keys")
+	})
+
+	suite.Run("rejects non-function KeyFn", func() {
+		// GOAL: Verify blim.subscribe() validates the KeyFn field
+		//
+		// TEST SCENARIO: KeyFn is a number → Lua error raised with clear message
+
+		err := suite.ExecuteScript(`
+			blim.subscribe{
+				services = {{service = "1234", chars = {"5678"}}},
+				KeyFn = 1,
+				Callback = function(record) end
+			}
+		`)
+		suite.AssertLuaError(err, "subscription KeyFn must be a function(bytes)")
+	})
+}
+
+func (suite *LuaApiTestSuite) TestSubscribeDuration() {
+	suite.Run("tears down after duration", func() {
+		// GOAL: Verify Duration stops the subscription automatically and fires a final record with the end marker
+		//
+		// TEST SCENARIO: Subscribe with Duration = 150 → send 0x01 → end marker received → send 0x02 after teardown → not delivered
+
+		err := suite.ExecuteScript(`
+			values = {}
+			ended = 0
+			blim.subscribe{
+				services = {{service = "1234", chars = {"5678"}}},
+				Duration = 150,
+				Callback = function(record)
+					if record["end"] then
+						ended = ended + 1
+						assert(record.Values == nil, "end marker MUST carry no values")
+						return
+					end
+					table.insert(values, string.byte(record.Values["5678"], 1))
+				end
+			}
+		`)
+		suite.Require().NoError(err, "subscription with Duration MUST succeed")
+
+		suite.NewPeripheralDataSimulator().
+			WithService("1234").
+			WithCharacteristic("5678", []byte{0x01}).
+			Simulate(false)
+		time.Sleep(300 * time.Millisecond)
+
+		suite.NewPeripheralDataSimulator().
+			WithService("1234").
+			WithCharacteristic("5678", []byte{0x02}).
+			Simulate(false)
+		time.Sleep(50 * time.Millisecond)
+
+		err = suite.ExecuteScript(`
+			assert(#values == 1 and values[1] == 0x01, "only values before the teardown MUST be delivered, got: " .. #values)
+			assert(ended == 1, "end marker MUST be delivered exactly once, got: " .. ended)
+		`)
+		suite.NoError(err, "Duration MUST tear the subscription down")
+	})
+
+	suite.Run("rejects invalid duration", func() {
+		// GOAL: Verify blim.subscribe() validates the Duration field
+		//
+		// TEST SCENARIO: Duration is negative → Lua error raised with clear message
+
+		err := suite.ExecuteScript(`
+			blim.subscribe{
+				services = {{service = "1234", chars = {"5678"}}},
+				Duration = -1,
+				Callback = function(record) end
+			}
+		`)
+		suite.AssertLuaError(err, "subscription Duration must be a positive number of milliseconds")
+	})
+}
+
+func (suite *LuaApiTestSuite) TestSubscribeAggregateWindow() {
+	suite.Run("delivers latest value and empty windows", func() {
+		// GOAL: Verify AggregateWindow flushes the latest value per window and EmitEmpty delivers quiet windows
+		//
+		// TEST SCENARIO: Aggregated with AggregateWindow = 100, EmitEmpty → send 0x01 → value delivered → quiet windows delivered with empty Values
+
+		err := suite.ExecuteScript(`
+			values = {}
+			empty = 0
+			blim.subscribe{
+				services = {{service = "1234", chars = {"5678"}}},
+				Mode = "Aggregated",
+				AggregateWindow = 100,
+				EmitEmpty = true,
+				Callback = function(record)
+					local v = record.Values["5678"]
+					if v then
+						table.insert(values, string.byte(v, 1))
+					else
+						empty = empty + 1
+					end
+				end
+			}
+		`)
+		suite.Require().NoError(err, "subscription with AggregateWindow MUST succeed")
+
+		suite.NewPeripheralDataSimulator().
+			WithService("1234").
+			WithCharacteristic("5678", []byte{0x01}).
+			Simulate(false)
+		time.Sleep(450 * time.Millisecond)
+
+		err = suite.ExecuteScript(`
+			assert(#values == 1 and values[1] == 0x01, "value MUST be delivered once, got: " .. #values)
+			assert(empty >= 2, "quiet windows MUST be delivered, got: " .. empty)
+		`)
+		suite.NoError(err, "AggregateWindow MUST flush on a steady timer")
+	})
+
+	suite.Run("rejects invalid options", func() {
+		// GOAL: Verify blim.subscribe() validates AggregateWindow and EmitEmpty
+		//
+		// TEST SCENARIO: Zero window, EmitEmpty without window, window outside Aggregated mode → Lua errors
+
+		err := suite.ExecuteScript(`
+			blim.subscribe{services = {{service = "1234", chars = {"5678"}}}, Mode = "Aggregated", AggregateWindow = 0, Callback = function() end}
+		`)
+		suite.AssertLuaError(err, "subscription AggregateWindow must be a positive number of milliseconds")
+
+		err = suite.ExecuteScript(`
+			blim.subscribe{services = {{service = "1234", chars = {"5678"}}}, Mode = "Aggregated", EmitEmpty = true, Callback = function() end}
+		`)
+		suite.AssertLuaError(err, "subscription EmitEmpty requires AggregateWindow")
+
+		err = suite.ExecuteScript(`
+			blim.subscribe{services = {{service = "1234", chars = {"5678"}}}, AggregateWindow = 100, Callback = function() end}
+		`)
+		suite.AssertLuaError(err, "aggregate window requires Aggregated stream mode")
+	})
+}
+
+func (suite *LuaApiTestSuite) TestSubscribeBuffer() {
+	suite.Run("absorbs bursts", func() {
+		// GOAL: Verify Buffer enlarges the notification queue so a burst larger than the default is delivered whole
+		//
+		// TEST SCENARIO: Batched subscription with Buffer = 512 → burst of 300 simulated notifications → all 300 delivered
+
+		suite.LuaApi.SetSimulatedNotifications(true)
+		err := suite.ExecuteScript(`
+			received = 0
+			blim.subscribe{
+				services = {{service = "180d", chars = {"2a37"}}},
+				Mode = "Batched",
+				MaxRate = 200,
+				Buffer = 512,
+				Callback = function(record)
+					received = received + #record.BatchValues["2a37"]
+				end
+			}
+			for i = 1, 300 do
+				assert(blim.simulate_notification("180d", "2a37", string.char(i % 256)))
+			end
+		`)
+		suite.Require().NoError(err, "subscription with Buffer MUST succeed")
+		time.Sleep(500 * time.Millisecond)
+
+		err = suite.ExecuteScript(`assert(received == 300, "whole burst MUST be delivered, got: " .. received)`)
+		suite.NoError(err, "Buffer MUST absorb the burst")
+	})
+
+	suite.Run("rejects invalid buffer", func() {
+		// GOAL: Verify blim.subscribe() validates the Buffer field
+		//
+		// TEST SCENARIO: Buffer is zero → Lua error raised with clear message
+
+		err := suite.ExecuteScript(`
+			blim.subscribe{services = {{service = "1234", chars = {"5678"}}}, Buffer = 0, Callback = function() end}
+		`)
+		suite.AssertLuaError(err, "subscription Buffer must be a positive number of notifications")
+	})
+}
+
+func (suite *LuaApiTestSuite) TestSubscribeRecordOrder() {
+	// GOAL: Verify record.order lists the record keys in the configured characteristic order
+	//
+	// TEST SCENARIO: Aggregated subscription to chars {"2a38", "2a37"} → notify both → record.order == {"2a38", "2a37"} and matches Values
+
+	err := suite.ExecuteScript(`
+		orders = {}
+		blim.subscribe{
+			services = {{service = "180d", chars = {"2a38", "2a37"}}},
+			Mode = "Aggregated",
+			AggregateWindow = 100,
+			Callback = function(record)
+				table.insert(orders, record.order)
+			end
+		}
+	`)
+	suite.Require().NoError(err, "subscription MUST succeed")
+
+	suite.NewPeripheralDataSimulator().
+		WithService("180d").
+		WithCharacteristic("2a37", []byte{0x00, 0x48}).
+		WithCharacteristic("2a38", []byte{0x01}).
+		Simulate(false)
+	time.Sleep(250 * time.Millisecond)
+
+	err = suite.ExecuteScript(`
+		assert(#orders >= 1, "aggregated record MUST be delivered")
+		local order = orders[1]
+		assert(#order == 2, "order MUST list both characteristics, got: " .. #order)
+		assert(order[1] == "2a38" and order[2] == "2a37", "order MUST follow the configured chars, got: " .. table.concat(order, ","))
+	`)
+	suite.NoError(err, "record.order MUST be deterministic")
+}
+
+func (suite *LuaApiTestSuite) TestSubscribeReadInitial() {
+	suite.Run("delivers current values before notifications", func() {
+		// GOAL: Verify ReadInitial reads subscribed characteristics once and delivers them flagged as initial
+		//
+		// TEST SCENARIO: Subscribe with ReadInitial = true → initial record with readable values and initial == true → later notification without the flag
+
+		err := suite.ExecuteScript(`
+			records = {}
+			blim.subscribe{
+				services = {{service = "180d", chars = {"2a37", "2a38"}}},
+				Mode = "EveryUpdate",
+				ReadInitial = true,
+				Callback = function(record)
+					table.insert(records, record)
+				end
+			}
+		`)
+		suite.Require().NoError(err, "subscription with ReadInitial MUST succeed")
+		time.Sleep(100 * time.Millisecond)
+
+		suite.NewPeripheralDataSimulator().
+			WithService("180d").
+			WithCharacteristic("2a37", []byte{0x00, 0x48}).
+			Simulate(false)
+		time.Sleep(100 * time.Millisecond)
+
+		err = suite.ExecuteScript(`
+			assert(#records == 2, "initial and notified records MUST be delivered, got: " .. #records)
+			local initial = records[1]
+			assert(initial.initial == true, "first record MUST be flagged initial")
+			assert(initial.Values["2a37"] ~= nil and initial.Values["2a38"] ~= nil, "initial record MUST carry every readable subscribed characteristic")
+			assert(records[2].initial == nil, "notified record MUST NOT be flagged initial")
+			assert(string.byte(records[2].Values["2a37"], 2) == 0x48, "notification MUST follow the initial record")
+		`)
+		suite.NoError(err, "ReadInitial MUST prime the callback with current values")
+	})
+
+	suite.Run("rejects non-boolean ReadInitial", func() {
+		// GOAL: Verify blim.subscribe() validates ReadInitial
+		//
+		// TEST SCENARIO: ReadInitial = 1 → Lua error
+
+		err := suite.ExecuteScript(`
+			blim.subscribe{services = {{service = "1234", chars = {"5678"}}}, ReadInitial = 1, Callback = function() end}
+		`)
+		suite.AssertLuaError(err, "subscription ReadInitial must be a boolean")
+	})
+}
+
+func (suite *LuaApiTestSuite) TestSubscribeRequireAll() {
+	suite.Run("names every missing attribute", func() {
+		// GOAL: Verify RequireAll fails the subscription with all missing services and characteristics named
+		//
+		// TEST SCENARIO: Subscribe with RequireAll to a missing char of 180d and a missing service → one error listing both
+
+		err := suite.ExecuteScript(`
+			blim.subscribe{
+				services = {
+					{service = "180d", chars = {"2a37", "2a39"}},
+					{service = "1816", chars = {"2a5b"}},
+				},
+				RequireAll = true,
+				Callback = function() end
+			}
+		`)
+		suite.AssertLuaError(err, "required attributes missing: 2a39 (in service 180d), 1816")
+	})
+
+	suite.Run("subscribes when everything is present", func() {
+		// GOAL: Verify RequireAll does not affect a subscription whose attributes all exist
+		//
+		// TEST SCENARIO: Subscribe with RequireAll to existing chars → no error
+
+		err := suite.ExecuteScript(`
+			blim.subscribe{services = {{service = "180d", chars = {"2a37"}}}, RequireAll = true, Callback = function() end}
+		`)
+		suite.NoError(err, "subscription with all attributes present MUST succeed")
+	})
+
+	suite.Run("rejects non-boolean RequireAll", func() {
+		// GOAL: Verify blim.subscribe() validates RequireAll
+		//
+		// TEST SCENARIO: RequireAll = "yes" → Lua error
+
+		err := suite.ExecuteScript(`
+			blim.subscribe{services = {{service = "180d", chars = {"2a37"}}}, RequireAll = "yes", Callback = function() end}
+		`)
+		suite.AssertLuaError(err, "subscription RequireAll must be a boolean")
+	})
+}
+
+func (suite *LuaApiTestSuite) TestSubscribeParsed() {
+	suite.Run("adds parsed values for characteristics with parsers", func() {
+		// GOAL: Verify Parsed = true adds record.Parsed[uuid] for characteristics with a registered parser, keeping raw values
+		//
+		// TEST SCENARIO: Subscribe to Current Time (2A2B) and raw 5678 with Parsed = true → notify both → Parsed has 2a2b only
+
+		suite.WithPeripheral().
+			WithService("1805").
+			WithCharacteristic("2a2b", "read,notify", []byte{})
+
+		err := suite.ExecuteScript(`
+			parsed_records = {}
+			blim.subscribe{
+				services = {{service = "1805", chars = {"2a2b"}}, {service = "1234", chars = {"5678"}}},
+				Parsed = true,
+				Callback = function(record)
+					table.insert(parsed_records, record)
+				end
+			}
+		`)
+		suite.Require().NoError(err, "subscription with Parsed MUST succeed")
+
+		suite.NewPeripheralDataSimulator().
+			WithService("1805").
+			WithCharacteristic("2a2b", []byte{0xEA, 0x07, 0x0A, 0x10, 0x0C, 0x1E, 0x2D, 0x05, 0x80, 0x09}).
+			WithService("1234").
+			WithCharacteristic("5678", []byte{0x01}).
+			Simulate(false)
+		time.Sleep(100 * time.Millisecond)
+
+		err = suite.ExecuteScript(`
+			local time_seen, raw_seen = false, false
+			for _, record in ipairs(parsed_records) do
+				assert(type(record.Parsed) == "table", "record.Parsed MUST be a table")
+				if record.Values["2a2b"] then
+					time_seen = true
+					assert(#record.Values["2a2b"] == 10, "raw value MUST be kept alongside the parsed one")
+					local t = record.Parsed["2a2b"]
+					assert(t and t.datetime.year == 2026 and t.day_of_week == 5, "Current Time MUST be parsed")
+				end
+				if record.Values["5678"] then
+					raw_seen = true
+					assert(record.Parsed["5678"] == nil, "characteristics without a parser MUST only have raw values")
+				end
+			end
+			assert(time_seen and raw_seen, "both characteristics MUST be delivered")
+		`)
+		suite.NoError(err, "Parsed MUST decode known characteristics")
+	})
+
+	suite.Run("rejects non-boolean Parsed", func() {
+		// GOAL: Verify blim.subscribe() validates the Parsed field
+		//
+		// TEST SCENARIO: Parsed is a string → Lua error raised with clear message
+
+		err := suite.ExecuteScript(`
+			blim.subscribe{
+				services = {{service = "1234", chars = {"5678"}}},
+				Parsed = "yes",
+				Callback = function(record) end
+			}
+		`)
+		suite.AssertLuaError(err, "subscription Parsed must be a boolean")
+	})
+}
+
+func (suite *LuaApiTestSuite) TestSubscribeReassemble() {
+	suite.Run("delivers terminated messages", func() {
+		// GOAL: Verify Reassemble buffers fragments and invokes the callback only with complete messages
+		//
+		// TEST SCENARIO: Terminator "\n" → send "he", "llo\nwo", "rld\n" → callback receives "hello" and "world"
+
+		err := suite.ExecuteScript(`
+			messages = {}
+			blim.subscribe{
+				services = {{service = "1234", chars = {"5678"}}},
+				Reassemble = {terminator = "\n"},
+				Callback = function(record)
+					table.insert(messages, record.Values["5678"])
+				end
+			}
+		`)
+		suite.Require().NoError(err, "subscription with Reassemble MUST succeed")
+
+		for _, fragment := range []string{"he", "llo\nwo", "rld\n"} {
+			suite.NewPeripheralDataSimulator().
+				WithService("1234").
+				WithCharacteristic("5678", []byte(fragment)).
+				Simulate(false)
+			time.Sleep(20 * time.Millisecond)
+		}
+		time.Sleep(50 * time.Millisecond)
+
+		err = suite.ExecuteScript(`
+			assert(#messages == 2, "callback MUST only run for complete messages, got: " .. #messages)
+			assert(messages[1] == "hello" and messages[2] == "world", "messages MUST be reassembled without terminator")
+		`)
+		suite.NoError(err, "Reassemble MUST join fragments")
+	})
+
+	suite.Run("rejects invalid options", func() {
+		// GOAL: Verify blim.subscribe() validates the Reassemble table
+		//
+		// TEST SCENARIO: Missing terminator, non-positive max_size → Lua errors with clear messages
+
+		err := suite.ExecuteScript(`
+			blim.subscribe{
+				services = {{service = "1234", chars = {"5678"}}},
+				Reassemble = {max_size = 10},
+				Callback = function(record) end
+			}
+		`)
+		suite.AssertLuaError(err, "subscription Reassemble.terminator must be a non-empty string")
+
+		err = suite.ExecuteScript(`
+			blim.subscribe{
+				services = {{service = "1234", chars = {"5678"}}},
+				Reassemble = {terminator = "\n", max_size = 0},
+				Callback = function(record) end
+			}
+		`)
+		suite.AssertLuaError(err, "subscription Reassemble.max_size must be a positive number of bytes")
+	})
+}
+
+func (suite *LuaApiTestSuite) TestEnabledNotifications() {
+	// GOAL: Verify blim.enabled_notifications() lists characteristics armed at the CCCD level
+	//
+	// TEST SCENARIO: No subscriptions → empty array → subscribe to 5678 and 2A37 → both UUIDs reported in sorted order
+
+	err := suite.ExecuteScript(`
+		assert(#blim.enabled_notifications() == 0, "no notifications MUST be enabled before subscribing")
+
+		blim.subscribe{
+			services = {{service = "1234", chars = {"5678"}}, {service = "180d", chars = {"2a37"}}},
+			Callback = function(record) end
+		}
+
+		local enabled = blim.enabled_notifications()
+		assert(#enabled == 2, "both subscribed characteristics MUST be enabled, got: " .. #enabled)
+		assert(enabled[1] == "2a37" and enabled[2] == "5678", "UUIDs MUST be sorted, got: " .. table.concat(enabled, ","))
+	`)
+	suite.NoError(err, "enabled_notifications() MUST reflect subscriptions")
+}
+
+func (suite *LuaApiTestSuite) TestFlushWrites() {
+	suite.Run("flushes writes without response", func() {
+		// GOAL: Verify blim.flush_writes() succeeds after write-without-response commands
+		//
+		// TEST SCENARIO: Write twice without response → flush_writes() with default and explicit timeout → true returned
+
+		err := suite.ExecuteScript(`
+			local char = blim.characteristic("1234", "ABCD")
+			assert(char.write("one", false))
+			assert(char.write("two", false))
+
+			local ok, err = blim.flush_writes()
+			assert(ok == true, "flush_writes() MUST return true, got error: " .. tostring(err))
+			assert(blim.flush_writes(1000) == true, "flush_writes(timeout_ms) MUST return true")
+		`)
+		suite.NoError(err, "flush_writes() MUST succeed")
+	})
+
+	suite.Run("invalid timeout", func() {
+		// GOAL: Verify blim.flush_writes() rejects non-positive timeouts
+		//
+		// TEST SCENARIO: flush_writes(0) → Lua error raised
+
+		err := suite.ExecuteScript(`blim.flush_writes(0)`)
+		suite.AssertLuaError(err, "flush_writes([timeout_ms]) expects a positive number argument")
+	})
+}
+
+func (suite *LuaApiTestSuite) TestPropertyConstants() {
+	suite.Run("constants match property values", func() {
+		// GOAL: Verify blim.PROPERTIES exposes GATT property bits matching the properties table values
+		//
+		// TEST SCENARIO: Read constants → values follow GATT bit order → READ/NOTIFY equal properties.read/notify values
+
+		err := suite.ExecuteScript(`
+			local P = blim.PROPERTIES
+			assert(P.BROADCAST == 0x01 and P.READ == 0x02 and P.WRITE_WITHOUT_RESPONSE == 0x04 and P.WRITE == 0x08,
+				"low property bits MUST follow GATT order")
+			assert(P.NOTIFY == 0x10 and P.INDICATE == 0x20 and P.AUTHENTICATED_SIGNED_WRITES == 0x40 and P.EXTENDED_PROPERTIES == 0x80,
+				"high property bits MUST follow GATT order")
+
+			local char = blim.characteristic("180d", "2a37")
+			assert(char.properties.read.value == P.READ, "READ MUST match properties.read.value")
+			assert(char.properties.notify.value == P.NOTIFY, "NOTIFY MUST match properties.notify.value")
+		`)
+		suite.NoError(err, "property constants MUST be exposed")
+	})
+
+	suite.Run("has_property tests bits", func() {
+		// GOAL: Verify char:has_property() checks single and combined property bits
+		//
+		// TEST SCENARIO: read,notify characteristic → READ/NOTIFY/READ+NOTIFY true → WRITE and READ+WRITE false
+
+		err := suite.ExecuteScript(`
+			local P = blim.PROPERTIES
+			local char = blim.characteristic("180d", "2a37")
+			assert(char:has_property(P.READ), "READ MUST be present")
+			assert(char:has_property(P.NOTIFY), "NOTIFY MUST be present")
+			assert(char:has_property(P.READ + P.NOTIFY), "combined bits MUST all be present")
+			assert(not char:has_property(P.WRITE), "WRITE MUST NOT be present")
+			assert(not char:has_property(P.READ + P.WRITE), "combined bits MUST fail if one is missing")
+		`)
+		suite.NoError(err, "has_property() MUST test property bits")
+	})
+
+	suite.Run("invalid bits", func() {
+		// GOAL: Verify char:has_property() rejects non-numeric arguments
+		//
+		// TEST SCENARIO: has_property("notify") → Lua error raised
+
+		err := suite.ExecuteScript(`blim.characteristic("180d", "2a37"):has_property("notify")`)
+		suite.AssertLuaError(err, "has_property(bits) expects a positive number")
+	})
+}
+
+func (suite *LuaApiTestSuite) TestStructuredErrors() {
+	suite.Run("error table is compatible with string errors", func() {
+		// GOAL: Verify errors are tables with code/message that still behave like strings
+		//
+		// TEST SCENARIO: Read write-only characteristic → err is table with code and message → tostring/concat/string methods use message → no att_code
+
+		err := suite.ExecuteScript(`
+			local _, err = blim.characteristic("1234", "ABCD").read()
+			assert(type(err) == "table", "error MUST be a table, got: " .. type(err))
+			assert(err.code == "unsupported", "code MUST be unsupported, got: " .. tostring(err.code))
+			assert(err.message:find("^read%(%) failed: "), "message MUST keep the operation prefix, got: " .. tostring(err.message))
+			assert(tostring(err) == err.message, "tostring() MUST return the message")
+			assert("E: " .. err == "E: " .. err.message, "concatenation MUST use the message")
+			assert(err:find("does not support read"), "string methods MUST operate on the message")
+			assert(err.att_code == nil, "att_code MUST be absent for non-ATT errors")
+		`)
+		suite.NoError(err, "structured errors MUST stay string-compatible")
+	})
+}
+
+func (suite *LuaApiTestSuite) TestWriteVerifiedFunction() {
+	suite.WithPeripheral().FromJSON(`{
+		"services": [
+			{
+				"uuid": "FFE0",
+				"characteristics": [
+					{ "uuid": "FFE1", "properties": "read,write", "value": [5] },
+					{ "uuid": "FFE2", "properties": "write", "value": [] }
+				]
+			}
+		]
+	}`).Build()
+
+	suite.Run("matching read-back", func() {
+		// GOAL: Verify write_verified() returns true when the read-back matches the written bytes
+		//
+		// TEST SCENARIO: Write the value the device reports → read-back matches → (true, nil)
+
+		err := suite.ExecuteScript(`
+			local ok, err = blim.characteristic("FFE0", "FFE1"):write_verified("\x05", 1000)
+			assert(ok == true, "write_verified() MUST succeed, got: " .. tostring(err))
+			assert(err == nil, "error MUST be nil on match")
+		`)
+		suite.NoError(err, "matching read-back MUST verify")
+	})
+
+	suite.Run("mismatching read-back", func() {
+		// GOAL: Verify write_verified() reports a mismatch with the actual bytes
+		//
+		// TEST SCENARIO: Write a value the device does not store → (false, "mismatch", actual)
+
+		err := suite.ExecuteScript(`
+			local ok, reason, actual = blim.characteristic("FFE0", "FFE1"):write_verified("\x09")
+			assert(ok == false, "write_verified() MUST report mismatch")
+			assert(reason == "mismatch", "reason MUST be mismatch, got: " .. tostring(reason))
+			assert(actual == "\x05", "actual bytes MUST be the read-back value")
+		`)
+		suite.NoError(err, "mismatching read-back MUST be reported")
+	})
+
+	suite.Run("write-only characteristic", func() {
+		// GOAL: Verify write_verified() fails for characteristics that cannot be read back
+		//
+		// TEST SCENARIO: Write-only characteristic → (nil, error) with code "unsupported"
+
+		err := suite.ExecuteScript(`
+			local ok, err = blim.characteristic("FFE0", "FFE2"):write_verified("\x01")
+			assert(ok == nil, "result MUST be nil")
+			assert(err.code == "unsupported", "code MUST be unsupported, got: " .. tostring(err.code))
+			assert(err:find("not readable"), "message MUST explain the problem, got: " .. tostring(err))
+		`)
+		suite.NoError(err, "write-only characteristic MUST be rejected")
+	})
+}
+
+func (suite *LuaApiTestSuite) TestScanFunction() {
+	// GOAL: Verify blim.scan() returns deduplicated advertisers, optionally filtered by service, with optional callbacks
+	//
+	// TEST SCENARIO: Mock scan emits three advertisers → blim.scan() lists them → services filter keeps only matching ones → callbacks stream and complete
+
+	newAd := func(addr, name string, rssi int, services ...string) device.Advertisement {
+		return testutils.NewAdvertisementBuilder().
+			WithAddress(addr).
+			WithName(name).
+			WithRSSI(rssi).
+			WithConnectable(true).
+			WithManufacturerData(nil).
+			WithServices(services...).
+			WithNoServiceData().
+			WithTxPower(0).
+			Build()
+	}
+	suite.WithPeripheral().
+		WithScanAdvertisements().
+		WithAdvertisements(
+			newAd("AA:BB:CC:DD:EE:01", "HRM", -40, "180D"),
+			newAd("AA:BB:CC:DD:EE:02", "Beacon", -70, "FEAA"),
+			newAd("AA:BB:CC:DD:EE:03", "Battery", -60, "180F", "180D"),
+		).
+		Build()
+
+	suite.Run("all advertisers", func() {
+		err := suite.ExecuteScript(`
+			local devices = blim.scan{duration_ms = 200}
+			assert(#devices == 3, "scan MUST return all advertisers, got: " .. #devices)
+			assert(devices[1].address == "AA:BB:CC:DD:EE:01", "devices MUST be in discovery order")
+			assert(devices[1].name == "HRM", "name MUST be exposed")
+			assert(devices[1].rssi == -40, "rssi MUST be exposed")
+			assert(devices[1].connectable == true, "connectable MUST be exposed")
+			assert(devices[1].services[1] == "180d", "services MUST be normalized UUIDs")
+		`)
+		suite.NoError(err, "blim.scan() MUST list advertisers")
+	})
+
+	suite.Run("services filter", func() {
+		err := suite.ExecuteScript(`
+			local devices = blim.scan{duration_ms = 200, services = {"Heart Rate"}}
+			assert(#devices == 2, "only Heart Rate advertisers MUST be returned, got: " .. #devices)
+			assert(devices[1].name == "HRM" and devices[2].name == "Battery", "filtered devices MUST match")
+		`)
+		suite.NoError(err, "blim.scan() MUST filter by service")
+	})
+
+	suite.Run("streaming and completion callbacks", func() {
+		err := suite.ExecuteScript(`
+			local streamed = {}
+			local completed
+			local devices = blim.scan{
+				duration_ms = 200,
+				on_device = function(dev) table.insert(streamed, dev.address) end,
+				on_complete = function(list) completed = list end,
+			}
+			assert(#streamed == 3, "on_device MUST fire once per discovered device, got: " .. #streamed)
+			assert(streamed[1] == "AA:BB:CC:DD:EE:01", "on_device MUST fire in discovery order")
+			assert(completed ~= nil and #completed == 3, "on_complete MUST receive the deduplicated list")
+			assert(#devices == 3, "scan MUST still return the array")
+		`)
+		suite.NoError(err, "blim.scan() callbacks MUST fire")
+	})
+
+	suite.Run("failing callbacks", func() {
+		err := suite.ExecuteScript(`
+			local devices = blim.scan{
+				duration_ms = 200,
+				on_device = function() error("boom") end,
+				on_complete = function() error("boom") end,
+			}
+			assert(#devices == 3, "callback errors MUST NOT abort the scan")
+		`)
+		suite.NoError(err, "callback errors MUST be contained")
+	})
+
+	suite.Run("invalid options", func() {
+		err := suite.ExecuteScript(`blim.scan{services = "180d"}`)
+		suite.AssertLuaError(err, "scan([options]) expects an array of service names or UUIDs as services")
+
+		err = suite.ExecuteScript(`blim.scan{on_device = true}`)
+		suite.AssertLuaError(err, "scan([options]) expects a function as on_device")
+	})
+}
+
+func (suite *LuaApiTestSuite) TestCurrentTimeParser() {
+	// GOAL: Verify char:parse() returns a structured table for the Current Time characteristic (0x2A2B)
+	//
+	// TEST SCENARIO: Read Current Time → parse() → datetime, day_of_week, fractions256, adjust_reason, and rfc3339 fields are set
+
+	suite.WithPeripheral().
+		WithService("1805").
+		WithCharacteristic("2a2b", "read,notify", []byte{0xEA, 0x07, 0x0A, 0x10, 0x0C, 0x1E, 0x2D, 0x05, 0x80, 0x09})
+
+	err := suite.ExecuteScript(`
+		local char = blim.characteristic("1805", "2a2b")
+		assert(char.has_parser, "Current Time MUST have a parser")
+
+		local value, err = char.read()
+		assert(err == nil, "read MUST succeed: " .. tostring(err))
+
+		local t = char:parse(value)
+		assert(type(t) == "table", "parse() MUST return a table, got: " .. type(t))
+		assert(t.datetime.year == 2026 and t.datetime.month == 10 and t.datetime.day == 16, "date MUST match")
+		assert(t.datetime.hours == 12 and t.datetime.minutes == 30 and t.datetime.seconds == 45, "time MUST match")
+		assert(t.day_of_week == 5, "day_of_week MUST be 5 (Friday)")
+		assert(t.fractions256 == 128, "fractions256 MUST be 128")
+		assert(t.adjust_reason.manual == true, "manual flag MUST be set")
+		assert(t.adjust_reason.external == false, "external flag MUST NOT be set")
+		assert(t.adjust_reason.timezone == false, "timezone flag MUST NOT be set")
+		assert(t.adjust_reason.dst == true, "dst flag MUST be set")
+		assert(t.rfc3339 == "2026-10-16T12:30:45.5Z", "rfc3339 MUST match, got: " .. tostring(t.rfc3339))
+
+		assert(char:parse("\x01\x02\x03") == nil, "parse() MUST return nil for other lengths")
+	`)
+	suite.NoError(err, "Current Time parsing MUST succeed")
+}
+
+func (suite *LuaApiTestSuite) TestLogLevelFunctions() {
+	// GOAL: Verify blim.set_log_level()/get_log_level() change the shared logger level and validate input
+	//
+	// TEST SCENARIO: Set "debug" → previous level returned, logger at debug → restore → invalid level raises error
+
+	originalLevel := suite.Logger.GetLevel()
+	suite.Logger.SetLevel(logrus.WarnLevel)
+	defer suite.Logger.SetLevel(originalLevel)
+
+	suite.Run("set and restore", func() {
+		err := suite.ExecuteScript(`
+			assert(blim.get_log_level() == "warn", "initial level MUST be warn, got: " .. blim.get_log_level())
+			local previous = blim.set_log_level("debug")
+			assert(previous == "warn", "set_log_level() MUST return the previous level, got: " .. tostring(previous))
+			assert(blim.get_log_level() == "debug", "level MUST be debug")
+		`)
+		suite.Require().NoError(err)
+		suite.Equal(logrus.DebugLevel, suite.Logger.GetLevel(), "shared logger MUST be at debug level")
+
+		err = suite.ExecuteScript(`assert(blim.set_log_level("ERROR") == "debug")`)
+		suite.Require().NoError(err)
+		suite.Equal(logrus.ErrorLevel, suite.Logger.GetLevel(), "level names MUST be case-insensitive")
+	})
+
+	suite.Run("invalid level", func() {
+		err := suite.ExecuteScript(`blim.set_log_level("verbose")`)
+		suite.AssertLuaError(err, "set_log_level(level) expects one of")
+		err = suite.ExecuteScript(`blim.set_log_level()`)
+		suite.AssertLuaError(err, "set_log_level(level) expects one of")
+		suite.Equal(logrus.ErrorLevel, suite.Logger.GetLevel(), "invalid levels MUST NOT change the logger level")
+	})
+}
+
+func (suite *LuaApiTestSuite) TestEstimateDistanceFunction() {
+	// GOAL: Verify blim.estimate_distance() applies the log-distance path-loss model with an optional exponent
+	//
+	// TEST SCENARIO: 20 dB loss → 10 m with default n=2 → 100 m with n=1 → non-numeric or non-positive arguments raise errors
+
+	err := suite.ExecuteScript(`
+		local d = blim.estimate_distance(-79, -59)
+		assert(math.abs(d - 10) < 1e-9, "default n MUST be 2, got: " .. tostring(d))
+		d = blim.estimate_distance(-79, -59, 1)
+		assert(math.abs(d - 100) < 1e-9, "n=1 MUST estimate 100 m, got: " .. tostring(d))
+		assert(math.abs(blim.estimate_distance(-59, -59) - 1) < 1e-9, "RSSI equal to TX power MUST estimate 1 m")
+	`)
+	suite.NoError(err)
+
+	err = suite.ExecuteScript(`blim.estimate_distance(-70)`)
+	suite.AssertLuaError(err, "expects numeric rssi and tx_power")
+	err = suite.ExecuteScript(`blim.estimate_distance(-70, -59, 0)`)
+	suite.AssertLuaError(err, "invalid path-loss exponent")
+}
+
+func (suite *LuaApiTestSuite) TestSimulateNotificationFunction() {
+	// GOAL: Verify blim.simulate_notification() is a guarded no-op by default and, once enabled, reaches subscription callbacks
+	//
+	// TEST SCENARIO: Disabled → returns false, callback not called → enabled → returns true, callback receives bytes → unknown characteristic → structured error
+
+	err := suite.ExecuteScript(`
+		received = {}
+		blim.subscribe{
+			services = {{service = "180d", chars = {"2a37"}}},
+			Callback = function(record)
+				table.insert(received, string.byte(record.Values["2a37"], 2))
+			end
+		}
+		assert(blim.simulate_notification("180d", "2a37", "\x00\x48") == false, "disabled simulation MUST return false")
+	`)
+	suite.Require().NoError(err)
+	time.Sleep(100 * time.Millisecond)
+
+	err = suite.ExecuteScript(`assert(#received == 0, "disabled simulation MUST NOT deliver notifications, got: " .. #received)`)
+	suite.Require().NoError(err)
+
+	suite.LuaApi.SetSimulatedNotifications(true)
+	err = suite.ExecuteScript(`
+		local ok, err = blim.simulate_notification("180d", "2a37", "\x00\x48")
+		assert(ok == true and err == nil, "enabled simulation MUST succeed: " .. tostring(err))
+	`)
+	suite.Require().NoError(err)
+	time.Sleep(100 * time.Millisecond)
+
+	err = suite.ExecuteScript(`
+		assert(#received == 1 and received[1] == 0x48, "callback MUST receive the simulated value, got: " .. #received)
+
+		local ok, err = blim.simulate_notification("180d", "9999", "\x01")
+		assert(ok == nil and err ~= nil, "unknown characteristic MUST fail")
+		assert(err.code == "not_found", "error code MUST be not_found, got: " .. tostring(err.code))
+	`)
+	suite.NoError(err)
+}
+
+func (suite *LuaApiTestSuite) TestSubscriptionStatsFunction() {
+	// GOAL: Verify blim.subscription_stats() reports delivery counters and per-characteristic notification intervals
+	//
+	// TEST SCENARIO: No notifications → no intervals → three simulated notifications 20ms apart → two intervals of at least 20ms reported
+
+	suite.LuaApi.SetSimulatedNotifications(true)
+	err := suite.ExecuteScript(`
+		blim.subscribe{
+			services = {{service = "180d", chars = {"2a37"}}},
+			Callback = function(record) end
+		}
+		assert(blim.subscription_stats().intervals["2a37"] == nil, "intervals MUST NOT be reported before two notifications")
+
+		for i = 1, 3 do
+			assert(blim.simulate_notification("180d", "2a37", "\x00\x48"))
+			blim.sleep(20)
+		end
+	`)
+	suite.Require().NoError(err)
+	time.Sleep(100 * time.Millisecond)
+
+	err = suite.ExecuteScript(`
+		local stats = blim.subscription_stats()
+		assert(type(stats.delivered) == "number", "delivered MUST be reported")
+		local hr = stats.intervals["2a37"]
+		assert(hr ~= nil, "intervals MUST be reported for 2a37")
+		assert(hr.count == 2, "three notifications MUST yield two intervals, got: " .. tostring(hr.count))
+		assert(hr.min_ms >= 20 and hr.min_ms <= hr.mean_ms and hr.mean_ms <= hr.max_ms,
+			string.format("intervals MUST be ordered and at least 20ms, got min=%.1f mean=%.1f max=%.1f", hr.min_ms, hr.mean_ms, hr.max_ms))
+		assert(hr.stddev_ms >= 0, "stddev MUST be non-negative")
+	`)
+	suite.NoError(err)
+}
+
+func (suite *LuaApiTestSuite) TestNotificationsEnabled() {
+	// GOAL: Verify char:notifications_enabled() reports the CCCD state and fails for characteristics without a CCCD
+	//
+	// TEST SCENARIO: Add characteristics with enabled, disabled and missing CCCDs → query each → true, false, not_found error
+
+	suite.WithPeripheral().
+		WithService("AAAA").
+		WithCharacteristic("BBBB", "read,notify", []byte{}).
+		WithDescriptor("2902", []byte{0x01, 0x00}).
+		WithCharacteristic("CCCC", "read,indicate", []byte{}).
+		WithDescriptor("2902", []byte{0x00, 0x00}).
+		WithCharacteristic("DDDD", "read", []byte{})
+
+	suite.Run("CCCD state", func() {
+		err := suite.ExecuteScript(`
+			local enabled, err = blim.characteristic("AAAA", "BBBB"):notifications_enabled()
+			assert(enabled == true and err == nil, "notify bit set MUST report enabled: " .. tostring(err))
+
+			enabled, err = blim.characteristic("AAAA", "CCCC"):notifications_enabled()
+			assert(enabled == false and err == nil, "cleared CCCD MUST report disabled: " .. tostring(err))
+
+			enabled, err = blim.characteristic("AAAA", "DDDD"):notifications_enabled()
+			assert(enabled == nil and err ~= nil, "missing CCCD MUST fail")
+			assert(err.code == "not_found", "error code MUST be not_found, got: " .. tostring(err.code))
+		`)
+		suite.NoError(err)
+	})
+}
+
+func (suite *LuaApiTestSuite) TestListPropertyFilter() {
+	// GOAL: Verify blim.list{properties=...} keeps only characteristics having all named properties and prunes empty services
+	//
+	// TEST SCENARIO: Add write-only and write+notify characteristics → filter by notify, write, both, indicate → invalid filters raise errors
+
+	suite.WithPeripheral().
+		WithService("AAAA").
+		WithCharacteristic("BBBB", "write", []byte{}).
+		WithCharacteristic("CCCC", "write,notify", []byte{})
+
+	suite.Run("filter by properties", func() {
+		err := suite.ExecuteScript(`
+			local function count(t)
+				local n = 0
+				for _ in ipairs(t) do n = n + 1 end
+				return n
+			end
+
+			local all = blim.list()
+			assert(#all.aaaa.characteristics == 2, "unfiltered list MUST include all characteristics")
 
-		script := `
-			local char = blim.characteristic("1234", "ABCD")
-			local result, err = char.write("test data", false)
-			assert(result == true, "write without response should return true on success")
-			assert(err == nil, "write should not return error")
-		`
-		err := suite.ExecuteScript(script)
-		suite.NoError(err, "Should successfully write without response")
-	})
+			local notifiable = blim.list{properties = {"notify"}}
+			assert(notifiable["180d"] ~= nil, "Heart Rate service MUST be kept")
+			assert(#notifiable.aaaa.characteristics == 1 and notifiable.aaaa.characteristics[1] == "cccc",
+				"only the notifiable characteristic MUST be kept")
 
-	suite.Run("Write with explicit with_response=true parameter", func() {
-		// GOAL: Verify write() accepts explicit with_response=true parameter
-		//
-		// TEST SCENARIO: Write with with_response=true explicitly → success → verify (true, nil) returned
+			local writable = blim.list{properties = {"write"}}
+			assert(count(writable) == 1 and writable[1] == "aaaa", "services without writable characteristics MUST be pruned")
+			assert(#writable.aaaa.characteristics == 2, "both writable characteristics MUST be kept")
 
-		script := `
-			local char = blim.characteristic("1234", "ABCD")
-			local result, err = char.write("test", true)
-			assert(result == true, "write with explicit with_response=true should succeed")
-			assert(err == nil, "write should not return error")
-		`
-		err := suite.ExecuteScript(script)
-		suite.NoError(err, "Should successfully write with explicit with_response=true")
+			local both = blim.list{properties = {"WRITE", "notify"}}
+			assert(count(both) == 1 and #both.aaaa.characteristics == 1, "all named properties MUST match")
+
+			assert(next(blim.list{properties = {"indicate"}}) == nil, "no service MUST match indicate")
+			assert(count(blim.list{}) == count(all), "empty filter MUST list everything")
+		`)
+		suite.NoError(err, "Property filtering MUST succeed")
 	})
 
-	suite.Run("Write returns nil and error on failure", func() {
-		// GOAL: Verify write() returns nil and error when writing to a read-only characteristic
-		//
-		// TEST SCENARIO: Write to read-only characteristic (5678) → returns (nil, error)
+	suite.Run("invalid filter", func() {
+		err := suite.ExecuteScript(`blim.list{properties = {"notifiable"}}`)
+		suite.AssertLuaError(err, "list([filter]) unknown property \"notifiable\"")
+		err = suite.ExecuteScript(`blim.list{properties = "notify"}`)
+		suite.AssertLuaError(err, "list([filter]) expects an array of property names as properties")
+		err = suite.ExecuteScript(`blim.list("notify")`)
+		suite.AssertLuaError(err, "list([filter]) expects a table as filter")
+	})
+}
 
-		script := `
-			local char = blim.characteristic("1234", "5678")
-			local result, err = char.write("data")
+func (suite *LuaApiTestSuite) TestLocationAndSpeedParser() {
+	// GOAL: Verify char:parse() returns scaled, flag-gated fields for the Location and Speed characteristic (0x2A67)
+	//
+	// TEST SCENARIO: Read speed + location + heading value → parse() → present fields scaled to m/s and degrees → absent fields nil → truncated value fails
+
+	suite.WithPeripheral().
+		WithService("1819").
+		WithCharacteristic("2a67", "read,notify", []byte{
+			0x95, 0x00, // Flags: speed, location, heading, position ok
+			0xD2, 0x04, // 12.34 m/s
+			0xCF, 0x24, 0x4E, 0x16, 0x81, 0x73, 0x3B, 0xB7, // 37.4219983, -122.0840575
+			0x78, 0x69, // 270.00°
+		})
 
-			-- MUST fail because the characteristic doesn't support write
-			assert(result == nil, "result MUST be nil when error occurs")
-			assert(err == "write() failed: characteristic 5678 does not support write operations", "error message MUST be exact, got: " .. tostring(err))
-		`
-		err := suite.ExecuteScript(script)
-		suite.NoError(err, "Should properly error on non-writable characteristic")
-	})
+	err := suite.ExecuteScript(`
+		local char = blim.characteristic("1819", "2a67")
+		assert(char.has_parser, "Location and Speed MUST have a parser")
+
+		local value, err = char.read()
+		assert(err == nil, "read MUST succeed: " .. tostring(err))
+
+		local t = char:parse(value)
+		assert(type(t) == "table", "parse() MUST return a table, got: " .. type(t))
+		assert(math.abs(t.speed - 12.34) < 1e-9, "speed MUST be in m/s, got: " .. tostring(t.speed))
+		assert(math.abs(t.latitude - 37.4219983) < 1e-9, "latitude MUST be in degrees, got: " .. tostring(t.latitude))
+		assert(math.abs(t.longitude + 122.0840575) < 1e-9, "longitude MUST be in degrees, got: " .. tostring(t.longitude))
+		assert(math.abs(t.heading - 270) < 1e-9, "heading MUST be in degrees, got: " .. tostring(t.heading))
+		assert(t.total_distance == nil and t.elevation == nil and t.rolling_time == nil and t.utc_time == nil,
+			"absent fields MUST be nil")
+		assert(t.position_status == "ok", "position_status MUST be ok, got: " .. tostring(t.position_status))
+		assert(t.heading_source == "movement", "heading_source MUST be movement")
+
+		local bad, perr = char:parse("\x95\x00\xD2")
+		assert(bad == nil and perr ~= nil, "truncated value MUST fail to parse")
+	`)
+	suite.NoError(err, "Location and Speed parsing MUST succeed")
+}
 
-	suite.Run("Write binary data", func() {
-		// GOAL: Verify write() correctly sends binary data including null bytes
-		//
-		// TEST SCENARIO: Write binary string with \x00 bytes → success → verify (true, nil) returned
+func (suite *LuaApiTestSuite) TestWeightMeasurementParser() {
+	// GOAL: Verify char:parse() returns the weight in kg and the present optional fields for Weight Measurement (0x2A9D)
+	//
+	// TEST SCENARIO: Read imperial value with user ID → parse() → weight converted to kg, units "imperial", user_id set → absent fields nil → truncated value fails
+
+	suite.WithPeripheral().
+		WithService("181d").
+		WithCharacteristic("2a9d", "indicate,read", []byte{
+			0x05,       // Flags: imperial, user ID
+			0x48, 0x3C, // 154.32 lb
+			0x02, // User 2
+		})
 
-		script := `
-			local char = blim.characteristic("1234", "ABCD")
-			local binary_data = "\x01\x02\x00\xFF\x03"
-			local result, err = char.write(binary_data)
-			assert(result == true, "write should succeed with binary data")
-			assert(err == nil, "write should not return error")
-		`
-		err := suite.ExecuteScript(script)
-		suite.NoError(err, "Should handle binary data correctly")
-	})
+	err := suite.ExecuteScript(`
+		local char = blim.characteristic("181d", "2a9d")
+		assert(char.has_parser, "Weight Measurement MUST have a parser")
 
-	suite.Run("Write empty string", func() {
-		// GOAL: Verify write() accepts empty string as valid data
-		//
-		// TEST SCENARIO: Write empty string "" → success → verify (true, nil) returned
+		local value, err = char.read()
+		assert(err == nil, "read MUST succeed: " .. tostring(err))
 
-		script := `
-			local char = blim.characteristic("1234", "ABCD")
-			local result, err = char.write("")
-			assert(result == true, "write should succeed with empty string")
-			assert(err == nil, "write should not return error")
-		`
-		err := suite.ExecuteScript(script)
-		suite.NoError(err, "Should handle empty string write")
-	})
+		local t = char:parse(value)
+		assert(type(t) == "table", "parse() MUST return a table, got: " .. type(t))
+		assert(math.abs(t.weight - 154.32 * 0.45359237) < 1e-9, "weight MUST be in kg, got: " .. tostring(t.weight))
+		assert(t.units == "imperial", "units MUST reflect the flags, got: " .. tostring(t.units))
+		assert(t.user_id == 2, "user_id MUST be present, got: " .. tostring(t.user_id))
+		assert(t.timestamp == nil and t.bmi == nil and t.height == nil, "absent fields MUST be nil")
 
-	suite.Run("Write multiple times to same characteristic", func() {
-		// GOAL: Verify write() is idempotent and can be called multiple times
-		//
-		// TEST SCENARIO: Write to same characteristic 3 times → all return success → verify consistent behavior
+		local bad, perr = char:parse("\x05\x48\x3C")
+		assert(bad == nil and perr ~= nil, "truncated value MUST fail to parse")
+	`)
+	suite.NoError(err, "Weight Measurement parsing MUST succeed")
+}
 
-		script := `
-			local char = blim.characteristic("1234", "ABCD")
+func (suite *LuaApiTestSuite) TestParserOverride() {
+	// GOAL: Verify blim.set_parser_override() applies to one service+characteristic pair only and takes precedence over built-in parsers
+	//
+	// TEST SCENARIO: Override 2a37 in a custom service → parse() uses the override → 2a37 in 180d keeps the built-in parser → failing override returns nil, err → nil removes it → bad arguments raise
+
+	suite.WithPeripheral().
+		WithService("180d").
+		WithCharacteristic("2a37", "notify,read", []byte{0x00, 0x48}).
+		WithService("fff0").
+		WithCharacteristic("2a37", "notify,read", []byte{0x00, 0x48}).
+		WithCharacteristic("fff1", "read", []byte{0x07})
+
+	err := suite.ExecuteScript(`
+		blim.set_parser_override("FFF0", "2A37", function(bytes)
+			return {custom = string.byte(bytes, 2)}
+		end)
+		blim.set_parser_override("fff0", "fff1", function(bytes) error("bad frame") end)
+
+		local custom = blim.characteristic("fff0", "2a37")
+		local t = custom:parse("\x00\x48")
+		assert(type(t) == "table" and t.custom == 0x48, "override MUST take precedence over the built-in parser")
+
+		local standard = blim.characteristic("180d", "2a37")
+		local hr = standard:parse("\x00\x48")
+		assert(type(hr) == "table" and hr.custom == nil, "override MUST NOT affect the same UUID in another service")
+
+		local plain = blim.characteristic("fff0", "fff1")
+		assert(plain.has_parser, "override MUST make has_parser true for an unknown characteristic")
+		local v, perr = plain:parse("\x07")
+		assert(v == nil and perr ~= nil, "override error MUST be returned as nil, err")
+
+		blim.set_parser_override("fff0", "2a37", nil)
+		local restored = custom:parse("\x00\x48")
+		assert(type(restored) == "table" and restored.custom == nil, "removing the override MUST restore the built-in parser")
+	`)
+	suite.NoError(err, "parser overrides MUST apply per service+characteristic")
+
+	err = suite.ExecuteScript(`blim.set_parser_override("fff0", "2a37", 42)`)
+	suite.AssertLuaError(err, "expects two string arguments")
+}
 
-			local result1, err1 = char.write("first")
-			local result2, err2 = char.write("second")
-			local result3, err3 = char.write("third")
+func (suite *LuaApiTestSuite) TestTemperatureParser() {
+	// GOAL: Verify char:parse() returns degrees Celsius for the Temperature characteristic (0x2A6E) and the unit is exposed
+	//
+	// TEST SCENARIO: Read 0x0929 → parse() → 23.45 → unit mentions Celsius → 1-byte value parses to nil
 
-			assert(result1 == true, "first write should succeed")
-			assert(result2 == true, "second write should succeed")
-			assert(result3 == true, "third write should succeed")
+	suite.WithPeripheral().
+		WithService("181a").
+		WithCharacteristic("2a6e", "read,notify", []byte{0x29, 0x09})
 
-			assert(err1 == nil, "first write should not error")
-			assert(err2 == nil, "second write should not error")
-			assert(err3 == nil, "third write should not error")
-		`
-		err := suite.ExecuteScript(script)
-		suite.NoError(err, "Should allow multiple writes")
-	})
+	err := suite.ExecuteScript(`
+		local char = blim.characteristic("181a", "2a6e")
+		assert(char.has_parser, "Temperature MUST have a parser")
+		assert(char.unit and char.unit:lower():find("celsius"), "unit MUST be degree Celsius, got: " .. tostring(char.unit))
 
-	suite.Run("Write with implicit number-to-string conversion", func() {
-		// GOAL: Verify write() handles Lua's implicit number-to-string conversion (consistent with characteristic())
-		//       (Note: Lua IsString() returns true for numbers due to implicit conversion, so 123 becomes "123")
-		//
-		// TEST SCENARIO: Call write(123) with number → number converted to string "123" → write succeeds with converted data
+		local value, err = char.read()
+		assert(err == nil, "read MUST succeed: " .. tostring(err))
 
-		script := `
-			local char = blim.characteristic("1234", "ABCD")
-			local result, err = char.write(123)
-			-- Number 123 gets converted to string "123" by Lua
-			assert(result == true, "write should succeed with implicitly converted number")
-			assert(err == nil, "write should not return error")
-		`
-		err := suite.ExecuteScript(script)
-		suite.NoError(err, "Should handle implicit number-to-string conversion")
-	})
+		local celsius = char:parse(value)
+		assert(type(celsius) == "number", "parse() MUST return a number, got: " .. type(celsius))
+		assert(math.abs(celsius - 23.45) < 1e-9, "temperature MUST be 23.45, got: " .. tostring(celsius))
 
-	suite.Run("Error: write() with invalid with_response parameter", func() {
-		// GOAL: Verify write() raises error when with_response parameter is not boolean
-		//
-		// TEST SCENARIO: Call write("data", "not a boolean") → Lua error raised → verify error message
+		assert(char:parse("\x29") == nil, "1-byte value MUST parse to nil")
+	`)
+	suite.NoError(err, "Temperature parsing MUST succeed")
+}
 
-		script := `
-			local char = blim.characteristic("1234", "ABCD")
-			local result, err = char.write("data", "not boolean")
-		`
-		err := suite.ExecuteScript(script)
-		suite.AssertLuaError(err, "expects boolean as second argument")
-	})
+func (suite *LuaApiTestSuite) TestHumidityParser() {
+	// GOAL: Verify temperature and humidity of an Environmental Sensing service decode via char:parse() without scaling math
+	//
+	// TEST SCENARIO: Read 2A6E and 2A6F → parse() → 23.45 °C and 45.5 % → humidity above 100 % parses to nil
+
+	suite.WithPeripheral().
+		WithService("181a").
+		WithCharacteristic("2a6e", "read,notify", []byte{0x29, 0x09}).
+		WithCharacteristic("2a6f", "read,notify", []byte{0xC6, 0x11})
+
+	err := suite.ExecuteScript(`
+		local temp = blim.characteristic("181a", "2a6e")
+		local hum = blim.characteristic("181a", "2a6f")
+		assert(hum.has_parser, "Humidity MUST have a parser")
+		assert(hum.unit ~= nil, "Humidity MUST have a unit")
+
+		local t = temp:parse(temp.read())
+		local h = hum:parse(hum.read())
+		assert(math.abs(t - 23.45) < 1e-9, "temperature MUST be 23.45, got: " .. tostring(t))
+		assert(math.abs(h - 45.5) < 1e-9, "humidity MUST be 45.5, got: " .. tostring(h))
+
+		assert(hum:parse("\x11\x27") == nil, "humidity above 100% MUST parse to nil")
+	`)
+	suite.NoError(err, "Environmental Sensing parsing MUST succeed")
+}
 
-	suite.Run("Error: write() on non-connected device", func() {
-		// GOAL: Verify write() returns error when called on disconnected device
-		//
-		// TEST SCENARIO: Disconnect device → attempt write → returns (nil, error)
+func (suite *LuaApiTestSuite) TestAlertLevelParser() {
+	// GOAL: Verify char:parse() returns value and label for the Alert Level characteristic (0x2A06)
+	//
+	// TEST SCENARIO: Read High Alert → parse() → {value=2, name="High"} → reserved level parses to nil
 
-		// Disconnect the device first
-		disconnectErr := suite.LuaApi.GetDevice().Disconnect()
-		suite.NoError(disconnectErr, "Should disconnect successfully")
+	suite.WithPeripheral().
+		WithService("1802").
+		WithCharacteristic("2a06", "read,write-without-response", []byte{0x02})
 
-		script := `
-			local char = blim.characteristic("1234", "ABCD")
-			local result, err = char.write("data")
+	err := suite.ExecuteScript(`
+		local char = blim.characteristic("1802", "2a06")
+		assert(char.has_parser, "Alert Level MUST have a parser")
 
-			-- MUST fail because device is not connected
-			assert(result == nil, "result MUST be nil when error occurs")
-			assert(err == "write() failed: write characteristic abcd", "error message MUST be exact, got: " .. tostring(err))
-		`
-		err := suite.ExecuteScript(script)
-		suite.NoError(err, "Should properly error on disconnected device")
-	})
+		local value, err = char.read()
+		assert(err == nil, "read MUST succeed: " .. tostring(err))
 
-	suite.Run("Write to characteristic supporting both write modes", func() {
-		// GOAL: Verify write() can use both with_response and without_response modes on the same characteristic
-		//
-		// TEST SCENARIO: Write twice to ABCD characteristic with both modes → both succeed → verify returns
+		local t = char:parse(value)
+		assert(type(t) == "table", "parse() MUST return a table, got: " .. type(t))
+		assert(t.value == 2, "value MUST be 2, got: " .. tostring(t.value))
+		assert(t.name == "High", "name MUST be High, got: " .. tostring(t.name))
 
-		script := `
-			local char = blim.characteristic("1234", "ABCD")
+		assert(char:parse("\x07") == nil, "reserved level MUST parse to nil")
+	`)
+	suite.NoError(err, "Alert Level parsing MUST succeed")
+}
 
-			-- Verify characteristic supports both modes
-			if not char.properties.write then
-				error("Test setup error: characteristic should support write with response")
-			end
-			if not char.properties.write_without_response then
-				error("Test setup error: characteristic should support write without response")
-			end
+func (suite *LuaApiTestSuite) TestBodySensorLocationParser() {
+	// GOAL: Verify char:parse() resolves the Body Sensor Location characteristic (0x2A38) to its label
+	//
+	// TEST SCENARIO: Read Wrist → parse() → {value=2, name="Wrist"} → reserved value parses to nil
 
-			-- Write with response (default)
-			local result1, err1 = char.write("with response")
-			assert(result1 == true, "write with response should succeed")
-			assert(err1 == nil, "write with response should not error")
+	suite.WithPeripheral().
+		WithService("180d").
+		WithCharacteristic("2a38", "read", []byte{0x02})
 
-			-- Write without response
-			local result2, err2 = char.write("without response", false)
-			assert(result2 == true, "write without response should succeed")
-			assert(err2 == nil, "write without response should not error")
-		`
-		err := suite.ExecuteScript(script)
-		suite.NoError(err, "Should support both write modes on same characteristic")
-	})
+	err := suite.ExecuteScript(`
+		local char = blim.characteristic("180d", "2a38")
+		assert(char.has_parser, "Body Sensor Location MUST have a parser")
 
-	suite.Run("Write large data payload", func() {
-		// GOAL: Verify write() handles large data payloads (MTU chunking is transparent to Lua API)
-		//
-		// TEST SCENARIO: Write 512 bytes of data → success → verify (true, nil) returned
+		local value, err = char.read()
+		assert(err == nil, "read MUST succeed: " .. tostring(err))
 
-		script := `
-			local char = blim.characteristic("1234", "ABCD")
+		local t = char:parse(value)
+		assert(type(t) == "table", "parse() MUST return a table, got: " .. type(t))
+		assert(t.value == 2, "value MUST be 2, got: " .. tostring(t.value))
+		assert(t.name == "Wrist", "name MUST be Wrist, got: " .. tostring(t.name))
 
-			-- Generate 512 bytes of data
-			local large_data = string.rep("A", 512)
+		assert(char:parse("\x10") == nil, "reserved value MUST parse to nil")
+	`)
+	suite.NoError(err, "Body Sensor Location parsing MUST succeed")
+}
 
-			local result, err = char.write(large_data)
-			assert(result == true, "write should succeed with large payload")
-			assert(err == nil, "write should not return error")
-		`
-		err := suite.ExecuteScript(script)
-		suite.NoError(err, "Should handle large data payloads")
-	})
+func (suite *LuaApiTestSuite) TestAlertCategoryParser() {
+	// GOAL: Verify char:parse() decodes the Supported New Alert Category bitmask (0x2A47) into an array of names
+	//
+	// TEST SCENARIO: Read Email + Call + Instant Message (2-byte form) → parse() → names in bit order → zero parses to empty array
 
-	suite.Run("Verify write() is a method not a field", func() {
-		// GOAL: Verify write() is exposed as a callable method (userdata type in aarzilli/golua, not a field)
-		//
-		// TEST SCENARIO: Get a characteristic handle → check write type is userdata/function → verify callable
+	suite.WithPeripheral().
+		WithService("1811").
+		WithCharacteristic("2a47", "read", []byte{0x0A, 0x02})
 
-		script := `
-			local char = blim.characteristic("1234", "ABCD")
+	err := suite.ExecuteScript(`
+		local char = blim.characteristic("1811", "2a47")
+		assert(char.has_parser, "Supported New Alert Category MUST have a parser")
 
-			-- write should be a callable (in aarzilli/golua, Go functions are userdata type, not "function")
-			assert(char.write ~= nil, "write should not be nil")
-			assert(type(char.write) == "function" or type(char.write) == "userdata",
-			       "write should be callable (function or userdata), got: " .. type(char.write))
+		local value, err = char.read()
+		assert(err == nil, "read MUST succeed: " .. tostring(err))
 
-			-- Calling it should work
-			local result, err = char.write("test")
-			assert(result ~= nil or err ~= nil, "should return either result or error")
-		`
-		err := suite.ExecuteScript(script)
-		suite.NoError(err, "write should be a callable method")
-	})
+		local names = char:parse(value)
+		assert(type(names) == "table", "parse() MUST return a table, got: " .. type(names))
+		assert(table.concat(names, ",") == "Email,Call,Instant Message",
+			"categories MUST be listed in bit order, got: " .. table.concat(names, ","))
 
-	suite.Run("Write with nil with_response parameter defaults to true", func() {
-		// GOAL: Verify write() treats nil with_response parameter as default (true)
-		//
-		// TEST SCENARIO: Call write("data", nil) → defaults to with_response=true → success
+		local none = char:parse("\x00")
+		assert(type(none) == "table" and #none == 0, "zero MUST parse to an empty array")
+	`)
+	suite.NoError(err, "alert category parsing MUST succeed")
+}
 
-		script := `
-			local char = blim.characteristic("1234", "ABCD")
-			local result, err = char.write("data", nil)
-			assert(result == true, "write with nil with_response should succeed (default to true)")
-			assert(err == nil, "write should not return error")
-		`
-		err := suite.ExecuteScript(script)
-		suite.NoError(err, "Should treat nil with_response as default true")
-	})
+func (suite *LuaApiTestSuite) TestRegisteredEnumParser() {
+	// GOAL: Verify an enum parser registered with device.RegisterEnumParser surfaces in Lua
+	//
+	// TEST SCENARIO: Register enum for a custom characteristic → has_parser is true → parse() returns {value, name}
+
+	device.RegisterEnumParser("ffe1", map[byte]string{0: "Idle", 1: "Running"})
+
+	suite.WithPeripheral().
+		WithService("ffe0").
+		WithCharacteristic("ffe1", "read", []byte{0x01})
+
+	err := suite.ExecuteScript(`
+		local char = blim.characteristic("ffe0", "ffe1")
+		assert(char.has_parser == true, "registered enum MUST set has_parser, got: " .. tostring(char.has_parser))
+
+		local value = char.read()
+		local t = char:parse(value)
+		assert(t.value == 1 and t.name == "Running", "parse() MUST return {value=1, name=\"Running\"}")
+	`)
+	suite.NoError(err, "registered enum parser MUST be usable from Lua")
 }
 
-// TestLuaBridgeAccess tests blim.bridge exposure to Lua
 func (suite *LuaApiTestSuite) TestLuaBridgeAccess() {
 	suite.Run("Bridge not set - raises error on getter function calls", func() {
 		// GOAL: Verify blim.bridge exists, but raises an error when calling getter functions in non-bridge mode
@@ -2246,6 +4364,50 @@ func (suite *LuaApiTestSuite) TestSleepReleasesLuaStateMutex() {
 	suite.NoError(err, "Sleep should release mutex allowing callback execution")
 }
 
+// TestYieldReleasesLuaStateMutex verifies that blim.yield() lets subscription callbacks run inside a busy loop.
+func (suite *LuaApiTestSuite) TestYieldReleasesLuaStateMutex() {
+	// GOAL: Verify blim.yield() releases the Lua state mutex so pending callbacks run without sleeping
+	//
+	// TEST SCENARIO: Start delayed notification → setup subscription + busy loop calling yield() → callback executes inside the loop → loop exits early
+
+	go func() {
+		time.Sleep(50 * time.Millisecond)
+		suite.NewPeripheralDataSimulator().
+			WithService("1234").
+			WithCharacteristic("5678", []byte{0x42}).
+			Simulate(false)
+	}()
+
+	script := `
+		callback_received = false
+
+		blim.subscribe{
+			services = {
+				{
+					service = "1234",
+					chars = {"5678"}
+				}
+			},
+			Mode = "EveryUpdate",
+			MaxRate = 0,
+			Callback = function(record)
+				callback_received = true
+			end
+		}
+
+		-- Busy loop without sleeping; only yield() gives the callback a chance to run
+		local iterations = 0
+		repeat
+			iterations = iterations + 1
+			blim.yield()
+		until callback_received or iterations >= 50000000
+
+		assert(callback_received == true, "callback MUST be invoked during blim.yield() (internal lua state mutex must be released)")
+	`
+	err := suite.ExecuteScript(script)
+	suite.NoError(err, "yield should release mutex allowing callback execution")
+}
+
 // TestLuaAPITestSuite runs the test suite using testify/suite
 func TestLuaAPITestSuite(t *testing.T) {
 	suitelib.Run(t, new(LuaApiTestSuite))
@@ -415,6 +415,11 @@ func (e *LuaEngine) OutputChannel() <-chan LuaOutputRecord {
 	return e.outputChan.C()
 }
 
+// OutputDropped returns how many output records were dropped because the output channel was full
+func (e *LuaEngine) OutputDropped() int64 {
+	return e.outputChan.GetMetrics().Overwritten
+}
+
 // parseLuaError extracts detailed info from Lua error messages
 func (e *LuaEngine) parseLuaError(errType, source string) *LuaError {
 	if e.state.GetTop() == 0 {
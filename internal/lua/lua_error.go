@@ -0,0 +1,67 @@
+package lua
+
+import (
+	"fmt"
+
+	"github.com/aarzilli/golua/lua"
+	"github.com/srg/blim/internal/device"
+)
+
+// luaErrorMetatableName is the registry name of the metatable shared by structured error tables
+const luaErrorMetatableName = "blim.error"
+
+// luaErrorMetatableInit populates the error metatable (passed as the chunk argument) so error tables stay
+// compatible with the former plain string errors: tostring(err) and concatenation yield the message,
+// and string methods (err:find(...), err:match(...)) operate on the message.
+const luaErrorMetatableInit = `
+local mt = ...
+mt.__tostring = function(e) return rawget(e, "message") end
+mt.__concat = function(a, b) return tostring(a) .. tostring(b) end
+mt.__index = function(_, key)
+    local fn = string[key]
+    if fn then
+        return function(self, ...) return fn(rawget(self, "message"), ...) end
+    end
+end
+`
+
+// registerErrorMetatable creates the metatable used by pushLuaError
+func registerErrorMetatable(L *lua.State) error {
+	L.NewMetaTable(luaErrorMetatableName)
+	defer L.Pop(1)
+
+	if L.LoadString(luaErrorMetatableInit) != 0 {
+		msg := L.ToString(-1)
+		L.Pop(1)
+		return fmt.Errorf("failed to load error metatable: %s", msg)
+	}
+	L.PushValue(-2)
+	if err := L.Call(1, 0); err != nil {
+		return fmt.Errorf("failed to initialize error metatable: %w", err)
+	}
+	return nil
+}
+
+// pushLuaError pushes a structured error table {code=, message=, att_code=} for a failed operation.
+// code comes from device.ErrorCode, att_code is only set for ATT protocol errors, and message keeps the
+// "<op> failed: <reason>" format returned before errors were structured.
+func pushLuaError(L *lua.State, op string, err error) {
+	L.NewTable()
+
+	L.PushString("code")
+	L.PushString(device.ErrorCode(err))
+	L.SetTable(-3)
+
+	L.PushString("message")
+	L.PushString(fmt.Sprintf("%s failed: %s", op, stripWrappedGoErrorSuffix(err.Error())))
+	L.SetTable(-3)
+
+	if code, ok := device.ATTErrorCodeOf(err); ok {
+		L.PushString("att_code")
+		L.PushInteger(int64(code))
+		L.SetTable(-3)
+	}
+
+	L.LGetMetaTable(luaErrorMetatableName)
+	L.SetMetaTable(-2)
+}
@@ -0,0 +1,163 @@
+package lua
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/srg/blim/internal/groutine"
+)
+
+// OutputFlushMode selects when buffered Lua output is flushed to the underlying writer
+type OutputFlushMode int
+
+const (
+	FlushImmediate  OutputFlushMode = iota // Write every record through (interactive default)
+	FlushOnSize                            // Flush when the buffer holds Size bytes
+	FlushOnInterval                        // Flush every Interval
+)
+
+// defaultIntervalBufferSize is the buffer size used by FlushOnInterval; a full buffer is flushed early
+const defaultIntervalBufferSize = 64 * 1024
+
+// OutputFlushPolicy configures buffering of Lua output written to stdout/stderr
+type OutputFlushPolicy struct {
+	Mode     OutputFlushMode
+	Size     int           // Buffer size in bytes for FlushOnSize
+	Interval time.Duration // Flush interval for FlushOnInterval
+}
+
+// String returns the policy in the format accepted by ParseOutputFlushPolicy
+func (p OutputFlushPolicy) String() string {
+	switch p.Mode {
+	case FlushOnSize:
+		return fmt.Sprintf("size:%d", p.Size)
+	case FlushOnInterval:
+		return fmt.Sprintf("interval:%s", p.Interval)
+	default:
+		return "immediate"
+	}
+}
+
+// ParseOutputFlushPolicy parses an output buffering policy:
+// "immediate" (or empty), "size:<bytes>" (e.g. size:65536) or "interval:<duration>" (e.g. interval:200ms).
+func ParseOutputFlushPolicy(s string) (OutputFlushPolicy, error) {
+	kind, value, _ := strings.Cut(strings.ToLower(strings.TrimSpace(s)), ":")
+	switch kind {
+	case "", "immediate":
+		if value != "" {
+			return OutputFlushPolicy{}, fmt.Errorf("immediate output buffer takes no value, got %q", s)
+		}
+		return OutputFlushPolicy{Mode: FlushImmediate}, nil
+	case "size":
+		size, err := strconv.Atoi(value)
+		if err != nil || size <= 0 {
+			return OutputFlushPolicy{}, fmt.Errorf("invalid output buffer size %q: expected a positive number of bytes", value)
+		}
+		return OutputFlushPolicy{Mode: FlushOnSize, Size: size}, nil
+	case "interval":
+		interval, err := time.ParseDuration(value)
+		if err != nil || interval <= 0 {
+			return OutputFlushPolicy{}, fmt.Errorf("invalid output buffer interval %q: expected a positive duration", value)
+		}
+		return OutputFlushPolicy{Mode: FlushOnInterval, Interval: interval}, nil
+	default:
+		return OutputFlushPolicy{}, fmt.Errorf("invalid output buffer %q: expected immediate, size:<bytes> or interval:<duration>", s)
+	}
+}
+
+// BufferedOutputWriter applies an OutputFlushPolicy to an io.Writer.
+// Close must be called on shutdown to flush the remaining output.
+//
+// All methods are thread-safe.
+type BufferedOutputWriter struct {
+	mu     sync.Mutex
+	w      io.Writer
+	buf    *bufio.Writer // nil for FlushImmediate
+	stop   chan struct{}
+	done   chan struct{}
+	closed bool
+}
+
+// NewBufferedOutputWriter wraps w according to policy. FlushOnInterval starts a background
+// goroutine that flushes the buffer every policy.Interval until Close is called.
+func NewBufferedOutputWriter(w io.Writer, policy OutputFlushPolicy) *BufferedOutputWriter {
+	bw := &BufferedOutputWriter{w: w}
+	switch policy.Mode {
+	case FlushOnSize:
+		bw.buf = bufio.NewWriterSize(w, policy.Size)
+	case FlushOnInterval:
+		bw.buf = bufio.NewWriterSize(w, defaultIntervalBufferSize)
+		bw.stop = make(chan struct{})
+		bw.done = make(chan struct{})
+		groutine.Go(context.Background(), "lua-output-flusher", func(ctx context.Context) {
+			bw.flushLoop(policy.Interval)
+		})
+	}
+	return bw
+}
+
+func (bw *BufferedOutputWriter) flushLoop(interval time.Duration) {
+	defer close(bw.done)
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			_ = bw.Flush()
+		case <-bw.stop:
+			return
+		}
+	}
+}
+
+// Write writes p through the buffer, or directly to the underlying writer for FlushImmediate
+// or after Close.
+func (bw *BufferedOutputWriter) Write(p []byte) (int, error) {
+	bw.mu.Lock()
+	defer bw.mu.Unlock()
+	if bw.buf == nil {
+		return bw.w.Write(p)
+	}
+	if bw.closed {
+		// Keep ordering with output still buffered when Close raced with this write
+		if err := bw.buf.Flush(); err != nil {
+			return 0, err
+		}
+		return bw.w.Write(p)
+	}
+	return bw.buf.Write(p)
+}
+
+// Flush writes any buffered output to the underlying writer
+func (bw *BufferedOutputWriter) Flush() error {
+	bw.mu.Lock()
+	defer bw.mu.Unlock()
+	if bw.buf == nil {
+		return nil
+	}
+	return bw.buf.Flush()
+}
+
+// Close stops the interval flusher and flushes the remaining output.
+// Writes after Close go directly to the underlying writer.
+func (bw *BufferedOutputWriter) Close() error {
+	bw.mu.Lock()
+	if bw.closed {
+		bw.mu.Unlock()
+		return nil
+	}
+	bw.closed = true
+	bw.mu.Unlock()
+
+	if bw.stop != nil {
+		close(bw.stop)
+		<-bw.done
+	}
+	return bw.Flush()
+}
@@ -0,0 +1,111 @@
+package lua
+
+import (
+	"bytes"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// syncBuffer is a bytes.Buffer safe for the interval flusher goroutine
+type syncBuffer struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (b *syncBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.Write(p)
+}
+
+func (b *syncBuffer) String() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.String()
+}
+
+func TestParseOutputFlushPolicy(t *testing.T) {
+	// GOAL: Verify --output-buffer values parse into flush policies and invalid values are rejected
+	//
+	// TEST SCENARIO: Parse immediate, size and interval forms → policies match → malformed values return errors
+
+	for _, tc := range []struct {
+		in   string
+		want OutputFlushPolicy
+	}{
+		{"", OutputFlushPolicy{Mode: FlushImmediate}},
+		{"immediate", OutputFlushPolicy{Mode: FlushImmediate}},
+		{"size:4096", OutputFlushPolicy{Mode: FlushOnSize, Size: 4096}},
+		{"interval:200ms", OutputFlushPolicy{Mode: FlushOnInterval, Interval: 200 * time.Millisecond}},
+	} {
+		got, err := ParseOutputFlushPolicy(tc.in)
+		require.NoError(t, err, tc.in)
+		assert.Equal(t, tc.want, got, tc.in)
+		assert.Equal(t, got, mustParseFlushPolicy(t, got.String()), "String() MUST round-trip for %q", tc.in)
+	}
+
+	for _, in := range []string{"immediate:1", "size:0", "size:abc", "interval:-1s", "interval:", "lines:10"} {
+		_, err := ParseOutputFlushPolicy(in)
+		assert.Error(t, err, "%q MUST be rejected", in)
+	}
+}
+
+func mustParseFlushPolicy(t *testing.T, s string) OutputFlushPolicy {
+	p, err := ParseOutputFlushPolicy(s)
+	require.NoError(t, err)
+	return p
+}
+
+func TestBufferedOutputWriter(t *testing.T) {
+	// GOAL: Verify each flush policy delivers output at the expected time and Close never loses buffered output
+	//
+	// TEST SCENARIO: Write through immediate, size and interval writers → check the underlying writer before and after flush points and Close
+
+	t.Run("immediate writes through", func(t *testing.T) {
+		var out syncBuffer
+		w := NewBufferedOutputWriter(&out, OutputFlushPolicy{Mode: FlushImmediate})
+		_, err := w.Write([]byte("line\n"))
+		require.NoError(t, err)
+		assert.Equal(t, "line\n", out.String(), "immediate policy MUST NOT buffer")
+		require.NoError(t, w.Close())
+	})
+
+	t.Run("size flushes when full", func(t *testing.T) {
+		var out syncBuffer
+		w := NewBufferedOutputWriter(&out, OutputFlushPolicy{Mode: FlushOnSize, Size: 8})
+		_, err := w.Write([]byte("abcd"))
+		require.NoError(t, err)
+		assert.Empty(t, out.String(), "output below the size MUST stay buffered")
+
+		_, err = w.Write([]byte("efghij"))
+		require.NoError(t, err)
+		assert.Equal(t, "abcdefgh", out.String(), "a full buffer MUST be flushed")
+
+		require.NoError(t, w.Close())
+		assert.Equal(t, "abcdefghij", out.String(), "Close MUST flush the remaining output")
+	})
+
+	t.Run("interval flushes periodically", func(t *testing.T) {
+		var out syncBuffer
+		w := NewBufferedOutputWriter(&out, OutputFlushPolicy{Mode: FlushOnInterval, Interval: 20 * time.Millisecond})
+		_, err := w.Write([]byte("tick\n"))
+		require.NoError(t, err)
+		assert.Empty(t, out.String(), "output MUST stay buffered until the interval elapses")
+
+		assert.Eventually(t, func() bool { return out.String() == "tick\n" }, time.Second, 5*time.Millisecond,
+			"interval flusher MUST write buffered output")
+
+		_, err = w.Write([]byte("tail\n"))
+		require.NoError(t, err)
+		require.NoError(t, w.Close())
+		assert.Equal(t, "tick\ntail\n", out.String(), "Close MUST flush the remaining output")
+
+		_, err = w.Write([]byte("late\n"))
+		require.NoError(t, err)
+		assert.Equal(t, "tick\ntail\nlate\n", out.String(), "writes after Close MUST go straight through")
+	})
+}
@@ -3,6 +3,8 @@ package lua
 import (
 	"context"
 	"fmt"
+	"io"
+	"os"
 	"reflect"
 	"strings"
 	"sync"
@@ -21,6 +23,7 @@ type LuaOutputCollectorMetrics struct {
 
 	// TODO: add proper collection after https://github.com/hedzr/go-ringbuf/issues/7 will be somehow added
 	RecordsOverwritten int64 // Records lost due to buffer overflow
+	RecordsDropped     int64 // Records dropped by the source output channel before they reached the collector
 }
 
 // IncrementRecordsProcessed atomically increments the records processed counter
@@ -38,6 +41,11 @@ func (m *LuaOutputCollectorMetrics) IncrementRecordsOverwritten(count uint32) {
 	atomic.AddInt64(&m.RecordsOverwritten, int64(count))
 }
 
+// SetRecordsDropped atomically stores the dropped records counter
+func (m *LuaOutputCollectorMetrics) SetRecordsDropped(count int64) {
+	atomic.StoreInt64(&m.RecordsDropped, count)
+}
+
 // GetRecordsProcessed atomically reads the record processed counter
 func (m *LuaOutputCollectorMetrics) GetRecordsProcessed() int64 {
 	return atomic.LoadInt64(&m.RecordsProcessed)
@@ -53,11 +61,22 @@ func (m *LuaOutputCollectorMetrics) GetRecordsOverwritten() int64 {
 	return atomic.LoadInt64(&m.RecordsOverwritten)
 }
 
+// GetRecordsDropped atomically reads the dropped records counter
+func (m *LuaOutputCollectorMetrics) GetRecordsDropped() int64 {
+	return atomic.LoadInt64(&m.RecordsDropped)
+}
+
 // Reset resets all counters to zero
 func (m *LuaOutputCollectorMetrics) Reset() {
 	atomic.StoreInt64(&m.RecordsProcessed, 0)
 	atomic.StoreInt64(&m.ErrorsOccurred, 0)
 	atomic.StoreInt64(&m.RecordsOverwritten, 0)
+	atomic.StoreInt64(&m.RecordsDropped, 0)
+}
+
+// lost returns the total number of records lost to overflow
+func (m *LuaOutputCollectorMetrics) lost() int64 {
+	return m.GetRecordsOverwritten() + m.GetRecordsDropped()
 }
 
 // LuaOutputCollector gathers output records from concurrent Lua execution into
@@ -73,6 +92,11 @@ type LuaOutputCollector struct {
 	onError    func(error)               // error handler, defaults to panic if nil
 	metrics    LuaOutputCollectorMetrics // lock-free metrics tracking
 	state      uint32                    // atomic state using CollectorState constants (uint32 required for atomic ops)
+
+	overflowSource func() int64 // cumulative drop counter of the source channel, nil if unknown
+	overflowBase   atomic.Int64 // overflowSource value when collection started or metrics were reset
+	overflowWarned atomic.Bool  // set once the first-overflow warning was written
+	warnOut        io.Writer    // overflow warnings destination, os.Stderr by default
 }
 
 const (
@@ -116,9 +140,36 @@ func NewLuaOutputCollector(ch <-chan LuaOutputRecord, bufferSize uint32, onError
 		onError:    onError,
 		metrics:    LuaOutputCollectorMetrics{}, // Initialize metrics
 		state:      CollectorStateNotRunning,    // Initialize state
+		warnOut:    os.Stderr,
 	}, nil
 }
 
+// SetOverflowSource sets the cumulative drop counter of the output channel (e.g. LuaAPI.OutputDropped),
+// so records dropped before they reach the collector are counted in RecordsDropped.
+// Must be called before Start.
+func (c *LuaOutputCollector) SetOverflowSource(source func() int64) {
+	c.overflowSource = source
+}
+
+// checkOverflow refreshes RecordsDropped from the overflow source and writes a one-time warning
+// when output is lost for the first time
+func (c *LuaOutputCollector) checkOverflow() {
+	if c.overflowSource != nil {
+		c.metrics.SetRecordsDropped(c.overflowSource() - c.overflowBase.Load())
+	}
+	if c.metrics.lost() > 0 && c.overflowWarned.CompareAndSwap(false, true) {
+		_, _ = fmt.Fprintln(c.warnOut, "warning: Lua output is produced faster than it is consumed, some records were dropped")
+	}
+}
+
+// reportOverflow writes how many records were lost in total, if any were
+func (c *LuaOutputCollector) reportOverflow() {
+	c.checkOverflow()
+	if lost := c.metrics.lost(); lost > 0 {
+		_, _ = fmt.Fprintf(c.warnOut, "warning: %d Lua output record(s) were dropped\n", lost)
+	}
+}
+
 // Start begins collecting output records.
 // Blocks until the collector goroutine is running or times out.
 // Returns an error if already started or if startup takes too long.
@@ -143,6 +194,11 @@ func (c *LuaOutputCollector) Start() error {
 	c.done = make(chan struct{})
 	c.chanMu.Unlock()
 
+	c.overflowWarned.Store(false)
+	if c.overflowSource != nil {
+		c.overflowBase.Store(c.overflowSource())
+	}
+
 	// Buffered channel for startup signaling. Buffered (not context.Context) because:
 	// - Simple one-time signal doesn't need context's propagation semantics
 	// - Buffer prevents goroutine blocking even if timeout occurs before signal is sent
@@ -154,6 +210,7 @@ func (c *LuaOutputCollector) Start() error {
 		started <- struct{}{}
 
 		defer func() {
+			c.reportOverflow()
 			close(c.done)
 			atomic.StoreUint32(&c.state, CollectorStateNotRunning) // Reset state on exit
 		}()
@@ -172,6 +229,7 @@ func (c *LuaOutputCollector) Start() error {
 					c.metrics.IncrementRecordsOverwritten(overwrites)
 					c.metrics.IncrementRecordsProcessed()
 				}
+				c.checkOverflow()
 			case <-c.stop:
 				// drain remaining messages non-blocking
 				for {
@@ -246,11 +304,15 @@ func (c *LuaOutputCollector) GetMetrics() LuaOutputCollectorMetrics {
 		RecordsProcessed:   c.metrics.GetRecordsProcessed(),
 		ErrorsOccurred:     c.metrics.GetErrorsOccurred(),
 		RecordsOverwritten: c.metrics.GetRecordsOverwritten(),
+		RecordsDropped:     c.metrics.GetRecordsDropped(),
 	}
 }
 
 // ResetMetrics atomically resets all metric counters
 func (c *LuaOutputCollector) ResetMetrics() {
+	if c.overflowSource != nil {
+		c.overflowBase.Store(c.overflowSource())
+	}
 	c.metrics.Reset()
 }
 
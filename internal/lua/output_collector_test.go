@@ -400,6 +400,39 @@ func (suite *LuaOutputCollectorTestSuite) TestMetrics() {
 }
 
 // TestConsumerFunctions tests the consumer pattern and records consumption
+func (suite *LuaOutputCollectorTestSuite) TestOverflowReporting() {
+	// GOAL: Verify records dropped by the source channel are counted and reported once, with the total on stop
+	//
+	// TEST SCENARIO: Drops before Start are ignored → drops while collecting update RecordsDropped → one warning → total reported on Stop
+
+	ch := make(chan LuaOutputRecord, 10)
+	defer close(ch)
+
+	var dropped atomic.Int64
+	dropped.Store(2) // Dropped before collection starts, not counted
+
+	collector, err := NewLuaOutputCollector(ch, 100, nil)
+	suite.Require().NoError(err)
+	collector.SetOverflowSource(dropped.Load)
+	var warnings strings.Builder
+	collector.warnOut = &warnings
+
+	suite.Require().NoError(collector.Start())
+	ch <- LuaOutputRecord{Content: "a", Source: "stdout"}
+	suite.Eventually(func() bool { return collector.GetMetrics().RecordsProcessed == 1 }, time.Second, time.Millisecond)
+	suite.Equal(int64(0), collector.GetMetrics().RecordsDropped, "overflow before Start MUST NOT be counted")
+
+	dropped.Add(3)
+	ch <- LuaOutputRecord{Content: "b", Source: "stdout"}
+	ch <- LuaOutputRecord{Content: "c", Source: "stdout"}
+	suite.Eventually(func() bool { return collector.GetMetrics().RecordsProcessed == 3 }, time.Second, time.Millisecond)
+	suite.Require().NoError(collector.Stop())
+
+	suite.Equal(int64(3), collector.GetMetrics().RecordsDropped, "drops while collecting MUST be counted")
+	suite.Equal(1, strings.Count(warnings.String(), "faster than it is consumed"), "first-overflow warning MUST be written once")
+	suite.Contains(warnings.String(), "3 Lua output record(s) were dropped", "Stop MUST report the total")
+}
+
 func (suite *LuaOutputCollectorTestSuite) TestConsumerFunctions() {
 	// GOAL: Verify ConsumerFunc pattern processes buffered records and handles early termination
 	//
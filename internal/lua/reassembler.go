@@ -0,0 +1,101 @@
+package lua
+
+import (
+	"bytes"
+	"sync"
+
+	"github.com/srg/blim/internal/device"
+)
+
+// DefaultReassembleMaxSize is the default limit for buffered, not yet terminated fragment data per characteristic
+const DefaultReassembleMaxSize = 4096
+
+// messageReassembler joins notification fragments into terminator-delimited messages, buffering per characteristic.
+// Delivered messages exclude the terminator.
+type messageReassembler struct {
+	terminator []byte
+	maxSize    int
+
+	mu      sync.Mutex
+	buffers map[string][]byte
+}
+
+func newMessageReassembler(terminator []byte, maxSize int) *messageReassembler {
+	if maxSize <= 0 {
+		maxSize = DefaultReassembleMaxSize
+	}
+	return &messageReassembler{
+		terminator: append([]byte(nil), terminator...),
+		maxSize:    maxSize,
+		buffers:    make(map[string][]byte),
+	}
+}
+
+// push appends a fragment and returns the messages it completes.
+// overflow is true if the unterminated remainder exceeded maxSize and was discarded.
+func (r *messageReassembler) push(uuid string, fragment []byte) (messages [][]byte, overflow bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	buf := append(r.buffers[uuid], fragment...)
+	for {
+		i := bytes.Index(buf, r.terminator)
+		if i < 0 {
+			break
+		}
+		messages = append(messages, append([]byte(nil), buf[:i]...))
+		buf = buf[i+len(r.terminator):]
+	}
+
+	if len(buf) > r.maxSize {
+		buf, overflow = nil, true
+	}
+	if len(buf) == 0 {
+		delete(r.buffers, uuid)
+	} else {
+		r.buffers[uuid] = append([]byte(nil), buf...)
+	}
+	return messages, overflow
+}
+
+// apply feeds every value of the record through the reassembler and returns records carrying completed messages.
+// A Values record yields one record per completed message; a BatchValues record yields a single record.
+// overflowed lists characteristics whose buffered data was discarded.
+func (r *messageReassembler) apply(record *device.Record) (records []*device.Record, overflowed []string) {
+	for _, uuid := range record.Keys() {
+		data, ok := record.Values[uuid]
+		if !ok {
+			continue
+		}
+		messages, overflow := r.push(uuid, data)
+		if overflow {
+			overflowed = append(overflowed, uuid)
+		}
+		for _, msg := range messages {
+			msgRecord := &device.Record{TsUs: record.TsUs, Seq: record.Seq, Flags: record.Flags}
+			msgRecord.SetValue(uuid, msg)
+			records = append(records, msgRecord)
+		}
+	}
+
+	var batched *device.Record
+	for _, uuid := range record.Keys() {
+		for _, data := range record.BatchValues[uuid] {
+			messages, overflow := r.push(uuid, data)
+			if overflow {
+				overflowed = append(overflowed, uuid)
+			}
+			if len(messages) == 0 {
+				continue
+			}
+			if batched == nil {
+				batched = &device.Record{TsUs: record.TsUs, Seq: record.Seq, Flags: record.Flags}
+			}
+			batched.AppendBatchValues(uuid, messages...)
+		}
+	}
+	if batched != nil {
+		records = append(records, batched)
+	}
+	return records, overflowed
+}
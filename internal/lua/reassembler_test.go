@@ -0,0 +1,66 @@
+package lua
+
+import (
+	"testing"
+
+	"github.com/srg/blim/internal/device"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMessageReassembler(t *testing.T) {
+	// GOAL: Verify fragments are buffered per characteristic and delivered as terminator-delimited messages
+	//
+	// TEST SCENARIO: Push fragments split across notifications → messages emitted without terminator → oversize remainder discarded
+
+	t.Run("joins fragments", func(t *testing.T) {
+		r := newMessageReassembler([]byte("\r\n"), 0)
+
+		msgs, overflow := r.push("2a37", []byte("hel"))
+		assert.Empty(t, msgs, "no message MUST be emitted before the terminator")
+		assert.False(t, overflow)
+
+		msgs, _ = r.push("2a38", []byte("other"))
+		assert.Empty(t, msgs, "characteristics MUST be buffered independently")
+
+		msgs, _ = r.push("2a37", []byte("lo\r"))
+		assert.Empty(t, msgs, "a partial terminator MUST NOT complete a message")
+
+		msgs, _ = r.push("2a37", []byte("\nab\r\ncd"))
+		assert.Equal(t, [][]byte{[]byte("hello"), []byte("ab")}, msgs, "completed messages MUST exclude the terminator")
+
+		msgs, _ = r.push("2a37", []byte("\r\n"))
+		assert.Equal(t, [][]byte{[]byte("cd")}, msgs, "the remainder MUST carry over to the next message")
+
+		msgs, _ = r.push("2a38", []byte("\r\n"))
+		assert.Equal(t, [][]byte{[]byte("other")}, msgs)
+	})
+
+	t.Run("discards oversize remainder", func(t *testing.T) {
+		r := newMessageReassembler([]byte{0x00}, 4)
+
+		_, overflow := r.push("2a37", []byte{1, 2, 3})
+		assert.False(t, overflow)
+
+		_, overflow = r.push("2a37", []byte{4, 5})
+		assert.True(t, overflow, "buffered data over max size MUST be reported")
+
+		msgs, _ := r.push("2a37", []byte{6, 0x00})
+		assert.Equal(t, [][]byte{{6}}, msgs, "discarded data MUST NOT leak into the next message")
+	})
+
+	t.Run("applies to records", func(t *testing.T) {
+		r := newMessageReassembler([]byte(";"), 0)
+
+		records, overflowed := r.apply(&device.Record{TsUs: 1, Values: map[string][]byte{"5678": []byte("a;b;c")}})
+		require.Len(t, records, 2, "every completed message MUST yield its own record")
+		assert.Equal(t, []byte("a"), records[0].Values["5678"])
+		assert.Equal(t, []byte("b"), records[1].Values["5678"])
+		assert.Empty(t, overflowed)
+
+		records, _ = r.apply(&device.Record{TsUs: 2, BatchValues: map[string][][]byte{"5678": {[]byte("d;"), []byte("e"), []byte(";")}}})
+		require.Len(t, records, 1, "batched values MUST yield a single record")
+		assert.Equal(t, [][]byte{[]byte("cd"), []byte("e")}, records[0].BatchValues["5678"])
+		assert.Equal(t, int64(2), records[0].TsUs)
+	})
+}
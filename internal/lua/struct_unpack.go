@@ -0,0 +1,118 @@
+package lua
+
+import (
+	"encoding/binary"
+	"fmt"
+	"math"
+)
+
+// unpackStruct decodes data according to a string.pack-style format spec and returns the decoded fields.
+//
+// Supported format options:
+//
+//	< little-endian (default)   > big-endian   = native (little-endian)
+//	b/B int8/uint8    h/H int16/uint16    i[n]/I[n] signed/unsigned n-byte integer (n = 1..8, default 4)
+//	l/L, j/J int64/uint64    f float32    d/n float64    x one byte of padding (skipped)
+//
+// Spaces are ignored. Integer fields are returned as int64 (uint64 values above math.MaxInt64 wrap, as in Lua),
+// float fields as float64. The total size of the format must match len(data) exactly.
+func unpackStruct(format string, data []byte) ([]any, error) {
+	var (
+		order  binary.ByteOrder = binary.LittleEndian
+		fields []any
+		offset int
+	)
+
+	take := func(size int) ([]byte, error) {
+		if offset+size > len(data) {
+			return nil, fmt.Errorf("data too short for format %q: need at least %d bytes, got %d", format, offset+size, len(data))
+		}
+		b := data[offset : offset+size]
+		offset += size
+		return b, nil
+	}
+
+	for i := 0; i < len(format); i++ {
+		opt := format[i]
+
+		switch opt {
+		case ' ':
+			continue
+		case '<', '=':
+			order = binary.LittleEndian
+			continue
+		case '>':
+			order = binary.BigEndian
+			continue
+		}
+
+		size, signed, isFloat := 0, false, false
+		switch opt {
+		case 'b', 'B':
+			size, signed = 1, opt == 'b'
+		case 'h', 'H':
+			size, signed = 2, opt == 'h'
+		case 'l', 'L', 'j', 'J':
+			size, signed = 8, opt == 'l' || opt == 'j'
+		case 'i', 'I':
+			size, signed = 4, opt == 'i'
+			if i+1 < len(format) && format[i+1] >= '0' && format[i+1] <= '9' {
+				size = int(format[i+1] - '0')
+				i++
+				if size < 1 || size > 8 {
+					return nil, fmt.Errorf("invalid integer size %d in format %q (must be 1..8)", size, format)
+				}
+			}
+		case 'f':
+			size, isFloat = 4, true
+		case 'd', 'n':
+			size, isFloat = 8, true
+		case 'x':
+			if _, err := take(1); err != nil {
+				return nil, err
+			}
+			continue
+		default:
+			return nil, fmt.Errorf("invalid format option %q in format %q", opt, format)
+		}
+
+		b, err := take(size)
+		if err != nil {
+			return nil, err
+		}
+
+		if isFloat {
+			if size == 4 {
+				fields = append(fields, float64(math.Float32frombits(order.Uint32(b))))
+			} else {
+				fields = append(fields, math.Float64frombits(order.Uint64(b)))
+			}
+			continue
+		}
+		fields = append(fields, decodeInt(b, order, signed))
+	}
+
+	if offset != len(data) {
+		return nil, fmt.Errorf("data length mismatch for format %q: format describes %d bytes, got %d", format, offset, len(data))
+	}
+	return fields, nil
+}
+
+// decodeInt decodes a 1..8 byte integer, sign-extending when signed
+func decodeInt(b []byte, order binary.ByteOrder, signed bool) int64 {
+	var u uint64
+	for i := range b {
+		shift := uint(i) * 8
+		if order == binary.BigEndian {
+			shift = uint(len(b)-1-i) * 8
+		}
+		u |= uint64(b[i]) << shift
+	}
+	if signed && len(b) < 8 {
+		bits := uint(len(b)) * 8
+		if u&(1<<(bits-1)) != 0 {
+			u |= ^uint64(0) << bits
+		}
+	}
+	return int64(u)
+}
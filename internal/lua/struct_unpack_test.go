@@ -0,0 +1,58 @@
+package lua
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUnpackStruct(t *testing.T) {
+	// GOAL: Verify string.pack-style formats decode fixed layouts and reject mismatches
+	//
+	// TEST SCENARIO: Decode mixed layouts in both byte orders → fields match → invalid format or length → error
+
+	tests := []struct {
+		name   string
+		format string
+		data   []byte
+		want   []any
+	}{
+		{"little-endian uint16, uint8, float", "<HBf", []byte{0x34, 0x12, 0xFF, 0x00, 0x00, 0x80, 0x3F}, []any{int64(0x1234), int64(255), float64(1)}},
+		{"big-endian int16", ">h", []byte{0xFF, 0xFE}, []any{int64(-2)}},
+		{"default is little-endian", "H", []byte{0x01, 0x00}, []any{int64(1)}},
+		{"sized signed integer", "<i3", []byte{0xFF, 0xFF, 0xFF}, []any{int64(-1)}},
+		{"sized unsigned integer", ">I3", []byte{0x01, 0x02, 0x03}, []any{int64(0x010203)}},
+		{"padding and spaces", "<B x B", []byte{0x01, 0xAA, 0x02}, []any{int64(1), int64(2)}},
+		{"mixed order", "<H>H", []byte{0x01, 0x00, 0x00, 0x01}, []any{int64(1), int64(1)}},
+		{"double", "<d", []byte{0, 0, 0, 0, 0, 0, 0xF0, 0x3F}, []any{float64(1)}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := unpackStruct(tt.format, tt.data)
+			require.NoError(t, err, "valid format MUST decode")
+			assert.Equal(t, tt.want, got, "decoded fields MUST match")
+		})
+	}
+
+	errorTests := []struct {
+		name    string
+		format  string
+		data    []byte
+		message string
+	}{
+		{"data too short", "<HH", []byte{0x01, 0x00, 0x02}, "data too short"},
+		{"trailing data", "<B", []byte{0x01, 0x02}, "data length mismatch"},
+		{"unknown option", "<Z", []byte{0x01}, "invalid format option"},
+		{"invalid integer size", "<i9", []byte{0x01}, "invalid integer size"},
+	}
+
+	for _, tt := range errorTests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := unpackStruct(tt.format, tt.data)
+			require.Error(t, err, "invalid format or length MUST fail")
+			assert.Contains(t, err.Error(), tt.message)
+		})
+	}
+}
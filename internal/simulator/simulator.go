@@ -0,0 +1,438 @@
+// Package simulator provides a simulated BLE peripheral backed by a JSON device description.
+// It lets blim commands run against a fake device with no Bluetooth hardware (e.g., for CI and demos).
+package simulator
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/go-ble/ble"
+	"github.com/srg/blim/internal/gattprofile"
+)
+
+// Profile is a JSON device description. The service/characteristic layout is the peripheral description
+// shared with the test suites' mock peripheral builder (see gattprofile.DeviceProfileConfig):
+//
+//	{
+//	  "services": [
+//	    {"uuid": "180D", "characteristics": [
+//	      {"uuid": "2A37", "properties": "read,notify", "value": [0, 72]}
+//	    ]}
+//	  ],
+//	  "script": [
+//	    {"delay_ms": 1000, "service": "180D", "char": "2A37", "value": [0, 75]}
+//	  ],
+//	  "repeat": true,
+//	  "rssi": -55
+//	}
+//
+// The optional script drives notifications once the first subscription is made.
+type Profile struct {
+	gattprofile.DeviceProfileConfig
+	Script []ScriptStep `json:"script,omitempty"`
+	Repeat bool         `json:"repeat,omitempty"` // Restart the script after the last step
+	RSSI   int          `json:"rssi,omitempty"`   // Connection RSSI in dBm (default: DefaultRSSI)
+}
+
+// DefaultRSSI is the connection RSSI reported when the profile does not set one
+const DefaultRSSI = -60
+
+// ScriptStep emits a single notification
+type ScriptStep struct {
+	DelayMs        int    `json:"delay_ms"` // Delay before the notification, relative to the previous step
+	Service        string `json:"service"`
+	Characteristic string `json:"char"`
+	Value          []byte `json:"value"`
+}
+
+// LoadProfile reads and validates a JSON device description from a file
+func LoadProfile(path string) (*Profile, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read simulated device description: %w", err)
+	}
+	profile, err := ParseProfile(data)
+	if err != nil {
+		return nil, fmt.Errorf("invalid simulated device description %s: %w", path, err)
+	}
+	return profile, nil
+}
+
+// ParseProfile parses and validates a JSON device description
+func ParseProfile(data []byte) (*Profile, error) {
+	var profile Profile
+	if err := json.Unmarshal(data, &profile); err != nil {
+		return nil, fmt.Errorf("failed to parse JSON: %w", err)
+	}
+	if len(profile.Services) == 0 {
+		return nil, fmt.Errorf("no services defined")
+	}
+
+	// Validate by building the GATT profile once
+	if _, _, err := buildProfile(&profile); err != nil {
+		return nil, err
+	}
+	return &profile, nil
+}
+
+// NewDevice creates a simulated ble.Device for the profile.
+// Each call returns an independent device with its own characteristic values.
+func NewDevice(profile *Profile) (ble.Device, error) {
+	bleProfile, script, err := buildProfile(profile)
+	if err != nil {
+		return nil, err
+	}
+	rssi := profile.RSSI
+	if rssi == 0 {
+		rssi = DefaultRSSI
+	}
+	return &simDevice{profile: bleProfile, script: script, repeat: profile.Repeat, rssi: rssi}, nil
+}
+
+// scriptStep is a ScriptStep resolved to its simulated characteristic
+type scriptStep struct {
+	delay time.Duration
+	char  *ble.Characteristic
+	value []byte
+}
+
+// buildProfile builds the GATT profile with the shared peripheral builder and resolves the notification script
+func buildProfile(profile *Profile) (*ble.Profile, []scriptStep, error) {
+	bleProfile, err := gattprofile.Build(profile.DeviceProfileConfig)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	script := make([]scriptStep, 0, len(profile.Script))
+	totalDelay := 0
+	for i, step := range profile.Script {
+		char := gattprofile.FindCharacteristic(bleProfile, step.Service, step.Characteristic)
+		if char == nil {
+			return nil, nil, fmt.Errorf("script step %d: characteristic %s not found in service %s", i+1, step.Characteristic, step.Service)
+		}
+		if char.Property&(ble.CharNotify|ble.CharIndicate) == 0 {
+			return nil, nil, fmt.Errorf("script step %d: characteristic %s does not support notify or indicate", i+1, step.Characteristic)
+		}
+		if step.DelayMs < 0 {
+			return nil, nil, fmt.Errorf("script step %d: delay_ms must not be negative", i+1)
+		}
+		totalDelay += step.DelayMs
+		script = append(script, scriptStep{
+			delay: time.Duration(step.DelayMs) * time.Millisecond,
+			char:  char,
+			value: step.Value,
+		})
+	}
+
+	if profile.Repeat && len(script) > 0 && totalDelay == 0 {
+		return nil, nil, fmt.Errorf("repeated script must have a non-zero total delay")
+	}
+
+	return bleProfile, script, nil
+}
+
+// errUnsupported is returned by the operations the simulated device cannot perform
+var errUnsupported = errors.New("not supported by the simulated device")
+
+// ----------------------------
+// Simulated Device
+// ----------------------------
+
+// simDevice is a central-role ble.Device: it dials the simulated peripheral and finds nothing when scanning.
+// The peripheral-role (GATT server and advertising) operations return errUnsupported.
+type simDevice struct {
+	profile *ble.Profile
+	script  []scriptStep
+	repeat  bool
+	rssi    int
+}
+
+// Scan emits no advertisements; it blocks until ctx is done like a scan that finds nothing
+func (d *simDevice) Scan(ctx context.Context, _ bool, _ ble.AdvHandler) error {
+	<-ctx.Done()
+	return ctx.Err()
+}
+
+// Dial connects to the simulated peripheral regardless of the address
+func (d *simDevice) Dial(ctx context.Context, addr ble.Addr) (ble.Client, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	return &simClient{
+		device:       d,
+		addr:         addr,
+		handlers:     make(map[*ble.Characteristic]ble.NotificationHandler),
+		disconnected: make(chan struct{}),
+	}, nil
+}
+
+// Stop is a no-op for the simulated device
+func (d *simDevice) Stop() error {
+	return nil
+}
+
+// AddService is not supported: the simulated device has no GATT server
+func (d *simDevice) AddService(*ble.Service) error {
+	return fmt.Errorf("add service: %w", errUnsupported)
+}
+
+// RemoveAllServices is not supported: the simulated device has no GATT server
+func (d *simDevice) RemoveAllServices() error {
+	return fmt.Errorf("remove services: %w", errUnsupported)
+}
+
+// SetServices is not supported: the simulated device has no GATT server
+func (d *simDevice) SetServices([]*ble.Service) error {
+	return fmt.Errorf("set services: %w", errUnsupported)
+}
+
+// Advertise is not supported: the simulated device does not advertise
+func (d *simDevice) Advertise(context.Context, ble.Advertisement) error {
+	return fmt.Errorf("advertise: %w", errUnsupported)
+}
+
+// AdvertiseNameAndServices is not supported: the simulated device does not advertise
+func (d *simDevice) AdvertiseNameAndServices(context.Context, string, ...ble.UUID) error {
+	return fmt.Errorf("advertise: %w", errUnsupported)
+}
+
+// AdvertiseMfgData is not supported: the simulated device does not advertise
+func (d *simDevice) AdvertiseMfgData(context.Context, uint16, []byte) error {
+	return fmt.Errorf("advertise: %w", errUnsupported)
+}
+
+// AdvertiseServiceData16 is not supported: the simulated device does not advertise
+func (d *simDevice) AdvertiseServiceData16(context.Context, uint16, []byte) error {
+	return fmt.Errorf("advertise: %w", errUnsupported)
+}
+
+// AdvertiseIBeaconData is not supported: the simulated device does not advertise
+func (d *simDevice) AdvertiseIBeaconData(context.Context, []byte) error {
+	return fmt.Errorf("advertise: %w", errUnsupported)
+}
+
+// AdvertiseIBeacon is not supported: the simulated device does not advertise
+func (d *simDevice) AdvertiseIBeacon(context.Context, ble.UUID, uint16, uint16, int8) error {
+	return fmt.Errorf("advertise: %w", errUnsupported)
+}
+
+// ----------------------------
+// Simulated Client
+// ----------------------------
+
+// simClient is a connection to the simulated peripheral. Discovery returns the profile's own objects,
+// so values written through one characteristic are read back through it like on a real peripheral.
+type simClient struct {
+	device *simDevice
+	addr   ble.Addr
+
+	mu           sync.Mutex
+	handlers     map[*ble.Characteristic]ble.NotificationHandler
+	scriptOnce   sync.Once
+	closeOnce    sync.Once
+	disconnected chan struct{}
+}
+
+// Addr returns the address the client was dialed with
+func (c *simClient) Addr() ble.Addr {
+	return c.addr
+}
+
+// Name returns an empty name; the simulated peripheral does not advertise one
+func (c *simClient) Name() string {
+	return ""
+}
+
+// Profile returns the simulated GATT profile
+func (c *simClient) Profile() *ble.Profile {
+	return c.device.profile
+}
+
+// Conn returns nil: the simulated link has no underlying HCI connection
+func (c *simClient) Conn() ble.Conn {
+	return nil
+}
+
+// DiscoverProfile returns the simulated GATT profile
+func (c *simClient) DiscoverProfile(bool) (*ble.Profile, error) {
+	return c.device.profile, nil
+}
+
+// DiscoverServices returns the services matching filter (all when empty) without their characteristics
+func (c *simClient) DiscoverServices(filter []ble.UUID) ([]*ble.Service, error) {
+	var services []*ble.Service
+	for _, svc := range c.device.profile.Services {
+		if matchesFilter(filter, svc.UUID) {
+			services = append(services, &ble.Service{UUID: svc.UUID, Handle: svc.Handle, EndHandle: svc.EndHandle})
+		}
+	}
+	return services, nil
+}
+
+// DiscoverIncludedServices returns no services; the simulated profile has no included services
+func (c *simClient) DiscoverIncludedServices([]ble.UUID, *ble.Service) ([]*ble.Service, error) {
+	return nil, nil
+}
+
+// DiscoverCharacteristics returns the characteristics of service s matching filter (all when empty)
+func (c *simClient) DiscoverCharacteristics(filter []ble.UUID, s *ble.Service) ([]*ble.Characteristic, error) {
+	for _, svc := range c.device.profile.Services {
+		if svc.Handle != s.Handle {
+			continue
+		}
+		var chars []*ble.Characteristic
+		for _, char := range svc.Characteristics {
+			if matchesFilter(filter, char.UUID) {
+				chars = append(chars, char)
+			}
+		}
+		return chars, nil
+	}
+	return nil, fmt.Errorf("service %s not found", s.UUID)
+}
+
+// DiscoverDescriptors returns the descriptors of char matching filter (all when empty)
+func (c *simClient) DiscoverDescriptors(filter []ble.UUID, char *ble.Characteristic) ([]*ble.Descriptor, error) {
+	var descs []*ble.Descriptor
+	for _, desc := range char.Descriptors {
+		if matchesFilter(filter, desc.UUID) {
+			descs = append(descs, desc)
+		}
+	}
+	return descs, nil
+}
+
+// ReadCharacteristic returns the current value of a readable characteristic
+func (c *simClient) ReadCharacteristic(char *ble.Characteristic) ([]byte, error) {
+	if char.Property&ble.CharRead == 0 {
+		return nil, fmt.Errorf("characteristic does not support read")
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return append([]byte(nil), char.Value...), nil
+}
+
+// ReadLongCharacteristic returns the full value; the simulated link has no MTU limit
+func (c *simClient) ReadLongCharacteristic(char *ble.Characteristic) ([]byte, error) {
+	return c.ReadCharacteristic(char)
+}
+
+// WriteCharacteristic stores the value of a writable characteristic
+func (c *simClient) WriteCharacteristic(char *ble.Characteristic, value []byte, noRsp bool) error {
+	if char.Property&(ble.CharWrite|ble.CharWriteNR) == 0 {
+		return fmt.Errorf("characteristic does not support write")
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	char.Value = append([]byte(nil), value...)
+	return nil
+}
+
+// ReadDescriptor returns the current descriptor value
+func (c *simClient) ReadDescriptor(desc *ble.Descriptor) ([]byte, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return append([]byte(nil), desc.Value...), nil
+}
+
+// WriteDescriptor stores the descriptor value
+func (c *simClient) WriteDescriptor(desc *ble.Descriptor, value []byte) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	desc.Value = append([]byte(nil), value...)
+	return nil
+}
+
+// ReadRSSI returns the RSSI of the simulated link, fixed by the profile
+func (c *simClient) ReadRSSI() int {
+	return c.device.rssi
+}
+
+// ExchangeMTU accepts the requested MTU; the simulated link has no MTU limit
+func (c *simClient) ExchangeMTU(rxMTU int) (int, error) {
+	return rxMTU, nil
+}
+
+// Subscribe registers a notification handler and starts the notification script on first use
+func (c *simClient) Subscribe(char *ble.Characteristic, ind bool, h ble.NotificationHandler) error {
+	required := ble.CharNotify
+	if ind {
+		required = ble.CharIndicate
+	}
+	if char.Property&required == 0 {
+		return fmt.Errorf("characteristic does not support subscription")
+	}
+
+	c.mu.Lock()
+	c.handlers[char] = h
+	c.mu.Unlock()
+
+	c.scriptOnce.Do(func() {
+		if len(c.device.script) > 0 {
+			go c.runScript()
+		}
+	})
+	return nil
+}
+
+// Unsubscribe removes the notification handler
+func (c *simClient) Unsubscribe(char *ble.Characteristic, _ bool) error {
+	c.mu.Lock()
+	delete(c.handlers, char)
+	c.mu.Unlock()
+	return nil
+}
+
+// ClearSubscriptions removes all notification handlers
+func (c *simClient) ClearSubscriptions() error {
+	c.mu.Lock()
+	clear(c.handlers)
+	c.mu.Unlock()
+	return nil
+}
+
+// CancelConnection disconnects the simulated peripheral and stops the notification script
+func (c *simClient) CancelConnection() error {
+	c.closeOnce.Do(func() { close(c.disconnected) })
+	return nil
+}
+
+// Disconnected returns a channel closed when the connection is canceled
+func (c *simClient) Disconnected() <-chan struct{} {
+	return c.disconnected
+}
+
+// runScript emits scripted notifications until the script ends or the connection is canceled
+func (c *simClient) runScript() {
+	for {
+		for _, step := range c.device.script {
+			select {
+			case <-time.After(step.delay):
+			case <-c.disconnected:
+				return
+			}
+
+			c.mu.Lock()
+			step.char.Value = append([]byte(nil), step.value...)
+			h := c.handlers[step.char]
+			c.mu.Unlock()
+
+			if h != nil {
+				h(append([]byte(nil), step.value...))
+			}
+		}
+		if !c.device.repeat {
+			return
+		}
+	}
+}
+
+// matchesFilter reports whether u passes a discovery filter; an empty filter matches everything
+func matchesFilter(filter []ble.UUID, u ble.UUID) bool {
+	return len(filter) == 0 || ble.Contains(filter, u)
+}
@@ -0,0 +1,236 @@
+package simulator
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/go-ble/ble"
+	"github.com/sirupsen/logrus"
+	"github.com/srg/blim/internal/device"
+	goble "github.com/srg/blim/internal/device/go-ble"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const heartRateProfile = `{
+	"services": [
+		{
+			"uuid": "180D",
+			"characteristics": [
+				{ "uuid": "2A37", "properties": "notify", "value": [0, 60] },
+				{ "uuid": "2A39", "properties": "read,write", "value": [0],
+				  "descriptors": [ { "uuid": "2901", "value": [67, 80] } ] }
+			]
+		}
+	],
+	"script": [
+		{ "delay_ms": 10, "service": "180D", "char": "2A37", "value": [0, 72] },
+		{ "delay_ms": 10, "service": "180d", "char": "2a37", "value": [0, 75] }
+	]
+}`
+
+// GOAL: Verify invalid device descriptions are rejected with a descriptive error
+//
+// TEST SCENARIO: Parse malformed or inconsistent JSON → error returned → message names the problem
+func TestParseProfile_Invalid(t *testing.T) {
+	tests := []struct {
+		name    string
+		json    string
+		wantErr string
+	}{
+		{name: "malformed JSON", json: `{"services": [`, wantErr: "failed to parse JSON"},
+		{name: "no services", json: `{"services": []}`, wantErr: "no services defined"},
+		{name: "invalid service UUID", json: `{"services": [{"uuid": "xyz"}]}`, wantErr: `invalid service UUID "xyz"`},
+		{
+			name:    "unknown property",
+			json:    `{"services": [{"uuid": "180D", "characteristics": [{"uuid": "2A37", "properties": "read,teleport"}]}]}`,
+			wantErr: `unknown property "teleport"`,
+		},
+		{
+			name:    "script references unknown characteristic",
+			json:    `{"services": [{"uuid": "180D", "characteristics": [{"uuid": "2A37"}]}], "script": [{"service": "180D", "char": "2A38", "value": [1]}]}`,
+			wantErr: "script step 1: characteristic 2A38 not found in service 180D",
+		},
+		{
+			name:    "script on non-notifiable characteristic",
+			json:    `{"services": [{"uuid": "180D", "characteristics": [{"uuid": "2A37", "properties": "read"}]}], "script": [{"service": "180D", "char": "2A37", "value": [1]}]}`,
+			wantErr: "does not support notify or indicate",
+		},
+		{
+			name:    "repeated script without delay",
+			json:    `{"services": [{"uuid": "180D", "characteristics": [{"uuid": "2A37"}]}], "script": [{"service": "180D", "char": "2A37", "value": [1]}], "repeat": true}`,
+			wantErr: "non-zero total delay",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ParseProfile([]byte(tt.json))
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.wantErr)
+		})
+	}
+}
+
+// GOAL: Verify the simulated client serves the described GATT profile and scripted notifications
+//
+// TEST SCENARIO: Dial simulated device → discover, read, write → subscribe → scripted notifications delivered in order → disconnect
+func TestSimulatedDevice(t *testing.T) {
+	profile, err := ParseProfile([]byte(heartRateProfile))
+	require.NoError(t, err)
+
+	dev, err := NewDevice(profile)
+	require.NoError(t, err)
+
+	client, err := dev.Dial(context.Background(), ble.NewAddr("00:00:00:00:00:01"))
+	require.NoError(t, err)
+
+	p, err := client.DiscoverProfile(true)
+	require.NoError(t, err)
+	require.Len(t, p.Services, 1)
+	require.Len(t, p.Services[0].Characteristics, 2)
+	hrm, control := p.Services[0].Characteristics[0], p.Services[0].Characteristics[1]
+
+	_, err = client.ReadCharacteristic(hrm)
+	assert.Error(t, err, "notify-only characteristic MUST NOT be readable")
+
+	require.NoError(t, client.WriteCharacteristic(control, []byte{0x05}, false))
+	value, err := client.ReadCharacteristic(control)
+	require.NoError(t, err)
+	assert.Equal(t, []byte{0x05}, value, "read MUST return the written value")
+
+	desc, err := client.ReadDescriptor(control.Descriptors[0])
+	require.NoError(t, err)
+	assert.Equal(t, []byte("CP"), desc)
+
+	received := make(chan []byte, 2)
+	require.NoError(t, client.Subscribe(hrm, false, func(data []byte) { received <- data }))
+
+	for _, want := range [][]byte{{0, 72}, {0, 75}} {
+		select {
+		case got := <-received:
+			assert.Equal(t, want, got)
+		case <-time.After(time.Second):
+			t.Fatalf("scripted notification %v not delivered", want)
+		}
+	}
+
+	require.NoError(t, client.CancelConnection())
+	select {
+	case <-client.Disconnected():
+	default:
+		t.Fatal("Disconnected() MUST be closed after CancelConnection()")
+	}
+}
+
+// GOAL: Verify the simulated device implements the full ble.Client and ble.Device without nil-interface panics
+//
+// TEST SCENARIO: Call the client methods blim does not exercise in the happy path → values or nil errors returned → peripheral-role device operations fail with an error
+func TestSimulatedDevice_FullInterface(t *testing.T) {
+	profile, err := ParseProfile([]byte(heartRateProfile))
+	require.NoError(t, err)
+
+	dev, err := NewDevice(profile)
+	require.NoError(t, err)
+
+	addr := ble.NewAddr("00:00:00:00:00:01")
+	client, err := dev.Dial(context.Background(), addr)
+	require.NoError(t, err)
+	defer client.CancelConnection()
+
+	assert.Equal(t, addr.String(), client.Addr().String())
+	assert.Equal(t, DefaultRSSI, client.ReadRSSI())
+	mtu, err := client.ExchangeMTU(247)
+	require.NoError(t, err)
+	assert.Equal(t, 247, mtu)
+
+	p := client.Profile()
+	control := p.Services[0].Characteristics[1]
+	require.NoError(t, client.WriteDescriptor(control.Descriptors[0], []byte("XY")))
+	desc, err := client.ReadDescriptor(control.Descriptors[0])
+	require.NoError(t, err)
+	assert.Equal(t, []byte("XY"), desc, "descriptor read MUST return the written value")
+
+	included, err := client.DiscoverIncludedServices(nil, p.Services[0])
+	require.NoError(t, err)
+	assert.Empty(t, included)
+	assert.NoError(t, client.ClearSubscriptions())
+
+	assert.Error(t, dev.AddService(&ble.Service{}))
+	assert.Error(t, dev.SetServices(nil))
+	assert.Error(t, dev.RemoveAllServices())
+	assert.Error(t, dev.AdvertiseNameAndServices(context.Background(), "blim"))
+}
+
+// GOAL: Verify step-by-step discovery (used by --no-descriptors and --only-service) serves the simulated profile
+//
+// TEST SCENARIO: Discover services with a filter → only the listed service returned without characteristics → characteristics and descriptors discovered per level
+func TestSimulatedDevice_ScopedDiscovery(t *testing.T) {
+	profile, err := ParseProfile([]byte(`{"services": [
+		{"uuid": "180D", "characteristics": [{"uuid": "2A37", "descriptors": [{"uuid": "2902"}, {"uuid": "2901"}]}]},
+		{"uuid": "180F", "characteristics": [{"uuid": "2A19"}]}
+	]}`))
+	require.NoError(t, err)
+
+	dev, err := NewDevice(profile)
+	require.NoError(t, err)
+	client, err := dev.Dial(context.Background(), ble.NewAddr("00:00:00:00:00:01"))
+	require.NoError(t, err)
+	defer client.CancelConnection()
+
+	services, err := client.DiscoverServices([]ble.UUID{ble.MustParse("180D")})
+	require.NoError(t, err)
+	require.Len(t, services, 1, "MUST return only the listed service")
+	assert.Equal(t, "180d", services[0].UUID.String())
+	assert.Empty(t, services[0].Characteristics, "discovered services MUST come back without characteristics")
+
+	chars, err := client.DiscoverCharacteristics(nil, services[0])
+	require.NoError(t, err)
+	require.Len(t, chars, 1)
+	assert.Equal(t, "2a37", chars[0].UUID.String())
+
+	descs, err := client.DiscoverDescriptors([]ble.UUID{ble.MustParse("2901")}, chars[0])
+	require.NoError(t, err)
+	require.Len(t, descs, 1, "MUST return only the descriptors matching the filter")
+	assert.Equal(t, "2901", descs[0].UUID.String())
+
+	all, err := client.DiscoverServices(nil)
+	require.NoError(t, err)
+	assert.Len(t, all, 2, "an empty filter MUST return every service")
+}
+
+// GOAL: Verify connection RSSI reads report the profile's RSSI, or DefaultRSSI when it is not set
+//
+// TEST SCENARIO: Profile with rssi → connect through the BLE connection → ReadRSSI returns it; profile without rssi → DefaultRSSI
+func TestSimulatedDevice_ReadRSSI(t *testing.T) {
+	tests := []struct {
+		name string
+		json string
+		want int
+	}{
+		{name: "configured", json: `{"services": [{"uuid": "180D"}], "rssi": -42}`, want: -42},
+		{name: "default", json: `{"services": [{"uuid": "180D"}]}`, want: DefaultRSSI},
+	}
+
+	originalFactory := goble.DeviceFactory
+	defer func() { goble.DeviceFactory = originalFactory }()
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			profile, err := ParseProfile([]byte(tt.json))
+			require.NoError(t, err)
+			goble.DeviceFactory = func() (ble.Device, error) { return NewDevice(profile) }
+
+			logger := logrus.New()
+			logger.SetLevel(logrus.ErrorLevel)
+			dev := goble.NewBLEDeviceWithAddress("AA:BB:CC:DD:EE:FF", logger)
+			require.NoError(t, dev.Connect(context.Background(), &device.ConnectOptions{ConnectTimeout: 5 * time.Second}))
+			defer dev.Disconnect()
+
+			rssi, err := dev.GetConnection().ReadRSSI()
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, rssi)
+		})
+	}
+}
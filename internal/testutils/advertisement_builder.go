@@ -20,15 +20,17 @@ import (
 //   - *MockAdvertisement for standalone usage
 //   - Custom parent type when using parent chaining (buildFunc must be set)
 type AdvertisementBuilder[T any] struct {
-	name        string
-	address     string
-	rssi        int
-	services    []string
-	manufData   []byte
-	serviceData map[string][]byte
-	txPower     *int
-	connectable bool
-	logger      *logrus.Logger
+	name         string
+	completeName string
+	shortName    string
+	address      string
+	rssi         int
+	services     []string
+	manufData    []byte
+	serviceData  map[string][]byte
+	txPower      *int
+	connectable  bool
+	logger       *logrus.Logger
 
 	// Track which fields were explicitly set
 	nameSet        bool
@@ -72,6 +74,18 @@ func (b *AdvertisementBuilder[T]) WithName(name string) *AdvertisementBuilder[T]
 	return b
 }
 
+// WithCompleteName sets the Complete Local Name AD field.
+func (b *AdvertisementBuilder[T]) WithCompleteName(name string) *AdvertisementBuilder[T] {
+	b.completeName = name
+	return b
+}
+
+// WithShortName sets the Shortened Local Name AD field.
+func (b *AdvertisementBuilder[T]) WithShortName(name string) *AdvertisementBuilder[T] {
+	b.shortName = name
+	return b
+}
+
 // WithAddress sets the device address for the advertisement.
 func (b *AdvertisementBuilder[T]) WithAddress(addr string) *AdvertisementBuilder[T] {
 	b.address = addr
@@ -144,6 +158,8 @@ func (b *AdvertisementBuilder[T]) FromJSON(jsonStrFmt string, args ...interface{
 	// Then unmarshal into typed struct
 	var data struct {
 		Name             *string           `json:"name"`
+		CompleteName     string            `json:"completeName"`
+		ShortName        string            `json:"shortName"`
 		Address          *string           `json:"address"`
 		RSSI             *int              `json:"rssi"`
 		Services         []string          `json:"services"`
@@ -166,6 +182,8 @@ func (b *AdvertisementBuilder[T]) FromJSON(jsonStrFmt string, args ...interface{
 		}
 		b.nameSet = true
 	}
+	b.completeName = data.CompleteName
+	b.shortName = data.ShortName
 	if _, exists := fieldPresence["address"]; exists {
 		if data.Address != nil {
 			b.address = *data.Address
@@ -274,6 +292,10 @@ func (b *AdvertisementBuilder[T]) Build() T {
 	adv.On("OverflowService").Return([]string{}).Maybe()
 	adv.On("SolicitedService").Return([]string{}).Maybe()
 
+	// The individual name AD fields are optional; unset ones report "" like a device advertising only one of them
+	adv.On("CompleteLocalName").Return(b.completeName).Maybe()
+	adv.On("ShortLocalName").Return(b.shortName).Maybe()
+
 	// Note: Appearance is NOT part of advertisements
 	// It is read from the GAP service (0x1800) during connection, not from advertisement packets
 
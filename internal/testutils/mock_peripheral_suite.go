@@ -242,6 +242,7 @@ type PeripheralDataSimulatorBuilder struct {
 	serviceData     *orderedmap.OrderedMap[string, *orderedmap.OrderedMap[string, [][]byte]]
 	logf            func(format string, args ...any)
 	connProvider    func() device.Connection // Optional: enables Simulate(verbose) without explicit conn
+	sentAt          time.Time                // When the last SimulateFor started sending
 }
 
 // ServiceDataSimulatorBuilder builds notification simulation for a specific service.
@@ -333,10 +334,17 @@ func (s *ServiceDataSimulatorBuilder) Simulate(verbose bool) *ServiceDataSimulat
 	return s
 }
 
+// SentAt returns when the last SimulateFor started sending notifications (zero if it was never called).
+// Used as the reference point for notification latency assertions.
+func (b *PeripheralDataSimulatorBuilder) SentAt() time.Time {
+	return b.sentAt
+}
+
 // SimulateFor executes all configured characteristic data simulations using the provided connection.
 // It sends notifications index-by-index across all characteristics (round-robin style) in insertion order.
 func (b *PeripheralDataSimulatorBuilder) SimulateFor(conn device.Connection, verbose bool) (*PeripheralDataSimulatorBuilder, error) {
 	b.suite.NotNil(conn, "Connection should be available")
+	b.sentAt = time.Now()
 
 	bleConn, ok := conn.(*goble.BLEConnection)
 	if !ok {
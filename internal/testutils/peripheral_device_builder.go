@@ -6,23 +6,17 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
-	"strings"
 	"testing"
 	"time"
 
 	blelib "github.com/go-ble/ble"
 	"github.com/srg/blim/internal/device"
+	"github.com/srg/blim/internal/gattprofile"
 	blemocks "github.com/srg/blim/internal/testutils/mocks/goble"
 	"github.com/stretchr/testify/mock"
 	"gopkg.in/yaml.v3"
 )
 
-// createMockUUID creates a ble.UUID from a string for testing
-func createMockUUID(name string) blelib.UUID {
-	// Parse as proper UUID - will panic if invalid, which is fine for tests
-	return blelib.MustParse(name)
-}
-
 // DescriptorReadBehavior specifies error behavior when reading a descriptor
 type DescriptorReadBehavior int
 
@@ -60,18 +54,21 @@ func (d *DescriptorReadBehavior) UnmarshalYAML(value *yaml.Node) error {
 type DescriptorConfig struct {
 	UUID              string                 `json:"uuid" yaml:"uuid"`
 	Value             []byte                 `json:"value,omitempty" yaml:"value,omitempty"`
-	ReadErrorBehavior DescriptorReadBehavior `json:"-" yaml:"read_error,omitempty"` // Maps YAML read_error field to enum
+	ReadErrorBehavior DescriptorReadBehavior `json:"-" yaml:"read_error,omitempty"`  // Maps YAML read_error field to enum
+	ParseError        string                 `json:"-" yaml:"parse_error,omitempty"` // Expected parse error of the value (asserted by the Lua API suite)
 }
 
 // CharacteristicConfig represents a BLE characteristic configuration for mocking
 type CharacteristicConfig struct {
-	UUID         string             `json:"uuid" yaml:"uuid"`
-	Properties   string             `json:"properties,omitempty" yaml:"properties,omitempty"` // e.g., "read,write,notify"
-	NoProperties bool               `json:"-" yaml:"-"`                                       // If true, characteristic has no properties (property flags = 0)
-	Value        []byte             `json:"value,omitempty" yaml:"value,omitempty"`
-	Descriptors  []DescriptorConfig `json:"descriptors,omitempty" yaml:"descriptors,omitempty"`
-	ReadDelay    time.Duration      `json:"-" yaml:"-"` // Delay before returning read response (for timeout testing)
-	WriteDelay   time.Duration      `json:"-" yaml:"-"` // Delay before returning write response (for timeout testing)
+	UUID         string                        `json:"uuid" yaml:"uuid"`
+	Properties   string                        `json:"properties,omitempty" yaml:"properties,omitempty"` // e.g., "read,write,notify"
+	NoProperties bool                          `json:"-" yaml:"-"`                                       // If true, characteristic has no properties (property flags = 0)
+	Value        []byte                        `json:"value,omitempty" yaml:"value,omitempty"`
+	Descriptors  []DescriptorConfig            `json:"descriptors,omitempty" yaml:"descriptors,omitempty"`
+	ReadDelay    time.Duration                 `json:"-" yaml:"-"` // Delay before returning read response (for timeout testing)
+	WriteDelay   time.Duration                 `json:"-" yaml:"-"` // Delay before returning write response (for timeout testing)
+	ReadMTU      int                           `json:"-" yaml:"-"` // If set, single reads return at most ReadMTU-1 bytes; the full value needs a long read
+	OnWrite      func(data []byte, noRsp bool) `json:"-" yaml:"-"` // If set, called with every successful write (for asserting written data)
 }
 
 // ServiceConfig represents a BLE service configuration for mocking
@@ -184,6 +181,9 @@ type PeripheralDeviceBuilder struct {
 	profile            DeviceProfileConfig
 	scanAdvertisements []device.Advertisement
 	scanDelayMs        int           // Delay in milliseconds before emitting each advertisement during scan
+	rssi               int           // RSSI reported by ReadRSSI of the connected client
+	mtu                int           // ATT MTU reported by the connection of the connected client
+	discoveryDelay     time.Duration // Delay before DiscoverProfile returns the profile
 	t                  *testing.T    // Testing instance for automatic cleanup registration
 	disconnectChan     chan struct{} // Disconnect channel for graceful disconnect testing
 }
@@ -195,10 +195,46 @@ func NewPeripheralDeviceBuilder(t *testing.T) *PeripheralDeviceBuilder {
 		profile: DeviceProfileConfig{
 			Services: []ServiceConfig{},
 		},
-		t: t,
+		rssi: DefaultMockRSSI,
+		mtu:  DefaultMockMTU,
+		t:    t,
 	}
 }
 
+// DefaultMockRSSI is the RSSI reported by ReadRSSI of a connected mock peripheral
+const DefaultMockRSSI = -60
+
+// WithRSSI sets the RSSI reported by ReadRSSI while connected
+func (b *PeripheralDeviceBuilder) WithRSSI(rssi int) *PeripheralDeviceBuilder {
+	b.rssi = rssi
+	return b
+}
+
+// DefaultMockMTU is the ATT MTU reported by the connection of a mock peripheral (the LE minimum)
+const DefaultMockMTU = 23
+
+// WithMTU sets the ATT MTU reported by the connection while connected
+func (b *PeripheralDeviceBuilder) WithMTU(mtu int) *PeripheralDeviceBuilder {
+	b.mtu = mtu
+	return b
+}
+
+// mockConn is the ble.Conn of a connected mock client; only TxMTU is implemented
+type mockConn struct {
+	blelib.Conn
+	txMTU int
+}
+
+func (c *mockConn) TxMTU() int {
+	return c.txMTU
+}
+
+// WithDiscoveryDelay delays profile discovery after connecting, to exercise discovery timeouts
+func (b *PeripheralDeviceBuilder) WithDiscoveryDelay(delay time.Duration) *PeripheralDeviceBuilder {
+	b.discoveryDelay = delay
+	return b
+}
+
 // WithService adds a service to the device profile
 func (b *PeripheralDeviceBuilder) WithService(uuid string) *PeripheralDeviceBuilder {
 	b.profile.Services = append(b.profile.Services, ServiceConfig{
@@ -218,6 +254,14 @@ func WithReadDelay(delay time.Duration) CharacteristicOption {
 	}
 }
 
+// WithReadMTU limits single reads to mtu-1 bytes like an ATT Read Response,
+// so the full value is only returned by a long (Read Blob) read
+func WithReadMTU(mtu int) CharacteristicOption {
+	return func(c *CharacteristicConfig) {
+		c.ReadMTU = mtu
+	}
+}
+
 // WithWriteDelay sets the writing delay for timeout testing
 func WithWriteDelay(delay time.Duration) CharacteristicOption {
 	return func(c *CharacteristicConfig) {
@@ -225,6 +269,13 @@ func WithWriteDelay(delay time.Duration) CharacteristicOption {
 	}
 }
 
+// WithWriteHook registers a function called with the data of every write to the characteristic
+func WithWriteHook(fn func(data []byte, noRsp bool)) CharacteristicOption {
+	return func(c *CharacteristicConfig) {
+		c.OnWrite = fn
+	}
+}
+
 // WithCharacteristic adds a characteristic to the last added service
 func (b *PeripheralDeviceBuilder) WithCharacteristic(uuid, properties string, value []byte, opts ...CharacteristicOption) *PeripheralDeviceBuilder {
 	if len(b.profile.Services) == 0 {
@@ -317,7 +368,8 @@ func (b *PeripheralDeviceBuilder) WithDescriptorReadError(uuid string) *Peripher
 	return b.addDescriptor(DescriptorConfig{UUID: uuid, ReadErrorBehavior: DescriptorReadError})
 }
 
-// FromJSON fills the device profile from JSON
+// FromJSON fills the device profile from JSON in the peripheral description format shared with --simulate
+// (see gattprofile.DeviceProfileConfig)
 func (b *PeripheralDeviceBuilder) FromJSON(jsonStrFmt string, args ...interface{}) *PeripheralDeviceBuilder {
 	jsonStr := fmt.Sprintf(jsonStrFmt, args...)
 
@@ -409,43 +461,36 @@ func remapAggregateIndices(aggregateValue []byte, descriptorHandles []uint16) []
 	return result
 }
 
-// parseCharacteristicProperties converts the property string to BLE.Property flags
-func parseCharacteristicProperties(props string) blelib.Property {
-	if props == "" {
-		return blelib.CharRead | blelib.CharWrite | blelib.CharNotify // default
-	}
-
-	var property blelib.Property
-
-	// Split by comma, trim whitespace, and parse each property individually
-	parts := strings.Split(props, ",")
-	for _, part := range parts {
-		part = strings.TrimSpace(part)
-		switch part {
-		case "read":
-			property |= blelib.CharRead
-		case "write":
-			property |= blelib.CharWrite
-		case "write-without-response":
-			property |= blelib.CharWriteNR
-		case "notify":
-			property |= blelib.CharNotify
-		case "indicate":
-			property |= blelib.CharIndicate
-		case "authenticated_signed_writes":
-			property |= blelib.CharSignedWrite
-		default:
-			// FAIL FAST on unknown properties to catch configuration errors
-			panic(fmt.Sprintf("parseCharacteristicProperties: unknown property '%s' in '%s'", part, props))
+// sharedProfileConfig converts the mock configuration to the shared peripheral description
+func (b *PeripheralDeviceBuilder) sharedProfileConfig() gattprofile.DeviceProfileConfig {
+	var config gattprofile.DeviceProfileConfig
+	for _, svcConfig := range b.profile.Services {
+		svc := gattprofile.ServiceConfig{UUID: svcConfig.UUID}
+		for _, charConfig := range svcConfig.Characteristics {
+			char := gattprofile.CharacteristicConfig{
+				UUID:       charConfig.UUID,
+				Properties: charConfig.Properties,
+				Value:      charConfig.Value,
+			}
+			for _, descConfig := range charConfig.Descriptors {
+				char.Descriptors = append(char.Descriptors, gattprofile.DescriptorConfig{UUID: descConfig.UUID, Value: descConfig.Value})
+			}
+			svc.Characteristics = append(svc.Characteristics, char)
 		}
+		config.Services = append(config.Services, svc)
 	}
+	return config
+}
 
-	// If no valid properties were parsed, return default
-	if property == 0 {
-		return blelib.CharRead | blelib.CharWrite | blelib.CharNotify
-	}
+// characteristicAt matches a characteristic by value handle, like an ATT read does, so reads through an
+// equivalent characteristic (e.g., one restored from the GATT cache) hit the same expectation
+func characteristicAt(char *blelib.Characteristic) any {
+	return mock.MatchedBy(func(c *blelib.Characteristic) bool { return c.ValueHandle == char.ValueHandle })
+}
 
-	return property
+// descriptorAt matches a descriptor by handle (see characteristicAt)
+func descriptorAt(desc *blelib.Descriptor) any {
+	return mock.MatchedBy(func(d *blelib.Descriptor) bool { return d.Handle == desc.Handle })
 }
 
 // Build creates a mocked ble.Device with the configured profile.
@@ -454,73 +499,58 @@ func (b *PeripheralDeviceBuilder) Build() blelib.Device {
 	mockDevice := &blemocks.MockDevice{}
 	mockClient := &blemocks.MockClient{}
 
-	// Calculate sequential ATT handles for the entire profile
-	// Handles increment: service → characteristic → descriptors
-	currentHandle := uint16(0x0001)
-
-	// Create the BLE profile with services and characteristics
-	var bleServices []*blelib.Service
-	for _, svcConfig := range b.profile.Services {
-		bleService := &blelib.Service{
-			UUID: createMockUUID(svcConfig.UUID),
-		}
-		currentHandle++ // Service consumes one handle
-
-		var bleCharacteristics []*blelib.Characteristic
-		for _, charConfig := range svcConfig.Characteristics {
-			currentHandle++ // Characteristic consumes one handle
+	// Build the GATT profile with the builder shared with the simulated device (--simulate)
+	mockProfile, err := gattprofile.Build(b.sharedProfileConfig())
+	if err != nil {
+		panic(fmt.Sprintf("PeripheralDeviceBuilder.Build: %v", err))
+	}
+	bleServices := mockProfile.Services
 
-			// Track descriptor handles for this characteristic
-			var descriptorHandles []uint16
-			var bleDescriptors []*blelib.Descriptor
-			for _, descConfig := range charConfig.Descriptors {
-				descriptorHandles = append(descriptorHandles, currentHandle)
-				bleDesc := &blelib.Descriptor{
-					UUID:   createMockUUID(descConfig.UUID),
-					Value:  descConfig.Value,
-					Handle: currentHandle,
-				}
-				bleDescriptors = append(bleDescriptors, bleDesc)
-				currentHandle++ // Descriptor consumes one handle
+	// Apply the mock-only configuration on top of the shared profile
+	aggregateUUID := blelib.UUID16(0x2905)
+	for svcIdx, svc := range bleServices {
+		for charIdx, char := range svc.Characteristics {
+			if b.profile.Services[svcIdx].Characteristics[charIdx].NoProperties {
+				char.Property = 0 // No properties (macOS hidden until paired)
 			}
 
 			// Fix up Aggregate Format descriptors: replace placeholder indices with actual handles
-			aggregateUUID := createMockUUID("2905")
-			for i, desc := range bleDescriptors {
-				if desc.UUID.String() == aggregateUUID.String() { // 0x2905 Aggregate Format
-					bleDescriptors[i].Value = remapAggregateIndices(desc.Value, descriptorHandles)
-				}
-			}
-
-			// Determine property flags: use 0 if NoProperties flag is set, otherwise parse Properties string
-			var property blelib.Property
-			if charConfig.NoProperties {
-				property = 0 // No properties (macOS hidden until paired)
-			} else {
-				property = parseCharacteristicProperties(charConfig.Properties)
+			descriptorHandles := make([]uint16, len(char.Descriptors))
+			for i, desc := range char.Descriptors {
+				descriptorHandles[i] = desc.Handle
 			}
-
-			bleChar := &blelib.Characteristic{
-				UUID:        createMockUUID(charConfig.UUID),
-				Property:    property,
-				Value:       charConfig.Value,
-				Descriptors: bleDescriptors,
+			for _, desc := range char.Descriptors {
+				if desc.UUID.Equal(aggregateUUID) {
+					desc.Value = remapAggregateIndices(desc.Value, descriptorHandles)
+				}
 			}
-			bleCharacteristics = append(bleCharacteristics, bleChar)
 		}
-		bleService.Characteristics = bleCharacteristics
-		bleServices = append(bleServices, bleService)
-	}
-
-	// Create the profile that will be returned by DiscoverProfile
-	mockProfile := &blelib.Profile{
-		Services: bleServices,
 	}
 
 	// Set up mock expectations
 	mockDevice.On("Dial", mock.Anything, mock.Anything).Return(mockClient, nil)
-	mockClient.On("DiscoverProfile", true).Return(mockProfile, nil)
+	discoverCall := mockClient.On("DiscoverProfile", true).Return(mockProfile, nil)
+	if b.discoveryDelay > 0 {
+		discoverCall.After(b.discoveryDelay)
+	}
+
+	// Step-by-step discovery (SkipDescriptors, DiscoverOnlyListed): services come back bare, characteristics
+	// and descriptors are discovered per service/characteristic, sharing the profile's objects
+	discoveredServices := make([]*blelib.Service, 0, len(bleServices))
+	for _, svc := range bleServices {
+		discoveredServices = append(discoveredServices, &blelib.Service{UUID: svc.UUID, Handle: svc.Handle, EndHandle: svc.EndHandle})
+		svcUUID := svc.UUID
+		mockClient.On("DiscoverCharacteristics", mock.Anything, mock.MatchedBy(func(s *blelib.Service) bool {
+			return s.UUID.Equal(svcUUID)
+		})).Return(svc.Characteristics, nil)
+		for _, char := range svc.Characteristics {
+			mockClient.On("DiscoverDescriptors", mock.Anything, char).Return(char.Descriptors, nil)
+		}
+	}
+	mockClient.On("DiscoverServices", mock.Anything).Return(discoveredServices, nil)
 	mockClient.On("CancelConnection").Return(nil)
+	mockClient.On("ReadRSSI").Return(b.rssi)
+	mockClient.On("Conn").Return(&mockConn{txMTU: b.mtu})
 
 	// Set up disconnect channel expectation for graceful disconnect handling.
 	// Each Build() creates a new disconnect channel to support the monitoring goroutine
@@ -545,37 +575,50 @@ func (b *PeripheralDeviceBuilder) Build() blelib.Device {
 		for charIdx, char := range svc.Characteristics {
 			charConfig := svcConfig.Characteristics[charIdx]
 
-			mockClient.On("Subscribe", char, false, mock.Anything).Return(nil)
-			mockClient.On("Subscribe", char, true, mock.Anything).Return(nil) // Indicate mode
-			mockClient.On("Unsubscribe", char, false).Return(nil)
-			mockClient.On("Unsubscribe", char, true).Return(nil)
+			mockClient.On("Subscribe", characteristicAt(char), false, mock.Anything).Return(nil)
+			mockClient.On("Subscribe", characteristicAt(char), true, mock.Anything).Return(nil) // Indicate mode
+			mockClient.On("Unsubscribe", characteristicAt(char), false).Return(nil)
+			mockClient.On("Unsubscribe", characteristicAt(char), true).Return(nil)
 
 			// Add read expectations - return value only if characteristic supports reading
 			if char.Property&blelib.CharRead != 0 {
+				readValue := char.Value
+				if charConfig.ReadMTU > 1 && len(readValue) > charConfig.ReadMTU-1 {
+					readValue = readValue[:charConfig.ReadMTU-1] // Truncated like a single ATT Read Response
+				}
 				if charConfig.ReadDelay > 0 {
 					// Add delay for timeout testing
-					mockClient.On("ReadCharacteristic", char).Run(func(args mock.Arguments) {
+					mockClient.On("ReadCharacteristic", characteristicAt(char)).Run(func(args mock.Arguments) {
+						time.Sleep(charConfig.ReadDelay)
+					}).Return(readValue, nil)
+					mockClient.On("ReadLongCharacteristic", characteristicAt(char)).Run(func(args mock.Arguments) {
 						time.Sleep(charConfig.ReadDelay)
 					}).Return(char.Value, nil)
 				} else {
-					mockClient.On("ReadCharacteristic", char).Return(char.Value, nil)
+					mockClient.On("ReadCharacteristic", characteristicAt(char)).Return(readValue, nil)
+					mockClient.On("ReadLongCharacteristic", characteristicAt(char)).Return(char.Value, nil)
 				}
 			} else {
-				mockClient.On("ReadCharacteristic", char).Return(nil, fmt.Errorf("characteristic does not support read"))
+				mockClient.On("ReadCharacteristic", characteristicAt(char)).Return(nil, fmt.Errorf("characteristic does not support read"))
+				mockClient.On("ReadLongCharacteristic", characteristicAt(char)).Return(nil, fmt.Errorf("characteristic does not support read"))
 			}
 
 			// Add write expectations - accept writes if characteristic supports writing
 			if char.Property&blelib.CharWrite != 0 || char.Property&blelib.CharWriteNR != 0 {
-				if charConfig.WriteDelay > 0 {
-					// Add delay for timeout testing
-					mockClient.On("WriteCharacteristic", char, mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
-						time.Sleep(charConfig.WriteDelay)
+				writeDelay, onWrite := charConfig.WriteDelay, charConfig.OnWrite
+				if writeDelay > 0 || onWrite != nil {
+					mockClient.On("WriteCharacteristic", characteristicAt(char), mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+						// Add delay for timeout testing
+						time.Sleep(writeDelay)
+						if onWrite != nil {
+							onWrite(append([]byte(nil), args.Get(1).([]byte)...), args.Bool(2))
+						}
 					}).Return(nil)
 				} else {
-					mockClient.On("WriteCharacteristic", char, mock.Anything, mock.Anything).Return(nil)
+					mockClient.On("WriteCharacteristic", characteristicAt(char), mock.Anything, mock.Anything).Return(nil)
 				}
 			} else {
-				mockClient.On("WriteCharacteristic", char, mock.Anything, mock.Anything).Return(fmt.Errorf("characteristic does not support write"))
+				mockClient.On("WriteCharacteristic", characteristicAt(char), mock.Anything, mock.Anything).Return(fmt.Errorf("characteristic does not support write"))
 			}
 
 			// Add descriptor read expectations based on ReadErrorBehavior
@@ -584,16 +627,16 @@ func (b *PeripheralDeviceBuilder) Build() blelib.Device {
 				switch descConfig.ReadErrorBehavior {
 				case DescriptorReadTimeout:
 					// Timeout: sleep for 10 seconds then panic if timeout wasn't handled properly
-					mockClient.On("ReadDescriptor", desc).Run(func(args mock.Arguments) {
+					mockClient.On("ReadDescriptor", descriptorAt(desc)).Run(func(args mock.Arguments) {
 						time.Sleep(10 * time.Second)
 						panic("BUG: Descriptor read timeout was not handled! The code should have timed out before this panic.")
 					}).Return(nil, fmt.Errorf("timeout"))
 				case DescriptorReadError:
 					// Error: return an error
-					mockClient.On("ReadDescriptor", desc).Return(nil, fmt.Errorf("permission denied"))
+					mockClient.On("ReadDescriptor", descriptorAt(desc)).Return(nil, fmt.Errorf("permission denied"))
 				default:
 					// Normal: return the value
-					mockClient.On("ReadDescriptor", desc).Return(desc.Value, nil)
+					mockClient.On("ReadDescriptor", descriptorAt(desc)).Return(desc.Value, nil)
 				}
 			}
 		}
@@ -0,0 +1,34 @@
+package scanner
+
+import "sync"
+
+// DefaultRSSISmoothing is the default weight of the newest sample in the RSSI moving average
+const DefaultRSSISmoothing = 0.2
+
+// rssiAverage is an exponential moving average of the RSSI reported by one advertiser.
+// The first sample seeds the average; each further sample moves it by alpha towards the new value.
+type rssiAverage struct {
+	mu      sync.Mutex
+	value   float64
+	samples int
+}
+
+// add folds an RSSI sample into the average
+func (a *rssiAverage) add(rssi int, alpha float64) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if a.samples == 0 {
+		a.value = float64(rssi)
+	} else {
+		a.value = alpha*float64(rssi) + (1-alpha)*a.value
+	}
+	a.samples++
+}
+
+// get returns the current average and the number of samples it is based on
+func (a *rssiAverage) get() (float64, int) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return a.value, a.samples
+}
@@ -34,6 +34,11 @@ type DeviceEntry struct {
 	Device   device.DeviceInfo
 	device   device.Device
 	LastSeen time.Time
+
+	// RSSI moving average (only populated with ScanOptions.AverageRSSI)
+	AvgRSSI float64
+	Samples int
+	rssiAvg *rssiAverage
 }
 
 // Scanner handles BLE device discovery
@@ -53,6 +58,13 @@ type ScanOptions struct {
 	ServiceUUIDs    []string
 	AllowList       []string
 	BlockList       []string
+
+	// AverageRSSI maintains an exponential moving average of RSSI per address over the scan
+	// (reported in DeviceEntry.AvgRSSI). Every advertisement packet is processed, including duplicates.
+	AverageRSSI bool
+	// RSSISmoothing is the weight of the newest sample in the average (0 < RSSISmoothing <= 1).
+	// Zero selects DefaultRSSISmoothing.
+	RSSISmoothing float64
 }
 
 // DefaultScanOptions returns default scanning options
@@ -102,7 +114,8 @@ func (s *Scanner) Scan(ctx context.Context, opts *ScanOptions, progressCallback
 		s.scanOptions = nil
 	}()
 
-	err = s.scanDevice.Scan(ctx, opts.DuplicateFilter, s.handleAdvertisement)
+	// RSSI averaging needs every advertisement packet, including duplicates
+	err = s.scanDevice.Scan(ctx, opts.DuplicateFilter || opts.AverageRSSI, s.handleAdvertisement)
 	if err != nil && !errors.Is(err, context.Canceled) && !errors.Is(err, context.DeadlineExceeded) {
 		return nil, fmt.Errorf("scan failed: %w", err)
 	}
@@ -114,11 +127,15 @@ func (s *Scanner) Scan(ctx context.Context, opts *ScanOptions, progressCallback
 
 	devices := make(map[string]DeviceEntry, s.devices.Len())
 	s.devices.Range(func(key string, value DeviceEntry) bool {
-		devices[key] = DeviceEntry{
+		entry := DeviceEntry{
 			Device:   value.device,
 			device:   value.device,
 			LastSeen: value.LastSeen,
 		}
+		if value.rssiAvg != nil {
+			entry.AvgRSSI, entry.Samples = value.rssiAvg.get()
+		}
+		devices[key] = entry
 		return true
 	})
 
@@ -139,10 +156,21 @@ func (s *Scanner) handleAdvertisement(adv device.Advertisement) {
 			device:   devicefactory.NewDeviceFromAdvertisement(adv, s.logger),
 			LastSeen: time.Now(),
 		}
+		if s.scanOptions.AverageRSSI {
+			entry.rssiAvg = &rssiAverage{}
+		}
 
 		e, existing = s.devices.GetOrInsert(deviceID, entry)
 	}
 
+	if e.rssiAvg != nil {
+		alpha := s.scanOptions.RSSISmoothing
+		if alpha <= 0 {
+			alpha = DefaultRSSISmoothing
+		}
+		e.rssiAvg.add(adv.RSSI(), alpha)
+	}
+
 	event := DeviceEvent{
 		DeviceInfo: e.device,
 		Timestamp:  e.LastSeen,
@@ -188,24 +216,8 @@ func (s *Scanner) shouldIncludeDevice(adv device.Advertisement, opts *ScanOption
 		}
 	}
 
-	if len(opts.ServiceUUIDs) > 0 {
-		hasRequired := false
-		for _, required := range opts.ServiceUUIDs {
-			requiredNorm := device.NormalizeUUID(required)
-			for _, advUUID := range adv.Services() {
-				advNorm := device.NormalizeUUID(advUUID)
-				if requiredNorm == advNorm {
-					hasRequired = true
-					break
-				}
-			}
-			if hasRequired {
-				break
-			}
-		}
-		if !hasRequired {
-			return false
-		}
+	if len(opts.ServiceUUIDs) > 0 && !device.ContainsAnyUUID(adv.Services(), opts.ServiceUUIDs) {
+		return false
 	}
 
 	return true
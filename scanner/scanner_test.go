@@ -253,6 +253,45 @@ func (suite *ScannerTestSuite) TestScannerFiltering() {
 	}
 }
 
+func (suite *ScannerTestSuite) TestScannerAverageRSSI() {
+	// GOAL: Verify RSSI averaging folds every advertisement of an address into an exponential moving average
+	//
+	// TEST SCENARIO: Device 1 advertises twice (-45, -65) → averaged with smoothing 0.5 → -55 over 2 samples, others 1 sample
+
+	repeat := testutils.NewAdvertisementBuilder().
+		WithAddress("AA:BB:CC:DD:EE:FF").
+		WithName("Test Device 1").
+		WithRSSI(-65).
+		WithServices("180F", "1800").
+		WithConnectable(true).
+		WithManufacturerData(nil).
+		WithNoServiceData().
+		WithTxPower(11).
+		Build()
+	suite.WithPeripheral().
+		WithScanAdvertisements().
+		WithAdvertisements(repeat).
+		Build()
+
+	s, err := scanner.NewScanner(suite.Logger)
+	suite.Require().NoError(err)
+
+	devices, err := s.Scan(context.Background(), &scanner.ScanOptions{
+		Duration:      100 * time.Millisecond,
+		AverageRSSI:   true,
+		RSSISmoothing: 0.5,
+	}, nil)
+	suite.Require().NoError(err, "scan MUST complete without error")
+
+	suite.Require().Contains(devices, "AA:BB:CC:DD:EE:FF")
+	suite.InDelta(-55.0, devices["AA:BB:CC:DD:EE:FF"].AvgRSSI, 0.001, "average MUST weight the newest sample by the smoothing factor")
+	suite.Equal(2, devices["AA:BB:CC:DD:EE:FF"].Samples, "every advertisement MUST be counted")
+
+	suite.Require().Contains(devices, "11:22:33:44:55:66")
+	suite.InDelta(-67.0, devices["11:22:33:44:55:66"].AvgRSSI, 0.001, "first sample MUST seed the average")
+	suite.Equal(1, devices["11:22:33:44:55:66"].Samples)
+}
+
 // TestScannerTestSuite runs the test suite using testify/suite
 func TestScannerTestSuite(t *testing.T) {
 	suitelib.Run(t, new(ScannerTestSuite))